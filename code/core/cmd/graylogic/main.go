@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/nerrad567/gray-logic-core/internal/audit"
 	"github.com/nerrad567/gray-logic-core/internal/auth"
 	"github.com/nerrad567/gray-logic-core/internal/automation"
+	"github.com/nerrad567/gray-logic-core/internal/bridges"
 	"github.com/nerrad567/gray-logic-core/internal/bridges/knx"
 	"github.com/nerrad567/gray-logic-core/internal/device"
 	"github.com/nerrad567/gray-logic-core/internal/infrastructure/config"
@@ -257,6 +259,92 @@ func run(ctx context.Context) error { //nolint:gocognit,gocyclo // application b
 	sceneDeviceAdapter := &sceneDeviceRegistryAdapter{registry: deviceRegistry}
 	sceneMQTTAdapter := &sceneMQTTClientAdapter{client: mqttClient}
 	sceneEngine := automation.NewEngine(sceneRegistry, sceneDeviceAdapter, sceneMQTTAdapter, wsHub, sceneRepo, log)
+	sceneEngine.SetTargetResolver(&sceneTargetAdapter{registry: deviceRegistry, tagRepo: tagRepo, groupRepo: groupRepo})
+
+	// Command tracker: bridge acks (subscribed by the API server) confirm
+	// each command. Dev mode without a bridge has nothing to ack scene
+	// commands, so scenes keep counting a publish as completed there.
+	commandTracker := automation.NewCommandTracker(automation.DefaultCommandTimeout, wsHub, log)
+	defer commandTracker.Stop()
+	if !cfg.DevMode || cfg.Protocols.KNX.Enabled {
+		sceneEngine.SetCommandTracker(commandTracker)
+	}
+
+	// Bridge health registry: health reports (subscribed by the API server)
+	// track each bridge; devices go "unknown" when their bridge goes down.
+	bridgeRegistry := bridges.NewRegistry(deviceRegistry, wsHub, log)
+	bridgeRegistry.Start(ctx)
+	defer bridgeRegistry.Stop()
+
+	// Conditions on scenes, schedules and rules are evaluated against the
+	// site, the current mode and live device state.
+	siteInfo := &siteInfoAdapter{repo: locationRepo}
+	conditionEvaluator := automation.NewConditionEvaluator(siteInfo, &deviceStateAdapter{registry: deviceRegistry})
+	sceneEngine.SetConditionEvaluator(conditionEvaluator)
+
+	// Create mode manager (home/away/night/holiday transitions). It is
+	// started once the scheduler and rule engine it toggles are running.
+	modeRepo := automation.NewSQLiteModeRepository(db.DB)
+	modeManager := automation.NewModeManager(modeRepo, &siteModeAdapter{repo: locationRepo}, sceneEngine, sceneMQTTAdapter, wsHub, auditRepo, log)
+	conditionEvaluator.SetModeReader(modeManager)
+
+	// Manual override latch: a physical change to a device automation
+	// controls holds off lower-priority schedules and rules until reset.
+	var overrides *automation.OverrideManager
+	if ov := cfg.Automation.Override; ov.Enabled {
+		overrides = automation.NewOverrideManager(automation.OverrideConfig{
+			Timeout:           time.Duration(ov.TimeoutMinutes) * time.Minute,
+			BypassPriority:    ov.BypassPriority,
+			ResetOnModeChange: ov.ResetOnModeChange,
+			ResetOnSchedule:   ov.ResetOnSchedule,
+		}, &overrideStoreAdapter{registry: deviceRegistry}, wsHub, log)
+		if startErr := overrides.Start(ctx); startErr != nil {
+			return fmt.Errorf("starting override manager: %w", startErr)
+		}
+		defer func() {
+			log.Info("stopping override manager")
+			overrides.Stop()
+		}()
+		sceneEngine.SetOverrideManager(overrides)
+		modeManager.SetOverrideManager(overrides)
+	}
+
+	// Start scheduler (time, cron and sunrise/sunset triggers for scenes)
+	scheduleRepo := automation.NewSQLiteScheduleRepository(db.DB)
+	scheduler := automation.NewScheduler(scheduleRepo, sceneEngine, siteInfo, wsHub, log)
+	scheduler.SetModeSetter(modeManager)
+	scheduler.SetConditionEvaluator(conditionEvaluator)
+	if startErr := scheduler.Start(ctx); startErr != nil {
+		return fmt.Errorf("starting scheduler: %w", startErr)
+	}
+	defer func() {
+		log.Info("stopping scheduler")
+		scheduler.Stop()
+	}()
+
+	// Start automation rule engine (device state change triggers)
+	ruleRepo := automation.NewSQLiteRuleRepository(db.DB)
+	ruleEngine := automation.NewRuleEngine(ruleRepo, sceneEngine, sceneMQTTAdapter, wsHub, log)
+	ruleEngine.SetModeSetter(modeManager)
+	ruleEngine.SetConditionEvaluator(conditionEvaluator)
+	ruleEngine.SetDeviceStateReader(&deviceStateAdapter{registry: deviceRegistry})
+	if startErr := ruleEngine.Start(ctx); startErr != nil {
+		return fmt.Errorf("starting rule engine: %w", startErr)
+	}
+	defer func() {
+		log.Info("stopping rule engine")
+		ruleEngine.Stop()
+	}()
+
+	if overrides != nil {
+		ruleEngine.SetOverrideManager(overrides)
+		overrides.SetControllers(sceneEngine, ruleEngine)
+	}
+
+	modeManager.SetTargets(scheduler, ruleEngine)
+	if startErr := modeManager.Start(ctx); startErr != nil {
+		return fmt.Errorf("starting mode manager: %w", startErr)
+	}
 
 	// Connect to TSDB (VictoriaMetrics — optional)
 	// NOTE: TSDB defer is registered here (after MQTT defer above) so that
@@ -294,6 +382,12 @@ func run(ctx context.Context) error { //nolint:gocognit,gocyclo // application b
 		SceneEngine:    sceneEngine,
 		SceneRegistry:  sceneRegistry,
 		SceneRepo:      sceneRepo,
+		Scheduler:      scheduler,
+		RuleEngine:     ruleEngine,
+		ModeManager:    modeManager,
+		Overrides:      overrides,
+		Commands:       commandTracker,
+		Bridges:        bridgeRegistry,
 		LocationRepo:   locationRepo,
 		TagRepo:        tagRepo,
 		GroupRepo:      groupRepo,
@@ -361,7 +455,12 @@ func run(ctx context.Context) error { //nolint:gocognit,gocyclo // application b
 	// Start KNX bridge (if enabled)
 	var knxBridge *knx.Bridge
 	if cfg.Protocols.KNX.Enabled {
-		knxBridge, err = startKNXBridge(ctx, cfg, knxdManager, mqttClient, log, deviceRegistry, gaRecorder)
+		keyringStore, storeErr := knx.NewKeyringStore(db.DB, cfg.Protocols.KNX.KeyringSecret)
+		if storeErr != nil {
+			return fmt.Errorf("creating KNX keyring store: %w", storeErr)
+		}
+		var secure *knx.SecureConnector
+		knxBridge, secure, err = startKNXBridge(ctx, cfg, knxdManager, mqttClient, log, deviceRegistry, gaRecorder, locationRepo, keyringStore, knx.NewStateCacheStore(db.DB))
 		if err != nil {
 			return fmt.Errorf("starting KNX bridge: %w", err)
 		}
@@ -375,6 +474,33 @@ func run(ctx context.Context) error { //nolint:gocognit,gocyclo // application b
 
 		// Wire KNX metrics provider to API server for /metrics endpoint
 		apiServer.SetKNXMetricsProvider(&knxMetricsAdapter{bridge: knxBridge})
+
+		// Wire KNX DPT conflicts to API server for commissioning
+		apiServer.SetKNXDPTConflictProvider(&knxDPTConflictAdapter{bridge: knxBridge})
+
+		// Wire KNX Secure keyring import to API server for commissioning
+		apiServer.SetKNXKeyringManager(&knxKeyringAdapter{store: keyringStore, secure: secure})
+
+		// Wire the KNX bus monitor to the API server and stream live telegrams
+		if monitor := knxBridge.BusMonitor(); monitor != nil {
+			apiServer.SetKNXBusMonitor(monitor)
+			monitor.SetOnEntry(func(e knx.MonitorEntry) {
+				wsHub.Broadcast(api.WSChannelKNXTelegram, e)
+			})
+		}
+
+		// Wire KNX bus statistics to the API server, TSDB and load alerts
+		if stats := knxBridge.BusStats(); stats != nil {
+			apiServer.SetKNXBusStats(stats)
+			if tsdbClient != nil {
+				stats.SetOnSample(func(snap knx.BusStatsSnapshot) {
+					writeKNXBusSample(tsdbClient, snap)
+				})
+			}
+			stats.SetOnAlert(func(alert knx.BusLoadAlert) {
+				reportKNXBusLoad(log, wsHub, alert)
+			})
+		}
 	} else {
 		log.Info("KNX bridge disabled")
 	}
@@ -676,39 +802,35 @@ func startKNXD(ctx context.Context, cfg *config.Config, log *logging.Logger) (*k
 //   - mqttClient: MQTT client for publishing/subscribing
 //   - log: Logger instance
 //   - deviceRegistry: Device registry for state/health persistence
+//   - locationRepo: Site repository, the time master's timezone source
+//   - stateStore: Persistence for the bridge state cache
 //
 // Returns:
 //   - *knx.Bridge: Running KNX bridge
+//   - *knx.SecureConnector: KNX Data Secure layer, for keyring imports
 //   - error: If bridge fails to start
-func startKNXBridge(ctx context.Context, cfg *config.Config, knxdManager *knxd.Manager, mqttClient *mqtt.Client, log *logging.Logger, deviceRegistry *device.Registry, gaRecorder *knx.GARecorder) (*knx.Bridge, error) {
+func startKNXBridge(ctx context.Context, cfg *config.Config, knxdManager *knxd.Manager, mqttClient *mqtt.Client, log *logging.Logger, deviceRegistry *device.Registry, gaRecorder *knx.GARecorder, locationRepo location.Repository, keyringStore *knx.KeyringStore, stateStore *knx.StateCacheStore) (*knx.Bridge, *knx.SecureConnector, error) {
 	// Load KNX bridge configuration (connection settings, MQTT, logging)
 	knxBridgeCfg, err := knx.LoadConfig(cfg.Protocols.KNX.ConfigFile)
 	if err != nil {
-		return nil, fmt.Errorf("loading KNX bridge config: %w", err)
+		return nil, nil, fmt.Errorf("loading KNX bridge config: %w", err)
 	}
 	log.Info("KNX bridge config loaded",
 		"path", cfg.Protocols.KNX.ConfigFile,
 	)
 
-	// Determine connection URL:
-	// - If knxd is managed, use its connection URL
-	// - Otherwise, use the configured host/port
-	var connURL string
-	if knxdManager != nil {
-		connURL = knxdManager.ConnectionURL()
-	} else {
-		connURL = fmt.Sprintf("tcp://%s:%d", cfg.Protocols.KNX.KNXDHost, cfg.Protocols.KNX.KNXDPort)
+	// Connect to the bus through the configured connector
+	connector, err := connectKNX(ctx, cfg, knxBridgeCfg, knxdManager, log)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Connect to knxd daemon
-	knxdClient, err := knx.Connect(ctx, knx.KNXDConfig{
-		Connection: connURL,
-	})
+	// Add KNX Data Secure with the stored keyring (plain telegrams pass through)
+	secure, err := startKNXSecure(ctx, connector, knxBridgeCfg, keyringStore, log)
 	if err != nil {
-		return nil, fmt.Errorf("connecting to knxd: %w", err)
+		_ = connector.Close()
+		return nil, nil, err
 	}
-	knxdClient.SetLogger(log)
-	log.Info("connected to knxd", "url", connURL)
 
 	// Create MQTT adapter to satisfy KNX bridge interface
 	mqttAdapter := &mqttBridgeAdapter{client: mqttClient, log: log}
@@ -720,25 +842,188 @@ func startKNXBridge(ctx context.Context, cfg *config.Config, knxdManager *knxd.M
 	bridge, err := knx.NewBridge(knx.BridgeOptions{
 		Config:     knxBridgeCfg,
 		MQTTClient: mqttAdapter,
-		KNXDClient: knxdClient,
+		KNXDClient: secure,
 		Logger:     log,
 		Registry:   registryAdapter,
 		GARecorder: gaRecorder, // May be nil if not started
+		Timezone:   &siteTimezoneAdapter{repo: locationRepo},
+		StateStore: stateStore,
 	})
 	if err != nil {
-		// Clean up knxd connection on error
-		_ = knxdClient.Close()
-		return nil, fmt.Errorf("creating KNX bridge: %w", err)
+		// Clean up bus connection on error
+		_ = secure.Close()
+		return nil, nil, fmt.Errorf("creating KNX bridge: %w", err)
 	}
 
 	// Start the bridge
 	if err := bridge.Start(ctx); err != nil {
-		_ = knxdClient.Close()
-		return nil, fmt.Errorf("starting KNX bridge: %w", err)
+		_ = secure.Close()
+		return nil, nil, fmt.Errorf("starting KNX bridge: %w", err)
 	}
 	log.Info("KNX bridge started")
 
-	return bridge, nil
+	return bridge, secure, nil
+}
+
+// startKNXSecure wraps the bus connector with KNX Data Secure and loads
+// the keyring stored by a previous commissioning import, if any.
+//
+// Parameters:
+//   - ctx: Context for loading the keyring and sequence numbers
+//   - connector: Connected bus connector
+//   - knxBridgeCfg: KNX bridge configuration (secure source address)
+//   - store: Encrypted keyring and sequence number store
+//   - log: Logger instance
+//
+// Returns:
+//   - *knx.SecureConnector: Connector the bridge should use
+//   - error: If the secure layer cannot be created or the stored keyring
+//     cannot be read
+func startKNXSecure(ctx context.Context, connector knx.Connector, knxBridgeCfg *knx.Config, store *knx.KeyringStore, log *logging.Logger) (*knx.SecureConnector, error) {
+	secure, err := knx.NewSecureConnector(ctx, connector, knx.SecureOptions{
+		Store:             store,
+		IndividualAddress: knxBridgeCfg.Secure.IndividualAddress,
+		Logger:            log,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("starting KNX Secure: %w", err)
+	}
+
+	// A keyring that cannot be decrypted stops start-up: running without it
+	// would leave secure group addresses silently undecrypted.
+	keyring, err := store.LoadKeyring(ctx)
+	if err != nil {
+		if errors.Is(err, knx.ErrKeyringSecret) {
+			return nil, fmt.Errorf("loading KNX Secure keyring: %w (restore the protocols.knx.keyring_secret it was imported with)", err)
+		}
+		return nil, fmt.Errorf("loading KNX Secure keyring: %w", err)
+	}
+	if keyring != nil {
+		secure.SetKeyring(keyring)
+	}
+	return secure, nil
+}
+
+// connectKNX opens the bus connection selected by the bridge config's
+// connector: a native KNXnet/IP tunnel or routing, or knxd (the default).
+//
+// Parameters:
+//   - ctx: Context for connection/cancellation
+//   - cfg: Application configuration (knxd host/port)
+//   - knxBridgeCfg: KNX bridge configuration (connector, tunnel/routing settings)
+//   - knxdManager: knxd manager (may be nil if not managed)
+//   - log: Logger instance
+//
+// Returns:
+//   - knx.Connector: Connected bus connector
+//   - error: If the connection fails
+func connectKNX(ctx context.Context, cfg *config.Config, knxBridgeCfg *knx.Config, knxdManager *knxd.Manager, log *logging.Logger) (knx.Connector, error) {
+	if knxdManager != nil && knxBridgeCfg.Connector != knx.ConnectorKNXD && knxBridgeCfg.Connector != "" {
+		log.Warn("knxd is managed but the KNX bridge does not use it",
+			"connector", knxBridgeCfg.Connector,
+		)
+	}
+
+	switch knxBridgeCfg.Connector {
+	case knx.ConnectorTunnel:
+		tunnel, err := knx.ConnectTunnel(ctx, knxBridgeCfg.ToTunnelConfig())
+		if err != nil {
+			return nil, fmt.Errorf("connecting KNXnet/IP tunnel: %w", err)
+		}
+		tunnel.SetLogger(log)
+		log.Info("connected to KNXnet/IP gateway",
+			"gateway", knxBridgeCfg.Tunnel.Gateway,
+			"individual_address", tunnel.IndividualAddress(),
+		)
+		return tunnel, nil
+	case knx.ConnectorRouting:
+		routing, err := knx.ConnectRouting(ctx, knxBridgeCfg.ToRoutingConfig())
+		if err != nil {
+			return nil, fmt.Errorf("joining KNXnet/IP routing group: %w", err)
+		}
+		routing.SetLogger(log)
+		log.Info("joined KNXnet/IP routing group",
+			"multicast_address", knxBridgeCfg.Routing.MulticastAddress,
+			"individual_address", routing.IndividualAddress(),
+		)
+		return routing, nil
+	}
+
+	// Determine connection URL:
+	// - If knxd is managed, use its connection URL
+	// - Otherwise, use the configured host/port
+	var connURL string
+	if knxdManager != nil {
+		connURL = knxdManager.ConnectionURL()
+	} else {
+		connURL = fmt.Sprintf("tcp://%s:%d", cfg.Protocols.KNX.KNXDHost, cfg.Protocols.KNX.KNXDPort)
+	}
+
+	// Connect to knxd daemon
+	knxdClient, err := knx.Connect(ctx, knx.KNXDConfig{
+		Connection: connURL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("connecting to knxd: %w", err)
+	}
+	knxdClient.SetLogger(log)
+	log.Info("connected to knxd", "url", connURL)
+	return knxdClient, nil
+}
+
+// writeKNXBusSample writes a KNX bus statistics sample to the TSDB: the
+// overall load, the load per line, and the top sources and group addresses.
+func writeKNXBusSample(client *tsdb.Client, snap knx.BusStatsSnapshot) {
+	client.WritePoint("knx_bus", nil, map[string]interface{}{
+		"telegrams_per_second": snap.TelegramsPerSecond,
+		"load_percent":         snap.LoadPercent,
+		"peak_per_second":      snap.PeakPerSecond,
+		"reads":                snap.Reads,
+		"writes":               snap.Writes,
+		"responses":            snap.Responses,
+	})
+	for _, line := range snap.Lines {
+		client.WritePoint("knx_bus_line", map[string]string{"line": line.Line}, map[string]interface{}{
+			"telegrams_per_second": line.TelegramsPerSecond,
+			"load_percent":         line.LoadPercent,
+		})
+	}
+	for _, source := range snap.TopSources {
+		client.WritePoint("knx_bus_source", map[string]string{"source": source.Address}, map[string]interface{}{
+			"telegrams_per_second": source.TelegramsPerSecond,
+		})
+	}
+	for _, ga := range snap.TopGroupAddresses {
+		client.WritePoint("knx_bus_ga", map[string]string{"ga": ga.Address}, map[string]interface{}{
+			"telegrams_per_second": ga.TelegramsPerSecond,
+		})
+	}
+}
+
+// reportKNXBusLoad logs a KNX bus load alert and broadcasts it to
+// WebSocket clients as a health.alert event.
+func reportKNXBusLoad(log *logging.Logger, hub *api.Hub, alert knx.BusLoadAlert) {
+	severity := "warning"
+	message := fmt.Sprintf("KNX bus load %.0f%% is above %d%%", alert.LoadPercent, alert.Threshold)
+	if alert.Active {
+		log.Warn("KNX bus load high", "load_percent", alert.LoadPercent, "threshold", alert.Threshold)
+	} else {
+		severity = "info"
+		message = fmt.Sprintf("KNX bus load %.0f%% is back below %d%%", alert.LoadPercent, alert.Threshold)
+		log.Info("KNX bus load back to normal", "load_percent", alert.LoadPercent, "threshold", alert.Threshold)
+	}
+
+	hub.Broadcast("health.alert", map[string]any{
+		"severity":   severity,
+		"alert_type": "knx_bus_load",
+		"message":    message,
+		"details": map[string]any{
+			"active":       alert.Active,
+			"load_percent": alert.LoadPercent,
+			"threshold":    alert.Threshold,
+			"top_sources":  alert.TopSources,
+		},
+	})
 }
 
 // mqttBridgeAdapter adapts the infrastructure MQTT client to the KNX bridge's
@@ -847,9 +1132,10 @@ func (a *deviceRegistryAdapter) GetKNXDevices(ctx context.Context) ([]knx.Regist
 		if knxFuncs := device.GetKNXFunctions(dev.Address); knxFuncs != nil {
 			for name, fc := range knxFuncs {
 				functions[name] = knx.FunctionMapping{
-					GA:    fc.GA,
-					DPT:   fc.DPT,
-					Flags: fc.Flags,
+					GA:           fc.GA,
+					DPT:          fc.DPT,
+					Flags:        fc.Flags,
+					PollInterval: fc.PollInterval,
 				}
 			}
 		}
@@ -861,6 +1147,10 @@ func (a *deviceRegistryAdapter) GetKNXDevices(ctx context.Context) ([]knx.Regist
 			Domain:       string(dev.Domain),
 			Functions:    functions,
 			Capabilities: caps,
+			State:        dev.State,
+		}
+		if dev.StateUpdatedAt != nil {
+			result[i].StateUpdatedAt = *dev.StateUpdatedAt
 		}
 	}
 	return result, nil
@@ -902,6 +1192,183 @@ func (a *sceneMQTTClientAdapter) Publish(topic string, payload []byte, qos byte,
 	return a.client.Publish(topic, payload, qos, retained)
 }
 
+// siteInfoAdapter adapts the location repository to the
+// automation.SiteProvider interface used by the scheduler.
+type siteInfoAdapter struct {
+	repo location.Repository
+}
+
+// GetSiteInfo implements automation.SiteProvider. Before a site has been
+// created, it returns an empty SiteInfo (UTC, no coordinates).
+func (a *siteInfoAdapter) GetSiteInfo(ctx context.Context) (automation.SiteInfo, error) {
+	site, err := a.repo.GetAnySite(ctx)
+	if err != nil {
+		if errors.Is(err, location.ErrSiteNotFound) {
+			return automation.SiteInfo{}, nil
+		}
+		return automation.SiteInfo{}, err
+	}
+	return automation.SiteInfo{
+		Latitude:  site.Latitude,
+		Longitude: site.Longitude,
+		Timezone:  site.Timezone,
+	}, nil
+}
+
+// siteTimezoneAdapter adapts the location repository to the
+// knx.TimezoneProvider interface used by the KNX time master.
+type siteTimezoneAdapter struct {
+	repo location.Repository
+}
+
+// SiteTimezone implements knx.TimezoneProvider. Before a site has been
+// created, it returns "" so the bridge's configured timezone applies.
+func (a *siteTimezoneAdapter) SiteTimezone(ctx context.Context) (string, error) {
+	site, err := a.repo.GetAnySite(ctx)
+	if err != nil {
+		if errors.Is(err, location.ErrSiteNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return site.Timezone, nil
+}
+
+// deviceStateAdapter adapts the device.Registry to the
+// automation.DeviceStateReader interface used by condition evaluation and
+// to seed rule triggers.
+type deviceStateAdapter struct {
+	registry *device.Registry
+}
+
+// GetDeviceState implements automation.DeviceStateReader.
+func (a *deviceStateAdapter) GetDeviceState(ctx context.Context, deviceID string) (map[string]any, error) {
+	dev, err := a.registry.GetDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	return dev.State, nil
+}
+
+// sceneTargetAdapter adapts device group resolution to the
+// automation.TargetResolver interface. Room, area and tag targets are
+// resolved as an ad-hoc dynamic group.
+type sceneTargetAdapter struct {
+	registry  *device.Registry
+	tagRepo   device.TagRepository
+	groupRepo device.GroupRepository
+}
+
+// ResolveTarget implements automation.TargetResolver.
+func (a *sceneTargetAdapter) ResolveTarget(ctx context.Context, target automation.ActionTarget) ([]string, error) {
+	group := &device.DeviceGroup{
+		Type: device.GroupTypeDynamic,
+		FilterRules: &device.FilterRules{
+			ScopeType: target.ScopeType,
+			ScopeID:   target.ScopeID,
+			Tags:      target.Tags,
+			Domains:   target.Domains,
+		},
+	}
+	if target.GroupID != "" {
+		var err error
+		group, err = a.groupRepo.GetByID(ctx, target.GroupID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	devices, err := device.ResolveGroup(ctx, group, a.registry, a.tagRepo, a.groupRepo)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(devices))
+	for i := range devices {
+		ids[i] = devices[i].ID
+	}
+	return ids, nil
+}
+
+// overrideStoreAdapter adapts the device.Registry to the
+// automation.OverrideStore interface used by the manual override latch.
+type overrideStoreAdapter struct {
+	registry *device.Registry
+}
+
+// SetOverrideState implements automation.OverrideStore.
+func (a *overrideStoreAdapter) SetOverrideState(ctx context.Context, deviceID string, state map[string]any) error {
+	return a.registry.SetDeviceState(ctx, deviceID, device.State{automation.OverrideStateKey: state})
+}
+
+// ListOverrideStates implements automation.OverrideStore.
+func (a *overrideStoreAdapter) ListOverrideStates(ctx context.Context) (map[string]map[string]any, error) {
+	devices, err := a.registry.ListDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	states := make(map[string]map[string]any)
+	for _, dev := range devices {
+		if state, ok := dev.State[automation.OverrideStateKey].(map[string]any); ok {
+			states[dev.ID] = state
+		}
+	}
+	return states, nil
+}
+
+// siteModeAdapter adapts the location repository to the
+// automation.ModeSiteStore interface used by the mode manager.
+type siteModeAdapter struct {
+	repo location.Repository
+}
+
+// GetCurrentMode implements automation.ModeSiteStore. Before a site has
+// been created, there is no current mode.
+func (a *siteModeAdapter) GetCurrentMode(ctx context.Context) (string, error) {
+	site, err := a.repo.GetAnySite(ctx)
+	if err != nil {
+		if errors.Is(err, location.ErrSiteNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return site.ModeCurrent, nil
+}
+
+// SetCurrentMode implements automation.ModeSiteStore.
+func (a *siteModeAdapter) SetCurrentMode(ctx context.Context, modeID string) error {
+	return a.updateSite(ctx, func(site *location.Site) bool {
+		site.ModeCurrent = modeID
+		return true
+	})
+}
+
+// SetAvailableModes implements automation.ModeSiteStore.
+func (a *siteModeAdapter) SetAvailableModes(ctx context.Context, modeIDs []string) error {
+	return a.updateSite(ctx, func(site *location.Site) bool {
+		if slices.Equal(site.ModesAvailable, modeIDs) {
+			return false
+		}
+		site.ModesAvailable = modeIDs
+		return true
+	})
+}
+
+// updateSite applies fn to the site record and saves it if fn reports a
+// change. It is a no-op before a site has been created.
+func (a *siteModeAdapter) updateSite(ctx context.Context, fn func(*location.Site) bool) error {
+	site, err := a.repo.GetAnySite(ctx)
+	if err != nil {
+		if errors.Is(err, location.ErrSiteNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !fn(site) {
+		return nil
+	}
+	return a.repo.UpdateSite(ctx, site)
+}
+
 // knxMetricsAdapter adapts knx.Bridge to api.KNXMetricsProvider.
 type knxMetricsAdapter struct {
 	bridge *knx.Bridge
@@ -916,5 +1383,61 @@ func (a *knxMetricsAdapter) GetMetrics() api.KNXBridgeMetrics {
 		TelegramsTx:    m.TelegramsTx,
 		TelegramsRx:    m.TelegramsRx,
 		DevicesManaged: m.DevicesManaged,
+		QueueDepth:     m.QueueDepth,
+		QueueDropped:   m.QueueDropped,
+		QueueCoalesced: m.QueueCoalesced,
+	}
+}
+
+// knxKeyringAdapter adapts the KNX keyring store and secure connector to
+// api.KNXKeyringManager.
+type knxKeyringAdapter struct {
+	store  *knx.KeyringStore
+	secure *knx.SecureConnector
+}
+
+// ImportKeyring implements api.KNXKeyringManager.
+func (a *knxKeyringAdapter) ImportKeyring(ctx context.Context, data []byte, password string) (knx.KeyringSummary, error) {
+	keyring, err := knx.ParseKeyring(data, password)
+	if err != nil {
+		return knx.KeyringSummary{}, err
+	}
+	if err := a.store.SaveKeyring(ctx, keyring); err != nil {
+		return knx.KeyringSummary{}, err
+	}
+	a.secure.SetKeyring(keyring)
+	return keyring.Summary(), nil
+}
+
+// KeyringSummary implements api.KNXKeyringManager.
+func (a *knxKeyringAdapter) KeyringSummary(ctx context.Context) (*knx.KeyringSummary, error) {
+	keyring, err := a.store.LoadKeyring(ctx)
+	if err != nil || keyring == nil {
+		return nil, err
+	}
+	summary := keyring.Summary()
+	return &summary, nil
+}
+
+// knxDPTConflictAdapter adapts knx.Bridge to api.KNXDPTConflictProvider.
+type knxDPTConflictAdapter struct {
+	bridge *knx.Bridge
+}
+
+// DPTConflicts implements api.KNXDPTConflictProvider.
+func (a *knxDPTConflictAdapter) DPTConflicts() []api.KNXDPTConflict {
+	conflicts := a.bridge.DPTConflicts()
+	out := make([]api.KNXDPTConflict, 0, len(conflicts))
+	for _, c := range conflicts {
+		mappings := make([]api.KNXDPTConflictMapping, 0, len(c.Mappings))
+		for _, m := range c.Mappings {
+			mappings = append(mappings, api.KNXDPTConflictMapping{
+				DeviceID: m.DeviceID,
+				Function: m.Function,
+				DPT:      m.DPT,
+			})
+		}
+		out = append(out, api.KNXDPTConflict{GA: c.GA, DPTs: c.DPTs, Mappings: mappings})
 	}
+	return out
 }
@@ -62,7 +62,8 @@ type ticketStore struct {
 type ticketEntry struct {
 	userID    string // empty for panel tickets
 	role      auth.Role
-	panelID   string // non-empty for panel tickets
+	panelID   string          // non-empty for panel tickets
+	scope     *auth.RoomScope // nil for unrestricted roles
 	expiresAt time.Time
 }
 
@@ -432,7 +433,8 @@ func (s *Server) handleChangePassword(w http.ResponseWriter, r *http.Request) {
 
 // handleWSTicket generates a single-use WebSocket authentication ticket.
 // The ticket carries the caller's identity (user claims or panel context)
-// so the WebSocket connection inherits the same auth context.
+// and room scope so the WebSocket connection inherits the same auth
+// context. The scope is fixed for the life of the connection.
 func (s *Server) handleWSTicket(w http.ResponseWriter, r *http.Request) {
 	ticket := generateTicket()
 
@@ -444,9 +446,23 @@ func (s *Server) handleWSTicket(w http.ResponseWriter, r *http.Request) {
 	if claims := claimsFromContext(r.Context()); claims != nil {
 		entry.userID = claims.Subject
 		entry.role = claims.Role
+		if auth.IsRoomScoped(claims.Role) {
+			if s.roomAccessRepo == nil {
+				writeForbidden(w, "room access not configured")
+				return
+			}
+			scope, err := s.resolveUserRoomScope(r.Context(), claims.Subject)
+			if err != nil {
+				s.logger.Error("failed to resolve room scope", "user_id", claims.Subject, "error", err)
+				writeInternalError(w, "failed to resolve room access")
+				return
+			}
+			entry.scope = scope
+		}
 	} else if pc := panelFromContext(r.Context()); pc != nil {
 		entry.panelID = pc.PanelID
 		entry.role = auth.RolePanel
+		entry.scope = &auth.RoomScope{RoomIDs: pc.RoomIDs}
 	}
 
 	s.wsTickets.mu.Lock()
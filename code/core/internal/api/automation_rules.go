@@ -0,0 +1,324 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/nerrad567/gray-logic-core/internal/auth"
+	"github.com/nerrad567/gray-logic-core/internal/automation"
+	"github.com/nerrad567/gray-logic-core/internal/device"
+)
+
+const ruleNotAccessibleMsg = "automation rule not in accessible rooms"
+
+// handleListRules returns all automation rules.
+//
+// Query parameters:
+//   - enabled: "true" or "false" to filter by enabled state
+//   - device_id: only rules triggered by this device
+func (s *Server) handleListRules(w http.ResponseWriter, r *http.Request) {
+	if s.ruleEngine == nil {
+		writeInternalError(w, "automation rules not configured")
+		return
+	}
+
+	query := r.URL.Query()
+	var enabledFilter *bool
+	if v := query.Get("enabled"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			writeBadRequest(w, "enabled must be true or false")
+			return
+		}
+		enabledFilter = &b
+	}
+	deviceFilter := query.Get("device_id")
+	if len(deviceFilter) > maxQueryParamLen {
+		writeBadRequest(w, "device_id too long")
+		return
+	}
+
+	ctx := r.Context()
+	scope := requestRoomScope(ctx)
+
+	rules := make([]automation.Rule, 0)
+	for _, rule := range s.ruleEngine.ListRules() {
+		if enabledFilter != nil && rule.Enabled != *enabledFilter {
+			continue
+		}
+		if deviceFilter != "" && rule.Trigger.DeviceID != deviceFilter {
+			continue
+		}
+		if denied, _ := s.ruleManageDenied(ctx, scope, &rule); denied {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"rules": rules, "count": len(rules)})
+}
+
+// handleGetRule returns a single automation rule by ID.
+func (s *Server) handleGetRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := s.loadRuleForRequest(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, rule)
+}
+
+// handleCreateRule creates a new automation rule.
+func (s *Server) handleCreateRule(w http.ResponseWriter, r *http.Request) {
+	if s.ruleEngine == nil {
+		writeInternalError(w, "automation rules not configured")
+		return
+	}
+
+	var rule automation.Rule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		writeBadRequest(w, "invalid JSON body")
+		return
+	}
+
+	if !s.checkRuleTargets(w, r, &rule) {
+		return
+	}
+
+	if err := s.ruleEngine.CreateRule(r.Context(), &rule); err != nil {
+		writeRuleError(w, err, "failed to create automation rule")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, rule)
+}
+
+// handleUpdateRule partially updates an automation rule.
+func (s *Server) handleUpdateRule(w http.ResponseWriter, r *http.Request) {
+	existing, ok := s.loadRuleForRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		writeBadRequest(w, "invalid JSON body")
+		return
+	}
+	// Trigger and execute are replaced as a whole, so switching e.g. from
+	// "crosses" to "changes_to" does not keep a stale threshold.
+	if _, ok := raw["trigger"]; ok {
+		existing.Trigger = automation.RuleTrigger{}
+	}
+	if _, ok := raw["execute"]; ok {
+		existing.Execute = automation.RuleExecute{}
+	}
+	if _, ok := raw["conditions"]; ok {
+		existing.Conditions = nil
+	}
+	body, err := json.Marshal(raw)
+	if err != nil {
+		writeBadRequest(w, "invalid JSON body")
+		return
+	}
+
+	id := existing.ID
+	if err := json.Unmarshal(body, existing); err != nil {
+		writeBadRequest(w, "invalid JSON body")
+		return
+	}
+	existing.ID = id // Ensure ID cannot be changed
+
+	if !s.checkRuleTargets(w, r, existing) {
+		return
+	}
+
+	if err := s.ruleEngine.UpdateRule(r.Context(), existing); err != nil {
+		writeRuleError(w, err, "failed to update automation rule")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, existing)
+}
+
+// handleDeleteRule removes an automation rule by ID.
+func (s *Server) handleDeleteRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := s.loadRuleForRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.ruleEngine.DeleteRule(r.Context(), rule.ID); err != nil {
+		writeRuleError(w, err, "failed to delete automation rule")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleEnableRule enables an automation rule.
+func (s *Server) handleEnableRule(w http.ResponseWriter, r *http.Request) {
+	s.setRuleEnabled(w, r, true)
+}
+
+// handleDisableRule disables an automation rule.
+func (s *Server) handleDisableRule(w http.ResponseWriter, r *http.Request) {
+	s.setRuleEnabled(w, r, false)
+}
+
+func (s *Server) setRuleEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	rule, ok := s.loadRuleForRequest(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := s.ruleEngine.SetEnabled(r.Context(), rule.ID, enabled)
+	if err != nil {
+		writeRuleError(w, err, "failed to update automation rule")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// loadRuleForRequest loads the rule named by the {id} URL parameter and
+// checks the caller may manage scenes in every room the rule touches.
+// Writes the error response and returns false on failure.
+func (s *Server) loadRuleForRequest(w http.ResponseWriter, r *http.Request) (*automation.Rule, bool) {
+	if s.ruleEngine == nil {
+		writeInternalError(w, "automation rules not configured")
+		return nil, false
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" || len(id) > maxQueryParamLen {
+		writeBadRequest(w, "invalid automation rule ID")
+		return nil, false
+	}
+
+	rule, err := s.ruleEngine.GetRule(id)
+	if err != nil {
+		writeRuleError(w, err, "failed to get automation rule")
+		return nil, false
+	}
+
+	if denied, message := s.ruleManageDenied(r.Context(), requestRoomScope(r.Context()), rule); denied {
+		if message == sceneNotAccessibleMessage {
+			message = ruleNotAccessibleMsg
+		}
+		writeForbidden(w, message)
+		return nil, false
+	}
+
+	return rule, true
+}
+
+// checkRuleTargets verifies the trigger device and the execute target
+// exist and that the caller may manage scenes in their rooms.
+func (s *Server) checkRuleTargets(w http.ResponseWriter, r *http.Request, rule *automation.Rule) bool {
+	ctx := r.Context()
+
+	if rule.Trigger.DeviceID != "" {
+		if _, err := s.registry.GetDevice(ctx, rule.Trigger.DeviceID); err != nil {
+			if errors.Is(err, device.ErrDeviceNotFound) {
+				writeBadRequest(w, "trigger.device_id: device not found")
+				return false
+			}
+			writeInternalError(w, "failed to get device")
+			return false
+		}
+	}
+
+	switch {
+	case rule.Execute.SceneID != "":
+		if _, err := s.sceneRegistry.GetScene(ctx, rule.Execute.SceneID); err != nil {
+			if errors.Is(err, automation.ErrSceneNotFound) {
+				writeBadRequest(w, "execute.scene_id: scene not found")
+				return false
+			}
+			writeInternalError(w, "failed to get scene")
+			return false
+		}
+	case rule.Execute.DeviceID != "":
+		if _, err := s.registry.GetDevice(ctx, rule.Execute.DeviceID); err != nil {
+			if errors.Is(err, device.ErrDeviceNotFound) {
+				writeBadRequest(w, "execute.device_id: device not found")
+				return false
+			}
+			writeInternalError(w, "failed to get device")
+			return false
+		}
+	case rule.Execute.Type == automation.RuleExecuteMode && rule.Execute.ModeID != "":
+		if !s.checkModeTarget(w, r, rule.Execute.ModeID) {
+			return false
+		}
+	}
+
+	if denied, message := s.ruleManageDenied(ctx, requestRoomScope(ctx), rule); denied {
+		writeForbidden(w, message)
+		return false
+	}
+	return true
+}
+
+// ruleManageDenied checks scene management permission in the rooms of the
+// rule's trigger device and execute target. Targets without a room
+// (whole-site scenes, unassigned devices, modes) need an unrestricted scope.
+func (s *Server) ruleManageDenied(ctx context.Context, scope *auth.RoomScope, rule *automation.Rule) (bool, string) {
+	if scope == nil {
+		return false, ""
+	}
+
+	rooms := []string{s.deviceRoomID(ctx, rule.Trigger.DeviceID)}
+	switch {
+	case rule.Execute.SceneID != "":
+		if s.sceneRegistry != nil {
+			if scene, err := s.sceneRegistry.GetScene(ctx, rule.Execute.SceneID); err == nil {
+				rooms = append(rooms, derefString(scene.RoomID))
+			}
+		}
+	case rule.Execute.DeviceID != "":
+		rooms = append(rooms, s.deviceRoomID(ctx, rule.Execute.DeviceID))
+	case rule.Execute.Type == automation.RuleExecuteMode:
+		rooms = append(rooms, "")
+	}
+
+	for _, roomID := range rooms {
+		if denied, message := sceneManageDenied(scope, roomID); denied {
+			return true, message
+		}
+	}
+	return false, ""
+}
+
+// deviceRoomID returns a device's room, or "" if it has none or does not exist.
+func (s *Server) deviceRoomID(ctx context.Context, deviceID string) string {
+	if deviceID == "" || s.registry == nil {
+		return ""
+	}
+	dev, err := s.registry.GetDevice(ctx, deviceID)
+	if err != nil {
+		return ""
+	}
+	return derefString(dev.RoomID)
+}
+
+// writeRuleError maps rule engine errors to HTTP responses.
+func writeRuleError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, automation.ErrRuleNotFound):
+		writeNotFound(w, "automation rule not found")
+	case errors.Is(err, automation.ErrInvalidRule):
+		writeBadRequest(w, err.Error())
+	case errors.Is(err, automation.ErrModeNotFound):
+		writeBadRequest(w, "execute.mode_id: mode not found")
+	case errors.Is(err, automation.ErrRuleExists):
+		writeConflict(w, err.Error())
+	default:
+		writeInternalError(w, fallback)
+	}
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nerrad567/gray-logic-core/internal/automation"
+)
+
+// testRuleServer extends testSceneServer with a rule engine backed by
+// in-memory SQLite, a scene ("scene-1") and a trigger device.
+// Returns the server and the trigger device ID.
+func testRuleServer(t *testing.T) (*Server, string) {
+	t.Helper()
+
+	srv, registry, _ := testSceneServer(t)
+
+	scene := &automation.Scene{
+		ID:      "scene-1",
+		Name:    "Welcome",
+		Enabled: true,
+		Actions: []automation.SceneAction{{DeviceID: "light-1", Command: "on", ContinueOnError: true}},
+	}
+	if err := registry.CreateScene(context.Background(), scene); err != nil {
+		t.Fatalf("CreateScene: %v", err)
+	}
+	dev := createDeviceWithRoom(t, srv.registry, "Hall PIR", "room-hall", "1/0/1")
+
+	engine := automation.NewRuleEngine(
+		automation.NewSQLiteRuleRepository(setupRuleTestDB(t)),
+		srv.sceneEngine, nil, nil, nil,
+	)
+	if err := engine.RefreshCache(context.Background()); err != nil {
+		t.Fatalf("RefreshCache: %v", err)
+	}
+	srv.ruleEngine = engine
+
+	return srv, dev.ID
+}
+
+// setupRuleTestDB creates an in-memory SQLite database with the automation_rules schema.
+func setupRuleTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	schema := `
+		CREATE TABLE automation_rules (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			slug TEXT NOT NULL UNIQUE,
+			description TEXT,
+			enabled INTEGER NOT NULL DEFAULT 1,
+			trigger_config TEXT NOT NULL,
+			execute_config TEXT NOT NULL,
+			conditions TEXT,
+			debounce_ms INTEGER NOT NULL DEFAULT 0,
+			cooldown_seconds INTEGER NOT NULL DEFAULT 0,
+			last_fired_at TEXT,
+			created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+			updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+		) STRICT;
+	`
+	if _, execErr := db.Exec(schema); execErr != nil {
+		db.Close()
+		t.Fatalf("failed to create test schema: %v", execErr)
+	}
+
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestRuleCRUD(t *testing.T) {
+	srv, deviceID := testRuleServer(t)
+	router := srv.buildRouter()
+
+	body := `{
+		"name": "Hall Motion",
+		"enabled": true,
+		"trigger": {"type": "changes_to", "device_id": "` + deviceID + `", "key": "motion", "value": true},
+		"execute": {"type": "scene", "scene_id": "scene-1"},
+		"debounce_ms": 250,
+		"cooldown_seconds": 60
+	}`
+	req := authReq(t, httptest.NewRequest(http.MethodPost, "/api/v1/automation-rules", strings.NewReader(body)))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want %d; body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	var created automation.Rule
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if created.ID == "" || created.Slug != "hall-motion" || created.CooldownSeconds != 60 {
+		t.Errorf("created = %+v", created)
+	}
+
+	// List filtered by trigger device
+	req = authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/automation-rules?device_id="+deviceID, nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var list map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &list)
+	if int(list["count"].(float64)) != 1 {
+		t.Errorf("count = %v, want 1", list["count"])
+	}
+
+	// Patch: switch to a direct command
+	req = authReq(t, httptest.NewRequest(http.MethodPatch, "/api/v1/automation-rules/"+created.ID,
+		strings.NewReader(`{"execute": {"type": "command", "device_id": "light-1", "command": "on"}}`)))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		// light-1 exists only in the scene engine's mock, not the device registry.
+		t.Fatalf("patch to unknown device status = %d; body: %s", w.Code, w.Body.String())
+	}
+	req = authReq(t, httptest.NewRequest(http.MethodPatch, "/api/v1/automation-rules/"+created.ID,
+		strings.NewReader(`{"execute": {"type": "command", "device_id": "`+deviceID+`", "command": "off"}}`)))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("patch status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var patched automation.Rule
+	_ = json.Unmarshal(w.Body.Bytes(), &patched)
+	if patched.Execute.SceneID != "" || patched.Execute.Command != "off" || patched.DebounceMS != 250 {
+		t.Errorf("patched = %+v", patched)
+	}
+
+	// Disable
+	req = authReq(t, httptest.NewRequest(http.MethodPost, "/api/v1/automation-rules/"+created.ID+"/disable", nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("disable status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var disabled automation.Rule
+	_ = json.Unmarshal(w.Body.Bytes(), &disabled)
+	if disabled.Enabled {
+		t.Error("rule still enabled after disable")
+	}
+
+	// Delete
+	req = authReq(t, httptest.NewRequest(http.MethodDelete, "/api/v1/automation-rules/"+created.ID, nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d; body: %s", w.Code, w.Body.String())
+	}
+
+	req = authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/automation-rules/"+created.ID, nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("get after delete status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestCreateRule_Validation(t *testing.T) {
+	srv, deviceID := testRuleServer(t)
+	router := srv.buildRouter()
+
+	trigger := `{"type": "changes_to", "device_id": "` + deviceID + `", "key": "motion", "value": true}`
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown trigger device", `{"name": "x", "trigger": {"type": "changes_to", "device_id": "nope", "key": "motion", "value": true}, "execute": {"scene_id": "scene-1"}}`},
+		{"unknown scene", `{"name": "x", "trigger": ` + trigger + `, "execute": {"scene_id": "nope"}}`},
+		{"missing value", `{"name": "x", "trigger": {"type": "changes_to", "device_id": "` + deviceID + `", "key": "motion"}, "execute": {"scene_id": "scene-1"}}`},
+		{"stays without duration", `{"name": "x", "trigger": {"type": "stays", "device_id": "` + deviceID + `", "key": "motion", "value": true}, "execute": {"scene_id": "scene-1"}}`},
+		{"negative cooldown", `{"name": "x", "trigger": ` + trigger + `, "execute": {"scene_id": "scene-1"}, "cooldown_seconds": -1}`},
+		{"invalid JSON", `{`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := authReq(t, httptest.NewRequest(http.MethodPost, "/api/v1/automation-rules", strings.NewReader(tt.body)))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d; body: %s", w.Code, http.StatusBadRequest, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRules_NotConfigured(t *testing.T) {
+	srv, _, _ := testSceneServer(t)
+	router := srv.buildRouter()
+
+	req := authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/automation-rules", nil))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
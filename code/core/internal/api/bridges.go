@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/nerrad567/gray-logic-core/internal/infrastructure/mqtt"
+)
+
+// handleListBridges returns the last known health of every protocol bridge.
+func (s *Server) handleListBridges(w http.ResponseWriter, _ *http.Request) {
+	if s.bridges == nil {
+		writeInternalError(w, "bridge health not configured")
+		return
+	}
+
+	list := s.bridges.List()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"bridges": list,
+		"count":   len(list),
+	})
+}
+
+// handleGetBridge returns the last known health of a single bridge.
+func (s *Server) handleGetBridge(w http.ResponseWriter, r *http.Request) {
+	if s.bridges == nil {
+		writeInternalError(w, "bridge health not configured")
+		return
+	}
+
+	b, ok := s.bridges.Get(chi.URLParam(r, "id"))
+	if !ok {
+		writeNotFound(w, "bridge not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, b)
+}
+
+// subscribeBridgeHealth subscribes to bridge health reports
+// (graylogic/health/{protocol}) and feeds them to the bridge registry.
+func (s *Server) subscribeBridgeHealth() error {
+	if s.mqtt == nil || s.bridges == nil {
+		return nil
+	}
+	topic := mqtt.Topics{}.AllBridgeHealth()
+	s.logger.Info("subscribing to bridge health", "topic", topic)
+	return s.mqtt.Subscribe(topic, 1, s.bridges.HandleHealth)
+}
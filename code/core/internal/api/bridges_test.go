@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nerrad567/gray-logic-core/internal/bridges"
+)
+
+func TestBridges(t *testing.T) {
+	srv, _ := testServer(t)
+	srv.bridges = bridges.NewRegistry(nil, nil, nil)
+	router := srv.buildRouter()
+
+	report := `{"bridge":"knx-bridge-01","status":"healthy","version":"1.0.0","devices_managed":3}`
+	if err := srv.bridges.HandleHealth("graylogic/health/knx", []byte(report)); err != nil {
+		t.Fatalf("HandleHealth: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/bridges", nil)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("list status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var list struct {
+		Bridges []bridges.Bridge `json:"bridges"`
+		Count   int              `json:"count"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &list)
+	if list.Count != 1 || list.Bridges[0].ID != "knx-bridge-01" || list.Bridges[0].Protocol != "knx" {
+		t.Errorf("list = %+v", list)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/bridges/knx-bridge-01", nil)))
+	var b bridges.Bridge
+	_ = json.Unmarshal(w.Body.Bytes(), &b)
+	if w.Code != http.StatusOK || b.Status != bridges.StatusHealthy || b.DevicesManaged != 3 {
+		t.Errorf("get: status %d, %+v", w.Code, b)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/bridges/dali", nil)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown bridge status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
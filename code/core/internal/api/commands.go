@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/nerrad567/gray-logic-core/internal/infrastructure/mqtt"
+)
+
+// handleGetCommand returns a device command and its confirmed outcome.
+//
+// Status is "pending" until the protocol bridge acknowledges the command,
+// then "completed", "failed" or "timeout" ("queued" while the bridge holds
+// it). Finished commands are kept for a few minutes. Room-scoped callers
+// only see commands for devices in their rooms.
+func (s *Server) handleGetCommand(w http.ResponseWriter, r *http.Request) {
+	if s.commands == nil {
+		writeInternalError(w, "command tracking not configured")
+		return
+	}
+
+	cmd, ok := s.commands.Get(chi.URLParam(r, "id"))
+	if !ok {
+		writeNotFound(w, "command not found")
+		return
+	}
+
+	if scope := requestRoomScope(r.Context()); scope != nil {
+		dev, err := s.registry.GetDevice(r.Context(), cmd.DeviceID)
+		if err != nil || !deviceInScope(scope, dev) {
+			writeNotFound(w, "command not found")
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, cmd)
+}
+
+// subscribeCommandAcks subscribes to bridge acknowledgements
+// (graylogic/ack/{protocol}/{address}) and feeds them to the command tracker.
+func (s *Server) subscribeCommandAcks() error {
+	if s.mqtt == nil || s.commands == nil {
+		return nil
+	}
+	topic := mqtt.Topics{}.AllBridgeAcks()
+	s.logger.Info("subscribing to command acknowledgements", "topic", topic)
+	return s.mqtt.Subscribe(topic, 1, func(_ string, payload []byte) error {
+		return s.commands.HandleAck(payload)
+	})
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nerrad567/gray-logic-core/internal/automation"
+)
+
+func TestGetCommand(t *testing.T) {
+	srv, registry := testServer(t)
+	srv.commands = automation.NewCommandTracker(time.Minute, nil, nil)
+	t.Cleanup(srv.commands.Stop)
+	router := srv.buildRouter()
+
+	dev := createDeviceWithRoom(t, registry, "Kitchen Light", "kitchen", "1/0/1")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodPut, "/api/v1/devices/"+dev.ID+"/state", strings.NewReader(`{"command": "on"}`))))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("set state status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var accepted map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &accepted)
+	commandID, _ := accepted["command_id"].(string)
+
+	get := func(id string) (int, automation.Command) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/commands/"+id, nil)))
+		var cmd automation.Command
+		_ = json.Unmarshal(w.Body.Bytes(), &cmd)
+		return w.Code, cmd
+	}
+
+	code, cmd := get(commandID)
+	if code != http.StatusOK || cmd.Status != automation.CommandPending || cmd.DeviceID != dev.ID || cmd.Source != "api" {
+		t.Fatalf("pending command: status %d, %+v", code, cmd)
+	}
+
+	ack := `{"command_id":"` + commandID + `","device_id":"` + dev.ID + `","status":"accepted","protocol":"knx","address":"1/0/1"}`
+	if err := srv.commands.HandleAck([]byte(ack)); err != nil {
+		t.Fatalf("HandleAck: %v", err)
+	}
+	if code, cmd = get(commandID); code != http.StatusOK || cmd.Status != automation.CommandCompleted {
+		t.Errorf("acked command: status %d, %+v", code, cmd)
+	}
+
+	if code, _ = get("nonexistent"); code != http.StatusNotFound {
+		t.Errorf("unknown command status = %d, want %d", code, http.StatusNotFound)
+	}
+}
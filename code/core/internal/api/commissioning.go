@@ -278,6 +278,7 @@ func (s *Server) handleETSImport(w http.ResponseWriter, r *http.Request) {
 			s.knxBridge.ReloadDevices(r.Context())
 			s.logger.Info("KNX bridge devices reloaded after ETS import")
 		}
+		s.invalidateSceneTargets()
 	}
 
 	writeJSON(w, http.StatusOK, response)
@@ -760,9 +761,9 @@ func deriveCapabilitiesFromAddresses(addresses []ETSAddressImport) []device.Capa
 			caps[device.CapOnOff] = true
 		case "brightness", "brightness_status":
 			caps[device.CapDim] = true
-		case "colour_temp", "colour_temp_status":
+		case "color_temperature", "color_temperature_status", "colour_temp", "colour_temp_status": //nolint:misspell // KNX function names
 			caps[device.CapColorTemp] = true
-		case "rgb", "rgb_status", "rgbw":
+		case "rgb", "rgb_status", "rgbw", "rgbw_status", "color_xyy", "color_xyy_status": //nolint:misspell // KNX function names
 			caps[device.CapColorRGB] = true
 		case "position", "position_status", "move", "stop":
 			caps[device.CapPosition] = true
@@ -772,7 +773,7 @@ func deriveCapabilitiesFromAddresses(addresses []ETSAddressImport) []device.Capa
 			caps[device.CapSpeed] = true
 		case "temperature":
 			caps[device.CapTemperatureRead] = true
-		case "setpoint":
+		case "setpoint", "setpoint_shift":
 			caps[device.CapTemperatureSet] = true
 		case "humidity":
 			caps[device.CapHumidityRead] = true
@@ -794,7 +795,7 @@ func deriveCapabilitiesFromAddresses(addresses []ETSAddressImport) []device.Capa
 			caps[device.CapCurrentRead] = true
 		case "valve", "valve_status", "valve_cmd":
 			caps[device.CapOnOff] = true
-		case "hvac_mode":
+		case "hvac_mode", "hvac_mode_status", "hvac_control_mode":
 			caps[device.CapModeSelect] = true
 		default:
 			// Suffix-based patterns for multi-channel device GA names
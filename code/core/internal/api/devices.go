@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/nerrad567/gray-logic-core/internal/auth"
+	"github.com/nerrad567/gray-logic-core/internal/automation"
+	"github.com/nerrad567/gray-logic-core/internal/bridges/knx"
 	"github.com/nerrad567/gray-logic-core/internal/device"
 )
 
@@ -75,6 +78,7 @@ func (s *Server) handleCreateDevice(w http.ResponseWriter, r *http.Request) {
 		writeInternalError(w, "failed to create device")
 		return
 	}
+	s.invalidateSceneTargets()
 
 	writeJSON(w, http.StatusCreated, dev)
 }
@@ -109,6 +113,7 @@ func (s *Server) handleUpdateDevice(w http.ResponseWriter, r *http.Request) {
 		writeInternalError(w, "failed to update device")
 		return
 	}
+	s.invalidateSceneTargets()
 
 	writeJSON(w, http.StatusOK, existing)
 }
@@ -125,6 +130,7 @@ func (s *Server) handleDeleteDevice(w http.ResponseWriter, r *http.Request) {
 		writeInternalError(w, "failed to delete device")
 		return
 	}
+	s.invalidateSceneTargets()
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -211,32 +217,16 @@ func (s *Server) handleSetDeviceState(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Build MQTT command message
-	commandID := generateRequestID()
-	mqttPayload := map[string]any{
-		"id":         commandID,
-		"device_id":  id,
-		"command":    cmd.Command,
-		"parameters": cmd.Parameters,
-		"source":     "api",
-	}
-
-	payload, marshalErr := json.Marshal(mqttPayload)
-	if marshalErr != nil {
-		writeInternalError(w, "failed to encode command")
-		return
-	}
-
-	// Publish to MQTT if available — the protocol bridge subscribes to this topic.
-	// Topic format: graylogic/command/{protocol}/{device_id}
-	if s.mqtt != nil {
-		topic := "graylogic/command/" + string(dev.Protocol) + "/" + id
-		if pubErr := s.mqtt.Publish(topic, payload, 1, false); pubErr != nil {
-			s.logger.Debug("MQTT publish failed", "error", pubErr)
+	commandID, err := s.publishDeviceCommand(dev, cmd)
+	if err != nil {
+		if !errors.Is(err, errCommandPublish) {
+			writeInternalError(w, "failed to send command")
+			return
 		}
+		s.logger.Debug("MQTT publish failed", "error", err)
 	}
 
-	newState, simulated := s.simulateDeviceStateChange(id, dev.State, cmd)
+	newState, simulated := s.simulateDeviceStateChange(id, commandID, dev.State, cmd)
 
 	logFields := []any{
 		"device_id", id,
@@ -256,6 +246,56 @@ func (s *Server) handleSetDeviceState(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// errCommandPublish marks a command that was built and tracked but could
+// not be published to MQTT.
+var errCommandPublish = errors.New("command publish failed")
+
+// publishDeviceCommand tracks a command (when a command tracker is
+// configured) and publishes it to the device's protocol bridge.
+// Topic format: graylogic/command/{protocol}/{device_id}
+//
+// Without MQTT the command is tracked but not published. Returns the
+// command ID; a publish failure wraps errCommandPublish and marks the
+// tracked command failed.
+func (s *Server) publishDeviceCommand(dev *device.Device, cmd DeviceCommand) (string, error) {
+	commandID := generateRequestID()
+	payload, err := json.Marshal(map[string]any{
+		"id":         commandID,
+		"device_id":  dev.ID,
+		"command":    cmd.Command,
+		"parameters": cmd.Parameters,
+		"source":     "api",
+	})
+	if err != nil {
+		return "", fmt.Errorf("encoding command: %w", err)
+	}
+
+	// Track the command so the bridge's ack can confirm it (GET /commands/{id}).
+	if s.commands != nil {
+		if trackErr := s.commands.Track(automation.Command{
+			ID:         commandID,
+			DeviceID:   dev.ID,
+			Command:    cmd.Command,
+			Parameters: cmd.Parameters,
+			Source:     "api",
+		}); trackErr != nil {
+			return "", trackErr
+		}
+	}
+
+	if s.mqtt == nil {
+		return commandID, nil
+	}
+	topic := "graylogic/command/" + string(dev.Protocol) + "/" + dev.ID
+	if pubErr := s.mqtt.Publish(topic, payload, 1, false); pubErr != nil {
+		if s.commands != nil {
+			s.commands.Fail(commandID, automation.CommandErrPublishFailed, pubErr.Error()) //nolint:errcheck // tracked above
+		}
+		return commandID, fmt.Errorf("%w: %w", errCommandPublish, pubErr)
+	}
+	return commandID, nil
+}
+
 // commandToState translates a device command into the resulting state.
 // Used in dev/demo mode when no protocol bridge is available to confirm the change.
 func commandToState(command string, params map[string]any, current device.State) device.State { //nolint:gocyclo // command-to-state mapping: switch on command type
@@ -296,6 +336,30 @@ func commandToState(command string, params map[string]any, current device.State)
 		if mode, ok := params["mode"]; ok {
 			newState["mode"] = mode
 		}
+	case "set_color_temp":
+		if ct, ok := params["color_temp"]; ok {
+			newState["color_temp"] = ct
+		}
+	case "set_rgb":
+		newState["rgb"] = map[string]any{"r": params["r"], "g": params["g"], "b": params["b"]}
+	case "set_rgbw":
+		newState["rgbw"] = map[string]any{"r": params["r"], "g": params["g"], "b": params["b"], "w": params["w"]}
+	case "set_hvac_mode":
+		if mode, ok := params["hvac_mode"]; ok {
+			newState["hvac_mode"] = mode
+		}
+	case "shift_setpoint":
+		if shift, ok := params["shift"]; ok {
+			newState["setpoint_shift"] = shift
+		}
+	case "set_hsv":
+		newState["hsv"] = map[string]any{"h": params["h"], "s": params["s"], "v": params["v"]}
+	case "write_function":
+		if fn, ok := params["function"].(string); ok && fn != "" {
+			newState[knx.StateKeyForFunction(fn)] = params["value"]
+		}
+	case "read_function":
+		// Reads report state through the bridge; nothing to simulate
 	default:
 		// For unknown commands, merge all parameters into state
 		for k, v := range params {
@@ -306,8 +370,9 @@ func commandToState(command string, params map[string]any, current device.State)
 	return newState
 }
 
-// simulateDeviceStateChange mimics bridge confirmations in dev mode.
-func (s *Server) simulateDeviceStateChange(deviceID string, current device.State, cmd DeviceCommand) (device.State, bool) {
+// simulateDeviceStateChange mimics bridge confirmations in dev mode,
+// completing the tracked command once the simulated state is applied.
+func (s *Server) simulateDeviceStateChange(deviceID, commandID string, current device.State, cmd DeviceCommand) (device.State, bool) {
 	// In dev mode WITHOUT a real bridge, simulate the bridge confirmation
 	// loop: delay the state write + WebSocket broadcast to mimic the real
 	// KNX bus round-trip time. When a bridge is active (knxBridge != nil),
@@ -341,6 +406,9 @@ func (s *Server) simulateDeviceStateChange(deviceID string, current device.State
 			"device_id": deviceID,
 			"state":     state,
 		})
+		if s.commands != nil {
+			s.commands.Complete(commandID) //nolint:errcheck // may have timed out already
+		}
 		s.logger.Debug("dev mode: simulated bridge confirmation", "device_id", deviceID)
 	}(newState)
 
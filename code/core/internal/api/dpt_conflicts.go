@@ -0,0 +1,21 @@
+package api
+
+import "net/http"
+
+// handleListDPTConflicts returns the KNX group addresses that devices map
+// with incompatible DPTs, so commissioning can correct them.
+func (s *Server) handleListDPTConflicts(w http.ResponseWriter, _ *http.Request) {
+	if s.knxDPTConflicts == nil {
+		writeError(w, http.StatusServiceUnavailable, serviceUnavailableKey, "KNX bridge not running")
+		return
+	}
+
+	conflicts := s.knxDPTConflicts.DPTConflicts()
+	if conflicts == nil {
+		conflicts = []KNXDPTConflict{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"conflicts": conflicts,
+		"count":     len(conflicts),
+	})
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type stubDPTConflicts []KNXDPTConflict
+
+func (s stubDPTConflicts) DPTConflicts() []KNXDPTConflict { return s }
+
+func TestListDPTConflicts(t *testing.T) {
+	srv, _ := testServer(t)
+	router := srv.buildRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/commissioning/knx/dpt-conflicts", nil)))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("without bridge: status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	srv.SetKNXDPTConflictProvider(stubDPTConflicts{{
+		GA:   "1/0/1",
+		DPTs: []string{"1.001", "5.001"},
+		Mappings: []KNXDPTConflictMapping{
+			{DeviceID: "light-hall", Function: "switch", DPT: "1.001"},
+			{DeviceID: "logic-hall", Function: "brightness", DPT: "5.001"},
+		},
+	}})
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/commissioning/knx/dpt-conflicts", nil)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Conflicts []KNXDPTConflict `json:"conflicts"`
+		Count     int              `json:"count"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Count != 1 || resp.Conflicts[0].GA != "1/0/1" || len(resp.Conflicts[0].Mappings) != 2 {
+		t.Errorf("response = %+v", resp)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
@@ -61,6 +62,7 @@ func (s *Server) handleCreateGroup(w http.ResponseWriter, r *http.Request) {
 		writeInternalError(w, "failed to create device group")
 		return
 	}
+	s.invalidateSceneTargets()
 
 	writeJSON(w, http.StatusCreated, group)
 }
@@ -186,6 +188,7 @@ func (s *Server) handleUpdateGroup(w http.ResponseWriter, r *http.Request) { //n
 		writeInternalError(w, "failed to update device group")
 		return
 	}
+	s.invalidateSceneTargets()
 
 	// Re-read to get updated timestamp
 	updated, err := s.groupRepo.GetByID(r.Context(), id)
@@ -212,10 +215,20 @@ func (s *Server) handleDeleteGroup(w http.ResponseWriter, r *http.Request) {
 		writeInternalError(w, "failed to delete device group")
 		return
 	}
+	s.invalidateSceneTargets()
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// invalidateSceneTargets tells the scene engine that group, scope or tag
+// targets may resolve to different devices. Called after groups, tags or
+// devices change.
+func (s *Server) invalidateSceneTargets() {
+	if s.sceneEngine != nil {
+		s.sceneEngine.InvalidateTargets()
+	}
+}
+
 // handleSetGroupMembers replaces the explicit member list for a group.
 //
 // PUT /device-groups/{id}/members
@@ -253,6 +266,7 @@ func (s *Server) handleSetGroupMembers(w http.ResponseWriter, r *http.Request) {
 		writeInternalError(w, "failed to set group members")
 		return
 	}
+	s.invalidateSceneTargets()
 
 	// Read back the member IDs
 	memberIDs, err := s.groupRepo.GetMemberDeviceIDs(r.Context(), id)
@@ -346,6 +360,156 @@ func (s *Server) handleResolveGroup(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Group command limits. Staggering spaces out publishes so a large group
+// does not flood the bus; the total spread is bounded well below the
+// server's write timeout (30s by default), as publishes happen within the
+// request.
+const (
+	maxGroupCommandStagger = time.Second
+	maxGroupCommandSpread  = 10 * time.Second
+)
+
+// Per-device group command outcomes.
+const (
+	groupCommandSent        = "sent"
+	groupCommandUnsupported = "unsupported"
+	groupCommandFailed      = "failed"
+)
+
+// GroupCommand is the request body for a group command.
+type GroupCommand struct {
+	Command    string         `json:"command"`
+	Parameters map[string]any `json:"parameters,omitempty"`
+	StaggerMS  int            `json:"stagger_ms,omitempty"`
+}
+
+// groupCommandResult is the outcome of a group command for one device.
+type groupCommandResult struct {
+	DeviceID  string `json:"device_id"`
+	Status    string `json:"status"`
+	CommandID string `json:"command_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// handleGroupCommand sends a command to every device in a group.
+//
+// Members are resolved with device.ResolveGroup and limited to the caller's
+// room scope. Devices lacking the capability the command needs are skipped
+// as "unsupported". Publishes are spaced by stagger_ms (max 1000). Commands
+// are tracked individually; use GET /commands/{id} for the bus outcome.
+//
+// POST /device-groups/{id}/command
+// Body: {"command": "off", "parameters": {...}, "stagger_ms": 50}
+// Response: 202 {"group_id": "grp-1", "command": "off", "total": N, "sent": N,
+// "unsupported": N, "failed": N, "results": [...]}
+func (s *Server) handleGroupCommand(w http.ResponseWriter, r *http.Request) { //nolint:gocognit,gocyclo // HTTP handler: validates, resolves, fans out
+	id := chi.URLParam(r, "id")
+	scope := requestRoomScope(r.Context())
+	if scope != nil && len(scope.RoomIDs) == 0 {
+		writeForbidden(w, "no accessible rooms")
+		return
+	}
+
+	var body GroupCommand
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeBadRequest(w, "invalid JSON body")
+		return
+	}
+	if body.Command == "" {
+		writeBadRequest(w, "command field is required")
+		return
+	}
+	stagger := time.Duration(body.StaggerMS) * time.Millisecond
+	if stagger < 0 || stagger > maxGroupCommandStagger {
+		writeBadRequest(w, "stagger_ms must be between 0 and 1000")
+		return
+	}
+
+	group, err := s.groupRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, device.ErrGroupNotFound) {
+			writeNotFound(w, "device group not found")
+			return
+		}
+		s.logger.Error("failed to get device group", "error", err, "id", id)
+		writeInternalError(w, "failed to get device group")
+		return
+	}
+
+	devices, err := device.ResolveGroup(r.Context(), group, s.registry, s.tagRepo, s.groupRepo)
+	if err != nil {
+		s.logger.Error("failed to resolve device group", "error", err, "id", id)
+		writeInternalError(w, "failed to resolve device group")
+		return
+	}
+	devices = applyDeviceScopeSlice(devices, scope)
+
+	if n := len(devices); n > 1 && time.Duration(n-1)*stagger > maxGroupCommandSpread {
+		writeBadRequest(w, "stagger_ms too large for group size (max 10s total)")
+		return
+	}
+
+	capability, needsCapability := device.CommandCapability(body.Command)
+	cmd := DeviceCommand{Command: body.Command, Parameters: body.Parameters}
+	results := make([]groupCommandResult, 0, len(devices))
+	counts := map[string]int{}
+	published := 0
+
+	for i := range devices {
+		dev := &devices[i]
+		result := groupCommandResult{DeviceID: dev.ID}
+
+		switch {
+		case needsCapability && !dev.HasCapability(capability):
+			result.Status = groupCommandUnsupported
+			result.Error = "device lacks capability " + string(capability)
+		case r.Context().Err() != nil:
+			result.Status = groupCommandFailed
+			result.Error = "request cancelled"
+		default:
+			if published > 0 && stagger > 0 {
+				select {
+				case <-time.After(stagger):
+				case <-r.Context().Done():
+				}
+			}
+			published++
+
+			commandID, pubErr := s.publishDeviceCommand(dev, cmd)
+			result.CommandID = commandID
+			if pubErr != nil {
+				result.Status = groupCommandFailed
+				result.Error = pubErr.Error()
+				break
+			}
+			result.Status = groupCommandSent
+			s.simulateDeviceStateChange(dev.ID, commandID, dev.State, cmd)
+		}
+
+		counts[result.Status]++
+		results = append(results, result)
+	}
+
+	s.logger.Info("group command sent",
+		"group_id", id,
+		"command", body.Command,
+		"devices", len(devices),
+		"sent", counts[groupCommandSent],
+		"unsupported", counts[groupCommandUnsupported],
+		"failed", counts[groupCommandFailed],
+	)
+
+	writeJSON(w, http.StatusAccepted, map[string]any{
+		"group_id":    id,
+		"command":     body.Command,
+		"total":       len(results),
+		"sent":        counts[groupCommandSent],
+		"unsupported": counts[groupCommandUnsupported],
+		"failed":      counts[groupCommandFailed],
+		"results":     results,
+	})
+}
+
 // applyDeviceScopeSlice filters a pre-fetched device slice by room scope.
 func applyDeviceScopeSlice(devices []device.Device, scope *auth.RoomScope) []device.Device {
 	if scope == nil {
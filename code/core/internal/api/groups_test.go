@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nerrad567/gray-logic-core/internal/automation"
+	"github.com/nerrad567/gray-logic-core/internal/device"
+)
+
+// mockGroupRepo holds static groups in memory.
+type mockGroupRepo struct {
+	groups  map[string]*device.DeviceGroup
+	members map[string][]string
+}
+
+func (m *mockGroupRepo) Create(_ context.Context, g *device.DeviceGroup) error {
+	m.groups[g.ID] = g
+	return nil
+}
+
+func (m *mockGroupRepo) GetByID(_ context.Context, id string) (*device.DeviceGroup, error) {
+	g, ok := m.groups[id]
+	if !ok {
+		return nil, device.ErrGroupNotFound
+	}
+	return g, nil
+}
+
+func (m *mockGroupRepo) List(_ context.Context) ([]device.DeviceGroup, error) { return nil, nil }
+
+func (m *mockGroupRepo) Update(_ context.Context, _ *device.DeviceGroup) error { return nil }
+
+func (m *mockGroupRepo) Delete(_ context.Context, _ string) error { return nil }
+
+func (m *mockGroupRepo) SetMembers(_ context.Context, groupID string, deviceIDs []string) error {
+	m.members[groupID] = deviceIDs
+	return nil
+}
+
+func (m *mockGroupRepo) GetMembers(_ context.Context, _ string) ([]device.GroupMember, error) {
+	return nil, nil
+}
+
+func (m *mockGroupRepo) GetMemberDeviceIDs(_ context.Context, groupID string) ([]string, error) {
+	return m.members[groupID], nil
+}
+
+func TestGroupCommand(t *testing.T) {
+	srv, registry := testServer(t)
+	srv.commands = automation.NewCommandTracker(time.Minute, nil, nil)
+	t.Cleanup(srv.commands.Stop)
+	groups := &mockGroupRepo{groups: map[string]*device.DeviceGroup{}, members: map[string][]string{}}
+	srv.groupRepo = groups
+	router := srv.buildRouter()
+
+	light1 := createDeviceWithRoom(t, registry, "Light 1", "kitchen", "1/0/1")
+	light2 := createDeviceWithRoom(t, registry, "Light 2", "kitchen", "1/0/2")
+	sensor := &device.Device{
+		Name: "Sensor", Type: device.DeviceTypeTemperatureSensor, Domain: device.DomainClimate, Protocol: device.ProtocolKNX,
+		Address: device.Address{"functions": map[string]any{
+			"temperature": map[string]any{"ga": "2/0/1", "dpt": "9.001", "flags": []any{"read"}},
+		}},
+		Capabilities: []device.Capability{device.CapTemperatureRead},
+	}
+	if err := registry.CreateDevice(context.Background(), sensor); err != nil {
+		t.Fatalf("CreateDevice: %v", err)
+	}
+	groups.groups["kitchen"] = &device.DeviceGroup{ID: "kitchen", Name: "Kitchen", Type: device.GroupTypeStatic}
+	groups.members["kitchen"] = []string{light1.ID, light2.ID, sensor.ID}
+
+	send := func(path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))))
+		return w
+	}
+
+	w := send("/api/v1/device-groups/kitchen/command", `{"command": "on", "stagger_ms": 5}`)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Total       int                  `json:"total"`
+		Sent        int                  `json:"sent"`
+		Unsupported int                  `json:"unsupported"`
+		Results     []groupCommandResult `json:"results"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Total != 3 || resp.Sent != 2 || resp.Unsupported != 1 {
+		t.Errorf("summary = %+v", resp)
+	}
+	for _, res := range resp.Results {
+		switch res.DeviceID {
+		case sensor.ID:
+			if res.Status != groupCommandUnsupported || res.CommandID != "" {
+				t.Errorf("sensor result = %+v", res)
+			}
+		default:
+			if _, tracked := srv.commands.Get(res.CommandID); res.Status != groupCommandSent || !tracked {
+				t.Errorf("light result = %+v (tracked %v)", res, tracked)
+			}
+		}
+	}
+
+	if w := send("/api/v1/device-groups/kitchen/command", `{"command": "on", "stagger_ms": 5000}`); w.Code != http.StatusBadRequest {
+		t.Errorf("oversized stagger status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	// 12 devices staggered by 1s would take 11s, more than the 10s spread
+	for i := range 9 {
+		light := createDeviceWithRoom(t, registry, fmt.Sprintf("Light %d", i+3), "kitchen", fmt.Sprintf("1/0/%d", i+3))
+		groups.members["kitchen"] = append(groups.members["kitchen"], light.ID)
+	}
+	if w := send("/api/v1/device-groups/kitchen/command", `{"command": "on", "stagger_ms": 1000}`); w.Code != http.StatusBadRequest {
+		t.Errorf("oversized spread status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if w := send("/api/v1/device-groups/missing/command", `{"command": "on"}`); w.Code != http.StatusNotFound {
+		t.Errorf("unknown group status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/nerrad567/gray-logic-core/internal/bridges/knx"
+)
+
+// handleGetKNXKeyring describes the imported KNX Secure keyring: project,
+// secure group addresses and import time. Keys are never returned.
+func (s *Server) handleGetKNXKeyring(w http.ResponseWriter, r *http.Request) {
+	if s.knxKeyring == nil {
+		writeError(w, http.StatusServiceUnavailable, serviceUnavailableKey, "KNX bridge not running")
+		return
+	}
+
+	summary, err := s.knxKeyring.KeyringSummary(r.Context())
+	if err != nil {
+		s.logger.Error("reading KNX keyring failed", "error", err)
+		writeInternalError(w, "failed to read KNX keyring")
+		return
+	}
+	if summary == nil {
+		writeNotFound(w, "no KNX keyring imported")
+		return
+	}
+	writeJSON(w, http.StatusOK, summary)
+}
+
+// handleImportKNXKeyring imports an ETS keyring (.knxkeys) so the bridge
+// can encrypt and decrypt KNX Data Secure group telegrams. It replaces any
+// previously imported keyring.
+//
+// Request: multipart/form-data with "file" (the .knxkeys export) and
+// "password" (the password set when exporting from ETS).
+// Response: the keyring summary.
+func (s *Server) handleImportKNXKeyring(w http.ResponseWriter, r *http.Request) {
+	if s.knxKeyring == nil {
+		writeError(w, http.StatusServiceUnavailable, serviceUnavailableKey, "KNX bridge not running")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, knx.MaxKeyringSize)
+	if err := r.ParseMultipartForm(knx.MaxKeyringSize); err != nil {
+		writeBadRequest(w, "failed to parse multipart form: file may be too large")
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeBadRequest(w, "missing required 'file' field in form data")
+		return
+	}
+	defer file.Close()
+
+	password := r.FormValue("password")
+	if password == "" {
+		writeBadRequest(w, "missing required 'password' field in form data")
+		return
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeBadRequest(w, "failed to read uploaded file")
+		return
+	}
+
+	summary, err := s.knxKeyring.ImportKeyring(r.Context(), data, password)
+	switch {
+	case errors.Is(err, knx.ErrKeyringPassword):
+		writeBadRequest(w, "keyring password is wrong or the file was modified")
+		return
+	case errors.Is(err, knx.ErrInvalidKeyring):
+		writeBadRequest(w, err.Error())
+		return
+	case err != nil:
+		s.logger.Error("importing KNX keyring failed", "error", err)
+		writeInternalError(w, "failed to import KNX keyring")
+		return
+	}
+
+	claims := claimsFromContext(r.Context())
+	s.logger.Info("KNX keyring imported",
+		"project", summary.Project,
+		"group_addresses", len(summary.GroupAddresses),
+		"imported_by", claims.Subject,
+	)
+	s.auditLog("import", "knx_keyring", summary.Project, claims.Subject, map[string]any{
+		"group_addresses": len(summary.GroupAddresses),
+		"devices":         summary.Devices,
+	})
+
+	writeJSON(w, http.StatusOK, summary)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nerrad567/gray-logic-core/internal/bridges/knx"
+)
+
+// stubKeyringManager accepts any keyring whose password is "secret".
+type stubKeyringManager struct {
+	stored *knx.KeyringSummary
+}
+
+func (s *stubKeyringManager) ImportKeyring(_ context.Context, data []byte, password string) (knx.KeyringSummary, error) {
+	if password != "secret" {
+		return knx.KeyringSummary{}, knx.ErrKeyringPassword
+	}
+	if len(data) == 0 {
+		return knx.KeyringSummary{}, fmt.Errorf("%w: empty document", knx.ErrInvalidKeyring)
+	}
+	s.stored = &knx.KeyringSummary{Project: "Test House", GroupAddresses: []string{"1/2/3"}}
+	return *s.stored, nil
+}
+
+func (s *stubKeyringManager) KeyringSummary(context.Context) (*knx.KeyringSummary, error) {
+	return s.stored, nil
+}
+
+func keyringUpload(t *testing.T, data, password string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if password != "" {
+		_ = mw.WriteField("password", password)
+	}
+	fw, err := mw.CreateFormFile("file", "project.knxkeys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = fw.Write([]byte(data))
+	_ = mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/commissioning/knx/keyring", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return authReq(t, req)
+}
+
+func TestKNXKeyring(t *testing.T) {
+	srv, _ := testServer(t)
+	router := srv.buildRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, keyringUpload(t, "<Keyring/>", "secret"))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("without bridge: status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	srv.SetKNXKeyringManager(&stubKeyringManager{})
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/commissioning/knx/keyring", nil)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("before import: status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	tests := []struct {
+		name     string
+		data     string
+		password string
+		want     int
+	}{
+		{"missing password", "<Keyring/>", "", http.StatusBadRequest},
+		{"wrong password", "<Keyring/>", "wrong", http.StatusBadRequest},
+		{"invalid file", "", "secret", http.StatusBadRequest},
+		{"valid", "<Keyring/>", "secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, keyringUpload(t, tt.data, tt.password))
+		if w.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d; body: %s", tt.name, w.Code, tt.want, w.Body.String())
+		}
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/commissioning/knx/keyring", nil)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("after import: status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var summary knx.KeyringSummary
+	_ = json.Unmarshal(w.Body.Bytes(), &summary)
+	if summary.Project != "Test House" || len(summary.GroupAddresses) != 1 {
+		t.Errorf("summary = %+v", summary)
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nerrad567/gray-logic-core/internal/bridges/knx"
+)
+
+// defaultMonitorLimit is how many telegrams the bus monitor query returns
+// when no limit is given.
+const defaultMonitorLimit = 500
+
+// handleListKNXTelegrams returns recent KNX telegrams from the bus monitor.
+//
+// Query parameters (all optional): ga (comma-separated addresses or
+// prefixes such as "1/2"), source, device_id, apci, direction (rx/tx),
+// since (sequence number, for polling) and limit (newest N, default 500).
+func (s *Server) handleListKNXTelegrams(w http.ResponseWriter, r *http.Request) {
+	if s.knxMonitor == nil {
+		writeError(w, http.StatusServiceUnavailable, serviceUnavailableKey, "KNX bus monitor not running")
+		return
+	}
+
+	filter, err := parseMonitorFilter(r)
+	if err != nil {
+		writeBadRequest(w, err.Error())
+		return
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultMonitorLimit
+	}
+
+	telegrams := s.knxMonitor.Telegrams(filter)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"telegrams": telegrams,
+		"count":     len(telegrams),
+		"capacity":  s.knxMonitor.Capacity(),
+	})
+}
+
+// handleExportKNXTelegrams downloads the bus monitor's telegrams as an ETS
+// telegram capture (XML) that the ETS group monitor can open. Accepts the
+// same filters as the list; without a limit the whole buffer is exported.
+func (s *Server) handleExportKNXTelegrams(w http.ResponseWriter, r *http.Request) {
+	if s.knxMonitor == nil {
+		writeError(w, http.StatusServiceUnavailable, serviceUnavailableKey, "KNX bus monitor not running")
+		return
+	}
+
+	filter, err := parseMonitorFilter(r)
+	if err != nil {
+		writeBadRequest(w, err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("knx-monitor-%s.xml", time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/xml")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if err := s.knxMonitor.WriteCapture(w, filter); err != nil {
+		// Headers are already sent; the client sees a truncated file
+		s.logger.Error("writing KNX capture failed", "error", err)
+	}
+}
+
+// parseMonitorFilter reads bus monitor filters from the query string.
+func parseMonitorFilter(r *http.Request) (knx.MonitorFilter, error) {
+	q := r.URL.Query()
+	filter := knx.MonitorFilter{
+		Source:    q.Get("source"),
+		DeviceID:  q.Get("device_id"),
+		APCI:      q.Get("apci"),
+		Direction: q.Get("direction"),
+	}
+
+	if v := q.Get("ga"); v != "" {
+		for _, ga := range strings.Split(v, ",") {
+			if ga = strings.TrimSpace(ga); ga != "" {
+				filter.GroupAddresses = append(filter.GroupAddresses, ga)
+			}
+		}
+	}
+	if v := q.Get("since"); v != "" {
+		since, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("invalid since")
+		}
+		filter.Since = since
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return filter, fmt.Errorf("invalid limit")
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nerrad567/gray-logic-core/internal/auth"
+	"github.com/nerrad567/gray-logic-core/internal/bridges/knx"
+	"github.com/nerrad567/gray-logic-core/internal/infrastructure/config"
+	"github.com/nerrad567/gray-logic-core/internal/infrastructure/logging"
+)
+
+func testBusMonitor() *knx.BusMonitor {
+	m := knx.NewBusMonitor(100)
+	light := []knx.GAMapping{{DeviceID: "light-hall", Function: "switch", DPT: "1.001"}}
+	m.Record(knx.Telegram{Source: "1.1.5", Destination: knx.GroupAddress{Main: 1, Middle: 0, Sub: 1}, APCI: knx.APCIWrite, Data: []byte{0x01}}, knx.MonitorRx, light)
+	m.Record(knx.Telegram{Source: "1.1.6", Destination: knx.GroupAddress{Main: 2, Middle: 0, Sub: 1}, APCI: knx.APCIRead}, knx.MonitorRx, nil)
+	m.Record(knx.NewWriteTelegram(knx.GroupAddress{Main: 1, Middle: 0, Sub: 1}, []byte{0x00}), knx.MonitorTx, light)
+	return m
+}
+
+func TestListKNXTelegrams(t *testing.T) {
+	srv, _ := testServer(t)
+	router := srv.buildRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/commissioning/knx/monitor", nil)))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("without monitor: status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	srv.SetKNXBusMonitor(testBusMonitor())
+
+	tests := []struct {
+		query string
+		want  []uint64
+	}{
+		{"", []uint64{1, 2, 3}},
+		{"?ga=1", []uint64{1, 3}},
+		{"?ga=2/0/1,1/0/9", []uint64{2}},
+		{"?device_id=light-hall&direction=rx", []uint64{1}},
+		{"?apci=read", []uint64{2}},
+		{"?since=1", []uint64{2, 3}},
+		{"?limit=1", []uint64{3}},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/commissioning/knx/monitor"+tt.query, nil)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: status = %d; body: %s", tt.query, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Telegrams []knx.MonitorEntry `json:"telegrams"`
+			Capacity  int                `json:"capacity"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		var got []uint64
+		for _, e := range resp.Telegrams {
+			got = append(got, e.Seq)
+		}
+		if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) || resp.Capacity != 100 {
+			t.Errorf("%q: seqs = %v, want %v (capacity %d)", tt.query, got, tt.want, resp.Capacity)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/commissioning/knx/monitor?limit=x", nil)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid limit: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestExportKNXTelegrams(t *testing.T) {
+	srv, _ := testServer(t)
+	srv.SetKNXBusMonitor(testBusMonitor())
+	router := srv.buildRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/commissioning/knx/monitor/export?direction=rx", nil)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", w.Code, w.Body.String())
+	}
+	if cd := w.Header().Get("Content-Disposition"); !strings.Contains(cd, "attachment") || !strings.HasSuffix(cd, `.xml"`) {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "<CommunicationLog") || strings.Count(body, "<Telegram ") != 2 {
+		t.Errorf("capture = %s", body)
+	}
+}
+
+func TestWebSocket_KNXTelegramChannel(t *testing.T) {
+	log := logging.New(config.LoggingConfig{Level: "error", Format: "text", Output: "stdout"}, "test")
+	hub := NewHub(config.WebSocketConfig{MaxMessageSize: 8192, PingInterval: 30, PongTimeout: 10}, log)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	subscribe := func(role auth.Role, panelID string, filter *WSFilter) *WSClient {
+		c := &WSClient{hub: hub, send: make(chan []byte, wsSendBufferSize), subscriptions: map[string]*WSFilter{}, role: role, panelID: panelID}
+		hub.Register(c)
+		c.handleSubscribe(WSMessage{ID: "1", Payload: WSSubscribePayload{Channels: []string{WSChannelKNXTelegram}, Filter: filter}})
+		var resp WSMessage
+		_ = json.Unmarshal(<-c.send, &resp)
+		return c
+	}
+
+	admin := subscribe(auth.RoleAdmin, "", nil)
+	filtered := subscribe(auth.RoleAdmin, "", &WSFilter{GroupAddresses: []string{"1/0"}})
+	user := subscribe(auth.RoleUser, "", nil)
+	panel := subscribe("", "panel-1", nil)
+	for name, c := range map[string]*WSClient{"user": user, "panel": panel} {
+		if _, ok, _ := c.subscription(WSChannelKNXTelegram); ok {
+			t.Errorf("%s subscribed to the bus monitor without commission:manage", name)
+		}
+	}
+
+	monitor := testBusMonitor()
+	monitor.SetOnEntry(func(e knx.MonitorEntry) { hub.Broadcast(WSChannelKNXTelegram, e) })
+	monitor.Record(knx.Telegram{Source: "1.1.5", Destination: knx.GroupAddress{Main: 1, Middle: 0, Sub: 7}, APCI: knx.APCIWrite, Data: []byte{0x01}}, knx.MonitorRx, nil)
+	monitor.Record(knx.Telegram{Source: "1.1.5", Destination: knx.GroupAddress{Main: 1, Middle: 1, Sub: 7}, APCI: knx.APCIWrite, Data: []byte{0x01}}, knx.MonitorRx, nil)
+
+	count := func(c *WSClient) int {
+		n := 0
+		for {
+			select {
+			case <-c.send:
+				n++
+			case <-time.After(50 * time.Millisecond):
+				return n
+			}
+		}
+	}
+	if n := count(admin); n != 2 {
+		t.Errorf("admin received %d telegrams, want 2", n)
+	}
+	if n := count(filtered); n != 1 {
+		t.Errorf("group address filter received %d telegrams, want 1", n)
+	}
+}
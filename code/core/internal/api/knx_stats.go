@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultStatsTop is how many top sources and group addresses the bus
+// statistics return when no top is given.
+const defaultStatsTop = 10
+
+// handleGetKNXBusStats returns rolling KNX bus traffic statistics: load,
+// load per line, top sources and group addresses, and the read, write and
+// response mix.
+//
+// Query parameters (all optional): period (seconds, default and maximum
+// the configured window) and top (default 10).
+func (s *Server) handleGetKNXBusStats(w http.ResponseWriter, r *http.Request) {
+	if s.knxStats == nil {
+		writeError(w, http.StatusServiceUnavailable, serviceUnavailableKey, "KNX bus statistics not running")
+		return
+	}
+
+	period := s.knxStats.Window()
+	if v := r.URL.Query().Get("period"); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil || seconds <= 0 {
+			writeBadRequest(w, "invalid period")
+			return
+		}
+		period = min(time.Duration(seconds)*time.Second, period)
+	}
+
+	top := defaultStatsTop
+	if v := r.URL.Query().Get("top"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeBadRequest(w, "invalid top")
+			return
+		}
+		top = n
+	}
+
+	writeJSON(w, http.StatusOK, s.knxStats.Snapshot(period, top))
+}
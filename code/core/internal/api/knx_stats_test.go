@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nerrad567/gray-logic-core/internal/bridges/knx"
+)
+
+func TestGetKNXBusStats(t *testing.T) {
+	srv, _ := testServer(t)
+	router := srv.buildRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/commissioning/knx/stats", nil)))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("without stats: status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	stats := knx.NewBusStats(knx.StatsSettings{Window: 60, SampleInterval: 10})
+	for _, source := range []string{"1.1.5", "1.1.5", "1.2.1"} {
+		stats.Record(knx.Telegram{Source: source, Destination: knx.GroupAddress{Main: 1, Sub: 1}, APCI: knx.APCIWrite})
+	}
+	srv.SetKNXBusStats(stats)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/commissioning/knx/stats?period=600&top=1", nil)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var snap knx.BusStatsSnapshot
+	if err := json.Unmarshal(w.Body.Bytes(), &snap); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if snap.WindowSeconds != 60 || snap.Telegrams != 3 || len(snap.Lines) != 2 {
+		t.Errorf("snapshot = %+v, want 3 telegrams from 2 lines over 60s", snap)
+	}
+	if len(snap.TopSources) != 1 || snap.TopSources[0].Address != "1.1.5" {
+		t.Errorf("TopSources = %+v, want only 1.1.5", snap.TopSources)
+	}
+
+	for _, query := range []string{"?period=0", "?period=x", "?top=-1"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/commissioning/knx/stats"+query, nil)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%q: status = %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
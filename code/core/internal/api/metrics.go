@@ -44,6 +44,9 @@ type KNXMetrics struct {
 	TelegramsTx    uint64 `json:"telegrams_tx"`
 	TelegramsRx    uint64 `json:"telegrams_rx"`
 	DevicesManaged int    `json:"devices_managed"`
+	QueueDepth     int    `json:"queue_depth"`
+	QueueDropped   uint64 `json:"queue_dropped"`
+	QueueCoalesced uint64 `json:"queue_coalesced"`
 }
 
 // DeviceMetrics contains device registry statistics.
@@ -99,6 +102,9 @@ func (s *Server) handleMetrics(w http.ResponseWriter, _ *http.Request) {
 			TelegramsTx:    knxStats.TelegramsTx,
 			TelegramsRx:    knxStats.TelegramsRx,
 			DevicesManaged: knxStats.DevicesManaged,
+			QueueDepth:     knxStats.QueueDepth,
+			QueueDropped:   knxStats.QueueDropped,
+			QueueCoalesced: knxStats.QueueCoalesced,
 		}
 	}
 
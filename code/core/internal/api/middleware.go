@@ -488,27 +488,33 @@ func (s *Server) resolveRoomScopeMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		// Check cache first to avoid DB hit on every request for room-scoped users
-		if cached := s.scopeCache.get(claims.Subject); cached != nil {
-			ctx := context.WithValue(r.Context(), ctxKeyRoomScope, cached)
-			next.ServeHTTP(w, r.WithContext(ctx))
-			return
-		}
-
-		scope, err := s.roomAccessRepo.ResolveRoomScope(r.Context(), claims.Subject)
+		scope, err := s.resolveUserRoomScope(r.Context(), claims.Subject)
 		if err != nil {
 			s.logger.Error("failed to resolve room scope", "user_id", claims.Subject, "error", err)
 			writeInternalError(w, "failed to resolve room access")
 			return
 		}
 
-		s.scopeCache.set(claims.Subject, scope)
-
 		ctx := context.WithValue(r.Context(), ctxKeyRoomScope, scope)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// resolveUserRoomScope returns a room-scoped user's accessible rooms.
+// The cache is checked first to avoid a DB hit on every request.
+func (s *Server) resolveUserRoomScope(ctx context.Context, userID string) (*auth.RoomScope, error) {
+	if cached := s.scopeCache.get(userID); cached != nil {
+		return cached, nil
+	}
+
+	scope, err := s.roomAccessRepo.ResolveRoomScope(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.scopeCache.set(userID, scope)
+	return scope, nil
+}
+
 // requireUsersOnly rejects panel requests — only user JWT auth is accepted.
 func requireUsersOnly(next http.Handler) http.Handler { //nolint:unused // reserved for future route protection
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/nerrad567/gray-logic-core/internal/automation"
+)
+
+const modeChangeForbiddenMsg = "changing the site mode requires access to the whole site"
+
+// handleListModes returns all modes and the current mode.
+func (s *Server) handleListModes(w http.ResponseWriter, _ *http.Request) {
+	if s.modeManager == nil {
+		writeInternalError(w, "modes not configured")
+		return
+	}
+
+	modes := s.modeManager.ListModes()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"modes":   modes,
+		"current": s.modeManager.Current(),
+		"count":   len(modes),
+	})
+}
+
+// handleGetMode returns a single mode by ID.
+func (s *Server) handleGetMode(w http.ResponseWriter, r *http.Request) {
+	mode, ok := s.loadModeForRequest(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, mode)
+}
+
+// handleActivateMode makes the mode current: runs the old mode's exit
+// scene, applies the new mode's behaviour and runs its entry scene.
+// Modes are site-wide, so room-scoped callers cannot change them.
+func (s *Server) handleActivateMode(w http.ResponseWriter, r *http.Request) {
+	mode, ok := s.loadModeForRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if denied, _ := sceneAccessDenied(requestRoomScope(r.Context()), ""); denied {
+		writeForbidden(w, modeChangeForbiddenMsg)
+		return
+	}
+
+	change, err := s.modeManager.SetMode(r.Context(), mode.ID, "api", requestUserID(r.Context()))
+	if err != nil {
+		writeModeError(w, err, "failed to change mode")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, change)
+}
+
+// handleCreateMode creates a new mode.
+func (s *Server) handleCreateMode(w http.ResponseWriter, r *http.Request) {
+	if s.modeManager == nil {
+		writeInternalError(w, "modes not configured")
+		return
+	}
+
+	var mode automation.Mode
+	if err := json.NewDecoder(r.Body).Decode(&mode); err != nil {
+		writeBadRequest(w, "invalid JSON body")
+		return
+	}
+
+	if err := s.modeManager.CreateMode(r.Context(), &mode); err != nil {
+		writeModeError(w, err, "failed to create mode")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, mode)
+}
+
+// handleUpdateMode partially updates a mode. Behaviour is replaced as a
+// whole when present.
+func (s *Server) handleUpdateMode(w http.ResponseWriter, r *http.Request) {
+	existing, ok := s.loadModeForRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		writeBadRequest(w, "invalid JSON body")
+		return
+	}
+	if _, ok := raw["behaviour"]; ok {
+		existing.Behaviour = automation.ModeBehaviour{}
+	}
+	body, err := json.Marshal(raw)
+	if err != nil {
+		writeBadRequest(w, "invalid JSON body")
+		return
+	}
+
+	id := existing.ID
+	if err := json.Unmarshal(body, existing); err != nil {
+		writeBadRequest(w, "invalid JSON body")
+		return
+	}
+	existing.ID = id // Ensure ID cannot be changed
+
+	if err := s.modeManager.UpdateMode(r.Context(), existing); err != nil {
+		writeModeError(w, err, "failed to update mode")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, existing)
+}
+
+// handleDeleteMode removes a mode. Schedules that switch to it are
+// deleted with it.
+func (s *Server) handleDeleteMode(w http.ResponseWriter, r *http.Request) {
+	mode, ok := s.loadModeForRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.modeManager.DeleteMode(r.Context(), mode.ID); err != nil {
+		writeModeError(w, err, "failed to delete mode")
+		return
+	}
+
+	// Schedules targeting the mode are removed by ON DELETE CASCADE;
+	// reload so the scheduler drops them too.
+	if s.scheduler != nil {
+		if err := s.scheduler.RefreshCache(r.Context()); err != nil {
+			s.logger.Warn("failed to refresh schedules after mode delete", "mode_id", mode.ID, "error", err)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadModeForRequest loads the mode named by the {id} URL parameter.
+// Writes the error response and returns false on failure.
+func (s *Server) loadModeForRequest(w http.ResponseWriter, r *http.Request) (*automation.Mode, bool) {
+	if s.modeManager == nil {
+		writeInternalError(w, "modes not configured")
+		return nil, false
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" || len(id) > maxQueryParamLen {
+		writeBadRequest(w, "invalid mode ID")
+		return nil, false
+	}
+
+	mode, err := s.modeManager.GetMode(id)
+	if err != nil {
+		writeModeError(w, err, "failed to get mode")
+		return nil, false
+	}
+	return mode, true
+}
+
+// checkModeTarget verifies a schedule or rule's target mode exists. Modes
+// are site-wide, so the caller needs unrestricted scene management.
+func (s *Server) checkModeTarget(w http.ResponseWriter, r *http.Request, modeID string) bool {
+	if s.modeManager == nil {
+		writeInternalError(w, "modes not configured")
+		return false
+	}
+	if _, err := s.modeManager.GetMode(modeID); err != nil {
+		if errors.Is(err, automation.ErrModeNotFound) {
+			writeBadRequest(w, "execute.mode_id: mode not found")
+			return false
+		}
+		writeInternalError(w, "failed to get mode")
+		return false
+	}
+	if denied, message := sceneManageDenied(requestRoomScope(r.Context()), ""); denied {
+		writeForbidden(w, message)
+		return false
+	}
+	return true
+}
+
+// requestUserID returns the authenticated user's ID ("" for panels).
+func requestUserID(ctx context.Context) string {
+	if claims := claimsFromContext(ctx); claims != nil {
+		return claims.Subject
+	}
+	return ""
+}
+
+// writeModeError maps mode manager errors to HTTP responses.
+func writeModeError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, automation.ErrModeNotFound):
+		writeNotFound(w, "mode not found")
+	case errors.Is(err, automation.ErrInvalidMode):
+		writeBadRequest(w, err.Error())
+	case errors.Is(err, automation.ErrSceneNotFound):
+		writeBadRequest(w, "entry_scene_id/exit_scene_id: scene not found")
+	case errors.Is(err, automation.ErrModeExists), errors.Is(err, automation.ErrModeInUse):
+		writeConflict(w, err.Error())
+	default:
+		writeInternalError(w, fallback)
+	}
+}
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nerrad567/gray-logic-core/internal/automation"
+)
+
+// memoryModeSite is an in-memory automation.ModeSiteStore.
+type memoryModeSite struct {
+	current   string
+	available []string
+}
+
+func (m *memoryModeSite) GetCurrentMode(context.Context) (string, error) { return m.current, nil }
+
+func (m *memoryModeSite) SetCurrentMode(_ context.Context, modeID string) error {
+	m.current = modeID
+	return nil
+}
+
+func (m *memoryModeSite) SetAvailableModes(_ context.Context, ids []string) error {
+	m.available = ids
+	return nil
+}
+
+// testModeServer extends testScheduleServer with a mode manager backed by
+// in-memory SQLite holding the four default modes, current mode "home".
+func testModeServer(t *testing.T) (*Server, *memoryModeSite) {
+	t.Helper()
+
+	srv, scheduler := testScheduleServer(t)
+
+	site := &memoryModeSite{current: "home"}
+	manager := automation.NewModeManager(
+		automation.NewSQLiteModeRepository(setupModeTestDB(t)),
+		site, srv.sceneEngine, nil, nil, nil, nil,
+	)
+	manager.SetTargets(scheduler, nil)
+	if err := manager.RefreshCache(context.Background()); err != nil {
+		t.Fatalf("RefreshCache: %v", err)
+	}
+	srv.modeManager = manager
+
+	return srv, site
+}
+
+// setupModeTestDB creates an in-memory SQLite database with the modes schema.
+func setupModeTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	schema := `
+		CREATE TABLE modes (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			icon TEXT,
+			color TEXT,
+			sort_order INTEGER NOT NULL DEFAULT 0,
+			entry_scene_id TEXT,
+			exit_scene_id TEXT,
+			behaviour TEXT NOT NULL DEFAULT '{}',
+			created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+			updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+		) STRICT;
+		INSERT INTO modes (id, name, sort_order) VALUES
+			('home', 'Home', 0), ('away', 'Away', 1), ('night', 'Night', 2), ('holiday', 'Holiday', 3);
+	`
+	if _, execErr := db.Exec(schema); execErr != nil {
+		db.Close()
+		t.Fatalf("failed to create test schema: %v", execErr)
+	}
+
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestModes_ListAndActivate(t *testing.T) {
+	srv, site := testModeServer(t)
+	router := srv.buildRouter()
+
+	req := authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/modes", nil))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var list struct {
+		Modes   []automation.Mode `json:"modes"`
+		Current string            `json:"current"`
+		Count   int               `json:"count"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &list)
+	if list.Count != 4 || list.Current != "home" || list.Modes[0].ID != "home" {
+		t.Errorf("list = %+v", list)
+	}
+
+	req = authReq(t, httptest.NewRequest(http.MethodPost, "/api/v1/modes/night/activate", nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("activate status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var change automation.ModeChange
+	_ = json.Unmarshal(w.Body.Bytes(), &change)
+	if !change.Changed || change.From != "home" || change.To != "night" || change.UserID != "test-admin" {
+		t.Errorf("change = %+v", change)
+	}
+	if site.current != "night" {
+		t.Errorf("site current = %q, want night", site.current)
+	}
+
+	req = authReq(t, httptest.NewRequest(http.MethodPost, "/api/v1/modes/disco/activate", nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("activate unknown status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestModes_CRUD(t *testing.T) {
+	srv, site := testModeServer(t)
+	router := srv.buildRouter()
+
+	body := `{"name": "Party", "icon": "music", "entry_scene_id": "scene-1", "sort_order": 9}`
+	req := authReq(t, httptest.NewRequest(http.MethodPost, "/api/v1/modes", strings.NewReader(body)))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var created automation.Mode
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	if created.ID != "party" || created.EntrySceneID == nil {
+		t.Errorf("created = %+v", created)
+	}
+	if len(site.available) != 5 {
+		t.Errorf("available modes = %v", site.available)
+	}
+
+	req = authReq(t, httptest.NewRequest(http.MethodPatch, "/api/v1/modes/party",
+		strings.NewReader(`{"behaviour": {"enable_schedules": ["x"], "disable_schedules": ["x"]}}`)))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid behaviour status = %d; body: %s", w.Code, w.Body.String())
+	}
+
+	req = authReq(t, httptest.NewRequest(http.MethodPatch, "/api/v1/modes/party",
+		strings.NewReader(`{"name": "Party Time", "behaviour": {"disable_schedules": ["sched-1"]}}`)))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("patch status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var patched automation.Mode
+	_ = json.Unmarshal(w.Body.Bytes(), &patched)
+	if patched.ID != "party" || patched.Name != "Party Time" || patched.Icon != "music" || len(patched.Behaviour.DisableSchedules) != 1 {
+		t.Errorf("patched = %+v", patched)
+	}
+
+	req = authReq(t, httptest.NewRequest(http.MethodDelete, "/api/v1/modes/home", nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Errorf("delete current status = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	req = authReq(t, httptest.NewRequest(http.MethodDelete, "/api/v1/modes/party", nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d; body: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestModes_ScheduleTarget(t *testing.T) {
+	srv, _ := testModeServer(t)
+	router := srv.buildRouter()
+
+	body := `{
+		"name": "Bedtime",
+		"trigger": {"type": "time", "value": "23:00"},
+		"execute": {"type": "mode", "mode_id": "disco"}
+	}`
+	req := authReq(t, httptest.NewRequest(http.MethodPost, "/api/v1/schedules", strings.NewReader(body)))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unknown mode status = %d; body: %s", w.Code, w.Body.String())
+	}
+
+	body = strings.Replace(body, "disco", "night", 1)
+	req = authReq(t, httptest.NewRequest(http.MethodPost, "/api/v1/schedules", strings.NewReader(body)))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var created automation.Schedule
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	if created.Execute.Type != automation.ExecuteMode || created.Execute.ModeID != "night" {
+		t.Errorf("execute = %+v", created.Execute)
+	}
+}
+
+func TestModes_NotConfigured(t *testing.T) {
+	srv, _, _ := testSceneServer(t)
+	router := srv.buildRouter()
+
+	req := authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/modes", nil))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/nerrad567/gray-logic-core/internal/automation"
+	"github.com/nerrad567/gray-logic-core/internal/device"
+)
+
+// handleGetDeviceOverride returns the device's manual override latch.
+func (s *Server) handleGetDeviceOverride(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.loadOverrideDevice(w, r)
+	if !ok {
+		return
+	}
+
+	resp := map[string]any{
+		"device_id": id,
+		"status":    automation.OverrideStatusAuto,
+		"override":  nil,
+	}
+	if o, latched := s.overrides.Get(id); latched {
+		resp["status"] = automation.OverrideStatusOverride
+		resp["override"] = o
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleClearDeviceOverride clears the device's manual override latch,
+// returning it to automation control ("Resume Auto").
+func (s *Server) handleClearDeviceOverride(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.loadOverrideDevice(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.overrides.Clear(r.Context(), id, automation.OverrideResetManual); err != nil {
+		if errors.Is(err, automation.ErrOverrideNotFound) {
+			writeNotFound(w, "device is not under manual override")
+			return
+		}
+		writeInternalError(w, "failed to clear override")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadOverrideDevice checks the override latch is configured and the
+// device exists and is in the caller's room scope, writing an error
+// response if not.
+func (s *Server) loadOverrideDevice(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if s.overrides == nil {
+		writeInternalError(w, "manual override not configured")
+		return "", false
+	}
+
+	scope := requestRoomScope(r.Context())
+	if scope != nil && len(scope.RoomIDs) == 0 {
+		writeForbidden(w, "device not in accessible rooms")
+		return "", false
+	}
+
+	dev, err := s.registry.GetDevice(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, device.ErrDeviceNotFound) {
+			writeNotFound(w, "device not found")
+			return "", false
+		}
+		writeInternalError(w, "failed to get device")
+		return "", false
+	}
+
+	if !deviceInScope(scope, dev) {
+		writeForbidden(w, "device not in accessible rooms")
+		return "", false
+	}
+	return dev.ID, true
+}
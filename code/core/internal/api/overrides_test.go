@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nerrad567/gray-logic-core/internal/automation"
+)
+
+func TestDeviceOverride_GetAndClear(t *testing.T) {
+	srv, registry := testServer(t)
+	srv.overrides = automation.NewOverrideManager(automation.OverrideConfig{BypassPriority: 80}, nil, nil, nil)
+	router := srv.buildRouter()
+
+	dev := createDeviceWithRoom(t, registry, "Kitchen Light", "kitchen", "1/0/1")
+	path := "/api/v1/devices/" + dev.ID + "/override"
+
+	get := func() map[string]any {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodGet, path, nil)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("get status = %d; body: %s", w.Code, w.Body.String())
+		}
+		var resp map[string]any
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+
+	if resp := get(); resp["status"] != automation.OverrideStatusAuto || resp["override"] != nil {
+		t.Errorf("unlatched = %v", resp)
+	}
+
+	srv.overrides.Latch(context.Background(), dev.ID, "physical")
+	if resp := get(); resp["status"] != automation.OverrideStatusOverride || resp["override"] == nil {
+		t.Errorf("latched = %v", resp)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodDelete, path, nil)))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("clear status = %d; body: %s", w.Code, w.Body.String())
+	}
+	if _, ok := srv.overrides.Get(dev.ID); ok {
+		t.Error("latch not cleared")
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authReq(t, httptest.NewRequest(http.MethodDelete, path, nil)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("second clear status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -64,6 +64,8 @@ func (s *Server) buildRouter() http.Handler {
 				r.Get("/devices/{id}/history", s.handleGetDeviceHistory)
 				r.Get("/devices/{id}/metrics", s.handleGetDeviceMetrics)
 				r.Get("/devices/{id}/metrics/summary", s.handleGetDeviceMetricsSummary)
+				r.Get("/devices/{id}/override", s.handleGetDeviceOverride)
+				r.Get("/commands/{id}", s.handleGetCommand)
 
 				// Tags listing (all unique tags across devices)
 				r.Get("/tags", s.handleListAllTags)
@@ -81,6 +83,8 @@ func (s *Server) buildRouter() http.Handler {
 				r.Use(s.resolveRoomScopeMiddleware)
 
 				r.Put("/devices/{id}/state", s.handleSetDeviceState)
+				r.Post("/device-groups/{id}/command", s.handleGroupCommand)
+				r.Delete("/devices/{id}/override", s.handleClearDeviceOverride)
 			})
 
 			// ── device:configure — admin, owner only ──
@@ -108,6 +112,16 @@ func (s *Server) buildRouter() http.Handler {
 				r.Get("/scenes/{id}", s.handleGetScene)
 				r.Post("/scenes/{id}/activate", s.handleActivateScene)
 				r.Get("/scenes/{id}/executions", s.handleListSceneExecutions)
+
+				// Schedules (read — scoped by the target scene's room)
+				r.Get("/schedules", s.handleListSchedules)
+				r.Get("/schedules/{id}", s.handleGetSchedule)
+				r.Get("/schedules/{id}/next-runs", s.handleScheduleNextRuns)
+
+				// Site modes (read; activation requires whole-site access)
+				r.Get("/modes", s.handleListModes)
+				r.Get("/modes/{id}", s.handleGetMode)
+				r.Post("/modes/{id}/activate", s.handleActivateMode)
 			})
 
 			// ── scene:manage — user (room-scoped, can_manage_scenes), admin, owner ──
@@ -118,6 +132,23 @@ func (s *Server) buildRouter() http.Handler {
 				r.Post("/scenes", s.handleCreateScene)
 				r.Patch("/scenes/{id}", s.handleUpdateScene)
 				r.Delete("/scenes/{id}", s.handleDeleteScene)
+
+				// Schedules (write)
+				r.Post("/schedules", s.handleCreateSchedule)
+				r.Patch("/schedules/{id}", s.handleUpdateSchedule)
+				r.Delete("/schedules/{id}", s.handleDeleteSchedule)
+				r.Post("/schedules/{id}/enable", s.handleEnableSchedule)
+				r.Post("/schedules/{id}/disable", s.handleDisableSchedule)
+
+				// Automation rules (read and write — scoped by the rooms of
+				// the trigger device and the execute target)
+				r.Get("/automation-rules", s.handleListRules)
+				r.Get("/automation-rules/{id}", s.handleGetRule)
+				r.Post("/automation-rules", s.handleCreateRule)
+				r.Patch("/automation-rules/{id}", s.handleUpdateRule)
+				r.Delete("/automation-rules/{id}", s.handleDeleteRule)
+				r.Post("/automation-rules/{id}/enable", s.handleEnableRule)
+				r.Post("/automation-rules/{id}/disable", s.handleDisableRule)
 			})
 
 			// ── location:manage — admin, owner ──
@@ -147,6 +178,11 @@ func (s *Server) buildRouter() http.Handler {
 				r.Delete("/zones/{id}", s.handleDeleteZone)
 				r.Get("/zones/{id}/rooms", s.handleGetZoneRooms)
 				r.Put("/zones/{id}/rooms", s.handleSetZoneRooms)
+
+				// Site modes (definitions)
+				r.Post("/modes", s.handleCreateMode)
+				r.Patch("/modes/{id}", s.handleUpdateMode)
+				r.Delete("/modes/{id}", s.handleDeleteMode)
 			})
 
 			// ── commission:manage — admin, owner ──
@@ -155,6 +191,12 @@ func (s *Server) buildRouter() http.Handler {
 
 				r.Post("/commissioning/ets/parse", s.handleETSParse)
 				r.Post("/commissioning/ets/import", s.handleETSImport)
+				r.Get("/commissioning/knx/dpt-conflicts", s.handleListDPTConflicts)
+				r.Get("/commissioning/knx/keyring", s.handleGetKNXKeyring)
+				r.Post("/commissioning/knx/keyring", s.handleImportKNXKeyring)
+				r.Get("/commissioning/knx/monitor", s.handleListKNXTelegrams)
+				r.Get("/commissioning/knx/monitor/export", s.handleExportKNXTelegrams)
+				r.Get("/commissioning/knx/stats", s.handleGetKNXBusStats)
 			})
 
 			// ── system:admin — admin, owner ──
@@ -164,6 +206,8 @@ func (s *Server) buildRouter() http.Handler {
 				// System metrics and discovery (admin-only)
 				r.Get("/metrics", s.handleMetrics)
 				r.Get("/discovery", s.handleListDiscovery)
+				r.Get("/bridges", s.handleListBridges)
+				r.Get("/bridges/{id}", s.handleGetBridge)
 
 				r.Get("/site", s.handleGetSite)
 				r.Post("/site", s.handleCreateSite)
@@ -218,6 +218,20 @@ func (s *Server) handleDeleteScene(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Schedules targeting the scene are removed by ON DELETE CASCADE;
+	// reload so the scheduler drops them too.
+	if s.scheduler != nil {
+		if err := s.scheduler.RefreshCache(r.Context()); err != nil {
+			s.logger.Warn("failed to refresh schedules after scene delete", "scene_id", id, "error", err)
+		}
+	}
+	// Modes using it as an entry or exit scene have it cleared (ON DELETE SET NULL).
+	if s.modeManager != nil {
+		if err := s.modeManager.RefreshCache(r.Context()); err != nil {
+			s.logger.Warn("failed to refresh modes after scene delete", "scene_id", id, "error", err)
+		}
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -230,6 +244,8 @@ type activateRequest struct {
 // handleActivateScene activates a scene and returns the execution ID.
 // This is an asynchronous operation — MQTT commands are published to bridges
 // and the response is 202 Accepted. Device state changes arrive via WebSocket.
+// When the scene's conditions do not pass the response is 200 with status
+// "conditions_not_met" and nothing is sent.
 func (s *Server) handleActivateScene(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" || len(id) > maxQueryParamLen {
@@ -276,6 +292,16 @@ func (s *Server) handleActivateScene(w http.ResponseWriter, r *http.Request) {
 			writeInternalError(w, "MQTT not available")
 			return
 		}
+		if errors.Is(err, automation.ErrConditionsNotMet) {
+			// Not a failure: the scene was evaluated and deliberately not run.
+			writeJSON(w, http.StatusOK, map[string]any{
+				"execution_id": executionID,
+				"room_id":      derefString(scene.RoomID),
+				"status":       string(automation.StatusConditionsNotMet),
+				"message":      err.Error(),
+			})
+			return
+		}
 		writeInternalError(w, "failed to activate scene")
 		return
 	}
@@ -480,13 +506,18 @@ func isValidSceneCategory(category automation.Category) bool {
 // validateActionDeviceScope checks that all scene action device_ids are
 // accessible to the current user's room scope. Admins (nil scope) skip this.
 // Devices not yet in the registry are allowed (pre-commissioning use case).
+// Room-scoped users may target their own rooms; group, area and tag targets
+// can reach any room and are refused.
 func (s *Server) validateActionDeviceScope(ctx context.Context, scope *auth.RoomScope, actions []automation.SceneAction) error {
 	if scope == nil {
 		return nil // admins/owners have full access
 	}
 	for _, action := range actions {
-		if action.DeviceID == "" {
-			continue
+		if !action.IsDeviceTarget() {
+			if action.ScopeType == automation.ScopeRoom && scope.CanAccessRoom(action.ScopeID) {
+				continue
+			}
+			return fmt.Errorf("action target %s is outside accessible rooms", action.Target())
 		}
 		dev, err := s.registry.GetDevice(ctx, action.DeviceID)
 		if err != nil {
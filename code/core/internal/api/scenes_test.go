@@ -152,6 +152,7 @@ func setupSceneTestDB(t *testing.T) *sql.DB {
 			colour TEXT,
 			category TEXT,
 			actions TEXT NOT NULL DEFAULT '[]',
+			conditions TEXT,
 			sort_order INTEGER NOT NULL DEFAULT 0,
 			created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
 			updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
@@ -620,6 +621,42 @@ func TestActivateScene_Disabled(t *testing.T) {
 	}
 }
 
+func TestActivateScene_ConditionsNotMet(t *testing.T) {
+	srv, registry, mockMQTT := testSceneServer(t)
+	router := srv.buildRouter()
+
+	scene := &automation.Scene{
+		Name:    "Night Only",
+		Enabled: true,
+		Actions: []automation.SceneAction{
+			{DeviceID: "light-1", Command: "on", ContinueOnError: true},
+		},
+		// No mode source is configured, so a mode condition never passes.
+		Conditions: []automation.Condition{{Type: automation.ConditionMode, Modes: []string{"night"}}},
+	}
+	if err := registry.CreateScene(context.Background(), scene); err != nil {
+		t.Fatalf("CreateScene: %v", err)
+	}
+
+	req := authReq(t, httptest.NewRequest(http.MethodPost, "/api/v1/scenes/"+scene.ID+"/activate", nil))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("activate status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp["status"] != "conditions_not_met" || resp["execution_id"] == "" {
+		t.Errorf("response = %v", resp)
+	}
+	if len(mockMQTT.published) != 0 {
+		t.Errorf("published messages = %d, want 0", len(mockMQTT.published))
+	}
+}
+
 // ─── Scene Executions Tests ────────────────────────────────────────
 
 func TestListSceneExecutions(t *testing.T) {
@@ -0,0 +1,318 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/nerrad567/gray-logic-core/internal/automation"
+)
+
+const (
+	defaultNextRunsCount     = 5
+	scheduleNotAccessibleMsg = "schedule not in accessible rooms"
+)
+
+// handleListSchedules returns all schedules with their next run time.
+//
+// Query parameters:
+//   - enabled: "true" or "false" to filter by enabled state
+func (s *Server) handleListSchedules(w http.ResponseWriter, r *http.Request) {
+	if s.scheduler == nil {
+		writeInternalError(w, "schedules not configured")
+		return
+	}
+
+	var enabledFilter *bool
+	if v := r.URL.Query().Get("enabled"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			writeBadRequest(w, "enabled must be true or false")
+			return
+		}
+		enabledFilter = &b
+	}
+
+	ctx := r.Context()
+	scope := requestRoomScope(ctx)
+
+	schedules := make([]automation.Schedule, 0)
+	for _, sched := range s.scheduler.ListSchedules(ctx) {
+		if enabledFilter != nil && sched.Enabled != *enabledFilter {
+			continue
+		}
+		if denied, _ := sceneAccessDenied(scope, s.scheduleRoomID(ctx, &sched)); denied {
+			continue
+		}
+		schedules = append(schedules, sched)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"schedules": schedules, "count": len(schedules)})
+}
+
+// handleGetSchedule returns a single schedule by ID.
+func (s *Server) handleGetSchedule(w http.ResponseWriter, r *http.Request) {
+	sched, ok := s.loadScheduleForRequest(w, r, false)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, sched)
+}
+
+// handleCreateSchedule creates a new schedule.
+func (s *Server) handleCreateSchedule(w http.ResponseWriter, r *http.Request) {
+	if s.scheduler == nil {
+		writeInternalError(w, "schedules not configured")
+		return
+	}
+
+	var sched automation.Schedule
+	if err := json.NewDecoder(r.Body).Decode(&sched); err != nil {
+		writeBadRequest(w, "invalid JSON body")
+		return
+	}
+
+	if !s.checkScheduleTarget(w, r, &sched) {
+		return
+	}
+
+	if err := s.scheduler.CreateSchedule(r.Context(), &sched); err != nil {
+		writeScheduleError(w, err, "failed to create schedule")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, sched)
+}
+
+// handleUpdateSchedule partially updates a schedule.
+func (s *Server) handleUpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	existing, ok := s.loadScheduleForRequest(w, r, true)
+	if !ok {
+		return
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		writeBadRequest(w, "invalid JSON body")
+		return
+	}
+	// A trigger is replaced as a whole: merging {"type": "cron"} into an
+	// existing time trigger would otherwise keep its days.
+	if _, ok := raw["trigger"]; ok {
+		existing.Trigger = automation.ScheduleTrigger{}
+	}
+	// Likewise switching the target from a scene to a mode.
+	if _, ok := raw["execute"]; ok {
+		existing.Execute = automation.ScheduleExecute{}
+	}
+	if _, ok := raw["conditions"]; ok {
+		existing.Conditions = nil
+	}
+	body, err := json.Marshal(raw)
+	if err != nil {
+		writeBadRequest(w, "invalid JSON body")
+		return
+	}
+
+	id := existing.ID
+	if err := json.Unmarshal(body, existing); err != nil {
+		writeBadRequest(w, "invalid JSON body")
+		return
+	}
+	existing.ID = id // Ensure ID cannot be changed
+
+	if !s.checkScheduleTarget(w, r, existing) {
+		return
+	}
+
+	if err := s.scheduler.UpdateSchedule(r.Context(), existing); err != nil {
+		writeScheduleError(w, err, "failed to update schedule")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, existing)
+}
+
+// handleDeleteSchedule removes a schedule by ID.
+func (s *Server) handleDeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	sched, ok := s.loadScheduleForRequest(w, r, true)
+	if !ok {
+		return
+	}
+
+	if err := s.scheduler.DeleteSchedule(r.Context(), sched.ID); err != nil {
+		writeScheduleError(w, err, "failed to delete schedule")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleEnableSchedule enables a schedule.
+func (s *Server) handleEnableSchedule(w http.ResponseWriter, r *http.Request) {
+	s.setScheduleEnabled(w, r, true)
+}
+
+// handleDisableSchedule disables a schedule.
+func (s *Server) handleDisableSchedule(w http.ResponseWriter, r *http.Request) {
+	s.setScheduleEnabled(w, r, false)
+}
+
+func (s *Server) setScheduleEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	sched, ok := s.loadScheduleForRequest(w, r, true)
+	if !ok {
+		return
+	}
+
+	updated, err := s.scheduler.SetEnabled(r.Context(), sched.ID, enabled)
+	if err != nil {
+		writeScheduleError(w, err, "failed to update schedule")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// handleScheduleNextRuns previews upcoming run times for a schedule.
+//
+// Query parameters:
+//   - count: number of runs to return (default 5, max 50)
+func (s *Server) handleScheduleNextRuns(w http.ResponseWriter, r *http.Request) {
+	sched, ok := s.loadScheduleForRequest(w, r, false)
+	if !ok {
+		return
+	}
+
+	count := defaultNextRunsCount
+	if v := r.URL.Query().Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeBadRequest(w, "count must be a positive integer")
+			return
+		}
+		count = n
+	}
+
+	runs, err := s.scheduler.NextRuns(r.Context(), sched.ID, count)
+	if err != nil {
+		writeScheduleError(w, err, "failed to compute next runs")
+		return
+	}
+
+	utc := make([]time.Time, len(runs))
+	for i, t := range runs {
+		utc[i] = t.UTC()
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"schedule_id": sched.ID,
+		"next_runs":   utc,
+		"count":       len(utc),
+	})
+}
+
+// loadScheduleForRequest loads the schedule named by the {id} URL parameter
+// and checks the caller's room scope against the target scene's room.
+// When manage is true, scene management permission in that room is required.
+// Writes the error response and returns false on failure.
+func (s *Server) loadScheduleForRequest(w http.ResponseWriter, r *http.Request, manage bool) (*automation.Schedule, bool) {
+	if s.scheduler == nil {
+		writeInternalError(w, "schedules not configured")
+		return nil, false
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" || len(id) > maxQueryParamLen {
+		writeBadRequest(w, "invalid schedule ID")
+		return nil, false
+	}
+
+	sched, err := s.scheduler.GetSchedule(r.Context(), id)
+	if err != nil {
+		writeScheduleError(w, err, "failed to get schedule")
+		return nil, false
+	}
+
+	scope := requestRoomScope(r.Context())
+	roomID := s.scheduleRoomID(r.Context(), sched)
+	check := sceneAccessDenied
+	if manage {
+		check = sceneManageDenied
+	}
+	if denied, message := check(scope, roomID); denied {
+		if message == sceneNotAccessibleMessage {
+			message = scheduleNotAccessibleMsg
+		}
+		writeForbidden(w, message)
+		return nil, false
+	}
+
+	return sched, true
+}
+
+// checkScheduleTarget verifies the schedule's target scene or mode exists
+// and that the caller may manage scenes in its room.
+func (s *Server) checkScheduleTarget(w http.ResponseWriter, r *http.Request, sched *automation.Schedule) bool {
+	if sched.Execute.Type == automation.ExecuteMode && sched.Execute.ModeID != "" {
+		return s.checkModeTarget(w, r, sched.Execute.ModeID)
+	}
+	if sched.Execute.SceneID == "" {
+		// Let validation report the missing field.
+		return true
+	}
+
+	scene, err := s.sceneRegistry.GetScene(r.Context(), sched.Execute.SceneID)
+	if err != nil {
+		if errors.Is(err, automation.ErrSceneNotFound) {
+			writeBadRequest(w, "execute.scene_id: scene not found")
+			return false
+		}
+		writeInternalError(w, "failed to get scene")
+		return false
+	}
+
+	if denied, message := sceneManageDenied(requestRoomScope(r.Context()), derefString(scene.RoomID)); denied {
+		writeForbidden(w, message)
+		return false
+	}
+	return true
+}
+
+// scheduleRoomID returns the room of a schedule's target scene, or "" for
+// whole-site scenes, mode schedules and scenes that no longer exist.
+func (s *Server) scheduleRoomID(ctx context.Context, sched *automation.Schedule) string {
+	if sched.Execute.SceneID == "" || s.sceneRegistry == nil {
+		return ""
+	}
+	scene, err := s.sceneRegistry.GetScene(ctx, sched.Execute.SceneID)
+	if err != nil {
+		return ""
+	}
+	return derefString(scene.RoomID)
+}
+
+// writeScheduleError maps scheduler errors to HTTP responses.
+func writeScheduleError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, automation.ErrScheduleNotFound):
+		writeNotFound(w, "schedule not found")
+	case errors.Is(err, automation.ErrSceneNotFound):
+		writeBadRequest(w, "execute.scene_id: scene not found")
+	case errors.Is(err, automation.ErrModeNotFound):
+		writeBadRequest(w, "execute.mode_id: mode not found")
+	case errors.Is(err, automation.ErrInvalidSchedule), errors.Is(err, automation.ErrInvalidTrigger):
+		writeBadRequest(w, err.Error())
+	case errors.Is(err, automation.ErrSiteLocationRequired):
+		writeBadRequest(w, "site latitude and longitude must be set for sunrise/sunset schedules")
+	case errors.Is(err, automation.ErrNoNextRun):
+		writeBadRequest(w, err.Error())
+	case errors.Is(err, automation.ErrScheduleExists):
+		writeConflict(w, err.Error())
+	default:
+		writeInternalError(w, fallback)
+	}
+}
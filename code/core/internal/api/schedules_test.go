@@ -0,0 +1,221 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nerrad567/gray-logic-core/internal/automation"
+)
+
+// staticSiteProvider implements automation.SiteProvider for schedule tests.
+type staticSiteProvider struct {
+	info automation.SiteInfo
+}
+
+func (p staticSiteProvider) GetSiteInfo(context.Context) (automation.SiteInfo, error) {
+	return p.info, nil
+}
+
+// testScheduleServer extends testSceneServer with a scheduler backed by
+// in-memory SQLite and a single scene ("scene-1") to target.
+func testScheduleServer(t *testing.T) (*Server, *automation.Scheduler) {
+	t.Helper()
+
+	srv, registry, _ := testSceneServer(t)
+
+	scene := &automation.Scene{
+		ID:      "scene-1",
+		Name:    "Morning",
+		Enabled: true,
+		Actions: []automation.SceneAction{{DeviceID: "light-1", Command: "on", ContinueOnError: true}},
+	}
+	if err := registry.CreateScene(context.Background(), scene); err != nil {
+		t.Fatalf("CreateScene: %v", err)
+	}
+
+	db := setupScheduleTestDB(t)
+	scheduler := automation.NewScheduler(
+		automation.NewSQLiteScheduleRepository(db),
+		srv.sceneEngine,
+		staticSiteProvider{info: automation.SiteInfo{Timezone: "UTC"}},
+		nil, nil,
+	)
+	if err := scheduler.RefreshCache(context.Background()); err != nil {
+		t.Fatalf("RefreshCache: %v", err)
+	}
+	srv.scheduler = scheduler
+
+	return srv, scheduler
+}
+
+// setupScheduleTestDB creates an in-memory SQLite database with the schedules schema.
+func setupScheduleTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+
+	schema := `
+		CREATE TABLE schedules (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			slug TEXT NOT NULL UNIQUE,
+			description TEXT,
+			enabled INTEGER NOT NULL DEFAULT 1,
+			trigger_config TEXT NOT NULL,
+			execute_type TEXT NOT NULL DEFAULT 'scene',
+			scene_id TEXT,
+			mode_id TEXT,
+			conditions TEXT,
+			missed_run_policy TEXT NOT NULL DEFAULT 'skip',
+			missed_run_grace_min INTEGER NOT NULL DEFAULT 60,
+			last_run_at TEXT,
+			created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+			updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+		) STRICT;
+	`
+	if _, execErr := db.Exec(schema); execErr != nil {
+		db.Close()
+		t.Fatalf("failed to create test schema: %v", execErr)
+	}
+
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestScheduleCRUD(t *testing.T) {
+	srv, _ := testScheduleServer(t)
+	router := srv.buildRouter()
+
+	body := `{
+		"name": "Weekday Morning",
+		"enabled": true,
+		"trigger": {"type": "time", "value": "06:30", "days": ["mon", "tue", "wed", "thu", "fri"]},
+		"execute": {"type": "scene", "scene_id": "scene-1"}
+	}`
+	req := authReq(t, httptest.NewRequest(http.MethodPost, "/api/v1/schedules", strings.NewReader(body)))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want %d; body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+
+	var created automation.Schedule
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if created.ID == "" || created.Slug != "weekday-morning" {
+		t.Errorf("id/slug = %q/%q", created.ID, created.Slug)
+	}
+	if created.NextRunAt == nil {
+		t.Error("expected next_run to be populated")
+	}
+
+	// List
+	req = authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/schedules?enabled=true", nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var list map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &list)
+	if int(list["count"].(float64)) != 1 {
+		t.Errorf("count = %v, want 1", list["count"])
+	}
+
+	// Patch
+	req = authReq(t, httptest.NewRequest(http.MethodPatch, "/api/v1/schedules/"+created.ID,
+		strings.NewReader(`{"trigger": {"type": "cron", "value": "0 7 * * *"}}`)))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("patch status = %d; body: %s", w.Code, w.Body.String())
+	}
+
+	// Next runs
+	req = authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/schedules/"+created.ID+"/next-runs?count=3", nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("next-runs status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var runs map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &runs)
+	if int(runs["count"].(float64)) != 3 {
+		t.Errorf("next-runs count = %v, want 3", runs["count"])
+	}
+
+	// Disable
+	req = authReq(t, httptest.NewRequest(http.MethodPost, "/api/v1/schedules/"+created.ID+"/disable", nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("disable status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var disabled automation.Schedule
+	_ = json.Unmarshal(w.Body.Bytes(), &disabled)
+	if disabled.Enabled || disabled.NextRunAt != nil {
+		t.Errorf("disabled schedule: enabled=%v next_run=%v", disabled.Enabled, disabled.NextRunAt)
+	}
+
+	// Delete
+	req = authReq(t, httptest.NewRequest(http.MethodDelete, "/api/v1/schedules/"+created.ID, nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d; body: %s", w.Code, w.Body.String())
+	}
+
+	req = authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/schedules/"+created.ID, nil))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("get after delete status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestCreateSchedule_Validation(t *testing.T) {
+	srv, _ := testScheduleServer(t)
+	router := srv.buildRouter()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown scene", `{"name": "x", "trigger": {"type": "time", "value": "06:30"}, "execute": {"scene_id": "nope"}}`},
+		{"bad time", `{"name": "x", "trigger": {"type": "time", "value": "25:00"}, "execute": {"scene_id": "scene-1"}}`},
+		{"bad cron", `{"name": "x", "trigger": {"type": "cron", "value": "* *"}, "execute": {"scene_id": "scene-1"}}`},
+		{"sun without location", `{"name": "x", "trigger": {"type": "sunset", "value": "-15m"}, "execute": {"scene_id": "scene-1"}}`},
+		{"invalid JSON", `{`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := authReq(t, httptest.NewRequest(http.MethodPost, "/api/v1/schedules", strings.NewReader(tt.body)))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d; body: %s", w.Code, http.StatusBadRequest, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSchedules_NotConfigured(t *testing.T) {
+	srv, _, _ := testSceneServer(t)
+	router := srv.buildRouter()
+
+	req := authReq(t, httptest.NewRequest(http.MethodGet, "/api/v1/schedules", nil))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"sync"
 	"time"
@@ -25,6 +26,8 @@ import (
 	"github.com/nerrad567/gray-logic-core/internal/audit"
 	"github.com/nerrad567/gray-logic-core/internal/auth"
 	"github.com/nerrad567/gray-logic-core/internal/automation"
+	"github.com/nerrad567/gray-logic-core/internal/bridges"
+	"github.com/nerrad567/gray-logic-core/internal/bridges/knx"
 	"github.com/nerrad567/gray-logic-core/internal/device"
 	"github.com/nerrad567/gray-logic-core/internal/infrastructure/config"
 	"github.com/nerrad567/gray-logic-core/internal/infrastructure/logging"
@@ -51,6 +54,9 @@ type KNXBridgeMetrics struct {
 	TelegramsTx    uint64
 	TelegramsRx    uint64
 	DevicesManaged int
+	QueueDepth     int
+	QueueDropped   uint64
+	QueueCoalesced uint64
 }
 
 // KNXMetricsProvider is an interface for getting KNX bridge metrics.
@@ -59,6 +65,51 @@ type KNXMetricsProvider interface {
 	GetMetrics() KNXBridgeMetrics
 }
 
+// KNXDPTConflict is a group address that devices map with incompatible DPTs.
+type KNXDPTConflict struct {
+	GA       string                  `json:"ga"`
+	DPTs     []string                `json:"dpts"`
+	Mappings []KNXDPTConflictMapping `json:"mappings"`
+}
+
+// KNXDPTConflictMapping is one device function involved in a DPT conflict.
+type KNXDPTConflictMapping struct {
+	DeviceID string `json:"device_id"`
+	Function string `json:"function"`
+	DPT      string `json:"dpt"`
+}
+
+// KNXDPTConflictProvider is an interface for listing the KNX bridge's
+// group address DPT conflicts without importing the knx package.
+type KNXDPTConflictProvider interface {
+	DPTConflicts() []KNXDPTConflict
+}
+
+// KNXKeyringManager is an interface for importing the ETS keyring that
+// holds the KNX Secure keys, and describing the stored one.
+type KNXKeyringManager interface {
+	// ImportKeyring verifies, decrypts, stores and loads a .knxkeys file.
+	ImportKeyring(ctx context.Context, data []byte, password string) (knx.KeyringSummary, error)
+
+	// KeyringSummary describes the stored keyring, or returns nil if none
+	// has been imported.
+	KeyringSummary(ctx context.Context) (*knx.KeyringSummary, error)
+}
+
+// KNXBusMonitor is an interface for querying and exporting the KNX bus
+// monitor's recent telegrams.
+type KNXBusMonitor interface {
+	Telegrams(filter knx.MonitorFilter) []knx.MonitorEntry
+	WriteCapture(w io.Writer, filter knx.MonitorFilter) error
+	Capacity() int
+}
+
+// KNXBusStats is an interface for the KNX bus traffic statistics.
+type KNXBusStats interface {
+	Snapshot(period time.Duration, top int) knx.BusStatsSnapshot
+	Window() time.Duration
+}
+
 // DBStatsProvider is an interface for getting database statistics and access.
 type DBStatsProvider interface {
 	Stats() sql.DBStats
@@ -78,6 +129,12 @@ type Deps struct {
 	SceneEngine    *automation.Engine
 	SceneRegistry  *automation.Registry
 	SceneRepo      automation.Repository
+	Scheduler      *automation.Scheduler       // Optional: time/sun-based scene schedules
+	RuleEngine     *automation.RuleEngine      // Optional: event-driven automation rules
+	ModeManager    *automation.ModeManager     // Optional: site modes (home/away/night/holiday)
+	Overrides      *automation.OverrideManager // Optional: manual override latch
+	Commands       *automation.CommandTracker  // Optional: command acknowledgement tracking
+	Bridges        *bridges.Registry           // Optional: protocol bridge health
 	LocationRepo   location.Repository
 	TagRepo        device.TagRepository
 	GroupRepo      device.GroupRepository
@@ -111,6 +168,12 @@ type Server struct {
 	sceneEngine        *automation.Engine
 	sceneRegistry      *automation.Registry
 	sceneRepo          automation.Repository
+	scheduler          *automation.Scheduler
+	ruleEngine         *automation.RuleEngine
+	modeManager        *automation.ModeManager
+	overrides          *automation.OverrideManager
+	commands           *automation.CommandTracker
+	bridges            *bridges.Registry
 	locationRepo       location.Repository
 	tagRepo            device.TagRepository
 	groupRepo          device.GroupRepository
@@ -131,14 +194,18 @@ type Server struct {
 	externalHub        bool               // true if hub was injected externally
 	cancel             context.CancelFunc // cancels background goroutines on Close()
 	rateLimiter        *rateLimiter
-	panelCache         *panelAuthCache      // in-memory cache for panel token auth lookups
-	scopeCache         *roomScopeCache      // in-memory cache for user room scope resolution
-	wsTickets          *ticketStore         // WebSocket auth ticket store (moved from package-level)
-	jwtSecretBytes     []byte               // pre-converted JWT secret to avoid per-request allocation
-	auditCh            chan *audit.AuditLog // buffered channel for async audit log writes
-	knxBridge          KNXBridgeReloader    // optional: for reloading devices after ETS import
-	knxMetricsProvider KNXMetricsProvider   // optional: for metrics endpoint
-	factoryResetMu     sync.Mutex           // serialises factory reset operations
+	panelCache         *panelAuthCache        // in-memory cache for panel token auth lookups
+	scopeCache         *roomScopeCache        // in-memory cache for user room scope resolution
+	wsTickets          *ticketStore           // WebSocket auth ticket store (moved from package-level)
+	jwtSecretBytes     []byte                 // pre-converted JWT secret to avoid per-request allocation
+	auditCh            chan *audit.AuditLog   // buffered channel for async audit log writes
+	knxBridge          KNXBridgeReloader      // optional: for reloading devices after ETS import
+	knxMetricsProvider KNXMetricsProvider     // optional: for metrics endpoint
+	knxDPTConflicts    KNXDPTConflictProvider // optional: for commissioning DPT conflict list
+	knxKeyring         KNXKeyringManager      // optional: for KNX Secure keyring import
+	knxMonitor         KNXBusMonitor          // optional: for the KNX bus monitor
+	knxStats           KNXBusStats            // optional: for KNX bus load statistics
+	factoryResetMu     sync.Mutex             // serialises factory reset operations
 }
 
 // New creates a new API server with the given dependencies.
@@ -172,6 +239,12 @@ func New(deps Deps) (*Server, error) {
 		sceneEngine:    deps.SceneEngine,
 		sceneRegistry:  deps.SceneRegistry,
 		sceneRepo:      deps.SceneRepo,
+		scheduler:      deps.Scheduler,
+		ruleEngine:     deps.RuleEngine,
+		modeManager:    deps.ModeManager,
+		overrides:      deps.Overrides,
+		commands:       deps.Commands,
+		bridges:        deps.Bridges,
 		locationRepo:   deps.LocationRepo,
 		tagRepo:        deps.TagRepo,
 		groupRepo:      deps.GroupRepo,
@@ -217,6 +290,28 @@ func (s *Server) SetKNXMetricsProvider(provider KNXMetricsProvider) {
 	s.knxMetricsProvider = provider
 }
 
+// SetKNXDPTConflictProvider sets the KNX DPT conflict provider for the
+// commissioning conflict list.
+func (s *Server) SetKNXDPTConflictProvider(provider KNXDPTConflictProvider) {
+	s.knxDPTConflicts = provider
+}
+
+// SetKNXKeyringManager sets the KNX Secure keyring manager for the
+// commissioning keyring import.
+func (s *Server) SetKNXKeyringManager(manager KNXKeyringManager) {
+	s.knxKeyring = manager
+}
+
+// SetKNXBusMonitor sets the KNX bus monitor for commissioning diagnostics.
+func (s *Server) SetKNXBusMonitor(monitor KNXBusMonitor) {
+	s.knxMonitor = monitor
+}
+
+// SetKNXBusStats sets the KNX bus traffic statistics for diagnostics.
+func (s *Server) SetKNXBusStats(stats KNXBusStats) {
+	s.knxStats = stats
+}
+
 // Start begins listening for HTTP connections.
 //
 // It sets up the router, starts the WebSocket hub, subscribes to MQTT state
@@ -239,6 +334,7 @@ func (s *Server) Start(ctx context.Context) error {
 		s.hub = NewHub(s.wsCfg, s.logger)
 		go s.hub.Run(srvCtx)
 	}
+	s.hub.SetRegistry(s.registry)
 
 	// Start audit log drain goroutine (replaces per-call goroutines)
 	if s.auditRepo != nil {
@@ -256,6 +352,16 @@ func (s *Server) Start(ctx context.Context) error {
 		s.logger.Warn("failed to subscribe to state updates for WebSocket", "error", err)
 	}
 
+	// Subscribe to command acknowledgements from bridges for command tracking
+	if err := s.subscribeCommandAcks(); err != nil {
+		s.logger.Warn("failed to subscribe to command acknowledgements", "error", err)
+	}
+
+	// Subscribe to bridge health reports for the bridges API
+	if err := s.subscribeBridgeHealth(); err != nil {
+		s.logger.Warn("failed to subscribe to bridge health", "error", err)
+	}
+
 	// Build router
 	router := s.buildRouter()
 
@@ -1383,7 +1383,7 @@ func TestHub_BroadcastToSubscribed(t *testing.T) {
 	client := &WSClient{
 		hub:           hub,
 		send:          make(chan []byte, wsSendBufferSize),
-		subscriptions: map[string]struct{}{"device.state_changed": {}},
+		subscriptions: map[string]*WSFilter{"device.state_changed": nil},
 	}
 	hub.Register(client)
 
@@ -1417,7 +1417,7 @@ func TestHub_NoMessageForUnsubscribed(t *testing.T) {
 	client := &WSClient{
 		hub:           hub,
 		send:          make(chan []byte, wsSendBufferSize),
-		subscriptions: map[string]struct{}{"scene.activated": {}},
+		subscriptions: map[string]*WSFilter{"scene.activated": nil},
 	}
 	hub.Register(client)
 
@@ -1432,6 +1432,96 @@ func TestHub_NoMessageForUnsubscribed(t *testing.T) {
 	}
 }
 
+func TestHub_RoomScopeAndFilters(t *testing.T) {
+	_, registry := testServer(t)
+	log := logging.New(config.LoggingConfig{Level: "error", Format: "text", Output: "stdout"}, "test")
+	hub := NewHub(config.WebSocketConfig{MaxMessageSize: 8192, PingInterval: 30, PongTimeout: 10}, log)
+	hub.SetRegistry(registry)
+
+	kitchen := createDeviceWithRoom(t, registry, "Kitchen Light", "kitchen", "1/0/1")
+	bedroom := createDeviceWithRoom(t, registry, "Bedroom Light", "bedroom", "1/0/2")
+
+	newClient := func(scope *auth.RoomScope, subs map[string]*WSFilter) *WSClient {
+		c := &WSClient{hub: hub, send: make(chan []byte, wsSendBufferSize), subscriptions: subs, scope: scope}
+		hub.Register(c)
+		return c
+	}
+	received := func(c *WSClient) []string {
+		var ids []string
+		for {
+			select {
+			case msg := <-c.send:
+				var wsMsg struct {
+					EventType string         `json:"event_type"`
+					Payload   map[string]any `json:"payload"`
+				}
+				_ = json.Unmarshal(msg, &wsMsg)
+				id, _ := wsMsg.Payload["device_id"].(string)
+				ids = append(ids, wsMsg.EventType+":"+id)
+			default:
+				return ids
+			}
+		}
+	}
+
+	all := newClient(nil, map[string]*WSFilter{"device.state_changed": nil, "mode.changed": nil})
+	panel := newClient(&auth.RoomScope{RoomIDs: []string{"bedroom"}}, map[string]*WSFilter{"device.state_changed": nil, "mode.changed": nil})
+	byDevice := newClient(nil, map[string]*WSFilter{"device.state_changed": {DeviceIDs: []string{kitchen.ID}}})
+	byRoom := newClient(nil, map[string]*WSFilter{"device.state_changed": {RoomIDs: []string{"bedroom"}}})
+	byDomain := newClient(nil, map[string]*WSFilter{"device.state_changed": {Domains: []string{"climate"}}})
+
+	hub.Broadcast("device.state_changed", map[string]any{"device_id": kitchen.ID, "state": map[string]any{"on": true}})
+	hub.Broadcast("device.state_changed", map[string]any{"device_id": bedroom.ID, "state": map[string]any{"on": true}})
+	hub.Broadcast("device.state_changed", map[string]any{"device_id": "unknown-device"})
+	hub.Broadcast("mode.changed", map[string]any{"new_mode": "night"})
+
+	tests := []struct {
+		name   string
+		client *WSClient
+		want   []string
+	}{
+		{"unrestricted", all, []string{"device.state_changed:" + kitchen.ID, "device.state_changed:" + bedroom.ID, "device.state_changed:unknown-device", "mode.changed:"}},
+		{"bedroom panel", panel, []string{"device.state_changed:" + bedroom.ID, "mode.changed:"}},
+		{"device filter", byDevice, []string{"device.state_changed:" + kitchen.ID}},
+		{"room filter", byRoom, []string{"device.state_changed:" + bedroom.ID}},
+		{"domain filter", byDomain, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := received(tt.client)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("received %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWSTicket_CarriesRoomScope(t *testing.T) {
+	srv, _, authDB := testServerWithAuth(t)
+	router := srv.buildRouter()
+
+	seedAuthRooms(t, authDB)
+	user := createTestUser(t, srv.userRepo, "user-ws", "wsuser", "testpass123", auth.RoleUser, true)
+	if err := srv.roomAccessRepo.SetRoomAccess(context.Background(), user.ID, []auth.RoomAccessGrant{{RoomID: "room-a"}}, "test-admin"); err != nil {
+		t.Fatalf("set room access: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/ws-ticket", nil)
+	req.Header.Set("Authorization", "Bearer "+testRoleToken(t, auth.RoleUser, user.ID))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d; body: %s", w.Code, w.Body.String())
+	}
+
+	var resp map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	entry, ok := srv.validateTicket(resp["ticket"].(string))
+	if !ok || entry.scope == nil || len(entry.scope.RoomIDs) != 1 || entry.scope.RoomIDs[0] != "room-a" {
+		t.Errorf("ticket scope = %+v, valid %v", entry.scope, ok)
+	}
+}
+
 func TestHub_ClientCount(t *testing.T) {
 	log := logging.New(config.LoggingConfig{Level: "error", Format: "text", Output: "stdout"}, "test")
 	hub := NewHub(config.WebSocketConfig{MaxMessageSize: 8192, PingInterval: 30, PongTimeout: 10}, log)
@@ -1447,7 +1537,7 @@ func TestHub_ClientCount(t *testing.T) {
 	client := &WSClient{
 		hub:           hub,
 		send:          make(chan []byte, wsSendBufferSize),
-		subscriptions: make(map[string]struct{}),
+		subscriptions: make(map[string]*WSFilter),
 	}
 	hub.Register(client)
 
@@ -2003,6 +2093,20 @@ func TestCommandToState(t *testing.T) {
 			current: device.State{},
 			want:    device.State{"brightness": float64(75)},
 		},
+		{
+			name:    "write_function sets function state key",
+			command: "write_function",
+			params:  map[string]any{"function": "scene_number", "value": float64(4)},
+			current: device.State{},
+			want:    device.State{"scene": float64(4)},
+		},
+		{
+			name:    "read_function leaves state unchanged",
+			command: "read_function",
+			params:  map[string]any{"function": "percentage"},
+			current: device.State{"percentage": float64(10)},
+			want:    device.State{"percentage": float64(10)},
+		},
 		{
 			name:    "set_position sets position",
 			command: "set_position",
@@ -2024,6 +2128,13 @@ func TestCommandToState(t *testing.T) {
 			current: device.State{},
 			want:    device.State{"mode": "auto"},
 		},
+		{
+			name:    "set_color_temp sets color_temp",
+			command: "set_color_temp",
+			params:  map[string]any{"color_temp": float64(2700)},
+			current: device.State{},
+			want:    device.State{"color_temp": float64(2700)},
+		},
 		{
 			name:    "unknown command returns current state",
 			command: "unknown_command",
@@ -86,6 +86,14 @@ func (s *Server) handleCreateSite(w http.ResponseWriter, r *http.Request) {
 
 	s.logger.Info("site created", "id", site.ID, "name", site.Name)
 
+	// Pick up the new site's current mode and replace its available modes
+	// with the defined ones.
+	if s.modeManager != nil {
+		if err := s.modeManager.RefreshCache(r.Context()); err != nil {
+			s.logger.Warn("failed to refresh modes after site create", "error", err)
+		}
+	}
+
 	// Re-read from DB to get timestamps populated by SQLite defaults.
 	created, err := s.locationRepo.GetAnySite(r.Context())
 	if err != nil {
@@ -162,7 +170,14 @@ func (s *Server) handleUpdateSite(w http.ResponseWriter, r *http.Request) { //no
 			site.ElevationM = elev
 		}
 	}
+	// With a mode manager, modes_available mirrors the modes table and
+	// mode_current changes go through a full mode transition.
+	var modeChange string
 	if v, ok := raw["modes_available"]; ok {
+		if s.modeManager != nil {
+			writeBadRequest(w, "modes_available is managed via /modes")
+			return
+		}
 		var modes []string
 		if json.Unmarshal(v, &modes) == nil && len(modes) > 0 {
 			site.ModesAvailable = modes
@@ -183,7 +198,11 @@ func (s *Server) handleUpdateSite(w http.ResponseWriter, r *http.Request) { //no
 				writeBadRequest(w, "mode_current must be one of modes_available: "+strings.Join(site.ModesAvailable, ", "))
 				return
 			}
-			site.ModeCurrent = mode
+			if s.modeManager != nil {
+				modeChange = mode
+			} else {
+				site.ModeCurrent = mode
+			}
 		}
 	}
 
@@ -193,6 +212,13 @@ func (s *Server) handleUpdateSite(w http.ResponseWriter, r *http.Request) { //no
 		return
 	}
 
+	if modeChange != "" {
+		if _, err := s.modeManager.SetMode(r.Context(), modeChange, "api", requestUserID(r.Context())); err != nil { //nolint:govet // shadow: err re-declared in nested scope, checked immediately
+			writeModeError(w, err, "failed to change mode")
+			return
+		}
+	}
+
 	s.logger.Info("site updated", "id", site.ID, "name", site.Name)
 
 	// Re-read from DB to get the updated_at timestamp.
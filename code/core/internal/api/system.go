@@ -172,6 +172,7 @@ func (s *Server) handleFactoryReset(w http.ResponseWriter, r *http.Request) { //
 		if err := s.registry.RefreshCache(ctx); err != nil {
 			s.logger.Warn("factory reset: failed to refresh device cache", "error", err)
 		}
+		s.invalidateSceneTargets()
 	}
 	if req.ClearScenes && s.sceneRegistry != nil {
 		if err := s.sceneRegistry.RefreshCache(ctx); err != nil {
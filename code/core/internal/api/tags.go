@@ -76,6 +76,7 @@ func (s *Server) handleSetDeviceTags(w http.ResponseWriter, r *http.Request) {
 		writeInternalError(w, "failed to set tags")
 		return
 	}
+	s.invalidateSceneTargets()
 
 	// Re-read tags to return the normalised set
 	tags, err := s.tagRepo.GetTags(r.Context(), id)
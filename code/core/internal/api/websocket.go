@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -27,8 +28,17 @@ const (
 
 	// wsSendBufferSize is the per-client outbound message buffer size.
 	wsSendBufferSize = 256
+
+	// WSChannelKNXTelegram streams the KNX bus monitor.
+	WSChannelKNXTelegram = "knx.telegram"
 )
 
+// wsChannelPermissions lists channels that need a permission beyond a
+// valid ticket to subscribe to.
+var wsChannelPermissions = map[string]auth.Permission{
+	WSChannelKNXTelegram: auth.PermCommissionManage,
+}
+
 // WSMessage represents a message sent to/from a WebSocket client.
 type WSMessage struct {
 	Type      string `json:"type"`
@@ -39,16 +49,32 @@ type WSMessage struct {
 }
 
 // WSSubscribePayload is the payload for subscribe/unsubscribe messages.
+// Filter is optional on subscribe; without it the client receives every
+// event on the channels (within its room scope).
 type WSSubscribePayload struct {
-	Channels []string `json:"channels"`
+	Channels []string  `json:"channels"`
+	Filter   *WSFilter `json:"filter,omitempty"`
+}
+
+// WSFilter narrows a channel subscription to specific devices, rooms,
+// domains or KNX group addresses. Each non-empty list must match (an event
+// matches a list if its value is in it; group addresses also match by
+// prefix, so "1/2" matches 1/2/3); events lacking the filtered field do
+// not match.
+type WSFilter struct {
+	DeviceIDs      []string `json:"device_ids,omitempty"`
+	RoomIDs        []string `json:"room_ids,omitempty"`
+	Domains        []string `json:"domains,omitempty"`
+	GroupAddresses []string `json:"group_addresses,omitempty"`
 }
 
 // Hub manages WebSocket connections and broadcasts events.
 type Hub struct {
-	cfg     config.WebSocketConfig
-	logger  *logging.Logger
-	clients map[*WSClient]struct{}
-	mu      sync.RWMutex
+	cfg      config.WebSocketConfig
+	logger   *logging.Logger
+	clients  map[*WSClient]struct{}
+	mu       sync.RWMutex
+	registry *device.Registry // For resolving an event's device to its room and domain
 }
 
 // WSClient represents a connected WebSocket client.
@@ -56,12 +82,23 @@ type WSClient struct {
 	hub           *Hub
 	conn          *websocket.Conn
 	send          chan []byte
-	subscriptions map[string]struct{}
+	subscriptions map[string]*WSFilter // channel -> filter (nil: whole channel)
 	mu            sync.RWMutex
 	// Identity fields propagated from the WebSocket ticket.
-	userID  string    // non-empty for user connections
-	role    auth.Role // role of the authenticated caller
-	panelID string    // non-empty for panel connections
+	userID  string          // non-empty for user connections
+	role    auth.Role       // role of the authenticated caller
+	panelID string          // non-empty for panel connections
+	scope   *auth.RoomScope // nil for unrestricted roles
+}
+
+// wsEventTarget is the device, room and domain an event concerns, taken
+// from its payload and, for device events, the device registry. Fields are
+// empty for site-wide events (e.g. mode.changed).
+type wsEventTarget struct {
+	DeviceID     string `json:"device_id"`
+	RoomID       string `json:"room_id"`
+	Domain       string `json:"domain"`
+	GroupAddress string `json:"group_address"`
 }
 
 // defaultUpgraderBufferSize is the read/write buffer size for WebSocket connections.
@@ -102,6 +139,15 @@ func (h *Hub) Run(ctx context.Context) {
 	h.closeAll()
 }
 
+// SetRegistry sets the device registry used to find the room and domain
+// of device events for room scoping and subscription filters. Without it,
+// device events reach only unrestricted clients without filters.
+func (h *Hub) SetRegistry(registry *device.Registry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.registry = registry
+}
+
 // Register adds a client to the hub.
 func (h *Hub) Register(client *WSClient) {
 	h.mu.Lock()
@@ -126,6 +172,8 @@ func (h *Hub) Unregister(client *WSClient) {
 }
 
 // Broadcast sends an event to all clients subscribed to the given channel.
+// Events about a device or room reach room-scoped clients only when the
+// room is in their scope, and subscriptions with a filter only when it matches.
 // Lock ordering: hub lock is acquired first, then released before per-client
 // subscription checks. This avoids holding both hub and client locks simultaneously.
 func (h *Hub) Broadcast(channel string, payload any) {
@@ -148,14 +196,26 @@ func (h *Hub) Broadcast(channel string, payload any) {
 	for client := range h.clients {
 		clients = append(clients, client)
 	}
+	registry := h.registry
 	h.mu.RUnlock()
 
+	var target *wsEventTarget
 	sentCount := 0
 	for _, client := range clients {
-		if client.isSubscribed(channel) {
-			client.trySend(data)
-			sentCount++
+		filter, subscribed, scope := client.subscription(channel)
+		if !subscribed {
+			continue
 		}
+		if scope != nil || filter != nil {
+			if target == nil {
+				target = eventTarget(data, registry)
+			}
+			if !target.inScope(scope) || !filter.matches(target) {
+				continue
+			}
+		}
+		client.trySend(data)
+		sentCount++
 	}
 	if sentCount > 0 {
 		h.logger.Debug("broadcast sent", "channel", channel, "recipients", sentCount)
@@ -184,6 +244,86 @@ func (h *Hub) closeAll() {
 	}
 }
 
+// eventTarget extracts the device, room and domain an encoded event
+// concerns. The room and domain of a device event are filled in from the
+// registry when the payload does not carry them.
+func eventTarget(data []byte, registry *device.Registry) *wsEventTarget {
+	var msg struct {
+		Payload json.RawMessage `json:"payload"`
+	}
+	target := &wsEventTarget{}
+	if json.Unmarshal(data, &msg) == nil {
+		_ = json.Unmarshal(msg.Payload, target) //nolint:errcheck // non-object payloads have no target
+	}
+
+	if target.DeviceID != "" && (target.RoomID == "" || target.Domain == "") && registry != nil {
+		if dev, err := registry.GetDevice(context.Background(), target.DeviceID); err == nil {
+			if target.RoomID == "" {
+				target.RoomID = derefString(dev.RoomID)
+			}
+			if target.Domain == "" {
+				target.Domain = string(dev.Domain)
+			}
+		}
+	}
+	return target
+}
+
+// inScope reports whether a client with the given room scope may see the
+// event. Site-wide events are visible to everyone; device and room events
+// only within the scope.
+func (t *wsEventTarget) inScope(scope *auth.RoomScope) bool {
+	if scope == nil || (t.DeviceID == "" && t.RoomID == "") {
+		return true
+	}
+	return t.RoomID != "" && scope.CanAccessRoom(t.RoomID)
+}
+
+// matches reports whether the event passes the filter. A nil filter
+// matches everything.
+func (f *WSFilter) matches(t *wsEventTarget) bool {
+	if f == nil {
+		return true
+	}
+	return matchesAny(f.DeviceIDs, t.DeviceID) &&
+		matchesAny(f.RoomIDs, t.RoomID) &&
+		matchesAny(f.Domains, t.Domain) &&
+		matchesGroupAddress(f.GroupAddresses, t.GroupAddress)
+}
+
+// matchesAny reports whether value is in list; an empty list matches anything.
+func matchesAny(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, v := range list {
+		if v == value && value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesGroupAddress reports whether a group address equals or lies
+// under one in list; an empty list matches anything.
+func matchesGroupAddress(list []string, ga string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, v := range list {
+		if ga != "" && (ga == v || strings.HasPrefix(ga, v+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// empty reports whether the filter has no criteria.
+func (f *WSFilter) empty() bool {
+	return f == nil || (len(f.DeviceIDs) == 0 && len(f.RoomIDs) == 0 &&
+		len(f.Domains) == 0 && len(f.GroupAddresses) == 0)
+}
+
 // subscribeStateUpdates subscribes to MQTT device state topics and broadcasts
 // changes to WebSocket clients subscribed to "device.state_changed".
 func (s *Server) subscribeStateUpdates() error { //nolint:gocognit // MQTT subscription: message parsing + registry + TSDB pipeline
@@ -251,6 +391,19 @@ func (s *Server) subscribeStateUpdates() error { //nolint:gocognit // MQTT subsc
 				}
 				histCancel()
 			}
+
+			// A physical change (wall switch, local control) latches the
+			// device to manual so lower-priority automation leaves it alone.
+			if source, _ := stateMsg["source"].(string); source == "physical" && s.overrides != nil { //nolint:errcheck // type assertion checked via comparison
+				latchCtx, latchCancel := context.WithTimeout(context.Background(), 2*time.Second)
+				s.overrides.Latch(latchCtx, deviceID, source)
+				latchCancel()
+			}
+
+			// Evaluate automation rules watching this device.
+			if s.ruleEngine != nil {
+				s.ruleEngine.HandleStateChange(deviceID, stateMap)
+			}
 		}
 
 		return nil
@@ -283,10 +436,11 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		hub:           s.hub,
 		conn:          conn,
 		send:          make(chan []byte, wsSendBufferSize),
-		subscriptions: make(map[string]struct{}),
+		subscriptions: make(map[string]*WSFilter),
 		userID:        entry.userID,
 		role:          entry.role,
 		panelID:       entry.panelID,
+		scope:         entry.scope,
 	}
 
 	s.hub.Register(client)
@@ -386,6 +540,7 @@ func (c *WSClient) handleMessage(data []byte) {
 }
 
 // handleSubscribe adds channels to the client's subscription list.
+// Subscribing to a channel again replaces its filter.
 func (c *WSClient) handleSubscribe(msg WSMessage) {
 	// Parse payload to get channels
 	payloadBytes, err := json.Marshal(msg.Payload)
@@ -400,17 +555,45 @@ func (c *WSClient) handleSubscribe(msg WSMessage) {
 		return
 	}
 
+	for _, ch := range sub.Channels {
+		if !c.canSubscribe(ch) {
+			c.sendError(msg.ID, "permission denied for channel: "+ch)
+			return
+		}
+	}
+
+	filter := sub.Filter
+	if filter.empty() {
+		filter = nil
+	}
+
 	c.mu.Lock()
 	for _, ch := range sub.Channels {
-		c.subscriptions[ch] = struct{}{}
+		c.subscriptions[ch] = filter
 	}
 	c.mu.Unlock()
 
-	c.hub.logger.Info("websocket client subscribed", "channels", sub.Channels)
+	c.hub.logger.Info("websocket client subscribed", "channels", sub.Channels, "filtered", filter != nil)
 
-	c.sendResponse(msg.ID, WSTypeResponse, map[string]any{
+	resp := map[string]any{
 		"subscribed": sub.Channels,
-	})
+	}
+	if filter != nil {
+		resp["filter"] = filter
+	}
+	c.sendResponse(msg.ID, WSTypeResponse, resp)
+}
+
+// canSubscribe reports whether the client may subscribe to a channel.
+func (c *WSClient) canSubscribe(channel string) bool {
+	perm, restricted := wsChannelPermissions[channel]
+	if !restricted {
+		return true
+	}
+	if c.panelID != "" {
+		return auth.HasPanelPermission(perm)
+	}
+	return auth.HasPermission(c.role, perm)
 }
 
 // handleUnsubscribe removes channels from the client's subscription list.
@@ -453,12 +636,13 @@ func (c *WSClient) trySend(data []byte) {
 	}
 }
 
-// isSubscribed checks if the client is subscribed to a channel.
-func (c *WSClient) isSubscribed(channel string) bool {
+// subscription returns the client's filter for a channel, whether it is
+// subscribed, and its room scope.
+func (c *WSClient) subscription(channel string) (*WSFilter, bool, *auth.RoomScope) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	_, ok := c.subscriptions[channel]
-	return ok
+	filter, ok := c.subscriptions[channel]
+	return filter, ok, c.scope
 }
 
 // sendResponse sends a response message to the client.
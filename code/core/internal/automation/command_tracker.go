@@ -0,0 +1,370 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CommandStatus is the lifecycle state of a device command.
+type CommandStatus string
+
+// Command status values.
+//
+//	pending ──ack accepted──▶ completed
+//	   │  ╲
+//	   │   ack queued──▶ queued ──▶ (completed | failed | timeout)
+//	   │
+//	   ├──ack failed / publish error──▶ failed
+//	   └──no ack within timeout──────▶ timeout
+const (
+	CommandPending   CommandStatus = "pending"
+	CommandQueued    CommandStatus = "queued"
+	CommandCompleted CommandStatus = "completed"
+	CommandFailed    CommandStatus = "failed"
+	CommandTimeout   CommandStatus = "timeout"
+)
+
+// Final reports whether the status is terminal.
+func (s CommandStatus) Final() bool {
+	return s == CommandCompleted || s == CommandFailed || s == CommandTimeout
+}
+
+// Error codes recorded on commands that Core itself fails.
+const (
+	CommandErrTimeout       = "TIMEOUT"
+	CommandErrPublishFailed = "PUBLISH_FAILED"
+)
+
+const (
+	// DefaultCommandTimeout is how long a command waits for a bridge
+	// acknowledgement before it is marked timed out. It is well above the
+	// KNX bridge's 5s send budget, which covers both the wait in its
+	// outgoing queue and the send, so the bridge's own accepted, failed or
+	// timeout ack arrives first. A late ack cannot reopen a timed-out command.
+	DefaultCommandTimeout = 15 * time.Second
+
+	// commandRetention is how long a finished command stays queryable.
+	commandRetention = 5 * time.Minute
+)
+
+// Command is a device command published to a protocol bridge and its
+// confirmed outcome.
+type Command struct {
+	ID           string         `json:"id"`
+	DeviceID     string         `json:"device_id"`
+	Command      string         `json:"command"`
+	Parameters   map[string]any `json:"parameters,omitempty"`
+	Source       string         `json:"source"`
+	ExecutionID  string         `json:"execution_id,omitempty"`
+	Status       CommandStatus  `json:"status"`
+	Address      string         `json:"address,omitempty"`
+	ErrorCode    string         `json:"error_code,omitempty"`
+	ErrorMessage string         `json:"error_message,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	CompletedAt  *time.Time     `json:"completed_at,omitempty"`
+}
+
+// commandAck is the acknowledgement a bridge publishes on
+// graylogic/ack/{protocol}/{address}.
+type commandAck struct {
+	CommandID string `json:"command_id"`
+	DeviceID  string `json:"device_id"`
+	Status    string `json:"status"`
+	Protocol  string `json:"protocol"`
+	Address   string `json:"address"`
+	Error     *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// trackedCommand is the runtime state of one command.
+type trackedCommand struct {
+	cmd   Command
+	done  chan struct{}
+	timer *time.Timer
+	gen   uint64
+}
+
+// CommandTracker follows device commands from publish to the bridge's
+// acknowledgement.
+//
+// A command is tracked before it is published. An "accepted" ack from the
+// bridge completes it; a "failed" or "timeout" ack fails it; a "queued" ack
+// restarts the timeout. Bridges send "accepted" once their bus interface
+// has taken the telegram (for a KNXnet/IP tunnel, on TUNNELLING_ACK, before
+// the interface confirms it on the line). A command with no final ack
+// within the timeout is marked timed out. Every status
+// change is broadcast as "command.status". Finished commands stay
+// queryable through Get for a few minutes.
+//
+// Thread Safety: All public methods are safe for concurrent use.
+type CommandTracker struct {
+	timeout time.Duration
+	hub     WSHub
+	logger  Logger
+
+	now       func() time.Time
+	afterFunc func(time.Duration, func()) *time.Timer
+
+	mu       sync.Mutex
+	commands map[string]*trackedCommand
+	gen      uint64
+	stopped  bool
+}
+
+// NewCommandTracker creates a new command tracker.
+//
+// Parameters:
+//   - timeout: How long to wait for a final ack (DefaultCommandTimeout if zero)
+//   - hub: WebSocket hub for command.status events (may be nil)
+//   - logger: Logger instance (may be nil)
+func NewCommandTracker(timeout time.Duration, hub WSHub, logger Logger) *CommandTracker {
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+	return &CommandTracker{
+		timeout:   timeout,
+		hub:       hub,
+		logger:    logger,
+		now:       time.Now,
+		afterFunc: time.AfterFunc,
+		commands:  make(map[string]*trackedCommand),
+	}
+}
+
+// Track starts tracking a command about to be published. The command's
+// status, timestamps and error fields are set by the tracker.
+// Returns ErrCommandExists if the ID is already tracked.
+func (t *CommandTracker) Track(cmd Command) error {
+	now := t.now().UTC()
+	cmd.Status = CommandPending
+	cmd.CreatedAt, cmd.UpdatedAt = now, now
+	cmd.CompletedAt = nil
+	cmd.ErrorCode, cmd.ErrorMessage = "", ""
+	cmd.Parameters = deepCopyMap(cmd.Parameters)
+
+	t.mu.Lock()
+	if _, exists := t.commands[cmd.ID]; exists {
+		t.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrCommandExists, cmd.ID)
+	}
+	tc := &trackedCommand{cmd: cmd, done: make(chan struct{})}
+	t.commands[cmd.ID] = tc
+	t.arm(tc, t.timeout, t.timeoutFunc(cmd.ID))
+	snapshot := tc.snapshot()
+	t.mu.Unlock()
+
+	t.broadcast(snapshot)
+	return nil
+}
+
+// HandleAck applies a bridge acknowledgement. Acks for commands that are
+// not tracked (published elsewhere, or already expired) are ignored.
+func (t *CommandTracker) HandleAck(payload []byte) error {
+	var ack commandAck
+	if err := json.Unmarshal(payload, &ack); err != nil {
+		return fmt.Errorf("parsing ack: %w", err)
+	}
+	if ack.CommandID == "" {
+		return nil
+	}
+
+	var status CommandStatus
+	switch ack.Status {
+	case "accepted":
+		status = CommandCompleted
+	case "queued":
+		status = CommandQueued
+	case "failed":
+		status = CommandFailed
+	case "timeout":
+		status = CommandTimeout
+	default:
+		t.logger.Warn("ignoring ack with unknown status",
+			"command_id", ack.CommandID,
+			"status", ack.Status,
+		)
+		return nil
+	}
+
+	var code, msg string
+	if ack.Error != nil {
+		code, msg = ack.Error.Code, ack.Error.Message
+	}
+	if !t.update(ack.CommandID, status, ack.Address, code, msg, 0) {
+		t.logger.Debug("ack for untracked command", "command_id", ack.CommandID)
+	}
+	return nil
+}
+
+// Complete marks a command completed without a bridge ack. Used when the
+// outcome is confirmed by other means (e.g. dev mode simulation).
+func (t *CommandTracker) Complete(id string) error {
+	if !t.update(id, CommandCompleted, "", "", "", 0) {
+		return ErrCommandNotFound
+	}
+	return nil
+}
+
+// Fail marks a command failed, e.g. when it could not be published.
+func (t *CommandTracker) Fail(id, code, message string) error {
+	if !t.update(id, CommandFailed, "", code, message, 0) {
+		return ErrCommandNotFound
+	}
+	return nil
+}
+
+// Get returns a tracked command.
+func (t *CommandTracker) Get(id string) (Command, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tc, ok := t.commands[id]
+	if !ok {
+		return Command{}, false
+	}
+	return tc.snapshot(), true
+}
+
+// Wait blocks until the command reaches a final status or ctx is done,
+// and returns the command as it stands.
+// Returns ErrCommandNotFound if the ID is not tracked.
+func (t *CommandTracker) Wait(ctx context.Context, id string) (Command, error) {
+	t.mu.Lock()
+	tc, ok := t.commands[id]
+	t.mu.Unlock()
+	if !ok {
+		return Command{}, ErrCommandNotFound
+	}
+
+	select {
+	case <-tc.done:
+	case <-ctx.Done():
+		cmd, _ := t.Get(id)
+		return cmd, ctx.Err()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return tc.snapshot(), nil
+}
+
+// Stop cancels all timers. Pending commands are left as they are.
+func (t *CommandTracker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	for _, tc := range t.commands {
+		if tc.timer != nil {
+			tc.timer.Stop()
+		}
+	}
+}
+
+// update moves a command to a new status. Updates to finished commands
+// are ignored, as are timer updates (expectedGen non-zero) whose timer has
+// since been re-armed. Reports whether the command is tracked.
+func (t *CommandTracker) update(id string, status CommandStatus, address, code, msg string, expectedGen uint64) bool {
+	t.mu.Lock()
+	tc, ok := t.commands[id]
+	if !ok {
+		t.mu.Unlock()
+		return false
+	}
+	if tc.cmd.Status.Final() || (expectedGen != 0 && tc.gen != expectedGen) {
+		t.mu.Unlock()
+		return true
+	}
+
+	now := t.now().UTC()
+	tc.cmd.Status = status
+	tc.cmd.UpdatedAt = now
+	if address != "" {
+		tc.cmd.Address = address
+	}
+	if code != "" || msg != "" {
+		tc.cmd.ErrorCode, tc.cmd.ErrorMessage = code, msg
+	}
+
+	if status.Final() {
+		tc.cmd.CompletedAt = &now
+		close(tc.done)
+		t.arm(tc, commandRetention, t.forgetFunc(id))
+	} else {
+		t.arm(tc, t.timeout, t.timeoutFunc(id))
+	}
+	snapshot := tc.snapshot()
+	t.mu.Unlock()
+
+	if status == CommandCompleted {
+		t.logger.Debug("command completed", "command_id", id, "device_id", snapshot.DeviceID)
+	} else if status.Final() {
+		t.logger.Warn("command not completed",
+			"command_id", id,
+			"device_id", snapshot.DeviceID,
+			"status", status,
+			"error_code", snapshot.ErrorCode,
+			"error", snapshot.ErrorMessage,
+		)
+	}
+	t.broadcast(snapshot)
+	return true
+}
+
+// arm replaces a command's timer. Caller must hold t.mu.
+func (t *CommandTracker) arm(tc *trackedCommand, d time.Duration, f func(gen uint64)) {
+	if tc.timer != nil {
+		tc.timer.Stop()
+	}
+	if t.stopped {
+		tc.timer = nil
+		return
+	}
+	t.gen++
+	gen := t.gen
+	tc.gen = gen
+	tc.timer = t.afterFunc(d, func() { f(gen) })
+}
+
+// timeoutFunc returns the timer callback that times a command out.
+func (t *CommandTracker) timeoutFunc(id string) func(uint64) {
+	return func(gen uint64) {
+		t.update(id, CommandTimeout, "", CommandErrTimeout, "no acknowledgement from bridge", gen)
+	}
+}
+
+// forgetFunc returns the timer callback that drops a finished command.
+func (t *CommandTracker) forgetFunc(id string) func(uint64) {
+	return func(gen uint64) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if tc, ok := t.commands[id]; ok && tc.gen == gen {
+			delete(t.commands, id)
+		}
+	}
+}
+
+// broadcast sends a command.status event.
+func (t *CommandTracker) broadcast(cmd Command) {
+	if t.hub != nil {
+		t.hub.Broadcast("command.status", cmd)
+	}
+}
+
+// snapshot returns a copy of the command safe to hand out.
+func (tc *trackedCommand) snapshot() Command {
+	cmd := tc.cmd
+	cmd.Parameters = deepCopyMap(tc.cmd.Parameters)
+	if tc.cmd.CompletedAt != nil {
+		completed := *tc.cmd.CompletedAt
+		cmd.CompletedAt = &completed
+	}
+	return cmd
+}
@@ -0,0 +1,149 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func ackPayload(id, status, code string) []byte {
+	ack := `{"command_id":"` + id + `","device_id":"light-01","status":"` + status + `","protocol":"knx","address":"1/0/1"`
+	if code != "" {
+		ack += `,"error":{"code":"` + code + `","message":"bus error"}`
+	}
+	return []byte(ack + "}")
+}
+
+func TestCommandTracker_Ack(t *testing.T) {
+	ctx := context.Background()
+	hub := newMockWSHub()
+	tr := NewCommandTracker(time.Minute, hub, nil)
+	t.Cleanup(tr.Stop)
+
+	if err := tr.Track(Command{ID: "cmd-1", DeviceID: "light-01", Command: "on", Source: "api"}); err != nil {
+		t.Fatalf("Track: %v", err)
+	}
+	if err := tr.Track(Command{ID: "cmd-1"}); !errors.Is(err, ErrCommandExists) {
+		t.Errorf("duplicate Track error = %v, want ErrCommandExists", err)
+	}
+	if cmd, _ := tr.Get("cmd-1"); cmd.Status != CommandPending {
+		t.Errorf("status = %q, want pending", cmd.Status)
+	}
+
+	if err := tr.HandleAck(ackPayload("cmd-1", "queued", "")); err != nil {
+		t.Fatalf("HandleAck: %v", err)
+	}
+	if cmd, _ := tr.Get("cmd-1"); cmd.Status != CommandQueued {
+		t.Errorf("status = %q, want queued", cmd.Status)
+	}
+
+	tr.HandleAck(ackPayload("cmd-1", "accepted", "")) //nolint:errcheck // valid payload
+	cmd, err := tr.Wait(ctx, "cmd-1")
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if cmd.Status != CommandCompleted || cmd.Address != "1/0/1" || cmd.CompletedAt == nil {
+		t.Errorf("command = %+v", cmd)
+	}
+
+	// Late acks do not reopen a finished command.
+	tr.HandleAck(ackPayload("cmd-1", "failed", "DEVICE_UNREACHABLE")) //nolint:errcheck // valid payload
+	if cmd, _ := tr.Get("cmd-1"); cmd.Status != CommandCompleted {
+		t.Errorf("status after late ack = %q", cmd.Status)
+	}
+
+	broadcasts := hub.getBroadcasts()
+	if len(broadcasts) != 3 || broadcasts[2].Channel != "command.status" {
+		t.Fatalf("broadcasts = %+v", broadcasts)
+	}
+
+	if err := tr.HandleAck(ackPayload("unknown", "accepted", "")); err != nil {
+		t.Errorf("ack for untracked command: %v", err)
+	}
+	if err := tr.HandleAck([]byte("{")); err == nil {
+		t.Error("malformed ack accepted")
+	}
+}
+
+func TestCommandTracker_Failed(t *testing.T) {
+	tr := NewCommandTracker(time.Minute, nil, nil)
+	t.Cleanup(tr.Stop)
+
+	tr.Track(Command{ID: "cmd-1", DeviceID: "light-01"})              //nolint:errcheck // new ID
+	tr.HandleAck(ackPayload("cmd-1", "failed", "DEVICE_UNREACHABLE")) //nolint:errcheck // valid payload
+	cmd, _ := tr.Wait(context.Background(), "cmd-1")
+	if cmd.Status != CommandFailed || cmd.ErrorCode != "DEVICE_UNREACHABLE" || cmd.ErrorMessage != "bus error" {
+		t.Errorf("command = %+v", cmd)
+	}
+
+	tr.Track(Command{ID: "cmd-2", DeviceID: "light-01"}) //nolint:errcheck // new ID
+	if err := tr.Fail("cmd-2", CommandErrPublishFailed, "broker down"); err != nil {
+		t.Fatalf("Fail: %v", err)
+	}
+	if cmd, _ := tr.Get("cmd-2"); cmd.Status != CommandFailed || cmd.ErrorCode != CommandErrPublishFailed {
+		t.Errorf("command = %+v", cmd)
+	}
+	if err := tr.Complete("missing"); !errors.Is(err, ErrCommandNotFound) {
+		t.Errorf("Complete(missing) error = %v, want ErrCommandNotFound", err)
+	}
+}
+
+func TestCommandTracker_Timeout(t *testing.T) {
+	tr := NewCommandTracker(10*time.Millisecond, nil, nil)
+	t.Cleanup(tr.Stop)
+
+	tr.Track(Command{ID: "cmd-1", DeviceID: "light-01"}) //nolint:errcheck // new ID
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	cmd, err := tr.Wait(ctx, "cmd-1")
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if cmd.Status != CommandTimeout || cmd.ErrorCode != CommandErrTimeout {
+		t.Errorf("command = %+v", cmd)
+	}
+}
+
+func TestCommandTracker_StaleTimeout(t *testing.T) {
+	tr := NewCommandTracker(time.Minute, nil, nil)
+	var fired []func()
+	tr.afterFunc = func(d time.Duration, f func()) *time.Timer {
+		fired = append(fired, f)
+		return time.AfterFunc(time.Hour, func() {})
+	}
+	t.Cleanup(tr.Stop)
+
+	tr.Track(Command{ID: "cmd-1", DeviceID: "light-01"}) //nolint:errcheck // new ID
+	// A queued ack re-arms the timeout after the first timer has fired but
+	// before its callback ran.
+	tr.HandleAck(ackPayload("cmd-1", "queued", "")) //nolint:errcheck // valid payload
+	fired[0]()
+	if cmd, _ := tr.Get("cmd-1"); cmd.Status != CommandQueued {
+		t.Fatalf("status after stale timeout = %q, want queued", cmd.Status)
+	}
+
+	fired[1]()
+	if cmd, _ := tr.Get("cmd-1"); cmd.Status != CommandTimeout {
+		t.Errorf("status after timeout = %q, want timeout", cmd.Status)
+	}
+}
+
+func TestCommandTracker_Retention(t *testing.T) {
+	tr := NewCommandTracker(time.Minute, nil, nil)
+	tr.afterFunc = func(d time.Duration, f func()) *time.Timer {
+		return time.AfterFunc(d/1e6, f) // 5m -> 300µs
+	}
+	t.Cleanup(tr.Stop)
+
+	tr.Track(Command{ID: "cmd-1", DeviceID: "light-01"}) //nolint:errcheck // new ID
+	tr.Complete("cmd-1")                                 //nolint:errcheck // tracked
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := tr.Get("cmd-1"); ok {
+		t.Error("finished command not forgotten")
+	}
+	if _, err := tr.Wait(context.Background(), "cmd-1"); !errors.Is(err, ErrCommandNotFound) {
+		t.Errorf("Wait error = %v, want ErrCommandNotFound", err)
+	}
+}
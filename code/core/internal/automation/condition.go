@@ -0,0 +1,478 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Condition is a test evaluated when a scene is activated or a schedule or
+// rule fires. A list of conditions passes only if every entry passes; use
+// "or" and "not" for other combinations.
+//
+// Fields used depend on Type:
+//   - and, or: Conditions (at least one)
+//   - not: Conditions (exactly one)
+//   - time_window: After and/or Before ("HH:MM", site timezone). The window
+//     includes After and excludes Before; After later than Before spans
+//     midnight ("22:00" to "06:00")
+//   - day_of_week: Days ("mon".."sun", site timezone)
+//   - mode: Modes (passes when the current site mode is one of them)
+//   - device_state: DeviceID, Key, Operator (default "eq") and Value
+//   - sun_elevation: Above and/or Below, in degrees (negative is below the
+//     horizon); needs the site location
+type Condition struct {
+	Type ConditionType `json:"type"`
+
+	// Nested conditions (and, or, not)
+	Conditions []Condition `json:"conditions,omitempty"`
+
+	// time_window
+	After  string `json:"after,omitempty"`
+	Before string `json:"before,omitempty"`
+
+	// day_of_week
+	Days []string `json:"days,omitempty"`
+
+	// mode
+	Modes []string `json:"modes,omitempty"`
+
+	// device_state
+	DeviceID string    `json:"device_id,omitempty"`
+	Key      string    `json:"key,omitempty"`
+	Operator CompareOp `json:"operator,omitempty"`
+	Value    any       `json:"value,omitempty"`
+
+	// sun_elevation
+	Above *float64 `json:"above,omitempty"`
+	Below *float64 `json:"below,omitempty"`
+}
+
+// ConditionType identifies what a condition tests.
+type ConditionType string
+
+const (
+	ConditionAnd          ConditionType = "and"
+	ConditionOr           ConditionType = "or"
+	ConditionNot          ConditionType = "not"
+	ConditionTimeWindow   ConditionType = "time_window"
+	ConditionDayOfWeek    ConditionType = "day_of_week"
+	ConditionMode         ConditionType = "mode"
+	ConditionDeviceState  ConditionType = "device_state"
+	ConditionSunElevation ConditionType = "sun_elevation"
+)
+
+// CompareOp is a device_state comparison operator. eq and ne compare any
+// value; the ordering operators need numbers.
+type CompareOp string
+
+const (
+	CompareEq  CompareOp = "eq"
+	CompareNe  CompareOp = "ne"
+	CompareGt  CompareOp = "gt"
+	CompareGte CompareOp = "gte"
+	CompareLt  CompareOp = "lt"
+	CompareLte CompareOp = "lte"
+)
+
+// Condition validation constants.
+const (
+	maxConditionDepth = 5
+	maxConditionNodes = 50
+)
+
+// DeviceStateReader provides current device state for device_state
+// conditions.
+type DeviceStateReader interface {
+	// GetDeviceState returns the device's last known state.
+	GetDeviceState(ctx context.Context, deviceID string) (map[string]any, error)
+}
+
+// ModeReader provides the current site mode. *ModeManager satisfies it.
+type ModeReader interface {
+	Current() string
+}
+
+// ValidateConditions checks a condition list. Returns an error wrapping
+// ErrInvalidCondition describing the first problem found.
+func ValidateConditions(conditions []Condition) error {
+	nodes := 0
+	for i := range conditions {
+		if err := validateCondition(&conditions[i], fmt.Sprintf("conditions[%d]", i), 1, &nodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateCondition(c *Condition, path string, depth int, nodes *int) error { //nolint:gocognit,gocyclo // one case per condition type
+	*nodes++
+	if *nodes > maxConditionNodes {
+		return fmt.Errorf("%w: more than %d conditions", ErrInvalidCondition, maxConditionNodes)
+	}
+	if depth > maxConditionDepth {
+		return fmt.Errorf("%w: %s: nested deeper than %d levels", ErrInvalidCondition, path, maxConditionDepth)
+	}
+
+	switch c.Type {
+	case ConditionAnd, ConditionOr, ConditionNot:
+		if len(c.Conditions) == 0 {
+			return fmt.Errorf("%w: %s: %s needs nested conditions", ErrInvalidCondition, path, c.Type)
+		}
+		if c.Type == ConditionNot && len(c.Conditions) != 1 {
+			return fmt.Errorf("%w: %s: not takes exactly one condition", ErrInvalidCondition, path)
+		}
+		for i := range c.Conditions {
+			if err := validateCondition(&c.Conditions[i], fmt.Sprintf("%s.conditions[%d]", path, i), depth+1, nodes); err != nil {
+				return err
+			}
+		}
+	case ConditionTimeWindow:
+		if c.After == "" && c.Before == "" {
+			return fmt.Errorf("%w: %s: time_window needs after or before", ErrInvalidCondition, path)
+		}
+		for _, v := range []string{c.After, c.Before} {
+			if v == "" {
+				continue
+			}
+			if _, _, err := parseClock(v); err != nil {
+				return fmt.Errorf("%w: %s: time must be HH:MM, got %q", ErrInvalidCondition, path, v)
+			}
+		}
+	case ConditionDayOfWeek:
+		if len(c.Days) == 0 {
+			return fmt.Errorf("%w: %s: day_of_week needs days", ErrInvalidCondition, path)
+		}
+		if _, err := parseDays(c.Days); err != nil {
+			return fmt.Errorf("%w: %s: days must be mon..sun", ErrInvalidCondition, path)
+		}
+	case ConditionMode:
+		if len(c.Modes) == 0 {
+			return fmt.Errorf("%w: %s: mode needs modes", ErrInvalidCondition, path)
+		}
+	case ConditionDeviceState:
+		if c.DeviceID == "" || c.Key == "" {
+			return fmt.Errorf("%w: %s: device_state needs device_id and key", ErrInvalidCondition, path)
+		}
+		if c.Value == nil {
+			return fmt.Errorf("%w: %s: device_state needs a value", ErrInvalidCondition, path)
+		}
+		switch c.Operator {
+		case "", CompareEq, CompareNe:
+		case CompareGt, CompareGte, CompareLt, CompareLte:
+			if _, ok := toFloat(c.Value); !ok {
+				return fmt.Errorf("%w: %s: operator %s needs a numeric value", ErrInvalidCondition, path, c.Operator)
+			}
+		default:
+			return fmt.Errorf("%w: %s: unknown operator %q", ErrInvalidCondition, path, c.Operator)
+		}
+	case ConditionSunElevation:
+		if c.Above == nil && c.Below == nil {
+			return fmt.Errorf("%w: %s: sun_elevation needs above or below", ErrInvalidCondition, path)
+		}
+		for _, v := range []*float64{c.Above, c.Below} {
+			if v != nil && (*v < -90 || *v > 90) {
+				return fmt.Errorf("%w: %s: elevation must be -90 to 90", ErrInvalidCondition, path)
+			}
+		}
+		if c.Above != nil && c.Below != nil && *c.Above >= *c.Below {
+			return fmt.Errorf("%w: %s: above must be less than below", ErrInvalidCondition, path)
+		}
+	default:
+		return fmt.Errorf("%w: %s: unknown condition type %q", ErrInvalidCondition, path, c.Type)
+	}
+	return nil
+}
+
+// cloneConditions deep-copies a condition list.
+func cloneConditions(conditions []Condition) []Condition {
+	if conditions == nil {
+		return nil
+	}
+	cpy := make([]Condition, len(conditions))
+	for i, c := range conditions {
+		cpy[i] = c
+		cpy[i].Conditions = cloneConditions(c.Conditions)
+		cpy[i].Days = cloneStrings(c.Days)
+		cpy[i].Modes = cloneStrings(c.Modes)
+		cpy[i].Value = deepCopyValue(c.Value)
+		if c.Above != nil {
+			v := *c.Above
+			cpy[i].Above = &v
+		}
+		if c.Below != nil {
+			v := *c.Below
+			cpy[i].Below = &v
+		}
+	}
+	return cpy
+}
+
+// marshalConditions encodes a condition list for storage; an empty list is
+// stored as NULL.
+func marshalConditions(conditions []Condition) (any, error) {
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(conditions)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling conditions: %w", err)
+	}
+	return string(data), nil
+}
+
+// unmarshalConditions decodes a stored condition list.
+func unmarshalConditions(data string) ([]Condition, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var conditions []Condition
+	if err := json.Unmarshal([]byte(data), &conditions); err != nil {
+		return nil, fmt.Errorf("unmarshalling conditions: %w", err)
+	}
+	return conditions, nil
+}
+
+// ConditionEvaluator evaluates conditions against the current time, site
+// location, site mode and device state.
+//
+// Evaluation fails closed: a condition whose input is unavailable (unknown
+// device or state key, no site location for sun_elevation, no mode
+// configured) does not pass.
+//
+// Thread Safety: All public methods are safe for concurrent use.
+type ConditionEvaluator struct {
+	site   SiteProvider
+	states DeviceStateReader
+	now    func() time.Time
+
+	mu    sync.RWMutex
+	modes ModeReader
+}
+
+// NewConditionEvaluator creates a condition evaluator.
+//
+// Parameters:
+//   - site: Provider for site coordinates and timezone (may be nil: UTC, no location)
+//   - states: Device state source (may be nil: device_state never passes)
+func NewConditionEvaluator(site SiteProvider, states DeviceStateReader) *ConditionEvaluator {
+	return &ConditionEvaluator{
+		site:   site,
+		states: states,
+		now:    time.Now,
+	}
+}
+
+// SetModeReader sets the source of the current site mode.
+func (c *ConditionEvaluator) SetModeReader(modes ModeReader) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.modes = modes
+}
+
+// Check evaluates a condition list.
+//
+// Returns nil when every condition passes, or an error wrapping
+// ErrConditionsNotMet that names the first condition that did not.
+func (c *ConditionEvaluator) Check(ctx context.Context, conditions []Condition) error {
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	env := &conditionEnv{ctx: ctx}
+	if c.site != nil {
+		site, err := c.site.GetSiteInfo(ctx)
+		if err != nil {
+			return fmt.Errorf("%w: loading site: %w", ErrConditionsNotMet, err)
+		}
+		env.site = site
+	}
+	env.now = c.now().In(env.site.Location())
+
+	for i := range conditions {
+		if !c.evaluate(env, &conditions[i]) {
+			return fmt.Errorf("%w: %s", ErrConditionsNotMet, describeCondition(&conditions[i]))
+		}
+	}
+	return nil
+}
+
+// conditionEnv holds the inputs for one Check call, so every condition in
+// the tree sees the same instant and site.
+type conditionEnv struct {
+	ctx  context.Context
+	now  time.Time // In the site timezone
+	site SiteInfo
+}
+
+func (c *ConditionEvaluator) evaluate(env *conditionEnv, cond *Condition) bool {
+	switch cond.Type {
+	case ConditionAnd:
+		for i := range cond.Conditions {
+			if !c.evaluate(env, &cond.Conditions[i]) {
+				return false
+			}
+		}
+		return true
+	case ConditionOr:
+		for i := range cond.Conditions {
+			if c.evaluate(env, &cond.Conditions[i]) {
+				return true
+			}
+		}
+		return false
+	case ConditionNot:
+		return len(cond.Conditions) == 1 && !c.evaluate(env, &cond.Conditions[0])
+	case ConditionTimeWindow:
+		return inTimeWindow(env.now, cond.After, cond.Before)
+	case ConditionDayOfWeek:
+		set, err := parseDays(cond.Days)
+		return err == nil && set != 0 && dayAllowed(set, env.now.Weekday())
+	case ConditionMode:
+		return c.modeIn(cond.Modes)
+	case ConditionDeviceState:
+		return c.deviceStateMatches(env.ctx, cond)
+	case ConditionSunElevation:
+		if env.site.Latitude == nil || env.site.Longitude == nil {
+			return false
+		}
+		elevation := SunElevation(env.now, *env.site.Latitude, *env.site.Longitude)
+		if cond.Above != nil && elevation <= *cond.Above {
+			return false
+		}
+		if cond.Below != nil && elevation >= *cond.Below {
+			return false
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *ConditionEvaluator) modeIn(modes []string) bool {
+	c.mu.RLock()
+	reader := c.modes
+	c.mu.RUnlock()
+	if reader == nil {
+		return false
+	}
+	current := reader.Current()
+	for _, m := range modes {
+		if m == current {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ConditionEvaluator) deviceStateMatches(ctx context.Context, cond *Condition) bool {
+	if c.states == nil {
+		return false
+	}
+	state, err := c.states.GetDeviceState(ctx, cond.DeviceID)
+	if err != nil {
+		return false
+	}
+	current, ok := state[cond.Key]
+	if !ok {
+		return false
+	}
+	return compareValues(current, cond.Operator, cond.Value)
+}
+
+// compareValues applies a comparison operator. Ordering operators are
+// false unless both values are numbers.
+func compareValues(current any, op CompareOp, want any) bool {
+	switch op {
+	case "", CompareEq:
+		return valuesEqual(current, want)
+	case CompareNe:
+		return !valuesEqual(current, want)
+	}
+
+	cur, okCur := toFloat(current)
+	w, okWant := toFloat(want)
+	if !okCur || !okWant {
+		return false
+	}
+	switch op {
+	case CompareGt:
+		return cur > w
+	case CompareGte:
+		return cur >= w
+	case CompareLt:
+		return cur < w
+	case CompareLte:
+		return cur <= w
+	default:
+		return false
+	}
+}
+
+// inTimeWindow reports whether the wall-clock time of t falls in
+// [after, before). An empty bound is open (midnight).
+func inTimeWindow(t time.Time, after, before string) bool {
+	minute := t.Hour()*60 + t.Minute()
+
+	start, end := 0, 24*60
+	if after != "" {
+		h, m, err := parseClock(after)
+		if err != nil {
+			return false
+		}
+		start = h*60 + m
+	}
+	if before != "" {
+		h, m, err := parseClock(before)
+		if err != nil {
+			return false
+		}
+		end = h*60 + m
+	}
+
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	// Spans midnight
+	return minute >= start || minute < end
+}
+
+// describeCondition summarises a condition for "conditions not met"
+// messages.
+func describeCondition(c *Condition) string {
+	switch c.Type {
+	case ConditionTimeWindow:
+		return fmt.Sprintf("time_window %s-%s", valueOrDash(c.After), valueOrDash(c.Before))
+	case ConditionDayOfWeek:
+		return "day_of_week " + strings.Join(c.Days, ",")
+	case ConditionMode:
+		return "mode " + strings.Join(c.Modes, ",")
+	case ConditionDeviceState:
+		op := c.Operator
+		if op == "" {
+			op = CompareEq
+		}
+		return fmt.Sprintf("device_state %s.%s %s %v", c.DeviceID, c.Key, op, c.Value)
+	case ConditionSunElevation:
+		parts := []string{"sun_elevation"}
+		if c.Above != nil {
+			parts = append(parts, fmt.Sprintf("above %g", *c.Above))
+		}
+		if c.Below != nil {
+			parts = append(parts, fmt.Sprintf("below %g", *c.Below))
+		}
+		return strings.Join(parts, " ")
+	default:
+		return string(c.Type)
+	}
+}
+
+func valueOrDash(v string) string {
+	if v == "" {
+		return "-"
+	}
+	return v
+}
@@ -0,0 +1,211 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockStateReader map[string]map[string]any
+
+func (m mockStateReader) GetDeviceState(_ context.Context, deviceID string) (map[string]any, error) {
+	state, ok := m[deviceID]
+	if !ok {
+		return nil, errors.New("device not found")
+	}
+	return state, nil
+}
+
+type staticMode string
+
+func (m staticMode) Current() string { return string(m) }
+
+// newTestEvaluator returns an evaluator for a London site fixed at now.
+func newTestEvaluator(now time.Time, states mockStateReader) *ConditionEvaluator {
+	c := NewConditionEvaluator(staticSite{londonSite()}, states)
+	c.now = func() time.Time { return now }
+	return c
+}
+
+func TestValidateConditions(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []Condition
+		wantErr    bool
+	}{
+		{"none", nil, false},
+		{"time window", []Condition{{Type: ConditionTimeWindow, After: "22:00", Before: "06:00"}}, false},
+		{"time window open ended", []Condition{{Type: ConditionTimeWindow, After: "18:00"}}, false},
+		{"time window empty", []Condition{{Type: ConditionTimeWindow}}, true},
+		{"time window bad clock", []Condition{{Type: ConditionTimeWindow, After: "25:00"}}, true},
+		{"days", []Condition{{Type: ConditionDayOfWeek, Days: []string{"sat", "sun"}}}, false},
+		{"days empty", []Condition{{Type: ConditionDayOfWeek}}, true},
+		{"days unknown", []Condition{{Type: ConditionDayOfWeek, Days: []string{"funday"}}}, true},
+		{"mode", []Condition{{Type: ConditionMode, Modes: []string{"night"}}}, false},
+		{"mode empty", []Condition{{Type: ConditionMode}}, true},
+		{"device state", []Condition{{Type: ConditionDeviceState, DeviceID: "d1", Key: "on", Operator: CompareEq, Value: true}}, false},
+		{"device state no key", []Condition{{Type: ConditionDeviceState, DeviceID: "d1", Operator: CompareEq, Value: true}}, true},
+		{"device state bad op", []Condition{{Type: ConditionDeviceState, DeviceID: "d1", Key: "on", Operator: "like", Value: true}}, true},
+		{"device state ordered non-number", []Condition{{Type: ConditionDeviceState, DeviceID: "d1", Key: "level", Operator: CompareGt, Value: "x"}}, true},
+		{"sun elevation", []Condition{{Type: ConditionSunElevation, Below: ptrFloat(-6)}}, false},
+		{"sun elevation no bound", []Condition{{Type: ConditionSunElevation}}, true},
+		{"sun elevation inverted", []Condition{{Type: ConditionSunElevation, Above: ptrFloat(10), Below: ptrFloat(0)}}, true},
+		{"not", []Condition{{Type: ConditionNot, Conditions: []Condition{{Type: ConditionMode, Modes: []string{"away"}}}}}, false},
+		{"not two children", []Condition{{Type: ConditionNot, Conditions: []Condition{
+			{Type: ConditionMode, Modes: []string{"away"}}, {Type: ConditionMode, Modes: []string{"home"}},
+		}}}, true},
+		{"or empty", []Condition{{Type: ConditionOr}}, true},
+		{"unknown type", []Condition{{Type: "weather"}}, true},
+		{"too deep", []Condition{nest(maxConditionDepth + 1)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateConditions(tt.conditions)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateConditions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidCondition) {
+				t.Errorf("error %v does not wrap ErrInvalidCondition", err)
+			}
+		})
+	}
+}
+
+// nest builds a chain of depth "and" nodes around a mode condition.
+func nest(depth int) Condition {
+	c := Condition{Type: ConditionMode, Modes: []string{"home"}}
+	for i := 1; i < depth; i++ {
+		c = Condition{Type: ConditionAnd, Conditions: []Condition{c}}
+	}
+	return c
+}
+
+func TestConditionEvaluator_TimeAndDay(t *testing.T) {
+	// Saturday 14 March 2026 23:30 in London (GMT)
+	c := newTestEvaluator(time.Date(2026, 3, 14, 23, 30, 0, 0, time.UTC), nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		cond Condition
+		want bool
+	}{
+		{"overnight window", Condition{Type: ConditionTimeWindow, After: "22:00", Before: "06:00"}, true},
+		{"daytime window", Condition{Type: ConditionTimeWindow, After: "08:00", Before: "18:00"}, false},
+		{"after only", Condition{Type: ConditionTimeWindow, After: "23:00"}, true},
+		{"before only", Condition{Type: ConditionTimeWindow, Before: "23:30"}, false},
+		{"weekend", Condition{Type: ConditionDayOfWeek, Days: []string{"sat", "sun"}}, true},
+		{"weekdays", Condition{Type: ConditionDayOfWeek, Days: []string{"mon", "tue", "wed", "thu", "fri"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.Check(ctx, []Condition{tt.cond})
+			if got := err == nil; got != tt.want {
+				t.Errorf("Check() error = %v, want pass %v", err, tt.want)
+			}
+			if err != nil && !errors.Is(err, ErrConditionsNotMet) {
+				t.Errorf("error %v does not wrap ErrConditionsNotMet", err)
+			}
+		})
+	}
+}
+
+func TestConditionEvaluator_ModeAndDeviceState(t *testing.T) {
+	c := newTestEvaluator(time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC), mockStateReader{
+		"lux-1":  {"lux": float64(120)},
+		"door-1": {"contact": "closed"},
+	})
+	ctx := context.Background()
+	night := []Condition{{Type: ConditionMode, Modes: []string{"night"}}}
+
+	// No mode source: fails closed.
+	if err := c.Check(ctx, night); err == nil {
+		t.Error("mode condition passed without a mode reader")
+	}
+	c.SetModeReader(staticMode("night"))
+	if err := c.Check(ctx, night); err != nil {
+		t.Errorf("mode condition: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		cond Condition
+		want bool
+	}{
+		{"lt", Condition{Type: ConditionDeviceState, DeviceID: "lux-1", Key: "lux", Operator: CompareLt, Value: 200}, true},
+		{"gte", Condition{Type: ConditionDeviceState, DeviceID: "lux-1", Key: "lux", Operator: CompareGte, Value: 200}, false},
+		{"eq string", Condition{Type: ConditionDeviceState, DeviceID: "door-1", Key: "contact", Operator: CompareEq, Value: "closed"}, true},
+		{"ne string", Condition{Type: ConditionDeviceState, DeviceID: "door-1", Key: "contact", Operator: CompareNe, Value: "closed"}, false},
+		{"missing key", Condition{Type: ConditionDeviceState, DeviceID: "door-1", Key: "battery", Operator: CompareNe, Value: 0}, false},
+		{"unknown device", Condition{Type: ConditionDeviceState, DeviceID: "nope", Key: "on", Operator: CompareEq, Value: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Check(ctx, []Condition{tt.cond}) == nil; got != tt.want {
+				t.Errorf("pass = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionEvaluator_SunElevation(t *testing.T) {
+	ctx := context.Background()
+	dark := []Condition{{Type: ConditionSunElevation, Below: ptrFloat(-6)}}
+
+	midnight := newTestEvaluator(time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), nil)
+	if err := midnight.Check(ctx, dark); err != nil {
+		t.Errorf("midnight: %v", err)
+	}
+	noon := newTestEvaluator(time.Date(2026, 6, 21, 12, 0, 0, 0, time.UTC), nil)
+	if err := noon.Check(ctx, dark); err == nil {
+		t.Error("noon passed a below -6 condition")
+	}
+
+	// No site location: fails closed.
+	c := NewConditionEvaluator(staticSite{SiteInfo{Timezone: "UTC"}}, nil)
+	c.now = func() time.Time { return time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC) }
+	if err := c.Check(ctx, dark); err == nil {
+		t.Error("sun elevation passed without a site location")
+	}
+}
+
+func TestConditionEvaluator_Nested(t *testing.T) {
+	// Wednesday 11 March 2026 07:00
+	c := newTestEvaluator(time.Date(2026, 3, 11, 7, 0, 0, 0, time.UTC), mockStateReader{
+		"light-1": {"on": false},
+	})
+	c.SetModeReader(staticMode("home"))
+	ctx := context.Background()
+
+	// (weekday AND 06:00-09:00) OR weekend, AND NOT light on
+	conditions := []Condition{
+		{Type: ConditionOr, Conditions: []Condition{
+			{Type: ConditionAnd, Conditions: []Condition{
+				{Type: ConditionDayOfWeek, Days: []string{"mon", "tue", "wed", "thu", "fri"}},
+				{Type: ConditionTimeWindow, After: "06:00", Before: "09:00"},
+			}},
+			{Type: ConditionDayOfWeek, Days: []string{"sat", "sun"}},
+		}},
+		{Type: ConditionNot, Conditions: []Condition{
+			{Type: ConditionDeviceState, DeviceID: "light-1", Key: "on", Operator: CompareEq, Value: true},
+		}},
+	}
+	if err := ValidateConditions(conditions); err != nil {
+		t.Fatalf("ValidateConditions: %v", err)
+	}
+	if err := c.Check(ctx, conditions); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+
+	conditions = append(conditions, Condition{Type: ConditionMode, Modes: []string{"away"}})
+	err := c.Check(ctx, conditions)
+	if !errors.Is(err, ErrConditionsNotMet) {
+		t.Fatalf("Check error = %v, want ErrConditionsNotMet", err)
+	}
+	if want := "mode away"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not name the failing condition %q", err, want)
+	}
+}
@@ -0,0 +1,191 @@
+package automation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchLimit bounds how far ahead Next looks for a match. Expressions
+// such as "0 0 30 2 *" (30th February) never match; five years covers every
+// valid leap-day pattern.
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// CronExpr is a parsed five-field cron expression:
+//
+//	minute hour day-of-month month day-of-week
+//
+// Each field accepts "*", single values, ranges ("1-5"), steps ("*/15",
+// "0-30/10") and comma-separated lists. Months and weekdays also accept
+// three-letter names ("jan", "mon"); weekday 7 is treated as Sunday.
+// As in classic cron, when both day-of-month and day-of-week are restricted
+// a day matches if either field matches.
+type CronExpr struct {
+	minute, hour, dom, month, dow uint64 // bitsets of allowed values
+	domAny, dowAny                bool   // field was "*" (unrestricted)
+}
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// ParseCron parses a five-field cron expression.
+func ParseCron(expr string) (*CronExpr, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 { //nolint:mnd // five cron fields
+		return nil, fmt.Errorf("%w: cron expression must have 5 fields, got %d", ErrInvalidTrigger, len(fields))
+	}
+
+	var c CronExpr
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("%w: minute: %w", ErrInvalidTrigger, err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("%w: hour: %w", ErrInvalidTrigger, err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("%w: day of month: %w", ErrInvalidTrigger, err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, fmt.Errorf("%w: month: %w", ErrInvalidTrigger, err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return nil, fmt.Errorf("%w: day of week: %w", ErrInvalidTrigger, err)
+	}
+	// Fold Sunday-as-7 onto 0.
+	if c.dow&(1<<7) != 0 {
+		c.dow = (c.dow | 1) &^ (1 << 7)
+	}
+	c.domAny = fields[2] == "*"
+	c.dowAny = fields[4] == "*"
+
+	return &c, nil
+}
+
+// Next returns the first matching minute strictly after t, evaluated in
+// t's location. Returns the zero time if no match exists within the search
+// limit.
+//
+// Wall-clock times skipped by a DST transition never match; times repeated
+// by a DST transition match once (the first occurrence).
+func (c *CronExpr) Next(t time.Time) time.Time {
+	loc := t.Location()
+	limit := t.Add(cronSearchLimit)
+
+	// Start at the next whole minute.
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	for t.Before(limit) {
+		y, mo, d := t.Date()
+
+		if c.month&(1<<uint(mo)) == 0 {
+			t = time.Date(y, mo+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(y, mo, d+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			next := time.Date(y, mo, d, t.Hour()+1, 0, 0, 0, loc)
+			if !next.After(t) {
+				// DST fall-back repeated the hour; step past it in absolute time.
+				next = t.Truncate(time.Hour).Add(time.Hour)
+			}
+			t = next
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches applies the classic cron OR rule for day-of-month/day-of-week.
+func (c *CronExpr) dayMatches(t time.Time) bool {
+	domOK := c.dom&(1<<uint(t.Day())) != 0
+	dowOK := c.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dowOK
+	case c.dowAny:
+		return domOK
+	default:
+		return domOK || dowOK
+	}
+}
+
+// parseCronField parses one comma-separated cron field into a bitset.
+func parseCronField(field string, minVal, maxVal int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		if part == "" {
+			return 0, fmt.Errorf("empty list element in %q", field)
+		}
+
+		step := 1
+		if base, stepStr, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+			step = n
+			part = base
+		}
+
+		lo, hi := minVal, maxVal
+		if part != "*" {
+			loStr, hiStr, isRange := strings.Cut(part, "-")
+			var err error
+			if lo, err = parseCronValue(loStr, minVal, maxVal, names); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = parseCronValue(hiStr, minVal, maxVal, names); err != nil {
+					return 0, err
+				}
+				if hi < lo {
+					return 0, fmt.Errorf("range %q is reversed", part)
+				}
+			} else if step > 1 {
+				// "5/15" means "from 5 to max every 15".
+				hi = maxVal
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// parseCronValue parses a single numeric or named cron value.
+func parseCronValue(s string, minVal, maxVal int, names map[string]int) (int, error) {
+	if names != nil {
+		if v, ok := names[strings.ToLower(s)]; ok {
+			return v, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < minVal || v > maxVal {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, minVal, maxVal)
+	}
+	return v, nil
+}
@@ -0,0 +1,141 @@
+package automation
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+	}
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := ParseCron(expr); !errors.Is(err, ErrInvalidTrigger) {
+				t.Errorf("ParseCron(%q) error = %v, want ErrInvalidTrigger", expr, err)
+			}
+		})
+	}
+}
+
+func TestCronExpr_Next(t *testing.T) {
+	utc := time.UTC
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "every minute",
+			expr: "* * * * *",
+			from: time.Date(2026, 3, 10, 12, 0, 30, 0, utc),
+			want: time.Date(2026, 3, 10, 12, 1, 0, 0, utc),
+		},
+		{
+			name: "strictly after exact match",
+			expr: "30 6 * * *",
+			from: time.Date(2026, 3, 10, 6, 30, 0, 0, utc),
+			want: time.Date(2026, 3, 11, 6, 30, 0, 0, utc),
+		},
+		{
+			name: "every two hours",
+			expr: "0 */2 * * *",
+			from: time.Date(2026, 3, 10, 13, 15, 0, 0, utc),
+			want: time.Date(2026, 3, 10, 14, 0, 0, 0, utc),
+		},
+		{
+			name: "weekday names",
+			expr: "0 7 * * mon-fri",
+			from: time.Date(2026, 3, 13, 8, 0, 0, 0, utc), // Friday
+			want: time.Date(2026, 3, 16, 7, 0, 0, 0, utc), // Monday
+		},
+		{
+			name: "sunday as 7",
+			expr: "0 9 * * 7",
+			from: time.Date(2026, 3, 10, 0, 0, 0, 0, utc),
+			want: time.Date(2026, 3, 15, 9, 0, 0, 0, utc),
+		},
+		{
+			name: "dom and dow are ORed",
+			expr: "0 9 1-7 * 1",
+			from: time.Date(2026, 3, 8, 0, 0, 0, 0, utc), // Sunday 8th
+			want: time.Date(2026, 3, 9, 9, 0, 0, 0, utc), // Monday 9th (dow match)
+		},
+		{
+			name: "month rollover",
+			expr: "0 0 1 jan *",
+			from: time.Date(2026, 3, 10, 0, 0, 0, 0, utc),
+			want: time.Date(2027, 1, 1, 0, 0, 0, 0, utc),
+		},
+		{
+			name: "leap day",
+			expr: "0 12 29 2 *",
+			from: time.Date(2026, 3, 1, 0, 0, 0, 0, utc),
+			want: time.Date(2028, 2, 29, 12, 0, 0, 0, utc),
+		},
+		{
+			name: "list and step from value",
+			expr: "5/20,50 * * * *",
+			from: time.Date(2026, 3, 10, 10, 46, 0, 0, utc),
+			want: time.Date(2026, 3, 10, 10, 50, 0, 0, utc),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+			}
+			if got := c.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronExpr_Next_Impossible(t *testing.T) {
+	c, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron: %v", err)
+	}
+	if got := c.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("Next() = %v, want zero time", got)
+	}
+}
+
+func TestCronExpr_Next_DST(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+
+	// 29 March 2026: clocks go forward 01:00 -> 02:00. 01:30 does not exist.
+	c, _ := ParseCron("30 1 * * *")
+	got := c.Next(time.Date(2026, 3, 28, 12, 0, 0, 0, london))
+	want := time.Date(2026, 3, 30, 1, 30, 0, 0, london)
+	if !got.Equal(want) {
+		t.Errorf("spring-forward: Next() = %v, want %v", got, want)
+	}
+
+	// 25 October 2026: clocks go back 02:00 -> 01:00. 01:30 occurs twice;
+	// it should fire once.
+	first := c.Next(time.Date(2026, 10, 25, 0, 0, 0, 0, london))
+	second := c.Next(first)
+	if first.Day() != 25 || second.Day() != 26 {
+		t.Errorf("fall-back: got %v then %v, want one run on the 25th then the 26th", first, second)
+	}
+}
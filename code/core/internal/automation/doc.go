@@ -18,25 +18,38 @@
 //	│  ┌──────────────────────────────────────────────┐    │
 //	│  │  Execution Pipeline                           │    │
 //	│  │  1. Load scene (cached)                       │    │
-//	│  │  2. Group actions by parallel flag            │    │
-//	│  │  3. Execute groups: goroutines + WaitGroup    │    │
-//	│  │  4. Publish MQTT commands to bridges          │    │
-//	│  │  5. Log execution result                      │    │
-//	│  │  6. Broadcast WebSocket event                 │    │
+//	│  │  2. Check conditions (skip if not met)        │    │
+//	│  │  3. Skip overridden devices (manual latch)    │    │
+//	│  │  4. Group actions by parallel flag            │    │
+//	│  │  5. Execute groups: goroutines + WaitGroup    │    │
+//	│  │  6. Publish MQTT commands to bridges          │    │
+//	│  │  7. Await bridge acks (command tracker)       │    │
+//	│  │  8. Log execution result                      │    │
+//	│  │  9. Broadcast WebSocket event                 │    │
 //	│  └──────────────────────────────────────────────┘    │
 //	└───────────────────────────────────────────────────────┘
 //
 // # Key Types
 //
 //   - Scene: Named collection of device actions with metadata
-//   - SceneAction: Individual device command (device_id, command, parameters)
+//   - SceneAction: Command for a device, or fanned out to a group, room/area or tag target
 //   - SceneExecution: Audit record of a scene activation
 //   - Engine: Orchestrator that activates scenes via MQTT
 //   - Registry: Thread-safe in-memory cache wrapping Repository
+//   - Schedule: Time, cron or sunrise/sunset trigger that activates a scene or mode
+//   - Scheduler: Background loop that fires schedules, with missed-run handling
+//   - Rule: Device state trigger (changes to / crosses / stays) that runs a scene, command or mode
+//   - RuleEngine: Evaluates rules on each state change, with debounce and cooldown
+//   - Mode: Site-wide state (home, away, night, holiday) with entry/exit scenes and behaviour
+//   - ModeManager: Performs mode transitions; publishes, broadcasts and audits them
+//   - Condition: Time window, day, mode, device state or sun elevation check (nestable with and/or/not)
+//   - ConditionEvaluator: Checks the conditions of scenes, schedules and rules
+//   - OverrideManager: Manual override latch set by physical changes; blocks lower-priority automation
+//   - CommandTracker: Follows each published command to its bridge ack (completed, failed or timeout)
 //
 // # Thread Safety
 //
-// Registry and Engine are safe for concurrent use from multiple goroutines.
+// Registry, Engine, Scheduler, RuleEngine, ModeManager, OverrideManager and CommandTracker are safe for concurrent use from multiple goroutines.
 // All public methods use appropriate synchronisation.
 //
 // # Usage
@@ -50,7 +63,34 @@
 //	}
 //
 //	engine := automation.NewEngine(registry, devices, mqtt, hub, repo, log)
+//	conditions := automation.NewConditionEvaluator(siteProvider, deviceStates)
+//	engine.SetConditionEvaluator(conditions)
+//	commands := automation.NewCommandTracker(automation.DefaultCommandTimeout, hub, log)
+//	engine.SetCommandTracker(commands) // feed bridge acks to commands.HandleAck
 //	executionID, err := engine.ActivateScene(ctx, "cinema-mode", "manual", "api")
 //
+//	scheduler := automation.NewScheduler(scheduleRepo, engine, siteProvider, hub, log)
+//	if err := scheduler.Start(ctx); err != nil {
+//	    return err
+//	}
+//	defer scheduler.Stop()
+//
+//	rules := automation.NewRuleEngine(ruleRepo, engine, mqtt, hub, log)
+//	rules.SetDeviceStateReader(deviceStates) // first edge after a restart
+//	if err := rules.Start(ctx); err != nil {
+//	    return err
+//	}
+//	defer rules.Stop()
+//	rules.HandleStateChange("pir-hall", map[string]any{"motion": true})
+//
+//	modes := automation.NewModeManager(modeRepo, siteStore, engine, mqtt, hub, auditRepo, log)
+//	modes.SetTargets(scheduler, rules)
+//	scheduler.SetModeSetter(modes)
+//	rules.SetModeSetter(modes)
+//	if err := modes.Start(ctx); err != nil {
+//	    return err
+//	}
+//	change, err := modes.SetMode(ctx, "night", "api", userID)
+//
 // See docs/automation/automation.md for the full automation specification.
 package automation
@@ -3,8 +3,11 @@ package automation
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -30,6 +33,12 @@ type MQTTClient interface {
 	Publish(topic string, payload []byte, qos byte, retained bool) error
 }
 
+// TargetResolver expands group, room/area and tag action targets into
+// device IDs. It is called each time such an action runs.
+type TargetResolver interface {
+	ResolveTarget(ctx context.Context, target ActionTarget) ([]string, error)
+}
+
 // WSHub is the interface for broadcasting WebSocket events.
 type WSHub interface {
 	// Broadcast sends an event to all clients subscribed to the given channel.
@@ -44,12 +53,25 @@ type WSHub interface {
 //
 // Thread Safety: ActivateScene is safe for concurrent use.
 type Engine struct {
-	registry *Registry
-	devices  DeviceRegistry
-	mqtt     MQTTClient
-	hub      WSHub
-	repo     Repository // For execution logging
-	logger   Logger
+	registry   *Registry
+	devices    DeviceRegistry
+	mqtt       MQTTClient
+	hub        WSHub
+	repo       Repository // For execution logging
+	conditions *ConditionEvaluator
+	overrides  *OverrideManager // Optional; nil disables the manual override latch
+	targets    TargetResolver   // Optional; required by group, scope and tag actions
+	commands   *CommandTracker  // Optional; nil counts a successful publish as completed
+	logger     Logger
+
+	// Devices with an action in an enabled scene, rebuilt by ControlsDevice
+	// when the scenes (registry version) or the targets (InvalidateTargets)
+	// have changed since it was built.
+	controlledMu      sync.Mutex
+	controlled        map[string]bool
+	controlledScenes  uint64 // Registry version controlled was built from
+	controlledTargets uint64 // targetsGen controlled was built from
+	targetsGen        atomic.Uint64
 }
 
 // NewEngine creates a new scene engine.
@@ -66,19 +88,105 @@ func NewEngine(registry *Registry, devices DeviceRegistry, mqtt MQTTClient, hub
 		logger = noopLogger{}
 	}
 	return &Engine{
-		registry: registry,
-		devices:  devices,
-		mqtt:     mqtt,
-		hub:      hub,
-		repo:     repo,
-		logger:   logger,
+		registry:   registry,
+		devices:    devices,
+		mqtt:       mqtt,
+		hub:        hub,
+		repo:       repo,
+		conditions: NewConditionEvaluator(nil, nil),
+		logger:     logger,
+	}
+}
+
+// SetConditionEvaluator sets the evaluator used for scene conditions.
+// Must be called before the engine is used. Without one, conditions are
+// evaluated in UTC with no site location, mode or device state.
+func (e *Engine) SetConditionEvaluator(conditions *ConditionEvaluator) {
+	if conditions != nil {
+		e.conditions = conditions
+	}
+}
+
+// SetOverrideManager sets the manual override latch consulted for automated
+// activations. Must be called before the engine is used.
+func (e *Engine) SetOverrideManager(overrides *OverrideManager) {
+	e.overrides = overrides
+}
+
+// SetTargetResolver sets the resolver for group, scope and tag action
+// targets. Must be called before the engine is used.
+func (e *Engine) SetTargetResolver(targets TargetResolver) {
+	e.targets = targets
+}
+
+// SetCommandTracker sets the tracker that confirms commands through bridge
+// acknowledgements. With one set, scene actions complete only once the
+// bridge acks every device command. Must be called before the engine is used.
+func (e *Engine) SetCommandTracker(commands *CommandTracker) {
+	e.commands = commands
+}
+
+// ControlsDevice reports whether any enabled scene has an action on the
+// device, directly or through a group, scope or tag target.
+//
+// The answer comes from a set of controlled devices built on first use and
+// rebuilt after scenes change or InvalidateTargets is called.
+func (e *Engine) ControlsDevice(ctx context.Context, deviceID string) bool {
+	e.controlledMu.Lock()
+	defer e.controlledMu.Unlock()
+
+	scenesVersion, targetsGen := e.registry.cacheVersion(), e.targetsGen.Load()
+	if e.controlled == nil || e.controlledScenes != scenesVersion || e.controlledTargets != targetsGen {
+		controlled, err := e.controlledDevices(ctx)
+		if err != nil {
+			return false
+		}
+		e.controlled, e.controlledScenes, e.controlledTargets = controlled, scenesVersion, targetsGen
+	}
+	return e.controlled[deviceID]
+}
+
+// InvalidateTargets makes ControlsDevice re-resolve group, scope and tag
+// targets. Call it after device groups, tags or device rooms change.
+func (e *Engine) InvalidateTargets() {
+	e.targetsGen.Add(1)
+}
+
+// controlledDevices resolves the actions of all enabled scenes to the set
+// of devices they control. Actions whose target cannot be resolved are
+// skipped.
+func (e *Engine) controlledDevices(ctx context.Context) (map[string]bool, error) {
+	scenes, err := e.registry.ListScenes(ctx)
+	if err != nil {
+		return nil, err
 	}
+	controlled := make(map[string]bool)
+	for i := range scenes {
+		if !scenes[i].Enabled {
+			continue
+		}
+		for _, a := range scenes[i].Actions {
+			ids, resolveErr := e.resolveAction(ctx, a)
+			if resolveErr != nil {
+				e.logger.Debug("scene action target not resolved",
+					"scene_id", scenes[i].ID,
+					"error", resolveErr,
+				)
+				continue
+			}
+			for _, id := range ids {
+				controlled[id] = true
+			}
+		}
+	}
+	return controlled, nil
 }
 
 // ActivateScene activates a scene by ID.
 //
-// It loads the scene, verifies it's enabled, groups actions by parallel flag,
-// executes each group (parallel actions via goroutines), and logs the result.
+// It loads the scene, verifies it's enabled, checks its conditions, groups
+// actions by parallel flag, executes each group (parallel actions via
+// goroutines), and logs the result.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout
@@ -92,6 +200,18 @@ func NewEngine(registry *Registry, devices DeviceRegistry, mqtt MQTTClient, hub
 //   - ErrSceneNotFound if scene doesn't exist
 //   - ErrSceneDisabled if scene is disabled
 //   - ErrMQTTUnavailable if MQTT client is nil
+//   - ErrConditionsNotMet if the scene's conditions do not pass; no actions
+//     run and the returned execution is recorded as StatusConditionsNotMet
+//
+// Group, scope and tag actions are resolved to devices when they run and
+// the command is sent to every member; each failed member is reported as
+// its own ActionFailure. For automated activations (schedule, event,
+// automation), devices under manual override are skipped unless the
+// scene's priority is at or above the override bypass priority.
+//
+// With a command tracker set, an action counts as completed only when the
+// bridge acknowledges every device command; a failed or missing ack is
+// recorded as an ActionFailure with the bridge's error code (or TIMEOUT).
 //
 // maxSceneExecutionTime is the hard limit for a single scene activation.
 // Even complex scenes (10+ devices, sequential groups with delays) should complete
@@ -119,6 +239,11 @@ func (e *Engine) ActivateScene(ctx context.Context, sceneID, triggerType, trigge
 		return "", ErrMQTTUnavailable
 	}
 
+	// Check conditions
+	if condErr := e.conditions.Check(ctx, scene.Conditions); condErr != nil {
+		return e.recordConditionsNotMet(ctx, scene, triggerType, triggerSource, condErr)
+	}
+
 	// Create execution record
 	now := time.Now().UTC()
 	exec := &SceneExecution{
@@ -153,6 +278,7 @@ func (e *Engine) ActivateScene(ctx context.Context, sceneID, triggerType, trigge
 
 	// Group actions by parallel flag and execute
 	groups := groupActions(scene.Actions)
+	run := &sceneRun{scene: scene, executionID: exec.ID, triggerType: triggerType}
 	var failures []ActionFailure
 	completed := 0
 	failed := 0
@@ -176,9 +302,11 @@ func (e *Engine) ActivateScene(ctx context.Context, sceneID, triggerType, trigge
 		}
 
 		// Execute group (all actions in parallel)
-		groupFailures := e.executeGroup(ctx, scene.ID, exec.ID, group)
-		completed += len(group) - len(groupFailures)
-		failed += len(groupFailures)
+		groupFailures, groupSkipped := e.executeGroup(ctx, run, group)
+		groupFailed := countFailedActions(groupFailures)
+		completed += len(group) - groupFailed - groupSkipped
+		failed += groupFailed
+		skipped += groupSkipped
 		failures = append(failures, groupFailures...)
 
 		// Check if we should abort (any action with ContinueOnError=false failed)
@@ -251,12 +379,57 @@ func (e *Engine) ActivateScene(ctx context.Context, sceneID, triggerType, trigge
 	return exec.ID, nil
 }
 
+// recordConditionsNotMet logs an activation that was stopped by the scene's
+// conditions. The execution is recorded with every action skipped, so the
+// history shows why the scene did nothing.
+func (e *Engine) recordConditionsNotMet(ctx context.Context, scene *Scene, triggerType, triggerSource string, condErr error) (string, error) {
+	now := time.Now().UTC()
+	exec := &SceneExecution{
+		ID:             GenerateID(),
+		SceneID:        scene.ID,
+		TriggeredAt:    now,
+		CompletedAt:    &now,
+		TriggerType:    triggerType,
+		Status:         StatusConditionsNotMet,
+		ActionsTotal:   len(scene.Actions),
+		ActionsSkipped: len(scene.Actions),
+	}
+	if triggerSource != "" {
+		exec.TriggerSource = &triggerSource
+	}
+
+	if createErr := e.repo.CreateExecution(ctx, exec); createErr != nil {
+		e.logger.Error("failed to create execution record", "error", createErr)
+	}
+
+	e.logger.Info("scene conditions not met",
+		"scene_id", scene.ID,
+		"scene_name", scene.Name,
+		"execution_id", exec.ID,
+		"reason", condErr.Error(),
+	)
+
+	return exec.ID, condErr
+}
+
+// sceneRun carries the state of one scene activation down to its actions.
+type sceneRun struct {
+	scene       *Scene
+	executionID string
+	triggerType string
+}
+
+// automatedTriggers are the trigger types subject to the manual override latch.
+var automatedTriggers = map[string]bool{"schedule": true, "event": true, "automation": true}
+
 // executeGroup executes all actions in a group concurrently.
-// Returns a slice of failures (empty if all succeeded).
-func (e *Engine) executeGroup(ctx context.Context, sceneID, executionID string, actions []SceneAction) []ActionFailure {
+// Returns the failures (empty if all succeeded) and the number of actions
+// skipped because every device they target is under manual override.
+func (e *Engine) executeGroup(ctx context.Context, run *sceneRun, actions []SceneAction) ([]ActionFailure, int) {
 	var (
 		mu       sync.Mutex
 		failures []ActionFailure
+		skipped  int
 		wg       sync.WaitGroup
 	)
 
@@ -265,50 +438,57 @@ func (e *Engine) executeGroup(ctx context.Context, sceneID, executionID string,
 		go func(idx int, a SceneAction) {
 			defer wg.Done()
 
-			if err := e.executeAction(ctx, sceneID, executionID, a); err != nil {
-				mu.Lock()
-				failures = append(failures, ActionFailure{
-					ActionIndex: idx,
-					DeviceID:    a.DeviceID,
-					Command:     a.Command,
-					ErrorCode:   "EXECUTION_FAILED",
-					ErrorMsg:    err.Error(),
-				})
-				mu.Unlock()
+			actionFailures, actionSkipped := e.executeAction(ctx, run, a)
+			mu.Lock()
+			for _, f := range actionFailures {
+				f.ActionIndex = idx
+				failures = append(failures, f)
+			}
+			if actionSkipped {
+				skipped++
 			}
+			mu.Unlock()
 		}(i, action)
 	}
 
 	wg.Wait()
-	return failures
+	return failures, skipped
 }
 
 // executeAction executes a single scene action.
-// It handles delay, device lookup, and MQTT command publishing.
-func (e *Engine) executeAction(ctx context.Context, sceneID, executionID string, action SceneAction) error {
+// It handles delay, target resolution, the manual override latch, MQTT
+// command publishing to each target device and, with a command tracker,
+// waiting for the bridges' acknowledgements. Returns one failure per
+// device that could not be commanded (ActionIndex is left to the caller),
+// and whether the action was skipped entirely for manual override.
+func (e *Engine) executeAction(ctx context.Context, run *sceneRun, action SceneAction) ([]ActionFailure, bool) {
 	// Handle delay
 	if action.DelayMS > 0 {
 		select {
 		case <-time.After(time.Duration(action.DelayMS) * time.Millisecond):
 		case <-ctx.Done():
-			return fmt.Errorf("action delayed: %w", ctx.Err())
+			return []ActionFailure{actionFailure(action, action.DeviceID, "EXECUTION_FAILED", fmt.Errorf("action delayed: %w", ctx.Err()))}, false
 		}
 	}
 
-	// Look up device for routing
-	dev, err := e.devices.GetDevice(ctx, action.DeviceID)
+	deviceIDs, err := e.resolveAction(ctx, action)
 	if err != nil {
-		return fmt.Errorf("device %q: %w", action.DeviceID, err)
+		return []ActionFailure{actionFailure(action, "", "TARGET_UNRESOLVED", err)}, false
 	}
-
-	// Build MQTT command payload
-	commandID := GenerateID()
-	params := action.Parameters
-	if params == nil {
-		params = make(map[string]any)
+	if len(deviceIDs) == 0 {
+		e.logger.Debug("scene action target has no devices",
+			"scene_id", run.scene.ID,
+			"target", action.Target().String(),
+		)
+		return nil, false
+	}
+	deviceIDs = e.withoutOverridden(ctx, run, deviceIDs)
+	if len(deviceIDs) == 0 {
+		return nil, true
 	}
 
 	// Add fade_ms to parameters if set
+	params := action.Parameters
 	if action.FadeMS > 0 {
 		// Deep copy to prevent shared mutable state in parallel execution
 		paramsCopy := deepCopyMap(params)
@@ -319,35 +499,207 @@ func (e *Engine) executeAction(ctx context.Context, sceneID, executionID string,
 		params = paramsCopy
 	}
 
-	mqttPayload := map[string]any{
-		"id":           commandID,
-		"device_id":    action.DeviceID,
-		"command":      action.Command,
-		"parameters":   params,
-		"source":       "scene:" + sceneID,
-		"execution_id": executionID,
+	var failures []ActionFailure
+	published := make(map[string]string, len(deviceIDs)) // command ID -> device ID
+	for _, deviceID := range deviceIDs {
+		commandID := GenerateID()
+		topic, pubErr := e.publishCommand(ctx, commandID, deviceID, action.Command, params, "scene:"+run.scene.ID, run.executionID)
+		if pubErr != nil {
+			failures = append(failures, actionFailure(action, deviceID, "EXECUTION_FAILED", pubErr))
+			continue
+		}
+		published[commandID] = deviceID
+
+		e.logger.Debug("scene action published",
+			"scene_id", run.scene.ID,
+			"device_id", deviceID,
+			"command", action.Command,
+			"topic", topic,
+		)
 	}
 
-	payload, marshalErr := json.Marshal(mqttPayload)
-	if marshalErr != nil {
-		return fmt.Errorf("marshalling command: %w", marshalErr)
+	return append(failures, e.awaitCommands(ctx, action, published)...), false
+}
+
+// awaitCommands waits for the bridge outcome of each published command and
+// returns a failure for every command that did not complete. Without a
+// command tracker, publishing is taken as success.
+func (e *Engine) awaitCommands(ctx context.Context, action SceneAction, published map[string]string) []ActionFailure {
+	if e.commands == nil {
+		return nil
 	}
 
-	// Publish command using flat topic scheme: graylogic/command/{protocol}/{device_id}
-	topic := "graylogic/command/" + dev.Protocol + "/" + action.DeviceID
+	var failures []ActionFailure
+	for commandID, deviceID := range published {
+		cmd, err := e.commands.Wait(ctx, commandID)
+		switch {
+		case err != nil:
+			failures = append(failures, actionFailure(action, deviceID, "EXECUTION_FAILED", fmt.Errorf("awaiting acknowledgement: %w", err)))
+		case cmd.Status != CommandCompleted:
+			code := cmd.ErrorCode
+			if code == "" {
+				code = strings.ToUpper(string(cmd.Status))
+			}
+			msg := cmd.ErrorMessage
+			if msg == "" {
+				msg = "command " + string(cmd.Status)
+			}
+			failures = append(failures, actionFailure(action, deviceID, code, errors.New(msg)))
+		}
+	}
+	return failures
+}
 
-	if pubErr := e.mqtt.Publish(topic, payload, 1, false); pubErr != nil {
-		return fmt.Errorf("publishing to %q: %w", topic, pubErr)
+// resolveAction returns the devices an action targets.
+func (e *Engine) resolveAction(ctx context.Context, action SceneAction) ([]string, error) {
+	if action.IsDeviceTarget() {
+		return []string{action.DeviceID}, nil
+	}
+	if e.targets == nil {
+		return nil, ErrTargetsUnavailable
+	}
+	deviceIDs, err := e.targets.ResolveTarget(ctx, action.Target())
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", action.Target(), err)
 	}
+	return deviceIDs, nil
+}
 
-	e.logger.Debug("scene action published",
-		"scene_id", sceneID,
-		"device_id", action.DeviceID,
-		"command", action.Command,
+// withoutOverridden drops devices under manual override from an automated
+// activation. A scheduled activation first clears the latches of the
+// devices it controls when reset on schedule is enabled.
+func (e *Engine) withoutOverridden(ctx context.Context, run *sceneRun, deviceIDs []string) []string {
+	if e.overrides == nil || !automatedTriggers[run.triggerType] {
+		return deviceIDs
+	}
+
+	if run.triggerType == "schedule" {
+		e.overrides.HandleScheduledScene(ctx, deviceIDs)
+	}
+
+	kept := make([]string, 0, len(deviceIDs))
+	for _, id := range deviceIDs {
+		if e.overrides.Blocks(id, run.scene.Priority) {
+			e.logger.Info("scene action skipped: device under manual override",
+				"scene_id", run.scene.ID,
+				"device_id", id,
+				"priority", run.scene.Priority,
+			)
+			continue
+		}
+		kept = append(kept, id)
+	}
+	return kept
+}
+
+// actionFailure builds the failure record for one device of an action.
+func actionFailure(action SceneAction, deviceID, code string, err error) ActionFailure {
+	f := ActionFailure{
+		DeviceID:  deviceID,
+		Command:   action.Command,
+		ErrorCode: code,
+		ErrorMsg:  err.Error(),
+	}
+	if !action.IsDeviceTarget() {
+		f.Target = action.Target().String()
+	}
+	return f
+}
+
+// countFailedActions returns the number of distinct actions with failures.
+func countFailedActions(failures []ActionFailure) int {
+	seen := make(map[int]struct{}, len(failures))
+	for _, f := range failures {
+		seen[f.ActionIndex] = struct{}{}
+	}
+	return len(seen)
+}
+
+// SendCommand publishes a single device command outside of a scene.
+// Used by automation rules whose action is a direct device command.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - deviceID: Target device
+//   - command: Command name (e.g., "on", "dim")
+//   - params: Command parameters (may be nil)
+//   - source: Originator recorded in the command payload (e.g., "rule:{id}")
+//
+// Returns:
+//   - string: The command ID
+//   - error: ErrMQTTUnavailable if MQTT is nil, or a routing/publish error
+func (e *Engine) SendCommand(ctx context.Context, deviceID, command string, params map[string]any, source string) (string, error) {
+	if e.mqtt == nil {
+		return "", ErrMQTTUnavailable
+	}
+
+	commandID := GenerateID()
+	topic, err := e.publishCommand(ctx, commandID, deviceID, command, params, source, "")
+	if err != nil {
+		return "", err
+	}
+
+	e.logger.Debug("command published",
+		"device_id", deviceID,
+		"command", command,
+		"source", source,
 		"topic", topic,
 	)
+	return commandID, nil
+}
+
+// publishCommand looks up the device's protocol and publishes a command
+// using the flat topic scheme graylogic/command/{protocol}/{device_id}.
+// With a command tracker, the command is tracked before it is published
+// and failed if publishing fails. Returns the topic used.
+func (e *Engine) publishCommand(ctx context.Context, commandID, deviceID, command string, params map[string]any, source, executionID string) (string, error) {
+	// Look up device for routing
+	dev, err := e.devices.GetDevice(ctx, deviceID)
+	if err != nil {
+		return "", fmt.Errorf("device %q: %w", deviceID, err)
+	}
+
+	if params == nil {
+		params = make(map[string]any)
+	}
 
-	return nil
+	mqttPayload := map[string]any{
+		"id":         commandID,
+		"device_id":  deviceID,
+		"command":    command,
+		"parameters": params,
+		"source":     source,
+	}
+	if executionID != "" {
+		mqttPayload["execution_id"] = executionID
+	}
+
+	payload, marshalErr := json.Marshal(mqttPayload)
+	if marshalErr != nil {
+		return "", fmt.Errorf("marshalling command: %w", marshalErr)
+	}
+
+	if e.commands != nil {
+		if trackErr := e.commands.Track(Command{
+			ID:          commandID,
+			DeviceID:    deviceID,
+			Command:     command,
+			Parameters:  params,
+			Source:      source,
+			ExecutionID: executionID,
+		}); trackErr != nil {
+			return "", trackErr
+		}
+	}
+
+	topic := "graylogic/command/" + dev.Protocol + "/" + deviceID
+	if pubErr := e.mqtt.Publish(topic, payload, 1, false); pubErr != nil {
+		if e.commands != nil {
+			e.commands.Fail(commandID, CommandErrPublishFailed, pubErr.Error()) //nolint:errcheck // tracked just above
+		}
+		return "", fmt.Errorf("publishing to %q: %w", topic, pubErr)
+	}
+	return topic, nil
 }
 
 // groupActions splits actions into sequential groups based on the Parallel flag.
@@ -275,6 +275,97 @@ func TestEngine_ActivateScene_MQTTUnavailable(t *testing.T) {
 	}
 }
 
+func TestEngine_ActivateScene_ConditionsNotMet(t *testing.T) {
+	engine, mqtt, _, repo := setupEngine(t)
+	ctx := context.Background()
+
+	repo.scenes["night-lights"] = &Scene{
+		ID:         "night-lights",
+		Name:       "Night Lights",
+		Slug:       "night-lights",
+		Enabled:    true,
+		Priority:   50,
+		Actions:    []SceneAction{{DeviceID: "light-01", Command: "set", ContinueOnError: true}},
+		Conditions: []Condition{{Type: ConditionMode, Modes: []string{"night"}}},
+	}
+	_ = engine.registry.RefreshCache(ctx)
+
+	evaluator := NewConditionEvaluator(nil, nil)
+	evaluator.SetModeReader(staticMode("home"))
+	engine.SetConditionEvaluator(evaluator)
+
+	execID, err := engine.ActivateScene(ctx, "night-lights", "manual", "api")
+	if !errors.Is(err, ErrConditionsNotMet) {
+		t.Fatalf("expected ErrConditionsNotMet, got: %v", err)
+	}
+	if len(mqtt.getMessages()) != 0 {
+		t.Error("commands published although conditions were not met")
+	}
+	exec := repo.executions[execID]
+	if exec == nil {
+		t.Fatal("no execution recorded")
+	}
+	if exec.Status != StatusConditionsNotMet || exec.ActionsSkipped != 1 || exec.CompletedAt == nil {
+		t.Errorf("execution = %+v", exec)
+	}
+
+	evaluator.SetModeReader(staticMode("night"))
+	if _, err := engine.ActivateScene(ctx, "night-lights", "manual", "api"); err != nil {
+		t.Fatalf("ActivateScene with conditions met: %v", err)
+	}
+	if len(mqtt.getMessages()) != 1 {
+		t.Errorf("expected 1 MQTT message, got %d", len(mqtt.getMessages()))
+	}
+}
+
+func TestEngine_ActivateScene_ManualOverride(t *testing.T) {
+	engine, mqtt, _, repo := setupEngine(t)
+	ctx := context.Background()
+
+	createTestScene(repo, engine.registry, "evening", "Evening", []SceneAction{
+		{DeviceID: "light-01", Command: "set", ContinueOnError: true},
+		{DeviceID: "light-02", Command: "set", ContinueOnError: true},
+	})
+	overrides := NewOverrideManager(OverrideConfig{BypassPriority: 80}, nil, nil, nil)
+	engine.SetOverrideManager(overrides)
+	overrides.Latch(ctx, "light-01", "physical")
+
+	// Automated activation skips the latched device.
+	execID, err := engine.ActivateScene(ctx, "evening", "automation", "rule:r1")
+	if err != nil {
+		t.Fatalf("ActivateScene: %v", err)
+	}
+	msgs := mqtt.getMessages()
+	if len(msgs) != 1 || msgs[0].Topic != "graylogic/command/knx/light-02" {
+		t.Fatalf("messages = %+v, want light-02 only", msgs)
+	}
+	if exec := repo.executions[execID]; exec.ActionsSkipped != 1 || exec.ActionsCompleted != 1 || exec.Status != StatusCompleted {
+		t.Errorf("execution = %+v", exec)
+	}
+
+	// Manual activation is not blocked.
+	if _, err := engine.ActivateScene(ctx, "evening", "manual", "api"); err != nil {
+		t.Fatalf("ActivateScene: %v", err)
+	}
+	if n := len(mqtt.getMessages()); n != 3 {
+		t.Errorf("messages = %d, want 3", n)
+	}
+
+	// A scheduled activation clears the latch when reset on schedule is on.
+	overrides = NewOverrideManager(OverrideConfig{BypassPriority: 80, ResetOnSchedule: true}, nil, nil, nil)
+	engine.SetOverrideManager(overrides)
+	overrides.Latch(ctx, "light-01", "physical")
+	if _, err := engine.ActivateScene(ctx, "evening", "schedule", "schedule:s1"); err != nil {
+		t.Fatalf("ActivateScene: %v", err)
+	}
+	if n := len(mqtt.getMessages()); n != 5 {
+		t.Errorf("messages = %d, want 5", n)
+	}
+	if _, ok := overrides.Get("light-01"); ok {
+		t.Error("scheduled scene did not clear the latch")
+	}
+}
+
 func TestEngine_ActivateScene_Parallel(t *testing.T) {
 	engine, mqtt, _, repo := setupEngine(t)
 	ctx := context.Background()
@@ -457,6 +548,116 @@ func TestEngine_ActivateScene_AbortOnError(t *testing.T) {
 	}
 }
 
+// mockTargetResolver resolves targets from a fixed table keyed by ActionTarget.String().
+type mockTargetResolver map[string][]string
+
+func (m mockTargetResolver) ResolveTarget(_ context.Context, target ActionTarget) ([]string, error) {
+	ids, ok := m[target.String()]
+	if !ok {
+		return nil, errors.New("group not found")
+	}
+	return ids, nil
+}
+
+// countingResolver is a mockTargetResolver that counts resolutions.
+type countingResolver struct {
+	mockTargetResolver
+	calls int
+}
+
+func (c *countingResolver) ResolveTarget(ctx context.Context, target ActionTarget) ([]string, error) {
+	c.calls++
+	return c.mockTargetResolver.ResolveTarget(ctx, target)
+}
+
+func TestEngine_ControlsDevice(t *testing.T) {
+	engine, _, _, repo := setupEngine(t)
+	ctx := context.Background()
+	resolver := &countingResolver{mockTargetResolver: mockTargetResolver{"group:east-wing": {"light-01"}}}
+	engine.SetTargetResolver(resolver)
+
+	createTestScene(repo, engine.registry, "all-off", "All Off", []SceneAction{
+		{GroupID: "east-wing", Command: "off"},
+		{DeviceID: "light-02", Command: "off"},
+	})
+
+	for range 3 {
+		if !engine.ControlsDevice(ctx, "light-01") || !engine.ControlsDevice(ctx, "light-02") {
+			t.Fatal("scene devices not controlled")
+		}
+		if engine.ControlsDevice(ctx, "blind-01") {
+			t.Fatal("blind-01 controlled")
+		}
+	}
+	if resolver.calls != 1 {
+		t.Errorf("group resolved %d times, want once", resolver.calls)
+	}
+
+	// Group membership changes are picked up once targets are invalidated
+	resolver.mockTargetResolver["group:east-wing"] = []string{"blind-01"}
+	if engine.ControlsDevice(ctx, "blind-01") {
+		t.Error("membership change seen before InvalidateTargets")
+	}
+	engine.InvalidateTargets()
+	if !engine.ControlsDevice(ctx, "blind-01") || engine.ControlsDevice(ctx, "light-01") {
+		t.Error("membership change not seen after InvalidateTargets")
+	}
+
+	// Scene changes are picked up without invalidation
+	scene, _ := engine.registry.GetScene(ctx, "all-off")
+	scene.Enabled = false
+	if err := engine.registry.UpdateScene(ctx, scene); err != nil {
+		t.Fatalf("UpdateScene: %v", err)
+	}
+	if engine.ControlsDevice(ctx, "light-02") {
+		t.Error("device of a disabled scene controlled")
+	}
+}
+
+func TestEngine_ActivateScene_FanOutTargets(t *testing.T) {
+	engine, mqtt, _, repo := setupEngine(t)
+	ctx := context.Background()
+
+	createTestScene(repo, engine.registry, "all-off", "All Off", []SceneAction{
+		{GroupID: "east-wing", Command: "off", ContinueOnError: true},
+		{ScopeType: ScopeRoom, ScopeID: "lounge", Command: "off", Parallel: true, ContinueOnError: true},
+		{Tags: []string{"garden"}, Command: "off", Parallel: true, ContinueOnError: true},
+	})
+
+	// Without a resolver, fan-out actions fail.
+	execID, err := engine.ActivateScene(ctx, "all-off", "manual", "api")
+	if err != nil {
+		t.Fatalf("ActivateScene: %v", err)
+	}
+	if exec := repo.executions[execID]; exec.ActionsFailed != 3 || exec.Failures[0].ErrorCode != "TARGET_UNRESOLVED" {
+		t.Fatalf("execution = %+v", exec)
+	}
+
+	engine.SetTargetResolver(mockTargetResolver{
+		"group:east-wing": {"light-01", "light-02", "missing-device"},
+		"room:lounge":     {"blind-01"},
+		"tags:garden":     {},
+	})
+	execID, err = engine.ActivateScene(ctx, "all-off", "manual", "api")
+	if err != nil {
+		t.Fatalf("ActivateScene: %v", err)
+	}
+
+	if n := len(mqtt.getMessages()); n != 3 {
+		t.Errorf("expected 3 MQTT messages, got %d", n)
+	}
+	exec := repo.executions[execID]
+	if exec.ActionsCompleted != 2 || exec.ActionsFailed != 1 || exec.Status != StatusPartial {
+		t.Errorf("execution = %+v", exec)
+	}
+	if len(exec.Failures) != 1 {
+		t.Fatalf("failures = %+v, want one", exec.Failures)
+	}
+	if f := exec.Failures[0]; f.DeviceID != "missing-device" || f.Target != "group:east-wing" || f.ActionIndex != 0 {
+		t.Errorf("failure = %+v", f)
+	}
+}
+
 func TestEngine_ActivateScene_ContextCancelled(t *testing.T) {
 	engine, _, _, repo := setupEngine(t)
 
@@ -760,3 +961,64 @@ func TestGroupActions(t *testing.T) {
 		})
 	}
 }
+
+// ackingMQTT answers every published command with a bridge ack: failed for
+// the devices in fail, accepted otherwise.
+type ackingMQTT struct {
+	*mockMQTT
+	tracker *CommandTracker
+	fail    map[string]bool
+}
+
+func (m *ackingMQTT) Publish(topic string, payload []byte, qos byte, retained bool) error {
+	if err := m.mockMQTT.Publish(topic, payload, qos, retained); err != nil {
+		return err
+	}
+	var cmd struct {
+		ID       string `json:"id"`
+		DeviceID string `json:"device_id"`
+	}
+	_ = json.Unmarshal(payload, &cmd)
+	status, code := "accepted", ""
+	if m.fail[cmd.DeviceID] {
+		status, code = "failed", "DEVICE_UNREACHABLE"
+	}
+	go m.tracker.HandleAck(ackPayload(cmd.ID, status, code)) //nolint:errcheck // valid payload
+	return nil
+}
+
+func TestEngine_ActivateScene_CommandAcks(t *testing.T) {
+	repo := newMockRepository()
+	registry := NewRegistry(repo)
+	tracker := NewCommandTracker(time.Minute, nil, nil)
+	t.Cleanup(tracker.Stop)
+	mqtt := &ackingMQTT{mockMQTT: newMockMQTT(), tracker: tracker, fail: map[string]bool{"light-02": true}}
+
+	engine := NewEngine(registry, newMockDeviceRegistry(), mqtt, nil, repo, nil)
+	engine.SetCommandTracker(tracker)
+	createTestScene(repo, registry, "evening", "Evening", []SceneAction{
+		{DeviceID: "light-01", Command: "on", ContinueOnError: true},
+		{DeviceID: "light-02", Command: "on", Parallel: true, ContinueOnError: true},
+	})
+
+	execID, err := engine.ActivateScene(context.Background(), "evening", "manual", "api")
+	if err != nil {
+		t.Fatalf("ActivateScene: %v", err)
+	}
+	exec := repo.executions[execID]
+	if exec.ActionsCompleted != 1 || exec.ActionsFailed != 1 || exec.Status != StatusPartial {
+		t.Errorf("execution = %+v", exec)
+	}
+	if len(exec.Failures) != 1 || exec.Failures[0].DeviceID != "light-02" || exec.Failures[0].ErrorCode != "DEVICE_UNREACHABLE" {
+		t.Errorf("failures = %+v", exec.Failures)
+	}
+
+	msgs := mqtt.getMessages()
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 MQTT messages, got %d", len(msgs))
+	}
+	cmd, ok := tracker.Get(msgs[0].Payload["id"].(string))
+	if !ok || cmd.ExecutionID != execID || cmd.Source != "scene:evening" {
+		t.Errorf("tracked command = %+v, %v", cmd, ok)
+	}
+}
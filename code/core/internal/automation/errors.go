@@ -39,4 +39,91 @@ var (
 
 	// ErrMQTTUnavailable is returned when MQTT is not connected.
 	ErrMQTTUnavailable = errors.New("scene: MQTT unavailable")
+
+	// ErrTargetsUnavailable is returned when an action targets a group, scope
+	// or tags but no target resolver is configured.
+	ErrTargetsUnavailable = errors.New("scene: target resolver not configured")
+)
+
+// Schedule errors.
+var (
+	// ErrScheduleNotFound is returned when a schedule ID does not exist.
+	ErrScheduleNotFound = errors.New("schedule: not found")
+
+	// ErrScheduleExists is returned when creating a schedule with an ID or slug that already exists.
+	ErrScheduleExists = errors.New("schedule: already exists")
+
+	// ErrInvalidSchedule is returned when schedule validation fails.
+	ErrInvalidSchedule = errors.New("schedule: invalid")
+
+	// ErrInvalidTrigger is returned when a schedule trigger is malformed.
+	ErrInvalidTrigger = errors.New("schedule: invalid trigger")
+
+	// ErrSiteLocationRequired is returned when an astronomical trigger is
+	// evaluated but the site has no latitude/longitude configured.
+	ErrSiteLocationRequired = errors.New("schedule: site location required")
+
+	// ErrNoNextRun is returned when a trigger never fires within the search horizon.
+	ErrNoNextRun = errors.New("schedule: no upcoming run")
+)
+
+// Automation rule errors.
+var (
+	// ErrRuleNotFound is returned when an automation rule ID does not exist.
+	ErrRuleNotFound = errors.New("rule: not found")
+
+	// ErrRuleExists is returned when creating a rule with an ID or slug that already exists.
+	ErrRuleExists = errors.New("rule: already exists")
+
+	// ErrInvalidRule is returned when rule validation fails.
+	ErrInvalidRule = errors.New("rule: invalid")
+)
+
+// Mode errors.
+var (
+	// ErrModeNotFound is returned when a mode ID does not exist.
+	ErrModeNotFound = errors.New("mode: not found")
+
+	// ErrModeExists is returned when creating a mode with an ID that already exists.
+	ErrModeExists = errors.New("mode: already exists")
+
+	// ErrInvalidMode is returned when mode validation fails.
+	ErrInvalidMode = errors.New("mode: invalid")
+
+	// ErrModeInUse is returned when deleting the current mode.
+	ErrModeInUse = errors.New("mode: is the current mode")
+
+	// ErrModesUnavailable is returned when a schedule or rule targets a mode
+	// but no mode manager is configured.
+	ErrModesUnavailable = errors.New("mode: mode manager not configured")
+)
+
+// Condition errors.
+var (
+	// ErrInvalidCondition is returned when condition validation fails.
+	ErrInvalidCondition = errors.New("condition: invalid")
+
+	// ErrConditionsNotMet is returned when a scene's or automation's
+	// conditions do not pass. The error message names the failing condition.
+	ErrConditionsNotMet = errors.New("condition: conditions not met")
+)
+
+// Override errors.
+var (
+	// ErrOverrideNotFound is returned when a device has no manual override latch.
+	ErrOverrideNotFound = errors.New("override: not found")
+
+	// ErrDeviceOverridden is returned when automation skips a device that is
+	// under manual override.
+	ErrDeviceOverridden = errors.New("override: device under manual override")
+)
+
+// Command errors.
+var (
+	// ErrCommandNotFound is returned when a command ID is not being tracked.
+	ErrCommandNotFound = errors.New("command: not found")
+
+	// ErrCommandExists is returned when tracking a command ID that is
+	// already tracked.
+	ErrCommandExists = errors.New("command: already tracked")
 )
@@ -0,0 +1,155 @@
+package automation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Mode is a site-wide operational state (home, away, night, holiday...).
+//
+// Exactly one mode is current at a time; the current mode ID is stored on
+// the site record (location.Site.ModeCurrent). Entering a mode runs the old
+// mode's exit scene, applies the new mode's behaviour, then runs its entry
+// scene.
+type Mode struct {
+	// Identity. ID is a slug ("night") and is what site.mode_current holds.
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	// Presentation (optional)
+	Icon      string `json:"icon,omitempty"`
+	Color     string `json:"color,omitempty"`
+	SortOrder int    `json:"sort_order"`
+
+	// Scenes run on transitions (optional)
+	EntrySceneID *string `json:"entry_scene_id,omitempty"`
+	ExitSceneID  *string `json:"exit_scene_id,omitempty"`
+
+	// Behaviour applied on entry
+	Behaviour ModeBehaviour `json:"behaviour"`
+
+	// Timestamps
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ModeBehaviour lists the schedules and automation rules a mode switches
+// on or off when it is entered. Anything not listed is left as it is, so a
+// schedule disabled by "away" stays disabled until a mode enables it again.
+type ModeBehaviour struct {
+	EnableSchedules  []string `json:"enable_schedules,omitempty"`
+	DisableSchedules []string `json:"disable_schedules,omitempty"`
+	EnableRules      []string `json:"enable_rules,omitempty"`
+	DisableRules     []string `json:"disable_rules,omitempty"`
+}
+
+// ModeChange describes the outcome of a mode transition.
+type ModeChange struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Source    string    `json:"source"` // "api", "schedule:{id}", "rule:{id}"
+	UserID    string    `json:"user_id,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+
+	// Changed is false when the requested mode was already current; no
+	// scenes run and nothing is published in that case.
+	Changed bool `json:"changed"`
+
+	ExitExecutionID  string `json:"exit_execution_id,omitempty"`
+	EntryExecutionID string `json:"entry_execution_id,omitempty"`
+
+	// Warnings lists non-fatal failures (a scene that would not start, a
+	// schedule that no longer exists). The mode change itself still applies.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// DefaultModes are the modes seeded on a new installation. They match the
+// default site.modes_available list.
+var DefaultModes = []string{"home", "away", "night", "holiday"}
+
+// Mode validation constants.
+const (
+	maxModeIconLength     = 50
+	maxModeColorLength    = 20
+	maxModeBehaviourItems = 100
+)
+
+// DeepCopy creates a complete independent copy of the Mode.
+func (m *Mode) DeepCopy() *Mode {
+	if m == nil {
+		return nil
+	}
+
+	cpy := *m
+	cpy.EntrySceneID = cloneStringPtr(m.EntrySceneID)
+	cpy.ExitSceneID = cloneStringPtr(m.ExitSceneID)
+	cpy.Behaviour = ModeBehaviour{
+		EnableSchedules:  cloneStrings(m.Behaviour.EnableSchedules),
+		DisableSchedules: cloneStrings(m.Behaviour.DisableSchedules),
+		EnableRules:      cloneStrings(m.Behaviour.EnableRules),
+		DisableRules:     cloneStrings(m.Behaviour.DisableRules),
+	}
+	return &cpy
+}
+
+// ValidateMode performs comprehensive validation on a mode.
+// Returns an error describing the first validation failure found.
+func ValidateMode(m *Mode) error {
+	if m == nil {
+		return ErrInvalidMode
+	}
+
+	if err := ValidateSlug(m.ID); err != nil {
+		return fmt.Errorf("%w: id: %w", ErrInvalidMode, err)
+	}
+	if strings.TrimSpace(m.Name) == "" {
+		return fmt.Errorf("%w: name cannot be empty", ErrInvalidMode)
+	}
+	if len(m.Name) > maxNameLength {
+		return fmt.Errorf("%w: name exceeds %d characters", ErrInvalidMode, maxNameLength)
+	}
+	if len(m.Icon) > maxModeIconLength {
+		return fmt.Errorf("%w: icon exceeds %d characters", ErrInvalidMode, maxModeIconLength)
+	}
+	if len(m.Color) > maxModeColorLength {
+		return fmt.Errorf("%w: color exceeds %d characters", ErrInvalidMode, maxModeColorLength)
+	}
+
+	b := m.Behaviour
+	if err := validateToggleLists("schedules", b.EnableSchedules, b.DisableSchedules); err != nil {
+		return err
+	}
+	return validateToggleLists("rules", b.EnableRules, b.DisableRules)
+}
+
+// validateToggleLists checks an enable/disable pair: bounded, no empty IDs,
+// and no ID in both lists.
+func validateToggleLists(kind string, enable, disable []string) error {
+	if len(enable) > maxModeBehaviourItems || len(disable) > maxModeBehaviourItems {
+		return fmt.Errorf("%w: behaviour %s lists exceed %d entries", ErrInvalidMode, kind, maxModeBehaviourItems)
+	}
+	enabled := make(map[string]struct{}, len(enable))
+	for _, id := range enable {
+		if id == "" {
+			return fmt.Errorf("%w: behaviour.enable_%s contains an empty ID", ErrInvalidMode, kind)
+		}
+		enabled[id] = struct{}{}
+	}
+	for _, id := range disable {
+		if id == "" {
+			return fmt.Errorf("%w: behaviour.disable_%s contains an empty ID", ErrInvalidMode, kind)
+		}
+		if _, ok := enabled[id]; ok {
+			return fmt.Errorf("%w: %q is in both enable_%s and disable_%s", ErrInvalidMode, id, kind, kind)
+		}
+	}
+	return nil
+}
+
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s...)
+}
@@ -0,0 +1,451 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nerrad567/gray-logic-core/internal/audit"
+	"github.com/nerrad567/gray-logic-core/internal/infrastructure/mqtt"
+)
+
+// ModeSetter changes the site mode. *ModeManager satisfies it; the
+// scheduler and rule engine use it for "mode" execute targets.
+type ModeSetter interface {
+	SetMode(ctx context.Context, modeID, source, userID string) (*ModeChange, error)
+}
+
+// ModeSiteStore persists the current mode and the list of available modes
+// on the site record.
+type ModeSiteStore interface {
+	// GetCurrentMode returns the site's current mode ID ("" if no site).
+	GetCurrentMode(ctx context.Context) (string, error)
+
+	// SetCurrentMode stores the site's current mode ID.
+	SetCurrentMode(ctx context.Context, modeID string) error
+
+	// SetAvailableModes stores the IDs of all defined modes.
+	SetAvailableModes(ctx context.Context, modeIDs []string) error
+}
+
+// ScheduleToggler enables and disables schedules. *Scheduler satisfies it.
+type ScheduleToggler interface {
+	SetEnabled(ctx context.Context, id string, enabled bool) (*Schedule, error)
+}
+
+// RuleToggler enables and disables automation rules. *RuleEngine satisfies it.
+type RuleToggler interface {
+	SetEnabled(ctx context.Context, id string, enabled bool) (*Rule, error)
+}
+
+// ModeManager owns the mode definitions and performs mode transitions.
+//
+// A transition (SetMode) is:
+//  1. Persist the new mode on the site record and clear manual overrides
+//     (when the override latch resets on mode change)
+//  2. Run the old mode's exit scene
+//  3. Apply the new mode's behaviour (enable/disable schedules and rules)
+//  4. Run the new mode's entry scene
+//  5. Publish graylogic/core/mode (retained), broadcast "mode.changed"
+//     and write an audit log entry
+//
+// Failures in steps 2-4 are reported as warnings on the ModeChange; the
+// mode still changes. Transitions are serialised.
+//
+// Thread Safety: All public methods are safe for concurrent use.
+type ModeManager struct {
+	repo   ModeRepository
+	site   ModeSiteStore
+	scenes SceneActivator
+	mqtt   MQTTClient
+	hub    WSHub
+	audit  audit.Repository
+	logger Logger
+	now    func() time.Time
+
+	mu        sync.RWMutex
+	modes     map[string]*Mode
+	current   string
+	schedules ScheduleToggler
+	rules     RuleToggler
+	overrides *OverrideManager
+
+	changeMu sync.Mutex // Serialises SetMode
+}
+
+// NewModeManager creates a new mode manager.
+//
+// Parameters:
+//   - repo: Repository for mode persistence
+//   - site: Store for the site's current and available modes
+//   - scenes: Scene activator for entry/exit scenes (usually the scene Engine)
+//   - mqttClient: MQTT client for publishing mode changes (may be nil)
+//   - hub: WebSocket hub for mode.changed events (may be nil)
+//   - auditRepo: Audit log repository (may be nil)
+//   - logger: Logger instance (may be nil)
+func NewModeManager(repo ModeRepository, site ModeSiteStore, scenes SceneActivator, mqttClient MQTTClient, hub WSHub, auditRepo audit.Repository, logger Logger) *ModeManager {
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	return &ModeManager{
+		repo:   repo,
+		site:   site,
+		scenes: scenes,
+		mqtt:   mqttClient,
+		hub:    hub,
+		audit:  auditRepo,
+		logger: logger,
+		now:    time.Now,
+		modes:  make(map[string]*Mode),
+	}
+}
+
+// SetTargets wires the schedule and rule togglers used by mode behaviour.
+// Either may be nil, in which case that part of the behaviour is skipped.
+func (m *ModeManager) SetTargets(schedules ScheduleToggler, rules RuleToggler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.schedules = schedules
+	m.rules = rules
+}
+
+// SetOverrideManager sets the manual override latch cleared on mode changes.
+func (m *ModeManager) SetOverrideManager(overrides *OverrideManager) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.overrides = overrides
+}
+
+// Start loads modes and publishes the current mode (retained) so MQTT
+// subscribers that connect later still learn it.
+func (m *ModeManager) Start(ctx context.Context) error {
+	if err := m.RefreshCache(ctx); err != nil {
+		return err
+	}
+
+	current := m.Current()
+	if current != "" {
+		m.publishMQTT(map[string]any{
+			"mode":       current,
+			"changed_at": m.now().UTC(),
+			"source":     "startup",
+		})
+	}
+
+	m.logger.Info("mode manager started", "modes", len(m.ListModes()), "current", current)
+	return nil
+}
+
+// RefreshCache reloads modes from the repository and the current mode from
+// the site, then syncs the site's list of available modes.
+func (m *ModeManager) RefreshCache(ctx context.Context) error {
+	modes, err := m.repo.ListModes(ctx)
+	if err != nil {
+		return fmt.Errorf("loading modes: %w", err)
+	}
+	current, err := m.site.GetCurrentMode(ctx)
+	if err != nil {
+		return fmt.Errorf("loading current mode: %w", err)
+	}
+
+	m.mu.Lock()
+	cache := make(map[string]*Mode, len(modes))
+	for i := range modes {
+		mode := modes[i].DeepCopy()
+		cache[mode.ID] = mode
+	}
+	m.modes = cache
+	m.current = current
+	m.mu.Unlock()
+
+	return m.syncAvailable(ctx)
+}
+
+// Current returns the current mode ID ("" if no site is configured).
+func (m *ModeManager) Current() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current
+}
+
+// ListModes returns all modes ordered by sort order, then name.
+func (m *ModeManager) ListModes() []Mode {
+	m.mu.RLock()
+	result := make([]Mode, 0, len(m.modes))
+	for _, mode := range m.modes {
+		result = append(result, *mode.DeepCopy())
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].SortOrder != result[j].SortOrder {
+			return result[i].SortOrder < result[j].SortOrder
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// GetMode returns a mode by ID.
+func (m *ModeManager) GetMode(id string) (*Mode, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	mode, ok := m.modes[id]
+	if !ok {
+		return nil, ErrModeNotFound
+	}
+	return mode.DeepCopy(), nil
+}
+
+// CreateMode validates and persists a new mode. The ID is derived from
+// the name when empty.
+func (m *ModeManager) CreateMode(ctx context.Context, mode *Mode) error {
+	if mode.ID == "" {
+		mode.ID = GenerateSlug(mode.Name)
+	}
+	if err := ValidateMode(mode); err != nil {
+		return err
+	}
+
+	if err := m.repo.CreateMode(ctx, mode); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.modes[mode.ID] = mode.DeepCopy()
+	m.mu.Unlock()
+
+	return m.syncAvailable(ctx)
+}
+
+// UpdateMode validates and persists changes to an existing mode. The new
+// behaviour applies the next time the mode is entered.
+func (m *ModeManager) UpdateMode(ctx context.Context, mode *Mode) error {
+	if _, err := m.GetMode(mode.ID); err != nil {
+		return err
+	}
+	if err := ValidateMode(mode); err != nil {
+		return err
+	}
+
+	if err := m.repo.UpdateMode(ctx, mode); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.modes[mode.ID] = mode.DeepCopy()
+	m.mu.Unlock()
+
+	// Sort order may have changed.
+	return m.syncAvailable(ctx)
+}
+
+// DeleteMode removes a mode. The current mode cannot be deleted.
+// Schedules targeting the mode are deleted by the database.
+func (m *ModeManager) DeleteMode(ctx context.Context, id string) error {
+	if m.Current() == id {
+		return ErrModeInUse
+	}
+	if err := m.repo.DeleteMode(ctx, id); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	delete(m.modes, id)
+	m.mu.Unlock()
+
+	return m.syncAvailable(ctx)
+}
+
+// SetMode makes modeID the current mode.
+//
+// Parameters:
+//   - ctx: Context for cancellation (also bounds entry/exit scene execution)
+//   - modeID: Target mode
+//   - source: Originator ("api", "schedule:{id}", "rule:{id}")
+//   - userID: User who requested the change ("" for automation)
+//
+// Returns:
+//   - *ModeChange: Transition outcome (Changed is false if already current)
+//   - error: ErrModeNotFound, or an error persisting the mode
+func (m *ModeManager) SetMode(ctx context.Context, modeID, source, userID string) (*ModeChange, error) {
+	m.changeMu.Lock()
+	defer m.changeMu.Unlock()
+
+	m.mu.RLock()
+	target, ok := m.modes[modeID]
+	from := m.current
+	var previous *Mode
+	if p, found := m.modes[from]; found {
+		previous = p.DeepCopy()
+	}
+	if ok {
+		target = target.DeepCopy()
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return nil, ErrModeNotFound
+	}
+
+	change := &ModeChange{
+		From:      from,
+		To:        modeID,
+		Source:    source,
+		UserID:    userID,
+		ChangedAt: m.now().UTC(),
+	}
+	if from == modeID {
+		return change, nil
+	}
+
+	if err := m.site.SetCurrentMode(ctx, modeID); err != nil {
+		return nil, fmt.Errorf("persisting mode: %w", err)
+	}
+	m.mu.Lock()
+	m.current = modeID
+	overrides := m.overrides
+	m.mu.Unlock()
+	change.Changed = true
+
+	if overrides != nil {
+		overrides.HandleModeChange(ctx)
+	}
+
+	if previous != nil && previous.ExitSceneID != nil {
+		change.ExitExecutionID = m.runScene(ctx, *previous.ExitSceneID, "mode:"+from+":exit", change)
+	}
+	m.applyBehaviour(ctx, target, change)
+	if target.EntrySceneID != nil {
+		change.EntryExecutionID = m.runScene(ctx, *target.EntrySceneID, "mode:"+modeID+":entry", change)
+	}
+
+	m.logger.Info("mode changed",
+		"from", from,
+		"to", modeID,
+		"source", source,
+		"warnings", len(change.Warnings),
+	)
+
+	m.publish(change)
+	m.recordAudit(ctx, change)
+	return change, nil
+}
+
+// runScene activates a transition scene, recording failures as warnings.
+func (m *ModeManager) runScene(ctx context.Context, sceneID, source string, change *ModeChange) string {
+	executionID, err := m.scenes.ActivateScene(ctx, sceneID, "automation", source)
+	if err != nil {
+		m.logger.Warn("mode scene failed", "scene_id", sceneID, "source", source, "error", err)
+		change.Warnings = append(change.Warnings, fmt.Sprintf("scene %s: %v", sceneID, err))
+		return ""
+	}
+	return executionID
+}
+
+// applyBehaviour enables and disables the schedules and rules listed by
+// the mode, recording failures as warnings.
+func (m *ModeManager) applyBehaviour(ctx context.Context, mode *Mode, change *ModeChange) {
+	m.mu.RLock()
+	schedules, rules := m.schedules, m.rules
+	m.mu.RUnlock()
+
+	b := mode.Behaviour
+	if schedules != nil {
+		for _, id := range b.EnableSchedules {
+			m.toggle(change, "schedule", id, func() error { _, err := schedules.SetEnabled(ctx, id, true); return err })
+		}
+		for _, id := range b.DisableSchedules {
+			m.toggle(change, "schedule", id, func() error { _, err := schedules.SetEnabled(ctx, id, false); return err })
+		}
+	}
+	if rules != nil {
+		for _, id := range b.EnableRules {
+			m.toggle(change, "rule", id, func() error { _, err := rules.SetEnabled(ctx, id, true); return err })
+		}
+		for _, id := range b.DisableRules {
+			m.toggle(change, "rule", id, func() error { _, err := rules.SetEnabled(ctx, id, false); return err })
+		}
+	}
+}
+
+func (m *ModeManager) toggle(change *ModeChange, kind, id string, fn func() error) {
+	if err := fn(); err != nil {
+		m.logger.Warn("mode behaviour failed", "mode", change.To, kind+"_id", id, "error", err)
+		change.Warnings = append(change.Warnings, fmt.Sprintf("%s %s: %v", kind, id, err))
+	}
+}
+
+// publish sends a mode change to MQTT and WebSocket subscribers.
+func (m *ModeManager) publish(change *ModeChange) {
+	m.publishMQTT(map[string]any{
+		"mode":       change.To,
+		"previous":   change.From,
+		"source":     change.Source,
+		"changed_at": change.ChangedAt,
+	})
+
+	if m.hub != nil {
+		m.hub.Broadcast("mode.changed", change)
+	}
+}
+
+func (m *ModeManager) publishMQTT(payload map[string]any) {
+	if m.mqtt == nil {
+		return
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		m.logger.Error("failed to marshal mode change", "error", err)
+		return
+	}
+	// Retained: the topic carries the current mode, not just an event.
+	if err := m.mqtt.Publish(mqtt.Topics{}.CoreMode(), data, 1, true); err != nil {
+		m.logger.Warn("failed to publish mode change", "error", err)
+	}
+}
+
+// recordAudit writes the mode change to the audit log.
+func (m *ModeManager) recordAudit(ctx context.Context, change *ModeChange) {
+	if m.audit == nil {
+		return
+	}
+
+	kind, _, _ := strings.Cut(change.Source, ":")
+	details := map[string]any{
+		"from":   change.From,
+		"to":     change.To,
+		"source": change.Source,
+	}
+	if len(change.Warnings) > 0 {
+		details["warnings"] = change.Warnings
+	}
+	entry := &audit.AuditLog{
+		Action:     "mode_change",
+		EntityType: "mode",
+		EntityID:   change.To,
+		UserID:     change.UserID,
+		Source:     kind,
+		Details:    details,
+	}
+	if err := m.audit.Create(context.WithoutCancel(ctx), entry); err != nil {
+		m.logger.Error("failed to write mode change audit log", "error", err)
+	}
+}
+
+// syncAvailable stores the current mode IDs (in display order) on the site.
+func (m *ModeManager) syncAvailable(ctx context.Context) error {
+	modes := m.ListModes()
+	ids := make([]string, len(modes))
+	for i, mode := range modes {
+		ids[i] = mode.ID
+	}
+	if err := m.site.SetAvailableModes(ctx, ids); err != nil {
+		return fmt.Errorf("updating available modes: %w", err)
+	}
+	return nil
+}
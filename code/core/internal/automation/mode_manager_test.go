@@ -0,0 +1,348 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nerrad567/gray-logic-core/internal/audit"
+)
+
+// mockModeSite is an in-memory ModeSiteStore.
+type mockModeSite struct {
+	mu        sync.Mutex
+	current   string
+	available []string
+}
+
+func (m *mockModeSite) GetCurrentMode(context.Context) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.current, nil
+}
+
+func (m *mockModeSite) SetCurrentMode(_ context.Context, modeID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.current = modeID
+	return nil
+}
+
+func (m *mockModeSite) SetAvailableModes(_ context.Context, ids []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.available = append([]string(nil), ids...)
+	return nil
+}
+
+// transitionLog records scene activations and toggles in call order.
+type transitionLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *transitionLog) add(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *transitionLog) get() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.events...)
+}
+
+type loggingActivator struct{ log *transitionLog }
+
+func (a loggingActivator) ActivateScene(_ context.Context, sceneID, _, triggerSource string) (string, error) {
+	if sceneID == "broken" {
+		return "", errors.New("scene unavailable")
+	}
+	a.log.add("scene " + sceneID + " " + triggerSource)
+	return "exec-" + sceneID, nil
+}
+
+type loggingScheduleToggler struct{ log *transitionLog }
+
+func (s loggingScheduleToggler) SetEnabled(_ context.Context, id string, enabled bool) (*Schedule, error) {
+	if id == "missing" {
+		return nil, ErrScheduleNotFound
+	}
+	if enabled {
+		s.log.add("enable schedule " + id)
+	} else {
+		s.log.add("disable schedule " + id)
+	}
+	return &Schedule{ID: id, Enabled: enabled}, nil
+}
+
+type loggingRuleToggler struct{ log *transitionLog }
+
+func (r loggingRuleToggler) SetEnabled(_ context.Context, id string, enabled bool) (*Rule, error) {
+	if enabled {
+		r.log.add("enable rule " + id)
+	} else {
+		r.log.add("disable rule " + id)
+	}
+	return &Rule{ID: id, Enabled: enabled}, nil
+}
+
+// mockAuditRepo captures audit log entries.
+type mockAuditRepo struct {
+	mu      sync.Mutex
+	entries []audit.AuditLog
+}
+
+func (m *mockAuditRepo) Create(_ context.Context, entry *audit.AuditLog) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+func (m *mockAuditRepo) List(context.Context, audit.Filter) (*audit.ListResult, error) {
+	return &audit.ListResult{}, nil
+}
+
+func (m *mockAuditRepo) PruneOldEntries(context.Context, time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockAuditRepo) getEntries() []audit.AuditLog {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]audit.AuditLog(nil), m.entries...)
+}
+
+type modeFixture struct {
+	manager *ModeManager
+	repo    *SQLiteModeRepository
+	scenes  *SQLiteRepository
+	site    *mockModeSite
+	log     *transitionLog
+	mqtt    *mockMQTT
+	hub     *mockWSHub
+	audit   *mockAuditRepo
+}
+
+func setupModeManager(t *testing.T, current string) *modeFixture {
+	t.Helper()
+	db := setupModeTestDB(t)
+	f := &modeFixture{
+		repo:   NewSQLiteModeRepository(db),
+		scenes: NewSQLiteRepository(db),
+		site:   &mockModeSite{current: current},
+		log:    &transitionLog{},
+		mqtt:   newMockMQTT(),
+		hub:    newMockWSHub(),
+		audit:  &mockAuditRepo{},
+	}
+	f.manager = NewModeManager(f.repo, f.site, loggingActivator{f.log}, f.mqtt, f.hub, f.audit, nil)
+	f.manager.SetTargets(loggingScheduleToggler{f.log}, loggingRuleToggler{f.log})
+	return f
+}
+
+// withScenes creates scenes so modes can reference them.
+func (f *modeFixture) withScenes(t *testing.T, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		if err := f.scenes.Create(context.Background(), testScene(id, "Scene "+id)); err != nil {
+			t.Fatalf("creating scene %s: %v", id, err)
+		}
+	}
+}
+
+func TestModeManager_StartPublishesRetainedMode(t *testing.T) {
+	f := setupModeManager(t, "home")
+	if err := f.manager.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	if got := f.manager.Current(); got != "home" {
+		t.Errorf("Current() = %q, want home", got)
+	}
+	if len(f.site.available) != 4 || f.site.available[0] != "home" {
+		t.Errorf("available modes = %v", f.site.available)
+	}
+
+	msgs := f.mqtt.getMessages()
+	if len(msgs) != 1 || msgs[0].Topic != "graylogic/core/mode" || !msgs[0].Retained {
+		t.Fatalf("published = %+v, want one retained graylogic/core/mode", msgs)
+	}
+	if msgs[0].Payload["mode"] != "home" || msgs[0].Payload["source"] != "startup" {
+		t.Errorf("payload = %v", msgs[0].Payload)
+	}
+}
+
+func TestModeManager_SetModeTransition(t *testing.T) {
+	ctx := context.Background()
+	f := setupModeManager(t, "home")
+	f.withScenes(t, "leave", "goodnight")
+
+	home, _ := f.repo.GetMode(ctx, "home")
+	exit := "leave"
+	home.ExitSceneID = &exit
+	if err := f.repo.UpdateMode(ctx, home); err != nil {
+		t.Fatalf("UpdateMode: %v", err)
+	}
+	night, _ := f.repo.GetMode(ctx, "night")
+	entry := "goodnight"
+	night.EntrySceneID = &entry
+	night.Behaviour = ModeBehaviour{
+		EnableSchedules:  []string{"sched-night"},
+		DisableSchedules: []string{"sched-day"},
+		DisableRules:     []string{"rule-motion"},
+	}
+	if err := f.repo.UpdateMode(ctx, night); err != nil {
+		t.Fatalf("UpdateMode: %v", err)
+	}
+	if err := f.manager.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	change, err := f.manager.SetMode(ctx, "night", "api", "user-1")
+	if err != nil {
+		t.Fatalf("SetMode: %v", err)
+	}
+	if !change.Changed || change.From != "home" || change.To != "night" || len(change.Warnings) != 0 {
+		t.Errorf("change = %+v", change)
+	}
+	if change.ExitExecutionID != "exec-leave" || change.EntryExecutionID != "exec-goodnight" {
+		t.Errorf("execution IDs = %q/%q", change.ExitExecutionID, change.EntryExecutionID)
+	}
+
+	want := []string{
+		"scene leave mode:home:exit",
+		"enable schedule sched-night",
+		"disable schedule sched-day",
+		"disable rule rule-motion",
+		"scene goodnight mode:night:entry",
+	}
+	got := f.log.get()
+	if len(got) != len(want) {
+		t.Fatalf("transition = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("step %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if f.manager.Current() != "night" || f.site.current != "night" {
+		t.Errorf("current = %q (site %q), want night", f.manager.Current(), f.site.current)
+	}
+
+	msgs := f.mqtt.getMessages()
+	last := msgs[len(msgs)-1]
+	if !last.Retained || last.QoS != 1 || last.Payload["mode"] != "night" || last.Payload["previous"] != "home" {
+		t.Errorf("mode message = %+v", last)
+	}
+
+	broadcasts := f.hub.getBroadcasts()
+	if len(broadcasts) != 1 || broadcasts[0].Channel != "mode.changed" {
+		t.Errorf("broadcasts = %+v, want one mode.changed", broadcasts)
+	}
+
+	entries := f.audit.getEntries()
+	if len(entries) != 1 {
+		t.Fatalf("audit entries = %d, want 1", len(entries))
+	}
+	if e := entries[0]; e.Action != "mode_change" || e.EntityID != "night" || e.UserID != "user-1" || e.Source != "api" {
+		t.Errorf("audit entry = %+v", e)
+	}
+}
+
+func TestModeManager_SetModeWarningsAndNoop(t *testing.T) {
+	ctx := context.Background()
+	f := setupModeManager(t, "home")
+	f.withScenes(t, "broken")
+
+	away, _ := f.repo.GetMode(ctx, "away")
+	entry := "broken"
+	away.EntrySceneID = &entry
+	away.Behaviour = ModeBehaviour{DisableSchedules: []string{"missing"}}
+	if err := f.repo.UpdateMode(ctx, away); err != nil {
+		t.Fatalf("UpdateMode: %v", err)
+	}
+	if err := f.manager.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	change, err := f.manager.SetMode(ctx, "away", "schedule:sched-1", "")
+	if err != nil {
+		t.Fatalf("SetMode: %v", err)
+	}
+	if !change.Changed || len(change.Warnings) != 2 {
+		t.Errorf("change = %+v, want changed with 2 warnings", change)
+	}
+	if entries := f.audit.getEntries(); len(entries) != 1 || entries[0].Source != "schedule" {
+		t.Errorf("audit entries = %+v", entries)
+	}
+
+	// Re-entering the current mode does nothing.
+	published := len(f.mqtt.getMessages())
+	change, err = f.manager.SetMode(ctx, "away", "api", "")
+	if err != nil {
+		t.Fatalf("SetMode (same): %v", err)
+	}
+	if change.Changed {
+		t.Error("Changed = true for the current mode")
+	}
+	if len(f.mqtt.getMessages()) != published || len(f.audit.getEntries()) != 1 {
+		t.Error("unchanged mode was published or audited")
+	}
+
+	if _, err := f.manager.SetMode(ctx, "disco", "api", ""); !errors.Is(err, ErrModeNotFound) {
+		t.Errorf("SetMode unknown error = %v, want ErrModeNotFound", err)
+	}
+}
+
+func TestModeManager_CRUD(t *testing.T) {
+	ctx := context.Background()
+	f := setupModeManager(t, "home")
+	if err := f.manager.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	party := &Mode{Name: "Party", SortOrder: 10}
+	if err := f.manager.CreateMode(ctx, party); err != nil {
+		t.Fatalf("CreateMode: %v", err)
+	}
+	if party.ID != "party" {
+		t.Errorf("ID = %q, want party", party.ID)
+	}
+	if n := len(f.site.available); n != 5 || f.site.available[4] != "party" {
+		t.Errorf("available modes = %v", f.site.available)
+	}
+
+	if err := f.manager.CreateMode(ctx, &Mode{ID: "party", Name: "Party"}); !errors.Is(err, ErrModeExists) {
+		t.Errorf("duplicate CreateMode error = %v, want ErrModeExists", err)
+	}
+	if err := f.manager.UpdateMode(ctx, &Mode{ID: "disco", Name: "Disco"}); !errors.Is(err, ErrModeNotFound) {
+		t.Errorf("UpdateMode unknown error = %v, want ErrModeNotFound", err)
+	}
+
+	party.SortOrder = -1
+	if err := f.manager.UpdateMode(ctx, party); err != nil {
+		t.Fatalf("UpdateMode: %v", err)
+	}
+	if f.site.available[0] != "party" {
+		t.Errorf("available modes after reorder = %v", f.site.available)
+	}
+
+	if err := f.manager.DeleteMode(ctx, "home"); !errors.Is(err, ErrModeInUse) {
+		t.Errorf("DeleteMode current error = %v, want ErrModeInUse", err)
+	}
+	if err := f.manager.DeleteMode(ctx, "party"); err != nil {
+		t.Fatalf("DeleteMode: %v", err)
+	}
+	if _, err := f.manager.GetMode("party"); !errors.Is(err, ErrModeNotFound) {
+		t.Errorf("GetMode after delete error = %v, want ErrModeNotFound", err)
+	}
+	if len(f.site.available) != 4 {
+		t.Errorf("available modes after delete = %v", f.site.available)
+	}
+}
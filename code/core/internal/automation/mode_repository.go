@@ -0,0 +1,219 @@
+package automation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ModeRepository defines the interface for mode persistence.
+type ModeRepository interface {
+	GetMode(ctx context.Context, id string) (*Mode, error)
+	ListModes(ctx context.Context) ([]Mode, error)
+	CreateMode(ctx context.Context, mode *Mode) error
+	UpdateMode(ctx context.Context, mode *Mode) error
+	DeleteMode(ctx context.Context, id string) error
+}
+
+// modeColumns is the SELECT column list for mode queries.
+const modeColumns = `id, name, icon, color, sort_order, entry_scene_id, exit_scene_id,
+			behaviour, created_at, updated_at`
+
+// SQLiteModeRepository implements ModeRepository using SQLite.
+type SQLiteModeRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteModeRepository creates a new SQLite-backed mode repository.
+func NewSQLiteModeRepository(db *sql.DB) *SQLiteModeRepository {
+	return &SQLiteModeRepository{db: db}
+}
+
+// GetMode retrieves a mode by its ID.
+func (r *SQLiteModeRepository) GetMode(ctx context.Context, id string) (*Mode, error) {
+	query := `SELECT ` + modeColumns + ` FROM modes WHERE id = ?`
+
+	mode, err := scanModeRow(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrModeNotFound
+		}
+		return nil, fmt.Errorf("querying mode by id: %w", err)
+	}
+	return mode, nil
+}
+
+// ListModes retrieves all modes ordered by sort order, then name.
+func (r *SQLiteModeRepository) ListModes(ctx context.Context) ([]Mode, error) {
+	query := `SELECT ` + modeColumns + ` FROM modes ORDER BY sort_order, name`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying modes: %w", err)
+	}
+	defer rows.Close()
+
+	var modes []Mode
+	for rows.Next() {
+		mode, scanErr := scanModeRow(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("scanning mode: %w", scanErr)
+		}
+		modes = append(modes, *mode)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating modes: %w", err)
+	}
+	return modes, nil
+}
+
+// CreateMode inserts a new mode.
+func (r *SQLiteModeRepository) CreateMode(ctx context.Context, mode *Mode) error {
+	behaviourJSON, err := json.Marshal(mode.Behaviour)
+	if err != nil {
+		return fmt.Errorf("marshalling behaviour: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if mode.CreatedAt.IsZero() {
+		mode.CreatedAt = now
+	}
+	mode.UpdatedAt = now
+
+	query := `
+		INSERT INTO modes (
+			id, name, icon, color, sort_order, entry_scene_id, exit_scene_id,
+			behaviour, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		mode.ID,
+		mode.Name,
+		nullableString(&mode.Icon),
+		nullableString(&mode.Color),
+		mode.SortOrder,
+		nullableString(mode.EntrySceneID),
+		nullableString(mode.ExitSceneID),
+		string(behaviourJSON),
+		mode.CreatedAt.Format(time.RFC3339),
+		mode.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrModeExists
+		}
+		if isForeignKeyError(err) {
+			return ErrSceneNotFound
+		}
+		return fmt.Errorf("inserting mode: %w", err)
+	}
+	return nil
+}
+
+// UpdateMode modifies an existing mode.
+func (r *SQLiteModeRepository) UpdateMode(ctx context.Context, mode *Mode) error {
+	behaviourJSON, err := json.Marshal(mode.Behaviour)
+	if err != nil {
+		return fmt.Errorf("marshalling behaviour: %w", err)
+	}
+
+	mode.UpdatedAt = time.Now().UTC()
+
+	query := `
+		UPDATE modes SET
+			name = ?, icon = ?, color = ?, sort_order = ?,
+			entry_scene_id = ?, exit_scene_id = ?, behaviour = ?,
+			updated_at = ?
+		WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query,
+		mode.Name,
+		nullableString(&mode.Icon),
+		nullableString(&mode.Color),
+		mode.SortOrder,
+		nullableString(mode.EntrySceneID),
+		nullableString(mode.ExitSceneID),
+		string(behaviourJSON),
+		mode.UpdatedAt.Format(time.RFC3339),
+		mode.ID,
+	)
+	if err != nil {
+		if isForeignKeyError(err) {
+			return ErrSceneNotFound
+		}
+		return fmt.Errorf("updating mode: %w", err)
+	}
+	return checkModeRowsAffected(result)
+}
+
+// DeleteMode removes a mode by ID. Schedules targeting the mode are
+// deleted with it (ON DELETE CASCADE).
+func (r *SQLiteModeRepository) DeleteMode(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM modes WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("deleting mode: %w", err)
+	}
+	return checkModeRowsAffected(result)
+}
+
+func checkModeRowsAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrModeNotFound
+	}
+	return nil
+}
+
+func scanModeRow(scanner rowScanner) (*Mode, error) {
+	var m Mode
+	var icon, color, entrySceneID, exitSceneID sql.NullString
+	var behaviourJSON string
+	var createdAt, updatedAt string
+
+	err := scanner.Scan(
+		&m.ID,
+		&m.Name,
+		&icon,
+		&color,
+		&m.SortOrder,
+		&entrySceneID,
+		&exitSceneID,
+		&behaviourJSON,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.Icon = icon.String
+	m.Color = color.String
+	if entrySceneID.Valid {
+		m.EntrySceneID = &entrySceneID.String
+	}
+	if exitSceneID.Valid {
+		m.ExitSceneID = &exitSceneID.String
+	}
+	if jsonErr := json.Unmarshal([]byte(behaviourJSON), &m.Behaviour); jsonErr != nil {
+		return nil, fmt.Errorf("unmarshalling behaviour: %w", jsonErr)
+	}
+
+	if t, parseErr := time.Parse(time.RFC3339, createdAt); parseErr == nil {
+		m.CreatedAt = t
+	} else {
+		return nil, fmt.Errorf("mode %s created_at: %w", m.ID, parseErr)
+	}
+	if t, parseErr := time.Parse(time.RFC3339, updatedAt); parseErr == nil {
+		m.UpdatedAt = t
+	} else {
+		return nil, fmt.Errorf("mode %s updated_at: %w", m.ID, parseErr)
+	}
+
+	return &m, nil
+}
@@ -0,0 +1,156 @@
+package automation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+// setupModeTestDB extends the scene test schema with the modes table and
+// the four seeded modes.
+func setupModeTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := setupTestDB(t)
+
+	schema := `
+		PRAGMA foreign_keys = ON;
+		CREATE TABLE modes (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			icon TEXT,
+			color TEXT,
+			sort_order INTEGER NOT NULL DEFAULT 0,
+			entry_scene_id TEXT,
+			exit_scene_id TEXT,
+			behaviour TEXT NOT NULL DEFAULT '{}',
+			created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+			updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+			FOREIGN KEY (entry_scene_id) REFERENCES scenes(id) ON DELETE SET NULL,
+			FOREIGN KEY (exit_scene_id) REFERENCES scenes(id) ON DELETE SET NULL
+		) STRICT;
+		INSERT INTO modes (id, name, sort_order) VALUES
+			('home', 'Home', 0), ('away', 'Away', 1), ('night', 'Night', 2), ('holiday', 'Holiday', 3);`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("creating modes schema: %v", err)
+	}
+	return db
+}
+
+func TestModeRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	db := setupModeTestDB(t)
+	scenes := NewSQLiteRepository(db)
+	repo := NewSQLiteModeRepository(db)
+
+	if err := scenes.Create(ctx, testScene("scene-1", "Goodnight")); err != nil {
+		t.Fatalf("creating scene: %v", err)
+	}
+
+	modes, err := repo.ListModes(ctx)
+	if err != nil {
+		t.Fatalf("ListModes: %v", err)
+	}
+	if len(modes) != 4 || modes[0].ID != "home" || modes[3].ID != "holiday" {
+		t.Fatalf("seeded modes = %+v", modes)
+	}
+
+	entry := "scene-1"
+	mode := &Mode{
+		ID:           "party",
+		Name:         "Party",
+		Icon:         "music",
+		SortOrder:    10,
+		EntrySceneID: &entry,
+		Behaviour:    ModeBehaviour{DisableSchedules: []string{"sched-1"}, EnableRules: []string{"rule-1"}},
+	}
+	if err := repo.CreateMode(ctx, mode); err != nil {
+		t.Fatalf("CreateMode: %v", err)
+	}
+	if err := repo.CreateMode(ctx, mode); !errors.Is(err, ErrModeExists) {
+		t.Errorf("duplicate CreateMode error = %v, want ErrModeExists", err)
+	}
+
+	got, err := repo.GetMode(ctx, "party")
+	if err != nil {
+		t.Fatalf("GetMode: %v", err)
+	}
+	if got.Icon != "music" || got.EntrySceneID == nil || *got.EntrySceneID != "scene-1" || got.ExitSceneID != nil {
+		t.Errorf("GetMode = %+v", got)
+	}
+	if len(got.Behaviour.DisableSchedules) != 1 || got.Behaviour.EnableRules[0] != "rule-1" {
+		t.Errorf("behaviour = %+v", got.Behaviour)
+	}
+
+	missing := "no-such-scene"
+	got.ExitSceneID = &missing
+	if err := repo.UpdateMode(ctx, got); !errors.Is(err, ErrSceneNotFound) {
+		t.Errorf("UpdateMode with unknown scene error = %v, want ErrSceneNotFound", err)
+	}
+	got.ExitSceneID = nil
+	got.Name = "Party Time"
+	if err := repo.UpdateMode(ctx, got); err != nil {
+		t.Fatalf("UpdateMode: %v", err)
+	}
+
+	// Deleting the entry scene clears the reference.
+	if err := scenes.Delete(ctx, "scene-1"); err != nil {
+		t.Fatalf("deleting scene: %v", err)
+	}
+	got, _ = repo.GetMode(ctx, "party")
+	if got.Name != "Party Time" || got.EntrySceneID != nil {
+		t.Errorf("after scene delete = %+v", got)
+	}
+
+	if err := repo.DeleteMode(ctx, "party"); err != nil {
+		t.Fatalf("DeleteMode: %v", err)
+	}
+	if _, err := repo.GetMode(ctx, "party"); !errors.Is(err, ErrModeNotFound) {
+		t.Errorf("GetMode after delete error = %v, want ErrModeNotFound", err)
+	}
+	if err := repo.DeleteMode(ctx, "party"); !errors.Is(err, ErrModeNotFound) {
+		t.Errorf("second DeleteMode error = %v, want ErrModeNotFound", err)
+	}
+}
+
+func TestScheduleRepository_ModeTarget(t *testing.T) {
+	ctx := context.Background()
+	db := setupScheduleTestDB(t)
+	modes := NewSQLiteModeRepository(db)
+	repo := NewSQLiteScheduleRepository(db)
+
+	sched := &Schedule{
+		ID:              "sched-night",
+		Name:            "Bedtime",
+		Slug:            "bedtime",
+		Enabled:         true,
+		Trigger:         ScheduleTrigger{Type: TriggerTime, Value: "23:00"},
+		Execute:         ScheduleExecute{Type: ExecuteMode, ModeID: "night"},
+		MissedRunPolicy: MissedRunSkip,
+	}
+	if err := repo.CreateSchedule(ctx, sched); err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+	got, err := repo.GetSchedule(ctx, "sched-night")
+	if err != nil {
+		t.Fatalf("GetSchedule: %v", err)
+	}
+	if got.Execute.Type != ExecuteMode || got.Execute.ModeID != "night" || got.Execute.SceneID != "" {
+		t.Errorf("execute = %+v", got.Execute)
+	}
+
+	bad := *sched
+	bad.ID, bad.Slug = "sched-bad", "bad"
+	bad.Execute.ModeID = "no-such-mode"
+	if err := repo.CreateSchedule(ctx, &bad); !errors.Is(err, ErrModeNotFound) {
+		t.Errorf("CreateSchedule with unknown mode error = %v, want ErrModeNotFound", err)
+	}
+
+	// Deleting the mode deletes its schedules.
+	if err := modes.DeleteMode(ctx, "night"); err != nil {
+		t.Fatalf("DeleteMode: %v", err)
+	}
+	if _, err := repo.GetSchedule(ctx, "sched-night"); !errors.Is(err, ErrScheduleNotFound) {
+		t.Errorf("GetSchedule after mode delete error = %v, want ErrScheduleNotFound", err)
+	}
+}
@@ -0,0 +1,58 @@
+package automation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateMode(t *testing.T) {
+	valid := func() *Mode {
+		return &Mode{
+			ID:   "night",
+			Name: "Night",
+			Behaviour: ModeBehaviour{
+				EnableSchedules: []string{"sched-1"},
+				DisableRules:    []string{"rule-1"},
+			},
+		}
+	}
+
+	if err := ValidateMode(valid()); err != nil {
+		t.Fatalf("valid mode rejected: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*Mode)
+	}{
+		{"empty id", func(m *Mode) { m.ID = "" }},
+		{"bad id", func(m *Mode) { m.ID = "Night Mode" }},
+		{"empty name", func(m *Mode) { m.Name = " " }},
+		{"icon too long", func(m *Mode) { m.Icon = string(make([]byte, maxModeIconLength+1)) }},
+		{"empty schedule id", func(m *Mode) { m.Behaviour.DisableSchedules = []string{""} }},
+		{"schedule enabled and disabled", func(m *Mode) { m.Behaviour.DisableSchedules = []string{"sched-1"} }},
+		{"rule enabled and disabled", func(m *Mode) { m.Behaviour.EnableRules = []string{"rule-1"} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := valid()
+			tt.mutate(m)
+			if err := ValidateMode(m); !errors.Is(err, ErrInvalidMode) {
+				t.Errorf("ValidateMode() error = %v, want ErrInvalidMode", err)
+			}
+		})
+	}
+}
+
+func TestMode_DeepCopy(t *testing.T) {
+	entry := "scene-1"
+	m := &Mode{ID: "away", Name: "Away", EntrySceneID: &entry, Behaviour: ModeBehaviour{DisableSchedules: []string{"sched-1"}}}
+
+	cpy := m.DeepCopy()
+	*cpy.EntrySceneID = "scene-2"
+	cpy.Behaviour.DisableSchedules[0] = "sched-2"
+
+	if *m.EntrySceneID != "scene-1" || m.Behaviour.DisableSchedules[0] != "sched-1" {
+		t.Errorf("DeepCopy shares state with the original: %+v", m)
+	}
+}
@@ -0,0 +1,458 @@
+package automation
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OverrideStateKey is the device state key holding a device's override
+// status. It sits alongside the bridge-reported keys in device state, so
+// the latch is visible wherever device state is (REST, WebSocket).
+const OverrideStateKey = "automation"
+
+// Override status values stored under OverrideStateKey.
+const (
+	OverrideStatusAuto     = "auto"
+	OverrideStatusOverride = "override"
+)
+
+// Reasons an override latch is cleared.
+const (
+	OverrideResetTimeout    = "timeout"
+	OverrideResetModeChange = "mode_change"
+	OverrideResetSchedule   = "schedule"
+	OverrideResetManual     = "manual"
+)
+
+// OverrideConfig controls the manual override latch.
+type OverrideConfig struct {
+	// Timeout clears a latch after this long. Zero means no timeout.
+	Timeout time.Duration
+
+	// BypassPriority is the scene priority (1-100) at and above which
+	// automation still controls an overridden device.
+	BypassPriority int
+
+	// ResetOnModeChange clears every latch when the site mode changes.
+	ResetOnModeChange bool
+
+	// ResetOnSchedule clears a device's latch when a scheduled scene
+	// that controls it next runs.
+	ResetOnSchedule bool
+}
+
+// OverrideStore persists override state into device state.
+type OverrideStore interface {
+	// SetOverrideState merges state into the device's OverrideStateKey value.
+	SetOverrideState(ctx context.Context, deviceID string, state map[string]any) error
+
+	// ListOverrideStates returns the OverrideStateKey value of every device
+	// that has one.
+	ListOverrideStates(ctx context.Context) (map[string]map[string]any, error)
+}
+
+// ControlChecker reports whether automation controls a device.
+// *Engine and *RuleEngine satisfy it.
+type ControlChecker interface {
+	ControlsDevice(ctx context.Context, deviceID string) bool
+}
+
+// Override is an active manual override latch on a device.
+type Override struct {
+	DeviceID       string     `json:"device_id"`
+	Source         string     `json:"source"`
+	Since          time.Time  `json:"since"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	ResetCondition string     `json:"reset_condition"`
+}
+
+// overrideLatch is the runtime state of one latch.
+type overrideLatch struct {
+	override Override
+	timer    *time.Timer
+	gen      uint64
+}
+
+// OverrideManager tracks manual override latches.
+//
+// A physical change to a device that automation controls latches the device
+// to "manual". While latched, scheduled and automated scene actions and rule
+// commands whose priority is below the configured bypass priority skip the
+// device. The latch clears on timeout, on a mode change, when the next
+// scheduled scene for the device runs, or through Clear.
+//
+// Latch state is written to device state under OverrideStateKey and
+// broadcast as "device.state_changed", and is restored by Start.
+//
+// Thread Safety: All public methods are safe for concurrent use.
+type OverrideManager struct {
+	cfg    OverrideConfig
+	store  OverrideStore
+	hub    WSHub
+	logger Logger
+
+	now       func() time.Time
+	afterFunc func(time.Duration, func()) *time.Timer
+
+	mu          sync.Mutex
+	latches     map[string]*overrideLatch
+	controllers []ControlChecker
+	gen         uint64
+	ctx         context.Context
+	stopped     bool
+}
+
+// NewOverrideManager creates a new override manager.
+//
+// Parameters:
+//   - cfg: Latch behaviour
+//   - store: Store for persisting latch state (may be nil)
+//   - hub: WebSocket hub for device.state_changed events (may be nil)
+//   - logger: Logger instance (may be nil)
+func NewOverrideManager(cfg OverrideConfig, store OverrideStore, hub WSHub, logger Logger) *OverrideManager {
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	if cfg.BypassPriority == 0 {
+		cfg.BypassPriority = maxPriority + 1
+	}
+	return &OverrideManager{
+		cfg:       cfg,
+		store:     store,
+		hub:       hub,
+		logger:    logger,
+		now:       time.Now,
+		afterFunc: time.AfterFunc,
+		latches:   make(map[string]*overrideLatch),
+		ctx:       context.Background(),
+	}
+}
+
+// SetControllers sets the checkers used to decide whether a device is under
+// automation control. With none set, every device can be latched.
+func (m *OverrideManager) SetControllers(controllers ...ControlChecker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.controllers = controllers
+}
+
+// Start restores persisted latches. Latches that expired while the
+// system was down are cleared.
+func (m *OverrideManager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	m.ctx = ctx
+	m.mu.Unlock()
+
+	if m.store == nil {
+		return nil
+	}
+	states, err := m.store.ListOverrideStates(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := m.now()
+	restored := 0
+	for deviceID, state := range states {
+		o, ok := overrideFromState(deviceID, state)
+		if !ok {
+			continue
+		}
+		if o.ExpiresAt != nil && !o.ExpiresAt.After(now) {
+			m.persist(ctx, deviceID, nil, OverrideResetTimeout)
+			continue
+		}
+		m.mu.Lock()
+		m.arm(&o)
+		m.mu.Unlock()
+		restored++
+	}
+
+	m.logger.Info("override manager started", "restored", restored)
+	return nil
+}
+
+// Stop cancels latch timers. Latches stay persisted and are restored by
+// the next Start.
+func (m *OverrideManager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopped = true
+	for _, l := range m.latches {
+		if l.timer != nil {
+			l.timer.Stop()
+		}
+	}
+}
+
+// Latch puts a device under manual override, or re-arms the timeout of an
+// existing latch. Devices no controller reports as automated are ignored.
+// Reports whether the device is latched.
+func (m *OverrideManager) Latch(ctx context.Context, deviceID, source string) bool {
+	m.mu.Lock()
+	controllers := m.controllers
+	m.mu.Unlock()
+	if !controlled(ctx, controllers, deviceID) {
+		return false
+	}
+
+	now := m.now().UTC()
+	o := Override{
+		DeviceID:       deviceID,
+		Source:         source,
+		Since:          now,
+		ResetCondition: m.resetCondition(),
+	}
+	if m.cfg.Timeout > 0 {
+		expires := now.Add(m.cfg.Timeout)
+		o.ExpiresAt = &expires
+	}
+
+	m.mu.Lock()
+	if m.stopped {
+		m.mu.Unlock()
+		return false
+	}
+	_, existing := m.latches[deviceID]
+	m.arm(&o)
+	m.mu.Unlock()
+
+	if !existing {
+		m.logger.Info("manual override latched", "device_id", deviceID, "source", source)
+	}
+	m.persist(ctx, deviceID, &o, "")
+	return true
+}
+
+// Clear releases a device's latch.
+// Returns ErrOverrideNotFound if the device is not latched.
+func (m *OverrideManager) Clear(ctx context.Context, deviceID, reason string) error {
+	m.mu.Lock()
+	ok := m.release(deviceID)
+	m.mu.Unlock()
+	if !ok {
+		return ErrOverrideNotFound
+	}
+
+	m.logger.Info("manual override cleared", "device_id", deviceID, "reason", reason)
+	m.persist(ctx, deviceID, nil, reason)
+	return nil
+}
+
+// ClearAll releases every latch and returns the number cleared.
+func (m *OverrideManager) ClearAll(ctx context.Context, reason string) int {
+	m.mu.Lock()
+	ids := make([]string, 0, len(m.latches))
+	for id := range m.latches {
+		m.release(id)
+		ids = append(ids, id)
+	}
+	m.mu.Unlock()
+
+	for _, id := range ids {
+		m.persist(ctx, id, nil, reason)
+	}
+	if len(ids) > 0 {
+		m.logger.Info("manual overrides cleared", "count", len(ids), "reason", reason)
+	}
+	return len(ids)
+}
+
+// Get returns a device's latch, if any.
+func (m *OverrideManager) Get(deviceID string) (Override, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	l, ok := m.latches[deviceID]
+	if !ok {
+		return Override{}, false
+	}
+	return l.override, true
+}
+
+// List returns every active latch, ordered by device ID.
+func (m *OverrideManager) List() []Override {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]Override, 0, len(m.latches))
+	for _, l := range m.latches {
+		result = append(result, l.override)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].DeviceID < result[j].DeviceID })
+	return result
+}
+
+// Blocks reports whether automation at the given priority must skip the
+// device because it is under manual override.
+func (m *OverrideManager) Blocks(deviceID string, priority int) bool {
+	if priority >= m.cfg.BypassPriority {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.latches[deviceID]
+	return ok
+}
+
+// HandleModeChange clears every latch when reset on mode change is enabled.
+func (m *OverrideManager) HandleModeChange(ctx context.Context) {
+	if m.cfg.ResetOnModeChange {
+		m.ClearAll(ctx, OverrideResetModeChange)
+	}
+}
+
+// HandleScheduledScene clears the latches of the devices a scheduled scene
+// is about to control, when reset on schedule is enabled.
+func (m *OverrideManager) HandleScheduledScene(ctx context.Context, deviceIDs []string) {
+	if !m.cfg.ResetOnSchedule {
+		return
+	}
+	for _, id := range deviceIDs {
+		m.Clear(ctx, id, OverrideResetSchedule) //nolint:errcheck // ErrOverrideNotFound: device not latched
+	}
+}
+
+// arm records a latch and starts its timeout. Caller must hold m.mu.
+func (m *OverrideManager) arm(o *Override) {
+	if l, ok := m.latches[o.DeviceID]; ok && l.timer != nil {
+		l.timer.Stop()
+	}
+	m.gen++
+	l := &overrideLatch{override: *o, gen: m.gen}
+	m.latches[o.DeviceID] = l
+
+	if o.ExpiresAt == nil {
+		return
+	}
+	deviceID, gen := o.DeviceID, l.gen
+	l.timer = m.afterFunc(o.ExpiresAt.Sub(m.now()), func() { m.expire(deviceID, gen) })
+}
+
+// release removes a latch. Caller must hold m.mu.
+func (m *OverrideManager) release(deviceID string) bool {
+	l, ok := m.latches[deviceID]
+	if !ok {
+		return false
+	}
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+	delete(m.latches, deviceID)
+	return true
+}
+
+// expire clears a latch whose timeout elapsed, unless it was re-armed or
+// cleared since the timer was started.
+func (m *OverrideManager) expire(deviceID string, gen uint64) {
+	m.mu.Lock()
+	l, ok := m.latches[deviceID]
+	if !ok || l.gen != gen || m.stopped {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.latches, deviceID)
+	ctx := m.ctx
+	m.mu.Unlock()
+
+	m.logger.Info("manual override cleared", "device_id", deviceID, "reason", OverrideResetTimeout)
+	m.persist(ctx, deviceID, nil, OverrideResetTimeout)
+}
+
+// persist writes a device's override status (nil o means auto) to the
+// store and broadcasts it.
+func (m *OverrideManager) persist(ctx context.Context, deviceID string, o *Override, reason string) {
+	state := overrideState(o, reason)
+	if m.store != nil {
+		if err := m.store.SetOverrideState(ctx, deviceID, state); err != nil {
+			m.logger.Error("failed to persist override state", "device_id", deviceID, "error", err)
+		}
+	}
+	if m.hub != nil {
+		m.hub.Broadcast("device.state_changed", map[string]any{
+			"device_id": deviceID,
+			"state":     map[string]any{OverrideStateKey: state},
+		})
+	}
+}
+
+// resetCondition describes how a new latch will be cleared, e.g.
+// "timeout_or_mode_change_or_manual".
+func (m *OverrideManager) resetCondition() string {
+	var parts []string
+	if m.cfg.Timeout > 0 {
+		parts = append(parts, OverrideResetTimeout)
+	}
+	if m.cfg.ResetOnModeChange {
+		parts = append(parts, OverrideResetModeChange)
+	}
+	if m.cfg.ResetOnSchedule {
+		parts = append(parts, OverrideResetSchedule)
+	}
+	parts = append(parts, OverrideResetManual)
+	return strings.Join(parts, "_or_")
+}
+
+// controlled reports whether any controller claims the device. With no
+// controllers every device counts as controlled.
+func controlled(ctx context.Context, controllers []ControlChecker, deviceID string) bool {
+	if len(controllers) == 0 {
+		return true
+	}
+	for _, c := range controllers {
+		if c.ControlsDevice(ctx, deviceID) {
+			return true
+		}
+	}
+	return false
+}
+
+// overrideState builds the device state value for an override. Keys that
+// do not apply are set to nil so that a merge into existing state removes them.
+func overrideState(o *Override, reason string) map[string]any {
+	if o == nil {
+		return map[string]any{
+			"status":          OverrideStatusAuto,
+			"source":          nil,
+			"since":           nil,
+			"expires_at":      nil,
+			"reset_condition": nil,
+			"cleared_by":      reason,
+		}
+	}
+	state := map[string]any{
+		"status":          OverrideStatusOverride,
+		"source":          o.Source,
+		"since":           o.Since.UTC().Format(time.RFC3339),
+		"expires_at":      nil,
+		"reset_condition": o.ResetCondition,
+		"cleared_by":      nil,
+	}
+	if o.ExpiresAt != nil {
+		state["expires_at"] = o.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	return state
+}
+
+// overrideFromState parses a persisted override state. ok is false unless
+// the device is latched.
+func overrideFromState(deviceID string, state map[string]any) (Override, bool) {
+	if status, _ := state["status"].(string); status != OverrideStatusOverride { //nolint:errcheck // type assertion checked via comparison
+		return Override{}, false
+	}
+	o := Override{DeviceID: deviceID}
+	o.Source, _ = state["source"].(string)                  //nolint:errcheck // optional field
+	o.ResetCondition, _ = state["reset_condition"].(string) //nolint:errcheck // optional field
+	if s, ok := state["since"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			o.Since = t
+		}
+	}
+	if s, ok := state["expires_at"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			o.ExpiresAt = &t
+		}
+	}
+	return o, true
+}
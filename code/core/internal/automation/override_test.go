@@ -0,0 +1,189 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockOverrideStore keeps override state in memory, merging like device state.
+type mockOverrideStore struct {
+	mu     sync.Mutex
+	states map[string]map[string]any
+}
+
+func newMockOverrideStore() *mockOverrideStore {
+	return &mockOverrideStore{states: make(map[string]map[string]any)}
+}
+
+func (m *mockOverrideStore) SetOverrideState(_ context.Context, deviceID string, state map[string]any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	merged := make(map[string]any, len(state))
+	for k, v := range state {
+		if v != nil {
+			merged[k] = v
+		}
+	}
+	m.states[deviceID] = merged
+	return nil
+}
+
+func (m *mockOverrideStore) ListOverrideStates(_ context.Context) (map[string]map[string]any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]map[string]any, len(m.states))
+	for id, s := range m.states {
+		out[id] = s
+	}
+	return out, nil
+}
+
+func (m *mockOverrideStore) status(deviceID string) any {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.states[deviceID]["status"]
+}
+
+type controlledDevices map[string]bool
+
+func (c controlledDevices) ControlsDevice(_ context.Context, deviceID string) bool {
+	return c[deviceID]
+}
+
+func testOverrideConfig() OverrideConfig {
+	return OverrideConfig{
+		Timeout:           2 * time.Hour,
+		BypassPriority:    80,
+		ResetOnModeChange: true,
+		ResetOnSchedule:   true,
+	}
+}
+
+func TestOverrideManager_LatchAndClear(t *testing.T) {
+	ctx := context.Background()
+	store := newMockOverrideStore()
+	hub := newMockWSHub()
+	m := NewOverrideManager(testOverrideConfig(), store, hub, nil)
+	m.SetControllers(controlledDevices{"light-01": true})
+
+	if m.Latch(ctx, "light-99", "physical") {
+		t.Error("latched a device no automation controls")
+	}
+	if !m.Latch(ctx, "light-01", "physical") {
+		t.Fatal("Latch() = false")
+	}
+
+	o, ok := m.Get("light-01")
+	if !ok || o.Source != "physical" || o.ExpiresAt == nil ||
+		o.ResetCondition != "timeout_or_mode_change_or_schedule_or_manual" {
+		t.Errorf("Get() = %+v, %v", o, ok)
+	}
+	if store.status("light-01") != OverrideStatusOverride {
+		t.Errorf("persisted status = %v", store.status("light-01"))
+	}
+	broadcasts := hub.getBroadcasts()
+	if len(broadcasts) != 1 || broadcasts[0].Channel != "device.state_changed" {
+		t.Fatalf("broadcasts = %+v", broadcasts)
+	}
+
+	if !m.Blocks("light-01", 50) {
+		t.Error("priority 50 not blocked")
+	}
+	if m.Blocks("light-01", 80) {
+		t.Error("bypass priority blocked")
+	}
+	if m.Blocks("light-02", 50) {
+		t.Error("unlatched device blocked")
+	}
+
+	if err := m.Clear(ctx, "light-01", OverrideResetManual); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if err := m.Clear(ctx, "light-01", OverrideResetManual); !errors.Is(err, ErrOverrideNotFound) {
+		t.Errorf("second Clear error = %v, want ErrOverrideNotFound", err)
+	}
+	if store.status("light-01") != OverrideStatusAuto || m.Blocks("light-01", 50) {
+		t.Errorf("after Clear: status %v, blocks %v", store.status("light-01"), m.Blocks("light-01", 50))
+	}
+}
+
+func TestOverrideManager_Timeout(t *testing.T) {
+	store := newMockOverrideStore()
+	m := NewOverrideManager(testOverrideConfig(), store, nil, nil)
+	m.afterFunc = func(d time.Duration, f func()) *time.Timer {
+		return time.AfterFunc(d/1e6, f) // 2h -> 7.2ms
+	}
+
+	m.Latch(context.Background(), "light-01", "physical")
+	time.Sleep(50 * time.Millisecond)
+
+	if _, ok := m.Get("light-01"); ok {
+		t.Error("latch did not time out")
+	}
+	if store.status("light-01") != OverrideStatusAuto {
+		t.Errorf("persisted status = %v", store.status("light-01"))
+	}
+}
+
+func TestOverrideManager_ResetTriggers(t *testing.T) {
+	ctx := context.Background()
+	m := NewOverrideManager(testOverrideConfig(), nil, nil, nil)
+
+	m.Latch(ctx, "light-01", "physical")
+	m.Latch(ctx, "light-02", "physical")
+	m.HandleScheduledScene(ctx, []string{"light-01", "blind-01"})
+	if _, ok := m.Get("light-01"); ok {
+		t.Error("scheduled scene did not clear light-01")
+	}
+	if _, ok := m.Get("light-02"); !ok {
+		t.Error("scheduled scene cleared a device it does not control")
+	}
+
+	m.HandleModeChange(ctx)
+	if n := len(m.List()); n != 0 {
+		t.Errorf("%d latches after mode change", n)
+	}
+
+	cfg := testOverrideConfig()
+	cfg.ResetOnModeChange, cfg.ResetOnSchedule = false, false
+	m = NewOverrideManager(cfg, nil, nil, nil)
+	m.Latch(ctx, "light-01", "physical")
+	m.HandleScheduledScene(ctx, []string{"light-01"})
+	m.HandleModeChange(ctx)
+	if _, ok := m.Get("light-01"); !ok {
+		t.Error("latch cleared with resets disabled")
+	}
+}
+
+func TestOverrideManager_Restore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	store := newMockOverrideStore()
+	store.states["light-01"] = map[string]any{
+		"status": OverrideStatusOverride, "source": "physical",
+		"since": now.Add(-time.Hour).Format(time.RFC3339), "expires_at": now.Add(time.Hour).Format(time.RFC3339),
+	}
+	store.states["light-02"] = map[string]any{
+		"status": OverrideStatusOverride, "source": "physical",
+		"since": now.Add(-3 * time.Hour).Format(time.RFC3339), "expires_at": now.Add(-time.Hour).Format(time.RFC3339),
+	}
+	store.states["light-03"] = map[string]any{"status": OverrideStatusAuto}
+
+	m := NewOverrideManager(testOverrideConfig(), store, nil, nil)
+	m.now = func() time.Time { return now }
+	if err := m.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(m.Stop)
+
+	list := m.List()
+	if len(list) != 1 || list[0].DeviceID != "light-01" || !list[0].Since.Equal(now.Add(-time.Hour)) {
+		t.Errorf("List() = %+v, want light-01 only", list)
+	}
+	if store.status("light-02") != OverrideStatusAuto {
+		t.Errorf("expired latch status = %v, want auto", store.status("light-02"))
+	}
+}
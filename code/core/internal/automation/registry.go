@@ -39,7 +39,8 @@ type Registry struct {
 	repo         Repository
 	cache        map[string]*Scene // Cached scenes by ID
 	activeScenes map[string]string // roomID -> sceneID (in-memory only)
-	cacheMu      sync.RWMutex      // Protects cache and activeScenes
+	version      uint64            // Incremented on every change to cache
+	cacheMu      sync.RWMutex      // Protects cache, activeScenes and version
 	logger       Logger
 }
 
@@ -76,6 +77,7 @@ func (r *Registry) RefreshCache(ctx context.Context) error {
 		s := scenes[i]
 		r.cache[s.ID] = s.DeepCopy()
 	}
+	r.version++
 
 	r.logger.Info("scene cache refreshed", "count", len(scenes))
 	return nil
@@ -213,6 +215,7 @@ func (r *Registry) CreateScene(ctx context.Context, scene *Scene) error {
 	// Update cache
 	r.cacheMu.Lock()
 	r.cache[scene.ID] = scene.DeepCopy()
+	r.version++
 	r.cacheMu.Unlock()
 
 	r.logger.Info("scene created", "id", scene.ID, "name", scene.Name)
@@ -234,6 +237,7 @@ func (r *Registry) UpdateScene(ctx context.Context, scene *Scene) error {
 	// Update cache
 	r.cacheMu.Lock()
 	r.cache[scene.ID] = scene.DeepCopy()
+	r.version++
 	r.cacheMu.Unlock()
 
 	r.logger.Info("scene updated", "id", scene.ID, "name", scene.Name)
@@ -256,12 +260,21 @@ func (r *Registry) DeleteScene(ctx context.Context, id string) error {
 		}
 	}
 	delete(r.cache, id)
+	r.version++
 	r.cacheMu.Unlock()
 
 	r.logger.Info("scene deleted", "id", id)
 	return nil
 }
 
+// cacheVersion returns a number that changes whenever a scene is created,
+// updated, deleted or reloaded.
+func (r *Registry) cacheVersion() uint64 {
+	r.cacheMu.RLock()
+	defer r.cacheMu.RUnlock()
+	return r.version
+}
+
 // GetSceneCount returns the number of cached scenes.
 func (r *Registry) GetSceneCount() int {
 	r.cacheMu.RLock()
@@ -34,7 +34,7 @@ type Repository interface {
 
 // sceneColumns is the SELECT column list for scene queries.
 const sceneColumns = `id, name, slug, description, room_id, area_id, enabled, priority,
-			icon, colour, category, actions, sort_order, created_at, updated_at`
+			icon, colour, category, actions, conditions, sort_order, created_at, updated_at`
 
 // SQLiteRepository implements Repository using SQLite.
 type SQLiteRepository struct {
@@ -106,6 +106,10 @@ func (r *SQLiteRepository) Create(ctx context.Context, scene *Scene) error {
 	if err != nil {
 		return fmt.Errorf("marshalling actions: %w", err)
 	}
+	conditionsJSON, err := marshalConditions(scene.Conditions)
+	if err != nil {
+		return err
+	}
 
 	now := time.Now().UTC()
 	if scene.CreatedAt.IsZero() {
@@ -116,8 +120,8 @@ func (r *SQLiteRepository) Create(ctx context.Context, scene *Scene) error {
 	query := `
 		INSERT INTO scenes (
 			id, name, slug, description, room_id, area_id, enabled, priority,
-			icon, colour, category, actions, sort_order, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			icon, colour, category, actions, conditions, sort_order, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err = r.db.ExecContext(ctx, query,
 		scene.ID,
@@ -132,6 +136,7 @@ func (r *SQLiteRepository) Create(ctx context.Context, scene *Scene) error {
 		nullableString(scene.Colour),
 		nullableCategory(scene.Category),
 		string(actionsJSON),
+		conditionsJSON,
 		scene.SortOrder,
 		scene.CreatedAt.Format(time.RFC3339),
 		scene.UpdatedAt.Format(time.RFC3339),
@@ -151,6 +156,10 @@ func (r *SQLiteRepository) Update(ctx context.Context, scene *Scene) error {
 	if err != nil {
 		return fmt.Errorf("marshalling actions: %w", err)
 	}
+	conditionsJSON, err := marshalConditions(scene.Conditions)
+	if err != nil {
+		return err
+	}
 
 	scene.UpdatedAt = time.Now().UTC()
 
@@ -158,7 +167,7 @@ func (r *SQLiteRepository) Update(ctx context.Context, scene *Scene) error {
 		UPDATE scenes SET
 			name = ?, slug = ?, description = ?, room_id = ?, area_id = ?,
 			enabled = ?, priority = ?, icon = ?, colour = ?, category = ?,
-			actions = ?, sort_order = ?, updated_at = ?
+			actions = ?, conditions = ?, sort_order = ?, updated_at = ?
 		WHERE id = ?`
 
 	result, err := r.db.ExecContext(ctx, query,
@@ -173,6 +182,7 @@ func (r *SQLiteRepository) Update(ctx context.Context, scene *Scene) error {
 		nullableString(scene.Colour),
 		nullableCategory(scene.Category),
 		string(actionsJSON),
+		conditionsJSON,
 		scene.SortOrder,
 		scene.UpdatedAt.Format(time.RFC3339),
 		scene.ID,
@@ -386,7 +396,7 @@ func scanSceneFromRows(rows *sql.Rows) (*Scene, error) {
 
 func scanSceneRow(scanner rowScanner) (*Scene, error) {
 	var s Scene
-	var description, roomID, areaID, icon, colour, category sql.NullString
+	var description, roomID, areaID, icon, colour, category, conditionsJSON sql.NullString
 	var actionsJSON string
 	var enabled int
 	var createdAt, updatedAt string
@@ -404,6 +414,7 @@ func scanSceneRow(scanner rowScanner) (*Scene, error) {
 		&colour,
 		&category,
 		&actionsJSON,
+		&conditionsJSON,
 		&s.SortOrder,
 		&createdAt,
 		&updatedAt,
@@ -456,6 +467,14 @@ func scanSceneRow(scanner rowScanner) (*Scene, error) {
 		s.Actions = []SceneAction{}
 	}
 
+	if conditionsJSON.Valid {
+		conditions, condErr := unmarshalConditions(conditionsJSON.String)
+		if condErr != nil {
+			return nil, condErr
+		}
+		s.Conditions = conditions
+	}
+
 	return &s, nil
 }
 
@@ -34,6 +34,7 @@ func setupTestDB(t *testing.T) *sql.DB {
 			colour TEXT,
 			category TEXT,
 			actions TEXT NOT NULL DEFAULT '[]',
+			conditions TEXT,
 			sort_order INTEGER NOT NULL DEFAULT 0,
 			created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
 			updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
@@ -371,6 +372,7 @@ func TestSQLiteRepository_Update(t *testing.T) {
 		scene.Slug = "updated-name"
 		scene.Priority = 80
 		scene.Enabled = false
+		scene.Conditions = []Condition{{Type: ConditionMode, Modes: []string{"night"}}}
 
 		err := repo.Update(ctx, scene)
 		if err != nil {
@@ -390,6 +392,9 @@ func TestSQLiteRepository_Update(t *testing.T) {
 		if got.Enabled {
 			t.Error("Enabled = true, want false")
 		}
+		if len(got.Conditions) != 1 || got.Conditions[0].Type != ConditionMode || got.Conditions[0].Modes[0] != "night" {
+			t.Errorf("Conditions = %+v", got.Conditions)
+		}
 	})
 
 	t.Run("not found", func(t *testing.T) {
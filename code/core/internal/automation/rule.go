@@ -0,0 +1,367 @@
+package automation
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Rule is an event-driven automation: when a device state key matches the
+// trigger, the rule activates a scene, sends a device command, or changes
+// the site mode. A "mode" rule on a KNX push button or binary input is how
+// a wall switch changes the mode.
+type Rule struct {
+	// Identity
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+
+	// Description (optional)
+	Description *string `json:"description,omitempty"`
+
+	// Configuration
+	Enabled bool        `json:"enabled"`
+	Trigger RuleTrigger `json:"trigger"`
+	Execute RuleExecute `json:"execute"`
+
+	// Conditions checked when the rule fires (optional). A fire whose
+	// conditions do not pass does nothing and does not start the cooldown.
+	Conditions []Condition `json:"conditions,omitempty"`
+
+	// DebounceMS is how long the trigger condition must hold before the
+	// rule fires. A state change that breaks the condition during this
+	// window cancels the pending fire. Ignored for "stays" triggers, which
+	// use ForSeconds instead.
+	DebounceMS int `json:"debounce_ms"`
+
+	// CooldownSeconds suppresses further fires for this long after the
+	// rule last fired.
+	CooldownSeconds int `json:"cooldown_seconds"`
+
+	// Runtime information
+	LastFiredAt *time.Time `json:"last_fired_at,omitempty"`
+
+	// Timestamps
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RuleTrigger defines the device state change that fires a rule.
+//
+// Fields used depend on Type:
+//   - changes_to: Value. Fires when Key changes from any other known value
+//     to Value. The first report after startup never fires, because the
+//     previous value is unknown.
+//   - crosses: Threshold and Direction. Fires when a numeric Key moves
+//     across Threshold (rising: from below to at-or-above; falling: from
+//     at-or-above to below; either: both).
+//   - stays: Value and ForSeconds. Fires once Key has equalled Value for
+//     ForSeconds without interruption; it re-arms when Key changes away.
+type RuleTrigger struct {
+	Type     RuleTriggerType `json:"type"`
+	DeviceID string          `json:"device_id"`
+	Key      string          `json:"key"`
+
+	Value      any            `json:"value,omitempty"`
+	Threshold  *float64       `json:"threshold,omitempty"`
+	Direction  CrossDirection `json:"direction,omitempty"`
+	ForSeconds int            `json:"for_seconds,omitempty"`
+}
+
+// RuleExecute defines what a rule does when it fires.
+type RuleExecute struct {
+	Type RuleExecuteType `json:"type"`
+
+	// Scene target (type "scene")
+	SceneID string `json:"scene_id,omitempty"`
+
+	// Command target (type "command")
+	DeviceID   string         `json:"device_id,omitempty"`
+	Command    string         `json:"command,omitempty"`
+	Parameters map[string]any `json:"parameters,omitempty"`
+
+	// Mode target (type "mode")
+	ModeID string `json:"mode_id,omitempty"`
+}
+
+// RuleTriggerType identifies how a rule matches state changes.
+type RuleTriggerType string
+
+const (
+	RuleTriggerChangesTo RuleTriggerType = "changes_to"
+	RuleTriggerCrosses   RuleTriggerType = "crosses"
+	RuleTriggerStays     RuleTriggerType = "stays"
+)
+
+// CrossDirection restricts a "crosses" trigger to one direction.
+type CrossDirection string
+
+const (
+	CrossRising  CrossDirection = "rising"
+	CrossFalling CrossDirection = "falling"
+	CrossEither  CrossDirection = "either"
+)
+
+// RuleExecuteType identifies what a rule executes.
+type RuleExecuteType string
+
+const (
+	RuleExecuteScene   RuleExecuteType = "scene"
+	RuleExecuteCommand RuleExecuteType = "command"
+	RuleExecuteMode    RuleExecuteType = "mode"
+)
+
+// Rule validation constants.
+const (
+	maxRuleDebounceMS      = 60_000
+	maxRuleCooldownSeconds = 24 * 60 * 60
+	maxRuleStaysSeconds    = 24 * 60 * 60
+	maxRuleKeyLength       = 100
+)
+
+// DeepCopy creates a complete independent copy of the Rule.
+func (r *Rule) DeepCopy() *Rule {
+	if r == nil {
+		return nil
+	}
+
+	cpy := *r
+	cpy.Description = cloneStringPtr(r.Description)
+	cpy.Trigger.Value = deepCopyValue(r.Trigger.Value)
+	if r.Trigger.Threshold != nil {
+		v := *r.Trigger.Threshold
+		cpy.Trigger.Threshold = &v
+	}
+	cpy.Execute.Parameters = deepCopyMap(r.Execute.Parameters)
+	cpy.Conditions = cloneConditions(r.Conditions)
+	if r.LastFiredAt != nil {
+		t := *r.LastFiredAt
+		cpy.LastFiredAt = &t
+	}
+	return &cpy
+}
+
+// ValidateRule performs comprehensive validation on a rule.
+// Returns an error describing the first validation failure found.
+func ValidateRule(r *Rule) error {
+	if r == nil {
+		return ErrInvalidRule
+	}
+
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("%w: name cannot be empty", ErrInvalidRule)
+	}
+	if len(r.Name) > maxNameLength {
+		return fmt.Errorf("%w: name exceeds %d characters", ErrInvalidRule, maxNameLength)
+	}
+	if r.Slug != "" {
+		if err := ValidateSlug(r.Slug); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidRule, err)
+		}
+	}
+	if r.Description != nil && len(*r.Description) > maxDescriptionLen {
+		return fmt.Errorf("%w: description exceeds %d characters", ErrInvalidRule, maxDescriptionLen)
+	}
+
+	if err := validateRuleTrigger(r.Trigger); err != nil {
+		return err
+	}
+	if err := validateRuleExecute(r.Execute); err != nil {
+		return err
+	}
+	if err := ValidateConditions(r.Conditions); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRule, err)
+	}
+
+	if r.DebounceMS < 0 || r.DebounceMS > maxRuleDebounceMS {
+		return fmt.Errorf("%w: debounce_ms must be 0-%d", ErrInvalidRule, maxRuleDebounceMS)
+	}
+	if r.CooldownSeconds < 0 || r.CooldownSeconds > maxRuleCooldownSeconds {
+		return fmt.Errorf("%w: cooldown_seconds must be 0-%d", ErrInvalidRule, maxRuleCooldownSeconds)
+	}
+
+	return nil
+}
+
+func validateRuleTrigger(t RuleTrigger) error {
+	if t.DeviceID == "" {
+		return fmt.Errorf("%w: trigger.device_id is required", ErrInvalidRule)
+	}
+	if t.Key == "" {
+		return fmt.Errorf("%w: trigger.key is required", ErrInvalidRule)
+	}
+	if len(t.Key) > maxRuleKeyLength {
+		return fmt.Errorf("%w: trigger.key exceeds %d characters", ErrInvalidRule, maxRuleKeyLength)
+	}
+
+	switch t.Type {
+	case RuleTriggerChangesTo:
+		if t.Value == nil {
+			return fmt.Errorf("%w: trigger.value is required for changes_to", ErrInvalidRule)
+		}
+	case RuleTriggerCrosses:
+		if t.Threshold == nil {
+			return fmt.Errorf("%w: trigger.threshold is required for crosses", ErrInvalidRule)
+		}
+		switch t.Direction {
+		case CrossRising, CrossFalling, CrossEither:
+		default:
+			return fmt.Errorf("%w: trigger.direction must be %q, %q or %q",
+				ErrInvalidRule, CrossRising, CrossFalling, CrossEither)
+		}
+	case RuleTriggerStays:
+		if t.Value == nil {
+			return fmt.Errorf("%w: trigger.value is required for stays", ErrInvalidRule)
+		}
+		if t.ForSeconds <= 0 || t.ForSeconds > maxRuleStaysSeconds {
+			return fmt.Errorf("%w: trigger.for_seconds must be 1-%d", ErrInvalidRule, maxRuleStaysSeconds)
+		}
+	default:
+		return fmt.Errorf("%w: unknown trigger type %q", ErrInvalidRule, t.Type)
+	}
+	return nil
+}
+
+func validateRuleExecute(e RuleExecute) error {
+	switch e.Type {
+	case RuleExecuteScene:
+		if e.SceneID == "" {
+			return fmt.Errorf("%w: execute.scene_id is required", ErrInvalidRule)
+		}
+	case RuleExecuteCommand:
+		if e.DeviceID == "" {
+			return fmt.Errorf("%w: execute.device_id is required", ErrInvalidRule)
+		}
+		if e.Command == "" {
+			return fmt.Errorf("%w: execute.command is required", ErrInvalidRule)
+		}
+		if len(e.Parameters) > maxParameterKeys {
+			return fmt.Errorf("%w: execute.parameters exceeds %d keys", ErrInvalidRule, maxParameterKeys)
+		}
+	case RuleExecuteMode:
+		if e.ModeID == "" {
+			return fmt.Errorf("%w: execute.mode_id is required", ErrInvalidRule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown execute type %q", ErrInvalidRule, e.Type)
+	}
+	return nil
+}
+
+// applyRuleDefaults fills optional fields with their defaults.
+func applyRuleDefaults(r *Rule) {
+	if r.Execute.Type == "" {
+		r.Execute.Type = RuleExecuteScene
+	}
+	if r.Trigger.Type == RuleTriggerCrosses && r.Trigger.Direction == "" {
+		r.Trigger.Direction = CrossEither
+	}
+}
+
+// triggerHolds reports whether the current value satisfies the trigger's
+// level condition: the state a pending (debounced) fire requires to still
+// be true when its timer expires.
+func triggerHolds(t RuleTrigger, current any) bool {
+	switch t.Type {
+	case RuleTriggerChangesTo, RuleTriggerStays:
+		return valuesEqual(current, t.Value)
+	case RuleTriggerCrosses:
+		v, ok := toFloat(current)
+		if !ok || t.Threshold == nil {
+			return false
+		}
+		switch t.Direction {
+		case CrossRising:
+			return v >= *t.Threshold
+		case CrossFalling:
+			return v < *t.Threshold
+		default:
+			// The engine resolves "either" to the direction of the crossing
+			// that armed the pending fire before calling this.
+			return true
+		}
+	default:
+		return false
+	}
+}
+
+// triggerEdge reports whether the transition from previous to current is
+// the event the trigger looks for. known is false when there is no
+// previous value for the key.
+func triggerEdge(t RuleTrigger, previous any, known bool, current any) bool {
+	switch t.Type {
+	case RuleTriggerChangesTo:
+		return known && !valuesEqual(previous, t.Value) && valuesEqual(current, t.Value)
+	case RuleTriggerCrosses:
+		if !known || t.Threshold == nil {
+			return false
+		}
+		prev, okPrev := toFloat(previous)
+		cur, okCur := toFloat(current)
+		if !okPrev || !okCur {
+			return false
+		}
+		rising := prev < *t.Threshold && cur >= *t.Threshold
+		falling := prev >= *t.Threshold && cur < *t.Threshold
+		switch t.Direction {
+		case CrossRising:
+			return rising
+		case CrossFalling:
+			return falling
+		default:
+			return rising || falling
+		}
+	default:
+		// "stays" triggers are level-based; the engine arms them on any
+		// matching report rather than on an edge.
+		return false
+	}
+}
+
+// valuesEqual compares two state values. Numbers compare by value
+// regardless of their Go type (JSON decodes every number as float64).
+func valuesEqual(a, b any) bool {
+	af, aNum := toFloat(a)
+	bf, bNum := toFloat(b)
+	if aNum && bNum {
+		return af == bf
+	}
+	if aNum != bNum {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// toFloat converts a numeric state value to float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, !math.IsNaN(n)
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
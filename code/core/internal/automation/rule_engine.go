@@ -0,0 +1,718 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nerrad567/gray-logic-core/internal/infrastructure/mqtt"
+)
+
+// RuleActions is the interface the rule engine needs to execute rules.
+// *Engine satisfies it.
+type RuleActions interface {
+	SceneActivator
+
+	// SendCommand publishes a single device command and returns its ID.
+	SendCommand(ctx context.Context, deviceID, command string, params map[string]any, source string) (string, error)
+}
+
+// Rule result status values published on the fired topic.
+const (
+	ruleStatusOK               = "ok"
+	ruleStatusFailed           = "failed"
+	ruleStatusConditionsNotMet = "conditions_not_met"
+	ruleStatusOverridden       = "overridden"
+)
+
+// ruleState is the per-rule runtime state. It is reset whenever the rule is
+// edited, apart from lastFired, which carries the cooldown across edits.
+type ruleState struct {
+	// timer is the pending (debounced or "stays") fire, nil when idle.
+	timer *time.Timer
+
+	// gen invalidates timers that expire after they were superseded or
+	// cancelled (time.Timer.Stop cannot stop a callback already running).
+	gen uint64
+
+	// crossed is the direction of the crossing that armed a pending fire
+	// for a "crosses" trigger with direction "either".
+	crossed CrossDirection
+
+	// latched is set once a "stays" trigger has fired for the current run
+	// of matching values; it clears when the value changes away.
+	latched bool
+
+	lastFired time.Time
+}
+
+// RuleEngine evaluates automation rules against device state changes.
+//
+// Every state update from the bridges is passed to HandleStateChange. The
+// engine compares each watched key with the previously reported value,
+// arms a timer for debounced and "stays" triggers, and executes the rule
+// when the condition still holds once the timer expires. Results are
+// published on graylogic/core/automation/{rule_id}/fired and broadcast to
+// WebSocket clients as "automation.fired".
+//
+// Thread Safety: All public methods are safe for concurrent use.
+// HandleStateChange never blocks on rule execution.
+type RuleEngine struct {
+	repo       RuleRepository
+	actions    RuleActions
+	modes      ModeSetter        // Optional; required by "mode" rules
+	devices    DeviceStateReader // Optional; seeds the values of watched devices
+	conditions *ConditionEvaluator
+	overrides  *OverrideManager // Optional; nil disables the manual override latch
+	mqtt       MQTTClient
+	hub        WSHub
+	logger     Logger
+
+	now       func() time.Time
+	afterFunc func(time.Duration, func()) *time.Timer
+
+	mu       sync.Mutex
+	rules    map[string]*Rule
+	byDevice map[string][]string       // Trigger device ID -> rule IDs
+	values   map[string]map[string]any // Last reported state per watched device
+	states   map[string]*ruleState
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopped  bool
+
+	wg sync.WaitGroup
+}
+
+// NewRuleEngine creates a new automation rule engine.
+//
+// Parameters:
+//   - repo: Repository for rule persistence
+//   - actions: Scene activator and command sender (usually the scene Engine)
+//   - mqttClient: MQTT client for publishing rule results (may be nil)
+//   - hub: WebSocket hub for automation.fired events (may be nil)
+//   - logger: Logger instance (may be nil)
+func NewRuleEngine(repo RuleRepository, actions RuleActions, mqttClient MQTTClient, hub WSHub, logger Logger) *RuleEngine {
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	return &RuleEngine{
+		repo:       repo,
+		actions:    actions,
+		conditions: NewConditionEvaluator(nil, nil),
+		mqtt:       mqttClient,
+		hub:        hub,
+		logger:     logger,
+		now:        time.Now,
+		afterFunc:  time.AfterFunc,
+		rules:      make(map[string]*Rule),
+		byDevice:   make(map[string][]string),
+		values:     make(map[string]map[string]any),
+		states:     make(map[string]*ruleState),
+		ctx:        context.Background(),
+	}
+}
+
+// Start loads rules and enables rule execution under ctx.
+func (e *RuleEngine) Start(ctx context.Context) error {
+	if err := e.RefreshCache(ctx); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.ctx, e.cancel = context.WithCancel(ctx)
+	count := len(e.rules)
+	e.mu.Unlock()
+
+	e.logger.Info("rule engine started", "rules", count)
+	return nil
+}
+
+// Stop cancels pending timers and waits for in-flight rule executions.
+// State changes received after Stop are ignored.
+func (e *RuleEngine) Stop() {
+	e.mu.Lock()
+	e.stopped = true
+	for _, st := range e.states {
+		cancelPending(st)
+	}
+	if e.cancel != nil {
+		e.cancel()
+	}
+	e.mu.Unlock()
+
+	e.wg.Wait()
+}
+
+// RefreshCache reloads all rules from the repository. Pending fires are
+// cancelled; remembered device values are kept, and those of newly watched
+// devices are seeded from their last known state.
+func (e *RuleEngine) RefreshCache(ctx context.Context) error {
+	rules, err := e.repo.ListRules(ctx)
+	if err != nil {
+		return fmt.Errorf("loading rules: %w", err)
+	}
+
+	e.mu.Lock()
+	for _, st := range e.states {
+		cancelPending(st)
+	}
+	e.rules = make(map[string]*Rule, len(rules))
+	e.states = make(map[string]*ruleState, len(rules))
+	for i := range rules {
+		rule := rules[i].DeepCopy()
+		e.rules[rule.ID] = rule
+		e.states[rule.ID] = newRuleState(rule)
+	}
+	e.reindexLocked()
+	e.mu.Unlock()
+	e.seedValues(ctx)
+
+	e.logger.Info("rule cache refreshed", "count", len(rules))
+	return nil
+}
+
+// ListRules returns all rules sorted by name.
+func (e *RuleEngine) ListRules() []Rule {
+	e.mu.Lock()
+	result := make([]Rule, 0, len(e.rules))
+	for _, rule := range e.rules {
+		result = append(result, *rule.DeepCopy())
+	}
+	e.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// GetRule returns a rule by ID.
+func (e *RuleEngine) GetRule(id string) (*Rule, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rule, ok := e.rules[id]
+	if !ok {
+		return nil, ErrRuleNotFound
+	}
+	return rule.DeepCopy(), nil
+}
+
+// CreateRule validates, persists and activates a new rule.
+// ID and slug are generated when empty.
+func (e *RuleEngine) CreateRule(ctx context.Context, rule *Rule) error {
+	if rule.ID == "" {
+		rule.ID = GenerateID()
+	}
+	if rule.Slug == "" {
+		rule.Slug = GenerateSlug(rule.Name)
+	}
+	applyRuleDefaults(rule)
+
+	if err := ValidateRule(rule); err != nil {
+		return err
+	}
+	rule.LastFiredAt = nil
+
+	if err := e.repo.CreateRule(ctx, rule); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.rules[rule.ID] = rule.DeepCopy()
+	e.states[rule.ID] = newRuleState(rule)
+	e.reindexLocked()
+	e.mu.Unlock()
+	e.seedValues(ctx)
+
+	return nil
+}
+
+// UpdateRule validates and persists changes to an existing rule. Any
+// pending fire is cancelled; the cooldown from the last fire still applies.
+func (e *RuleEngine) UpdateRule(ctx context.Context, rule *Rule) error {
+	e.mu.Lock()
+	existing, ok := e.rules[rule.ID]
+	var lastFired *time.Time
+	if ok && existing.LastFiredAt != nil {
+		t := *existing.LastFiredAt
+		lastFired = &t
+	}
+	e.mu.Unlock()
+	if !ok {
+		return ErrRuleNotFound
+	}
+
+	if rule.Slug == "" {
+		rule.Slug = GenerateSlug(rule.Name)
+	}
+	applyRuleDefaults(rule)
+
+	if err := ValidateRule(rule); err != nil {
+		return err
+	}
+	rule.LastFiredAt = lastFired
+
+	if err := e.repo.UpdateRule(ctx, rule); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	if st, ok := e.states[rule.ID]; ok {
+		cancelPending(st)
+	}
+	e.rules[rule.ID] = rule.DeepCopy()
+	e.states[rule.ID] = newRuleState(rule)
+	e.reindexLocked()
+	e.mu.Unlock()
+	e.seedValues(ctx)
+
+	return nil
+}
+
+// SetModeSetter sets the mode manager used by rules that change the site
+// mode.
+func (e *RuleEngine) SetModeSetter(modes ModeSetter) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.modes = modes
+}
+
+// SetDeviceStateReader sets the source of last known device state. Devices
+// watched by rules start from it, so the first edge after a restart or a
+// rule change is not lost. Call before Start.
+func (e *RuleEngine) SetDeviceStateReader(devices DeviceStateReader) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.devices = devices
+}
+
+// SetConditionEvaluator sets the evaluator used for rule conditions.
+func (e *RuleEngine) SetConditionEvaluator(conditions *ConditionEvaluator) {
+	if conditions == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.conditions = conditions
+}
+
+// SetOverrideManager sets the manual override latch consulted before rule
+// commands. Rules run at the default scene priority.
+func (e *RuleEngine) SetOverrideManager(overrides *OverrideManager) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.overrides = overrides
+}
+
+// ControlsDevice reports whether any enabled rule sends commands to the device.
+func (e *RuleEngine) ControlsDevice(_ context.Context, deviceID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, rule := range e.rules {
+		if rule.Enabled && rule.Execute.Type == RuleExecuteCommand && rule.Execute.DeviceID == deviceID {
+			return true
+		}
+	}
+	return false
+}
+
+// SetEnabled enables or disables a rule.
+func (e *RuleEngine) SetEnabled(ctx context.Context, id string, enabled bool) (*Rule, error) {
+	rule, err := e.GetRule(id)
+	if err != nil {
+		return nil, err
+	}
+	if rule.Enabled == enabled {
+		return rule, nil
+	}
+	rule.Enabled = enabled
+	if err := e.UpdateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteRule removes a rule and cancels any pending fire.
+func (e *RuleEngine) DeleteRule(ctx context.Context, id string) error {
+	if err := e.repo.DeleteRule(ctx, id); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	if st, ok := e.states[id]; ok {
+		cancelPending(st)
+	}
+	delete(e.rules, id)
+	delete(e.states, id)
+	e.reindexLocked()
+	e.mu.Unlock()
+
+	return nil
+}
+
+// HandleStateChange evaluates the rules watching deviceID against a state
+// update. state may be partial; keys not present are left unchanged.
+//
+// It only arms timers and starts goroutines, so it is safe to call from an
+// MQTT message handler.
+func (e *RuleEngine) HandleStateChange(deviceID string, state map[string]any) {
+	if deviceID == "" || len(state) == 0 {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ruleIDs, watched := e.byDevice[deviceID]
+	if !watched || e.stopped {
+		return
+	}
+
+	previous := e.values[deviceID]
+	for _, id := range ruleIDs {
+		rule := e.rules[id]
+		if rule == nil || !rule.Enabled {
+			continue
+		}
+		current, ok := state[rule.Trigger.Key]
+		if !ok {
+			continue
+		}
+		prev, known := previous[rule.Trigger.Key]
+		e.evaluateLocked(rule, e.states[id], prev, known, current)
+	}
+
+	if previous == nil {
+		previous = make(map[string]any, len(state))
+		e.values[deviceID] = previous
+	}
+	for k, v := range state {
+		previous[k] = deepCopyValue(v)
+	}
+}
+
+// evaluateLocked applies one state transition to one rule.
+// Caller must hold e.mu.
+func (e *RuleEngine) evaluateLocked(rule *Rule, st *ruleState, prev any, known bool, current any) {
+	trigger := rule.Trigger
+
+	if st.timer != nil && !e.holdsLocked(trigger, st, current) {
+		cancelPending(st)
+		e.logger.Debug("pending rule fire cancelled", "rule_id", rule.ID, "value", current)
+	}
+
+	if trigger.Type == RuleTriggerStays {
+		if !valuesEqual(current, trigger.Value) {
+			st.latched = false
+			return
+		}
+		if st.latched || st.timer != nil {
+			return
+		}
+		e.armLocked(rule, st, time.Duration(trigger.ForSeconds)*time.Second, current)
+		return
+	}
+
+	if !triggerEdge(trigger, prev, known, current) {
+		return
+	}
+
+	if trigger.Type == RuleTriggerCrosses && trigger.Direction == CrossEither {
+		st.crossed = CrossFalling
+		if v, _ := toFloat(current); trigger.Threshold != nil && v >= *trigger.Threshold {
+			st.crossed = CrossRising
+		}
+	}
+
+	if rule.DebounceMS == 0 {
+		e.fireLocked(rule, st, current)
+		return
+	}
+	// A new edge restarts the debounce window.
+	cancelPending(st)
+	e.armLocked(rule, st, time.Duration(rule.DebounceMS)*time.Millisecond, current)
+}
+
+// holdsLocked reports whether current still satisfies the condition a
+// pending fire is waiting on.
+func (e *RuleEngine) holdsLocked(trigger RuleTrigger, st *ruleState, current any) bool {
+	if trigger.Type == RuleTriggerCrosses && trigger.Direction == CrossEither {
+		trigger.Direction = st.crossed
+	}
+	return triggerHolds(trigger, current)
+}
+
+// armLocked starts the pending-fire timer for a rule.
+// Caller must hold e.mu.
+func (e *RuleEngine) armLocked(rule *Rule, st *ruleState, delay time.Duration, value any) {
+	st.gen++
+	gen := st.gen
+	id := rule.ID
+	value = deepCopyValue(value)
+	st.timer = e.afterFunc(delay, func() {
+		e.onTimer(id, gen, value)
+	})
+}
+
+// onTimer fires a rule whose debounce or "stays" window elapsed without
+// the condition breaking.
+func (e *RuleEngine) onTimer(id string, gen uint64, value any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	st, ok := e.states[id]
+	if !ok || st.gen != gen || e.stopped {
+		return
+	}
+	st.timer = nil
+
+	rule := e.rules[id]
+	if rule == nil || !rule.Enabled {
+		return
+	}
+	if rule.Trigger.Type == RuleTriggerStays {
+		st.latched = true
+	}
+	e.fireLocked(rule, st, value)
+}
+
+// fireLocked applies the cooldown and starts execution of the rule.
+// Caller must hold e.mu.
+func (e *RuleEngine) fireLocked(rule *Rule, st *ruleState, value any) {
+	now := e.now()
+	cooldown := time.Duration(rule.CooldownSeconds) * time.Second
+	if cooldown > 0 && !st.lastFired.IsZero() && now.Sub(st.lastFired) < cooldown {
+		e.logger.Debug("rule fire suppressed by cooldown",
+			"rule_id", rule.ID,
+			"remaining", (cooldown - now.Sub(st.lastFired)).Round(time.Second).String(),
+		)
+		return
+	}
+
+	previous := st.lastFired
+	st.lastFired = now
+	t := now
+	rule.LastFiredAt = &t
+
+	e.wg.Add(1)
+	go e.execute(e.ctx, rule.DeepCopy(), now, previous, deepCopyValue(value))
+}
+
+// execute checks the rule's conditions, runs its action, records the fire,
+// and publishes the result. A fire whose conditions do not pass gives the
+// cooldown back (previous is the last fire before this one).
+func (e *RuleEngine) execute(ctx context.Context, rule *Rule, firedAt, previous time.Time, value any) {
+	defer e.wg.Done()
+	defer func() {
+		if r := recover(); r != nil {
+			e.logger.Error("panic in rule execution", "rule_id", rule.ID, "panic", r)
+		}
+	}()
+
+	e.mu.Lock()
+	conditions, overrides := e.conditions, e.overrides
+	e.mu.Unlock()
+
+	result := map[string]any{
+		"rule_id":   rule.ID,
+		"rule_name": rule.Name,
+		"fired_at":  firedAt.UTC(),
+		"trigger": map[string]any{
+			"type":      string(rule.Trigger.Type),
+			"device_id": rule.Trigger.DeviceID,
+			"key":       rule.Trigger.Key,
+			"value":     value,
+		},
+	}
+
+	if err := conditions.Check(ctx, rule.Conditions); err != nil {
+		e.restoreLastFired(rule.ID, firedAt, previous)
+		result["status"] = ruleStatusConditionsNotMet
+		result["reason"] = err.Error()
+		e.logger.Info("automation rule conditions not met", "rule_id", rule.ID, "reason", err.Error())
+		e.publishResult(rule.ID, result)
+		return
+	}
+
+	if err := e.repo.UpdateRuleLastFired(ctx, rule.ID, firedAt); err != nil {
+		e.logger.Error("failed to record rule fire", "rule_id", rule.ID, "error", err)
+	}
+
+	source := "rule:" + rule.ID
+	executed := map[string]any{"type": string(rule.Execute.Type)}
+	result["executed"] = executed
+
+	var err error
+	switch rule.Execute.Type {
+	case RuleExecuteScene:
+		executed["scene_id"] = rule.Execute.SceneID
+		var executionID string
+		executionID, err = e.actions.ActivateScene(ctx, rule.Execute.SceneID, "automation", source)
+		if err == nil {
+			result["execution_id"] = executionID
+		}
+	case RuleExecuteCommand:
+		executed["device_id"] = rule.Execute.DeviceID
+		executed["command"] = rule.Execute.Command
+		if overrides != nil && overrides.Blocks(rule.Execute.DeviceID, defaultPriority) {
+			err = fmt.Errorf("%w: %s", ErrDeviceOverridden, rule.Execute.DeviceID)
+			break
+		}
+		var commandID string
+		commandID, err = e.actions.SendCommand(ctx, rule.Execute.DeviceID, rule.Execute.Command, rule.Execute.Parameters, source)
+		if err == nil {
+			result["command_id"] = commandID
+		}
+	case RuleExecuteMode:
+		executed["mode_id"] = rule.Execute.ModeID
+		e.mu.Lock()
+		modes := e.modes
+		e.mu.Unlock()
+		if modes == nil {
+			err = ErrModesUnavailable
+			break
+		}
+		_, err = modes.SetMode(ctx, rule.Execute.ModeID, source, "")
+	default:
+		err = fmt.Errorf("%w: unknown execute type %q", ErrInvalidRule, rule.Execute.Type)
+	}
+
+	switch {
+	case errors.Is(err, ErrConditionsNotMet):
+		// The target scene's own conditions stopped it.
+		result["status"] = ruleStatusConditionsNotMet
+		result["reason"] = err.Error()
+		e.logger.Info("automation rule scene conditions not met", "rule_id", rule.ID, "reason", err.Error())
+	case errors.Is(err, ErrDeviceOverridden):
+		result["status"] = ruleStatusOverridden
+		result["reason"] = err.Error()
+		e.logger.Info("automation rule skipped: device under manual override", "rule_id", rule.ID, "device_id", rule.Execute.DeviceID)
+	case err != nil:
+		result["status"] = ruleStatusFailed
+		result["error"] = err.Error()
+		e.logger.Error("automation rule failed", "rule_id", rule.ID, "error", err)
+	default:
+		result["status"] = ruleStatusOK
+		e.logger.Info("automation rule fired",
+			"rule_id", rule.ID,
+			"device_id", rule.Trigger.DeviceID,
+			"key", rule.Trigger.Key,
+			"execute", string(rule.Execute.Type),
+		)
+	}
+
+	e.publishResult(rule.ID, result)
+}
+
+// restoreLastFired undoes the cooldown start of a fire that did nothing,
+// unless the rule has fired again or been reloaded since.
+func (e *RuleEngine) restoreLastFired(ruleID string, firedAt, previous time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if st, ok := e.states[ruleID]; ok && st.lastFired.Equal(firedAt) {
+		st.lastFired = previous
+	}
+	if rule, ok := e.rules[ruleID]; ok && rule.LastFiredAt != nil && rule.LastFiredAt.Equal(firedAt) {
+		if previous.IsZero() {
+			rule.LastFiredAt = nil
+		} else {
+			t := previous
+			rule.LastFiredAt = &t
+		}
+	}
+}
+
+// publishResult sends a rule result to MQTT and WebSocket subscribers.
+func (e *RuleEngine) publishResult(ruleID string, result map[string]any) {
+	if e.mqtt != nil {
+		payload, err := json.Marshal(result)
+		if err != nil {
+			e.logger.Error("failed to marshal rule result", "rule_id", ruleID, "error", err)
+		} else if pubErr := e.mqtt.Publish(mqtt.Topics{}.CoreAutomationFired(ruleID), payload, 1, false); pubErr != nil {
+			e.logger.Warn("failed to publish rule result", "rule_id", ruleID, "error", pubErr)
+		}
+	}
+
+	if e.hub != nil {
+		e.hub.Broadcast("automation.fired", result)
+	}
+}
+
+// reindexLocked rebuilds the device -> rules index and drops remembered
+// values for devices no rule watches any more. Caller must hold e.mu.
+func (e *RuleEngine) reindexLocked() {
+	index := make(map[string][]string, len(e.rules))
+	for id, rule := range e.rules {
+		index[rule.Trigger.DeviceID] = append(index[rule.Trigger.DeviceID], id)
+	}
+	for _, ids := range index {
+		sort.Strings(ids)
+	}
+	for deviceID := range e.values {
+		if _, ok := index[deviceID]; !ok {
+			delete(e.values, deviceID)
+		}
+	}
+	e.byDevice = index
+}
+
+// seedValues fills in the remembered values of watched devices that have
+// none from the device state reader, if set. Values reported meanwhile by
+// HandleStateChange are kept.
+func (e *RuleEngine) seedValues(ctx context.Context) {
+	e.mu.Lock()
+	devices := e.devices
+	var missing []string
+	if devices != nil {
+		for deviceID := range e.byDevice {
+			if _, ok := e.values[deviceID]; !ok {
+				missing = append(missing, deviceID)
+			}
+		}
+	}
+	e.mu.Unlock()
+
+	for _, deviceID := range missing {
+		state, err := devices.GetDeviceState(ctx, deviceID)
+		if err != nil {
+			e.logger.Debug("rule trigger device state unavailable", "device_id", deviceID, "error", err)
+			continue
+		}
+		if len(state) == 0 {
+			continue
+		}
+
+		e.mu.Lock()
+		if _, watched := e.byDevice[deviceID]; watched && e.values[deviceID] == nil {
+			values := make(map[string]any, len(state))
+			for k, v := range state {
+				values[k] = deepCopyValue(v)
+			}
+			e.values[deviceID] = values
+		}
+		e.mu.Unlock()
+	}
+}
+
+func newRuleState(rule *Rule) *ruleState {
+	st := &ruleState{}
+	if rule.LastFiredAt != nil {
+		st.lastFired = *rule.LastFiredAt
+	}
+	return st
+}
+
+// cancelPending stops a rule's pending fire, if any.
+func cancelPending(st *ruleState) {
+	st.gen++
+	if st.timer != nil {
+		st.timer.Stop()
+		st.timer = nil
+	}
+}
@@ -0,0 +1,430 @@
+package automation
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockRuleRepo is an in-memory RuleRepository.
+type mockRuleRepo struct {
+	mu    sync.Mutex
+	rules map[string]*Rule
+}
+
+func newMockRuleRepo() *mockRuleRepo {
+	return &mockRuleRepo{rules: make(map[string]*Rule)}
+}
+
+func (m *mockRuleRepo) GetRule(_ context.Context, id string) (*Rule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.rules[id]
+	if !ok {
+		return nil, ErrRuleNotFound
+	}
+	return r.DeepCopy(), nil
+}
+
+func (m *mockRuleRepo) ListRules(_ context.Context) ([]Rule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Rule, 0, len(m.rules))
+	for _, r := range m.rules {
+		out = append(out, *r.DeepCopy())
+	}
+	return out, nil
+}
+
+func (m *mockRuleRepo) CreateRule(_ context.Context, r *Rule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.rules[r.ID]; ok {
+		return ErrRuleExists
+	}
+	m.rules[r.ID] = r.DeepCopy()
+	return nil
+}
+
+func (m *mockRuleRepo) UpdateRule(_ context.Context, r *Rule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.rules[r.ID]; !ok {
+		return ErrRuleNotFound
+	}
+	m.rules[r.ID] = r.DeepCopy()
+	return nil
+}
+
+func (m *mockRuleRepo) DeleteRule(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.rules[id]; !ok {
+		return ErrRuleNotFound
+	}
+	delete(m.rules, id)
+	return nil
+}
+
+func (m *mockRuleRepo) UpdateRuleLastFired(_ context.Context, id string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.rules[id]
+	if !ok {
+		return ErrRuleNotFound
+	}
+	r.LastFiredAt = &at
+	return nil
+}
+
+type sentCommand struct {
+	deviceID, command, source string
+}
+
+// mockRuleActions records scene activations and direct commands.
+type mockRuleActions struct {
+	mockActivator
+	cmdMu    sync.Mutex
+	commands []sentCommand
+}
+
+func (m *mockRuleActions) SendCommand(_ context.Context, deviceID, command string, _ map[string]any, source string) (string, error) {
+	m.cmdMu.Lock()
+	defer m.cmdMu.Unlock()
+	m.commands = append(m.commands, sentCommand{deviceID, command, source})
+	return "cmd-1", nil
+}
+
+func (m *mockRuleActions) getCommands() []sentCommand {
+	m.cmdMu.Lock()
+	defer m.cmdMu.Unlock()
+	return append([]sentCommand(nil), m.commands...)
+}
+
+// setupRuleEngine creates a rule engine whose timers run 1000x faster
+// than configured, so a 1s "stays" window takes 1ms.
+func setupRuleEngine(t *testing.T, rules ...*Rule) (*RuleEngine, *mockRuleActions, *mockMQTT, *testClock) {
+	t.Helper()
+	repo := newMockRuleRepo()
+	for _, r := range rules {
+		applyRuleDefaults(r)
+		repo.rules[r.ID] = r
+	}
+	actions := &mockRuleActions{}
+	mqttClient := newMockMQTT()
+	clock := &testClock{now: time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)}
+
+	e := NewRuleEngine(repo, actions, mqttClient, newMockWSHub(), nil)
+	e.now = clock.Now
+	e.afterFunc = func(d time.Duration, f func()) *time.Timer {
+		return time.AfterFunc(d/1000, f)
+	}
+	if err := e.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(e.Stop)
+	return e, actions, mqttClient, clock
+}
+
+// settle waits for timers and in-flight executions to finish.
+func settle(e *RuleEngine) {
+	time.Sleep(50 * time.Millisecond)
+	e.wg.Wait()
+}
+
+func TestRuleEngine_ChangesTo(t *testing.T) {
+	e, actions, mqttClient, _ := setupRuleEngine(t, &Rule{
+		ID: "rule-1", Name: "Door", Enabled: true,
+		Trigger: RuleTrigger{Type: RuleTriggerChangesTo, DeviceID: "door-1", Key: "contact", Value: "open"},
+		Execute: RuleExecute{Type: RuleExecuteScene, SceneID: "scene-1"},
+	})
+
+	e.HandleStateChange("door-1", map[string]any{"contact": "open"}) // Unknown previous: no fire
+	e.HandleStateChange("door-1", map[string]any{"contact": "closed"})
+	e.HandleStateChange("door-1", map[string]any{"contact": "open"})
+	e.HandleStateChange("door-1", map[string]any{"contact": "open"}) // Repeat: no fire
+	settle(e)
+
+	calls := actions.getCalls()
+	if len(calls) != 1 {
+		t.Fatalf("activations = %d, want 1", len(calls))
+	}
+	if calls[0].sceneID != "scene-1" || calls[0].triggerType != "automation" || calls[0].triggerSource != "rule:rule-1" {
+		t.Errorf("activation = %+v", calls[0])
+	}
+
+	msgs := mqttClient.getMessages()
+	if len(msgs) != 1 || msgs[0].Topic != "graylogic/core/automation/rule-1/fired" {
+		t.Fatalf("published = %+v", msgs)
+	}
+	if msgs[0].Payload["status"] != ruleStatusOK || msgs[0].Payload["execution_id"] == nil {
+		t.Errorf("payload = %v", msgs[0].Payload)
+	}
+}
+
+func TestRuleEngine_SeedsWatchedValues(t *testing.T) {
+	e, actions, _, _ := setupRuleEngine(t)
+	e.SetDeviceStateReader(mockStateReader{
+		"door-1":   {"contact": "closed"},
+		"sensor-1": {"temperature": 22.0},
+	})
+	ctx := context.Background()
+
+	// A rule loaded from the repository, and one created afterwards
+	door := &Rule{
+		ID: "rule-1", Name: "Door", Enabled: true,
+		Trigger: RuleTrigger{Type: RuleTriggerChangesTo, DeviceID: "door-1", Key: "contact", Value: "open"},
+		Execute: RuleExecute{Type: RuleExecuteScene, SceneID: "scene-1"},
+	}
+	applyRuleDefaults(door)
+	if err := e.repo.CreateRule(ctx, door); err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+	if err := e.RefreshCache(ctx); err != nil {
+		t.Fatalf("RefreshCache: %v", err)
+	}
+	if err := e.CreateRule(ctx, &Rule{
+		ID: "rule-2", Name: "Too warm", Enabled: true,
+		Trigger: RuleTrigger{Type: RuleTriggerCrosses, DeviceID: "sensor-1", Key: "temperature", Threshold: ptrFloat(24), Direction: CrossRising},
+		Execute: RuleExecute{Type: RuleExecuteCommand, DeviceID: "blind-1", Command: "close"},
+	}); err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+
+	// The first edge after loading the rules fires
+	e.HandleStateChange("door-1", map[string]any{"contact": "open"})
+	e.HandleStateChange("sensor-1", map[string]any{"temperature": 25.0})
+	settle(e)
+
+	if calls := actions.getCalls(); len(calls) != 1 {
+		t.Errorf("activations = %d, want 1", len(calls))
+	}
+	if cmds := actions.getCommands(); len(cmds) != 1 {
+		t.Errorf("commands = %d, want 1", len(cmds))
+	}
+}
+
+func TestRuleEngine_CrossesWithCommand(t *testing.T) {
+	e, actions, _, _ := setupRuleEngine(t, &Rule{
+		ID: "rule-1", Name: "Too warm", Enabled: true,
+		Trigger: RuleTrigger{Type: RuleTriggerCrosses, DeviceID: "sensor-1", Key: "temperature", Threshold: ptrFloat(24), Direction: CrossRising},
+		Execute: RuleExecute{Type: RuleExecuteCommand, DeviceID: "blind-1", Command: "close"},
+	})
+
+	e.HandleStateChange("sensor-1", map[string]any{"temperature": 22.0})
+	e.HandleStateChange("sensor-1", map[string]any{"temperature": 25.0})
+	e.HandleStateChange("sensor-1", map[string]any{"temperature": 26.0})
+	e.HandleStateChange("sensor-1", map[string]any{"temperature": 20.0}) // Falling: ignored
+	settle(e)
+
+	cmds := actions.getCommands()
+	if len(cmds) != 1 || cmds[0].deviceID != "blind-1" || cmds[0].command != "close" || cmds[0].source != "rule:rule-1" {
+		t.Errorf("commands = %+v", cmds)
+	}
+}
+
+func TestRuleEngine_CommandOverridden(t *testing.T) {
+	e, actions, mqttClient, _ := setupRuleEngine(t, &Rule{
+		ID: "rule-1", Name: "Close blind", Enabled: true,
+		Trigger: RuleTrigger{Type: RuleTriggerChangesTo, DeviceID: "sensor-1", Key: "sun", Value: true},
+		Execute: RuleExecute{Type: RuleExecuteCommand, DeviceID: "blind-1", Command: "close"},
+	})
+	overrides := NewOverrideManager(OverrideConfig{BypassPriority: 80}, nil, nil, nil)
+	overrides.SetControllers(e)
+	e.SetOverrideManager(overrides)
+
+	if !overrides.Latch(context.Background(), "blind-1", "physical") {
+		t.Fatal("rule target not reported as automation-controlled")
+	}
+	e.HandleStateChange("sensor-1", map[string]any{"sun": false})
+	e.HandleStateChange("sensor-1", map[string]any{"sun": true})
+	settle(e)
+
+	if cmds := actions.getCommands(); len(cmds) != 0 {
+		t.Errorf("commands = %+v, want none", cmds)
+	}
+	msgs := mqttClient.getMessages()
+	if len(msgs) != 1 || msgs[0].Payload["status"] != ruleStatusOverridden {
+		t.Errorf("published = %+v, want one overridden result", msgs)
+	}
+}
+
+func TestRuleEngine_DebounceCancelledWhenConditionBreaks(t *testing.T) {
+	e, actions, _, _ := setupRuleEngine(t, &Rule{
+		ID: "rule-1", Name: "Motion", Enabled: true, DebounceMS: 20_000, // 20ms after scaling
+		Trigger: RuleTrigger{Type: RuleTriggerChangesTo, DeviceID: "pir-1", Key: "motion", Value: true},
+		Execute: RuleExecute{Type: RuleExecuteScene, SceneID: "scene-1"},
+	})
+
+	e.HandleStateChange("pir-1", map[string]any{"motion": false})
+	e.HandleStateChange("pir-1", map[string]any{"motion": true})
+	e.HandleStateChange("pir-1", map[string]any{"motion": false})
+	settle(e)
+	if n := len(actions.getCalls()); n != 0 {
+		t.Fatalf("activations after bounce = %d, want 0", n)
+	}
+
+	e.HandleStateChange("pir-1", map[string]any{"motion": true})
+	settle(e)
+	if n := len(actions.getCalls()); n != 1 {
+		t.Errorf("activations after settled change = %d, want 1", n)
+	}
+}
+
+func TestRuleEngine_Stays(t *testing.T) {
+	e, actions, _, _ := setupRuleEngine(t, &Rule{
+		ID: "rule-1", Name: "Window left open", Enabled: true,
+		Trigger: RuleTrigger{Type: RuleTriggerStays, DeviceID: "window-1", Key: "contact", Value: "open", ForSeconds: 20},
+		Execute: RuleExecute{Type: RuleExecuteScene, SceneID: "scene-1"},
+	})
+
+	// Interrupted before the window elapses.
+	e.HandleStateChange("window-1", map[string]any{"contact": "open"})
+	e.HandleStateChange("window-1", map[string]any{"contact": "closed"})
+	settle(e)
+	if n := len(actions.getCalls()); n != 0 {
+		t.Fatalf("activations after interruption = %d, want 0", n)
+	}
+
+	// Held: fires once, and repeated reports do not fire again.
+	e.HandleStateChange("window-1", map[string]any{"contact": "open"})
+	settle(e)
+	e.HandleStateChange("window-1", map[string]any{"contact": "open"})
+	settle(e)
+	if n := len(actions.getCalls()); n != 1 {
+		t.Fatalf("activations while held = %d, want 1", n)
+	}
+
+	// Re-arms after the value changes away.
+	e.HandleStateChange("window-1", map[string]any{"contact": "closed"})
+	e.HandleStateChange("window-1", map[string]any{"contact": "open"})
+	settle(e)
+	if n := len(actions.getCalls()); n != 2 {
+		t.Errorf("activations after re-arm = %d, want 2", n)
+	}
+}
+
+func TestRuleEngine_Cooldown(t *testing.T) {
+	e, actions, _, clock := setupRuleEngine(t, &Rule{
+		ID: "rule-1", Name: "Doorbell", Enabled: true, CooldownSeconds: 60,
+		Trigger: RuleTrigger{Type: RuleTriggerChangesTo, DeviceID: "bell-1", Key: "pressed", Value: true},
+		Execute: RuleExecute{Type: RuleExecuteScene, SceneID: "scene-1"},
+	})
+
+	press := func() {
+		e.HandleStateChange("bell-1", map[string]any{"pressed": false})
+		e.HandleStateChange("bell-1", map[string]any{"pressed": true})
+		settle(e)
+	}
+
+	press()
+	clock.Set(clock.Now().Add(30 * time.Second))
+	press()
+	if n := len(actions.getCalls()); n != 1 {
+		t.Fatalf("activations within cooldown = %d, want 1", n)
+	}
+
+	clock.Set(clock.Now().Add(31 * time.Second))
+	press()
+	if n := len(actions.getCalls()); n != 2 {
+		t.Errorf("activations after cooldown = %d, want 2", n)
+	}
+}
+
+func TestRuleEngine_ConditionsNotMet(t *testing.T) {
+	e, actions, mqttClient, _ := setupRuleEngine(t, &Rule{
+		ID: "rule-1", Name: "Landing light", Enabled: true, CooldownSeconds: 60,
+		Trigger:    RuleTrigger{Type: RuleTriggerChangesTo, DeviceID: "pir-1", Key: "motion", Value: true},
+		Execute:    RuleExecute{Type: RuleExecuteScene, SceneID: "scene-1"},
+		Conditions: []Condition{{Type: ConditionMode, Modes: []string{"night"}}},
+	})
+	evaluator := NewConditionEvaluator(nil, nil)
+	evaluator.SetModeReader(staticMode("home"))
+	e.SetConditionEvaluator(evaluator)
+
+	motion := func() {
+		e.HandleStateChange("pir-1", map[string]any{"motion": false})
+		e.HandleStateChange("pir-1", map[string]any{"motion": true})
+		settle(e)
+	}
+
+	motion()
+	if len(actions.getCalls()) != 0 {
+		t.Fatal("rule ran although conditions were not met")
+	}
+	msgs := mqttClient.getMessages()
+	if len(msgs) != 1 || msgs[0].Payload["status"] != ruleStatusConditionsNotMet {
+		t.Fatalf("published = %+v, want one conditions_not_met result", msgs)
+	}
+	if rule, _ := e.GetRule("rule-1"); rule.LastFiredAt != nil {
+		t.Errorf("LastFiredAt = %v, want nil", rule.LastFiredAt)
+	}
+
+	// The skipped fire did not start the cooldown.
+	evaluator.SetModeReader(staticMode("night"))
+	motion()
+	if n := len(actions.getCalls()); n != 1 {
+		t.Errorf("activations = %d, want 1", n)
+	}
+}
+
+func TestRuleEngine_DisabledAndDeleted(t *testing.T) {
+	e, actions, _, _ := setupRuleEngine(t, &Rule{
+		ID: "rule-1", Name: "Door", Enabled: true,
+		Trigger: RuleTrigger{Type: RuleTriggerChangesTo, DeviceID: "door-1", Key: "contact", Value: "open"},
+		Execute: RuleExecute{Type: RuleExecuteScene, SceneID: "scene-1"},
+	})
+	ctx := context.Background()
+
+	if _, err := e.SetEnabled(ctx, "rule-1", false); err != nil {
+		t.Fatalf("SetEnabled: %v", err)
+	}
+	e.HandleStateChange("door-1", map[string]any{"contact": "closed"})
+	e.HandleStateChange("door-1", map[string]any{"contact": "open"})
+	settle(e)
+	if n := len(actions.getCalls()); n != 0 {
+		t.Errorf("disabled rule fired %d times", n)
+	}
+
+	if err := e.DeleteRule(ctx, "rule-1"); err != nil {
+		t.Fatalf("DeleteRule: %v", err)
+	}
+	if _, err := e.GetRule("rule-1"); err != ErrRuleNotFound {
+		t.Errorf("GetRule after delete error = %v, want ErrRuleNotFound", err)
+	}
+}
+
+func TestRuleEngine_ModeTarget(t *testing.T) {
+	e, actions, mqttClient, _ := setupRuleEngine(t, &Rule{
+		ID: "rule-1", Name: "Leaving button", Enabled: true,
+		Trigger: RuleTrigger{Type: RuleTriggerChangesTo, DeviceID: "button-1", Key: "on", Value: true},
+		Execute: RuleExecute{Type: RuleExecuteMode, ModeID: "away"},
+	})
+
+	// Without a mode manager the rule fails.
+	e.HandleStateChange("button-1", map[string]any{"on": false})
+	e.HandleStateChange("button-1", map[string]any{"on": true})
+	settle(e)
+	msgs := mqttClient.getMessages()
+	if len(msgs) != 1 || msgs[0].Payload["status"] != ruleStatusFailed {
+		t.Fatalf("published = %+v, want one failed result", msgs)
+	}
+
+	modes := &mockModeSetter{}
+	e.SetModeSetter(modes)
+	e.HandleStateChange("button-1", map[string]any{"on": false})
+	e.HandleStateChange("button-1", map[string]any{"on": true})
+	settle(e)
+
+	if calls := modes.getCalls(); len(calls) != 1 || calls[0] != "away rule:rule-1" {
+		t.Errorf("SetMode calls = %v", calls)
+	}
+	if len(actions.getCalls()) != 0 {
+		t.Error("mode rule activated a scene")
+	}
+	msgs = mqttClient.getMessages()
+	if last := msgs[len(msgs)-1]; last.Payload["status"] != ruleStatusOK {
+		t.Errorf("payload = %v", last.Payload)
+	}
+}
@@ -0,0 +1,276 @@
+package automation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RuleRepository defines the interface for automation rule persistence.
+type RuleRepository interface {
+	GetRule(ctx context.Context, id string) (*Rule, error)
+	ListRules(ctx context.Context) ([]Rule, error)
+	CreateRule(ctx context.Context, rule *Rule) error
+	UpdateRule(ctx context.Context, rule *Rule) error
+	DeleteRule(ctx context.Context, id string) error
+
+	// UpdateRuleLastFired records when a rule last fired, without touching
+	// updated_at (which tracks configuration changes).
+	UpdateRuleLastFired(ctx context.Context, id string, at time.Time) error
+}
+
+// ruleColumns is the SELECT column list for rule queries.
+const ruleColumns = `id, name, slug, description, enabled, trigger_config,
+			execute_config, conditions, debounce_ms, cooldown_seconds, last_fired_at,
+			created_at, updated_at`
+
+// SQLiteRuleRepository implements RuleRepository using SQLite.
+type SQLiteRuleRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRuleRepository creates a new SQLite-backed rule repository.
+func NewSQLiteRuleRepository(db *sql.DB) *SQLiteRuleRepository {
+	return &SQLiteRuleRepository{db: db}
+}
+
+// GetRule retrieves a rule by its unique identifier.
+func (r *SQLiteRuleRepository) GetRule(ctx context.Context, id string) (*Rule, error) {
+	query := `SELECT ` + ruleColumns + ` FROM automation_rules WHERE id = ?`
+
+	rule, err := scanRuleRow(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRuleNotFound
+		}
+		return nil, fmt.Errorf("querying rule by id: %w", err)
+	}
+	return rule, nil
+}
+
+// ListRules retrieves all rules ordered by name.
+func (r *SQLiteRuleRepository) ListRules(ctx context.Context) ([]Rule, error) {
+	query := `SELECT ` + ruleColumns + ` FROM automation_rules ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []Rule
+	for rows.Next() {
+		rule, scanErr := scanRuleRow(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("scanning rule: %w", scanErr)
+		}
+		rules = append(rules, *rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating rules: %w", err)
+	}
+	return rules, nil
+}
+
+// CreateRule inserts a new rule.
+func (r *SQLiteRuleRepository) CreateRule(ctx context.Context, rule *Rule) error {
+	triggerJSON, executeJSON, err := marshalRuleConfig(rule)
+	if err != nil {
+		return err
+	}
+	conditionsJSON, err := marshalConditions(rule.Conditions)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = now
+	}
+	rule.UpdatedAt = now
+
+	query := `
+		INSERT INTO automation_rules (
+			id, name, slug, description, enabled, trigger_config,
+			execute_config, conditions, debounce_ms, cooldown_seconds, last_fired_at,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		rule.ID,
+		rule.Name,
+		rule.Slug,
+		nullableString(rule.Description),
+		boolToInt(rule.Enabled),
+		triggerJSON,
+		executeJSON,
+		conditionsJSON,
+		rule.DebounceMS,
+		rule.CooldownSeconds,
+		nullableTime(rule.LastFiredAt),
+		rule.CreatedAt.Format(time.RFC3339),
+		rule.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrRuleExists
+		}
+		return fmt.Errorf("inserting rule: %w", err)
+	}
+	return nil
+}
+
+// UpdateRule modifies an existing rule's configuration.
+func (r *SQLiteRuleRepository) UpdateRule(ctx context.Context, rule *Rule) error {
+	triggerJSON, executeJSON, err := marshalRuleConfig(rule)
+	if err != nil {
+		return err
+	}
+	conditionsJSON, err := marshalConditions(rule.Conditions)
+	if err != nil {
+		return err
+	}
+
+	rule.UpdatedAt = time.Now().UTC()
+
+	query := `
+		UPDATE automation_rules SET
+			name = ?, slug = ?, description = ?, enabled = ?, trigger_config = ?,
+			execute_config = ?, conditions = ?, debounce_ms = ?, cooldown_seconds = ?,
+			updated_at = ?
+		WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query,
+		rule.Name,
+		rule.Slug,
+		nullableString(rule.Description),
+		boolToInt(rule.Enabled),
+		triggerJSON,
+		executeJSON,
+		conditionsJSON,
+		rule.DebounceMS,
+		rule.CooldownSeconds,
+		rule.UpdatedAt.Format(time.RFC3339),
+		rule.ID,
+	)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrRuleExists
+		}
+		return fmt.Errorf("updating rule: %w", err)
+	}
+	return checkRuleRowsAffected(result)
+}
+
+// DeleteRule removes a rule by ID.
+func (r *SQLiteRuleRepository) DeleteRule(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM automation_rules WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("deleting rule: %w", err)
+	}
+	return checkRuleRowsAffected(result)
+}
+
+// UpdateRuleLastFired records when a rule last fired.
+func (r *SQLiteRuleRepository) UpdateRuleLastFired(ctx context.Context, id string, at time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE automation_rules SET last_fired_at = ? WHERE id = ?",
+		at.UTC().Format(time.RFC3339), id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating rule last fired: %w", err)
+	}
+	return checkRuleRowsAffected(result)
+}
+
+func checkRuleRowsAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+func marshalRuleConfig(rule *Rule) (triggerJSON, executeJSON string, err error) {
+	t, err := json.Marshal(rule.Trigger)
+	if err != nil {
+		return "", "", fmt.Errorf("marshalling trigger: %w", err)
+	}
+	e, err := json.Marshal(rule.Execute)
+	if err != nil {
+		return "", "", fmt.Errorf("marshalling execute: %w", err)
+	}
+	return string(t), string(e), nil
+}
+
+func scanRuleRow(scanner rowScanner) (*Rule, error) {
+	var r Rule
+	var description, conditionsJSON, lastFiredAt sql.NullString
+	var triggerJSON, executeJSON string
+	var enabled int
+	var createdAt, updatedAt string
+
+	err := scanner.Scan(
+		&r.ID,
+		&r.Name,
+		&r.Slug,
+		&description,
+		&enabled,
+		&triggerJSON,
+		&executeJSON,
+		&conditionsJSON,
+		&r.DebounceMS,
+		&r.CooldownSeconds,
+		&lastFiredAt,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if description.Valid {
+		r.Description = &description.String
+	}
+	r.Enabled = enabled != 0
+
+	if jsonErr := json.Unmarshal([]byte(triggerJSON), &r.Trigger); jsonErr != nil {
+		return nil, fmt.Errorf("unmarshalling trigger: %w", jsonErr)
+	}
+	if jsonErr := json.Unmarshal([]byte(executeJSON), &r.Execute); jsonErr != nil {
+		return nil, fmt.Errorf("unmarshalling execute: %w", jsonErr)
+	}
+	if conditionsJSON.Valid {
+		conditions, condErr := unmarshalConditions(conditionsJSON.String)
+		if condErr != nil {
+			return nil, condErr
+		}
+		r.Conditions = conditions
+	}
+
+	if lastFiredAt.Valid {
+		t, parseErr := time.Parse(time.RFC3339, lastFiredAt.String)
+		if parseErr != nil {
+			return nil, fmt.Errorf("rule %s last_fired_at: %w", r.ID, parseErr)
+		}
+		r.LastFiredAt = &t
+	}
+	if t, parseErr := time.Parse(time.RFC3339, createdAt); parseErr == nil {
+		r.CreatedAt = t
+	} else {
+		return nil, fmt.Errorf("rule %s created_at: %w", r.ID, parseErr)
+	}
+	if t, parseErr := time.Parse(time.RFC3339, updatedAt); parseErr == nil {
+		r.UpdatedAt = t
+	} else {
+		return nil, fmt.Errorf("rule %s updated_at: %w", r.ID, parseErr)
+	}
+
+	return &r, nil
+}
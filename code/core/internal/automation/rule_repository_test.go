@@ -0,0 +1,104 @@
+package automation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+// setupRuleTestDB creates an in-memory SQLite database with the automation_rules table.
+func setupRuleTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := setupTestDB(t)
+
+	schema := `
+		CREATE TABLE automation_rules (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			slug TEXT NOT NULL UNIQUE,
+			description TEXT,
+			enabled INTEGER NOT NULL DEFAULT 1,
+			trigger_config TEXT NOT NULL,
+			execute_config TEXT NOT NULL,
+			conditions TEXT,
+			debounce_ms INTEGER NOT NULL DEFAULT 0,
+			cooldown_seconds INTEGER NOT NULL DEFAULT 0,
+			last_fired_at TEXT,
+			created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+			updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+		) STRICT;`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("creating automation_rules schema: %v", err)
+	}
+	return db
+}
+
+func TestRuleRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSQLiteRuleRepository(setupRuleTestDB(t))
+
+	rule := &Rule{
+		ID:      "rule-1",
+		Name:    "Too warm",
+		Slug:    "too-warm",
+		Enabled: true,
+		Trigger: RuleTrigger{
+			Type: RuleTriggerCrosses, DeviceID: "sensor-1", Key: "temperature",
+			Threshold: ptrFloat(24.5), Direction: CrossRising,
+		},
+		Execute: RuleExecute{
+			Type: RuleExecuteCommand, DeviceID: "blind-1", Command: "set_position",
+			Parameters: map[string]any{"position": 20},
+		},
+		Conditions:      []Condition{{Type: ConditionTimeWindow, After: "09:00", Before: "18:00"}},
+		DebounceMS:      500,
+		CooldownSeconds: 600,
+	}
+	if err := repo.CreateRule(ctx, rule); err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+	if err := repo.CreateRule(ctx, rule); !errors.Is(err, ErrRuleExists) {
+		t.Errorf("duplicate CreateRule error = %v, want ErrRuleExists", err)
+	}
+
+	got, err := repo.GetRule(ctx, "rule-1")
+	if err != nil {
+		t.Fatalf("GetRule: %v", err)
+	}
+	if got.Trigger.Threshold == nil || *got.Trigger.Threshold != 24.5 || got.Trigger.Direction != CrossRising ||
+		got.Execute.Command != "set_position" || got.Execute.Parameters["position"] != float64(20) ||
+		len(got.Conditions) != 1 || got.Conditions[0].After != "09:00" ||
+		got.DebounceMS != 500 || got.CooldownSeconds != 600 || got.LastFiredAt != nil {
+		t.Errorf("round-trip mismatch: %+v", got)
+	}
+
+	fired := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	if err := repo.UpdateRuleLastFired(ctx, "rule-1", fired); err != nil {
+		t.Fatalf("UpdateRuleLastFired: %v", err)
+	}
+	got, _ = repo.GetRule(ctx, "rule-1")
+	if got.LastFiredAt == nil || !got.LastFiredAt.Equal(fired) {
+		t.Errorf("LastFiredAt = %v, want %v", got.LastFiredAt, fired)
+	}
+
+	got.Enabled = false
+	if err := repo.UpdateRule(ctx, got); err != nil {
+		t.Fatalf("UpdateRule: %v", err)
+	}
+	list, err := repo.ListRules(ctx)
+	if err != nil || len(list) != 1 || list[0].Enabled {
+		t.Fatalf("ListRules() = %+v, %v", list, err)
+	}
+
+	if err := repo.DeleteRule(ctx, "rule-1"); err != nil {
+		t.Fatalf("DeleteRule: %v", err)
+	}
+	if _, err := repo.GetRule(ctx, "rule-1"); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("GetRule after delete error = %v, want ErrRuleNotFound", err)
+	}
+	if err := repo.DeleteRule(ctx, "rule-1"); !errors.Is(err, ErrRuleNotFound) {
+		t.Errorf("DeleteRule unknown error = %v, want ErrRuleNotFound", err)
+	}
+}
@@ -0,0 +1,108 @@
+package automation
+
+import (
+	"errors"
+	"testing"
+)
+
+func ptrFloat(v float64) *float64 { return &v }
+
+func TestValidateRule(t *testing.T) {
+	valid := func() *Rule {
+		r := &Rule{
+			Name:    "Hall motion",
+			Trigger: RuleTrigger{Type: RuleTriggerChangesTo, DeviceID: "pir-1", Key: "motion", Value: true},
+			Execute: RuleExecute{SceneID: "scene-1"},
+		}
+		applyRuleDefaults(r)
+		return r
+	}
+
+	if err := ValidateRule(valid()); err != nil {
+		t.Fatalf("valid rule rejected: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*Rule)
+	}{
+		{"empty name", func(r *Rule) { r.Name = "" }},
+		{"bad slug", func(r *Rule) { r.Slug = "Bad Slug" }},
+		{"missing device", func(r *Rule) { r.Trigger.DeviceID = "" }},
+		{"missing key", func(r *Rule) { r.Trigger.Key = "" }},
+		{"missing value", func(r *Rule) { r.Trigger.Value = nil }},
+		{"unknown trigger", func(r *Rule) { r.Trigger.Type = "toggles" }},
+		{"crosses without threshold", func(r *Rule) { r.Trigger.Type = RuleTriggerCrosses; r.Trigger.Direction = CrossRising }},
+		{"crosses bad direction", func(r *Rule) {
+			r.Trigger.Type = RuleTriggerCrosses
+			r.Trigger.Threshold = ptrFloat(20)
+			r.Trigger.Direction = "sideways"
+		}},
+		{"stays without duration", func(r *Rule) { r.Trigger.Type = RuleTriggerStays }},
+		{"missing scene", func(r *Rule) { r.Execute.SceneID = "" }},
+		{"command without name", func(r *Rule) { r.Execute = RuleExecute{Type: RuleExecuteCommand, DeviceID: "light-1"} }},
+		{"unknown execute", func(r *Rule) { r.Execute.Type = "webhook" }},
+		{"mode without mode_id", func(r *Rule) { r.Execute = RuleExecute{Type: RuleExecuteMode} }},
+		{"negative debounce", func(r *Rule) { r.DebounceMS = -1 }},
+		{"cooldown too large", func(r *Rule) { r.CooldownSeconds = maxRuleCooldownSeconds + 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.mutate(r)
+			if err := ValidateRule(r); !errors.Is(err, ErrInvalidRule) {
+				t.Errorf("ValidateRule() error = %v, want ErrInvalidRule", err)
+			}
+		})
+	}
+}
+
+func TestTriggerEdge(t *testing.T) {
+	changesTo := RuleTrigger{Type: RuleTriggerChangesTo, Value: "open"}
+	rising := RuleTrigger{Type: RuleTriggerCrosses, Threshold: ptrFloat(24), Direction: CrossRising}
+	falling := RuleTrigger{Type: RuleTriggerCrosses, Threshold: ptrFloat(24), Direction: CrossFalling}
+	either := RuleTrigger{Type: RuleTriggerCrosses, Threshold: ptrFloat(24), Direction: CrossEither}
+
+	tests := []struct {
+		name    string
+		trigger RuleTrigger
+		prev    any
+		known   bool
+		cur     any
+		want    bool
+	}{
+		{"changes to value", changesTo, "closed", true, "open", true},
+		{"already at value", changesTo, "open", true, "open", false},
+		{"unknown previous", changesTo, nil, false, "open", false},
+		{"numeric types compare by value", RuleTrigger{Type: RuleTriggerChangesTo, Value: 1}, 0.0, true, 1.0, true},
+		{"rising crossing", rising, 23.5, true, 24.0, true},
+		{"rising stays above", rising, 25.0, true, 26.0, false},
+		{"rising ignores falling", rising, 25.0, true, 23.0, false},
+		{"falling crossing", falling, 24.0, true, 23.9, true},
+		{"either rising", either, 20.0, true, 30.0, true},
+		{"either falling", either, 30.0, true, 20.0, true},
+		{"non-numeric", either, "hot", true, 30.0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := triggerEdge(tt.trigger, tt.prev, tt.known, tt.cur); got != tt.want {
+				t.Errorf("triggerEdge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRule_DeepCopy(t *testing.T) {
+	orig := &Rule{
+		Trigger: RuleTrigger{Threshold: ptrFloat(10), Value: map[string]any{"a": 1}},
+		Execute: RuleExecute{Parameters: map[string]any{"level": 50}},
+	}
+	cpy := orig.DeepCopy()
+	*cpy.Trigger.Threshold = 20
+	cpy.Trigger.Value.(map[string]any)["a"] = 2
+	cpy.Execute.Parameters["level"] = 80
+
+	if *orig.Trigger.Threshold != 10 || orig.Trigger.Value.(map[string]any)["a"] != 1 || orig.Execute.Parameters["level"] != 50 {
+		t.Error("DeepCopy shares state with original")
+	}
+}
@@ -0,0 +1,416 @@
+package automation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule triggers a scene at a fixed time, on a cron pattern, or relative
+// to an astronomical event (sunrise, sunset, etc.) at the site location.
+type Schedule struct {
+	// Identity
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+
+	// Description (optional)
+	Description *string `json:"description,omitempty"`
+
+	// Configuration
+	Enabled bool            `json:"enabled"`
+	Trigger ScheduleTrigger `json:"trigger"`
+	Execute ScheduleExecute `json:"execute"`
+
+	// Conditions checked when the schedule fires (optional). A run whose
+	// conditions do not pass is skipped.
+	Conditions []Condition `json:"conditions,omitempty"`
+
+	// Missed-run handling: what to do when a run was due while Core was
+	// stopped (or the host was suspended).
+	MissedRunPolicy       MissedRunPolicy `json:"missed_run_policy"`
+	MissedRunGraceMinutes int             `json:"missed_run_grace_minutes"`
+
+	// Runtime information
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt *time.Time `json:"next_run,omitempty"` // Computed, not persisted
+
+	// Timestamps
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ScheduleTrigger defines when a schedule fires.
+//
+// Value depends on Type:
+//   - time: wall-clock time in the site timezone ("06:30")
+//   - cron: five-field cron expression ("0 */2 * * *")
+//   - sunrise, sunset, solar_noon, dawn, dusk: offset from the event
+//     ("-15m", "+1h", "0m"; empty means no offset)
+//
+// Days restricts time and astronomical triggers to the given weekdays
+// ("mon".."sun"); nil or empty means every day. Cron triggers carry their
+// own day fields and must not set Days.
+type ScheduleTrigger struct {
+	Type  TriggerType `json:"type"`
+	Value string      `json:"value"`
+	Days  []string    `json:"days,omitempty"`
+}
+
+// ScheduleExecute defines what a schedule does when it fires: activate
+// SceneID (type "scene") or change the site mode to ModeID (type "mode").
+type ScheduleExecute struct {
+	Type    ExecuteType `json:"type"`
+	SceneID string      `json:"scene_id,omitempty"`
+	ModeID  string      `json:"mode_id,omitempty"`
+}
+
+// TriggerType identifies how a schedule's fire time is calculated.
+type TriggerType string
+
+const (
+	TriggerTime      TriggerType = "time"
+	TriggerCron      TriggerType = "cron"
+	TriggerSunrise   TriggerType = "sunrise"
+	TriggerSunset    TriggerType = "sunset"
+	TriggerSolarNoon TriggerType = "solar_noon"
+	TriggerDawn      TriggerType = "dawn"
+	TriggerDusk      TriggerType = "dusk"
+)
+
+// ExecuteType identifies what a schedule executes.
+type ExecuteType string
+
+const (
+	ExecuteScene ExecuteType = "scene"
+	ExecuteMode  ExecuteType = "mode"
+)
+
+// MissedRunPolicy controls catch-up behaviour for runs missed while Core
+// was not running.
+type MissedRunPolicy string
+
+const (
+	// MissedRunSkip drops missed runs and waits for the next occurrence.
+	MissedRunSkip MissedRunPolicy = "skip"
+
+	// MissedRunOnce fires a single catch-up run if the most recent missed
+	// occurrence is within the grace window.
+	MissedRunOnce MissedRunPolicy = "run_once"
+)
+
+// Schedule validation constants.
+const (
+	defaultMissedRunGraceMinutes = 60
+	maxMissedRunGraceMinutes     = 24 * 60
+	maxSunOffset                 = 6 * time.Hour
+	maxNextRunsPreview           = 50
+)
+
+// IsSunTrigger reports whether the trigger type is relative to an
+// astronomical event and therefore needs the site coordinates.
+func (t TriggerType) IsSunTrigger() bool {
+	switch t {
+	case TriggerSunrise, TriggerSunset, TriggerSolarNoon, TriggerDawn, TriggerDusk:
+		return true
+	default:
+		return false
+	}
+}
+
+// SiteInfo holds the site details the scheduler needs to compute run times.
+type SiteInfo struct {
+	Latitude  *float64
+	Longitude *float64
+	Timezone  string
+}
+
+// Location returns the site timezone, falling back to UTC when unset or
+// unknown.
+func (si SiteInfo) Location() *time.Location {
+	if si.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(si.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// DeepCopy creates a complete independent copy of the Schedule.
+func (s *Schedule) DeepCopy() *Schedule {
+	if s == nil {
+		return nil
+	}
+
+	cpy := *s
+	cpy.Description = cloneStringPtr(s.Description)
+	if s.Trigger.Days != nil {
+		cpy.Trigger.Days = append([]string(nil), s.Trigger.Days...)
+	}
+	cpy.Conditions = cloneConditions(s.Conditions)
+	if s.LastRunAt != nil {
+		t := *s.LastRunAt
+		cpy.LastRunAt = &t
+	}
+	if s.NextRunAt != nil {
+		t := *s.NextRunAt
+		cpy.NextRunAt = &t
+	}
+	return &cpy
+}
+
+// NextRun returns the first time strictly after `after` at which the
+// trigger fires, given the site location and timezone.
+//
+// Returns:
+//   - time.Time: Next fire time (in the site timezone)
+//   - error: ErrInvalidTrigger for malformed triggers, ErrSiteLocationRequired
+//     for astronomical triggers without site coordinates, or ErrNoNextRun
+//     if the trigger never fires within the search horizon
+func NextRun(trigger ScheduleTrigger, after time.Time, site SiteInfo) (time.Time, error) {
+	loc := site.Location()
+	after = after.In(loc)
+
+	switch {
+	case trigger.Type == TriggerTime:
+		return nextTimeRun(trigger, after)
+	case trigger.Type == TriggerCron:
+		expr, err := ParseCron(trigger.Value)
+		if err != nil {
+			return time.Time{}, err
+		}
+		next := expr.Next(after)
+		if next.IsZero() {
+			return time.Time{}, ErrNoNextRun
+		}
+		return next, nil
+	case trigger.Type.IsSunTrigger():
+		if site.Latitude == nil || site.Longitude == nil {
+			return time.Time{}, ErrSiteLocationRequired
+		}
+		return nextSunRun(trigger, after, *site.Latitude, *site.Longitude)
+	default:
+		return time.Time{}, fmt.Errorf("%w: unknown trigger type %q", ErrInvalidTrigger, trigger.Type)
+	}
+}
+
+// nextTimeRun finds the next fixed-time occurrence on an allowed weekday.
+// A time that falls inside a DST gap is moved forward by the gap (Go's
+// time.Date normalisation), so it still fires once that day.
+func nextTimeRun(trigger ScheduleTrigger, after time.Time) (time.Time, error) {
+	hour, minute, err := parseClock(trigger.Value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	days, err := parseDays(trigger.Days)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc := after.Location()
+	y, m, d := after.Date()
+	for i := 0; i <= 7; i++ {
+		candidate := time.Date(y, m, d+i, hour, minute, 0, 0, loc)
+		if !candidate.After(after) || !dayAllowed(days, time.Date(y, m, d+i, 0, 0, 0, 0, loc).Weekday()) {
+			continue
+		}
+		return candidate, nil
+	}
+	return time.Time{}, ErrNoNextRun
+}
+
+// nextSunRun finds the next astronomical event (plus offset) on an allowed
+// weekday. The weekday filter applies to the calendar day of the event,
+// not of the offset result. Polar day/night can suppress events for months,
+// so the search covers a full year.
+func nextSunRun(trigger ScheduleTrigger, after time.Time, lat, lon float64) (time.Time, error) {
+	offset, err := parseOffset(trigger.Value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	days, err := parseDays(trigger.Days)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc := after.Location()
+	y, m, d := after.Date()
+	// Start a day early: a large positive offset can carry yesterday's
+	// event past `after`.
+	for i := -1; i <= 366; i++ {
+		day := time.Date(y, m, d+i, 12, 0, 0, 0, loc)
+		if !dayAllowed(days, day.Weekday()) {
+			continue
+		}
+		at, ok := SunEventTime(day, lat, lon, SunEvent(trigger.Type))
+		if !ok {
+			continue
+		}
+		at = at.Add(offset)
+		if at.After(after) {
+			return at, nil
+		}
+	}
+	return time.Time{}, ErrNoNextRun
+}
+
+// ValidateTrigger checks a schedule trigger for structural validity.
+// It does not require site coordinates.
+func ValidateTrigger(t ScheduleTrigger) error {
+	switch {
+	case t.Type == TriggerTime:
+		if _, _, err := parseClock(t.Value); err != nil {
+			return err
+		}
+	case t.Type == TriggerCron:
+		if len(t.Days) > 0 {
+			return fmt.Errorf("%w: days cannot be combined with a cron expression", ErrInvalidTrigger)
+		}
+		if _, err := ParseCron(t.Value); err != nil {
+			return err
+		}
+	case t.Type.IsSunTrigger():
+		if _, err := parseOffset(t.Value); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: unknown trigger type %q", ErrInvalidTrigger, t.Type)
+	}
+	if _, err := parseDays(t.Days); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidateSchedule performs comprehensive validation on a schedule.
+// Returns an error describing the first validation failure found.
+func ValidateSchedule(s *Schedule) error {
+	if s == nil {
+		return ErrInvalidSchedule
+	}
+
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("%w: name cannot be empty", ErrInvalidSchedule)
+	}
+	if len(s.Name) > maxNameLength {
+		return fmt.Errorf("%w: name exceeds %d characters", ErrInvalidSchedule, maxNameLength)
+	}
+	if s.Slug != "" {
+		if err := ValidateSlug(s.Slug); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+		}
+	}
+	if s.Description != nil && len(*s.Description) > maxDescriptionLen {
+		return fmt.Errorf("%w: description exceeds %d characters", ErrInvalidSchedule, maxDescriptionLen)
+	}
+
+	if err := ValidateTrigger(s.Trigger); err != nil {
+		return err
+	}
+
+	switch s.Execute.Type {
+	case ExecuteScene:
+		if s.Execute.SceneID == "" {
+			return fmt.Errorf("%w: execute.scene_id is required", ErrInvalidSchedule)
+		}
+		if s.Execute.ModeID != "" {
+			return fmt.Errorf("%w: execute.mode_id is not valid for scene schedules", ErrInvalidSchedule)
+		}
+	case ExecuteMode:
+		if s.Execute.ModeID == "" {
+			return fmt.Errorf("%w: execute.mode_id is required", ErrInvalidSchedule)
+		}
+		if s.Execute.SceneID != "" {
+			return fmt.Errorf("%w: execute.scene_id is not valid for mode schedules", ErrInvalidSchedule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown execute type %q", ErrInvalidSchedule, s.Execute.Type)
+	}
+
+	if err := ValidateConditions(s.Conditions); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+	}
+
+	switch s.MissedRunPolicy {
+	case MissedRunSkip, MissedRunOnce:
+	default:
+		return fmt.Errorf("%w: missed_run_policy must be %q or %q", ErrInvalidSchedule, MissedRunSkip, MissedRunOnce)
+	}
+	if s.MissedRunGraceMinutes < 0 || s.MissedRunGraceMinutes > maxMissedRunGraceMinutes {
+		return fmt.Errorf("%w: missed_run_grace_minutes must be 0-%d", ErrInvalidSchedule, maxMissedRunGraceMinutes)
+	}
+
+	return nil
+}
+
+// applyScheduleDefaults fills optional fields with their defaults.
+func applyScheduleDefaults(s *Schedule) {
+	if s.Execute.Type == "" {
+		s.Execute.Type = ExecuteScene
+	}
+	if s.MissedRunPolicy == "" {
+		s.MissedRunPolicy = MissedRunSkip
+	}
+	if s.MissedRunGraceMinutes == 0 {
+		s.MissedRunGraceMinutes = defaultMissedRunGraceMinutes
+	}
+	for i, day := range s.Trigger.Days {
+		s.Trigger.Days[i] = strings.ToLower(day)
+	}
+}
+
+// parseClock parses "HH:MM" (24-hour).
+func parseClock(value string) (hour, minute int, err error) {
+	h, m, ok := strings.Cut(value, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: time must be HH:MM, got %q", ErrInvalidTrigger, value)
+	}
+	hour, errH := strconv.Atoi(h)
+	minute, errM := strconv.Atoi(m)
+	if errH != nil || errM != nil || len(m) != 2 || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("%w: time must be HH:MM, got %q", ErrInvalidTrigger, value)
+	}
+	return hour, minute, nil
+}
+
+// parseOffset parses an astronomical offset such as "-30m", "+1h" or "0m".
+// An empty value means no offset.
+func parseOffset(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(strings.TrimPrefix(value, "+"))
+	if err != nil {
+		return 0, fmt.Errorf("%w: offset must be a duration like \"-30m\" or \"+1h\", got %q", ErrInvalidTrigger, value)
+	}
+	if d < -maxSunOffset || d > maxSunOffset {
+		return 0, fmt.Errorf("%w: offset must be within ±%s", ErrInvalidTrigger, maxSunOffset)
+	}
+	return d, nil
+}
+
+// weekdayNames maps the API day names to time.Weekday.
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseDays converts day names to a weekday bitset. Zero means every day.
+func parseDays(days []string) (uint8, error) {
+	var set uint8
+	for _, day := range days {
+		wd, ok := weekdayNames[strings.ToLower(day)]
+		if !ok {
+			return 0, fmt.Errorf("%w: unknown day %q", ErrInvalidTrigger, day)
+		}
+		set |= 1 << uint(wd)
+	}
+	return set, nil
+}
+
+func dayAllowed(set uint8, wd time.Weekday) bool {
+	return set == 0 || set&(1<<uint(wd)) != 0
+}
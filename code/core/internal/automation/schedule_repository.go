@@ -0,0 +1,293 @@
+package automation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScheduleRepository defines the interface for schedule persistence.
+type ScheduleRepository interface {
+	GetSchedule(ctx context.Context, id string) (*Schedule, error)
+	ListSchedules(ctx context.Context) ([]Schedule, error)
+	CreateSchedule(ctx context.Context, sched *Schedule) error
+	UpdateSchedule(ctx context.Context, sched *Schedule) error
+	DeleteSchedule(ctx context.Context, id string) error
+
+	// UpdateScheduleLastRun records when a schedule last fired, without
+	// touching updated_at (which tracks configuration changes).
+	UpdateScheduleLastRun(ctx context.Context, id string, at time.Time) error
+}
+
+// scheduleColumns is the SELECT column list for schedule queries.
+const scheduleColumns = `id, name, slug, description, enabled, trigger_config,
+			execute_type, scene_id, mode_id, conditions, missed_run_policy, missed_run_grace_min,
+			last_run_at, created_at, updated_at`
+
+// SQLiteScheduleRepository implements ScheduleRepository using SQLite.
+type SQLiteScheduleRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteScheduleRepository creates a new SQLite-backed schedule repository.
+func NewSQLiteScheduleRepository(db *sql.DB) *SQLiteScheduleRepository {
+	return &SQLiteScheduleRepository{db: db}
+}
+
+// GetSchedule retrieves a schedule by its unique identifier.
+func (r *SQLiteScheduleRepository) GetSchedule(ctx context.Context, id string) (*Schedule, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM schedules WHERE id = ?`
+
+	sched, err := scanScheduleRow(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, fmt.Errorf("querying schedule by id: %w", err)
+	}
+	return sched, nil
+}
+
+// ListSchedules retrieves all schedules ordered by name.
+func (r *SQLiteScheduleRepository) ListSchedules(ctx context.Context) ([]Schedule, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM schedules ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []Schedule
+	for rows.Next() {
+		sched, scanErr := scanScheduleRow(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("scanning schedule: %w", scanErr)
+		}
+		schedules = append(schedules, *sched)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// CreateSchedule inserts a new schedule.
+func (r *SQLiteScheduleRepository) CreateSchedule(ctx context.Context, sched *Schedule) error {
+	triggerJSON, err := json.Marshal(sched.Trigger)
+	if err != nil {
+		return fmt.Errorf("marshalling trigger: %w", err)
+	}
+	conditionsJSON, err := marshalConditions(sched.Conditions)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if sched.CreatedAt.IsZero() {
+		sched.CreatedAt = now
+	}
+	sched.UpdatedAt = now
+
+	query := `
+		INSERT INTO schedules (
+			id, name, slug, description, enabled, trigger_config,
+			execute_type, scene_id, mode_id, conditions, missed_run_policy, missed_run_grace_min,
+			last_run_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		sched.ID,
+		sched.Name,
+		sched.Slug,
+		nullableString(sched.Description),
+		boolToInt(sched.Enabled),
+		string(triggerJSON),
+		string(sched.Execute.Type),
+		nullableString(&sched.Execute.SceneID),
+		nullableString(&sched.Execute.ModeID),
+		conditionsJSON,
+		string(sched.MissedRunPolicy),
+		sched.MissedRunGraceMinutes,
+		nullableTime(sched.LastRunAt),
+		sched.CreatedAt.Format(time.RFC3339),
+		sched.UpdatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrScheduleExists
+		}
+		if isForeignKeyError(err) {
+			return scheduleTargetNotFound(sched)
+		}
+		return fmt.Errorf("inserting schedule: %w", err)
+	}
+	return nil
+}
+
+// UpdateSchedule modifies an existing schedule's configuration.
+func (r *SQLiteScheduleRepository) UpdateSchedule(ctx context.Context, sched *Schedule) error {
+	triggerJSON, err := json.Marshal(sched.Trigger)
+	if err != nil {
+		return fmt.Errorf("marshalling trigger: %w", err)
+	}
+	conditionsJSON, err := marshalConditions(sched.Conditions)
+	if err != nil {
+		return err
+	}
+
+	sched.UpdatedAt = time.Now().UTC()
+
+	query := `
+		UPDATE schedules SET
+			name = ?, slug = ?, description = ?, enabled = ?, trigger_config = ?,
+			execute_type = ?, scene_id = ?, mode_id = ?, conditions = ?, missed_run_policy = ?, missed_run_grace_min = ?,
+			updated_at = ?
+		WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query,
+		sched.Name,
+		sched.Slug,
+		nullableString(sched.Description),
+		boolToInt(sched.Enabled),
+		string(triggerJSON),
+		string(sched.Execute.Type),
+		nullableString(&sched.Execute.SceneID),
+		nullableString(&sched.Execute.ModeID),
+		conditionsJSON,
+		string(sched.MissedRunPolicy),
+		sched.MissedRunGraceMinutes,
+		sched.UpdatedAt.Format(time.RFC3339),
+		sched.ID,
+	)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrScheduleExists
+		}
+		if isForeignKeyError(err) {
+			return scheduleTargetNotFound(sched)
+		}
+		return fmt.Errorf("updating schedule: %w", err)
+	}
+	return checkScheduleRowsAffected(result)
+}
+
+// DeleteSchedule removes a schedule by ID.
+func (r *SQLiteScheduleRepository) DeleteSchedule(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM schedules WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("deleting schedule: %w", err)
+	}
+	return checkScheduleRowsAffected(result)
+}
+
+// UpdateScheduleLastRun records when a schedule last fired.
+func (r *SQLiteScheduleRepository) UpdateScheduleLastRun(ctx context.Context, id string, at time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE schedules SET last_run_at = ? WHERE id = ?",
+		at.UTC().Format(time.RFC3339), id,
+	)
+	if err != nil {
+		return fmt.Errorf("updating schedule last run: %w", err)
+	}
+	return checkScheduleRowsAffected(result)
+}
+
+func checkScheduleRowsAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("checking rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+// scheduleTargetNotFound maps a foreign key violation to the missing
+// execute target.
+func scheduleTargetNotFound(sched *Schedule) error {
+	if sched.Execute.Type == ExecuteMode {
+		return ErrModeNotFound
+	}
+	return ErrSceneNotFound
+}
+
+// isForeignKeyError reports whether err is a SQLite foreign key violation
+// (e.g. a schedule referencing a scene that does not exist).
+func isForeignKeyError(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "foreign key constraint failed")
+}
+
+func scanScheduleRow(scanner rowScanner) (*Schedule, error) {
+	var s Schedule
+	var description, sceneID, modeID, conditionsJSON, lastRunAt sql.NullString
+	var triggerJSON, executeType, policy string
+	var enabled int
+	var createdAt, updatedAt string
+
+	err := scanner.Scan(
+		&s.ID,
+		&s.Name,
+		&s.Slug,
+		&description,
+		&enabled,
+		&triggerJSON,
+		&executeType,
+		&sceneID,
+		&modeID,
+		&conditionsJSON,
+		&policy,
+		&s.MissedRunGraceMinutes,
+		&lastRunAt,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if description.Valid {
+		s.Description = &description.String
+	}
+	s.Enabled = enabled != 0
+	s.Execute.Type = ExecuteType(executeType)
+	s.Execute.SceneID = sceneID.String
+	s.Execute.ModeID = modeID.String
+	s.MissedRunPolicy = MissedRunPolicy(policy)
+
+	if jsonErr := json.Unmarshal([]byte(triggerJSON), &s.Trigger); jsonErr != nil {
+		return nil, fmt.Errorf("unmarshalling trigger: %w", jsonErr)
+	}
+	if conditionsJSON.Valid {
+		conditions, condErr := unmarshalConditions(conditionsJSON.String)
+		if condErr != nil {
+			return nil, condErr
+		}
+		s.Conditions = conditions
+	}
+
+	if lastRunAt.Valid {
+		t, parseErr := time.Parse(time.RFC3339, lastRunAt.String)
+		if parseErr != nil {
+			return nil, fmt.Errorf("schedule %s last_run_at: %w", s.ID, parseErr)
+		}
+		s.LastRunAt = &t
+	}
+	if t, parseErr := time.Parse(time.RFC3339, createdAt); parseErr == nil {
+		s.CreatedAt = t
+	} else {
+		return nil, fmt.Errorf("schedule %s created_at: %w", s.ID, parseErr)
+	}
+	if t, parseErr := time.Parse(time.RFC3339, updatedAt); parseErr == nil {
+		s.UpdatedAt = t
+	} else {
+		return nil, fmt.Errorf("schedule %s updated_at: %w", s.ID, parseErr)
+	}
+
+	return &s, nil
+}
@@ -0,0 +1,124 @@
+package automation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+// setupScheduleTestDB extends the mode test schema with the schedules table.
+func setupScheduleTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := setupModeTestDB(t)
+
+	schema := `
+		PRAGMA foreign_keys = ON;
+		CREATE TABLE schedules (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			slug TEXT NOT NULL UNIQUE,
+			description TEXT,
+			enabled INTEGER NOT NULL DEFAULT 1,
+			trigger_config TEXT NOT NULL,
+			execute_type TEXT NOT NULL DEFAULT 'scene',
+			scene_id TEXT,
+			mode_id TEXT REFERENCES modes(id) ON DELETE CASCADE,
+			conditions TEXT,
+			missed_run_policy TEXT NOT NULL DEFAULT 'skip',
+			missed_run_grace_min INTEGER NOT NULL DEFAULT 60,
+			last_run_at TEXT,
+			created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+			updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+			FOREIGN KEY (scene_id) REFERENCES scenes(id) ON DELETE CASCADE
+		) STRICT;`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("creating schedules schema: %v", err)
+	}
+	return db
+}
+
+func TestScheduleRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	db := setupScheduleTestDB(t)
+	scenes := NewSQLiteRepository(db)
+	repo := NewSQLiteScheduleRepository(db)
+
+	if err := scenes.Create(ctx, testScene("scene-1", "Morning")); err != nil {
+		t.Fatalf("creating scene: %v", err)
+	}
+
+	sched := &Schedule{
+		ID:                    "sched-1",
+		Name:                  "Weekday Morning",
+		Slug:                  "weekday-morning",
+		Enabled:               true,
+		Trigger:               ScheduleTrigger{Type: TriggerTime, Value: "06:30", Days: []string{"mon", "tue"}},
+		Execute:               ScheduleExecute{Type: ExecuteScene, SceneID: "scene-1"},
+		MissedRunPolicy:       MissedRunOnce,
+		MissedRunGraceMinutes: 30,
+	}
+	if err := repo.CreateSchedule(ctx, sched); err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+
+	got, err := repo.GetSchedule(ctx, "sched-1")
+	if err != nil {
+		t.Fatalf("GetSchedule: %v", err)
+	}
+	if got.Trigger.Value != "06:30" || len(got.Trigger.Days) != 2 || got.Execute.SceneID != "scene-1" ||
+		got.MissedRunPolicy != MissedRunOnce || got.MissedRunGraceMinutes != 30 || got.LastRunAt != nil {
+		t.Errorf("round-trip mismatch: %+v", got)
+	}
+
+	ran := time.Date(2026, 3, 10, 6, 30, 0, 0, time.UTC)
+	if err := repo.UpdateScheduleLastRun(ctx, "sched-1", ran); err != nil {
+		t.Fatalf("UpdateScheduleLastRun: %v", err)
+	}
+	got, _ = repo.GetSchedule(ctx, "sched-1")
+	if got.LastRunAt == nil || !got.LastRunAt.Equal(ran) {
+		t.Errorf("LastRunAt = %v, want %v", got.LastRunAt, ran)
+	}
+
+	got.Enabled = false
+	if err := repo.UpdateSchedule(ctx, got); err != nil {
+		t.Fatalf("UpdateSchedule: %v", err)
+	}
+	list, err := repo.ListSchedules(ctx)
+	if err != nil || len(list) != 1 || list[0].Enabled {
+		t.Fatalf("ListSchedules() = %+v, %v", list, err)
+	}
+
+	if err := repo.DeleteSchedule(ctx, "sched-1"); err != nil {
+		t.Fatalf("DeleteSchedule: %v", err)
+	}
+	if _, err := repo.GetSchedule(ctx, "sched-1"); !errors.Is(err, ErrScheduleNotFound) {
+		t.Errorf("GetSchedule after delete error = %v, want ErrScheduleNotFound", err)
+	}
+}
+
+func TestScheduleRepository_Errors(t *testing.T) {
+	ctx := context.Background()
+	db := setupScheduleTestDB(t)
+	repo := NewSQLiteScheduleRepository(db)
+
+	sched := &Schedule{
+		ID:              "sched-1",
+		Name:            "Orphan",
+		Slug:            "orphan",
+		Trigger:         ScheduleTrigger{Type: TriggerTime, Value: "06:30"},
+		Execute:         ScheduleExecute{Type: ExecuteScene, SceneID: "missing-scene"},
+		MissedRunPolicy: MissedRunSkip,
+	}
+	if err := repo.CreateSchedule(ctx, sched); !errors.Is(err, ErrSceneNotFound) {
+		t.Errorf("CreateSchedule with unknown scene error = %v, want ErrSceneNotFound", err)
+	}
+
+	if err := repo.UpdateSchedule(ctx, sched); !errors.Is(err, ErrScheduleNotFound) {
+		t.Errorf("UpdateSchedule unknown error = %v, want ErrScheduleNotFound", err)
+	}
+	if err := repo.DeleteSchedule(ctx, "nope"); !errors.Is(err, ErrScheduleNotFound) {
+		t.Errorf("DeleteSchedule unknown error = %v, want ErrScheduleNotFound", err)
+	}
+}
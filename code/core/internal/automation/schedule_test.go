@@ -0,0 +1,181 @@
+package automation
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func londonSite() SiteInfo {
+	lat, lon := londonLat, londonLon
+	return SiteInfo{Latitude: &lat, Longitude: &lon, Timezone: "Europe/London"}
+}
+
+func TestNextRun_Time(t *testing.T) {
+	site := SiteInfo{Timezone: "UTC"}
+	trigger := ScheduleTrigger{Type: TriggerTime, Value: "06:30", Days: []string{"mon", "wed"}}
+
+	// Tuesday 10 March 2026 07:00 -> Wednesday 06:30
+	got, err := NextRun(trigger, time.Date(2026, 3, 10, 7, 0, 0, 0, time.UTC), site)
+	if err != nil {
+		t.Fatalf("NextRun: %v", err)
+	}
+	want := time.Date(2026, 3, 11, 6, 30, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("NextRun() = %v, want %v", got, want)
+	}
+
+	// Same day, before the time, no day filter.
+	trigger.Days = nil
+	got, _ = NextRun(trigger, time.Date(2026, 3, 10, 6, 0, 0, 0, time.UTC), site)
+	if want := time.Date(2026, 3, 10, 6, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("NextRun() = %v, want %v", got, want)
+	}
+}
+
+func TestNextRun_TimeUsesSiteTimezone(t *testing.T) {
+	site := SiteInfo{Timezone: "Europe/London"}
+	if site.Location() == time.UTC {
+		t.Skip("tzdata unavailable")
+	}
+	trigger := ScheduleTrigger{Type: TriggerTime, Value: "07:00"}
+
+	// In BST, 07:00 local is 06:00 UTC.
+	got, err := NextRun(trigger, time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), site)
+	if err != nil {
+		t.Fatalf("NextRun: %v", err)
+	}
+	if want := time.Date(2026, 7, 1, 6, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("NextRun() = %v, want %v", got.UTC(), want)
+	}
+}
+
+func TestNextRun_SunsetOffset(t *testing.T) {
+	site := londonSite()
+	after := time.Date(2026, 6, 21, 12, 0, 0, 0, time.UTC)
+
+	base, err := NextRun(ScheduleTrigger{Type: TriggerSunset, Value: "0m"}, after, site)
+	if err != nil {
+		t.Fatalf("NextRun: %v", err)
+	}
+	offset, err := NextRun(ScheduleTrigger{Type: TriggerSunset, Value: "-15m"}, after, site)
+	if err != nil {
+		t.Fatalf("NextRun: %v", err)
+	}
+	if diff := base.Sub(offset); diff != 15*time.Minute {
+		t.Errorf("offset difference = %v, want 15m", diff)
+	}
+
+	// After today's sunset, the next run is tomorrow.
+	next, _ := NextRun(ScheduleTrigger{Type: TriggerSunset}, base, site)
+	if next.Sub(base) < 23*time.Hour || next.Sub(base) > 25*time.Hour {
+		t.Errorf("next sunset %v is not about a day after %v", next, base)
+	}
+}
+
+func TestNextRun_SunRequiresLocation(t *testing.T) {
+	_, err := NextRun(ScheduleTrigger{Type: TriggerSunrise}, time.Now(), SiteInfo{})
+	if !errors.Is(err, ErrSiteLocationRequired) {
+		t.Errorf("NextRun() error = %v, want ErrSiteLocationRequired", err)
+	}
+}
+
+func TestNextRun_Cron(t *testing.T) {
+	got, err := NextRun(ScheduleTrigger{Type: TriggerCron, Value: "0 9 * * *"},
+		time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC), SiteInfo{})
+	if err != nil {
+		t.Fatalf("NextRun: %v", err)
+	}
+	if want := time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("NextRun() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateTrigger(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger ScheduleTrigger
+		wantErr bool
+	}{
+		{"valid time", ScheduleTrigger{Type: TriggerTime, Value: "23:59"}, false},
+		{"time out of range", ScheduleTrigger{Type: TriggerTime, Value: "24:00"}, true},
+		{"time missing minutes", ScheduleTrigger{Type: TriggerTime, Value: "7"}, true},
+		{"valid sunset", ScheduleTrigger{Type: TriggerSunset, Value: "-30m"}, false},
+		{"valid sunrise plus", ScheduleTrigger{Type: TriggerSunrise, Value: "+1h"}, false},
+		{"empty offset", ScheduleTrigger{Type: TriggerDusk}, false},
+		{"offset too large", ScheduleTrigger{Type: TriggerSunrise, Value: "+7h"}, true},
+		{"bad offset", ScheduleTrigger{Type: TriggerSunrise, Value: "soon"}, true},
+		{"valid cron", ScheduleTrigger{Type: TriggerCron, Value: "*/5 * * * *"}, false},
+		{"cron with days", ScheduleTrigger{Type: TriggerCron, Value: "* * * * *", Days: []string{"mon"}}, true},
+		{"unknown day", ScheduleTrigger{Type: TriggerTime, Value: "07:00", Days: []string{"funday"}}, true},
+		{"unknown type", ScheduleTrigger{Type: "moonrise"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTrigger(tt.trigger)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTrigger() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidTrigger) {
+				t.Errorf("error %v does not wrap ErrInvalidTrigger", err)
+			}
+		})
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	valid := func() *Schedule {
+		s := &Schedule{
+			Name:    "Morning",
+			Trigger: ScheduleTrigger{Type: TriggerTime, Value: "06:30"},
+			Execute: ScheduleExecute{SceneID: "scene-1"},
+		}
+		applyScheduleDefaults(s)
+		return s
+	}
+
+	if err := ValidateSchedule(valid()); err != nil {
+		t.Fatalf("valid schedule rejected: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*Schedule)
+	}{
+		{"empty name", func(s *Schedule) { s.Name = " " }},
+		{"bad slug", func(s *Schedule) { s.Slug = "Bad Slug" }},
+		{"missing scene", func(s *Schedule) { s.Execute.SceneID = "" }},
+		{"unknown execute", func(s *Schedule) { s.Execute.Type = "actions" }},
+		{"mode without mode_id", func(s *Schedule) { s.Execute = ScheduleExecute{Type: ExecuteMode} }},
+		{"mode with scene_id", func(s *Schedule) { s.Execute.Type = ExecuteMode; s.Execute.ModeID = "night" }},
+		{"unknown policy", func(s *Schedule) { s.MissedRunPolicy = "always" }},
+		{"grace too large", func(s *Schedule) { s.MissedRunGraceMinutes = maxMissedRunGraceMinutes + 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := valid()
+			tt.mutate(s)
+			if err := ValidateSchedule(s); !errors.Is(err, ErrInvalidSchedule) {
+				t.Errorf("ValidateSchedule() error = %v, want ErrInvalidSchedule", err)
+			}
+		})
+	}
+}
+
+func TestSchedule_DeepCopy(t *testing.T) {
+	desc := "d"
+	last := time.Now()
+	orig := &Schedule{
+		Description: &desc,
+		Trigger:     ScheduleTrigger{Days: []string{"mon"}},
+		LastRunAt:   &last,
+	}
+	cpy := orig.DeepCopy()
+	cpy.Trigger.Days[0] = "tue"
+	*cpy.Description = "x"
+	*cpy.LastRunAt = last.Add(time.Hour)
+
+	if orig.Trigger.Days[0] != "mon" || *orig.Description != "d" || !orig.LastRunAt.Equal(last) {
+		t.Error("DeepCopy shares state with original")
+	}
+}
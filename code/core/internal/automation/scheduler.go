@@ -0,0 +1,669 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SceneActivator is the interface the scheduler needs to run scenes.
+// *Engine satisfies it.
+type SceneActivator interface {
+	ActivateScene(ctx context.Context, sceneID, triggerType, triggerSource string) (string, error)
+}
+
+// SiteProvider supplies the site location and timezone used to compute
+// schedule run times. It is queried on every evaluation so edits to the
+// site take effect without a restart.
+type SiteProvider interface {
+	GetSiteInfo(ctx context.Context) (SiteInfo, error)
+}
+
+const (
+	// schedulerMaxSleep caps how long the loop sleeps between evaluations,
+	// so wall-clock jumps (NTP steps, suspend/resume) and site timezone
+	// changes are noticed promptly.
+	schedulerMaxSleep = time.Minute
+
+	// scheduleLateTolerance is how late a run may be and still count as
+	// on time. Anything later is treated as missed and handled by the
+	// schedule's MissedRunPolicy.
+	scheduleLateTolerance = 2 * time.Minute
+
+	// maxDueScan bounds the number of occurrences walked when looking for
+	// the most recent due run (an every-minute cron over a 24h grace window
+	// is 1440).
+	maxDueScan = 2000
+)
+
+// runDecision describes what the scheduler should do for a schedule at a
+// given evaluation instant.
+type runDecision int
+
+const (
+	runNone    runDecision = iota // Nothing due
+	runOnTime                     // Due within tolerance: fire
+	runCatchUp                    // Missed, but policy allows one catch-up run
+	runSkipped                    // Missed and dropped per policy
+)
+
+// Scheduler fires scenes from time, cron and astronomical schedules.
+//
+// It keeps all schedules in memory, sleeps until the earliest next run
+// (capped at schedulerMaxSleep), and activates the target scene with
+// trigger type "schedule". Each schedule has an evaluation cursor: on every
+// pass the scheduler looks for occurrences between the cursor and now, so
+// runs missed while Core was stopped are detected on startup using the
+// persisted last_run_at.
+//
+// Thread Safety: All public methods are safe for concurrent use.
+type Scheduler struct {
+	repo       ScheduleRepository
+	scenes     SceneActivator
+	site       SiteProvider
+	modes      ModeSetter // Optional; required by "mode" schedules
+	conditions *ConditionEvaluator
+	hub        WSHub
+	logger     Logger
+	now        func() time.Time
+
+	mu        sync.RWMutex
+	schedules map[string]*Schedule
+	cursors   map[string]time.Time // Last evaluated instant per schedule ID
+
+	wake   chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates a new scheduler.
+//
+// Parameters:
+//   - repo: Repository for schedule persistence
+//   - scenes: Scene activator (usually the scene Engine)
+//   - site: Provider for site coordinates and timezone
+//   - hub: WebSocket hub for schedule.triggered events (may be nil)
+//   - logger: Logger instance (may be nil)
+func NewScheduler(repo ScheduleRepository, scenes SceneActivator, site SiteProvider, hub WSHub, logger Logger) *Scheduler {
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	return &Scheduler{
+		repo:       repo,
+		scenes:     scenes,
+		site:       site,
+		conditions: NewConditionEvaluator(site, nil),
+		hub:        hub,
+		logger:     logger,
+		now:        time.Now,
+		schedules:  make(map[string]*Schedule),
+		cursors:    make(map[string]time.Time),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// Start loads schedules and launches the scheduling loop.
+// Missed runs since each schedule's last run are evaluated on the first pass.
+func (s *Scheduler) Start(ctx context.Context) error {
+	if err := s.RefreshCache(ctx); err != nil {
+		return err
+	}
+
+	loopCtx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go s.run(loopCtx)
+
+	s.logger.Info("scheduler started", "schedules", s.count())
+	return nil
+}
+
+// Stop halts the scheduling loop and waits for in-flight activations.
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// RefreshCache reloads all schedules from the repository.
+//
+// Schedules seen for the first time get a cursor at their last run (or last
+// configuration change if they have never run), which is what makes
+// missed-run detection work across restarts. Existing cursors are kept.
+func (s *Scheduler) RefreshCache(ctx context.Context) error {
+	schedules, err := s.repo.ListSchedules(ctx)
+	if err != nil {
+		return fmt.Errorf("loading schedules: %w", err)
+	}
+
+	s.mu.Lock()
+	cache := make(map[string]*Schedule, len(schedules))
+	cursors := make(map[string]time.Time, len(schedules))
+	for i := range schedules {
+		sched := schedules[i].DeepCopy()
+		cache[sched.ID] = sched
+		if cur, ok := s.cursors[sched.ID]; ok {
+			cursors[sched.ID] = cur
+		} else {
+			cursors[sched.ID] = initialCursor(sched)
+		}
+	}
+	s.schedules = cache
+	s.cursors = cursors
+	s.mu.Unlock()
+
+	s.logger.Info("schedule cache refreshed", "count", len(schedules))
+	s.notify()
+	return nil
+}
+
+// ListSchedules returns all schedules with NextRunAt populated.
+func (s *Scheduler) ListSchedules(ctx context.Context) []Schedule {
+	site := s.siteInfo(ctx)
+	now := s.now()
+
+	s.mu.RLock()
+	result := make([]Schedule, 0, len(s.schedules))
+	for _, sched := range s.schedules {
+		cpy := sched.DeepCopy()
+		s.fillNextRun(cpy, now, site)
+		result = append(result, *cpy)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// GetSchedule returns a schedule by ID with NextRunAt populated.
+func (s *Scheduler) GetSchedule(ctx context.Context, id string) (*Schedule, error) {
+	site := s.siteInfo(ctx)
+
+	s.mu.RLock()
+	sched, ok := s.schedules[id]
+	if !ok {
+		s.mu.RUnlock()
+		return nil, ErrScheduleNotFound
+	}
+	cpy := sched.DeepCopy()
+	s.mu.RUnlock()
+
+	s.fillNextRun(cpy, s.now(), site)
+	return cpy, nil
+}
+
+// CreateSchedule validates, persists and activates a new schedule.
+// ID and slug are generated when empty. Astronomical triggers are rejected
+// with ErrSiteLocationRequired if the site has no coordinates.
+func (s *Scheduler) CreateSchedule(ctx context.Context, sched *Schedule) error {
+	if sched.ID == "" {
+		sched.ID = GenerateID()
+	}
+	if sched.Slug == "" {
+		sched.Slug = GenerateSlug(sched.Name)
+	}
+	applyScheduleDefaults(sched)
+
+	if err := s.validate(ctx, sched); err != nil {
+		return err
+	}
+	sched.LastRunAt = nil
+	sched.NextRunAt = nil
+
+	if err := s.repo.CreateSchedule(ctx, sched); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.schedules[sched.ID] = sched.DeepCopy()
+	s.cursors[sched.ID] = s.now()
+	s.mu.Unlock()
+
+	s.fillNextRun(sched, s.now(), s.siteInfo(ctx))
+	s.notify()
+	return nil
+}
+
+// UpdateSchedule validates and persists changes to an existing schedule.
+// The evaluation cursor is reset to now, so editing a trigger never causes
+// a catch-up run for an occurrence that was only "missed" under the new
+// definition.
+func (s *Scheduler) UpdateSchedule(ctx context.Context, sched *Schedule) error {
+	s.mu.RLock()
+	existing, ok := s.schedules[sched.ID]
+	var lastRun *time.Time
+	if ok && existing.LastRunAt != nil {
+		t := *existing.LastRunAt
+		lastRun = &t
+	}
+	s.mu.RUnlock()
+	if !ok {
+		return ErrScheduleNotFound
+	}
+
+	if sched.Slug == "" {
+		sched.Slug = GenerateSlug(sched.Name)
+	}
+	applyScheduleDefaults(sched)
+
+	if err := s.validate(ctx, sched); err != nil {
+		return err
+	}
+	sched.LastRunAt = lastRun
+	sched.NextRunAt = nil
+
+	if err := s.repo.UpdateSchedule(ctx, sched); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.schedules[sched.ID] = sched.DeepCopy()
+	s.cursors[sched.ID] = s.now()
+	s.mu.Unlock()
+
+	s.fillNextRun(sched, s.now(), s.siteInfo(ctx))
+	s.notify()
+	return nil
+}
+
+// SetModeSetter sets the mode manager used by schedules that change the site
+// mode.
+func (s *Scheduler) SetModeSetter(modes ModeSetter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.modes = modes
+}
+
+// SetConditionEvaluator sets the evaluator used for schedule conditions.
+// By default conditions are evaluated against the scheduler's site only.
+func (s *Scheduler) SetConditionEvaluator(conditions *ConditionEvaluator) {
+	if conditions == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conditions = conditions
+}
+
+// SetEnabled enables or disables a schedule. Runs that fell due while a
+// schedule was disabled are never caught up.
+func (s *Scheduler) SetEnabled(ctx context.Context, id string, enabled bool) (*Schedule, error) {
+	sched, err := s.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sched.Enabled == enabled {
+		return sched, nil
+	}
+	sched.Enabled = enabled
+	if err := s.UpdateSchedule(ctx, sched); err != nil {
+		return nil, err
+	}
+	return sched, nil
+}
+
+// DeleteSchedule removes a schedule.
+func (s *Scheduler) DeleteSchedule(ctx context.Context, id string) error {
+	if err := s.repo.DeleteSchedule(ctx, id); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.schedules, id)
+	delete(s.cursors, id)
+	s.mu.Unlock()
+
+	s.notify()
+	return nil
+}
+
+// NextRuns previews the next count run times for a schedule, starting now.
+// Disabled schedules are previewed as if enabled.
+func (s *Scheduler) NextRuns(ctx context.Context, id string, count int) ([]time.Time, error) {
+	if count <= 0 {
+		count = 1
+	}
+	if count > maxNextRunsPreview {
+		count = maxNextRunsPreview
+	}
+
+	s.mu.RLock()
+	sched, ok := s.schedules[id]
+	var trigger ScheduleTrigger
+	if ok {
+		trigger = sched.DeepCopy().Trigger
+	}
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrScheduleNotFound
+	}
+
+	site := s.siteInfo(ctx)
+	runs := make([]time.Time, 0, count)
+	after := s.now()
+	for len(runs) < count {
+		next, err := NextRun(trigger, after, site)
+		if err != nil {
+			if len(runs) > 0 && errors.Is(err, ErrNoNextRun) {
+				break
+			}
+			return nil, err
+		}
+		runs = append(runs, next)
+		after = next
+	}
+	return runs, nil
+}
+
+// run is the scheduling loop.
+func (s *Scheduler) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	for {
+		s.tick(ctx)
+
+		timer := time.NewTimer(s.sleepDuration(ctx))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-s.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// tick evaluates every enabled schedule at the current instant and fires
+// anything due.
+func (s *Scheduler) tick(ctx context.Context) {
+	site := s.siteInfo(ctx)
+	now := s.now()
+
+	type dueRun struct {
+		sched   *Schedule
+		at      time.Time
+		catchUp bool
+	}
+	var due []dueRun
+
+	s.mu.Lock()
+	for id, sched := range s.schedules {
+		cursor := s.cursors[id]
+		s.cursors[id] = now
+		if !sched.Enabled {
+			continue
+		}
+
+		at, decision := resolveDueRun(sched, cursor, now, site)
+		switch decision {
+		case runOnTime:
+			due = append(due, dueRun{sched: sched.DeepCopy(), at: at})
+		case runCatchUp:
+			s.logger.Info("schedule run missed, catching up",
+				"schedule_id", id, "scheduled_for", at, "late_by", now.Sub(at).Round(time.Second).String())
+			due = append(due, dueRun{sched: sched.DeepCopy(), at: at, catchUp: true})
+		case runSkipped:
+			s.logger.Warn("schedule run missed, skipping",
+				"schedule_id", id, "scheduled_for", at, "policy", string(sched.MissedRunPolicy))
+		case runNone:
+		}
+	}
+	s.mu.Unlock()
+
+	for _, d := range due {
+		s.wg.Add(1)
+		go s.fire(ctx, d.sched, d.at, d.catchUp)
+	}
+}
+
+// fire runs the schedule's execute target and records the run. A run whose
+// conditions do not pass is recorded but does nothing.
+func (s *Scheduler) fire(ctx context.Context, sched *Schedule, scheduledFor time.Time, catchUp bool) {
+	defer s.wg.Done()
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("panic in schedule run", "schedule_id", sched.ID, "panic", r)
+		}
+	}()
+
+	firedAt := s.now()
+	s.mu.Lock()
+	if cached, ok := s.schedules[sched.ID]; ok {
+		t := firedAt
+		cached.LastRunAt = &t
+	}
+	modes := s.modes
+	conditions := s.conditions
+	s.mu.Unlock()
+
+	if err := s.repo.UpdateScheduleLastRun(ctx, sched.ID, firedAt); err != nil {
+		s.logger.Error("failed to record schedule run", "schedule_id", sched.ID, "error", err)
+	}
+
+	if err := conditions.Check(ctx, sched.Conditions); err != nil {
+		s.logger.Info("schedule skipped",
+			"schedule_id", sched.ID,
+			"reason", err.Error(),
+		)
+		return
+	}
+
+	source := "schedule:" + sched.ID
+	executed := map[string]any{"type": string(sched.Execute.Type)}
+	event := map[string]any{
+		"schedule_id":   sched.ID,
+		"schedule_name": sched.Name,
+		"scheduled_for": scheduledFor.UTC(),
+		"catch_up":      catchUp,
+		"executed":      executed,
+	}
+
+	switch sched.Execute.Type {
+	case ExecuteMode:
+		executed["mode_id"] = sched.Execute.ModeID
+		if modes == nil {
+			s.logger.Error("scheduled mode change failed",
+				"schedule_id", sched.ID,
+				"mode_id", sched.Execute.ModeID,
+				"error", ErrModesUnavailable,
+			)
+			return
+		}
+		if _, err := modes.SetMode(ctx, sched.Execute.ModeID, source, ""); err != nil {
+			s.logger.Error("scheduled mode change failed",
+				"schedule_id", sched.ID,
+				"mode_id", sched.Execute.ModeID,
+				"error", err,
+			)
+			return
+		}
+		s.logger.Info("schedule triggered",
+			"schedule_id", sched.ID,
+			"mode_id", sched.Execute.ModeID,
+			"catch_up", catchUp,
+		)
+	default:
+		executed["scene_id"] = sched.Execute.SceneID
+		executionID, err := s.scenes.ActivateScene(ctx, sched.Execute.SceneID, "schedule", source)
+		if errors.Is(err, ErrConditionsNotMet) {
+			s.logger.Info("scheduled scene skipped",
+				"schedule_id", sched.ID,
+				"scene_id", sched.Execute.SceneID,
+				"execution_id", executionID,
+				"reason", err.Error(),
+			)
+			return
+		}
+		if err != nil {
+			s.logger.Error("scheduled scene activation failed",
+				"schedule_id", sched.ID,
+				"scene_id", sched.Execute.SceneID,
+				"error", err,
+			)
+			return
+		}
+		event["execution_id"] = executionID
+		s.logger.Info("schedule triggered",
+			"schedule_id", sched.ID,
+			"scene_id", sched.Execute.SceneID,
+			"execution_id", executionID,
+			"catch_up", catchUp,
+		)
+	}
+
+	if s.hub != nil {
+		s.hub.Broadcast("schedule.triggered", event)
+	}
+}
+
+// resolveDueRun finds the most recent occurrence in (cursor, now] and
+// decides whether to fire it.
+//
+// Only occurrences inside the schedule's grace window can be caught up, so
+// the scan starts no earlier than now minus that window. Occurrences before
+// the window are dropped silently apart from the most recent one, which is
+// reported as runSkipped.
+func resolveDueRun(sched *Schedule, cursor, now time.Time, site SiteInfo) (time.Time, runDecision) {
+	if !now.After(cursor) {
+		return time.Time{}, runNone
+	}
+
+	window := time.Duration(sched.MissedRunGraceMinutes) * time.Minute
+	if window < scheduleLateTolerance {
+		window = scheduleLateTolerance
+	}
+	start := cursor
+	if floor := now.Add(-window); start.Before(floor) {
+		start = floor
+	}
+
+	var latest time.Time
+	after := start
+	for i := 0; i < maxDueScan; i++ {
+		next, err := NextRun(sched.Trigger, after, site)
+		if err != nil || next.After(now) {
+			break
+		}
+		latest = next
+		after = next
+	}
+
+	if latest.IsZero() {
+		// Nothing inside the window; report an older miss if there was one.
+		if start.After(cursor) {
+			if next, err := NextRun(sched.Trigger, cursor, site); err == nil && !next.After(start) {
+				return next, runSkipped
+			}
+		}
+		return time.Time{}, runNone
+	}
+
+	late := now.Sub(latest)
+	switch {
+	case late <= scheduleLateTolerance:
+		return latest, runOnTime
+	case sched.MissedRunPolicy == MissedRunOnce:
+		return latest, runCatchUp
+	default:
+		return latest, runSkipped
+	}
+}
+
+// sleepDuration returns how long the loop may sleep before the next run.
+func (s *Scheduler) sleepDuration(ctx context.Context) time.Duration {
+	site := s.siteInfo(ctx)
+	now := s.now()
+	sleep := schedulerMaxSleep
+
+	s.mu.RLock()
+	for id, sched := range s.schedules {
+		if !sched.Enabled {
+			continue
+		}
+		next, err := NextRun(sched.Trigger, s.cursors[id], site)
+		if err != nil {
+			continue
+		}
+		if d := next.Sub(now); d < sleep {
+			sleep = d
+		}
+	}
+	s.mu.RUnlock()
+
+	if sleep < 0 {
+		return 0
+	}
+	return sleep
+}
+
+// validate runs structural validation plus checks that need site context.
+func (s *Scheduler) validate(ctx context.Context, sched *Schedule) error {
+	if err := ValidateSchedule(sched); err != nil {
+		return err
+	}
+	if sched.Trigger.Type.IsSunTrigger() {
+		site := s.siteInfo(ctx)
+		if site.Latitude == nil || site.Longitude == nil {
+			return ErrSiteLocationRequired
+		}
+	}
+	return nil
+}
+
+// fillNextRun sets NextRunAt for an enabled schedule.
+func (s *Scheduler) fillNextRun(sched *Schedule, now time.Time, site SiteInfo) {
+	sched.NextRunAt = nil
+	if !sched.Enabled {
+		return
+	}
+	if next, err := NextRun(sched.Trigger, now, site); err == nil {
+		next = next.UTC()
+		sched.NextRunAt = &next
+	}
+}
+
+// siteInfo fetches the current site info, falling back to UTC without
+// coordinates if the provider is unavailable.
+func (s *Scheduler) siteInfo(ctx context.Context) SiteInfo {
+	if s.site == nil {
+		return SiteInfo{}
+	}
+	info, err := s.site.GetSiteInfo(ctx)
+	if err != nil {
+		s.logger.Warn("failed to load site info for scheduler", "error", err)
+		return SiteInfo{}
+	}
+	return info
+}
+
+// notify wakes the loop so it recomputes its sleep after a change.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.schedules)
+}
+
+// initialCursor returns the instant from which a freshly loaded schedule
+// is evaluated: its last run, or its last configuration change if later.
+func initialCursor(sched *Schedule) time.Time {
+	cursor := sched.UpdatedAt
+	if sched.LastRunAt != nil && sched.LastRunAt.After(cursor) {
+		cursor = *sched.LastRunAt
+	}
+	return cursor
+}
@@ -0,0 +1,466 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ─── Test doubles ───────────────────────────────────────────────────────────
+
+type mockScheduleRepo struct {
+	mu        sync.Mutex
+	schedules map[string]*Schedule
+}
+
+func newMockScheduleRepo() *mockScheduleRepo {
+	return &mockScheduleRepo{schedules: make(map[string]*Schedule)}
+}
+
+func (m *mockScheduleRepo) GetSchedule(_ context.Context, id string) (*Schedule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.schedules[id]
+	if !ok {
+		return nil, ErrScheduleNotFound
+	}
+	return s.DeepCopy(), nil
+}
+
+func (m *mockScheduleRepo) ListSchedules(_ context.Context) ([]Schedule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Schedule, 0, len(m.schedules))
+	for _, s := range m.schedules {
+		out = append(out, *s.DeepCopy())
+	}
+	return out, nil
+}
+
+func (m *mockScheduleRepo) CreateSchedule(_ context.Context, s *Schedule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.schedules[s.ID]; ok {
+		return ErrScheduleExists
+	}
+	m.schedules[s.ID] = s.DeepCopy()
+	return nil
+}
+
+func (m *mockScheduleRepo) UpdateSchedule(_ context.Context, s *Schedule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.schedules[s.ID]; !ok {
+		return ErrScheduleNotFound
+	}
+	m.schedules[s.ID] = s.DeepCopy()
+	return nil
+}
+
+func (m *mockScheduleRepo) DeleteSchedule(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.schedules[id]; !ok {
+		return ErrScheduleNotFound
+	}
+	delete(m.schedules, id)
+	return nil
+}
+
+func (m *mockScheduleRepo) UpdateScheduleLastRun(_ context.Context, id string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.schedules[id]
+	if !ok {
+		return ErrScheduleNotFound
+	}
+	s.LastRunAt = &at
+	return nil
+}
+
+type activation struct {
+	sceneID, triggerType, triggerSource string
+}
+
+type mockActivator struct {
+	mu    sync.Mutex
+	calls []activation
+	err   error
+}
+
+func (m *mockActivator) ActivateScene(_ context.Context, sceneID, triggerType, triggerSource string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, activation{sceneID, triggerType, triggerSource})
+	if m.err != nil {
+		return "", m.err
+	}
+	return "exec-1", nil
+}
+
+func (m *mockActivator) getCalls() []activation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]activation(nil), m.calls...)
+}
+
+type staticSite struct{ info SiteInfo }
+
+func (s staticSite) GetSiteInfo(context.Context) (SiteInfo, error) { return s.info, nil }
+
+// testClock is a settable clock for the scheduler.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+func setupScheduler(t *testing.T, now time.Time) (*Scheduler, *mockScheduleRepo, *mockActivator, *mockWSHub, *testClock) {
+	t.Helper()
+	repo := newMockScheduleRepo()
+	act := &mockActivator{}
+	hub := newMockWSHub()
+	clock := &testClock{now: now}
+	s := NewScheduler(repo, act, staticSite{SiteInfo{Timezone: "UTC"}}, hub, nil)
+	s.now = clock.Now
+	return s, repo, act, hub, clock
+}
+
+func dailySchedule(id, at string) *Schedule {
+	return &Schedule{
+		ID:      id,
+		Name:    "Schedule " + id,
+		Enabled: true,
+		Trigger: ScheduleTrigger{Type: TriggerTime, Value: at},
+		Execute: ScheduleExecute{Type: ExecuteScene, SceneID: "scene-" + id},
+	}
+}
+
+// ─── resolveDueRun ──────────────────────────────────────────────────────────
+
+func TestResolveDueRun(t *testing.T) {
+	day := func(h, m int) time.Time { return time.Date(2026, 3, 10, h, m, 0, 0, time.UTC) }
+	site := SiteInfo{Timezone: "UTC"}
+
+	tests := []struct {
+		name     string
+		policy   MissedRunPolicy
+		grace    int
+		cursor   time.Time
+		now      time.Time
+		wantAt   time.Time
+		decision runDecision
+	}{
+		{"nothing due", MissedRunSkip, 60, day(6, 0), day(6, 29), time.Time{}, runNone},
+		{"on time", MissedRunSkip, 60, day(6, 29), day(6, 30), day(6, 30), runOnTime},
+		{"slightly late", MissedRunSkip, 60, day(6, 29), day(6, 31), day(6, 30), runOnTime},
+		{"missed skip", MissedRunSkip, 60, day(5, 0), day(7, 0), day(6, 30), runSkipped},
+		{"missed run once", MissedRunOnce, 60, day(5, 0), day(7, 0), day(6, 30), runCatchUp},
+		{"missed beyond grace", MissedRunOnce, 15, day(5, 0), day(7, 0), day(6, 30), runSkipped},
+		{"already evaluated", MissedRunOnce, 60, day(6, 45), day(7, 0), time.Time{}, runNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched := dailySchedule("a", "06:30")
+			sched.MissedRunPolicy = tt.policy
+			sched.MissedRunGraceMinutes = tt.grace
+
+			at, decision := resolveDueRun(sched, tt.cursor, tt.now, site)
+			if decision != tt.decision {
+				t.Errorf("decision = %v, want %v", decision, tt.decision)
+			}
+			if !at.Equal(tt.wantAt) {
+				t.Errorf("at = %v, want %v", at, tt.wantAt)
+			}
+		})
+	}
+}
+
+// ─── Scheduler ──────────────────────────────────────────────────────────────
+
+func TestScheduler_TickFiresDueSchedule(t *testing.T) {
+	ctx := context.Background()
+	s, repo, act, hub, clock := setupScheduler(t, time.Date(2026, 3, 10, 6, 0, 0, 0, time.UTC))
+
+	sched := dailySchedule("morning", "06:30")
+	if err := s.CreateSchedule(ctx, sched); err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+	if sched.NextRunAt == nil || !sched.NextRunAt.Equal(time.Date(2026, 3, 10, 6, 30, 0, 0, time.UTC)) {
+		t.Errorf("NextRunAt = %v, want 06:30", sched.NextRunAt)
+	}
+
+	clock.Set(time.Date(2026, 3, 10, 6, 30, 5, 0, time.UTC))
+	s.tick(ctx)
+	s.wg.Wait()
+
+	calls := act.getCalls()
+	if len(calls) != 1 {
+		t.Fatalf("activations = %d, want 1", len(calls))
+	}
+	if calls[0].sceneID != "scene-morning" || calls[0].triggerType != "schedule" || calls[0].triggerSource != "schedule:morning" {
+		t.Errorf("activation = %+v", calls[0])
+	}
+
+	stored, _ := repo.GetSchedule(ctx, "morning")
+	if stored.LastRunAt == nil {
+		t.Error("last_run_at not persisted")
+	}
+
+	broadcasts := hub.getBroadcasts()
+	if len(broadcasts) != 1 || broadcasts[0].Channel != "schedule.triggered" {
+		t.Errorf("broadcasts = %+v, want one schedule.triggered", broadcasts)
+	}
+
+	// A second tick at the same instant must not fire again.
+	s.tick(ctx)
+	s.wg.Wait()
+	if len(act.getCalls()) != 1 {
+		t.Error("schedule fired twice for the same occurrence")
+	}
+}
+
+func TestScheduler_DisabledScheduleDoesNotFire(t *testing.T) {
+	ctx := context.Background()
+	s, _, act, _, clock := setupScheduler(t, time.Date(2026, 3, 10, 6, 0, 0, 0, time.UTC))
+
+	sched := dailySchedule("morning", "06:30")
+	sched.Enabled = false
+	if err := s.CreateSchedule(ctx, sched); err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+
+	clock.Set(time.Date(2026, 3, 10, 6, 30, 0, 0, time.UTC))
+	s.tick(ctx)
+	s.wg.Wait()
+	if len(act.getCalls()) != 0 {
+		t.Error("disabled schedule fired")
+	}
+
+	// Enabling after the run time must not catch up the missed run.
+	clock.Set(time.Date(2026, 3, 10, 6, 45, 0, 0, time.UTC))
+	if _, err := s.SetEnabled(ctx, "morning", true); err != nil {
+		t.Fatalf("SetEnabled: %v", err)
+	}
+	s.tick(ctx)
+	s.wg.Wait()
+	if len(act.getCalls()) != 0 {
+		t.Error("enabling caught up a run missed while disabled")
+	}
+}
+
+func TestScheduler_MissedRunAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	lastRun := time.Date(2026, 3, 9, 6, 30, 0, 0, time.UTC)
+	now := time.Date(2026, 3, 10, 7, 0, 0, 0, time.UTC) // 30 minutes after today's run
+
+	for _, tt := range []struct {
+		policy    MissedRunPolicy
+		wantFires int
+	}{
+		{MissedRunOnce, 1},
+		{MissedRunSkip, 0},
+	} {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			s, repo, act, _, _ := setupScheduler(t, now)
+
+			sched := dailySchedule("morning", "06:30")
+			sched.MissedRunPolicy = tt.policy
+			sched.MissedRunGraceMinutes = 60
+			sched.LastRunAt = &lastRun
+			sched.UpdatedAt = lastRun.Add(-24 * time.Hour)
+			repo.schedules[sched.ID] = sched
+
+			if err := s.RefreshCache(ctx); err != nil {
+				t.Fatalf("RefreshCache: %v", err)
+			}
+			s.tick(ctx)
+			s.wg.Wait()
+
+			if got := len(act.getCalls()); got != tt.wantFires {
+				t.Errorf("activations = %d, want %d", got, tt.wantFires)
+			}
+		})
+	}
+}
+
+func TestScheduler_CreateSunTriggerRequiresLocation(t *testing.T) {
+	s, _, _, _, _ := setupScheduler(t, time.Now())
+
+	sched := dailySchedule("dusk", "")
+	sched.Trigger = ScheduleTrigger{Type: TriggerSunset, Value: "-15m"}
+	if err := s.CreateSchedule(context.Background(), sched); !errors.Is(err, ErrSiteLocationRequired) {
+		t.Errorf("CreateSchedule() error = %v, want ErrSiteLocationRequired", err)
+	}
+}
+
+func TestScheduler_CRUD(t *testing.T) {
+	ctx := context.Background()
+	s, repo, _, _, _ := setupScheduler(t, time.Date(2026, 3, 10, 6, 0, 0, 0, time.UTC))
+
+	sched := &Schedule{
+		Name:    "Weekday Morning",
+		Enabled: true,
+		Trigger: ScheduleTrigger{Type: TriggerTime, Value: "06:30", Days: []string{"MON", "fri"}},
+		Execute: ScheduleExecute{SceneID: "scene-1"},
+	}
+	if err := s.CreateSchedule(ctx, sched); err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+	if sched.ID == "" || sched.Slug != "weekday-morning" {
+		t.Errorf("ID/slug not generated: %q %q", sched.ID, sched.Slug)
+	}
+	if sched.MissedRunPolicy != MissedRunSkip || sched.MissedRunGraceMinutes != defaultMissedRunGraceMinutes {
+		t.Errorf("defaults not applied: %+v", sched)
+	}
+	if sched.Trigger.Days[0] != "mon" {
+		t.Errorf("days not normalised: %v", sched.Trigger.Days)
+	}
+
+	if got := s.ListSchedules(ctx); len(got) != 1 {
+		t.Fatalf("ListSchedules() len = %d, want 1", len(got))
+	}
+
+	sched.Trigger.Value = "07:15"
+	if err := s.UpdateSchedule(ctx, sched); err != nil {
+		t.Fatalf("UpdateSchedule: %v", err)
+	}
+	stored, _ := repo.GetSchedule(ctx, sched.ID)
+	if stored.Trigger.Value != "07:15" {
+		t.Errorf("update not persisted: %q", stored.Trigger.Value)
+	}
+
+	runs, err := s.NextRuns(ctx, sched.ID, 3)
+	if err != nil {
+		t.Fatalf("NextRuns: %v", err)
+	}
+	want := []time.Time{
+		time.Date(2026, 3, 13, 7, 15, 0, 0, time.UTC), // Fri
+		time.Date(2026, 3, 16, 7, 15, 0, 0, time.UTC), // Mon
+		time.Date(2026, 3, 20, 7, 15, 0, 0, time.UTC), // Fri
+	}
+	for i := range want {
+		if !runs[i].Equal(want[i]) {
+			t.Errorf("run[%d] = %v, want %v", i, runs[i], want[i])
+		}
+	}
+
+	if err := s.DeleteSchedule(ctx, sched.ID); err != nil {
+		t.Fatalf("DeleteSchedule: %v", err)
+	}
+	if _, err := s.GetSchedule(ctx, sched.ID); !errors.Is(err, ErrScheduleNotFound) {
+		t.Errorf("GetSchedule after delete error = %v, want ErrScheduleNotFound", err)
+	}
+}
+
+func TestScheduler_StartStop(t *testing.T) {
+	s, _, _, _, _ := setupScheduler(t, time.Now())
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop() did not return")
+	}
+}
+
+// mockModeSetter records SetMode calls.
+type mockModeSetter struct {
+	mu    sync.Mutex
+	calls []string // "modeID source"
+}
+
+func (m *mockModeSetter) SetMode(_ context.Context, modeID, source, _ string) (*ModeChange, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, modeID+" "+source)
+	return &ModeChange{To: modeID, Source: source, Changed: true}, nil
+}
+
+func (m *mockModeSetter) getCalls() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.calls...)
+}
+
+func TestScheduler_ModeTarget(t *testing.T) {
+	ctx := context.Background()
+	s, _, act, hub, clock := setupScheduler(t, time.Date(2026, 3, 10, 22, 0, 0, 0, time.UTC))
+	modes := &mockModeSetter{}
+	s.SetModeSetter(modes)
+
+	sched := dailySchedule("bedtime", "23:00")
+	sched.Execute = ScheduleExecute{Type: ExecuteMode, ModeID: "night"}
+	if err := s.CreateSchedule(ctx, sched); err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+
+	clock.Set(time.Date(2026, 3, 10, 23, 0, 5, 0, time.UTC))
+	s.tick(ctx)
+	s.wg.Wait()
+
+	if calls := modes.getCalls(); len(calls) != 1 || calls[0] != "night schedule:bedtime" {
+		t.Errorf("SetMode calls = %v", calls)
+	}
+	if len(act.getCalls()) != 0 {
+		t.Error("mode schedule activated a scene")
+	}
+	broadcasts := hub.getBroadcasts()
+	if len(broadcasts) != 1 {
+		t.Fatalf("broadcasts = %d, want 1", len(broadcasts))
+	}
+	executed := broadcasts[0].Payload.(map[string]any)["executed"].(map[string]any)
+	if executed["mode_id"] != "night" {
+		t.Errorf("executed = %v", executed)
+	}
+}
+
+func TestScheduler_ConditionsSkipRun(t *testing.T) {
+	ctx := context.Background()
+	s, repo, act, hub, clock := setupScheduler(t, time.Date(2026, 3, 10, 6, 0, 0, 0, time.UTC))
+
+	// Tuesday: a weekend-only condition skips the run.
+	sched := dailySchedule("morning", "07:00")
+	sched.Conditions = []Condition{{Type: ConditionDayOfWeek, Days: []string{"sat", "sun"}}}
+	if err := s.CreateSchedule(ctx, sched); err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+	evaluator := NewConditionEvaluator(staticSite{SiteInfo{Timezone: "UTC"}}, nil)
+	evaluator.now = clock.Now
+	s.SetConditionEvaluator(evaluator)
+
+	clock.Set(time.Date(2026, 3, 10, 7, 0, 5, 0, time.UTC))
+	s.tick(ctx)
+	s.wg.Wait()
+
+	if len(act.getCalls()) != 0 {
+		t.Error("scene activated although conditions were not met")
+	}
+	if len(hub.getBroadcasts()) != 0 {
+		t.Error("schedule.triggered broadcast for a skipped run")
+	}
+	if got, _ := repo.GetSchedule(ctx, "morning"); got.LastRunAt == nil {
+		t.Error("skipped run was not recorded")
+	}
+}
@@ -0,0 +1,130 @@
+package automation
+
+import (
+	"math"
+	"time"
+)
+
+// SunEvent identifies an astronomical event used by schedule triggers.
+type SunEvent string
+
+const (
+	SunEventDawn      SunEvent = "dawn"       // Civil dawn: sun 6° below horizon, rising
+	SunEventSunrise   SunEvent = "sunrise"    // Upper limb crosses the horizon, rising
+	SunEventSolarNoon SunEvent = "solar_noon" // Sun at its highest point
+	SunEventSunset    SunEvent = "sunset"     // Upper limb crosses the horizon, setting
+	SunEventDusk      SunEvent = "dusk"       // Civil dusk: sun 6° below horizon, setting
+)
+
+// Zenith angles (degrees) for the supported horizon crossings.
+// 90.833° accounts for atmospheric refraction and the solar disc radius.
+const (
+	zenithOfficial = 90.833
+	zenithCivil    = 96.0
+)
+
+// SunEventTime calculates when an astronomical event occurs on the given
+// calendar day at the given position.
+//
+// The calendar day is taken from date's year/month/day in date's location,
+// so callers should pass a time already converted to the site timezone.
+// Uses the NOAA solar position equations, which are accurate to about a
+// minute between ±72° latitude.
+//
+// Returns:
+//   - time.Time: Event time in date's location
+//   - bool: false if the event does not occur that day (polar day/night)
+func SunEventTime(date time.Time, lat, lon float64, event SunEvent) (time.Time, bool) {
+	loc := date.Location()
+	y, m, d := date.Date()
+	midnightUTC := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	// Evaluate the equation of time and declination at local solar noon,
+	// which is close enough to every event of the day for minute accuracy.
+	eqTime, decl := solarParams(midnightUTC.Add(12*time.Hour - time.Duration(lon*4)*time.Minute))
+
+	var minutes float64
+	switch event {
+	case SunEventSolarNoon:
+		minutes = 720 - 4*lon - eqTime
+	case SunEventSunrise, SunEventSunset, SunEventDawn, SunEventDusk:
+		zenith := zenithOfficial
+		if event == SunEventDawn || event == SunEventDusk {
+			zenith = zenithCivil
+		}
+		ha, ok := hourAngle(lat, decl, zenith)
+		if !ok {
+			return time.Time{}, false
+		}
+		if event == SunEventSunrise || event == SunEventDawn {
+			minutes = 720 - 4*(lon+ha) - eqTime
+		} else {
+			minutes = 720 - 4*(lon-ha) - eqTime
+		}
+	default:
+		return time.Time{}, false
+	}
+
+	at := midnightUTC.Add(time.Duration(minutes * float64(time.Minute))).Truncate(time.Second)
+	return at.In(loc), true
+}
+
+// SunElevation returns the sun's elevation above the horizon in degrees at
+// instant t for the given position. Negative values mean the sun is below
+// the horizon. Refraction is not applied.
+func SunElevation(t time.Time, lat, lon float64) float64 {
+	utc := t.UTC()
+	eqTime, decl := solarParams(utc)
+
+	// True solar time in minutes, then hour angle in degrees.
+	minutesOfDay := float64(utc.Hour()*60+utc.Minute()) + float64(utc.Second())/60
+	tst := minutesOfDay + eqTime + 4*lon
+	ha := tst/4 - 180
+
+	latRad := degToRad(lat)
+	cosZenith := math.Sin(latRad)*math.Sin(decl) + math.Cos(latRad)*math.Cos(decl)*math.Cos(degToRad(ha))
+	cosZenith = math.Max(-1, math.Min(1, cosZenith))
+	return 90 - radToDeg(math.Acos(cosZenith))
+}
+
+// solarParams returns the equation of time (minutes) and solar declination
+// (radians) at instant t using the NOAA fractional-year approximation.
+func solarParams(t time.Time) (eqTime, decl float64) {
+	utc := t.UTC()
+	daysInYear := 365.0
+	if isLeapYear(utc.Year()) {
+		daysInYear = 366
+	}
+	hour := float64(utc.Hour()) + float64(utc.Minute())/60
+	gamma := 2 * math.Pi / daysInYear * (float64(utc.YearDay()-1) + (hour-12)/24)
+
+	eqTime = 229.18 * (0.000075 +
+		0.001868*math.Cos(gamma) - 0.032077*math.Sin(gamma) -
+		0.014615*math.Cos(2*gamma) - 0.040849*math.Sin(2*gamma))
+
+	decl = 0.006918 -
+		0.399912*math.Cos(gamma) + 0.070257*math.Sin(gamma) -
+		0.006758*math.Cos(2*gamma) + 0.000907*math.Sin(2*gamma) -
+		0.002697*math.Cos(3*gamma) + 0.00148*math.Sin(3*gamma)
+
+	return eqTime, decl
+}
+
+// hourAngle returns the hour angle (degrees) at which the sun reaches the
+// given zenith. Returns false when the sun never reaches it that day.
+func hourAngle(lat, decl, zenith float64) (float64, bool) {
+	latRad := degToRad(lat)
+	cosHA := math.Cos(degToRad(zenith))/(math.Cos(latRad)*math.Cos(decl)) - math.Tan(latRad)*math.Tan(decl)
+	if cosHA < -1 || cosHA > 1 {
+		return 0, false
+	}
+	return radToDeg(math.Acos(cosHA)), true
+}
+
+func isLeapYear(y int) bool {
+	return y%4 == 0 && (y%100 != 0 || y%400 == 0)
+}
+
+func degToRad(d float64) float64 { return d * math.Pi / 180 }
+
+func radToDeg(r float64) float64 { return r * 180 / math.Pi }
@@ -0,0 +1,110 @@
+package automation
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// London reference values from the NOAA solar calculator.
+const (
+	londonLat = 51.5074
+	londonLon = -0.1278
+)
+
+func TestSunEventTime_London(t *testing.T) {
+	tests := []struct {
+		name  string
+		date  time.Time
+		event SunEvent
+		want  time.Time
+	}{
+		{
+			name:  "summer solstice sunrise",
+			date:  time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC),
+			event: SunEventSunrise,
+			want:  time.Date(2026, 6, 21, 3, 43, 0, 0, time.UTC),
+		},
+		{
+			name:  "summer solstice sunset",
+			date:  time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC),
+			event: SunEventSunset,
+			want:  time.Date(2026, 6, 21, 20, 21, 0, 0, time.UTC),
+		},
+		{
+			name:  "winter solstice sunrise",
+			date:  time.Date(2026, 12, 21, 0, 0, 0, 0, time.UTC),
+			event: SunEventSunrise,
+			want:  time.Date(2026, 12, 21, 8, 4, 0, 0, time.UTC),
+		},
+		{
+			name:  "winter solstice solar noon",
+			date:  time.Date(2026, 12, 21, 0, 0, 0, 0, time.UTC),
+			event: SunEventSolarNoon,
+			want:  time.Date(2026, 12, 21, 11, 58, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SunEventTime(tt.date, londonLat, londonLon, tt.event)
+			if !ok {
+				t.Fatal("SunEventTime() returned ok=false")
+			}
+			if diff := got.Sub(tt.want); diff < -3*time.Minute || diff > 3*time.Minute {
+				t.Errorf("SunEventTime() = %v, want %v ±3m", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSunEventTime_Ordering(t *testing.T) {
+	date := time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)
+	var prev time.Time
+	for _, ev := range []SunEvent{SunEventDawn, SunEventSunrise, SunEventSolarNoon, SunEventSunset, SunEventDusk} {
+		at, ok := SunEventTime(date, londonLat, londonLon, ev)
+		if !ok {
+			t.Fatalf("%s: ok=false", ev)
+		}
+		if !at.After(prev) {
+			t.Errorf("%s at %v is not after previous event %v", ev, at, prev)
+		}
+		prev = at
+	}
+}
+
+func TestSunEventTime_UsesDateLocation(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	got, ok := SunEventTime(time.Date(2026, 6, 21, 23, 0, 0, 0, london), londonLat, londonLon, SunEventSunrise)
+	if !ok {
+		t.Fatal("ok=false")
+	}
+	if got.Location() != london || got.Day() != 21 {
+		t.Errorf("SunEventTime() = %v, want 21 June in Europe/London", got)
+	}
+}
+
+func TestSunEventTime_Polar(t *testing.T) {
+	// Tromsø in midsummer: the sun never sets.
+	_, ok := SunEventTime(time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), 69.65, 18.96, SunEventSunset)
+	if ok {
+		t.Error("expected no sunset during polar day")
+	}
+}
+
+func TestSunElevation(t *testing.T) {
+	noon, _ := SunEventTime(time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), londonLat, londonLon, SunEventSolarNoon)
+	elev := SunElevation(noon, londonLat, londonLon)
+	// Max elevation at the solstice ≈ 90 - 51.5 + 23.44 ≈ 61.9°
+	if math.Abs(elev-61.9) > 0.5 {
+		t.Errorf("SunElevation(solar noon) = %.2f, want ≈61.9", elev)
+	}
+
+	midnight := noon.Add(12 * time.Hour)
+	if e := SunElevation(midnight, londonLat, londonLon); e >= 0 {
+		t.Errorf("SunElevation(midnight) = %.2f, want negative", e)
+	}
+}
@@ -1,6 +1,9 @@
 package automation
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Scene represents a predefined collection of device actions that can be
 // activated together. Actions execute in parallel or sequentially based on
@@ -30,6 +33,9 @@ type Scene struct {
 	// Actions to execute (ordered)
 	Actions []SceneAction `json:"actions"`
 
+	// Conditions that must all pass for the scene to run (optional)
+	Conditions []Condition `json:"conditions,omitempty"`
+
 	// Sort order for UI display
 	SortOrder int `json:"sort_order"`
 
@@ -43,10 +49,29 @@ type Scene struct {
 // Actions are executed in sort order. When Parallel is true, the action
 // runs concurrently with the previous action's group. When false, it
 // starts a new sequential group.
+//
+// An action targets exactly one of: a device (DeviceID), a device group
+// (GroupID), a room or area (ScopeType/ScopeID), or a tag filter (Tags).
+// Group, scope and tag targets are resolved when the action runs, so
+// devices added later are included, and the command fans out to every
+// member.
 type SceneAction struct {
 	// Target device
 	DeviceID string `json:"device_id"`
 
+	// Target device group
+	GroupID string `json:"group_id,omitempty"`
+
+	// Target room or area
+	ScopeType string `json:"scope_type,omitempty"` // "room" or "area"
+	ScopeID   string `json:"scope_id,omitempty"`
+
+	// Target devices carrying any of these tags
+	Tags []string `json:"tags,omitempty"`
+
+	// Narrows a scope or tag target to these domains (e.g., ["lighting"])
+	Domains []string `json:"domains,omitempty"`
+
 	// Command to execute (e.g., "set", "dim", "position")
 	Command string `json:"command"`
 
@@ -94,14 +119,60 @@ type SceneExecution struct {
 }
 
 // ActionFailure records details of a failed action within an execution.
+// An action that fans out to several devices records one failure per
+// failed member, each naming the action's target.
 type ActionFailure struct {
 	ActionIndex int    `json:"action_index"`
 	DeviceID    string `json:"device_id"`
+	Target      string `json:"target,omitempty"` // e.g. "group:{id}", "room:{id}"; empty for device actions
 	Command     string `json:"command"`
 	ErrorCode   string `json:"error_code"`
 	ErrorMsg    string `json:"error_message"`
 }
 
+// Scope types for room and area action targets.
+const (
+	ScopeRoom = "room"
+	ScopeArea = "area"
+)
+
+// ActionTarget is a group, scope or tag target to be resolved to devices.
+type ActionTarget struct {
+	GroupID   string
+	ScopeType string
+	ScopeID   string
+	Tags      []string
+	Domains   []string
+}
+
+// IsDeviceTarget reports whether the action targets a single device.
+func (a SceneAction) IsDeviceTarget() bool {
+	return a.DeviceID != ""
+}
+
+// Target returns the action's group, scope or tag target.
+func (a SceneAction) Target() ActionTarget {
+	return ActionTarget{
+		GroupID:   a.GroupID,
+		ScopeType: a.ScopeType,
+		ScopeID:   a.ScopeID,
+		Tags:      a.Tags,
+		Domains:   a.Domains,
+	}
+}
+
+// String describes the target, e.g. "group:east-wing" or "tags:outdoor,security".
+func (t ActionTarget) String() string {
+	switch {
+	case t.GroupID != "":
+		return "group:" + t.GroupID
+	case t.ScopeType != "":
+		return t.ScopeType + ":" + t.ScopeID
+	default:
+		return "tags:" + strings.Join(t.Tags, ",")
+	}
+}
+
 // ExecutionStatus represents the state of a scene execution.
 type ExecutionStatus string
 
@@ -112,6 +183,10 @@ const (
 	StatusPartial   ExecutionStatus = "partial"   // Some actions failed, but scene continued
 	StatusFailed    ExecutionStatus = "failed"    // Critical action failed, scene aborted
 	StatusCancelled ExecutionStatus = "cancelled" // Context cancelled mid-execution
+
+	// StatusConditionsNotMet records an activation refused because the
+	// scene's conditions did not pass; no actions ran.
+	StatusConditionsNotMet ExecutionStatus = "conditions_not_met"
 )
 
 // Category represents a scene category for UI organisation.
@@ -163,8 +238,11 @@ func (s *Scene) DeepCopy() *Scene {
 			if action.Parameters != nil {
 				cpy.Actions[i].Parameters = deepCopyMap(action.Parameters)
 			}
+			cpy.Actions[i].Tags = cloneStrings(action.Tags)
+			cpy.Actions[i].Domains = cloneStrings(action.Domains)
 		}
 	}
+	cpy.Conditions = cloneConditions(s.Conditions)
 
 	return &cpy
 }
@@ -85,6 +85,10 @@ func ValidateScene(s *Scene) error {
 		}
 	}
 
+	if err := ValidateConditions(s.Conditions); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidScene, err)
+	}
+
 	return nil
 }
 
@@ -116,8 +120,8 @@ func ValidateSlug(slug string) error {
 
 // ValidateAction checks if a scene action is valid.
 func ValidateAction(action SceneAction) error {
-	if action.DeviceID == "" {
-		return fmt.Errorf("%w: device_id is required", ErrInvalidAction)
+	if err := validateActionTarget(action); err != nil {
+		return err
 	}
 	if action.Command == "" {
 		return fmt.Errorf("%w: command is required", ErrInvalidAction)
@@ -134,6 +138,45 @@ func ValidateAction(action SceneAction) error {
 	return nil
 }
 
+// validateActionTarget checks that the action has exactly one target.
+func validateActionTarget(action SceneAction) error {
+	targets := 0
+	for _, set := range []bool{
+		action.DeviceID != "",
+		action.GroupID != "",
+		action.ScopeType != "" || action.ScopeID != "",
+		len(action.Tags) > 0,
+	} {
+		if set {
+			targets++
+		}
+	}
+	switch {
+	case targets == 0:
+		return fmt.Errorf("%w: one of device_id, group_id, scope_type or tags is required", ErrInvalidAction)
+	case targets > 1:
+		return fmt.Errorf("%w: device_id, group_id, scope_type and tags are mutually exclusive", ErrInvalidAction)
+	}
+
+	if action.ScopeType != "" || action.ScopeID != "" {
+		if action.ScopeType != ScopeRoom && action.ScopeType != ScopeArea {
+			return fmt.Errorf("%w: scope_type must be %q or %q", ErrInvalidAction, ScopeRoom, ScopeArea)
+		}
+		if action.ScopeID == "" {
+			return fmt.Errorf("%w: scope_id is required", ErrInvalidAction)
+		}
+	}
+	for _, tag := range action.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("%w: tags cannot be empty", ErrInvalidAction)
+		}
+	}
+	if len(action.Domains) > 0 && action.ScopeType == "" && len(action.Tags) == 0 {
+		return fmt.Errorf("%w: domains only apply to scope and tag targets", ErrInvalidAction)
+	}
+	return nil
+}
+
 // GenerateSlug creates a URL-safe slug from a name.
 // It lowercases, replaces spaces/underscores with hyphens, removes
 // non-alphanumeric characters, and trims to maxSlugLength.
@@ -33,6 +33,16 @@ func TestValidateScene(t *testing.T) {
 			scene:   nil,
 			wantErr: ErrInvalidScene,
 		},
+		{
+			name: "invalid condition",
+			scene: &Scene{
+				Name:       "Cinema Mode",
+				Priority:   50,
+				Actions:    []SceneAction{validAction},
+				Conditions: []Condition{{Type: ConditionTimeWindow, After: "late"}},
+			},
+			wantErr: ErrInvalidCondition,
+		},
 		{
 			name: "empty name",
 			scene: &Scene{
@@ -278,6 +288,41 @@ func TestValidateAction(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name:    "group target",
+			action:  SceneAction{GroupID: "east-wing", Command: "off"},
+			wantErr: nil,
+		},
+		{
+			name:    "area target with domain filter",
+			action:  SceneAction{ScopeType: ScopeArea, ScopeID: "east-wing", Domains: []string{"lighting"}, Command: "off"},
+			wantErr: nil,
+		},
+		{
+			name:    "tag target",
+			action:  SceneAction{Tags: []string{"outdoor"}, Command: "on"},
+			wantErr: nil,
+		},
+		{
+			name:    "two targets",
+			action:  SceneAction{DeviceID: "light-01", GroupID: "east-wing", Command: "off"},
+			wantErr: ErrInvalidAction,
+		},
+		{
+			name:    "unknown scope type",
+			action:  SceneAction{ScopeType: "floor", ScopeID: "f1", Command: "off"},
+			wantErr: ErrInvalidAction,
+		},
+		{
+			name:    "scope without id",
+			action:  SceneAction{ScopeType: ScopeRoom, Command: "off"},
+			wantErr: ErrInvalidAction,
+		},
+		{
+			name:    "domains on device target",
+			action:  SceneAction{DeviceID: "light-01", Domains: []string{"lighting"}, Command: "off"},
+			wantErr: ErrInvalidAction,
+		},
 	}
 
 	for _, tt := range tests {
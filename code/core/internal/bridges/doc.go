@@ -0,0 +1,14 @@
+// Package bridges aggregates the health of protocol bridges for Gray Logic Core.
+//
+// Each bridge (KNX today; DALI and Modbus later) publishes a retained health
+// report on graylogic/health/{protocol} every 30 seconds, and registers the
+// same topic as its MQTT last will with status "offline". The Registry keeps
+// the latest report per bridge and notices bridges that go quiet.
+//
+// Every status change is broadcast as a "bridge.status" WebSocket event.
+// When a bridge stops serving its devices (offline, stopping, or silent for
+// three report intervals) the devices on its protocol are marked health
+// "unknown", since Core can no longer observe them.
+//
+// The protocol-specific bridges themselves live in subpackages (bridges/knx).
+package bridges
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"math"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -14,7 +17,9 @@ const (
 	// minTopicParts is the minimum number of parts in a valid MQTT topic.
 	minTopicParts = 3
 
-	// commandTimeout is the timeout for sending commands to devices.
+	// commandTimeout is the timeout for sending commands to devices,
+	// including the wait in the outgoing queue. Core's command timeout
+	// (automation.DefaultCommandTimeout) must stay well above it.
 	commandTimeout = 5 * time.Second
 
 	// readAllTimeout is the timeout for reading all device states.
@@ -22,6 +27,17 @@ const (
 
 	// interReadDelay is the delay between read requests to avoid bus flooding.
 	interReadDelay = 50 * time.Millisecond
+
+	// stateFlushInterval is how often changed state cache values are
+	// written to the state store.
+	stateFlushInterval = 5 * time.Second
+
+	// stateFlushTimeout bounds the final state cache flush on Stop.
+	stateFlushTimeout = 5 * time.Second
+
+	// colourTempMin and colourTempMax bound set_color_temp in Kelvin.
+	colourTempMin = 1000
+	colourTempMax = 10000
 )
 
 // Bridge orchestrates bidirectional translation between KNX and MQTT.
@@ -36,18 +52,29 @@ type Bridge struct {
 	mqtt       MQTTClient
 	knxd       Connector
 	health     *HealthReporter
+	timeMaster *TimeMaster         // Optional bus time master (nil if disabled)
 	registry   DeviceRegistry      // Optional device registry for state/health persistence
 	gaRecorder GARecorderInterface // Optional GA recorder for passive discovery
+	monitor    *BusMonitor         // Optional bus monitor (nil if disabled)
+	scheduler  *telegramScheduler  // Optional outgoing queue (nil if disabled)
+	stats      *BusStats           // Optional bus traffic statistics (nil if disabled)
+	poller     *poller             // Periodic reads of polled functions
+	buffer     *commandBuffer      // Optional commands held while disconnected (nil if disabled)
 
 	// Device mappings (built from config)
 	gaToDevice        map[string][]GAMapping
 	deviceToGAs       map[string]map[string]AddressConfig
 	infrastructureIDs map[string]bool // device IDs with domain "infrastructure"
+	dptConflicts      []DPTConflict   // GAs mapped with incompatible DPTs
 	mappingMu         sync.RWMutex
 
-	// State cache for change detection
-	stateCache   map[string]map[string]any
+	// State cache for change detection, persisted by stateStore if set.
+	// Changed values are marked in stateDirty and written in batches.
+	stateCache   map[string]map[string]CachedValue
+	stateDirty   map[string]map[string]struct{}
 	stateCacheMu sync.RWMutex
+	stateStore   StateStore
+	stateFlushMu sync.Mutex // Serialises writes to stateStore
 
 	// Shutdown coordination
 	done      chan struct{}
@@ -96,6 +123,27 @@ type DeviceRegistry interface {
 	GetKNXDevices(ctx context.Context) ([]RegistryDevice, error)
 }
 
+// CachedValue is the last known value of a device function.
+type CachedValue struct {
+	Value     any       `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"` // When the bus or a command last set it
+	Stale     bool      `json:"stale"`      // Restored at startup, not yet confirmed by the bus
+}
+
+// StateStore persists the bridge state cache across restarts.
+// This interface is satisfied by *StateCacheStore (SQLite).
+// It is optional - if nil, the cache starts from the registry's state.
+type StateStore interface {
+	// LoadStates returns the stored values by device ID and function.
+	LoadStates(ctx context.Context) (map[string]map[string]CachedValue, error)
+
+	// SaveStates stores values by device ID and function in one batch.
+	SaveStates(ctx context.Context, states map[string]map[string]CachedValue) error
+
+	// DeleteDevice removes the stored values of a device.
+	DeleteDevice(ctx context.Context, deviceID string) error
+}
+
 // GARecorderInterface records telegrams seen on the bus for passive discovery.
 // This is optional - if nil, the bridge operates without recording.
 type GARecorderInterface interface {
@@ -115,13 +163,20 @@ type RegistryDevice struct {
 	Domain       string
 	Functions    map[string]FunctionMapping // function -> {GA, DPT, Flags}
 	Capabilities []string
+
+	// State is the last known state (state key -> value) and when it was
+	// last updated, used to seed the state cache at startup.
+	State          map[string]any
+	StateUpdatedAt time.Time
 }
 
-// FunctionMapping holds the GA, DPT, and flags for a single device function.
+// FunctionMapping holds the GA, DPT, flags and poll interval for a single
+// device function.
 type FunctionMapping struct {
-	GA    string
-	DPT   string
-	Flags []string
+	GA           string
+	DPT          string
+	Flags        []string
+	PollInterval int // Seconds; 0 uses the polling default for the DPT
 }
 
 // DeviceSeed holds device fields derivable from bridge config.
@@ -145,7 +200,7 @@ type BridgeOptions struct {
 	// MQTTClient is the MQTT client implementation.
 	MQTTClient MQTTClient
 
-	// KNXDClient is the knxd connection.
+	// KNXDClient is the bus connection (knxd or a KNXnet/IP tunnel).
 	KNXDClient Connector
 
 	// Logger is optional structured logger.
@@ -158,6 +213,14 @@ type BridgeOptions struct {
 	// GARecorder is optional GA recorder for passive discovery.
 	// If nil, the bridge operates without recording seen GAs.
 	GARecorder GARecorderInterface
+
+	// Timezone is optional site timezone source for the time master.
+	// If nil, the time master uses time_master.timezone from config.
+	Timezone TimezoneProvider
+
+	// StateStore is optional persistence for the state cache.
+	// If nil, the cache is seeded only from the registry's state.
+	StateStore StateStore
 }
 
 // NewBridge creates a new bridge instance.
@@ -179,19 +242,56 @@ func NewBridge(opts BridgeOptions) (*Bridge, error) {
 	b := &Bridge{
 		cfg:               opts.Config,
 		mqtt:              opts.MQTTClient,
-		knxd:              opts.KNXDClient,
 		registry:          opts.Registry,   // May be nil (optional)
 		gaRecorder:        opts.GARecorder, // May be nil (optional)
 		gaToDevice:        make(map[string][]GAMapping),
 		deviceToGAs:       make(map[string]map[string]AddressConfig),
 		infrastructureIDs: make(map[string]bool),
-		stateCache:        make(map[string]map[string]any),
+		stateCache:        make(map[string]map[string]CachedValue),
+		stateDirty:        make(map[string]map[string]struct{}),
+		stateStore:        opts.StateStore, // May be nil (optional)
 		done:              make(chan struct{}),
 		ctx:               ctx,
 		ctxCancel:         ctxCancel,
 		logger:            opts.Logger,
 	}
 
+	// Record sent telegrams in the bus monitor and statistics as they go out
+	// (received ones are recorded in handleKNXTelegram). The monitor sits
+	// under the scheduler, so only telegrams actually sent are recorded:
+	// not each coalesced write, nor telegrams dropped from the queue.
+	b.knxd = opts.KNXDClient
+	if opts.Config.Monitor.Size > 0 {
+		b.monitor = NewBusMonitor(opts.Config.Monitor.Size)
+	}
+	if opts.Config.Stats.Window > 0 {
+		b.stats = NewBusStats(opts.Config.Stats)
+	}
+	if b.monitor != nil || b.stats != nil {
+		b.knxd = &monitoredConnector{Connector: b.knxd, record: b.recordTelegram}
+	}
+
+	// Pace outgoing telegrams through the priority queue
+	if opts.Config.Scheduler.Rate > 0 {
+		b.scheduler = newTelegramScheduler(b.knxd, opts.Config.Scheduler.Rate, opts.Config.Scheduler.QueueSize)
+		b.knxd = b.scheduler
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.scheduler.run(b.ctx)
+		}()
+	}
+
+	// Poll functions with a poll interval through the paced send path
+	b.poller = newPoller(opts.Config.Polling.MaxFailures, func(ctx context.Context, ga GroupAddress) error {
+		return b.knxd.SendRead(ctx, ga)
+	}, b.updatePollHealth)
+
+	// Hold commands while knxd is disconnected
+	if opts.Config.Buffer.Size > 0 {
+		b.buffer = newCommandBuffer(opts.Config.Buffer.Size, time.Duration(opts.Config.Buffer.TTL)*time.Second)
+	}
+
 	// Create health reporter
 	b.health = NewHealthReporter(HealthReporterConfig{
 		BridgeID:   opts.Config.Bridge.ID,
@@ -205,6 +305,21 @@ func NewBridge(opts BridgeOptions) (*Bridge, error) {
 		b.health.SetLogger(opts.Logger)
 	}
 
+	// Create time master (if enabled)
+	if opts.Config.TimeMaster.Enabled {
+		tm, err := NewTimeMaster(TimeMasterConfig{
+			Settings:   opts.Config.TimeMaster,
+			KNXDClient: b.knxd,
+			Timezone:   opts.Timezone,
+			Logger:     opts.Logger,
+		})
+		if err != nil {
+			ctxCancel()
+			return nil, fmt.Errorf("creating time master: %w", err)
+		}
+		b.timeMaster = tm
+	}
+
 	return b, nil
 }
 
@@ -212,6 +327,9 @@ func NewBridge(opts BridgeOptions) (*Bridge, error) {
 // This subscribes to MQTT topics, sets up the KNX telegram handler,
 // and starts health reporting.
 func (b *Bridge) Start(ctx context.Context) error {
+	// Restore the state cache persisted before the last shutdown
+	b.restoreStateCache(ctx)
+
 	// Load devices from registry (sole source of device mappings)
 	b.loadDevicesFromRegistry(ctx)
 
@@ -240,6 +358,45 @@ func (b *Bridge) Start(ctx context.Context) error {
 	// Start health reporting
 	b.health.Start(ctx)
 
+	// Start polling functions that do not transmit on change
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.poller.run(b.ctx)
+	}()
+
+	// Start replaying commands held while knxd was disconnected
+	if b.buffer != nil {
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.runCommandBuffer(b.ctx)
+		}()
+	}
+
+	// Start writing state cache changes to the store
+	if b.stateStore != nil {
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.runStateFlush(b.ctx)
+		}()
+	}
+
+	// Start sampling bus statistics
+	if b.stats != nil {
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.stats.run(b.ctx)
+		}()
+	}
+
+	// Start broadcasting date/time to the bus (sends immediately)
+	if b.timeMaster != nil {
+		b.timeMaster.Start(WithPriority(b.ctx, PriorityBulk))
+	}
+
 	// Publish initial healthy status
 	if err := b.health.PublishNow(); err != nil {
 		b.logError("failed to publish healthy status", err)
@@ -270,12 +427,21 @@ func (b *Bridge) Stop() {
 		// Cancel bridge context to abort in-flight commands
 		b.ctxCancel()
 
+		if b.timeMaster != nil {
+			b.timeMaster.Stop()
+		}
+
 		// Stop health reporting (publishes "stopping" status)
 		b.health.Stop()
 
 		// Wait for pending operations
 		b.wg.Wait()
 
+		// Write the state cache changes since the last flush
+		flushCtx, cancel := context.WithTimeout(context.Background(), stateFlushTimeout)
+		b.flushStateCache(flushCtx)
+		cancel()
+
 		b.logInfo("bridge stopped")
 	})
 }
@@ -283,6 +449,10 @@ func (b *Bridge) Stop() {
 // loadDevicesFromRegistry loads KNX devices from the device registry and
 // builds the bridge's device mappings. The registry is the sole source of
 // device→GA mappings — devices are created via ETS import or the admin panel.
+//
+// Mappings are rebuilt from scratch on every load, so devices removed or
+// re-addressed since the last load do not linger. Group addresses mapped
+// with incompatible DPTs are logged and kept for DPTConflicts.
 func (b *Bridge) loadDevicesFromRegistry(ctx context.Context) { //nolint:gocognit // device loading: iterates devices, builds address maps
 	if b.registry == nil {
 		return
@@ -298,10 +468,10 @@ func (b *Bridge) loadDevicesFromRegistry(ctx context.Context) { //nolint:gocogni
 		return
 	}
 
-	b.mappingMu.Lock()
-	defer b.mappingMu.Unlock()
+	gaToDevice := make(map[string][]GAMapping)
+	deviceToGAs := make(map[string]map[string]AddressConfig)
+	infrastructureIDs := make(map[string]bool)
 
-	loaded := 0
 	for _, dev := range devices {
 		// Convert registry device functions to address configs.
 		// Uses stored DPT and flags from the registry, falling back to
@@ -322,9 +492,10 @@ func (b *Bridge) loadDevicesFromRegistry(ctx context.Context) { //nolint:gocogni
 			}
 
 			addresses[fn] = AddressConfig{
-				GA:    fm.GA,
-				DPT:   dpt,
-				Flags: flags,
+				GA:           fm.GA,
+				DPT:          dpt,
+				Flags:        flags,
+				PollInterval: fm.PollInterval,
 			}
 		}
 
@@ -333,29 +504,57 @@ func (b *Bridge) loadDevicesFromRegistry(ctx context.Context) { //nolint:gocogni
 		}
 
 		// Build GA mappings (one GA may map to multiple devices)
-		b.deviceToGAs[dev.ID] = addresses
+		deviceToGAs[dev.ID] = addresses
 		for fn, addr := range addresses {
-			b.gaToDevice[addr.GA] = append(b.gaToDevice[addr.GA], GAMapping{
+			gaToDevice[addr.GA] = append(gaToDevice[addr.GA], GAMapping{
 				DeviceID: dev.ID,
 				Function: fn,
 				Type:     dev.Type,
 				DPT:      addr.DPT,
+				Command:  addr.HasFlag("write"),
 			})
 		}
 
 		// Track infrastructure devices for periodic polling
 		if dev.Domain == "infrastructure" {
-			b.infrastructureIDs[dev.ID] = true
+			infrastructureIDs[dev.ID] = true
 		}
-
-		loaded++
 	}
 
-	if loaded > 0 {
-		b.logInfo("loaded devices from registry", "count", loaded)
+	conflicts := findDPTConflicts(gaToDevice)
+
+	b.mappingMu.Lock()
+	b.gaToDevice = gaToDevice
+	b.deviceToGAs = deviceToGAs
+	b.infrastructureIDs = infrastructureIDs
+	b.dptConflicts = conflicts
+	b.mappingMu.Unlock()
+
+	b.updatePollTargets()
+	b.seedStateCache(devices, deviceToGAs)
+
+	if len(deviceToGAs) > 0 {
+		b.logInfo("loaded devices from registry", "count", len(deviceToGAs))
+	}
+	for _, c := range conflicts {
+		b.logWarn("group address has conflicting DPTs",
+			"ga", c.GA,
+			"dpts", strings.Join(c.DPTs, ","),
+			"mappings", len(c.Mappings))
 	}
 	// Update health reporter with total device count from maps
-	b.health.SetDeviceCount(len(b.deviceToGAs))
+	b.health.SetDeviceCount(len(deviceToGAs))
+}
+
+// DPTConflicts returns the group addresses that devices map with
+// incompatible DPTs, as found at the last device load.
+func (b *Bridge) DPTConflicts() []DPTConflict {
+	b.mappingMu.RLock()
+	defer b.mappingMu.RUnlock()
+
+	out := make([]DPTConflict, len(b.dptConflicts))
+	copy(out, b.dptConflicts)
+	return out
 }
 
 // ReloadDevices reloads device mappings from the registry.
@@ -374,7 +573,7 @@ func (b *Bridge) ReloadDevices(ctx context.Context) {
 // readAllDevices sends KNX read requests for all readable GAs across all
 // devices. Used after import/reload to populate initial state values.
 func (b *Bridge) readAllDevices(ctx context.Context) {
-	readCtx, cancel := context.WithTimeout(ctx, readAllTimeout)
+	readCtx, cancel := context.WithTimeout(WithPriority(ctx, PriorityBackground), readAllTimeout)
 	defer cancel()
 
 	b.mappingMu.RLock()
@@ -514,6 +713,14 @@ func (b *Bridge) handleCommand(payload []byte) {
 		return
 	}
 
+	// Hold the command while knxd is disconnected, and behind commands
+	// still held so that they do not overwrite it when replayed. Reads
+	// are not held: their answer would arrive long after it was wanted.
+	if b.buffer != nil && cmd.Command != "read_function" && (!b.knxd.IsConnected() || b.buffer.pending()) {
+		b.bufferCommand(cmd, deviceGAs)
+		return
+	}
+
 	// Execute command based on type
 	err := b.executeCommand(cmd, deviceGAs)
 
@@ -526,10 +733,95 @@ func (b *Bridge) handleCommand(payload []byte) {
 	// Success - ack already sent by executeCommand
 }
 
+// bufferCommand holds a command until knxd reconnects and acknowledges it
+// as queued. A held command for the same group address is replaced and
+// failed.
+func (b *Bridge) bufferCommand(cmd CommandMessage, deviceGAs map[string]AddressConfig) {
+	superseded, ok := b.buffer.add(commandKey(cmd, deviceGAs), cmd, time.Now())
+	if !ok {
+		b.publishAckError(cmd, "", ErrCodeDeviceUnreachable,
+			"knxd disconnected and command buffer full", 0)
+		return
+	}
+	if superseded != nil {
+		b.publishAckError(*superseded, "", ErrCodeBridgeError,
+			fmt.Sprintf("superseded by command %s", cmd.ID), 0)
+	}
+
+	b.logInfo("knxd disconnected, command held",
+		"command_id", cmd.ID,
+		"device_id", cmd.DeviceID,
+		"command", cmd.Command)
+	b.publishAck(cmd, "", AckQueued)
+}
+
+// runCommandBuffer checks held commands every bufferCheckInterval until
+// ctx is cancelled.
+func (b *Bridge) runCommandBuffer(ctx context.Context) {
+	ticker := time.NewTicker(bufferCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.checkCommandBuffer(time.Now())
+		}
+	}
+}
+
+// checkCommandBuffer times out expired held commands and replays the rest
+// if knxd is connected. While knxd stays disconnected, held commands are
+// acknowledged as queued again so Core keeps waiting for them.
+func (b *Bridge) checkCommandBuffer(now time.Time) {
+	for _, cmd := range b.buffer.expire(now) {
+		b.publishAckError(cmd, "", ErrCodeTimeout,
+			fmt.Sprintf("knxd still disconnected after %ds", b.cfg.Buffer.TTL), 0)
+	}
+
+	if !b.knxd.IsConnected() {
+		for _, cmd := range b.buffer.held() {
+			b.publishAck(cmd, "", AckQueued)
+		}
+		return
+	}
+	b.replayCommands()
+}
+
+// replayCommands sends the held commands in arrival order, one at a time
+// so that commands arriving meanwhile queue behind them. It stops if knxd
+// disconnects again.
+func (b *Bridge) replayCommands() {
+	replayed := 0
+	for b.knxd.IsConnected() {
+		cmd, ok := b.buffer.next()
+		if !ok {
+			break
+		}
+		replayed++
+
+		// The device may have been removed or remapped meanwhile
+		b.mappingMu.RLock()
+		deviceGAs, ok := b.deviceToGAs[cmd.DeviceID]
+		b.mappingMu.RUnlock()
+		if !ok {
+			b.publishAckError(cmd, "", ErrCodeNotConfigured,
+				fmt.Sprintf("device %s not configured", cmd.DeviceID), 0)
+		} else if err := b.executeCommand(cmd, deviceGAs); err != nil {
+			b.logError("held command execution failed", err)
+		}
+		b.buffer.done()
+	}
+	if replayed > 0 {
+		b.logInfo("replayed held commands", "count", replayed)
+	}
+}
+
 // executeCommand translates and sends a command to the KNX bus.
 func (b *Bridge) executeCommand(cmd CommandMessage, deviceGAs map[string]AddressConfig) error {
 	// Derive timeout from bridge context so commands are cancelled on shutdown
-	ctx, cancel := context.WithTimeout(b.ctx, commandTimeout)
+	ctx, cancel := context.WithTimeout(WithPriority(b.ctx, commandPriority(cmd)), commandTimeout)
 	defer cancel()
 
 	switch cmd.Command {
@@ -545,6 +837,22 @@ func (b *Bridge) executeCommand(cmd CommandMessage, deviceGAs map[string]Address
 		return b.executeStop(ctx, cmd, deviceGAs)
 	case "set_setpoint":
 		return b.executeSetSetpoint(ctx, cmd, deviceGAs)
+	case "set_color_temp":
+		return b.executeSetColourTemp(ctx, cmd, deviceGAs)
+	case "set_rgb":
+		return b.executeSetRGB(ctx, cmd, deviceGAs)
+	case "set_rgbw":
+		return b.executeSetRGBW(ctx, cmd, deviceGAs)
+	case "set_hsv":
+		return b.executeSetHSV(ctx, cmd, deviceGAs)
+	case "set_hvac_mode":
+		return b.executeSetHVACMode(ctx, cmd, deviceGAs)
+	case "shift_setpoint":
+		return b.executeShiftSetpoint(ctx, cmd, deviceGAs)
+	case "write_function":
+		return b.executeWriteFunction(ctx, cmd, deviceGAs)
+	case "read_function":
+		return b.executeReadFunction(ctx, cmd, deviceGAs)
 	default:
 		b.publishAckError(cmd, "", ErrCodeInvalidCommand,
 			fmt.Sprintf("unknown command: %s", cmd.Command), 0)
@@ -552,6 +860,14 @@ func (b *Bridge) executeCommand(cmd CommandMessage, deviceGAs map[string]Address
 	}
 }
 
+// commandPriority queues scene actions behind commands a user is waiting on.
+func commandPriority(cmd CommandMessage) Priority {
+	if strings.HasPrefix(cmd.Source, "scene") {
+		return PriorityBulk
+	}
+	return PriorityInteractive
+}
+
 // resolveFunction finds the address config for a command by checking:
 //  1. An explicit "function" parameter (e.g. "ch_a_switch" for multi-channel actuators)
 //  2. The canonical function names provided as fallbacks
@@ -596,9 +912,6 @@ func (b *Bridge) executeOnOff(ctx context.Context, cmd CommandMessage, deviceGAs
 	// Encode DPT 1.001
 	data := EncodeDPT1(on)
 
-	// Publish accepted ack before sending
-	b.publishAck(cmd, addr.GA, AckAccepted)
-
 	// Send to KNX bus
 	if err := b.knxd.Send(ctx, ga, data); err != nil {
 		b.publishAckError(cmd, addr.GA, ErrCodeDeviceUnreachable,
@@ -606,6 +919,9 @@ func (b *Bridge) executeOnOff(ctx context.Context, cmd CommandMessage, deviceGAs
 		return err
 	}
 
+	// Acknowledge once the telegram is sent
+	b.publishAck(cmd, addr.GA, AckAccepted)
+
 	// Write-through: publish confirmed state immediately so the UI
 	// updates without waiting for the device echo.
 	b.publishWriteThrough(cmd.DeviceID, addr.GA, fnName, on)
@@ -655,9 +971,6 @@ func (b *Bridge) executeDim(ctx context.Context, cmd CommandMessage, deviceGAs m
 	// Encode DPT 5.001 (0-100% → 0-255)
 	data := EncodeDPT5(level)
 
-	// Publish accepted ack before sending
-	b.publishAck(cmd, addr.GA, AckAccepted)
-
 	// Send to KNX bus
 	if err := b.knxd.Send(ctx, ga, data); err != nil {
 		b.publishAckError(cmd, addr.GA, ErrCodeDeviceUnreachable,
@@ -665,6 +978,9 @@ func (b *Bridge) executeDim(ctx context.Context, cmd CommandMessage, deviceGAs m
 		return err
 	}
 
+	// Acknowledge once the telegram is sent
+	b.publishAck(cmd, addr.GA, AckAccepted)
+
 	// Write-through: publish confirmed brightness so the UI updates immediately.
 	b.publishWriteThrough(cmd.DeviceID, addr.GA, fnName, level)
 
@@ -713,9 +1029,6 @@ func (b *Bridge) executeSetPosition(ctx context.Context, cmd CommandMessage, dev
 	// Encode DPT 5.001 (0-100%)
 	data := EncodeDPT5(position)
 
-	// Publish accepted ack before sending
-	b.publishAck(cmd, addr.GA, AckAccepted)
-
 	// Send to KNX bus
 	if err := b.knxd.Send(ctx, ga, data); err != nil {
 		b.publishAckError(cmd, addr.GA, ErrCodeDeviceUnreachable,
@@ -723,6 +1036,9 @@ func (b *Bridge) executeSetPosition(ctx context.Context, cmd CommandMessage, dev
 		return err
 	}
 
+	// Acknowledge once the telegram is sent
+	b.publishAck(cmd, addr.GA, AckAccepted)
+
 	// Write-through: publish confirmed position so the UI updates immediately.
 	b.publishWriteThrough(cmd.DeviceID, addr.GA, fnName, position)
 
@@ -749,9 +1065,6 @@ func (b *Bridge) executeStop(ctx context.Context, cmd CommandMessage, deviceGAs
 	// Encode stop command (DPT 1.007: 1 = stop)
 	data := EncodeDPT1(true)
 
-	// Publish accepted ack before sending
-	b.publishAck(cmd, addr.GA, AckAccepted)
-
 	// Send to KNX bus
 	if err := b.knxd.Send(ctx, ga, data); err != nil {
 		b.publishAckError(cmd, addr.GA, ErrCodeDeviceUnreachable,
@@ -759,6 +1072,9 @@ func (b *Bridge) executeStop(ctx context.Context, cmd CommandMessage, deviceGAs
 		return err
 	}
 
+	// Acknowledge once the telegram is sent
+	b.publishAck(cmd, addr.GA, AckAccepted)
+
 	return nil
 }
 
@@ -809,9 +1125,6 @@ func (b *Bridge) executeSetSetpoint(ctx context.Context, cmd CommandMessage, dev
 		return err
 	}
 
-	// Publish accepted ack before sending
-	b.publishAck(cmd, addr.GA, AckAccepted)
-
 	// Send to KNX bus
 	if err := b.knxd.Send(ctx, ga, data); err != nil {
 		b.publishAckError(cmd, addr.GA, ErrCodeDeviceUnreachable,
@@ -819,6 +1132,9 @@ func (b *Bridge) executeSetSetpoint(ctx context.Context, cmd CommandMessage, dev
 		return err
 	}
 
+	// Acknowledge once the telegram is sent
+	b.publishAck(cmd, addr.GA, AckAccepted)
+
 	// Write-through: publish the setpoint state immediately.
 	// Many KNX thermostats echo the written setpoint back as a response
 	// telegram, but not all do (and simulators often don't).  Publishing
@@ -828,6 +1144,298 @@ func (b *Bridge) executeSetSetpoint(ctx context.Context, cmd CommandMessage, dev
 	return nil
 }
 
+// executeSetColourTemp sends a colour temperature command (DPT 7.600).
+func (b *Bridge) executeSetColourTemp(ctx context.Context, cmd CommandMessage, deviceGAs map[string]AddressConfig) error {
+	kelvin, err := b.numberParam(cmd, "color_temp", colourTempMin, colourTempMax)
+	if err != nil {
+		return err
+	}
+
+	addr, fnName, ok := resolveFunction(cmd.Parameters, deviceGAs, "color_temperature")
+	if !ok {
+		b.publishAckError(cmd, "", ErrCodeNotConfigured,
+			"device has no colour temperature address", 0)
+		return fmt.Errorf("knx: no colour temperature address")
+	}
+
+	k := math.Round(kelvin)
+	return b.writeFunction(ctx, cmd, addr, fnName, EncodeDPT7(uint16(k)), k) //nolint:gosec // k bounded by colourTempMax
+}
+
+// executeSetRGB sends an RGB colour command (DPT 232.600).
+func (b *Bridge) executeSetRGB(ctx context.Context, cmd CommandMessage, deviceGAs map[string]AddressConfig) error {
+	var rgb RGB
+	for _, c := range []struct {
+		name string
+		dst  *uint8
+	}{{"r", &rgb.R}, {"g", &rgb.G}, {"b", &rgb.B}} {
+		v, err := b.numberParam(cmd, c.name, 0, dpt5MaxValue)
+		if err != nil {
+			return err
+		}
+		*c.dst = uint8(math.Round(v)) //nolint:gosec // v bounded 0-255 by numberParam
+	}
+
+	addr, fnName, ok := resolveFunction(cmd.Parameters, deviceGAs, "rgb")
+	if !ok {
+		b.publishAckError(cmd, "", ErrCodeNotConfigured,
+			"device has no rgb address", 0)
+		return fmt.Errorf("knx: no rgb address")
+	}
+
+	return b.writeFunction(ctx, cmd, addr, fnName, EncodeDPT232(rgb), rgb)
+}
+
+// executeSetRGBW sends an RGBW colour command (DPT 251.600).
+func (b *Bridge) executeSetRGBW(ctx context.Context, cmd CommandMessage, deviceGAs map[string]AddressConfig) error {
+	var rgbw RGBW
+	for _, c := range []struct {
+		name string
+		dst  *uint8
+	}{{"r", &rgbw.R}, {"g", &rgbw.G}, {"b", &rgbw.B}, {"w", &rgbw.W}} {
+		v, err := b.numberParam(cmd, c.name, 0, dpt5MaxValue)
+		if err != nil {
+			return err
+		}
+		*c.dst = uint8(math.Round(v)) //nolint:gosec // v bounded 0-255 by numberParam
+	}
+
+	addr, fnName, ok := resolveFunction(cmd.Parameters, deviceGAs, "rgbw")
+	if !ok {
+		b.publishAckError(cmd, "", ErrCodeNotConfigured,
+			"device has no rgbw address", 0)
+		return fmt.Errorf("knx: no rgbw address")
+	}
+
+	return b.writeFunction(ctx, cmd, addr, fnName, EncodeDPT251(rgbw), rgbw)
+}
+
+// executeSetHSV sends a hue/saturation/value colour command.
+//
+// The colour is sent in the richest format the device has an address for:
+// xyY (DPT 242.600), then RGBW (DPT 251.600, white channel off), then RGB
+// (DPT 232.600). The "v" parameter is optional and defaults to 100.
+func (b *Bridge) executeSetHSV(ctx context.Context, cmd CommandMessage, deviceGAs map[string]AddressConfig) error {
+	h, err := b.numberParam(cmd, "h", 0, 360) //nolint:mnd // degrees in a circle
+	if err != nil {
+		return err
+	}
+	sat, err := b.numberParam(cmd, "s", 0, 100) //nolint:mnd // percent
+	if err != nil {
+		return err
+	}
+	v := 100.0
+	if _, ok := cmd.Parameters["v"]; ok {
+		if v, err = b.numberParam(cmd, "v", 0, 100); err != nil { //nolint:mnd // percent
+			return err
+		}
+	}
+
+	addr, fnName, ok := resolveFunction(cmd.Parameters, deviceGAs, "color_xyy", "rgbw", "rgb")
+	if !ok {
+		b.publishAckError(cmd, "", ErrCodeNotConfigured,
+			"device has no colour address", 0)
+		return fmt.Errorf("knx: no colour address")
+	}
+
+	dpt := addr.DPT
+	if dpt == "" {
+		dpt = DefaultDPTForFunction(fnName)
+	}
+
+	rgb := HSVToRGB(h, sat, v)
+	switch DPT(dpt) {
+	case DPTColourXYY:
+		// Chromaticity from the full-brightness colour; brightness is v.
+		xyy := RGBToXYY(HSVToRGB(h, sat, 100)) //nolint:mnd // full value
+		xyy.Brightness = v
+		return b.writeFunction(ctx, cmd, addr, fnName, EncodeDPT242(xyy), xyy)
+	case DPTColourRGBW:
+		rgbw := RGBW{R: rgb.R, G: rgb.G, B: rgb.B}
+		return b.writeFunction(ctx, cmd, addr, fnName, EncodeDPT251(rgbw), rgbw)
+	default:
+		return b.writeFunction(ctx, cmd, addr, fnName, EncodeDPT232(rgb), rgb)
+	}
+}
+
+// executeSetHVACMode sends a room operating mode command (DPT 20.102).
+func (b *Bridge) executeSetHVACMode(ctx context.Context, cmd CommandMessage, deviceGAs map[string]AddressConfig) error {
+	name, ok := cmd.Parameters["hvac_mode"].(string)
+	if !ok {
+		b.publishAckError(cmd, "", ErrCodeInvalidParameters,
+			"missing 'hvac_mode' parameter", 0)
+		return fmt.Errorf("knx: missing hvac_mode parameter")
+	}
+	mode, err := ParseHVACMode(name)
+	if err != nil {
+		b.publishAckError(cmd, "", ErrCodeInvalidParameters,
+			fmt.Sprintf("'hvac_mode' must be auto, comfort, standby, economy or building_protection, got %q", name), 0)
+		return err
+	}
+
+	addr, fnName, ok := resolveFunction(cmd.Parameters, deviceGAs, "hvac_mode")
+	if !ok {
+		b.publishAckError(cmd, "", ErrCodeNotConfigured,
+			"device has no hvac_mode address", 0)
+		return fmt.Errorf("knx: no hvac_mode address")
+	}
+
+	return b.writeFunction(ctx, cmd, addr, fnName, EncodeDPT20(uint8(mode)), mode.String())
+}
+
+// executeShiftSetpoint sends a setpoint shift in controller steps (DPT 6.010).
+// The step size (typically 0.5 K) is configured in the room controller.
+func (b *Bridge) executeShiftSetpoint(ctx context.Context, cmd CommandMessage, deviceGAs map[string]AddressConfig) error {
+	shift, err := b.numberParam(cmd, "shift", math.MinInt8, math.MaxInt8)
+	if err != nil {
+		return err
+	}
+	if shift != math.Trunc(shift) {
+		b.publishAckError(cmd, "", ErrCodeInvalidParameters,
+			fmt.Sprintf("'shift' must be a whole number of steps, got %g", shift), 0)
+		return fmt.Errorf("knx: shift not a whole number: %g", shift)
+	}
+
+	addr, fnName, ok := resolveFunction(cmd.Parameters, deviceGAs, "setpoint_shift")
+	if !ok {
+		b.publishAckError(cmd, "", ErrCodeNotConfigured,
+			"device has no setpoint_shift address", 0)
+		return fmt.Errorf("knx: no setpoint_shift address")
+	}
+
+	return b.writeFunction(ctx, cmd, addr, fnName, EncodeDPT6(int8(shift)), shift)
+}
+
+// executeWriteFunction writes a value to any writable function in the
+// device's address map, encoding it with the codec for the mapping's DPT.
+// This covers functions without a dedicated command (enable, trigger,
+// percentage, scene_number, button LEDs, ...).
+func (b *Bridge) executeWriteFunction(ctx context.Context, cmd CommandMessage, deviceGAs map[string]AddressConfig) error {
+	addr, fnName, err := b.functionParam(cmd, deviceGAs, "write")
+	if err != nil {
+		return err
+	}
+
+	value, ok := cmd.Parameters["value"]
+	if !ok {
+		b.publishAckError(cmd, addr.GA, ErrCodeInvalidParameters,
+			"missing 'value' parameter", 0)
+		return fmt.Errorf("knx: missing value parameter")
+	}
+
+	dpt := addr.DPT
+	if dpt == "" {
+		dpt = inferDPTFromFunction(fnName)
+	}
+	data, err := EncodeValue(dpt, value)
+	if err != nil {
+		b.publishAckError(cmd, addr.GA, ErrCodeInvalidParameters,
+			fmt.Sprintf("invalid value for %s: %v", fnName, err), 0)
+		return err
+	}
+
+	// Publish the value as the bus would report it (e.g. HVAC mode names
+	// normalised, scene control as an object).
+	state, err := DecodeValue(dpt, data)
+	if err != nil {
+		state = value
+	}
+	return b.writeFunction(ctx, cmd, addr, fnName, data, state)
+}
+
+// executeReadFunction sends a read request for any readable function in
+// the device's address map. The value arrives later as a state update.
+func (b *Bridge) executeReadFunction(ctx context.Context, cmd CommandMessage, deviceGAs map[string]AddressConfig) error {
+	addr, _, err := b.functionParam(cmd, deviceGAs, "read")
+	if err != nil {
+		return err
+	}
+
+	ga, err := ParseGroupAddress(addr.GA)
+	if err != nil {
+		b.publishAckError(cmd, addr.GA, ErrCodeProtocolError,
+			fmt.Sprintf("invalid GA: %v", err), 0)
+		return err
+	}
+
+	if err := b.knxd.SendRead(ctx, ga); err != nil {
+		b.publishAckError(cmd, addr.GA, ErrCodeDeviceUnreachable,
+			fmt.Sprintf("read failed: %v", err), 0)
+		return err
+	}
+
+	b.publishAck(cmd, addr.GA, AckAccepted)
+	return nil
+}
+
+// functionParam looks up the function named by the "function" parameter and
+// checks it has the given flag, sending an error ack if not.
+func (b *Bridge) functionParam(cmd CommandMessage, deviceGAs map[string]AddressConfig, flag string) (AddressConfig, string, error) {
+	fnName, ok := cmd.Parameters["function"].(string)
+	if !ok || fnName == "" {
+		b.publishAckError(cmd, "", ErrCodeInvalidParameters,
+			"missing 'function' parameter", 0)
+		return AddressConfig{}, "", fmt.Errorf("knx: missing function parameter")
+	}
+
+	addr, ok := deviceGAs[fnName]
+	if !ok {
+		b.publishAckError(cmd, "", ErrCodeNotConfigured,
+			fmt.Sprintf("device has no %s address", fnName), 0)
+		return AddressConfig{}, "", fmt.Errorf("knx: no %s address", fnName)
+	}
+	if !addr.HasFlag(flag) {
+		b.publishAckError(cmd, addr.GA, ErrCodeNotConfigured,
+			fmt.Sprintf("function %s does not have the %s flag", fnName, flag), 0)
+		return AddressConfig{}, "", fmt.Errorf("knx: function %s lacks %s flag", fnName, flag)
+	}
+	return addr, fnName, nil
+}
+
+// numberParam reads a numeric command parameter and checks its range,
+// sending an error ack if it is missing or invalid.
+func (b *Bridge) numberParam(cmd CommandMessage, name string, minVal, maxVal float64) (float64, error) {
+	raw, ok := cmd.Parameters[name]
+	if !ok {
+		b.publishAckError(cmd, "", ErrCodeInvalidParameters,
+			fmt.Sprintf("missing '%s' parameter", name), 0)
+		return 0, fmt.Errorf("knx: missing %s parameter", name)
+	}
+	v, ok := raw.(float64)
+	if !ok {
+		b.publishAckError(cmd, "", ErrCodeInvalidParameters,
+			fmt.Sprintf("'%s' must be a number", name), 0)
+		return 0, fmt.Errorf("knx: %s must be a number", name)
+	}
+	if v < minVal || v > maxVal {
+		b.publishAckError(cmd, "", ErrCodeInvalidParameters,
+			fmt.Sprintf("'%s' must be %g-%g, got %.2f", name, minVal, maxVal, v), 0)
+		return 0, fmt.Errorf("knx: %s out of range: %.2f", name, v)
+	}
+	return v, nil
+}
+
+// writeFunction sends encoded data to a function's group address, acks the
+// command once it is on the bus, and publishes the written value as state.
+func (b *Bridge) writeFunction(ctx context.Context, cmd CommandMessage, addr AddressConfig, fnName string, data []byte, value any) error {
+	ga, err := ParseGroupAddress(addr.GA)
+	if err != nil {
+		b.publishAckError(cmd, addr.GA, ErrCodeProtocolError,
+			fmt.Sprintf("invalid GA: %v", err), 0)
+		return err
+	}
+
+	if err := b.knxd.Send(ctx, ga, data); err != nil {
+		b.publishAckError(cmd, addr.GA, ErrCodeDeviceUnreachable,
+			fmt.Sprintf("send failed: %v", err), 0)
+		return err
+	}
+
+	b.publishAck(cmd, addr.GA, AckAccepted)
+	b.publishWriteThrough(cmd.DeviceID, addr.GA, fnName, value)
+	return nil
+}
+
 // publishWriteThrough publishes a state update for a value the bridge just
 // wrote to the bus.  This mirrors what would happen if the device echoed the
 // value back, ensuring the UI gets timely feedback even when the device or
@@ -837,6 +1445,7 @@ func (b *Bridge) publishWriteThrough(deviceID, ga, function string, value any) {
 	state := map[string]any{stateKey: value}
 
 	msg := NewStateMessage(deviceID, ga, state)
+	msg.Source = StateSourceCommand
 	payload, err := json.Marshal(msg)
 	if err != nil {
 		b.logError("failed to marshal write-through state", err)
@@ -857,18 +1466,25 @@ func (b *Bridge) publishWriteThrough(deviceID, ga, function string, value any) {
 		}
 	}
 
-	// Update state cache so stateUnchanged() won't suppress a later echo
+	// Update state cache so checkState() won't suppress a later echo
 	b.stateCacheMu.Lock()
 	if b.stateCache[deviceID] == nil {
-		b.stateCache[deviceID] = make(map[string]any)
+		b.stateCache[deviceID] = make(map[string]CachedValue)
 	}
-	b.stateCache[deviceID][function] = value
+	b.stateCache[deviceID][function] = CachedValue{Value: value, UpdatedAt: time.Now().UTC()}
+	b.markStateDirty(deviceID, function)
 	b.stateCacheMu.Unlock()
 }
 
 // publishAck publishes a command acknowledgment.
 //
-//nolint:unparam // status parameter will be used for AckQueued when queue support is added
+// AckAccepted is published only after the telegram has been sent, so it is
+// the command's final outcome: Core's command tracker completes a command on
+// accepted and fails it on failed or timeout. AckQueued is not final.
+//
+// "Sent" means the connector took the telegram: knxd accepted it, or the
+// tunnelling server answered with TUNNELLING_ACK. That is before the
+// interface's L_Data.con, so accepted does not prove the line carried it.
 func (b *Bridge) publishAck(cmd CommandMessage, address string, status AckStatus) {
 	ack := NewAckMessage(cmd, status, address)
 
@@ -1001,13 +1617,15 @@ func (b *Bridge) handleReadState(req RequestMessage) ResponseMessage {
 		}
 	}
 
-	// Return success - actual state will come via telegram callback
+	// Return success with the last known values (stale ones restored at
+	// startup) - current state will come via telegram callback
 	return ResponseMessage{
 		RequestID: req.RequestID,
 		Timestamp: time.Now().UTC(),
 		Success:   true,
 		Data: map[string]any{
 			"message": "read requests sent, state updates will follow",
+			"cached":  b.CachedState(req.DeviceID),
 		},
 	}
 }
@@ -1015,7 +1633,7 @@ func (b *Bridge) handleReadState(req RequestMessage) ResponseMessage {
 // handleReadAll handles a read_all request.
 func (b *Bridge) handleReadAll(req RequestMessage) ResponseMessage {
 	// Derive timeout from bridge context so reads are cancelled on shutdown
-	ctx, cancel := context.WithTimeout(b.ctx, readAllTimeout)
+	ctx, cancel := context.WithTimeout(WithPriority(b.ctx, PriorityBackground), readAllTimeout)
 	defer cancel()
 
 	b.mappingMu.RLock()
@@ -1081,6 +1699,22 @@ func (b *Bridge) handleKNXTelegram(t Telegram) { //nolint:gocognit // telegram p
 		isResponse := t.APCI == APCIResponse
 		b.gaRecorder.RecordTelegram(t.Source, gaStr, isResponse)
 	}
+	b.recordTelegram(t, MonitorRx)
+
+	// Secure telegram for a group address without a key: nothing to decode
+	if t.APCI == APCISecure {
+		return
+	}
+
+	// A value on the bus answers the poll for its group address
+	if t.APCI == APCIWrite || t.APCI == APCIResponse {
+		b.poller.answered(gaStr)
+	}
+
+	// Answer read requests for the time master's GAs
+	if t.APCI == APCIRead && b.timeMaster != nil && b.timeMaster.HandleRead(b.ctx, t.Destination) {
+		return
+	}
 
 	// Look up device mappings (one GA may map to multiple devices)
 	b.mappingMu.RLock()
@@ -1092,32 +1726,57 @@ func (b *Bridge) handleKNXTelegram(t Telegram) { //nolint:gocognit // telegram p
 		return
 	}
 
-	// Decode the value using the best available DPT from the mappings.
-	// Pick the first non-empty DPT (order may vary due to map iteration).
-	dpt := ""
-	for _, m := range mappings {
-		if m.DPT != "" {
-			dpt = m.DPT
-			break
-		}
-	}
-	value, err := b.decodeTelegramValue(t, dpt)
-	if err != nil {
-		b.logError("failed to decode telegram",
-			fmt.Errorf("ga=%s dpt=%s: %w", gaStr, dpt, err))
-		return
+	// Decode once per distinct DPT: each mapping is decoded with its own
+	// DPT, so devices that disagree on a GA's format (see DPTConflicts)
+	// do not corrupt each other's state.
+	type decoded struct {
+		value any
+		err   error
 	}
+	decodedByDPT := make(map[string]decoded, 1)
 
 	// Update each mapped device
 	for _, mapping := range mappings {
+		dpt := mapping.DPT
+		if dpt == "" {
+			dpt = inferDPTFromFunction(mapping.Function)
+		}
+		d, ok := decodedByDPT[dpt]
+		if !ok {
+			d.value, d.err = b.decodeTelegramValue(t, dpt)
+			decodedByDPT[dpt] = d
+			if d.err != nil {
+				b.logError("failed to decode telegram",
+					fmt.Errorf("ga=%s dpt=%s: %w", gaStr, dpt, d.err))
+			}
+		}
+		if d.err != nil {
+			continue
+		}
+		value := d.value
+
 		state := b.buildStateUpdate(mapping, value)
 
-		if b.stateUnchanged(mapping.DeviceID, mapping.Function, value) {
+		switch b.checkState(mapping.DeviceID, mapping.Function, value) {
+		case stateSame:
 			continue // No change for this device, skip
+		case stateConfirmed:
+			// Not republished, but the device is answering
+			b.markDeviceOnline(mapping.DeviceID)
+			continue
+		case stateChanged:
 		}
 
-		// Publish state message
+		// Publish state message. A write to a command address came from
+		// a wall switch or keypad rather than from the actuator's feedback.
 		msg := NewStateMessage(mapping.DeviceID, gaStr, state)
+		msg.Source = StateSourceFeedback
+		if unit := UnitForDPT(dpt); unit != "" {
+			msg.Units = map[string]string{StateKeyForFunction(mapping.Function): unit}
+		}
+		if t.APCI == APCIWrite && mapping.Command {
+			msg.Source = StateSourcePhysical
+		}
 
 		payload, err := json.Marshal(msg)
 		if err != nil {
@@ -1138,31 +1797,111 @@ func (b *Bridge) handleKNXTelegram(t Telegram) { //nolint:gocognit // telegram p
 					"device", mapping.DeviceID,
 					"reason", err.Error())
 			} else {
-				if healthErr := b.registry.SetDeviceHealth(b.ctx, mapping.DeviceID, "online"); healthErr != nil {
-					b.logDebug("registry health update skipped",
-						"device", mapping.DeviceID,
-						"reason", healthErr.Error())
-				}
+				b.markDeviceOnline(mapping.DeviceID)
 			}
 		}
 	}
 }
 
-// decodeTelegramValue decodes the telegram data based on DPT.
-func (b *Bridge) decodeTelegramValue(t Telegram, dpt string) (any, error) {
-	switch {
-	case strings.HasPrefix(dpt, "1."):
-		return DecodeDPT1(t.Data)
-	case strings.HasPrefix(dpt, "5."):
-		return DecodeDPT5(t.Data)
-	case strings.HasPrefix(dpt, "9."):
-		return DecodeDPT9(t.Data)
-	default:
-		// Return raw bytes for unknown DPT
-		return t.Data, nil
+// markDeviceOnline sets a device's registry health to online after a
+// telegram from it. Devices with polled GAs that stopped answering keep
+// their poll health.
+func (b *Bridge) markDeviceOnline(deviceID string) {
+	if b.registry == nil || b.poller.deviceHealth(deviceID) != "" {
+		return
+	}
+	if err := b.registry.SetDeviceHealth(b.ctx, deviceID, "online"); err != nil {
+		b.logDebug("registry health update skipped",
+			"device", deviceID,
+			"reason", err.Error())
+	}
+}
+
+// recordTelegram counts a telegram in the bus statistics and adds it to
+// the bus monitor with the device functions mapped to its group address.
+func (b *Bridge) recordTelegram(t Telegram, direction string) {
+	if b.stats != nil {
+		b.stats.Record(t)
+	}
+	if b.monitor == nil {
+		return
+	}
+	b.mappingMu.RLock()
+	mappings := b.gaToDevice[t.Destination.String()]
+	b.mappingMu.RUnlock()
+	b.monitor.Record(t, direction, mappings)
+}
+
+// BusMonitor returns the bus monitor, or nil if it is disabled.
+func (b *Bridge) BusMonitor() *BusMonitor {
+	return b.monitor
+}
+
+// BusStats returns the bus traffic statistics, or nil if they are disabled.
+func (b *Bridge) BusStats() *BusStats {
+	return b.stats
+}
+
+// updatePollTargets hands the functions with a poll interval to the poller.
+// A GA mapped by several functions is polled at the shortest interval.
+func (b *Bridge) updatePollTargets() {
+	intervals := make(map[string]time.Duration)
+	devices := make(map[string][]string)
+
+	b.mappingMu.RLock()
+	for deviceID, gas := range b.deviceToGAs {
+		for _, addr := range gas {
+			interval := pollInterval(addr, b.cfg.Polling.Defaults)
+			if interval == 0 {
+				continue
+			}
+			if current, ok := intervals[addr.GA]; !ok || interval < current {
+				intervals[addr.GA] = interval
+			}
+			if !slices.Contains(devices[addr.GA], deviceID) {
+				devices[addr.GA] = append(devices[addr.GA], deviceID)
+			}
+		}
+	}
+	b.mappingMu.RUnlock()
+
+	b.poller.setTargets(intervals, devices)
+	if len(intervals) > 0 {
+		b.logInfo("polling group addresses", "count", len(intervals))
+	}
+}
+
+// updatePollHealth sets the health of devices whose polled GAs stopped or
+// resumed answering.
+func (b *Bridge) updatePollHealth(deviceIDs []string) {
+	for _, deviceID := range slices.Compact(slices.Sorted(slices.Values(deviceIDs))) {
+		status := b.poller.deviceHealth(deviceID)
+		if status == "" {
+			status = "online"
+			b.logInfo("polled device answering again", "device", deviceID)
+		} else {
+			b.logWarn("polled device not answering, polling stopped", "device", deviceID, "health", status)
+		}
+
+		if b.registry == nil {
+			continue
+		}
+		if err := b.registry.SetDeviceHealth(b.ctx, deviceID, status); err != nil {
+			b.logDebug("registry health update skipped",
+				"device", deviceID,
+				"reason", err.Error())
+		}
 	}
 }
 
+// decodeTelegramValue decodes the telegram data based on DPT using the codec
+// registry (codecs.go). Counters (DPT 12/13) are returned as float64 so
+// metering values take the same numeric path as DPT 9/14 floats (state,
+// TSDB, automation conditions). Unknown DPTs yield the raw bytes.
+func (b *Bridge) decodeTelegramValue(t Telegram, dpt string) (any, error) {
+	return DecodeValue(dpt, t.Data)
+}
+
 // buildStateUpdate builds a state object from the decoded value.
 // Uses the canonical function registry (functions.go) for state key lookup.
 func (b *Bridge) buildStateUpdate(mapping GAMapping, value any) map[string]any {
@@ -1187,24 +1926,193 @@ func (b *Bridge) buildStateUpdate(mapping GAMapping, value any) map[string]any {
 	return state
 }
 
-// stateUnchanged checks if the new value matches the cached state.
-// Returns true if unchanged (should skip publish).
-func (b *Bridge) stateUnchanged(deviceID, function string, value any) bool {
+// stateCheck is the outcome of comparing a value with the state cache.
+type stateCheck int
+
+const (
+	stateChanged   stateCheck = iota // New or different value: publish it
+	stateSame                        // Same as the cached value: skip it
+	stateConfirmed                   // Same as a stale restored value, now confirmed
+)
+
+// checkState compares a new value with the cached state and updates the
+// cache. A stale value restored at startup that the bus confirms is no
+// longer stale; it is reported as stateConfirmed so the caller can skip
+// the republish but still record that the device answered.
+func (b *Bridge) checkState(deviceID, function string, value any) stateCheck {
+	b.stateCacheMu.Lock()
+	if b.stateCache[deviceID] == nil {
+		b.stateCache[deviceID] = make(map[string]CachedValue)
+	}
+
+	cached, ok := b.stateCache[deviceID][function]
+	unchanged := ok && valuesEqual(cached.Value, value)
+	if unchanged && !cached.Stale {
+		b.stateCacheMu.Unlock()
+		return stateSame
+	}
+
+	// Update cache (or confirm the stale value)
+	b.stateCache[deviceID][function] = CachedValue{Value: value, UpdatedAt: time.Now().UTC()}
+	b.markStateDirty(deviceID, function)
+	b.stateCacheMu.Unlock()
+
+	if unchanged {
+		return stateConfirmed
+	}
+	return stateChanged
+}
+
+// restoreStateCache loads the persisted state cache. Restored values are
+// stale until the bus confirms them.
+func (b *Bridge) restoreStateCache(ctx context.Context) {
+	if b.stateStore == nil {
+		return
+	}
+	states, err := b.stateStore.LoadStates(ctx)
+	if err != nil {
+		b.logError("failed to restore state cache", err)
+		return
+	}
+
+	count := 0
+	b.stateCacheMu.Lock()
+	for deviceID, functions := range states {
+		if b.stateCache[deviceID] == nil {
+			b.stateCache[deviceID] = make(map[string]CachedValue, len(functions))
+		}
+		for function, cv := range functions {
+			if _, ok := b.stateCache[deviceID][function]; ok {
+				continue
+			}
+			cv.Stale = true
+			b.stateCache[deviceID][function] = cv
+			count++
+		}
+	}
+	b.stateCacheMu.Unlock()
+
+	if count > 0 {
+		b.logInfo("restored state cache", "values", count)
+	}
+}
+
+// seedStateCache fills in cache values missing for the devices' functions
+// from the registry's last known state, marked stale. Functions are matched
+// to state by their state key.
+func (b *Bridge) seedStateCache(devices []RegistryDevice, deviceToGAs map[string]map[string]AddressConfig) {
 	b.stateCacheMu.Lock()
 	defer b.stateCacheMu.Unlock()
 
-	if b.stateCache[deviceID] == nil {
-		b.stateCache[deviceID] = make(map[string]any)
+	for _, dev := range devices {
+		if len(dev.State) == 0 {
+			continue
+		}
+		for function := range deviceToGAs[dev.ID] {
+			value, ok := dev.State[StateKeyForFunction(function)]
+			if !ok {
+				continue
+			}
+			if b.stateCache[dev.ID] == nil {
+				b.stateCache[dev.ID] = make(map[string]CachedValue)
+			}
+			if _, ok := b.stateCache[dev.ID][function]; ok {
+				continue
+			}
+			b.stateCache[dev.ID][function] = CachedValue{Value: value, UpdatedAt: dev.StateUpdatedAt, Stale: true}
+		}
+	}
+}
+
+// markStateDirty marks a cache value to be written by the next flush, if a
+// state store is set. Caller must hold stateCacheMu.
+func (b *Bridge) markStateDirty(deviceID, function string) {
+	if b.stateStore == nil {
+		return
+	}
+	if b.stateDirty[deviceID] == nil {
+		b.stateDirty[deviceID] = make(map[string]struct{})
+	}
+	b.stateDirty[deviceID][function] = struct{}{}
+}
+
+// runStateFlush writes state cache changes to the store every
+// stateFlushInterval until ctx is cancelled. Stop flushes what is left.
+func (b *Bridge) runStateFlush(ctx context.Context) {
+	ticker := time.NewTicker(stateFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.flushStateCache(ctx)
+		}
+	}
+}
+
+// flushStateCache writes the cache values changed since the last flush to
+// the store in one batch. Values that fail to save are kept for the next
+// flush.
+func (b *Bridge) flushStateCache(ctx context.Context) {
+	if b.stateStore == nil {
+		return
+	}
+	b.stateFlushMu.Lock()
+	defer b.stateFlushMu.Unlock()
+
+	b.stateCacheMu.Lock()
+	batch := make(map[string]map[string]CachedValue, len(b.stateDirty))
+	for deviceID, functions := range b.stateDirty {
+		for function := range functions {
+			cv, ok := b.stateCache[deviceID][function]
+			if !ok {
+				continue // Cleared since it changed
+			}
+			if batch[deviceID] == nil {
+				batch[deviceID] = make(map[string]CachedValue, len(functions))
+			}
+			batch[deviceID][function] = cv
+		}
 	}
+	b.stateDirty = make(map[string]map[string]struct{})
+	b.stateCacheMu.Unlock()
 
-	cached := b.stateCache[deviceID][function]
-	if valuesEqual(cached, value) {
-		return true // Unchanged
+	if len(batch) == 0 {
+		return
 	}
+	if err := b.stateStore.SaveStates(ctx, batch); err != nil {
+		b.logDebug("state cache persistence skipped",
+			"devices", len(batch),
+			"reason", err.Error())
+		b.stateCacheMu.Lock()
+		for deviceID, functions := range batch {
+			for function := range functions {
+				b.markStateDirty(deviceID, function)
+			}
+		}
+		b.stateCacheMu.Unlock()
+	}
+}
 
-	// Update cache
-	b.stateCache[deviceID][function] = value
-	return false
+// CachedState returns the cached values of a device's functions.
+//
+// Parameters:
+//   - deviceID: Device to look up
+//
+// Returns:
+//   - map[string]CachedValue: Values by function (nil if none are cached)
+func (b *Bridge) CachedState(deviceID string) map[string]CachedValue {
+	b.stateCacheMu.RLock()
+	defer b.stateCacheMu.RUnlock()
+
+	if len(b.stateCache[deviceID]) == 0 {
+		return nil
+	}
+	out := make(map[string]CachedValue, len(b.stateCache[deviceID]))
+	maps.Copy(out, b.stateCache[deviceID])
+	return out
 }
 
 // valuesEqual compares two values for equality, handling []byte specially
@@ -1233,28 +2141,67 @@ func valuesEqual(a, b any) bool {
 		return true
 	}
 
+	// Numbers compare by value: restored cache values are float64 after
+	// their JSON round trip, while decoders return uint8, int, float32, etc.
+	if aNum, ok := numericValue(a); ok {
+		if bNum, ok := numericValue(b); ok {
+			return aNum == bNum
+		}
+	}
+
 	// For all other types, use direct comparison
 	// This is safe because decode functions return bool, float64, uint8, etc.
 	return a == b
 }
 
+// numericValue returns v as float64 if it is a number.
+func numericValue(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
 // ClearStateCache removes all entries from the state cache.
 // Call this when configuration is reloaded to prevent unbounded memory growth
 // from stale device IDs accumulating over multi-decade deployments.
+// The persisted cache is kept (see PruneStateCache).
 func (b *Bridge) ClearStateCache() {
 	b.stateCacheMu.Lock()
 	defer b.stateCacheMu.Unlock()
 
 	// Replace with fresh map to allow GC of old entries
-	b.stateCache = make(map[string]map[string]any)
+	b.stateCache = make(map[string]map[string]CachedValue)
 }
 
 // PruneStateCache removes cache entries for devices not in the current config.
 // This is a less disruptive alternative to ClearStateCache that preserves
-// state for active devices while removing orphaned entries.
+// state for active devices while removing orphaned entries, also from the
+// persisted cache.
 func (b *Bridge) PruneStateCache() {
 	b.stateCacheMu.Lock()
-	defer b.stateCacheMu.Unlock()
 
 	// Deep copy valid device IDs to avoid data race with concurrent config reload
 	b.mappingMu.RLock()
@@ -1265,9 +2212,27 @@ func (b *Bridge) PruneStateCache() {
 	b.mappingMu.RUnlock()
 
 	// Remove entries for devices not in current config
+	var removed []string
 	for deviceID := range b.stateCache {
 		if _, exists := validIDs[deviceID]; !exists {
 			delete(b.stateCache, deviceID)
+			delete(b.stateDirty, deviceID)
+			removed = append(removed, deviceID)
+		}
+	}
+	b.stateCacheMu.Unlock()
+
+	if b.stateStore == nil {
+		return
+	}
+	// Wait for a flush in progress so it cannot write the devices back
+	b.stateFlushMu.Lock()
+	defer b.stateFlushMu.Unlock()
+	for _, deviceID := range removed {
+		if err := b.stateStore.DeleteDevice(b.ctx, deviceID); err != nil {
+			b.logDebug("state cache prune skipped",
+				"device", deviceID,
+				"reason", err.Error())
 		}
 	}
 }
@@ -1294,6 +2259,17 @@ func (b *Bridge) logInfo(msg string, keysAndValues ...any) {
 	}
 }
 
+// logWarn logs a warning message if logger is set.
+func (b *Bridge) logWarn(msg string, keysAndValues ...any) {
+	b.loggerMu.RLock()
+	logger := b.logger
+	b.loggerMu.RUnlock()
+
+	if logger != nil {
+		logger.Warn(msg, keysAndValues...)
+	}
+}
+
 // logError logs an error message if logger is set.
 func (b *Bridge) logError(msg string, err error) {
 	b.loggerMu.RLock()
@@ -1323,6 +2299,11 @@ type BridgeMetrics struct {
 	TelegramsTx    uint64
 	TelegramsRx    uint64
 	DevicesManaged int
+
+	// Outgoing queue (zero when the scheduler is disabled)
+	QueueDepth     int
+	QueueDropped   uint64
+	QueueCoalesced uint64
 }
 
 // GetMetrics returns current bridge metrics for the API metrics endpoint.
@@ -1343,11 +2324,19 @@ func (b *Bridge) GetMetrics() BridgeMetrics {
 		}
 	}
 
+	var queue SchedulerStats
+	if b.scheduler != nil {
+		queue = b.scheduler.SchedulerStats()
+	}
+
 	return BridgeMetrics{
 		Connected:      connected,
 		Status:         status,
 		TelegramsTx:    stats.TelegramsTx,
 		TelegramsRx:    stats.TelegramsRx,
 		DevicesManaged: deviceCount,
+		QueueDepth:     queue.QueueDepth,
+		QueueDropped:   queue.Dropped,
+		QueueCoalesced: queue.Coalesced,
 	}
 }
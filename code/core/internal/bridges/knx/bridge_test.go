@@ -1,6 +1,7 @@
 package knx
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"sync"
@@ -103,6 +104,7 @@ type MockConnector struct {
 	stats          KNXDStats
 	sentTelegrams  []sentTelegram
 	readRequests   []GroupAddress
+	responses      []sentTelegram
 	onTelegramFunc func(Telegram)
 	sendError      error
 }
@@ -135,6 +137,16 @@ func (m *MockConnector) SendRead(ctx context.Context, ga GroupAddress) error {
 	return nil
 }
 
+func (m *MockConnector) SendResponse(ctx context.Context, ga GroupAddress, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sendError != nil {
+		return m.sendError
+	}
+	m.responses = append(m.responses, sentTelegram{GA: ga, Data: data})
+	return nil
+}
+
 func (m *MockConnector) SetOnTelegram(callback func(Telegram)) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -176,11 +188,18 @@ func (m *MockConnector) GetReadRequests() []GroupAddress {
 	return m.readRequests
 }
 
+func (m *MockConnector) GetResponses() []sentTelegram {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.responses
+}
+
 func (m *MockConnector) ClearSent() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.sentTelegrams = nil
 	m.readRequests = nil
+	m.responses = nil
 }
 
 // SimulateTelegram simulates receiving a KNX telegram.
@@ -193,6 +212,12 @@ func (m *MockConnector) SimulateTelegram(t Telegram) {
 	}
 }
 
+func (m *MockConnector) SetConnected(connected bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.connected = connected
+}
+
 func (m *MockConnector) SetSendError(err error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -286,6 +311,127 @@ func createTestDevices() []DeviceConfig {
 				},
 			},
 		},
+		{
+			DeviceID: "light-kitchen-rgbw",
+			Type:     "light_rgbw",
+			Addresses: map[string]AddressConfig{
+				"color_temperature": {
+					GA:    "1/3/1",
+					DPT:   "7.600",
+					Flags: []string{"write"},
+				},
+				"rgbw": {
+					GA:    "1/3/2",
+					DPT:   "251.600",
+					Flags: []string{"write"},
+				},
+				"rgbw_status": {
+					GA:    "1/3/3",
+					DPT:   "251.600",
+					Flags: []string{"transmit"},
+				},
+			},
+		},
+		{
+			DeviceID: "light-hall-rgb",
+			Type:     "light_rgb",
+			Addresses: map[string]AddressConfig{
+				"rgb": {
+					GA:    "1/4/1",
+					DPT:   "232.600",
+					Flags: []string{"write"},
+				},
+			},
+		},
+		{
+			DeviceID: "light-desk-xyy",
+			Type:     "light_rgb",
+			Addresses: map[string]AddressConfig{
+				"color_xyy": {
+					GA:    "1/5/1",
+					DPT:   "242.600",
+					Flags: []string{"write"},
+				},
+			},
+		},
+		{
+			DeviceID: "thermostat-living",
+			Type:     "thermostat",
+			Addresses: map[string]AddressConfig{
+				"hvac_mode": {
+					GA:    "3/1/1",
+					DPT:   "20.102",
+					Flags: []string{"write"},
+				},
+				"hvac_mode_status": {
+					GA:    "3/1/2",
+					DPT:   "20.102",
+					Flags: []string{"transmit"},
+				},
+				"setpoint_shift": {
+					GA:    "3/1/3",
+					DPT:   "6.010",
+					Flags: []string{"write"},
+				},
+				"hvac_control_mode_status": {
+					GA:    "3/1/4",
+					DPT:   "20.105",
+					Flags: []string{"transmit"},
+				},
+			},
+		},
+		{
+			DeviceID: "controller-hall",
+			Type:     "push_button",
+			Addresses: map[string]AddressConfig{
+				"enable": {
+					GA:    "4/2/1",
+					DPT:   "1.003",
+					Flags: []string{"write"},
+				},
+				"percentage": {
+					GA:    "4/2/2",
+					DPT:   "5.004",
+					Flags: []string{"write", "read"},
+				},
+				"scene_number": {
+					GA:    "4/2/3",
+					DPT:   "17.001",
+					Flags: []string{"write"},
+				},
+				"scene_control": {
+					GA:    "4/2/4",
+					DPT:   "18.001",
+					Flags: []string{"write"},
+				},
+				"button_1_led": {
+					GA:    "4/2/5",
+					DPT:   "1.001",
+					Flags: []string{"write"},
+				},
+				"button_1": {
+					GA:    "4/2/6",
+					DPT:   "1.001",
+					Flags: []string{"transmit"},
+				},
+			},
+		},
+		{
+			DeviceID: "meter-kitchen",
+			Type:     "energy_meter",
+			Addresses: map[string]AddressConfig{
+				"power": {
+					GA:    "7/0/1",
+					DPT:   "14.056",
+					Flags: []string{"transmit"},
+				},
+				"active_energy": {
+					GA:    "7/0/2",
+					DPT:   "13.010",
+					Flags: []string{"transmit"},
+				},
+			},
+		},
 	}
 }
 
@@ -812,6 +958,64 @@ func TestBridgeKNXTelegramToState(t *testing.T) {
 	}
 }
 
+func TestBridgeKNXTelegramStateSource(t *testing.T) {
+	mqtt := NewMockMQTTClient()
+	knxd := NewMockConnector()
+
+	b := createTestBridge(t, BridgeOptions{
+		Config:     createTestConfig(),
+		MQTTClient: mqtt,
+		KNXDClient: knxd,
+	})
+	// The registry indexes every address, including write-only command
+	// GAs; mirror that for the switch GA.
+	b.mappingMu.Lock()
+	b.gaToDevice["1/2/3"] = []GAMapping{{DeviceID: "light-living-main", Function: "switch", DPT: "1.001", Type: "light_dimmer", Command: true}}
+	b.mappingMu.Unlock()
+
+	if err := b.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer b.Stop()
+
+	tests := []struct {
+		name string
+		ga   GroupAddress
+		apci byte
+		data byte
+		want StateSource
+	}{
+		{"wall switch writes command GA", GroupAddress{Main: 1, Middle: 2, Sub: 3}, APCIWrite, 0x01, StateSourcePhysical},
+		{"actuator writes status GA", GroupAddress{Main: 1, Middle: 2, Sub: 4}, APCIWrite, 0x01, StateSourceFeedback},
+		{"read response on status GA", GroupAddress{Main: 1, Middle: 2, Sub: 4}, APCIResponse, 0x00, StateSourceFeedback},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mqtt.ClearPublished()
+			knxd.SimulateTelegram(Telegram{Destination: tt.ga, APCI: tt.apci, Data: []byte{tt.data}})
+			time.Sleep(50 * time.Millisecond)
+
+			var found bool
+			for _, p := range mqtt.GetPublished() {
+				if p.Topic != StateTopic(tt.ga.String()) {
+					continue
+				}
+				found = true
+				var state StateMessage
+				if err := json.Unmarshal(p.Payload, &state); err != nil {
+					t.Fatalf("Failed to unmarshal state: %v", err)
+				}
+				if state.Source != tt.want {
+					t.Errorf("Source = %q, want %q", state.Source, tt.want)
+				}
+			}
+			if !found {
+				t.Error("Expected state message to be published")
+			}
+		})
+	}
+}
+
 func TestBridgeKNXTelegramBrightness(t *testing.T) {
 	mqtt := NewMockMQTTClient()
 	knxd := NewMockConnector()
@@ -912,6 +1116,70 @@ func TestBridgeKNXTelegramTemperature(t *testing.T) {
 	}
 }
 
+func TestBridgeKNXTelegramMetering(t *testing.T) {
+	mqtt := NewMockMQTTClient()
+	knxd := NewMockConnector()
+	cfg := createTestConfig()
+
+	b := createTestBridge(t, BridgeOptions{
+		Config:     cfg,
+		MQTTClient: mqtt,
+		KNXDClient: knxd,
+	})
+
+	ctx := context.Background()
+	if err := b.Start(ctx); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer b.Stop()
+
+	mqtt.ClearPublished()
+
+	// DPT 14.056: 1500.5 W as IEEE 754 float (0x44BB9000)
+	knxd.SimulateTelegram(Telegram{
+		Destination: GroupAddress{Main: 7, Middle: 0, Sub: 1},
+		APCI:        APCIWrite,
+		Data:        []byte{0x44, 0xBB, 0x90, 0x00},
+	})
+	// DPT 13.010: 123456 Wh
+	knxd.SimulateTelegram(Telegram{
+		Destination: GroupAddress{Main: 7, Middle: 0, Sub: 2},
+		APCI:        APCIWrite,
+		Data:        EncodeDPT13(123456),
+	})
+
+	time.Sleep(50 * time.Millisecond)
+
+	want := map[string]struct {
+		key   string
+		value float64
+		unit  string
+	}{
+		StateTopic("7/0/1"): {"power", 1500.5, "W"},
+		StateTopic("7/0/2"): {"energy", 123456, "Wh"},
+	}
+	for _, p := range mqtt.GetPublished() {
+		w, ok := want[p.Topic]
+		if !ok {
+			continue
+		}
+		delete(want, p.Topic)
+		var state StateMessage
+		if err := json.Unmarshal(p.Payload, &state); err != nil {
+			t.Fatalf("Failed to unmarshal state: %v", err)
+		}
+		if v, ok := state.State[w.key].(float64); !ok || v != w.value {
+			t.Errorf("State[%s] = %v, want %v", w.key, state.State[w.key], w.value)
+		}
+		if state.Units[w.key] != w.unit {
+			t.Errorf("Units[%s] = %q, want %q", w.key, state.Units[w.key], w.unit)
+		}
+	}
+	for topic := range want {
+		t.Errorf("no state published on %s", topic)
+	}
+}
+
 func TestBridgeStateChangeDetection(t *testing.T) {
 	mqtt := NewMockMQTTClient()
 	knxd := NewMockConnector()
@@ -1099,9 +1367,10 @@ func TestBridgeReadAllRequest(t *testing.T) {
 
 	// Check that read requests were sent
 	reads := knxd.GetReadRequests()
-	// We have 2 devices with "read" flags: light (switch) and blind (position_status)
-	if len(reads) != 2 {
-		t.Errorf("Expected 2 read requests, got %d", len(reads))
+	// We have 3 devices with "read" flags: light (switch), blind (position_status)
+	// and controller (percentage)
+	if len(reads) != 3 {
+		t.Errorf("Expected 3 read requests, got %d", len(reads))
 	}
 
 	// Check response was published
@@ -1117,8 +1386,8 @@ func TestBridgeReadAllRequest(t *testing.T) {
 			if !resp.Success {
 				t.Errorf("Response.Success = false, want true")
 			}
-			if reads, ok := resp.Data["reads_sent"].(float64); !ok || reads != 2 {
-				t.Errorf("Response.Data[reads_sent] = %v, want 2", resp.Data["reads_sent"])
+			if reads, ok := resp.Data["reads_sent"].(float64); !ok || reads != 3 {
+				t.Errorf("Response.Data[reads_sent] = %v, want 3", resp.Data["reads_sent"])
 			}
 			break
 		}
@@ -1261,6 +1530,439 @@ func TestBridgeDimMissingLevel(t *testing.T) {
 	}
 }
 
+func TestBridgeColourCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		deviceID string
+		command  string
+		params   map[string]any
+		wantGA   GroupAddress
+		wantData []byte
+		wantErr  string
+	}{
+		{
+			name: "colour temperature", deviceID: "light-kitchen-rgbw", command: "set_color_temp",
+			params: map[string]any{"color_temp": 2700.0},
+			wantGA: GroupAddress{Main: 1, Middle: 3, Sub: 1}, wantData: []byte{0x0A, 0x8C},
+		},
+		{
+			name: "rgbw", deviceID: "light-kitchen-rgbw", command: "set_rgbw",
+			params: map[string]any{"r": 255.0, "g": 128.0, "b": 0.0, "w": 64.0},
+			wantGA: GroupAddress{Main: 1, Middle: 3, Sub: 2}, wantData: []byte{0xFF, 0x80, 0x00, 0x40, 0x00, 0x0F},
+		},
+		{
+			name: "rgb", deviceID: "light-hall-rgb", command: "set_rgb",
+			params: map[string]any{"r": 0.0, "g": 255.0, "b": 0.0},
+			wantGA: GroupAddress{Main: 1, Middle: 4, Sub: 1}, wantData: []byte{0x00, 0xFF, 0x00},
+		},
+		{
+			name: "hsv to rgb", deviceID: "light-hall-rgb", command: "set_hsv",
+			params: map[string]any{"h": 240.0, "s": 100.0, "v": 50.0},
+			wantGA: GroupAddress{Main: 1, Middle: 4, Sub: 1}, wantData: []byte{0x00, 0x00, 0x80},
+		},
+		{
+			name: "hsv to rgbw", deviceID: "light-kitchen-rgbw", command: "set_hsv",
+			params: map[string]any{"h": 0.0, "s": 100.0},
+			wantGA: GroupAddress{Main: 1, Middle: 3, Sub: 2}, wantData: []byte{0xFF, 0x00, 0x00, 0x00, 0x00, 0x0F},
+		},
+		{
+			name: "hsv to xyY", deviceID: "light-desk-xyy", command: "set_hsv",
+			params: map[string]any{"h": 0.0, "s": 0.0, "v": 100.0},
+			// D65 white: x≈0.3127, y≈0.3290
+			wantGA: GroupAddress{Main: 1, Middle: 5, Sub: 1}, wantData: []byte{0x50, 0x0E, 0x54, 0x39, 0xFF, 0x03},
+		},
+		{
+			name: "colour temperature out of range", deviceID: "light-kitchen-rgbw", command: "set_color_temp",
+			params: map[string]any{"color_temp": 50000.0}, wantErr: ErrCodeInvalidParameters,
+		},
+		{
+			name: "rgb missing component", deviceID: "light-hall-rgb", command: "set_rgb",
+			params: map[string]any{"r": 10.0, "g": 10.0}, wantErr: ErrCodeInvalidParameters,
+		},
+		{
+			name: "no colour address", deviceID: "light-living-main", command: "set_hsv",
+			params: map[string]any{"h": 120.0, "s": 50.0}, wantErr: ErrCodeNotConfigured,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mqtt := NewMockMQTTClient()
+			knxd := NewMockConnector()
+			b := createTestBridge(t, BridgeOptions{
+				Config:     createTestConfig(),
+				MQTTClient: mqtt,
+				KNXDClient: knxd,
+			})
+			if err := b.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error: %v", err)
+			}
+			defer b.Stop()
+			mqtt.ClearPublished()
+
+			cmdPayload, _ := json.Marshal(CommandMessage{
+				ID:         "cmd-colour",
+				DeviceID:   tt.deviceID,
+				Command:    tt.command,
+				Parameters: tt.params,
+				Timestamp:  time.Now().UTC(),
+			})
+			b.handleMQTTMessage("graylogic/command/knx/"+tt.deviceID, cmdPayload)
+
+			telegrams := knxd.GetSentTelegrams()
+			if tt.wantErr != "" {
+				if len(telegrams) != 0 {
+					t.Errorf("sent %d telegrams, want none", len(telegrams))
+				}
+				hasErrorAck := false
+				for _, p := range mqtt.GetPublished() {
+					var ack AckMessage
+					if err := json.Unmarshal(p.Payload, &ack); err == nil && ack.Error != nil && ack.Error.Code == tt.wantErr {
+						hasErrorAck = true
+					}
+				}
+				if !hasErrorAck {
+					t.Errorf("expected %s error ack", tt.wantErr)
+				}
+				return
+			}
+
+			if len(telegrams) != 1 {
+				t.Fatalf("sent %d telegrams, want 1", len(telegrams))
+			}
+			if telegrams[0].GA != tt.wantGA {
+				t.Errorf("GA = %v, want %v", telegrams[0].GA, tt.wantGA)
+			}
+			if !bytes.Equal(telegrams[0].Data, tt.wantData) {
+				t.Errorf("data = % X, want % X", telegrams[0].Data, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestBridgeKNXTelegramColour(t *testing.T) {
+	mqtt := NewMockMQTTClient()
+	knxd := NewMockConnector()
+	b := createTestBridge(t, BridgeOptions{
+		Config:     createTestConfig(),
+		MQTTClient: mqtt,
+		KNXDClient: knxd,
+	})
+	if err := b.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer b.Stop()
+	mqtt.ClearPublished()
+
+	knxd.SimulateTelegram(Telegram{
+		Destination: GroupAddress{Main: 1, Middle: 3, Sub: 3}, // rgbw_status
+		APCI:        APCIResponse,
+		Data:        []byte{0x10, 0x20, 0x30, 0x40, 0x00, 0x0F},
+	})
+	time.Sleep(50 * time.Millisecond)
+
+	for _, p := range mqtt.GetPublished() {
+		if p.Topic != StateTopic("1/3/3") {
+			continue
+		}
+		var state StateMessage
+		if err := json.Unmarshal(p.Payload, &state); err != nil {
+			t.Fatalf("Failed to unmarshal state: %v", err)
+		}
+		rgbw, _ := state.State["rgbw"].(map[string]any)
+		if rgbw["r"] != 16.0 || rgbw["g"] != 32.0 || rgbw["b"] != 48.0 || rgbw["w"] != 64.0 {
+			t.Errorf("State[rgbw] = %v, want r=16 g=32 b=48 w=64", state.State["rgbw"])
+		}
+		return
+	}
+	t.Error("Expected state message to be published")
+}
+
+func TestBridgeHVACCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		params   map[string]any
+		wantGA   GroupAddress
+		wantData []byte
+		wantErr  string
+	}{
+		{
+			name: "comfort", command: "set_hvac_mode", params: map[string]any{"hvac_mode": "comfort"},
+			wantGA: GroupAddress{Main: 3, Middle: 1, Sub: 1}, wantData: []byte{0x01},
+		},
+		{
+			name: "frost alias", command: "set_hvac_mode", params: map[string]any{"hvac_mode": "frost"},
+			wantGA: GroupAddress{Main: 3, Middle: 1, Sub: 1}, wantData: []byte{0x04},
+		},
+		{
+			name: "shift down two steps", command: "shift_setpoint", params: map[string]any{"shift": -2.0},
+			wantGA: GroupAddress{Main: 3, Middle: 1, Sub: 3}, wantData: []byte{0xFE},
+		},
+		{
+			name: "unknown mode", command: "set_hvac_mode", params: map[string]any{"hvac_mode": "turbo"},
+			wantErr: ErrCodeInvalidParameters,
+		},
+		{
+			name: "fractional shift", command: "shift_setpoint", params: map[string]any{"shift": 1.5},
+			wantErr: ErrCodeInvalidParameters,
+		},
+		{
+			name: "shift out of range", command: "shift_setpoint", params: map[string]any{"shift": 200.0},
+			wantErr: ErrCodeInvalidParameters,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mqtt := NewMockMQTTClient()
+			knxd := NewMockConnector()
+			b := createTestBridge(t, BridgeOptions{
+				Config:     createTestConfig(),
+				MQTTClient: mqtt,
+				KNXDClient: knxd,
+			})
+			if err := b.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error: %v", err)
+			}
+			defer b.Stop()
+			mqtt.ClearPublished()
+
+			cmdPayload, _ := json.Marshal(CommandMessage{
+				ID:         "cmd-hvac",
+				DeviceID:   "thermostat-living",
+				Command:    tt.command,
+				Parameters: tt.params,
+				Timestamp:  time.Now().UTC(),
+			})
+			b.handleMQTTMessage("graylogic/command/knx/thermostat-living", cmdPayload)
+
+			telegrams := knxd.GetSentTelegrams()
+			if tt.wantErr != "" {
+				if len(telegrams) != 0 {
+					t.Errorf("sent %d telegrams, want none", len(telegrams))
+				}
+				hasErrorAck := false
+				for _, p := range mqtt.GetPublished() {
+					var ack AckMessage
+					if err := json.Unmarshal(p.Payload, &ack); err == nil && ack.Error != nil && ack.Error.Code == tt.wantErr {
+						hasErrorAck = true
+					}
+				}
+				if !hasErrorAck {
+					t.Errorf("expected %s error ack", tt.wantErr)
+				}
+				return
+			}
+
+			if len(telegrams) != 1 {
+				t.Fatalf("sent %d telegrams, want 1", len(telegrams))
+			}
+			if telegrams[0].GA != tt.wantGA || !bytes.Equal(telegrams[0].Data, tt.wantData) {
+				t.Errorf("sent %v % X, want %v % X", telegrams[0].GA, telegrams[0].Data, tt.wantGA, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestBridgeGenericFunctions(t *testing.T) {
+	tests := []struct {
+		name      string
+		command   string
+		params    map[string]any
+		wantGA    GroupAddress
+		wantData  []byte
+		wantRead  bool
+		wantState any
+		wantErr   string
+	}{
+		{
+			name: "enable", command: "write_function", params: map[string]any{"function": "enable", "value": false},
+			wantGA: GroupAddress{Main: 4, Middle: 2, Sub: 1}, wantData: []byte{0x00}, wantState: false,
+		},
+		{
+			name: "raw percentage", command: "write_function", params: map[string]any{"function": "percentage", "value": 200.0},
+			wantGA: GroupAddress{Main: 4, Middle: 2, Sub: 2}, wantData: []byte{0xC8}, wantState: 200.0,
+		},
+		{
+			name: "scene number", command: "write_function", params: map[string]any{"function": "scene_number", "value": 5.0},
+			wantGA: GroupAddress{Main: 4, Middle: 2, Sub: 3}, wantData: []byte{0x05}, wantState: 5.0,
+		},
+		{
+			name: "scene learn", command: "write_function",
+			params: map[string]any{"function": "scene_control", "value": map[string]any{"scene": 2.0, "learn": true}},
+			wantGA: GroupAddress{Main: 4, Middle: 2, Sub: 4}, wantData: []byte{0x82},
+			wantState: map[string]any{"scene": 2.0, "learn": true},
+		},
+		{
+			name: "button led", command: "write_function", params: map[string]any{"function": "button_1_led", "value": true},
+			wantGA: GroupAddress{Main: 4, Middle: 2, Sub: 5}, wantData: []byte{0x01}, wantState: true,
+		},
+		{
+			name: "read percentage", command: "read_function", params: map[string]any{"function": "percentage"},
+			wantGA: GroupAddress{Main: 4, Middle: 2, Sub: 2}, wantRead: true,
+		},
+		{
+			name: "missing function", command: "write_function", params: map[string]any{"value": true},
+			wantErr: ErrCodeInvalidParameters,
+		},
+		{
+			name: "unknown function", command: "write_function", params: map[string]any{"function": "dim", "value": true},
+			wantErr: ErrCodeNotConfigured,
+		},
+		{
+			name: "not writable", command: "write_function", params: map[string]any{"function": "button_1", "value": true},
+			wantErr: ErrCodeNotConfigured,
+		},
+		{
+			name: "not readable", command: "read_function", params: map[string]any{"function": "enable"},
+			wantErr: ErrCodeNotConfigured,
+		},
+		{
+			name: "missing value", command: "write_function", params: map[string]any{"function": "enable"},
+			wantErr: ErrCodeInvalidParameters,
+		},
+		{
+			name: "scene out of range", command: "write_function", params: map[string]any{"function": "scene_number", "value": 65.0},
+			wantErr: ErrCodeInvalidParameters,
+		},
+		{
+			name: "wrong value type", command: "write_function", params: map[string]any{"function": "enable", "value": "yes"},
+			wantErr: ErrCodeInvalidParameters,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mqtt := NewMockMQTTClient()
+			knxd := NewMockConnector()
+			b := createTestBridge(t, BridgeOptions{
+				Config:     createTestConfig(),
+				MQTTClient: mqtt,
+				KNXDClient: knxd,
+			})
+			if err := b.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error: %v", err)
+			}
+			defer b.Stop()
+			mqtt.ClearPublished()
+
+			cmdPayload, _ := json.Marshal(CommandMessage{
+				ID:         "cmd-generic",
+				DeviceID:   "controller-hall",
+				Command:    tt.command,
+				Parameters: tt.params,
+				Timestamp:  time.Now().UTC(),
+			})
+			b.handleMQTTMessage("graylogic/command/knx/controller-hall", cmdPayload)
+
+			telegrams := knxd.GetSentTelegrams()
+			reads := knxd.GetReadRequests()
+			if tt.wantErr != "" {
+				if len(telegrams) != 0 || len(reads) != 0 {
+					t.Errorf("sent %d telegrams and %d reads, want none", len(telegrams), len(reads))
+				}
+				hasErrorAck := false
+				for _, p := range mqtt.GetPublished() {
+					var ack AckMessage
+					if err := json.Unmarshal(p.Payload, &ack); err == nil && ack.Error != nil && ack.Error.Code == tt.wantErr {
+						hasErrorAck = true
+					}
+				}
+				if !hasErrorAck {
+					t.Errorf("expected %s error ack", tt.wantErr)
+				}
+				return
+			}
+
+			if tt.wantRead {
+				if len(reads) != 1 || reads[0] != tt.wantGA || len(telegrams) != 0 {
+					t.Errorf("reads = %v, writes = %d; want one read of %v", reads, len(telegrams), tt.wantGA)
+				}
+				return
+			}
+
+			if len(telegrams) != 1 {
+				t.Fatalf("sent %d telegrams, want 1", len(telegrams))
+			}
+			if telegrams[0].GA != tt.wantGA || !bytes.Equal(telegrams[0].Data, tt.wantData) {
+				t.Errorf("sent %v % X, want %v % X", telegrams[0].GA, telegrams[0].Data, tt.wantGA, tt.wantData)
+			}
+
+			// Write-through state carries the decoded value
+			found := false
+			for _, p := range mqtt.GetPublished() {
+				if p.Topic != StateTopic(tt.wantGA.String()) {
+					continue
+				}
+				var state StateMessage
+				if err := json.Unmarshal(p.Payload, &state); err != nil {
+					t.Fatalf("Failed to unmarshal state: %v", err)
+				}
+				stateKey := StateKeyForFunction(tt.params["function"].(string))
+				got, _ := json.Marshal(state.State[stateKey])
+				want, _ := json.Marshal(tt.wantState)
+				if !bytes.Equal(got, want) {
+					t.Errorf("state[%s] = %s, want %s", stateKey, got, want)
+				}
+				found = true
+			}
+			if !found {
+				t.Error("no write-through state published")
+			}
+		})
+	}
+}
+
+func TestBridgeKNXTelegramHVACState(t *testing.T) {
+	mqtt := NewMockMQTTClient()
+	knxd := NewMockConnector()
+	b := createTestBridge(t, BridgeOptions{
+		Config:     createTestConfig(),
+		MQTTClient: mqtt,
+		KNXDClient: knxd,
+	})
+	if err := b.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer b.Stop()
+	mqtt.ClearPublished()
+
+	knxd.SimulateTelegram(Telegram{
+		Destination: GroupAddress{Main: 3, Middle: 1, Sub: 2}, // hvac_mode_status
+		APCI:        APCIWrite,
+		Data:        []byte{0x03},
+	})
+	knxd.SimulateTelegram(Telegram{
+		Destination: GroupAddress{Main: 3, Middle: 1, Sub: 4}, // hvac_control_mode_status
+		APCI:        APCIWrite,
+		Data:        []byte{0x01},
+	})
+	time.Sleep(50 * time.Millisecond)
+
+	want := map[string][2]string{
+		StateTopic("3/1/2"): {"hvac_mode", "economy"},
+		StateTopic("3/1/4"): {"hvac_control_mode", "heat"},
+	}
+	for _, p := range mqtt.GetPublished() {
+		w, ok := want[p.Topic]
+		if !ok {
+			continue
+		}
+		delete(want, p.Topic)
+		var state StateMessage
+		if err := json.Unmarshal(p.Payload, &state); err != nil {
+			t.Fatalf("Failed to unmarshal state: %v", err)
+		}
+		if state.State[w[0]] != w[1] {
+			t.Errorf("State[%s] = %v, want %q", w[0], state.State[w[0]], w[1])
+		}
+	}
+	for topic := range want {
+		t.Errorf("no state published on %s", topic)
+	}
+}
+
 func TestResolveFunction(t *testing.T) {
 	deviceGAs := map[string]AddressConfig{
 		"switch":             {GA: "1/0/1", DPT: "1.001"},
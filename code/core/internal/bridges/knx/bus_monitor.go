@@ -0,0 +1,403 @@
+package knx
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bus monitor defaults.
+const (
+	// DefaultMonitorSize is how many telegrams the bus monitor keeps by default.
+	DefaultMonitorSize = 5000
+
+	// MaxMonitorSize bounds the bus monitor buffer.
+	MaxMonitorSize = 100000
+
+	// captureNamespace is the namespace of ETS telegram capture files.
+	captureNamespace = "http://knx.org/xml/telegrams/01"
+
+	// captureTimeFormat is the timestamp format ETS writes in captures.
+	captureTimeFormat = "2006-01-02T15:04:05.0000000Z"
+)
+
+// Telegram directions in the bus monitor.
+const (
+	// MonitorRx is a telegram received from the bus.
+	MonitorRx = "rx"
+
+	// MonitorTx is a telegram the bridge sent.
+	MonitorTx = "tx"
+)
+
+// MonitorEntry is one telegram in the bus monitor.
+type MonitorEntry struct {
+	Seq          uint64          `json:"seq"` // Increases by one per telegram
+	Timestamp    time.Time       `json:"timestamp"`
+	Direction    string          `json:"direction"` // "rx" or "tx"
+	Source       string          `json:"source,omitempty"`
+	GroupAddress string          `json:"group_address"`
+	APCI         string          `json:"apci"` // "write", "read", "response" or "secure"
+	Data         string          `json:"data"` // Raw payload, hex
+	DPT          string          `json:"dpt,omitempty"`
+	Value        any             `json:"value,omitempty"`
+	DeviceID     string          `json:"device_id,omitempty"` // First mapped device
+	Devices      []MonitorDevice `json:"devices,omitempty"`
+
+	telegram Telegram // For capture export
+}
+
+// MonitorDevice is a device function mapped to a telegram's group address.
+type MonitorDevice struct {
+	DeviceID string `json:"device_id"`
+	Function string `json:"function"`
+}
+
+// MonitorFilter selects bus monitor entries. Empty fields match everything.
+type MonitorFilter struct {
+	// GroupAddresses matches exact addresses ("1/2/3") or groups by prefix
+	// ("1/2" matches 1/2/0-255, "1" matches main group 1).
+	GroupAddresses []string
+
+	Source    string // Individual address of the sender
+	DeviceID  string // Any mapped device
+	APCI      string // "write", "read", "response" or "secure"
+	Direction string // "rx" or "tx"
+
+	// Since returns only entries with a greater Seq, for polling.
+	Since uint64
+
+	// Limit returns only the newest entries (0 = all).
+	Limit int
+}
+
+// BusMonitor keeps the most recent telegrams in a ring buffer, for live
+// diagnostics without an ETS group monitor.
+//
+// Thread Safety: All methods are safe for concurrent use.
+type BusMonitor struct {
+	mu      sync.RWMutex
+	entries []MonitorEntry // Ring buffer
+	next    int            // Index of the next write
+	full    bool
+	seq     uint64
+
+	onEntryMu sync.RWMutex
+	onEntry   func(MonitorEntry)
+}
+
+// NewBusMonitor creates a bus monitor keeping size telegrams.
+//
+// Parameters:
+//   - size: Buffer capacity (at least 1)
+//
+// Returns:
+//   - *BusMonitor: Empty monitor
+func NewBusMonitor(size int) *BusMonitor {
+	return &BusMonitor{entries: make([]MonitorEntry, max(size, 1))}
+}
+
+// SetOnEntry sets a callback for each recorded telegram, for live streaming.
+// It runs on the bus receive and send paths and must not block.
+//
+// Parameters:
+//   - callback: Function called with each new entry (nil to disable)
+func (m *BusMonitor) SetOnEntry(callback func(MonitorEntry)) {
+	m.onEntryMu.Lock()
+	m.onEntry = callback
+	m.onEntryMu.Unlock()
+}
+
+// Record adds a telegram to the monitor.
+//
+// Parameters:
+//   - t: Telegram as received or sent
+//   - direction: MonitorRx or MonitorTx
+//   - mappings: Device functions mapped to the group address; the first
+//     one's DPT decodes the value
+func (m *BusMonitor) Record(t Telegram, direction string, mappings []GAMapping) {
+	entry := MonitorEntry{
+		Timestamp:    t.Timestamp,
+		Direction:    direction,
+		Source:       t.Source,
+		GroupAddress: t.Destination.String(),
+		APCI:         apciName(t.APCI),
+		Data:         strings.ToUpper(hex.EncodeToString(t.Data)),
+		telegram:     t,
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	entry.Timestamp = entry.Timestamp.UTC()
+
+	if len(mappings) > 0 {
+		entry.DeviceID = mappings[0].DeviceID
+		entry.Devices = make([]MonitorDevice, 0, len(mappings))
+		for _, mp := range mappings {
+			entry.Devices = append(entry.Devices, MonitorDevice{DeviceID: mp.DeviceID, Function: mp.Function})
+		}
+		entry.DPT = mappings[0].DPT
+		if entry.DPT == "" {
+			entry.DPT = inferDPTFromFunction(mappings[0].Function)
+		}
+		if (t.APCI == APCIWrite || t.APCI == APCIResponse) && entry.DPT != "" {
+			if value, err := DecodeValue(entry.DPT, t.Data); err == nil {
+				entry.Value = value
+			}
+		}
+	}
+
+	m.mu.Lock()
+	m.seq++
+	entry.Seq = m.seq
+	m.entries[m.next] = entry
+	m.next = (m.next + 1) % len(m.entries)
+	if m.next == 0 {
+		m.full = true
+	}
+	m.mu.Unlock()
+
+	m.onEntryMu.RLock()
+	callback := m.onEntry
+	m.onEntryMu.RUnlock()
+	if callback != nil {
+		callback(entry)
+	}
+}
+
+// Telegrams returns the entries matching the filter, oldest first.
+//
+// Parameters:
+//   - filter: Selection criteria
+//
+// Returns:
+//   - []MonitorEntry: Matching entries (never nil)
+func (m *BusMonitor) Telegrams(filter MonitorFilter) []MonitorEntry {
+	m.mu.RLock()
+	ordered := make([]MonitorEntry, 0, m.lenLocked())
+	if m.full {
+		ordered = append(ordered, m.entries[m.next:]...)
+	}
+	ordered = append(ordered, m.entries[:m.next]...)
+	m.mu.RUnlock()
+
+	out := make([]MonitorEntry, 0, len(ordered))
+	for _, e := range ordered {
+		if filter.matches(e) {
+			out = append(out, e)
+		}
+	}
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[len(out)-filter.Limit:]
+	}
+	return out
+}
+
+// Len returns the number of telegrams in the buffer.
+func (m *BusMonitor) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lenLocked()
+}
+
+// Capacity returns the buffer size.
+func (m *BusMonitor) Capacity() int {
+	return len(m.entries)
+}
+
+// lenLocked returns the number of entries. Callers hold mu.
+func (m *BusMonitor) lenLocked() int {
+	if m.full {
+		return len(m.entries)
+	}
+	return m.next
+}
+
+// WriteCapture writes the matching telegrams as an ETS telegram capture
+// (CommunicationLog XML), which the ETS group monitor can open.
+//
+// Parameters:
+//   - w: Destination
+//   - filter: Selection criteria
+//
+// Returns:
+//   - error: If writing fails
+func (m *BusMonitor) WriteCapture(w io.Writer, filter MonitorFilter) error {
+	entries := m.Telegrams(filter)
+
+	type captureRecord struct {
+		Timestamp     string `xml:"Timestamp,attr"`
+		Mode          string `xml:"Mode,attr,omitempty"`
+		Host          string `xml:"Host,attr,omitempty"`
+		ConnectorType string `xml:"ConnectorType,attr,omitempty"`
+		MediumType    string `xml:"MediumType,attr,omitempty"`
+	}
+	type captureTelegram struct {
+		Timestamp   string `xml:"Timestamp,attr"`
+		Service     string `xml:"Service,attr"`
+		FrameFormat string `xml:"FrameFormat,attr"`
+		RawData     string `xml:"RawData,attr"`
+	}
+	type captureLog struct {
+		XMLName     xml.Name          `xml:"CommunicationLog"`
+		Xmlns       string            `xml:"xmlns,attr"`
+		RecordStart captureRecord     `xml:"RecordStart"`
+		Telegrams   []captureTelegram `xml:"Telegram"`
+		RecordStop  captureRecord     `xml:"RecordStop"`
+	}
+
+	start, stop := time.Now().UTC(), time.Now().UTC()
+	if len(entries) > 0 {
+		start, stop = entries[0].Timestamp, entries[len(entries)-1].Timestamp
+	}
+	log := captureLog{
+		Xmlns: captureNamespace,
+		RecordStart: captureRecord{
+			Timestamp:     start.Format(captureTimeFormat),
+			Mode:          "LinkLayer",
+			Host:          "Gray Logic",
+			ConnectorType: "KnxIpTunneling",
+			MediumType:    "TP",
+		},
+		Telegrams:  make([]captureTelegram, 0, len(entries)),
+		RecordStop: captureRecord{Timestamp: stop.Format(captureTimeFormat)},
+	}
+	for _, e := range entries {
+		service, code := "L_Data.ind", byte(cemiLDataInd)
+		if e.Direction == MonitorTx {
+			service, code = "L_Data.req", cemiLDataReq
+		}
+		log.Telegrams = append(log.Telegrams, captureTelegram{
+			Timestamp:   e.Timestamp.Format(captureTimeFormat),
+			Service:     service,
+			FrameFormat: "CommonEmi",
+			RawData:     strings.ToUpper(hex.EncodeToString(captureFrame(code, e.telegram))),
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("writing capture: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(log); err != nil {
+		return fmt.Errorf("writing capture: %w", err)
+	}
+	return nil
+}
+
+// captureFrame encodes a telegram as a cEMI frame with its source address.
+func captureFrame(code byte, t Telegram) []byte {
+	frame := encodeCEMI(code, t)
+	if src, err := parseIndividualAddress(t.Source); err == nil {
+		frame[4], frame[5] = byte(src>>8), byte(src) //nolint:gosec,mnd // cEMI source address bytes
+	}
+	return frame
+}
+
+// matches reports whether an entry passes the filter.
+func (f MonitorFilter) matches(e MonitorEntry) bool {
+	if e.Seq <= f.Since {
+		return false
+	}
+	if f.Source != "" && e.Source != f.Source {
+		return false
+	}
+	if f.APCI != "" && e.APCI != f.APCI {
+		return false
+	}
+	if f.Direction != "" && e.Direction != f.Direction {
+		return false
+	}
+	if f.DeviceID != "" && !e.hasDevice(f.DeviceID) {
+		return false
+	}
+	if len(f.GroupAddresses) > 0 {
+		for _, ga := range f.GroupAddresses {
+			if e.GroupAddress == ga || strings.HasPrefix(e.GroupAddress, ga+"/") {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// hasDevice reports whether a device is mapped to the entry's group address.
+func (e MonitorEntry) hasDevice(deviceID string) bool {
+	for _, d := range e.Devices {
+		if d.DeviceID == deviceID {
+			return true
+		}
+	}
+	return false
+}
+
+// apciName returns the bus monitor name of an APCI.
+func apciName(apci byte) string {
+	switch apci {
+	case APCIRead:
+		return "read"
+	case APCIResponse:
+		return "response"
+	case APCIWrite:
+		return "write"
+	case APCISecure:
+		return "secure"
+	default:
+		return "unknown"
+	}
+}
+
+// monitoredConnector records telegrams the bridge sends in the bus monitor
+// and statistics.
+type monitoredConnector struct {
+	Connector
+	record func(t Telegram, direction string)
+}
+
+// Send sends a group write and records it.
+func (c *monitoredConnector) Send(ctx context.Context, ga GroupAddress, data []byte) error {
+	if err := c.Connector.Send(ctx, ga, data); err != nil {
+		return err
+	}
+	c.record(c.withSource(NewWriteTelegram(ga, data)), MonitorTx)
+	return nil
+}
+
+// SendRead sends a group read and records it.
+func (c *monitoredConnector) SendRead(ctx context.Context, ga GroupAddress) error {
+	if err := c.Connector.SendRead(ctx, ga); err != nil {
+		return err
+	}
+	c.record(c.withSource(NewReadTelegram(ga)), MonitorTx)
+	return nil
+}
+
+// SendResponse sends a group response and records it.
+func (c *monitoredConnector) SendResponse(ctx context.Context, ga GroupAddress, data []byte) error {
+	if err := c.Connector.SendResponse(ctx, ga, data); err != nil {
+		return err
+	}
+	c.record(c.withSource(NewResponseTelegram(ga, data)), MonitorTx)
+	return nil
+}
+
+// IndividualAddress returns the wrapped connector's address, if it has one.
+func (c *monitoredConnector) IndividualAddress() string {
+	if r, ok := c.Connector.(addressReporter); ok {
+		return r.IndividualAddress()
+	}
+	return ""
+}
+
+// withSource fills in the bridge's individual address if the connector knows it.
+func (c *monitoredConnector) withSource(t Telegram) Telegram {
+	t.Source = c.IndividualAddress()
+	return t
+}
@@ -0,0 +1,196 @@
+package knx
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestBusMonitorRing(t *testing.T) {
+	m := NewBusMonitor(3)
+	for i := range 5 {
+		m.Record(NewWriteTelegram(GroupAddress{Main: 1, Middle: 0, Sub: uint8(i)}, []byte{0x01}), MonitorRx, nil)
+	}
+
+	if m.Len() != 3 || m.Capacity() != 3 {
+		t.Errorf("Len() = %d, Capacity() = %d, want 3, 3", m.Len(), m.Capacity())
+	}
+	got := m.Telegrams(MonitorFilter{})
+	if len(got) != 3 || got[0].Seq != 3 || got[2].Seq != 5 || got[2].GroupAddress != "1/0/4" {
+		t.Errorf("Telegrams() = %+v, want seq 3-5 oldest first", got)
+	}
+	if got := m.Telegrams(MonitorFilter{Since: 4}); len(got) != 1 || got[0].Seq != 5 {
+		t.Errorf("Telegrams(Since: 4) = %+v, want seq 5", got)
+	}
+	if got := m.Telegrams(MonitorFilter{Limit: 2}); len(got) != 2 || got[0].Seq != 4 {
+		t.Errorf("Telegrams(Limit: 2) = %+v, want seq 4-5", got)
+	}
+}
+
+func TestBusMonitorRecord(t *testing.T) {
+	m := NewBusMonitor(10)
+	var streamed []MonitorEntry
+	m.SetOnEntry(func(e MonitorEntry) { streamed = append(streamed, e) })
+
+	mappings := []GAMapping{
+		{DeviceID: "sensor-hall", Function: "temperature", DPT: "9.001"},
+		{DeviceID: "display-hall", Function: "temperature"},
+	}
+	m.Record(Telegram{
+		Source:      "1.1.5",
+		Destination: GroupAddress{Main: 6, Middle: 0, Sub: 1},
+		APCI:        APCIWrite,
+		Data:        []byte{0x0C, 0x1A},
+	}, MonitorRx, mappings)
+	m.Record(NewReadTelegram(GroupAddress{Main: 6, Middle: 0, Sub: 2}), MonitorTx, nil)
+
+	if len(streamed) != 2 {
+		t.Fatalf("streamed %d entries, want 2", len(streamed))
+	}
+	e := streamed[0]
+	if e.Source != "1.1.5" || e.GroupAddress != "6/0/1" || e.APCI != "write" || e.Data != "0C1A" || e.Direction != MonitorRx {
+		t.Errorf("entry = %+v", e)
+	}
+	if e.DPT != "9.001" || e.Value != 21.0 || e.DeviceID != "sensor-hall" || len(e.Devices) != 2 {
+		t.Errorf("decoded entry = %+v, want 9.001 value 21 from two devices", e)
+	}
+	if streamed[1].APCI != "read" || streamed[1].Value != nil || streamed[1].Timestamp.IsZero() {
+		t.Errorf("read entry = %+v", streamed[1])
+	}
+
+	tests := []struct {
+		name   string
+		filter MonitorFilter
+		want   int
+	}{
+		{"group prefix", MonitorFilter{GroupAddresses: []string{"6/0"}}, 2},
+		{"group no partial match", MonitorFilter{GroupAddresses: []string{"6/0/1"}}, 1},
+		{"other main group", MonitorFilter{GroupAddresses: []string{"60"}}, 0},
+		{"device", MonitorFilter{DeviceID: "display-hall"}, 1},
+		{"source", MonitorFilter{Source: "1.1.5"}, 1},
+		{"apci", MonitorFilter{APCI: "read"}, 1},
+		{"direction", MonitorFilter{Direction: MonitorTx}, 1},
+	}
+	for _, tt := range tests {
+		if got := len(m.Telegrams(tt.filter)); got != tt.want {
+			t.Errorf("%s: %d entries, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBusMonitorCapture(t *testing.T) {
+	m := NewBusMonitor(10)
+	m.Record(Telegram{
+		Source:      "1.1.5",
+		Destination: GroupAddress{Main: 1, Middle: 0, Sub: 1},
+		APCI:        APCIWrite,
+		Data:        []byte{0x01},
+		Timestamp:   time.Date(2026, 10, 16, 12, 0, 0, 123456700, time.UTC),
+	}, MonitorRx, nil)
+	m.Record(NewWriteTelegram(GroupAddress{Main: 1, Middle: 0, Sub: 1}, []byte{0x00}), MonitorTx, nil)
+
+	var buf bytes.Buffer
+	if err := m.WriteCapture(&buf, MonitorFilter{}); err != nil {
+		t.Fatalf("WriteCapture() error: %v", err)
+	}
+
+	var capture struct {
+		XMLName   xml.Name `xml:"http://knx.org/xml/telegrams/01 CommunicationLog"`
+		Telegrams []struct {
+			Timestamp string `xml:"Timestamp,attr"`
+			Service   string `xml:"Service,attr"`
+			RawData   string `xml:"RawData,attr"`
+		} `xml:"Telegram"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &capture); err != nil {
+		t.Fatalf("capture is not valid XML: %v\n%s", err, buf.String())
+	}
+	if len(capture.Telegrams) != 2 {
+		t.Fatalf("capture has %d telegrams, want 2", len(capture.Telegrams))
+	}
+	rx := capture.Telegrams[0]
+	// L_Data.ind, standard frame, 1.1.5 -> 1/0/1, write 1
+	if rx.Service != "L_Data.ind" || rx.RawData != "2900BCE011050801010081" || rx.Timestamp != "2026-10-16T12:00:00.1234567Z" {
+		t.Errorf("rx telegram = %+v", rx)
+	}
+	if tx := capture.Telegrams[1]; tx.Service != "L_Data.req" || tx.RawData != "1100BCE000000801010080" {
+		t.Errorf("tx telegram = %+v", tx)
+	}
+}
+
+func TestBridgeBusMonitor(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Monitor.Size = 10
+	knxd := NewMockConnector()
+	b := createTestBridge(t, BridgeOptions{Config: cfg, MQTTClient: NewMockMQTTClient(), KNXDClient: knxd})
+	if err := b.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer b.Stop()
+
+	knxd.SimulateTelegram(Telegram{Source: "1.1.5", Destination: GroupAddress{Main: 1, Middle: 2, Sub: 4}, APCI: APCIWrite, Data: []byte{0x01}})
+	if err := b.knxd.SendResponse(context.Background(), GroupAddress{Main: 1, Middle: 2, Sub: 4}, []byte{0x00}); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	var rx, tx *MonitorEntry
+	for _, e := range b.BusMonitor().Telegrams(MonitorFilter{}) {
+		switch {
+		case e.Direction == MonitorRx && e.GroupAddress == "1/2/4":
+			rx = &e
+		case e.Direction == MonitorTx && e.GroupAddress == "1/2/4":
+			tx = &e
+		}
+	}
+	if rx == nil || rx.DeviceID != "light-living-main" || rx.Value != true {
+		t.Errorf("received telegram entry = %+v", rx)
+	}
+	if tx == nil || tx.APCI != "response" || tx.Value != false {
+		t.Errorf("sent telegram entry = %+v", tx)
+	}
+	if len(knxd.GetResponses()) != 1 {
+		t.Error("monitored send did not reach the connector")
+	}
+
+	// Disabled when the size is 0
+	plain := createTestBridge(t, BridgeOptions{Config: createTestConfig(), MQTTClient: NewMockMQTTClient(), KNXDClient: NewMockConnector()})
+	if plain.BusMonitor() != nil {
+		t.Error("BusMonitor() with size 0 is not nil")
+	}
+}
+
+func TestBridgeBusMonitorCoalesced(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Monitor.Size = 10
+	cfg.Scheduler = SchedulerSettings{Rate: 5, QueueSize: 10} // 200ms between telegrams
+	knxd := NewMockConnector()
+	b := createTestBridge(t, BridgeOptions{Config: cfg, MQTTClient: NewMockMQTTClient(), KNXDClient: knxd})
+	defer b.Stop()
+
+	// The first write is sent at once; two writes to 1/2/5 queued behind it
+	// are merged and sent once with the newest value
+	first := GroupAddress{Main: 1, Middle: 2, Sub: 3}
+	if err := b.knxd.Send(context.Background(), first, []byte{0x01}); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	ga := GroupAddress{Main: 1, Middle: 2, Sub: 5}
+	older, _ := queueSends(t, b.scheduler, PriorityInteractive, []GroupAddress{ga}, 0x40)
+	newer, _ := queueSends(t, b.scheduler, PriorityInteractive, []GroupAddress{ga}, 0x80)
+	older.Wait()
+	newer.Wait()
+
+	var tx []MonitorEntry
+	for _, e := range b.BusMonitor().Telegrams(MonitorFilter{}) {
+		if e.Direction == MonitorTx && e.GroupAddress == "1/2/5" {
+			tx = append(tx, e)
+		}
+	}
+	if len(tx) != 1 || tx[0].Data != "80" {
+		t.Errorf("recorded writes to 1/2/5 = %+v, want one with the newest value", tx)
+	}
+	if sent := knxd.GetSentTelegrams(); len(sent) != 2 {
+		t.Errorf("sent %d telegrams, want 2", len(sent))
+	}
+}
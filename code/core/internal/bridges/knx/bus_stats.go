@@ -0,0 +1,317 @@
+package knx
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bus statistics defaults and limits.
+const (
+	// DefaultStatsWindow is the default period covered by the rolling
+	// statistics (seconds).
+	DefaultStatsWindow = 300
+
+	// MaxStatsWindow is the longest configurable statistics window (seconds).
+	MaxStatsWindow = 3600
+
+	// DefaultStatsSampleInterval is the default time between samples (seconds).
+	DefaultStatsSampleInterval = 10
+
+	// DefaultStatsAlertLoad is the default bus load that raises an alert
+	// (percent of line capacity).
+	DefaultStatsAlertLoad = 60
+
+	// LineCapacity is the approximate number of telegrams per second a
+	// 9600 baud TP line carries at 100% load (short group telegrams with
+	// acknowledgement). Bus load percentages are relative to it.
+	LineCapacity = 50
+
+	// sampleTop is how many top sources and group addresses a sample holds.
+	sampleTop = 10
+)
+
+// BusStatsSnapshot is the bus traffic over a period.
+type BusStatsSnapshot struct {
+	WindowSeconds      int     `json:"window_seconds"`
+	Telegrams          int     `json:"telegrams"`
+	TelegramsPerSecond float64 `json:"telegrams_per_second"`
+	PeakPerSecond      int     `json:"peak_per_second"` // Busiest single second
+	LoadPercent        float64 `json:"load_percent"`    // Relative to LineCapacity
+
+	Reads         int     `json:"reads"`
+	Writes        int     `json:"writes"`
+	Responses     int     `json:"responses"`
+	ReadRatio     float64 `json:"read_ratio"` // Share of all telegrams, 0-1
+	WriteRatio    float64 `json:"write_ratio"`
+	ResponseRatio float64 `json:"response_ratio"`
+
+	Lines             []LineStats    `json:"lines"`               // By source line, busiest first
+	TopSources        []TrafficStats `json:"top_sources"`         // By source individual address
+	TopGroupAddresses []TrafficStats `json:"top_group_addresses"` // By destination group address
+}
+
+// LineStats is the traffic from one line (area.line of the sender).
+type LineStats struct {
+	Line               string  `json:"line"`
+	Telegrams          int     `json:"telegrams"`
+	TelegramsPerSecond float64 `json:"telegrams_per_second"`
+	LoadPercent        float64 `json:"load_percent"`
+}
+
+// TrafficStats is the traffic from one source or to one group address.
+type TrafficStats struct {
+	Address            string  `json:"address"`
+	Telegrams          int     `json:"telegrams"`
+	TelegramsPerSecond float64 `json:"telegrams_per_second"`
+	Share              float64 `json:"share"` // Share of all telegrams, 0-1
+}
+
+// BusLoadAlert reports bus load crossing the alert threshold.
+type BusLoadAlert struct {
+	Active      bool    // true when the load rose above the threshold, false when it fell back
+	LoadPercent float64 // Load over the last sample interval
+	Threshold   int     // Configured alert_load
+	TopSources  []TrafficStats
+}
+
+// statsBucket counts the telegrams of one second.
+type statsBucket struct {
+	second    int64
+	total     int
+	reads     int
+	writes    int
+	responses int
+	sources   map[string]int
+	groups    map[string]int
+}
+
+// BusStats keeps rolling bus traffic statistics in one-second buckets:
+// load per line, top talkers by source and group address, and the mix of
+// reads, writes and responses. It samples them periodically for the TSDB
+// and raises an alert while the load is above a threshold.
+//
+// Thread Safety: All methods are safe for concurrent use.
+type BusStats struct {
+	mu      sync.Mutex
+	buckets []statsBucket // Ring indexed by second % window
+	now     func() time.Time
+
+	sampleInterval time.Duration
+	alertLoad      int
+	alerting       bool
+
+	callbackMu sync.RWMutex
+	onSample   func(BusStatsSnapshot)
+	onAlert    func(BusLoadAlert)
+}
+
+// NewBusStats creates bus statistics from the stats settings.
+//
+// Parameters:
+//   - settings: Window, sample interval and alert threshold
+//
+// Returns:
+//   - *BusStats: Statistics with no traffic recorded
+func NewBusStats(settings StatsSettings) *BusStats {
+	return &BusStats{
+		buckets:        make([]statsBucket, max(settings.Window, 1)),
+		now:            time.Now,
+		sampleInterval: time.Duration(max(settings.SampleInterval, 1)) * time.Second,
+		alertLoad:      settings.AlertLoad,
+	}
+}
+
+// SetOnSample sets a callback for each periodic sample (e.g. to write it
+// to the TSDB).
+//
+// Parameters:
+//   - callback: Function called with the traffic of the last sample interval
+func (s *BusStats) SetOnSample(callback func(BusStatsSnapshot)) {
+	s.callbackMu.Lock()
+	s.onSample = callback
+	s.callbackMu.Unlock()
+}
+
+// SetOnAlert sets a callback for bus load alerts.
+//
+// Parameters:
+//   - callback: Function called when the load crosses the threshold
+func (s *BusStats) SetOnAlert(callback func(BusLoadAlert)) {
+	s.callbackMu.Lock()
+	s.onAlert = callback
+	s.callbackMu.Unlock()
+}
+
+// Window returns the period the statistics cover.
+func (s *BusStats) Window() time.Duration {
+	return time.Duration(len(s.buckets)) * time.Second
+}
+
+// Record counts a telegram seen on or sent to the bus.
+func (s *BusStats) Record(t Telegram) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	second := s.now().Unix()
+	b := &s.buckets[second%int64(len(s.buckets))]
+	if b.second != second {
+		*b = statsBucket{second: second, sources: make(map[string]int), groups: make(map[string]int)}
+	}
+	b.total++
+	switch t.APCI {
+	case APCIRead:
+		b.reads++
+	case APCIWrite:
+		b.writes++
+	case APCIResponse:
+		b.responses++
+	}
+	if t.Source != "" {
+		b.sources[t.Source]++
+	}
+	b.groups[t.Destination.String()]++
+}
+
+// Snapshot returns the traffic over the last period.
+//
+// Parameters:
+//   - period: Time to cover, capped at the window
+//   - top: Maximum number of top sources and group addresses
+//
+// Returns:
+//   - BusStatsSnapshot: Aggregated statistics
+func (s *BusStats) Snapshot(period time.Duration, top int) BusStatsSnapshot {
+	seconds := min(max(int(period/time.Second), 1), len(s.buckets))
+	snap := BusStatsSnapshot{WindowSeconds: seconds}
+	sources := make(map[string]int)
+	groups := make(map[string]int)
+
+	s.mu.Lock()
+	now := s.now().Unix()
+	for i := range s.buckets {
+		b := &s.buckets[i]
+		if b.total == 0 || b.second <= now-int64(seconds) || b.second > now {
+			continue
+		}
+		snap.Telegrams += b.total
+		snap.PeakPerSecond = max(snap.PeakPerSecond, b.total)
+		snap.Reads += b.reads
+		snap.Writes += b.writes
+		snap.Responses += b.responses
+		for source, n := range b.sources {
+			sources[source] += n
+		}
+		for ga, n := range b.groups {
+			groups[ga] += n
+		}
+	}
+	s.mu.Unlock()
+
+	perSecond := func(n int) float64 { return float64(n) / float64(seconds) }
+	share := func(n int) float64 {
+		if snap.Telegrams == 0 {
+			return 0
+		}
+		return float64(n) / float64(snap.Telegrams)
+	}
+
+	snap.TelegramsPerSecond = perSecond(snap.Telegrams)
+	snap.LoadPercent = loadPercent(snap.TelegramsPerSecond)
+	snap.ReadRatio = share(snap.Reads)
+	snap.WriteRatio = share(snap.Writes)
+	snap.ResponseRatio = share(snap.Responses)
+
+	lines := make(map[string]int)
+	for source, n := range sources {
+		if i := strings.LastIndexByte(source, '.'); i > 0 {
+			lines[source[:i]] += n
+		}
+	}
+	snap.Lines = make([]LineStats, 0, len(lines))
+	for _, line := range rankCounts(lines, len(lines)) {
+		tps := perSecond(lines[line])
+		snap.Lines = append(snap.Lines, LineStats{Line: line, Telegrams: lines[line], TelegramsPerSecond: tps, LoadPercent: loadPercent(tps)})
+	}
+
+	traffic := func(counts map[string]int) []TrafficStats {
+		ranked := rankCounts(counts, top)
+		out := make([]TrafficStats, 0, len(ranked))
+		for _, addr := range ranked {
+			out = append(out, TrafficStats{Address: addr, Telegrams: counts[addr], TelegramsPerSecond: perSecond(counts[addr]), Share: share(counts[addr])})
+		}
+		return out
+	}
+	snap.TopSources = traffic(sources)
+	snap.TopGroupAddresses = traffic(groups)
+
+	return snap
+}
+
+// run samples the statistics every sample interval until ctx is cancelled.
+func (s *BusStats) run(ctx context.Context) {
+	ticker := time.NewTicker(s.sampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sample()
+		}
+	}
+}
+
+// sample hands the last interval's traffic to the sample callback and
+// raises or clears the load alert.
+func (s *BusStats) sample() {
+	snap := s.Snapshot(s.sampleInterval, sampleTop)
+
+	s.callbackMu.RLock()
+	onSample, onAlert := s.onSample, s.onAlert
+	s.callbackMu.RUnlock()
+
+	if onSample != nil {
+		onSample(snap)
+	}
+	if s.alertLoad <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	over := snap.LoadPercent >= float64(s.alertLoad)
+	changed := over != s.alerting
+	s.alerting = over
+	s.mu.Unlock()
+
+	if changed && onAlert != nil {
+		onAlert(BusLoadAlert{Active: over, LoadPercent: snap.LoadPercent, Threshold: s.alertLoad, TopSources: snap.TopSources})
+	}
+}
+
+// loadPercent converts telegrams per second to percent of line capacity.
+func loadPercent(telegramsPerSecond float64) float64 {
+	return telegramsPerSecond / LineCapacity * 100 //nolint:mnd // percent
+}
+
+// rankCounts returns up to n keys with the highest counts, ties by key.
+func rankCounts(counts map[string]int, n int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		if c := cmp.Compare(counts[b], counts[a]); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	if n >= 0 && len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
@@ -0,0 +1,119 @@
+package knx
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestBusStats returns bus statistics on a clock the test controls.
+func newTestBusStats(settings StatsSettings) (*BusStats, *time.Time) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	s := NewBusStats(settings)
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func TestBusStatsSnapshot(t *testing.T) {
+	s, now := newTestBusStats(StatsSettings{Window: 60, SampleInterval: 10})
+
+	write := func(source string, sub uint8) Telegram {
+		return Telegram{Source: source, Destination: GroupAddress{Main: 1, Middle: 0, Sub: sub}, APCI: APCIWrite}
+	}
+	// A chattering sensor on line 1.1, one read and its response on 1.2
+	for range 8 {
+		s.Record(write("1.1.5", 1))
+	}
+	*now = now.Add(time.Second)
+	s.Record(write("1.1.6", 2))
+	s.Record(Telegram{Source: "1.2.1", Destination: GroupAddress{Main: 1, Middle: 0, Sub: 3}, APCI: APCIRead})
+	s.Record(Telegram{Source: "1.2.9", Destination: GroupAddress{Main: 1, Middle: 0, Sub: 3}, APCI: APCIResponse})
+
+	snap := s.Snapshot(10*time.Second, 2)
+	if snap.Telegrams != 11 || snap.PeakPerSecond != 8 || snap.TelegramsPerSecond != 1.1 {
+		t.Errorf("totals = %d telegrams, peak %d, %.2f/s; want 11, 8, 1.10", snap.Telegrams, snap.PeakPerSecond, snap.TelegramsPerSecond)
+	}
+	if snap.LoadPercent != 2.2 {
+		t.Errorf("LoadPercent = %v, want 2.2", snap.LoadPercent)
+	}
+	if snap.Writes != 9 || snap.Reads != 1 || snap.Responses != 1 || snap.ReadRatio != 1.0/11 {
+		t.Errorf("service mix = %d/%d/%d (read ratio %v)", snap.Writes, snap.Reads, snap.Responses, snap.ReadRatio)
+	}
+	if len(snap.Lines) != 2 || snap.Lines[0].Line != "1.1" || snap.Lines[0].Telegrams != 9 || snap.Lines[1].Line != "1.2" {
+		t.Errorf("Lines = %+v", snap.Lines)
+	}
+	if len(snap.TopSources) != 2 || snap.TopSources[0].Address != "1.1.5" || snap.TopSources[0].Share != 8.0/11 {
+		t.Errorf("TopSources = %+v", snap.TopSources)
+	}
+	if len(snap.TopGroupAddresses) != 2 || snap.TopGroupAddresses[0].Address != "1/0/1" || snap.TopGroupAddresses[1].Address != "1/0/3" {
+		t.Errorf("TopGroupAddresses = %+v", snap.TopGroupAddresses)
+	}
+
+	// Only the last second
+	if snap := s.Snapshot(time.Second, 10); snap.Telegrams != 3 {
+		t.Errorf("1s snapshot has %d telegrams, want 3", snap.Telegrams)
+	}
+
+	// Traffic ages out of the window
+	*now = now.Add(time.Minute)
+	if snap := s.Snapshot(time.Hour, 10); snap.Telegrams != 0 || snap.WindowSeconds != 60 || len(snap.Lines) != 0 {
+		t.Errorf("snapshot after the window = %+v", snap)
+	}
+}
+
+func TestBusStatsAlert(t *testing.T) {
+	s, now := newTestBusStats(StatsSettings{Window: 60, SampleInterval: 1, AlertLoad: 50})
+	var samples []BusStatsSnapshot
+	var alerts []BusLoadAlert
+	s.SetOnSample(func(snap BusStatsSnapshot) { samples = append(samples, snap) })
+	s.SetOnAlert(func(a BusLoadAlert) { alerts = append(alerts, a) })
+
+	burst := func(n int) {
+		for range n {
+			s.Record(Telegram{Source: "1.1.5", Destination: GroupAddress{Main: 1}, APCI: APCIWrite})
+		}
+	}
+
+	burst(10) // 20% of line capacity
+	s.sample()
+	*now = now.Add(time.Second)
+	burst(30) // 60%
+	s.sample()
+	s.sample() // Still high: no second alert
+	*now = now.Add(time.Second)
+	s.sample() // Quiet second
+
+	if len(samples) != 4 {
+		t.Errorf("got %d samples, want 4", len(samples))
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("got %d alerts, want 2: %+v", len(alerts), alerts)
+	}
+	if !alerts[0].Active || alerts[0].LoadPercent != 60 || alerts[0].Threshold != 50 || alerts[0].TopSources[0].Address != "1.1.5" {
+		t.Errorf("raised alert = %+v", alerts[0])
+	}
+	if alerts[1].Active {
+		t.Errorf("second alert = %+v, want cleared", alerts[1])
+	}
+}
+
+func TestBridgeBusStats(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Stats = StatsSettings{Window: 60, SampleInterval: 10}
+	knxd := NewMockConnector()
+	b := createTestBridge(t, BridgeOptions{Config: cfg, MQTTClient: NewMockMQTTClient(), KNXDClient: knxd})
+	if err := b.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer b.Stop()
+
+	knxd.SimulateTelegram(Telegram{Source: "1.1.5", Destination: GroupAddress{Main: 1, Middle: 2, Sub: 4}, APCI: APCIWrite, Data: []byte{0x01}})
+	if err := b.knxd.SendRead(t.Context(), GroupAddress{Main: 1, Middle: 2, Sub: 4}); err != nil {
+		t.Fatalf("SendRead() error: %v", err)
+	}
+
+	// Received and sent telegrams count, without the monitor enabled
+	snap := b.BusStats().Snapshot(time.Minute, 10)
+	if snap.Telegrams != 2 || snap.Reads != 1 || snap.Writes != 1 {
+		t.Errorf("snapshot = %+v, want one write and one read", snap)
+	}
+}
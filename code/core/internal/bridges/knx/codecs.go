@@ -0,0 +1,386 @@
+package knx
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// dptCodec converts between state values and KNX data for one DPT.
+//
+// Encoders accept values as they arrive in JSON command parameters
+// (bool, float64, string, map[string]any). Decoders return comparable
+// values (bool, float64, string or a struct) so state change detection
+// can use ==. A nil encode means the DPT is read-only for the bridge.
+type dptCodec struct {
+	encode func(value any) ([]byte, error)
+	decode func(data []byte) (any, error)
+}
+
+// dptCodecs is the codec registry, keyed by exact DPT ("5.004") or by main
+// type ("5") for codecs that apply to every subtype. Exact keys win.
+var dptCodecs = map[string]dptCodec{
+	"1": {
+		encode: func(v any) ([]byte, error) {
+			b, err := boolValue(v)
+			return EncodeDPT1(b), err
+		},
+		decode: func(d []byte) (any, error) { return DecodeDPT1(d) },
+	},
+	"5": {
+		encode: func(v any) ([]byte, error) {
+			n, err := rangeValue(v, 0, 100) //nolint:mnd // percent
+			return EncodeDPT5(n), err
+		},
+		decode: func(d []byte) (any, error) { return DecodeDPT5(d) },
+	},
+	string(DPTAngle): {
+		encode: func(v any) ([]byte, error) {
+			n, err := rangeValue(v, 0, dpt5AngleMax)
+			return EncodeDPT5Angle(n), err
+		},
+		decode: func(d []byte) (any, error) { return DecodeDPT5Angle(d) },
+	},
+	string(DPTPercentU8): {
+		encode: func(v any) ([]byte, error) {
+			n, err := wholeValue(v, 0, math.MaxUint8)
+			return []byte{uint8(n)}, err
+		},
+		decode: func(d []byte) (any, error) {
+			v, err := DecodeDPT20(d) // same 1-byte unsigned wire format
+			return float64(v), err
+		},
+	},
+	"6": {
+		encode: func(v any) ([]byte, error) {
+			n, err := wholeValue(v, math.MinInt8, math.MaxInt8)
+			return EncodeDPT6(int8(n)), err
+		},
+		decode: func(d []byte) (any, error) {
+			v, err := DecodeDPT6(d)
+			return float64(v), err
+		},
+	},
+	"7": {
+		encode: func(v any) ([]byte, error) {
+			n, err := wholeValue(v, 0, math.MaxUint16)
+			return EncodeDPT7(uint16(n)), err
+		},
+		decode: func(d []byte) (any, error) {
+			v, err := DecodeDPT7(d)
+			return float64(v), err
+		},
+	},
+	"9": {
+		encode: func(v any) ([]byte, error) {
+			n, err := numberValue(v)
+			if err != nil {
+				return nil, err
+			}
+			return EncodeDPT9(n)
+		},
+		decode: func(d []byte) (any, error) { return DecodeDPT9(d) },
+	},
+	"10": {
+		decode: func(d []byte) (any, error) {
+			v, err := DecodeDPT10(d)
+			return v.String(), err
+		},
+	},
+	"11": {
+		decode: func(d []byte) (any, error) {
+			v, err := DecodeDPT11(d)
+			return v.Format(time.DateOnly), err
+		},
+	},
+	"12": {
+		encode: func(v any) ([]byte, error) {
+			n, err := wholeValue(v, 0, math.MaxUint32)
+			return EncodeDPT12(uint32(n)), err
+		},
+		decode: func(d []byte) (any, error) {
+			v, err := DecodeDPT12(d)
+			return float64(v), err
+		},
+	},
+	"13": {
+		encode: func(v any) ([]byte, error) {
+			n, err := wholeValue(v, math.MinInt32, math.MaxInt32)
+			return EncodeDPT13(int32(n)), err
+		},
+		decode: func(d []byte) (any, error) {
+			v, err := DecodeDPT13(d)
+			return float64(v), err
+		},
+	},
+	"14": {
+		encode: func(v any) ([]byte, error) {
+			n, err := numberValue(v)
+			if err != nil {
+				return nil, err
+			}
+			return EncodeDPT14(n)
+		},
+		decode: func(d []byte) (any, error) { return DecodeDPT14(d) },
+	},
+	"17": {
+		encode: func(v any) ([]byte, error) {
+			n, err := wholeValue(v, 0, dpt17MaxScene)
+			if err != nil {
+				return nil, err
+			}
+			return EncodeDPT17(uint8(n))
+		},
+		decode: func(d []byte) (any, error) {
+			v, err := DecodeDPT17(d)
+			return float64(v), err
+		},
+	},
+	"18": {
+		encode: func(v any) ([]byte, error) {
+			sc, err := sceneControlValue(v)
+			if err != nil {
+				return nil, err
+			}
+			return EncodeDPT18(sc.Scene, sc.Learn)
+		},
+		decode: func(d []byte) (any, error) {
+			scene, learn, err := DecodeDPT18(d)
+			return SceneControl{Scene: scene, Learn: learn}, err
+		},
+	},
+	"19": {
+		decode: func(d []byte) (any, error) {
+			v, err := DecodeDPT19(d)
+			return v.Format("2006-01-02T15:04:05"), err
+		},
+	},
+	"20": {
+		encode: func(v any) ([]byte, error) {
+			n, err := wholeValue(v, 0, math.MaxUint8)
+			return EncodeDPT20(uint8(n)), err
+		},
+		decode: func(d []byte) (any, error) {
+			v, err := DecodeDPT20(d)
+			return float64(v), err
+		},
+	},
+	string(DPTHVACMode): {
+		encode: func(v any) ([]byte, error) {
+			s, err := stringValue(v)
+			if err != nil {
+				return nil, err
+			}
+			m, err := ParseHVACMode(s)
+			return EncodeDPT20(uint8(m)), err
+		},
+		decode: func(d []byte) (any, error) {
+			m, err := DecodeHVACMode(d)
+			return m.String(), err
+		},
+	},
+	string(DPTHVACControlMode): {
+		encode: func(v any) ([]byte, error) {
+			s, err := stringValue(v)
+			if err != nil {
+				return nil, err
+			}
+			m, err := ParseHVACControlMode(s)
+			return EncodeDPT20(uint8(m)), err
+		},
+		decode: func(d []byte) (any, error) {
+			m, err := DecodeHVACControlMode(d)
+			return m.String(), err
+		},
+	},
+	string(DPTColourRGB): {
+		encode: func(v any) ([]byte, error) {
+			c, err := channelValues(v, "r", "g", "b")
+			return EncodeDPT232(RGB{R: c[0], G: c[1], B: c[2]}), err
+		},
+		decode: func(d []byte) (any, error) { return DecodeDPT232(d) },
+	},
+	string(DPTColourXYY): {
+		encode: func(v any) ([]byte, error) {
+			m, ok := v.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%w: expected object with x, y, brightness", ErrEncodingFailed)
+			}
+			x, errX := rangeValue(m["x"], 0, 1)
+			y, errY := rangeValue(m["y"], 0, 1)
+			bri, errB := rangeValue(m["brightness"], 0, 100) //nolint:mnd // percent
+			for _, err := range []error{errX, errY, errB} {
+				if err != nil {
+					return nil, err
+				}
+			}
+			return EncodeDPT242(XYY{X: x, Y: y, Brightness: bri}), nil
+		},
+		decode: func(d []byte) (any, error) { return DecodeDPT242(d) },
+	},
+	string(DPTColourRGBW): {
+		encode: func(v any) ([]byte, error) {
+			c, err := channelValues(v, "r", "g", "b", "w")
+			return EncodeDPT251(RGBW{R: c[0], G: c[1], B: c[2], W: c[3]}), err
+		},
+		decode: func(d []byte) (any, error) { return DecodeDPT251(d) },
+	},
+}
+
+// SceneControl is a decoded DPT 18.001 value.
+type SceneControl struct {
+	Scene uint8 `json:"scene"`
+	Learn bool  `json:"learn"`
+}
+
+// lookupCodec returns the codec for a DPT: an exact match first, then the
+// codec for its main type.
+func lookupCodec(dpt string) (dptCodec, bool) {
+	if c, ok := dptCodecs[dpt]; ok {
+		return c, true
+	}
+	c, ok := dptCodecs[dptMainType(dpt)]
+	return c, ok
+}
+
+// EncodeValue encodes a state value for a DPT using the codec registry.
+//
+// Parameters:
+//   - dpt: Datapoint type (e.g. "1.001", "5.004", "232.600")
+//   - value: Value as decoded from JSON (bool, number, string or object)
+//
+// Returns:
+//   - []byte: KNX data
+//   - error: If the DPT has no encoder or the value does not fit it
+func EncodeValue(dpt string, value any) ([]byte, error) {
+	c, ok := lookupCodec(dpt)
+	if !ok || c.encode == nil {
+		return nil, fmt.Errorf("%w: no encoder for DPT %q", ErrEncodingFailed, dpt)
+	}
+	data, err := c.encode(value)
+	if err != nil {
+		return nil, fmt.Errorf("DPT %s: %w", dpt, err)
+	}
+	return data, nil
+}
+
+// DecodeValue decodes KNX data for a DPT using the codec registry.
+// Data for a DPT without a codec is returned as raw bytes.
+//
+// Parameters:
+//   - dpt: Datapoint type
+//   - data: KNX data
+//
+// Returns:
+//   - any: Decoded value
+//   - error: If the data is invalid for the DPT
+func DecodeValue(dpt string, data []byte) (any, error) {
+	c, ok := lookupCodec(dpt)
+	if !ok || c.decode == nil {
+		return data, nil
+	}
+	return c.decode(data)
+}
+
+// CanEncode reports whether the codec registry can encode values for a DPT.
+func CanEncode(dpt string) bool {
+	c, ok := lookupCodec(dpt)
+	return ok && c.encode != nil
+}
+
+// boolValue accepts a bool, or 0/1 as a number.
+func boolValue(v any) (bool, error) {
+	switch b := v.(type) {
+	case bool:
+		return b, nil
+	case float64:
+		if b == 0 || b == 1 {
+			return b == 1, nil
+		}
+	}
+	return false, fmt.Errorf("%w: expected true/false, got %v", ErrEncodingFailed, v)
+}
+
+// numberValue accepts a JSON number or a Go integer.
+func numberValue(v any) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	}
+	return 0, fmt.Errorf("%w: expected a number, got %v", ErrEncodingFailed, v)
+}
+
+// rangeValue accepts a number within [minVal, maxVal].
+func rangeValue(v any, minVal, maxVal float64) (float64, error) {
+	n, err := numberValue(v)
+	if err != nil {
+		return 0, err
+	}
+	if n < minVal || n > maxVal || math.IsNaN(n) {
+		return 0, fmt.Errorf("%w: %g out of range %g-%g", ErrEncodingFailed, n, minVal, maxVal)
+	}
+	return n, nil
+}
+
+// wholeValue accepts a whole number within [minVal, maxVal].
+func wholeValue(v any, minVal, maxVal float64) (int64, error) {
+	n, err := rangeValue(v, minVal, maxVal)
+	if err != nil {
+		return 0, err
+	}
+	if n != math.Trunc(n) {
+		return 0, fmt.Errorf("%w: %g is not a whole number", ErrEncodingFailed, n)
+	}
+	return int64(n), nil
+}
+
+// stringValue accepts a non-empty string.
+func stringValue(v any) (string, error) {
+	s, ok := v.(string)
+	if !ok || strings.TrimSpace(s) == "" {
+		return "", fmt.Errorf("%w: expected a string, got %v", ErrEncodingFailed, v)
+	}
+	return s, nil
+}
+
+// sceneControlValue accepts a scene number (recall) or an object
+// {"scene": n, "learn": bool}.
+func sceneControlValue(v any) (SceneControl, error) {
+	if m, ok := v.(map[string]any); ok {
+		scene, err := wholeValue(m["scene"], 0, dpt17MaxScene)
+		if err != nil {
+			return SceneControl{}, err
+		}
+		learn := false
+		if l, ok := m["learn"]; ok {
+			if learn, err = boolValue(l); err != nil {
+				return SceneControl{}, err
+			}
+		}
+		return SceneControl{Scene: uint8(scene), Learn: learn}, nil
+	}
+	scene, err := wholeValue(v, 0, dpt17MaxScene)
+	return SceneControl{Scene: uint8(scene)}, err
+}
+
+// channelValues reads 0-255 colour channels from an object by key.
+func channelValues(v any, keys ...string) ([]uint8, error) {
+	out := make([]uint8, len(keys))
+	m, ok := v.(map[string]any)
+	if !ok {
+		return out, fmt.Errorf("%w: expected object with %s", ErrEncodingFailed, strings.Join(keys, ", "))
+	}
+	for i, k := range keys {
+		n, err := wholeValue(m[k], 0, math.MaxUint8)
+		if err != nil {
+			return out, fmt.Errorf("%s: %w", k, err)
+		}
+		out[i] = uint8(n)
+	}
+	return out, nil
+}
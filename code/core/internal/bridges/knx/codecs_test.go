@@ -0,0 +1,103 @@
+package knx
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEncodeValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		dpt   string
+		value any
+		want  []byte
+	}{
+		{"bool", "1.001", true, []byte{0x01}},
+		{"bool from number", "1.003", 0.0, []byte{0x00}},
+		{"percent", "5.001", 100.0, []byte{0xFF}},
+		{"angle", "5.003", 360.0, []byte{0xFF}},
+		{"raw u8", "5.004", 42.0, []byte{0x2A}},
+		{"signed step", "6.010", -1.0, []byte{0xFF}},
+		{"u16", "7.600", 2700.0, []byte{0x0A, 0x8C}},
+		{"float16", "9.001", 21.0, []byte{0x0C, 0x1A}},
+		{"scene", "17.001", 1.0, []byte{0x01}},
+		{"scene recall", "18.001", 3.0, []byte{0x03}},
+		{"scene learn", "18.001", map[string]any{"scene": 3.0, "learn": true}, []byte{0x83}},
+		{"hvac mode", "20.102", "economy", []byte{0x03}},
+		{"rgb", "232.600", map[string]any{"r": 255.0, "g": 0.0, "b": 16.0}, []byte{0xFF, 0x00, 0x10}},
+		{"main type only", "1", false, []byte{0x00}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodeValue(tt.dpt, tt.value)
+			if err != nil {
+				t.Fatalf("EncodeValue(%s, %v) error: %v", tt.dpt, tt.value, err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("EncodeValue(%s, %v) = % X, want % X", tt.dpt, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeValueErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		dpt   string
+		value any
+	}{
+		{"bool from string", "1.001", "on"},
+		{"bool from 2", "1.001", 2.0},
+		{"percent too high", "5.001", 101.0},
+		{"raw u8 fraction", "5.004", 1.5},
+		{"scene too high", "17.001", 65.0},
+		{"scene control missing scene", "18.001", map[string]any{"learn": true}},
+		{"unknown hvac mode", "20.102", "turbo"},
+		{"rgb missing channel", "232.600", map[string]any{"r": 1.0, "g": 2.0}},
+		{"decode-only DPT", "10.001", "12:00:00"},
+		{"unknown DPT", "999.001", 1.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := EncodeValue(tt.dpt, tt.value)
+			if !errors.Is(err, ErrEncodingFailed) {
+				t.Errorf("EncodeValue(%s, %v) error = %v, want ErrEncodingFailed", tt.dpt, tt.value, err)
+			}
+		})
+	}
+}
+
+func TestDecodeValue(t *testing.T) {
+	tests := []struct {
+		name string
+		dpt  string
+		data []byte
+		want any
+	}{
+		{"bool", "1.001", []byte{0x01}, true},
+		{"raw u8", "5.004", []byte{0xC8}, 200.0},
+		{"scene", "17.001", []byte{0x05}, 5.0},
+		{"scene control", "18.001", []byte{0x85}, SceneControl{Scene: 5, Learn: true}},
+		{"hvac control mode", "20.105", []byte{0x01}, "heat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeValue(tt.dpt, tt.data)
+			if err != nil {
+				t.Fatalf("DecodeValue(%s) error: %v", tt.dpt, err)
+			}
+			if got != tt.want {
+				t.Errorf("DecodeValue(%s) = %v, want %v", tt.dpt, got, tt.want)
+			}
+		})
+	}
+
+	raw, err := DecodeValue("999.001", []byte{0xAB})
+	if err != nil {
+		t.Fatalf("DecodeValue(unknown) error: %v", err)
+	}
+	if b, ok := raw.([]byte); !ok || !bytes.Equal(b, []byte{0xAB}) {
+		t.Errorf("DecodeValue(unknown) = %v, want raw bytes", raw)
+	}
+}
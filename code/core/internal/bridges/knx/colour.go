@@ -0,0 +1,91 @@
+package knx
+
+import "math"
+
+// Colour conversion constants.
+const (
+	// hueSectors is the number of 60° sectors in the HSV hue circle.
+	hueSectors = 6
+
+	// d65X and d65Y are the chromaticity of the sRGB white point (D65),
+	// used for black where chromaticity is undefined.
+	d65X = 0.3127
+	d65Y = 0.3290
+)
+
+// HSVToRGB converts a hue/saturation/value colour to RGB.
+//
+// Parameters:
+//   - h: Hue in degrees (wrapped into 0-360)
+//   - s: Saturation (0-100%)
+//   - v: Value/brightness (0-100%)
+//
+// Returns:
+//   - RGB: Colour with components 0-255
+func HSVToRGB(h, s, v float64) RGB {
+	h = math.Mod(h, 360) //nolint:mnd // degrees in a circle
+	if h < 0 {
+		h += 360
+	}
+	s = clamp(s, 0, 100) / 100
+	v = clamp(v, 0, 100) / 100
+
+	c := v * s
+	sector := h / 60 //nolint:mnd // degrees per sector
+	x := c * (1 - math.Abs(math.Mod(sector, 2)-1))
+	m := v - c
+
+	var r, g, b float64
+	switch int(sector) % hueSectors {
+	case 0:
+		r, g, b = c, x, 0
+	case 1:
+		r, g, b = x, c, 0
+	case 2:
+		r, g, b = 0, c, x
+	case 3:
+		r, g, b = 0, x, c
+	case 4:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	scale := func(f float64) uint8 { return uint8(math.Round((f + m) * dpt5MaxValue)) }
+	return RGB{R: scale(r), G: scale(g), B: scale(b)}
+}
+
+// RGBToXYY converts an sRGB colour to CIE 1931 xyY chromaticity.
+//
+// The returned brightness is the colour's relative luminance (0-100%).
+// Black has no chromaticity and maps to the D65 white point at zero
+// brightness.
+//
+// Parameters:
+//   - c: sRGB colour
+//
+// Returns:
+//   - XYY: Chromaticity and brightness
+func RGBToXYY(c RGB) XYY {
+	r, g, b := srgbToLinear(c.R), srgbToLinear(c.G), srgbToLinear(c.B)
+
+	// sRGB (D65) to CIE XYZ.
+	bigX := 0.4124*r + 0.3576*g + 0.1805*b
+	bigY := 0.2126*r + 0.7152*g + 0.0722*b
+	bigZ := 0.0193*r + 0.1192*g + 0.9505*b
+
+	sum := bigX + bigY + bigZ
+	if sum == 0 {
+		return XYY{X: d65X, Y: d65Y}
+	}
+	return XYY{X: bigX / sum, Y: bigY / sum, Brightness: bigY * 100}
+}
+
+// srgbToLinear converts a gamma-encoded sRGB component to linear light (0-1).
+func srgbToLinear(v uint8) float64 {
+	f := float64(v) / dpt5MaxValue
+	if f <= 0.04045 { //nolint:mnd // sRGB transfer function breakpoint
+		return f / 12.92 //nolint:mnd // sRGB transfer function
+	}
+	return math.Pow((f+0.055)/1.055, 2.4) //nolint:mnd // sRGB transfer function
+}
@@ -0,0 +1,57 @@
+package knx
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHSVToRGB(t *testing.T) {
+	tests := []struct {
+		name    string
+		h, s, v float64
+		want    RGB
+	}{
+		{"red", 0, 100, 100, RGB{255, 0, 0}},
+		{"green", 120, 100, 100, RGB{0, 255, 0}},
+		{"blue", 240, 100, 100, RGB{0, 0, 255}},
+		{"yellow", 60, 100, 100, RGB{255, 255, 0}},
+		{"magenta", 300, 100, 100, RGB{255, 0, 255}},
+		{"white", 0, 0, 100, RGB{255, 255, 255}},
+		{"half blue", 240, 100, 50, RGB{0, 0, 128}},
+		{"black", 90, 100, 0, RGB{0, 0, 0}},
+		{"hue wraps", 360, 100, 100, RGB{255, 0, 0}},
+		{"negative hue", -120, 100, 100, RGB{0, 0, 255}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HSVToRGB(tt.h, tt.s, tt.v); got != tt.want {
+				t.Errorf("HSVToRGB(%v, %v, %v) = %+v, want %+v", tt.h, tt.s, tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRGBToXYY(t *testing.T) {
+	tests := []struct {
+		name string
+		rgb  RGB
+		want XYY
+	}{
+		{"white", RGB{255, 255, 255}, XYY{X: 0.3127, Y: 0.3290, Brightness: 100}},
+		{"red", RGB{255, 0, 0}, XYY{X: 0.64, Y: 0.33, Brightness: 21.26}},
+		{"green", RGB{0, 255, 0}, XYY{X: 0.30, Y: 0.60, Brightness: 71.52}},
+		{"blue", RGB{0, 0, 255}, XYY{X: 0.15, Y: 0.06, Brightness: 7.22}},
+		{"black", RGB{0, 0, 0}, XYY{X: 0.3127, Y: 0.3290, Brightness: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RGBToXYY(tt.rgb)
+			if math.Abs(got.X-tt.want.X) > 0.001 || math.Abs(got.Y-tt.want.Y) > 0.001 ||
+				math.Abs(got.Brightness-tt.want.Brightness) > 0.1 {
+				t.Errorf("RGBToXYY(%+v) = %+v, want ~%+v", tt.rgb, got, tt.want)
+			}
+		})
+	}
+}
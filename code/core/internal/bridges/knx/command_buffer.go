@@ -0,0 +1,172 @@
+package knx
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Command buffer defaults and limits.
+const (
+	// DefaultBufferSize is the default number of commands held while knxd
+	// is disconnected.
+	DefaultBufferSize = 100
+
+	// MaxBufferSize is the largest configurable command buffer.
+	MaxBufferSize = 10000
+
+	// DefaultBufferTTL is the default time a held command stays valid (seconds).
+	DefaultBufferTTL = 30
+
+	// MaxBufferTTL is the longest configurable command TTL (seconds).
+	MaxBufferTTL = 3600
+
+	// bufferCheckInterval is how often held commands are expired, replayed
+	// once knxd is back, or acknowledged as queued again. It stays below
+	// Core's command timeout so Core keeps waiting for held commands.
+	bufferCheckInterval = 2 * time.Second
+)
+
+// bufferedCommand is a command held until knxd reconnects.
+type bufferedCommand struct {
+	key     string
+	cmd     CommandMessage
+	expires time.Time
+}
+
+// commandBuffer holds commands received while knxd is disconnected, in
+// arrival order. Only the latest command per key (see commandKey) is kept:
+// a newer command for the same group address replaces the held one.
+//
+// Thread Safety: All methods are safe for concurrent use.
+type commandBuffer struct {
+	mu        sync.Mutex
+	items     []bufferedCommand
+	replaying bool // A command taken with next is being sent
+	size      int
+	ttl       time.Duration
+}
+
+// newCommandBuffer creates a buffer holding up to size commands for ttl each.
+func newCommandBuffer(size int, ttl time.Duration) *commandBuffer {
+	return &commandBuffer{size: size, ttl: ttl}
+}
+
+// add holds a command until now plus the TTL.
+//
+// Parameters:
+//   - key: What the command writes (commandKey)
+//   - cmd: Command to hold
+//   - now: Time the command was received
+//
+// Returns:
+//   - superseded: The held command with the same key it replaces, if any
+//   - ok: false if the buffer is full and the command was not held
+func (c *commandBuffer) add(key string, cmd CommandMessage, now time.Time) (superseded *CommandMessage, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if i := slices.IndexFunc(c.items, func(item bufferedCommand) bool { return item.key == key }); i >= 0 {
+		old := c.items[i].cmd
+		superseded = &old
+		c.items = slices.Delete(c.items, i, i+1)
+	} else if len(c.items) >= c.size {
+		return nil, false
+	}
+	c.items = append(c.items, bufferedCommand{key: key, cmd: cmd, expires: now.Add(c.ttl)})
+	return superseded, true
+}
+
+// expire removes and returns the commands whose TTL has passed.
+func (c *commandBuffer) expire(now time.Time) []CommandMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expired []CommandMessage
+	c.items = slices.DeleteFunc(c.items, func(item bufferedCommand) bool {
+		if now.Before(item.expires) {
+			return false
+		}
+		expired = append(expired, item.cmd)
+		return true
+	})
+	return expired
+}
+
+// next removes and returns the oldest held command. The buffer counts as
+// pending until done is called, so later commands wait for it.
+func (c *commandBuffer) next() (CommandMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.items) == 0 {
+		return CommandMessage{}, false
+	}
+	cmd := c.items[0].cmd
+	c.items = slices.Delete(c.items, 0, 1)
+	c.replaying = true
+	return cmd, true
+}
+
+// done marks the command taken with next as sent.
+func (c *commandBuffer) done() {
+	c.mu.Lock()
+	c.replaying = false
+	c.mu.Unlock()
+}
+
+// held returns the held commands without removing them.
+func (c *commandBuffer) held() []CommandMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cmds := make([]CommandMessage, 0, len(c.items))
+	for _, item := range c.items {
+		cmds = append(cmds, item.cmd)
+	}
+	return cmds
+}
+
+// pending reports whether any commands are held or being replayed.
+func (c *commandBuffer) pending() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items) > 0 || c.replaying
+}
+
+// commandFunctions are the functions each command writes, in the order the
+// executors in bridge.go try them (an explicit "function" parameter wins).
+var commandFunctions = map[string][]string{
+	"on":             {"switch"},
+	"off":            {"switch"},
+	"dim":            {"brightness", "switch"},
+	"set_position":   {"position"},
+	"stop":           {"stop", "move"},
+	"set_setpoint":   {"setpoint"},
+	"set_color_temp": {"color_temperature"},
+	"set_rgb":        {"rgb"},
+	"set_rgbw":       {"rgbw"},
+	"set_hsv":        {"color_xyy", "rgbw", "rgb"},
+	"set_hvac_mode":  {"hvac_mode"},
+	"shift_setpoint": {"setpoint_shift"},
+	"write_function": nil, // Function parameter only
+}
+
+// commandKey identifies the group address a command writes, so a newer
+// command for it replaces a held one. Commands whose target cannot be
+// resolved are keyed by their ID and never replaced.
+//
+// Parameters:
+//   - cmd: Command to key
+//   - deviceGAs: The device's function addresses
+//
+// Returns:
+//   - string: Device ID and target group address, or the command ID
+func commandKey(cmd CommandMessage, deviceGAs map[string]AddressConfig) string {
+	if fallbacks, known := commandFunctions[cmd.Command]; known {
+		if addr, _, ok := resolveFunction(cmd.Parameters, deviceGAs, fallbacks...); ok {
+			return cmd.DeviceID + "/" + addr.GA
+		}
+	}
+	return "id:" + cmd.ID
+}
@@ -0,0 +1,175 @@
+package knx
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestCommandBuffer(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	c := newCommandBuffer(2, 30*time.Second)
+
+	dim := func(id string, level int) CommandMessage {
+		return CommandMessage{ID: id, DeviceID: "light", Command: "dim", Parameters: map[string]any{"level": level}}
+	}
+	if _, ok := c.add("light/1/0/2", dim("cmd-1", 20), now); !ok {
+		t.Fatal("add() rejected the first command")
+	}
+	if _, ok := c.add("blind/2/0/1", CommandMessage{ID: "cmd-2", DeviceID: "blind", Command: "set_position"}, now.Add(10*time.Second)); !ok {
+		t.Fatal("add() rejected the second command")
+	}
+
+	// A newer dim replaces the held one, even with the buffer full
+	superseded, ok := c.add("light/1/0/2", dim("cmd-3", 60), now.Add(20*time.Second))
+	if !ok || superseded == nil || superseded.ID != "cmd-1" {
+		t.Fatalf("add() = %v, %v; want cmd-1 superseded", superseded, ok)
+	}
+	if _, ok := c.add("light/1/0/1", CommandMessage{ID: "cmd-4", DeviceID: "light", Command: "on"}, now); ok {
+		t.Error("add() held a third group address in a buffer of 2")
+	}
+
+	// Each command expires on its own TTL
+	expired := c.expire(now.Add(45 * time.Second))
+	if len(expired) != 1 || expired[0].ID != "cmd-2" {
+		t.Errorf("expire() = %+v, want cmd-2", expired)
+	}
+	cmd, ok := c.next()
+	if !ok || cmd.ID != "cmd-3" || !c.pending() {
+		t.Errorf("next() = %s, %v (pending %v); want cmd-3 still pending", cmd.ID, ok, c.pending())
+	}
+	c.done()
+	if c.pending() {
+		t.Error("pending() after the last command was replayed")
+	}
+}
+
+func TestCommandKey(t *testing.T) {
+	deviceGAs := map[string]AddressConfig{
+		"switch":            {GA: "1/0/1"},
+		"brightness":        {GA: "1/0/2"},
+		"color_temperature": {GA: "1/0/3"},
+		"rgb":               {GA: "1/0/4"},
+		"setpoint":          {GA: "3/0/1"},
+		"setpoint_shift":    {GA: "3/0/2"},
+		"position":          {GA: "2/0/1"},
+		"stop":              {GA: "2/0/2"},
+		"ch_a_switch":       {GA: "4/0/1"},
+		"ch_b_switch":       {GA: "4/0/2"},
+	}
+	cmd := func(id, command string, params map[string]any) CommandMessage {
+		return CommandMessage{ID: id, DeviceID: "dev", Command: command, Parameters: params}
+	}
+	tests := []struct {
+		name string
+		a, b CommandMessage
+		same bool
+	}{
+		{"on then off", cmd("1", "on", nil), cmd("2", "off", nil), true},
+		{"switch and dim", cmd("1", "on", nil), cmd("2", "dim", nil), false},
+		{"absolute and shifted setpoint", cmd("1", "set_setpoint", nil), cmd("2", "shift_setpoint", nil), false},
+		{"colour temperature and RGB", cmd("1", "set_color_temp", nil), cmd("2", "set_rgb", nil), false},
+		{"HSV falls back to RGB", cmd("1", "set_hsv", nil), cmd("2", "set_rgb", nil), true},
+		{"position and stop", cmd("1", "set_position", nil), cmd("2", "stop", nil), false},
+		{"write_function and matching command", cmd("1", "write_function", map[string]any{"function": "setpoint"}), cmd("2", "set_setpoint", nil), true},
+		{"channels", cmd("1", "on", map[string]any{"function": "ch_a_switch"}), cmd("2", "on", map[string]any{"function": "ch_b_switch"}), false},
+		{"unresolved target", cmd("1", "set_hvac_mode", nil), cmd("2", "set_hvac_mode", nil), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := commandKey(tt.a, deviceGAs) == commandKey(tt.b, deviceGAs); same != tt.same {
+				t.Errorf("same key = %v, want %v", same, tt.same)
+			}
+		})
+	}
+}
+
+func TestBridgeBuffersCommandsWhileDisconnected(t *testing.T) {
+	mqtt := NewMockMQTTClient()
+	knxd := NewMockConnector()
+	cfg := createTestConfig()
+	cfg.Buffer = BufferSettings{Size: 10, TTL: 30}
+	b := createTestBridge(t, BridgeOptions{Config: cfg, MQTTClient: mqtt, KNXDClient: knxd})
+
+	send := func(cmd CommandMessage) {
+		payload, _ := json.Marshal(cmd)
+		b.handleMQTTMessage("graylogic/command/knx/"+cmd.DeviceID, payload)
+	}
+	acks := func() map[string]AckStatus {
+		status := make(map[string]AckStatus)
+		for _, p := range mqtt.GetPublished() {
+			var ack AckMessage
+			if json.Unmarshal(p.Payload, &ack) == nil && ack.CommandID != "" {
+				status[ack.CommandID] = ack.Status
+			}
+		}
+		return status
+	}
+
+	knxd.SetConnected(false)
+	send(CommandMessage{ID: "cmd-1", DeviceID: "light-living-main", Command: "on"})
+	send(CommandMessage{ID: "cmd-2", DeviceID: "light-living-main", Command: "off"})
+	send(CommandMessage{ID: "cmd-3", DeviceID: "light-living-main", Command: "dim", Parameters: map[string]any{"level": 50.0}})
+	// A read of the switch is not held, and does not replace the held write
+	send(CommandMessage{ID: "cmd-4", DeviceID: "light-living-main", Command: "read_function", Parameters: map[string]any{"function": "switch"}})
+
+	if got := knxd.GetSentTelegrams(); len(got) != 0 {
+		t.Fatalf("sent %d telegrams while disconnected", len(got))
+	}
+	if got := knxd.GetReadRequests(); len(got) != 1 {
+		t.Errorf("read requests = %v, want the read passed straight to the connector", got)
+	}
+	status := acks()
+	if status["cmd-1"] != AckFailed || status["cmd-2"] != AckQueued || status["cmd-3"] != AckQueued || status["cmd-4"] == AckQueued {
+		t.Errorf("acks while disconnected = %v, want cmd-1 failed (superseded), cmd-2 and cmd-3 queued, cmd-4 not held", status)
+	}
+
+	// After reconnecting, only the latest switch value and the dim are sent
+	knxd.SetConnected(true)
+	b.checkCommandBuffer(time.Now())
+	sent := knxd.GetSentTelegrams()
+	if len(sent) != 2 || sent[0].GA.String() != "1/2/3" || sent[0].Data[0] != 0x00 || sent[1].GA.String() != "1/2/5" {
+		t.Errorf("replayed telegrams = %+v, want off to 1/2/3 then dim to 1/2/5", sent)
+	}
+	status = acks()
+	if status["cmd-2"] != AckAccepted || status["cmd-3"] != AckAccepted {
+		t.Errorf("acks after replay = %v, want cmd-2 and cmd-3 accepted", status)
+	}
+}
+
+func TestBridgeBufferedCommandExpires(t *testing.T) {
+	mqtt := NewMockMQTTClient()
+	knxd := NewMockConnector()
+	cfg := createTestConfig()
+	cfg.Buffer = BufferSettings{Size: 10, TTL: 30}
+	b := createTestBridge(t, BridgeOptions{Config: cfg, MQTTClient: mqtt, KNXDClient: knxd})
+
+	knxd.SetConnected(false)
+	payload, _ := json.Marshal(CommandMessage{ID: "cmd-1", DeviceID: "light-living-main", Command: "on"})
+	b.handleMQTTMessage("graylogic/command/knx/light-living-main", payload)
+
+	lastAck := func() AckMessage {
+		published := mqtt.GetPublished()
+		var ack AckMessage
+		if err := json.Unmarshal(published[len(published)-1].Payload, &ack); err != nil {
+			t.Fatalf("Failed to unmarshal ack: %v", err)
+		}
+		return ack
+	}
+
+	// Still disconnected within the TTL: queued again
+	b.checkCommandBuffer(time.Now().Add(10 * time.Second))
+	if ack := lastAck(); ack.CommandID != "cmd-1" || ack.Status != AckQueued {
+		t.Errorf("ack within TTL = %+v, want cmd-1 queued", ack)
+	}
+
+	// Past the TTL: timed out, and not sent after reconnecting
+	knxd.SetConnected(true)
+	b.checkCommandBuffer(time.Now().Add(time.Minute))
+	if ack := lastAck(); ack.CommandID != "cmd-1" || ack.Status != AckTimeout || ack.Error == nil || ack.Error.Code != ErrCodeTimeout {
+		t.Errorf("ack after TTL = %+v, want cmd-1 timeout", ack)
+	}
+	if sent := knxd.GetSentTelegrams(); len(sent) != 0 {
+		t.Errorf("expired command was sent: %+v", sent)
+	}
+}
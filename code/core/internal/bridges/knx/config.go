@@ -3,6 +3,7 @@ package knx
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"strings"
 	"time"
@@ -13,16 +14,43 @@ import (
 // DefaultKNXDConnection is the default knxd connection address.
 const DefaultKNXDConnection = "tcp://localhost:6720"
 
+// Connector types selectable with the connector setting.
+const (
+	// ConnectorKNXD connects through the knxd daemon (default).
+	ConnectorKNXD = "knxd"
+
+	// ConnectorTunnel connects directly to a KNX/IP interface with
+	// KNXnet/IP tunnelling.
+	ConnectorTunnel = "tunnel"
+
+	// ConnectorRouting joins the KNXnet/IP routing multicast group.
+	ConnectorRouting = "routing"
+)
+
 // Config is the root configuration for the KNX bridge.
 // Loaded from YAML with environment variable overrides.
 //
 // Devices are NOT configured here — they come from the device registry
 // (populated via ETS import or manual entry in the admin panel).
 type Config struct {
-	Bridge  BridgeConfig  `yaml:"bridge"`
-	KNXD    KNXDSettings  `yaml:"knxd"`
-	MQTT    MQTTSettings  `yaml:"mqtt"`
-	Logging LoggingConfig `yaml:"logging"`
+	Bridge BridgeConfig `yaml:"bridge"`
+
+	// Connector selects how the bridge reaches the bus: "knxd" or "tunnel".
+	// Default: "knxd" (also used when empty)
+	Connector string `yaml:"connector"`
+
+	KNXD       KNXDSettings       `yaml:"knxd"`
+	Tunnel     TunnelSettings     `yaml:"tunnel"`
+	Routing    RoutingSettings    `yaml:"routing"`
+	Secure     SecureSettings     `yaml:"secure"`
+	MQTT       MQTTSettings       `yaml:"mqtt"`
+	Logging    LoggingConfig      `yaml:"logging"`
+	TimeMaster TimeMasterSettings `yaml:"time_master"`
+	Monitor    MonitorSettings    `yaml:"monitor"`
+	Scheduler  SchedulerSettings  `yaml:"scheduler"`
+	Stats      StatsSettings      `yaml:"stats"`
+	Polling    PollingSettings    `yaml:"polling"`
+	Buffer     BufferSettings     `yaml:"buffer"`
 }
 
 // BridgeConfig contains bridge identity and operational settings.
@@ -61,6 +89,58 @@ type KNXDSettings struct {
 	ReconnectInterval int `yaml:"reconnect_interval"`
 }
 
+// TunnelSettings contains KNXnet/IP tunnelling settings, used when
+// connector is "tunnel". These override the defaults in TunnelConfig.
+type TunnelSettings struct {
+	// Gateway is the KNX/IP interface address, "host" or "host:port".
+	// Example: "192.168.1.50:3671"
+	Gateway string `yaml:"gateway"`
+
+	// LocalAddress is the local IPv4 address to advertise to the gateway.
+	// Leave empty for NAT mode (the gateway replies to the sender).
+	LocalAddress string `yaml:"local_address"`
+
+	// ConnectTimeout is the maximum time to wait for the gateway (seconds).
+	// Default: 10 seconds.
+	ConnectTimeout int `yaml:"connect_timeout"`
+
+	// HeartbeatInterval is how often the connection state is checked
+	// (seconds). Must be below the gateway's 120 second timeout.
+	// Default: 60 seconds.
+	HeartbeatInterval int `yaml:"heartbeat_interval"`
+
+	// ReconnectInterval is the delay between reconnection attempts (seconds).
+	// Default: 5 seconds.
+	ReconnectInterval int `yaml:"reconnect_interval"`
+}
+
+// RoutingSettings contains KNXnet/IP routing settings, used when
+// connector is "routing".
+type RoutingSettings struct {
+	// MulticastAddress is the routing multicast group.
+	// Default: "224.0.23.12"
+	MulticastAddress string `yaml:"multicast_address"`
+
+	// Interface is the network interface to join the group on.
+	// Leave empty for the system default.
+	Interface string `yaml:"interface"`
+
+	// IndividualAddress is the source address of sent frames ("A.L.D").
+	// Must be unique on the IP backbone.
+	// Default: "15.15.250"
+	IndividualAddress string `yaml:"individual_address"`
+}
+
+// SecureSettings contains KNX Data Secure settings. Keys come from an ETS
+// keyring imported through the commissioning API.
+type SecureSettings struct {
+	// IndividualAddress is the bridge's source address for secure frames
+	// ("A.L.D"), which they are authenticated with. Required with the knxd
+	// connector, which does not report the address knxd sends from; tunnel
+	// and routing connectors use their own when empty.
+	IndividualAddress string `yaml:"individual_address"`
+}
+
 // MQTTSettings contains MQTT broker connection settings.
 type MQTTSettings struct {
 	// Broker is the MQTT broker URL.
@@ -111,6 +191,105 @@ func (m MQTTSettings) MarshalJSON() ([]byte, error) {
 	return json.Marshal(safe)
 }
 
+// TimeMasterSettings configures the bridge as the bus time master.
+// When enabled, the bridge broadcasts the site's local date and time to the
+// configured group addresses and answers read requests on them.
+type TimeMasterSettings struct {
+	// Enabled turns on date/time broadcasting.
+	// Default: false (another device may already be the time master).
+	Enabled bool `yaml:"enabled"`
+
+	// TimeGA receives the time of day and weekday (DPT 10.001). Optional.
+	TimeGA string `yaml:"time_ga"`
+
+	// DateGA receives the date (DPT 11.001). Optional.
+	DateGA string `yaml:"date_ga"`
+
+	// DateTimeGA receives the combined date and time (DPT 19.001). Optional.
+	DateTimeGA string `yaml:"datetime_ga"`
+
+	// Interval is how often to broadcast (seconds). The time is also sent
+	// on startup, at local midnight, and when daylight saving time changes.
+	// Default: 60 seconds.
+	Interval int `yaml:"interval"`
+
+	// Timezone is the IANA timezone (e.g. "Europe/London") used when the
+	// site has no timezone configured.
+	// Default: UTC
+	Timezone string `yaml:"timezone"`
+}
+
+// MonitorSettings contains bus monitor settings.
+type MonitorSettings struct {
+	// Size is how many recent telegrams the bus monitor keeps.
+	// 0 disables the monitor.
+	// Default: 5000
+	Size int `yaml:"size"`
+}
+
+// SchedulerSettings configures the outgoing telegram queue.
+// The bridge's connection is one line, so the budget applies per line.
+type SchedulerSettings struct {
+	// Rate is the telegram budget in telegrams per second.
+	// 0 sends telegrams straight away without queueing.
+	// Default: 20
+	Rate int `yaml:"rate"`
+
+	// QueueSize is how many telegrams may wait to be sent. When full,
+	// lower-priority telegrams are dropped first.
+	// Default: 500
+	QueueSize int `yaml:"queue_size"`
+}
+
+// StatsSettings configures the rolling bus traffic statistics.
+type StatsSettings struct {
+	// Window is how far back the statistics reach (seconds).
+	// 0 disables bus statistics.
+	// Default: 300
+	Window int `yaml:"window"`
+
+	// SampleInterval is how often a sample is taken for the TSDB and the
+	// load alert (seconds). Default: 10
+	SampleInterval int `yaml:"sample_interval"`
+
+	// AlertLoad is the bus load that raises an alert, in percent of line
+	// capacity. 0 disables the alert.
+	// Default: 60
+	AlertLoad int `yaml:"alert_load"`
+}
+
+// PollingSettings configures periodic reads of group addresses whose
+// devices do not transmit on change. Only functions with the "read" flag
+// are polled.
+type PollingSettings struct {
+	// Defaults are poll intervals (seconds) by DPT for functions without
+	// their own poll_interval. Keys are a full DPT ("13.010") or a main
+	// number ("13"); the full DPT wins.
+	// Default: none (only functions with a poll_interval are polled)
+	Defaults map[string]int `yaml:"defaults"`
+
+	// MaxFailures is how many reads in a row may go unanswered before a
+	// group address is no longer polled and its devices are marked
+	// unhealthy. Polling resumes when a value for it is seen on the bus.
+	// Default: 3 (also used when 0)
+	MaxFailures int `yaml:"max_failures"`
+}
+
+// BufferSettings configures holding commands while knxd is disconnected.
+// Held commands are sent when the connection returns; only the latest
+// write per group address is kept.
+type BufferSettings struct {
+	// Size is how many commands may be held. When full, further commands
+	// fail. 0 fails commands straight away while disconnected.
+	// Default: 100
+	Size int `yaml:"size"`
+
+	// TTL is how long a held command stays valid (seconds). Commands not
+	// sent by then are acknowledged with status "timeout".
+	// Default: 30
+	TTL int `yaml:"ttl"`
+}
+
 // LoggingConfig contains logging settings.
 type LoggingConfig struct {
 	// Level is the minimum log level: debug, info, warn, error.
@@ -150,6 +329,10 @@ type AddressConfig struct {
 	//   - write: Bridge can send write commands to this GA
 	//   - transmit: Device transmits state changes on this GA
 	Flags []string `yaml:"flags"`
+
+	// PollInterval is how often to read this GA (seconds), for devices that
+	// do not transmit on change. 0 uses the polling default for the DPT.
+	PollInterval int `yaml:"poll_interval"`
 }
 
 // LoadConfig reads configuration from a YAML file.
@@ -200,12 +383,22 @@ func defaultConfig() *Config {
 			ID:             "knx-bridge-01",
 			HealthInterval: 30,
 		},
+		Connector: ConnectorKNXD,
 		KNXD: KNXDSettings{
 			Connection:        DefaultKNXDConnection,
 			ConnectTimeout:    10,
 			ReadTimeout:       30,
 			ReconnectInterval: 5,
 		},
+		Tunnel: TunnelSettings{
+			ConnectTimeout:    10,
+			HeartbeatInterval: 60,
+			ReconnectInterval: 5,
+		},
+		Routing: RoutingSettings{
+			MulticastAddress:  DefaultRoutingMulticast,
+			IndividualAddress: DefaultRoutingAddress,
+		},
 		MQTT: MQTTSettings{
 			Broker:    "tcp://localhost:1883",
 			QoS:       1,
@@ -215,6 +408,28 @@ func defaultConfig() *Config {
 			Level:  "info",
 			Format: "json",
 		},
+		TimeMaster: TimeMasterSettings{
+			Interval: 60,
+		},
+		Monitor: MonitorSettings{
+			Size: DefaultMonitorSize,
+		},
+		Scheduler: SchedulerSettings{
+			Rate:      DefaultSchedulerRate,
+			QueueSize: DefaultSchedulerQueueSize,
+		},
+		Stats: StatsSettings{
+			Window:         DefaultStatsWindow,
+			SampleInterval: DefaultStatsSampleInterval,
+			AlertLoad:      DefaultStatsAlertLoad,
+		},
+		Polling: PollingSettings{
+			MaxFailures: DefaultPollMaxFailures,
+		},
+		Buffer: BufferSettings{
+			Size: DefaultBufferSize,
+			TTL:  DefaultBufferTTL,
+		},
 	}
 }
 
@@ -226,11 +441,26 @@ func applyEnvOverrides(cfg *Config) {
 		cfg.Bridge.ID = v
 	}
 
+	// Connector
+	if v := os.Getenv("KNX_BRIDGE_CONNECTOR"); v != "" {
+		cfg.Connector = v
+	}
+
 	// KNXD
 	if v := os.Getenv("KNX_BRIDGE_KNXD_CONNECTION"); v != "" {
 		cfg.KNXD.Connection = v
 	}
 
+	// Tunnel
+	if v := os.Getenv("KNX_BRIDGE_TUNNEL_GATEWAY"); v != "" {
+		cfg.Tunnel.Gateway = v
+	}
+
+	// Routing
+	if v := os.Getenv("KNX_BRIDGE_ROUTING_INTERFACE"); v != "" {
+		cfg.Routing.Interface = v
+	}
+
 	// MQTT
 	if v := os.Getenv("KNX_BRIDGE_MQTT_BROKER"); v != "" {
 		cfg.MQTT.Broker = v
@@ -251,9 +481,23 @@ func (c *Config) Validate() error {
 	var errs []string
 
 	errs = append(errs, c.validateBridge()...)
-	errs = append(errs, c.validateKNXD()...)
+	errs = append(errs, c.validateConnector()...)
+	errs = append(errs, c.validateSecure()...)
 	errs = append(errs, c.validateMQTT()...)
 	errs = append(errs, c.validateLogging()...)
+	errs = append(errs, c.validateTimeMaster()...)
+	errs = append(errs, c.validateStats()...)
+	errs = append(errs, c.validatePolling()...)
+	errs = append(errs, c.validateBuffer()...)
+	if c.Monitor.Size < 0 || c.Monitor.Size > MaxMonitorSize {
+		errs = append(errs, fmt.Sprintf("monitor.size must be 0-%d", MaxMonitorSize))
+	}
+	if c.Scheduler.Rate < 0 || c.Scheduler.Rate > MaxSchedulerRate {
+		errs = append(errs, fmt.Sprintf("scheduler.rate must be 0-%d", MaxSchedulerRate))
+	}
+	if c.Scheduler.Rate > 0 && (c.Scheduler.QueueSize < 1 || c.Scheduler.QueueSize > MaxSchedulerQueueSize) {
+		errs = append(errs, fmt.Sprintf("scheduler.queue_size must be 1-%d", MaxSchedulerQueueSize))
+	}
 
 	if len(errs) > 0 {
 		return fmt.Errorf("configuration errors: %s", strings.Join(errs, "; "))
@@ -274,6 +518,21 @@ func (c *Config) validateBridge() []string {
 	return errs
 }
 
+// validateConnector validates the connector type and the settings of the
+// selected connector.
+func (c *Config) validateConnector() []string {
+	switch c.Connector {
+	case "", ConnectorKNXD:
+		return c.validateKNXD()
+	case ConnectorTunnel:
+		return c.validateTunnel()
+	case ConnectorRouting:
+		return c.validateRouting()
+	default:
+		return []string{fmt.Sprintf("connector %q is invalid (use knxd, tunnel, or routing)", c.Connector)}
+	}
+}
+
 // validateKNXD validates knxd connection settings.
 func (c *Config) validateKNXD() []string {
 	var errs []string
@@ -289,6 +548,56 @@ func (c *Config) validateKNXD() []string {
 	return errs
 }
 
+// validateTunnel validates KNXnet/IP tunnelling settings.
+func (c *Config) validateTunnel() []string {
+	var errs []string
+	if c.Tunnel.Gateway == "" {
+		errs = append(errs, "tunnel.gateway is required")
+	} else if _, err := resolveGateway(c.Tunnel.Gateway); err != nil {
+		errs = append(errs, fmt.Sprintf("tunnel.gateway %q is invalid", c.Tunnel.Gateway))
+	}
+	if c.Tunnel.LocalAddress != "" {
+		if ip := net.ParseIP(c.Tunnel.LocalAddress); ip == nil || ip.To4() == nil {
+			errs = append(errs, fmt.Sprintf("tunnel.local_address %q is not an IPv4 address", c.Tunnel.LocalAddress))
+		}
+	}
+	if c.Tunnel.ConnectTimeout < 1 {
+		errs = append(errs, "tunnel.connect_timeout must be at least 1 second")
+	}
+	if c.Tunnel.HeartbeatInterval < 1 || c.Tunnel.HeartbeatInterval >= 120 {
+		errs = append(errs, "tunnel.heartbeat_interval must be 1-119 seconds")
+	}
+	return errs
+}
+
+// validateRouting validates KNXnet/IP routing settings.
+func (c *Config) validateRouting() []string {
+	// Empty settings fall back to the RoutingConfig defaults
+	var errs []string
+	if c.Routing.MulticastAddress != "" {
+		if _, err := resolveRoutingGroup(c.Routing.MulticastAddress); err != nil {
+			errs = append(errs, fmt.Sprintf("routing.multicast_address %q is not an IPv4 multicast address", c.Routing.MulticastAddress))
+		}
+	}
+	if c.Routing.IndividualAddress != "" {
+		if _, err := parseIndividualAddress(c.Routing.IndividualAddress); err != nil {
+			errs = append(errs, fmt.Sprintf("routing.individual_address: %v", err))
+		}
+	}
+	return errs
+}
+
+// validateSecure validates KNX Data Secure settings.
+func (c *Config) validateSecure() []string {
+	if c.Secure.IndividualAddress == "" {
+		return nil
+	}
+	if _, err := parseIndividualAddress(c.Secure.IndividualAddress); err != nil {
+		return []string{fmt.Sprintf("secure.individual_address: %v", err)}
+	}
+	return nil
+}
+
 // validateMQTT validates MQTT broker settings.
 func (c *Config) validateMQTT() []string {
 	var errs []string
@@ -318,6 +627,83 @@ func (c *Config) validateLogging() []string {
 	return errs
 }
 
+// validateTimeMaster validates time master settings.
+// Settings are only checked when the time master is enabled.
+func (c *Config) validateTimeMaster() []string {
+	tm := c.TimeMaster
+	if !tm.Enabled {
+		return nil
+	}
+
+	var errs []string
+	if tm.TimeGA == "" && tm.DateGA == "" && tm.DateTimeGA == "" {
+		errs = append(errs, "time_master requires at least one of time_ga, date_ga, datetime_ga")
+	}
+	for _, f := range []struct{ key, ga string }{
+		{"time_ga", tm.TimeGA},
+		{"date_ga", tm.DateGA},
+		{"datetime_ga", tm.DateTimeGA},
+	} {
+		if f.ga == "" {
+			continue
+		}
+		if _, err := ParseGroupAddress(f.ga); err != nil {
+			errs = append(errs, fmt.Sprintf("time_master.%s %q is invalid", f.key, f.ga))
+		}
+	}
+	if tm.Interval < 1 {
+		errs = append(errs, "time_master.interval must be at least 1 second")
+	}
+	if tm.Timezone != "" {
+		if _, err := time.LoadLocation(tm.Timezone); err != nil {
+			errs = append(errs, fmt.Sprintf("time_master.timezone %q is invalid", tm.Timezone))
+		}
+	}
+	return errs
+}
+
+// validateStats checks the bus statistics settings.
+func (c *Config) validateStats() []string {
+	var errs []string
+	st := c.Stats
+	if st.Window < 0 || st.Window > MaxStatsWindow {
+		errs = append(errs, fmt.Sprintf("stats.window must be 0-%d", MaxStatsWindow))
+	}
+	if st.Window > 0 && (st.SampleInterval < 1 || st.SampleInterval > st.Window) {
+		errs = append(errs, "stats.sample_interval must be between 1 and stats.window")
+	}
+	if st.AlertLoad < 0 || st.AlertLoad > 100 { //nolint:mnd // percent
+		errs = append(errs, "stats.alert_load must be 0-100")
+	}
+	return errs
+}
+
+// validatePolling checks the polling settings.
+func (c *Config) validatePolling() []string {
+	var errs []string
+	for dpt, seconds := range c.Polling.Defaults {
+		if seconds < MinPollInterval {
+			errs = append(errs, fmt.Sprintf("polling.defaults[%q] must be at least %d seconds", dpt, MinPollInterval))
+		}
+	}
+	if c.Polling.MaxFailures < 0 {
+		errs = append(errs, "polling.max_failures must not be negative")
+	}
+	return errs
+}
+
+// validateBuffer checks the command buffer settings.
+func (c *Config) validateBuffer() []string {
+	var errs []string
+	if c.Buffer.Size < 0 || c.Buffer.Size > MaxBufferSize {
+		errs = append(errs, fmt.Sprintf("buffer.size must be 0-%d", MaxBufferSize))
+	}
+	if c.Buffer.Size > 0 && (c.Buffer.TTL < 1 || c.Buffer.TTL > MaxBufferTTL) {
+		errs = append(errs, fmt.Sprintf("buffer.ttl must be 1-%d", MaxBufferTTL))
+	}
+	return errs
+}
+
 // ToKNXDConfig converts settings to a KNXDConfig for the client.
 func (c *Config) ToKNXDConfig() KNXDConfig {
 	return KNXDConfig{
@@ -328,11 +714,36 @@ func (c *Config) ToKNXDConfig() KNXDConfig {
 	}
 }
 
+// ToTunnelConfig converts settings to a TunnelConfig for the client.
+func (c *Config) ToTunnelConfig() TunnelConfig {
+	return TunnelConfig{
+		Gateway:           c.Tunnel.Gateway,
+		LocalAddress:      c.Tunnel.LocalAddress,
+		ConnectTimeout:    time.Duration(c.Tunnel.ConnectTimeout) * time.Second,
+		HeartbeatInterval: time.Duration(c.Tunnel.HeartbeatInterval) * time.Second,
+		ReconnectInterval: time.Duration(c.Tunnel.ReconnectInterval) * time.Second,
+	}
+}
+
+// ToRoutingConfig converts settings to a RoutingConfig for the client.
+func (c *Config) ToRoutingConfig() RoutingConfig {
+	return RoutingConfig{
+		MulticastAddress:  c.Routing.MulticastAddress,
+		Interface:         c.Routing.Interface,
+		IndividualAddress: c.Routing.IndividualAddress,
+	}
+}
+
 // GetHealthInterval returns the health reporting interval as a Duration.
 func (c *Config) GetHealthInterval() time.Duration {
 	return time.Duration(c.Bridge.HealthInterval) * time.Second
 }
 
+// GetTimeMasterInterval returns the time broadcast interval as a Duration.
+func (c *Config) GetTimeMasterInterval() time.Duration {
+	return time.Duration(c.TimeMaster.Interval) * time.Second
+}
+
 // GetMQTTClientID returns the MQTT client ID, defaulting to bridge ID if not set.
 func (c *Config) GetMQTTClientID() string {
 	if c.MQTT.ClientID != "" {
@@ -364,6 +775,7 @@ func BuildDeviceIndex(devices []DeviceConfig) (gaToDevice map[string][]GAMapping
 						Function: funcName,
 						DPT:      addr.DPT,
 						Type:     dev.Type,
+						Command:  addr.HasFlag("write"),
 					})
 					break
 				}
@@ -380,6 +792,7 @@ type GAMapping struct {
 	Function string // Function name (e.g., "switch", "brightness_status")
 	DPT      string // Datapoint type
 	Type     string // Device type
+	Command  bool   // GA has the write flag: a command address, not status feedback
 }
 
 // HasFlag checks if an AddressConfig has a specific flag.
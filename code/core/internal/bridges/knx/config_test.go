@@ -205,6 +205,137 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantError: "logging.level",
 		},
+		{
+			name: "time master without addresses",
+			config: Config{
+				Bridge:     BridgeConfig{ID: "test", HealthInterval: 30},
+				KNXD:       KNXDSettings{Connection: "tcp://localhost:6720", ConnectTimeout: 10, ReadTimeout: 30},
+				MQTT:       MQTTSettings{Broker: "tcp://localhost:1883", QoS: 1},
+				Logging:    LoggingConfig{Level: "info", Format: "json"},
+				TimeMaster: TimeMasterSettings{Enabled: true, Interval: 60},
+			},
+			wantError: "time_master requires at least one",
+		},
+		{
+			name: "time master invalid GA",
+			config: Config{
+				Bridge:     BridgeConfig{ID: "test", HealthInterval: 30},
+				KNXD:       KNXDSettings{Connection: "tcp://localhost:6720", ConnectTimeout: 10, ReadTimeout: 30},
+				MQTT:       MQTTSettings{Broker: "tcp://localhost:1883", QoS: 1},
+				Logging:    LoggingConfig{Level: "info", Format: "json"},
+				TimeMaster: TimeMasterSettings{Enabled: true, TimeGA: "0/0/x", Interval: 60},
+			},
+			wantError: "time_master.time_ga",
+		},
+		{
+			name: "time master invalid timezone",
+			config: Config{
+				Bridge:     BridgeConfig{ID: "test", HealthInterval: 30},
+				KNXD:       KNXDSettings{Connection: "tcp://localhost:6720", ConnectTimeout: 10, ReadTimeout: 30},
+				MQTT:       MQTTSettings{Broker: "tcp://localhost:1883", QoS: 1},
+				Logging:    LoggingConfig{Level: "info", Format: "json"},
+				TimeMaster: TimeMasterSettings{Enabled: true, DateGA: "0/0/2", Interval: 60, Timezone: "Mars/Olympus"},
+			},
+			wantError: "time_master.timezone",
+		},
+		{
+			name: "unknown connector",
+			config: Config{
+				Bridge:    BridgeConfig{ID: "test", HealthInterval: 30},
+				Connector: "serial",
+				MQTT:      MQTTSettings{Broker: "tcp://localhost:1883", QoS: 1},
+				Logging:   LoggingConfig{Level: "info", Format: "json"},
+			},
+			wantError: "connector \"serial\" is invalid",
+		},
+		{
+			name: "tunnel without gateway",
+			config: Config{
+				Bridge:    BridgeConfig{ID: "test", HealthInterval: 30},
+				Connector: ConnectorTunnel,
+				Tunnel:    TunnelSettings{ConnectTimeout: 10, HeartbeatInterval: 60},
+				MQTT:      MQTTSettings{Broker: "tcp://localhost:1883", QoS: 1},
+				Logging:   LoggingConfig{Level: "info", Format: "json"},
+			},
+			wantError: "tunnel.gateway is required",
+		},
+		{
+			name: "tunnel heartbeat beyond gateway timeout",
+			config: Config{
+				Bridge:    BridgeConfig{ID: "test", HealthInterval: 30},
+				Connector: ConnectorTunnel,
+				Tunnel:    TunnelSettings{Gateway: "192.168.1.50", ConnectTimeout: 10, HeartbeatInterval: 120},
+				MQTT:      MQTTSettings{Broker: "tcp://localhost:1883", QoS: 1},
+				Logging:   LoggingConfig{Level: "info", Format: "json"},
+			},
+			wantError: "tunnel.heartbeat_interval",
+		},
+		{
+			name: "routing unicast group",
+			config: Config{
+				Bridge:    BridgeConfig{ID: "test", HealthInterval: 30},
+				Connector: ConnectorRouting,
+				Routing:   RoutingSettings{MulticastAddress: "192.168.1.50"},
+				MQTT:      MQTTSettings{Broker: "tcp://localhost:1883", QoS: 1},
+				Logging:   LoggingConfig{Level: "info", Format: "json"},
+			},
+			wantError: "routing.multicast_address",
+		},
+		{
+			name: "routing invalid individual address",
+			config: Config{
+				Bridge:    BridgeConfig{ID: "test", HealthInterval: 30},
+				Connector: ConnectorRouting,
+				Routing:   RoutingSettings{IndividualAddress: "1.16.1"},
+				MQTT:      MQTTSettings{Broker: "tcp://localhost:1883", QoS: 1},
+				Logging:   LoggingConfig{Level: "info", Format: "json"},
+			},
+			wantError: "routing.individual_address",
+		},
+		{
+			name: "secure invalid individual address",
+			config: Config{
+				Bridge:  BridgeConfig{ID: "test", HealthInterval: 30},
+				KNXD:    KNXDSettings{Connection: "tcp://localhost:6720", ConnectTimeout: 10, ReadTimeout: 30},
+				Secure:  SecureSettings{IndividualAddress: "1.1.x"},
+				MQTT:    MQTTSettings{Broker: "tcp://localhost:1883", QoS: 1},
+				Logging: LoggingConfig{Level: "info", Format: "json"},
+			},
+			wantError: "secure.individual_address",
+		},
+		{
+			name: "scheduler rate too high",
+			config: Config{
+				Bridge:    BridgeConfig{ID: "test", HealthInterval: 30},
+				KNXD:      KNXDSettings{Connection: "tcp://localhost:6720", ConnectTimeout: 10, ReadTimeout: 30},
+				MQTT:      MQTTSettings{Broker: "tcp://localhost:1883", QoS: 1},
+				Logging:   LoggingConfig{Level: "info", Format: "json"},
+				Scheduler: SchedulerSettings{Rate: 100, QueueSize: 500},
+			},
+			wantError: "scheduler.rate",
+		},
+		{
+			name: "poll interval too short",
+			config: Config{
+				Bridge:  BridgeConfig{ID: "test", HealthInterval: 30},
+				KNXD:    KNXDSettings{Connection: "tcp://localhost:6720", ConnectTimeout: 10, ReadTimeout: 30},
+				MQTT:    MQTTSettings{Broker: "tcp://localhost:1883", QoS: 1},
+				Logging: LoggingConfig{Level: "info", Format: "json"},
+				Polling: PollingSettings{Defaults: map[string]int{"13": 5}},
+			},
+			wantError: "polling.defaults",
+		},
+		{
+			name: "buffer without ttl",
+			config: Config{
+				Bridge:  BridgeConfig{ID: "test", HealthInterval: 30},
+				KNXD:    KNXDSettings{Connection: "tcp://localhost:6720", ConnectTimeout: 10, ReadTimeout: 30},
+				MQTT:    MQTTSettings{Broker: "tcp://localhost:1883", QoS: 1},
+				Logging: LoggingConfig{Level: "info", Format: "json"},
+				Buffer:  BufferSettings{Size: 100},
+			},
+			wantError: "buffer.ttl",
+		},
 	}
 
 	for _, tt := range tests {
@@ -233,6 +364,40 @@ func TestConfigValidationSuccess(t *testing.T) {
 	}
 }
 
+func TestLoadConfigTunnel(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+bridge:
+  id: "tunnel-bridge"
+connector: "tunnel"
+tunnel:
+  gateway: "192.168.1.50:3671"
+  heartbeat_interval: 30
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Connector != ConnectorTunnel {
+		t.Errorf("Connector = %q, want tunnel", cfg.Connector)
+	}
+
+	tunnelCfg := cfg.ToTunnelConfig()
+	if tunnelCfg.Gateway != "192.168.1.50:3671" {
+		t.Errorf("Gateway = %q, want 192.168.1.50:3671", tunnelCfg.Gateway)
+	}
+	if tunnelCfg.HeartbeatInterval.Seconds() != 30 {
+		t.Errorf("HeartbeatInterval = %v, want 30s", tunnelCfg.HeartbeatInterval)
+	}
+	if tunnelCfg.ConnectTimeout.Seconds() != 10 {
+		t.Errorf("ConnectTimeout = %v, want default 10s", tunnelCfg.ConnectTimeout)
+	}
+}
+
 func TestToKNXDConfig(t *testing.T) {
 	cfg := Config{
 		KNXD: KNXDSettings{
@@ -0,0 +1,89 @@
+package knx
+
+import (
+	"sort"
+	"strings"
+)
+
+// DPTConflict is a group address that devices map with incompatible DPTs.
+//
+// Each mapping is still decoded with its own DPT, but at most one of them
+// can match what the bus actually sends, so the others will report wrong
+// values until commissioning fixes the assignment.
+type DPTConflict struct {
+	GA       string              `json:"ga"`
+	DPTs     []string            `json:"dpts"`
+	Mappings []DPTConflictDevice `json:"mappings"`
+}
+
+// DPTConflictDevice is one device function involved in a DPT conflict.
+type DPTConflictDevice struct {
+	DeviceID string `json:"device_id"`
+	Function string `json:"function"`
+	DPT      string `json:"dpt"`
+}
+
+// dptMainType returns the main number of a DPT ("5.001" → "5").
+// Subtypes of one main type share a wire format.
+func dptMainType(dpt string) string {
+	main, _, _ := strings.Cut(dpt, ".")
+	return main
+}
+
+// findDPTConflicts returns the group addresses whose mappings use DPTs of
+// different main types, in group address order. Mappings without a DPT
+// are ignored.
+func findDPTConflicts(gaToDevice map[string][]GAMapping) []DPTConflict {
+	var conflicts []DPTConflict
+	for ga, mappings := range gaToDevice {
+		mainTypes := make(map[string]bool)
+		dpts := make(map[string]bool)
+		for _, m := range mappings {
+			if m.DPT == "" {
+				continue
+			}
+			mainTypes[dptMainType(m.DPT)] = true
+			dpts[m.DPT] = true
+		}
+		if len(mainTypes) < 2 { //nolint:mnd // a conflict needs two formats
+			continue
+		}
+
+		c := DPTConflict{GA: ga}
+		for dpt := range dpts {
+			c.DPTs = append(c.DPTs, dpt)
+		}
+		sort.Strings(c.DPTs)
+		for _, m := range mappings {
+			if m.DPT == "" {
+				continue
+			}
+			c.Mappings = append(c.Mappings, DPTConflictDevice{
+				DeviceID: m.DeviceID,
+				Function: m.Function,
+				DPT:      m.DPT,
+			})
+		}
+		sort.Slice(c.Mappings, func(i, j int) bool {
+			if c.Mappings[i].DeviceID != c.Mappings[j].DeviceID {
+				return c.Mappings[i].DeviceID < c.Mappings[j].DeviceID
+			}
+			return c.Mappings[i].Function < c.Mappings[j].Function
+		})
+		conflicts = append(conflicts, c)
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool { return gaLess(conflicts[i].GA, conflicts[j].GA) })
+	return conflicts
+}
+
+// gaLess orders group addresses numerically ("3/1/2" before "3/1/10"),
+// falling back to string order for unparseable addresses.
+func gaLess(a, b string) bool {
+	gaA, errA := ParseGroupAddress(a)
+	gaB, errB := ParseGroupAddress(b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return gaA.ToUint16() < gaB.ToUint16()
+}
@@ -0,0 +1,194 @@
+package knx
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRegistry is a DeviceRegistry serving a settable device list and
+// recording health updates.
+type fakeRegistry struct {
+	mu      sync.Mutex
+	devices []RegistryDevice
+	health  map[string]string
+}
+
+func (r *fakeRegistry) SetDeviceState(_ context.Context, _ string, _ map[string]any) error {
+	return nil
+}
+
+func (r *fakeRegistry) SetDeviceHealth(_ context.Context, deviceID string, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.health == nil {
+		r.health = make(map[string]string)
+	}
+	r.health[deviceID] = status
+	return nil
+}
+
+func (r *fakeRegistry) getHealth(deviceID string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.health[deviceID]
+}
+
+func (r *fakeRegistry) CreateDeviceIfNotExists(_ context.Context, _ DeviceSeed) error {
+	return nil
+}
+
+func (r *fakeRegistry) GetKNXDevices(_ context.Context) ([]RegistryDevice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.devices, nil
+}
+
+func (r *fakeRegistry) setDevices(devices []RegistryDevice) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.devices = devices
+}
+
+// sharedGADevices maps 9/0/1 to a switch (1.001) and a logic module (5.001),
+// and 9/0/2 to two compatible DPT 9 subtypes.
+func sharedGADevices() []RegistryDevice {
+	return []RegistryDevice{
+		{
+			ID:   "switch-hall",
+			Type: "light_switch",
+			Functions: map[string]FunctionMapping{
+				"switch_status": {GA: "9/0/1", DPT: "1.001", Flags: []string{"transmit"}},
+				"temperature":   {GA: "9/0/2", DPT: "9.001", Flags: []string{"transmit"}},
+			},
+		},
+		{
+			ID:   "logic-hall",
+			Type: "sensor",
+			Functions: map[string]FunctionMapping{
+				"brightness_status": {GA: "9/0/1", DPT: "5.001", Flags: []string{"transmit"}},
+				"lux":               {GA: "9/0/2", DPT: "9.004", Flags: []string{"transmit"}},
+			},
+		},
+	}
+}
+
+func TestFindDPTConflicts(t *testing.T) {
+	gaToDevice := map[string][]GAMapping{
+		"3/1/10": {
+			{DeviceID: "b", Function: "level", DPT: "5.001"},
+			{DeviceID: "a", Function: "on", DPT: "1.001"},
+		},
+		"3/1/2": {
+			{DeviceID: "c", Function: "on", DPT: "1.001"},
+			{DeviceID: "d", Function: "value", DPT: "9.001"},
+			{DeviceID: "e", Function: "unknown"},
+		},
+		"2/0/0": {
+			{DeviceID: "f", Function: "temperature", DPT: "9.001"},
+			{DeviceID: "g", Function: "lux", DPT: "9.004"},
+		},
+	}
+
+	conflicts := findDPTConflicts(gaToDevice)
+	if len(conflicts) != 2 {
+		t.Fatalf("findDPTConflicts() returned %d conflicts, want 2: %+v", len(conflicts), conflicts)
+	}
+	if conflicts[0].GA != "3/1/2" || conflicts[1].GA != "3/1/10" {
+		t.Errorf("conflict order = %s, %s; want 3/1/2, 3/1/10", conflicts[0].GA, conflicts[1].GA)
+	}
+	if len(conflicts[0].Mappings) != 2 {
+		t.Errorf("3/1/2 mappings = %+v, want 2 (mapping without DPT skipped)", conflicts[0].Mappings)
+	}
+	c := conflicts[1]
+	if c.DPTs[0] != "1.001" || c.DPTs[1] != "5.001" {
+		t.Errorf("3/1/10 DPTs = %v, want [1.001 5.001]", c.DPTs)
+	}
+	if c.Mappings[0].DeviceID != "a" || c.Mappings[1].DeviceID != "b" {
+		t.Errorf("3/1/10 mappings not sorted by device: %+v", c.Mappings)
+	}
+}
+
+func TestBridgeDPTConflictsOnLoad(t *testing.T) {
+	registry := &fakeRegistry{devices: sharedGADevices()}
+	b, err := NewBridge(BridgeOptions{
+		Config:     createTestConfig(),
+		MQTTClient: NewMockMQTTClient(),
+		KNXDClient: NewMockConnector(),
+		Registry:   registry,
+	})
+	if err != nil {
+		t.Fatalf("NewBridge() error: %v", err)
+	}
+
+	b.loadDevicesFromRegistry(context.Background())
+	conflicts := b.DPTConflicts()
+	if len(conflicts) != 1 || conflicts[0].GA != "9/0/1" {
+		t.Fatalf("DPTConflicts() = %+v, want one conflict on 9/0/1", conflicts)
+	}
+
+	// Fixing the logic module's GA clears the conflict, and the old
+	// mapping does not linger after reload.
+	fixed := sharedGADevices()
+	fixed[1].Functions["brightness_status"] = FunctionMapping{GA: "9/0/3", DPT: "5.001", Flags: []string{"transmit"}}
+	registry.setDevices(fixed)
+
+	b.loadDevicesFromRegistry(context.Background())
+	if conflicts := b.DPTConflicts(); len(conflicts) != 0 {
+		t.Errorf("DPTConflicts() after fix = %+v, want none", conflicts)
+	}
+	b.mappingMu.RLock()
+	n := len(b.gaToDevice["9/0/1"])
+	b.mappingMu.RUnlock()
+	if n != 1 {
+		t.Errorf("9/0/1 has %d mappings after reload, want 1", n)
+	}
+}
+
+func TestBridgeKNXTelegramPerMappingDPT(t *testing.T) {
+	mqtt := NewMockMQTTClient()
+	knxd := NewMockConnector()
+	b, err := NewBridge(BridgeOptions{
+		Config:     createTestConfig(),
+		MQTTClient: mqtt,
+		KNXDClient: knxd,
+		Registry:   &fakeRegistry{devices: sharedGADevices()},
+	})
+	if err != nil {
+		t.Fatalf("NewBridge() error: %v", err)
+	}
+	if err := b.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer b.Stop()
+	mqtt.ClearPublished()
+
+	knxd.SimulateTelegram(Telegram{
+		Destination: GroupAddress{Main: 9, Middle: 0, Sub: 1},
+		APCI:        APCIWrite,
+		Data:        []byte{0x01},
+	})
+	time.Sleep(50 * time.Millisecond)
+
+	got := make(map[string]map[string]any)
+	for _, p := range mqtt.GetPublished() {
+		if p.Topic != StateTopic("9/0/1") {
+			continue
+		}
+		var state StateMessage
+		if err := json.Unmarshal(p.Payload, &state); err != nil {
+			t.Fatalf("Failed to unmarshal state: %v", err)
+		}
+		got[state.DeviceID] = state.State
+	}
+
+	if got["switch-hall"]["on"] != true {
+		t.Errorf("switch-hall state = %v, want on=true (DPT 1.001)", got["switch-hall"])
+	}
+	level, ok := got["logic-hall"]["level"].(float64)
+	if !ok || level < 0.39 || level > 0.4 {
+		t.Errorf("logic-hall state = %v, want level≈0.39 (DPT 5.001)", got["logic-hall"])
+	}
+}
@@ -1,7 +1,7 @@
 // Package knx implements the KNX protocol bridge for Gray Logic.
 //
 // This package provides connectivity to KNX building automation systems via
-// the knxd daemon. It translates between Gray Logic's internal representation
+// the knxd daemon or directly to a KNX/IP interface. It translates between Gray Logic's internal representation
 // and KNX group address telegrams.
 //
 // # Architecture
@@ -15,12 +15,13 @@
 //
 // # Key Responsibilities
 //
-//   - Connect to knxd daemon via Unix socket or TCP
+//   - Connect to knxd via Unix socket or TCP, or natively over KNXnet/IP
 //   - Subscribe to KNX group address telegrams
 //   - Translate KNX telegrams to MQTT state messages
 //   - Translate MQTT commands to KNX telegrams
 //   - Handle DPT (Datapoint Type) encoding/decoding
 //   - Publish health status and metrics
+//   - Optionally act as the bus time master (TimeMaster)
 //
 // # Group Addresses
 //
@@ -38,12 +39,111 @@
 // # Datapoint Types
 //
 // KNX defines standardised data formats (DPTs). This package supports common
-// DPTs for lighting, blinds, climate, and sensors:
+// DPTs for lighting, blinds, climate, sensors, and metering:
 //
 //   - DPT 1.xxx: 1-bit (switch, bool, up/down)
 //   - DPT 5.xxx: 1-byte unsigned (percentage, angle)
+//   - DPT 6.xxx: 1-byte signed (setpoint shift)
+//   - DPT 7.xxx: 2-byte unsigned (colour temperature)
 //   - DPT 9.xxx: 2-byte float (temperature, lux)
+//   - DPT 10.001: 3-byte time of day
+//   - DPT 11.001: 3-byte date
+//   - DPT 12.xxx: 4-byte unsigned counter (pulses)
+//   - DPT 13.xxx: 4-byte signed counter (energy Wh/kWh)
+//   - DPT 14.xxx: 4-byte IEEE float (power, voltage, current)
+//   - DPT 17.001/18.001: 1-byte scene number, scene control
+//   - DPT 19.001: 8-byte date and time
+//   - DPT 20.102/20.105: 1-byte enum (HVAC mode, HVAC control mode)
 //   - DPT 232.600: 3-byte RGB colour
+//   - DPT 242.600: 6-byte CIE xyY colour
+//   - DPT 251.600: 6-byte RGBW colour
+//
+// Numeric state carries its unit (UnitForDPT) in the state message.
+//
+// Encoding and decoding go through a codec registry keyed by DPT
+// (EncodeValue, DecodeValue), which also backs the generic write_function
+// and read_function commands for functions without a dedicated command.
+//
+// # Connectors
+//
+// The bridge talks to the bus through a Connector. KNXDClient uses the knxd
+// daemon (connector: knxd, the default). TunnelClient implements KNXnet/IP
+// tunnelling in pure Go (connector: tunnel): it opens a tunnel connection to
+// the gateway, sends heartbeats, tracks sequence counters, repeats
+// unacknowledged requests once, and reconnects when the tunnel is lost.
+// RoutingClient joins the KNXnet/IP routing multicast group (connector:
+// routing): it paces sending, honours ROUTING_BUSY flow control, and drops
+// duplicate frames forwarded by more than one router.
+//
+// # KNX Data Secure
+//
+// SecureConnector wraps any connector. With a keyring imported from ETS
+// (ParseKeyring, stored encrypted by KeyringStore), group telegrams to
+// secure group addresses are encrypted and authenticated with AES-CCM on
+// send, and received ones are authenticated, checked against the sender's
+// last sequence number (replay protection) and decrypted, so the bridge
+// handles secure group addresses like plain ones. Sequence numbers are
+// persisted. KNX IP Secure (secure tunnelling and routing) is not yet
+// supported; its keys are imported but unused.
+//
+// # Time Master
+//
+// With time_master enabled, the bridge writes the site's local time, date,
+// or both to the configured group addresses on start, every interval, at
+// local midnight, and when the UTC offset changes (daylight saving). Read
+// requests on those addresses are answered with the current time.
+//
+// # Telegram Scheduler
+//
+// Outgoing telegrams pass through a queue paced to scheduler.rate telegrams
+// per second. Telegrams are sent by priority (WithPriority): interactive
+// commands, then scene writes and time broadcasts (PriorityBulk), then
+// background state reads. A telegram for a group address that is already
+// queued is merged into it, and when the queue is full the lowest priority
+// is dropped first (ErrQueueFull). Queue depth, drops and merges are
+// reported in BridgeMetrics.
+//
+// # Bus Statistics
+//
+// BusStats counts received and sent telegrams in one-second buckets over
+// stats.window: load per line (relative to LineCapacity), top sources and
+// group addresses, and the read, write and response mix. Every sample
+// interval it hands a snapshot to the sample callback (written to the TSDB
+// by Core) and reports the load crossing stats.alert_load.
+//
+// # Polling
+//
+// Functions with the "read" flag and a poll interval (their own
+// poll_interval, or polling.defaults for their DPT) are read periodically,
+// with jitter, at PriorityBackground. A group address that stays silent for
+// polling.max_failures polls is no longer polled and its devices are marked
+// degraded or offline; a value for it seen on the bus resumes polling.
+//
+// # Command Buffer
+//
+// Commands received while the connector is disconnected are held, up to
+// buffer.size, and acknowledged AckQueued. A newer command writing the same
+// group address replaces a held one (the older is failed); reads are not
+// held. Held commands
+// are sent in arrival order once the connection returns; those older than
+// buffer.ttl are acknowledged AckTimeout instead.
+//
+// # Bus Monitor
+//
+// BusMonitor keeps the last monitor.size telegrams in a ring buffer, both
+// received and sent (sends are recorded by wrapping the connector), each
+// decoded with the DPT of its mapped device function. It feeds the
+// commissioning API, the knx.telegram WebSocket channel and an export in
+// the ETS telegram capture format.
+//
+// # State Cache
+//
+// The bridge caches the last value of each device function to publish only
+// changes. With a StateStore (StateCacheStore in SQLite) the cache is
+// persisted and restored at startup, filled in from the registry's state;
+// restored values are Stale until a telegram confirms them. Changes are
+// written in batches every few seconds and on Stop. CachedState returns a
+// device's values.
 //
 // # Thread Safety
 //
@@ -53,5 +153,8 @@
 //
 //   - KNX Specification: https://www.knx.org
 //   - knxd daemon: https://github.com/knxd/knxd
+//   - KNXnet/IP tunnelling: KNX Standard 03.08.04
+//   - KNXnet/IP routing: KNX Standard 03.08.05
+//   - KNX Data Secure: KNX Standard 03.03.07, AN158
 //   - Gray Logic KNX spec: docs/protocols/knx.md
 package knx
@@ -1,8 +1,10 @@
 package knx
 
 import (
+	"encoding/binary"
 	"fmt"
 	"math"
+	"time"
 )
 
 // KNX Datapoint Type encoding constants.
@@ -30,6 +32,50 @@ const (
 
 	// dpt9MantissaMask is the mask for extracting mantissa from DPT9.
 	dpt9MantissaMask = 0x07FF
+
+	// dpt4ByteLen is the number of bytes for DPT12/13/14 values.
+	dpt4ByteLen = 4
+
+	// dpt6Len is the number of bytes for DPT6 1-byte signed values.
+	dpt6Len = 1
+
+	// dpt20Len is the number of bytes for DPT20 1-byte enum values.
+	dpt20Len = 1
+
+	// dpt7Len is the number of bytes for DPT7 2-byte unsigned values.
+	dpt7Len = 2
+
+	// dpt10Len and dpt11Len are the number of bytes for DPT10 time of day
+	// and DPT11 date values.
+	dpt10Len = 3
+	dpt11Len = 3
+
+	// dpt11CenturyPivot is the two-digit year from which DPT11 years are
+	// in the 1900s (90-99 = 1990-1999, 0-89 = 2000-2089).
+	dpt11CenturyPivot = 90
+
+	// dpt19Len is the number of bytes for DPT19 date and time values.
+	dpt19Len = 8
+
+	// dpt19YearBase is the year encoded as 0 in DPT19.
+	dpt19YearBase = 1900
+
+	// DPT19 flag bits (byte 6).
+	dpt19Fault        = 0x80
+	dpt19NoWorkingDay = 0x20
+	dpt19NoDate       = 0x08
+	dpt19NoTime       = 0x02
+	dpt19SummerTime   = 0x01
+
+	// dptColourLen is the number of bytes for DPT242/251 colour values.
+	dptColourLen = 6
+
+	// dpt251ValidAll marks R, G, B and W valid in a DPT251 value.
+	dpt251ValidAll = 0x0F
+
+	// dpt242ValidAll marks colour (bit 1) and brightness (bit 0) valid
+	// in a DPT242 value.
+	dpt242ValidAll = 0x03
 )
 
 // DPT represents a KNX Datapoint Type identifier.
@@ -58,6 +104,13 @@ const (
 	DPTAngle      DPT = "5.003" // 0-360°
 	DPTPercentU8  DPT = "5.004" // 0-255 raw
 
+	// 1-byte signed types (DPT 6.xxx)
+	DPTPercentV8     DPT = "6.001" // -128 to 127%
+	DPTCounterPulse8 DPT = "6.010" // -128 to 127 pulses (setpoint shift steps)
+
+	// 2-byte unsigned types (DPT 7.xxx)
+	DPTColourTemp DPT = "7.600" // Kelvin
+
 	// 2-byte float types (DPT 9.xxx)
 	DPTTemperature DPT = "9.001" // -273 to 670760 °C
 	DPTLux         DPT = "9.004" // 0 to 670760 lux
@@ -65,12 +118,44 @@ const (
 	DPTHumidity    DPT = "9.007" // 0-100%
 	DPTAirQuality  DPT = "9.008" // ppm
 
+	// 3-byte time and date types (DPT 10.xxx, 11.xxx)
+	DPTTimeOfDay DPT = "10.001" // Day of week + hh:mm:ss
+	DPTDate      DPT = "11.001" // Day, month, year (1990-2089)
+
+	// 4-byte unsigned counter types (DPT 12.xxx)
+	DPTCounterPulses DPT = "12.001" // pulses
+
+	// 4-byte signed counter types (DPT 13.xxx)
+	DPTCounter        DPT = "13.001" // pulses
+	DPTActiveEnergy   DPT = "13.010" // Wh
+	DPTApparentEnergy DPT = "13.011" // VAh
+	DPTReactiveEnergy DPT = "13.012" // VARh
+	DPTActiveEnergyKW DPT = "13.013" // kWh
+	DPTLongDeltaTime  DPT = "13.100" // s
+
+	// 4-byte float types (DPT 14.xxx)
+	DPTElectricCurrent   DPT = "14.019" // A
+	DPTElectricPotential DPT = "14.027" // V
+	DPTFrequency         DPT = "14.033" // Hz
+	DPTPower             DPT = "14.056" // W
+	DPTPowerFactor       DPT = "14.057" // cos φ
+	DPTVolumeFlux        DPT = "14.077" // m³/s
+
+	// 1-byte enum types (DPT 20.xxx)
+	DPTHVACMode        DPT = "20.102" // Auto/Comfort/Standby/Economy/Protection
+	DPTHVACControlMode DPT = "20.105" // Heat/Cool/Off/...
+
 	// 1-byte scene types (DPT 17/18.xxx)
 	DPTSceneNumber  DPT = "17.001" // 0-63 scene number
 	DPTSceneControl DPT = "18.001" // Scene + learn bit
 
-	// 3-byte colour types (DPT 232.xxx)
-	DPTColourRGB DPT = "232.600" // R, G, B
+	// 8-byte date and time (DPT 19.xxx)
+	DPTDateTime DPT = "19.001" // Date, time, day of week + flags
+
+	// Colour types (DPT 232/242/251.xxx)
+	DPTColourRGB  DPT = "232.600" // R, G, B (3 bytes)
+	DPTColourXYY  DPT = "242.600" // CIE x, y + brightness (6 bytes)
+	DPTColourRGBW DPT = "251.600" // R, G, B, W (6 bytes)
 )
 
 // EncodeDPT1 encodes a boolean value to 1-bit KNX format.
@@ -211,6 +296,63 @@ func DecodeDPT5Angle(data []byte) (float64, error) {
 	return float64(data[0]) * dpt5AngleMax / dpt5MaxValue, nil
 }
 
+// EncodeDPT6 encodes a signed value to 1-byte KNX format.
+//
+// Used for: signed percentages (DPT 6.001) and counters such as setpoint
+// shift steps (DPT 6.010).
+//
+// Parameters:
+//   - value: Value to encode (-128 to 127)
+//
+// Returns:
+//   - []byte: Single byte, two's complement
+func EncodeDPT6(value int8) []byte {
+	return []byte{byte(value)}
+}
+
+// DecodeDPT6 decodes a 1-byte signed KNX value.
+//
+// Parameters:
+//   - data: KNX data (at least 1 byte)
+//
+// Returns:
+//   - int8: Decoded value
+//   - error: If data is empty
+func DecodeDPT6(data []byte) (int8, error) {
+	if len(data) < dpt6Len {
+		return 0, fmt.Errorf("%w: DPT6 requires 1 byte, got %d", ErrDecodingFailed, len(data))
+	}
+	return int8(data[0]), nil //nolint:gosec // two's complement reinterpretation is the wire format
+}
+
+// EncodeDPT7 encodes an unsigned value to 2-byte KNX format.
+//
+// Used for: colour temperature in Kelvin (DPT 7.600).
+//
+// Parameters:
+//   - value: Value to encode (0-65535)
+//
+// Returns:
+//   - []byte: Two bytes, big-endian
+func EncodeDPT7(value uint16) []byte {
+	return binary.BigEndian.AppendUint16(nil, value)
+}
+
+// DecodeDPT7 decodes a 2-byte unsigned KNX value.
+//
+// Parameters:
+//   - data: KNX data (at least 2 bytes)
+//
+// Returns:
+//   - uint16: Decoded value
+//   - error: If data is too short
+func DecodeDPT7(data []byte) (uint16, error) {
+	if len(data) < dpt7Len {
+		return 0, fmt.Errorf("%w: DPT7 requires %d bytes, got %d", ErrDecodingFailed, dpt7Len, len(data))
+	}
+	return binary.BigEndian.Uint16(data), nil
+}
+
 // EncodeDPT9 encodes a float value to 2-byte KNX floating point format.
 //
 // Used for: temperature, lux, humidity, etc.
@@ -293,6 +435,280 @@ func DecodeDPT9(data []byte) (float64, error) {
 	return value, nil
 }
 
+// TimeOfDay is a KNX time of day with optional day of week (DPT 10.001).
+type TimeOfDay struct {
+	Weekday int // 1 = Monday … 7 = Sunday, 0 = no day
+	Hour    int
+	Minute  int
+	Second  int
+}
+
+// String formats the time as "15:04:05".
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+}
+
+// knxWeekday converts a Go weekday to KNX numbering (1 = Monday … 7 = Sunday).
+func knxWeekday(d time.Weekday) int {
+	if d == time.Sunday {
+		return 7 //nolint:mnd // KNX numbers Sunday 7
+	}
+	return int(d)
+}
+
+// EncodeDPT10 encodes the wall-clock time of t to 3-byte KNX format.
+//
+// KNX time of day format:
+//
+//	Byte 0: DDDH HHHH (Day of week 1-7, Hour 0-23)
+//	Byte 1: 00MM MMMM (Minutes 0-59)
+//	Byte 2: 00SS SSSS (Seconds 0-59)
+//
+// Parameters:
+//   - t: Time to encode, already in the site's timezone
+//
+// Returns:
+//   - []byte: Three bytes in KNX format
+func EncodeDPT10(t time.Time) []byte {
+	//nolint:gosec // weekday 1-7 and clock fields fit their bit fields
+	return []byte{
+		byte(knxWeekday(t.Weekday())<<5 | t.Hour()),
+		byte(t.Minute()),
+		byte(t.Second()),
+	}
+}
+
+// DecodeDPT10 decodes a KNX time of day.
+//
+// Parameters:
+//   - data: KNX data (at least 3 bytes)
+//
+// Returns:
+//   - TimeOfDay: Decoded time
+//   - error: If data is too short or a field is out of range
+func DecodeDPT10(data []byte) (TimeOfDay, error) {
+	if len(data) < dpt10Len {
+		return TimeOfDay{}, fmt.Errorf("%w: DPT10 requires %d bytes, got %d", ErrDecodingFailed, dpt10Len, len(data))
+	}
+	t := TimeOfDay{
+		Weekday: int(data[0] >> 5),   //nolint:mnd // top 3 bits
+		Hour:    int(data[0] & 0x1F), //nolint:mnd // low 5 bits
+		Minute:  int(data[1] & 0x3F), //nolint:mnd // low 6 bits
+		Second:  int(data[2] & 0x3F), //nolint:mnd // low 6 bits
+	}
+	if t.Hour > 23 || t.Minute > 59 || t.Second > 59 {
+		return TimeOfDay{}, fmt.Errorf("%w: DPT10 invalid time %s", ErrDecodingFailed, t)
+	}
+	return t, nil
+}
+
+// EncodeDPT11 encodes the calendar date of t to 3-byte KNX format.
+//
+// KNX date format:
+//
+//	Byte 0: 000D DDDD (Day 1-31)
+//	Byte 1: 0000 MMMM (Month 1-12)
+//	Byte 2: 0YYY YYYY (Year 0-99: 90-99 = 1990s, 0-89 = 2000-2089)
+//
+// Parameters:
+//   - t: Date to encode, already in the site's timezone
+//
+// Returns:
+//   - []byte: Three bytes in KNX format
+//   - error: If the year is outside 1990-2089
+func EncodeDPT11(t time.Time) ([]byte, error) {
+	year := t.Year()
+	if year < 1900+dpt11CenturyPivot || year >= 2000+dpt11CenturyPivot {
+		return nil, fmt.Errorf("%w: DPT11 year must be %d-%d, got %d",
+			ErrEncodingFailed, 1900+dpt11CenturyPivot, 2000+dpt11CenturyPivot-1, year)
+	}
+	//nolint:gosec // day, month and two-digit year fit in a byte
+	return []byte{byte(t.Day()), byte(t.Month()), byte(year % 100)}, nil
+}
+
+// DecodeDPT11 decodes a KNX date.
+//
+// Parameters:
+//   - data: KNX data (at least 3 bytes)
+//
+// Returns:
+//   - time.Time: Midnight UTC on the decoded date
+//   - error: If data is too short or the date does not exist
+func DecodeDPT11(data []byte) (time.Time, error) {
+	if len(data) < dpt11Len {
+		return time.Time{}, fmt.Errorf("%w: DPT11 requires %d bytes, got %d", ErrDecodingFailed, dpt11Len, len(data))
+	}
+	day := int(data[0] & 0x1F)   //nolint:mnd // low 5 bits
+	month := int(data[1] & 0x0F) //nolint:mnd // low 4 bits
+	year := int(data[2] & 0x7F)  //nolint:mnd // low 7 bits
+	if year >= dpt11CenturyPivot {
+		year += 1900
+	} else {
+		year += 2000
+	}
+	return validDate(year, month, day, 0, 0, 0, "DPT11")
+}
+
+// EncodeDPT12 encodes an unsigned counter value to 4-byte KNX format.
+//
+// Used for: pulse counters (DPT 12.001) and operating hours/meters
+// reported as unsigned counts.
+//
+// Parameters:
+//   - value: Counter value (0 to 4294967295)
+//
+// Returns:
+//   - []byte: Four bytes, big-endian
+func EncodeDPT12(value uint32) []byte {
+	return binary.BigEndian.AppendUint32(nil, value)
+}
+
+// DecodeDPT12 decodes a 4-byte unsigned counter value.
+//
+// Parameters:
+//   - data: KNX data (at least 4 bytes)
+//
+// Returns:
+//   - uint32: Decoded counter value
+//   - error: If data is too short
+func DecodeDPT12(data []byte) (uint32, error) {
+	if len(data) < dpt4ByteLen {
+		return 0, fmt.Errorf("%w: DPT12 requires %d bytes, got %d", ErrDecodingFailed, dpt4ByteLen, len(data))
+	}
+	return binary.BigEndian.Uint32(data), nil
+}
+
+// EncodeDPT13 encodes a signed counter value to 4-byte KNX format.
+//
+// Used for: energy meters (DPT 13.010 Wh, 13.013 kWh) and other
+// two's-complement counters.
+//
+// Parameters:
+//   - value: Counter value (-2147483648 to 2147483647)
+//
+// Returns:
+//   - []byte: Four bytes, big-endian two's complement
+func EncodeDPT13(value int32) []byte {
+	return binary.BigEndian.AppendUint32(nil, uint32(value)) //nolint:gosec // two's complement reinterpretation is the wire format
+}
+
+// DecodeDPT13 decodes a 4-byte signed counter value.
+//
+// Parameters:
+//   - data: KNX data (at least 4 bytes)
+//
+// Returns:
+//   - int32: Decoded counter value
+//   - error: If data is too short
+func DecodeDPT13(data []byte) (int32, error) {
+	if len(data) < dpt4ByteLen {
+		return 0, fmt.Errorf("%w: DPT13 requires %d bytes, got %d", ErrDecodingFailed, dpt4ByteLen, len(data))
+	}
+	return int32(binary.BigEndian.Uint32(data)), nil //nolint:gosec // two's complement reinterpretation is the wire format
+}
+
+// EncodeDPT14 encodes a value to 4-byte KNX floating point format.
+//
+// Used for: power, voltage, current, frequency and other metering values.
+// DPT 14.xxx is an IEEE 754 single-precision float, big-endian.
+//
+// Parameters:
+//   - value: Float value to encode
+//
+// Returns:
+//   - []byte: Four bytes in KNX format
+//   - error: If value is NaN, infinite or outside float32 range
+func EncodeDPT14(value float64) ([]byte, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) || math.Abs(value) > math.MaxFloat32 {
+		return nil, fmt.Errorf("%w: DPT14 value not representable as float32: %g", ErrEncodingFailed, value)
+	}
+	return binary.BigEndian.AppendUint32(nil, math.Float32bits(float32(value))), nil
+}
+
+// DecodeDPT14 decodes a 4-byte KNX floating point value.
+//
+// Parameters:
+//   - data: KNX data (at least 4 bytes)
+//
+// Returns:
+//   - float64: Decoded value
+//   - error: If data is too short or not a finite number
+func DecodeDPT14(data []byte) (float64, error) {
+	if len(data) < dpt4ByteLen {
+		return 0, fmt.Errorf("%w: DPT14 requires %d bytes, got %d", ErrDecodingFailed, dpt4ByteLen, len(data))
+	}
+	value := float64(math.Float32frombits(binary.BigEndian.Uint32(data)))
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("%w: DPT14 value is not a finite number", ErrDecodingFailed)
+	}
+	return value, nil
+}
+
+// dptUnits maps DPT identifiers to the unit of their decoded value.
+var dptUnits = map[DPT]string{
+	DPTColourTemp:        "K",
+	DPTTemperature:       "°C",
+	DPTLux:               "lx",
+	DPTSpeed:             "m/s",
+	DPTHumidity:          "%",
+	DPTAirQuality:        "ppm",
+	DPTPercentage:        "%",
+	DPTAngle:             "°",
+	DPTCounterPulses:     "pulses",
+	DPTCounter:           "pulses",
+	DPTActiveEnergy:      "Wh",
+	DPTApparentEnergy:    "VAh",
+	DPTReactiveEnergy:    "VARh",
+	DPTActiveEnergyKW:    "kWh",
+	DPTLongDeltaTime:     "s",
+	DPTElectricCurrent:   "A",
+	DPTElectricPotential: "V",
+	DPTFrequency:         "Hz",
+	DPTPower:             "W",
+	DPTVolumeFlux:        "m³/s",
+}
+
+// UnitForDPT returns the unit of a DPT's decoded value, or "" if the DPT
+// is dimensionless or unknown.
+//
+// Parameters:
+//   - dpt: DPT identifier (e.g. "14.056")
+//
+// Returns:
+//   - string: Unit symbol (e.g. "W")
+func UnitForDPT(dpt string) string {
+	return dptUnits[DPT(dpt)]
+}
+
+// EncodeDPT20 encodes an enumeration value to 1-byte KNX format.
+//
+// Used for: HVAC mode (DPT 20.102), HVAC control mode (DPT 20.105).
+// See HVACMode and HVACControlMode for the enumerations.
+//
+// Parameters:
+//   - value: Enumeration value
+//
+// Returns:
+//   - []byte: Single byte
+func EncodeDPT20(value uint8) []byte {
+	return []byte{value}
+}
+
+// DecodeDPT20 decodes a 1-byte KNX enumeration value.
+//
+// Parameters:
+//   - data: KNX data (at least 1 byte)
+//
+// Returns:
+//   - uint8: Enumeration value
+//   - error: If data is empty
+func DecodeDPT20(data []byte) (uint8, error) {
+	if len(data) < dpt20Len {
+		return 0, fmt.Errorf("%w: DPT20 requires 1 byte, got %d", ErrDecodingFailed, len(data))
+	}
+	return data[0], nil
+}
+
 // EncodeDPT17 encodes a scene number (0-63) to 1-byte format.
 //
 // Parameters:
@@ -361,11 +777,118 @@ func DecodeDPT18(data []byte) (scene uint8, learn bool, err error) {
 	return scene, learn, nil
 }
 
+// EncodeDPT19 encodes the wall-clock date and time of t to 8-byte KNX format.
+//
+// KNX date time format:
+//
+//	Byte 0: Year - 1900 (0-255)
+//	Byte 1: 0000 MMMM (Month 1-12)
+//	Byte 2: 000D DDDD (Day 1-31)
+//	Byte 3: DDDH HHHH (Day of week 1-7, Hour 0-23)
+//	Byte 4: 00MM MMMM (Minutes 0-59)
+//	Byte 5: 00SS SSSS (Seconds 0-59)
+//	Byte 6: F WD NWD NY ND NDoW NT SUTI (flags)
+//	Byte 7: CLQ SRC 00 0000 (clock quality)
+//
+// Working day is marked unknown (NWD) since Core does not track holidays.
+// SUTI is set when t is in daylight saving time.
+//
+// Parameters:
+//   - t: Time to encode, already in the site's timezone
+//
+// Returns:
+//   - []byte: Eight bytes in KNX format
+//   - error: If the year is outside 1900-2155
+func EncodeDPT19(t time.Time) ([]byte, error) {
+	year := t.Year() - dpt19YearBase
+	if year < 0 || year > 255 {
+		return nil, fmt.Errorf("%w: DPT19 year must be %d-%d, got %d",
+			ErrEncodingFailed, dpt19YearBase, dpt19YearBase+255, t.Year())
+	}
+	var flags byte = dpt19NoWorkingDay
+	if t.IsDST() {
+		flags |= dpt19SummerTime
+	}
+	//nolint:gosec // all fields are range-checked or bounded by time.Time
+	return []byte{
+		byte(year),
+		byte(t.Month()),
+		byte(t.Day()),
+		byte(knxWeekday(t.Weekday())<<5 | t.Hour()),
+		byte(t.Minute()),
+		byte(t.Second()),
+		flags,
+		0,
+	}, nil
+}
+
+// DecodeDPT19 decodes a KNX date and time.
+//
+// The result carries the sender's wall-clock time in UTC; DPT19 has no
+// timezone, only a summer time flag.
+//
+// Parameters:
+//   - data: KNX data (at least 8 bytes)
+//
+// Returns:
+//   - time.Time: Decoded wall-clock date and time
+//   - error: If data is too short, the fault flag is set, the date or
+//     time is marked invalid, or a field is out of range
+func DecodeDPT19(data []byte) (time.Time, error) {
+	if len(data) < dpt19Len {
+		return time.Time{}, fmt.Errorf("%w: DPT19 requires %d bytes, got %d", ErrDecodingFailed, dpt19Len, len(data))
+	}
+	flags := data[6]
+	if flags&dpt19Fault != 0 {
+		return time.Time{}, fmt.Errorf("%w: DPT19 clock fault", ErrDecodingFailed)
+	}
+	if flags&(dpt19NoDate|dpt19NoTime) != 0 {
+		return time.Time{}, fmt.Errorf("%w: DPT19 date or time not valid (flags %02X)", ErrDecodingFailed, flags)
+	}
+	return validDate(
+		dpt19YearBase+int(data[0]),
+		int(data[1]&0x0F), //nolint:mnd // low 4 bits
+		int(data[2]&0x1F), //nolint:mnd // low 5 bits
+		int(data[3]&0x1F), //nolint:mnd // low 5 bits
+		int(data[4]&0x3F), //nolint:mnd // low 6 bits
+		int(data[5]&0x3F), //nolint:mnd // low 6 bits
+		"DPT19",
+	)
+}
+
+// validDate builds a UTC time, rejecting fields time.Date would normalise
+// (e.g. 31 February or hour 24).
+func validDate(year, month, day, hour, minute, second int, dpt string) (time.Time, error) {
+	t := time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC)
+	if t.Month() != time.Month(month) || t.Day() != day || t.Hour() != hour ||
+		t.Minute() != minute || t.Second() != second {
+		return time.Time{}, fmt.Errorf("%w: %s invalid date/time %04d-%02d-%02d %02d:%02d:%02d",
+			ErrDecodingFailed, dpt, year, month, day, hour, minute, second)
+	}
+	return t, nil
+}
+
 // RGB represents an RGB colour value.
 type RGB struct {
-	R uint8
-	G uint8
-	B uint8
+	R uint8 `json:"r"`
+	G uint8 `json:"g"`
+	B uint8 `json:"b"`
+}
+
+// RGBW represents an RGBW colour value.
+type RGBW struct {
+	R uint8 `json:"r"`
+	G uint8 `json:"g"`
+	B uint8 `json:"b"`
+	W uint8 `json:"w"`
+}
+
+// XYY represents a CIE 1931 xyY colour: chromaticity coordinates x and y
+// (0-1) and brightness (0-100%).
+type XYY struct {
+	X          float64 `json:"x"`
+	Y          float64 `json:"y"`
+	Brightness float64 `json:"brightness"`
 }
 
 // EncodeDPT232 encodes an RGB colour to 3-byte format.
@@ -393,3 +916,86 @@ func DecodeDPT232(data []byte) (RGB, error) {
 	}
 	return RGB{R: data[0], G: data[1], B: data[2]}, nil
 }
+
+// EncodeDPT251 encodes an RGBW colour to 6-byte format.
+//
+// Format: R, G, B, W, reserved, validity mask (bits 3-0 = R, G, B, W).
+// All four components are marked valid.
+//
+// Parameters:
+//   - rgbw: RGBW colour value
+//
+// Returns:
+//   - []byte: Six bytes
+func EncodeDPT251(rgbw RGBW) []byte {
+	return []byte{rgbw.R, rgbw.G, rgbw.B, rgbw.W, 0x00, dpt251ValidAll}
+}
+
+// DecodeDPT251 decodes a 6-byte RGBW colour value.
+// Components the sender marks invalid are returned as zero.
+//
+// Parameters:
+//   - data: KNX data (at least 6 bytes)
+//
+// Returns:
+//   - RGBW: Decoded colour
+//   - error: If data is too short
+func DecodeDPT251(data []byte) (RGBW, error) {
+	if len(data) < dptColourLen {
+		return RGBW{}, fmt.Errorf("%w: DPT251 requires %d bytes, got %d", ErrDecodingFailed, dptColourLen, len(data))
+	}
+	valid := data[5]
+	component := func(i int) uint8 {
+		if valid&(0x08>>i) == 0 {
+			return 0
+		}
+		return data[i]
+	}
+	return RGBW{R: component(0), G: component(1), B: component(2), W: component(3)}, nil
+}
+
+// EncodeDPT242 encodes a CIE xyY colour to 6-byte format.
+//
+// Format: x (2 bytes, 0-65535 = 0-1), y (2 bytes), brightness (1 byte,
+// 0-255 = 0-100%), validity (bit 1 = colour, bit 0 = brightness).
+// Both colour and brightness are marked valid.
+//
+// Parameters:
+//   - c: xyY colour value (x and y are clamped to 0-1, brightness to 0-100)
+//
+// Returns:
+//   - []byte: Six bytes
+func EncodeDPT242(c XYY) []byte {
+	x := uint16(math.Round(clamp(c.X, 0, 1) * math.MaxUint16))
+	y := uint16(math.Round(clamp(c.Y, 0, 1) * math.MaxUint16))
+	data := binary.BigEndian.AppendUint16(nil, x)
+	data = binary.BigEndian.AppendUint16(data, y)
+	return append(data, EncodeDPT5(c.Brightness)[0], dpt242ValidAll)
+}
+
+// DecodeDPT242 decodes a 6-byte CIE xyY colour value.
+//
+// Parameters:
+//   - data: KNX data (at least 6 bytes)
+//
+// Returns:
+//   - XYY: Decoded colour
+//   - error: If data is too short or marks neither colour nor brightness valid
+func DecodeDPT242(data []byte) (XYY, error) {
+	if len(data) < dptColourLen {
+		return XYY{}, fmt.Errorf("%w: DPT242 requires %d bytes, got %d", ErrDecodingFailed, dptColourLen, len(data))
+	}
+	if data[5]&dpt242ValidAll == 0 {
+		return XYY{}, fmt.Errorf("%w: DPT242 value has no valid fields", ErrDecodingFailed)
+	}
+	return XYY{
+		X:          float64(binary.BigEndian.Uint16(data[0:2])) / math.MaxUint16,
+		Y:          float64(binary.BigEndian.Uint16(data[2:4])) / math.MaxUint16,
+		Brightness: float64(data[4]) * 100 / dpt5MaxValue,
+	}, nil
+}
+
+// clamp limits v to the range [lo, hi].
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
@@ -1,8 +1,10 @@
 package knx
 
 import (
+	"bytes"
 	"math"
 	"testing"
+	"time"
 )
 
 // ─── DPT1 (Boolean) ────────────────────────────────────────────────
@@ -313,6 +315,270 @@ func TestDPT9_RoundTrip(t *testing.T) {
 	}
 }
 
+// ─── DPT6 (1-byte Signed) ──────────────────────────────────────────
+
+func TestDPT6(t *testing.T) {
+	tests := []struct {
+		name  string
+		value int8
+		want  byte
+	}{
+		{"zero", 0, 0x00},
+		{"plus one step", 1, 0x01},
+		{"minus two steps", -2, 0xFE},
+		{"max", 127, 0x7F},
+		{"min", -128, 0x80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EncodeDPT6(tt.value)
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("EncodeDPT6(%d) = % X, want %02X", tt.value, got, tt.want)
+			}
+			decoded, err := DecodeDPT6(got)
+			if err != nil || decoded != tt.value {
+				t.Errorf("DecodeDPT6(% X) = %d, %v; want %d", got, decoded, err, tt.value)
+			}
+		})
+	}
+
+	if _, err := DecodeDPT6(nil); err == nil {
+		t.Error("DecodeDPT6() with no data: expected error")
+	}
+}
+
+// ─── DPT7 (2-byte Unsigned) ────────────────────────────────────────
+
+func TestDPT7(t *testing.T) {
+	tests := []struct {
+		name  string
+		value uint16
+		want  []byte
+	}{
+		{"zero", 0, []byte{0x00, 0x00}},
+		{"warm white 2700K", 2700, []byte{0x0A, 0x8C}},
+		{"daylight 6500K", 6500, []byte{0x19, 0x64}},
+		{"max", 65535, []byte{0xFF, 0xFF}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EncodeDPT7(tt.value)
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("EncodeDPT7(%d) = % X, want % X", tt.value, got, tt.want)
+			}
+			decoded, err := DecodeDPT7(got)
+			if err != nil || decoded != tt.value {
+				t.Errorf("DecodeDPT7(% X) = %d, %v; want %d", got, decoded, err, tt.value)
+			}
+		})
+	}
+
+	if _, err := DecodeDPT7([]byte{0x0A}); err == nil {
+		t.Error("DecodeDPT7() with 1 byte: expected error")
+	}
+}
+
+// ─── DPT10/11 (Time of Day, Date) ──────────────────────────────────
+
+func TestDPT10(t *testing.T) {
+	// Sunday 23:59:58 → day 7
+	sunday := time.Date(2026, 3, 29, 23, 59, 58, 0, time.UTC)
+	got := EncodeDPT10(sunday)
+	want := []byte{7<<5 | 23, 59, 58}
+	if !bytes.Equal(got, want) {
+		t.Errorf("EncodeDPT10(%v) = % X, want % X", sunday, got, want)
+	}
+	decoded, err := DecodeDPT10(got)
+	if err != nil {
+		t.Fatalf("DecodeDPT10() error: %v", err)
+	}
+	if decoded != (TimeOfDay{Weekday: 7, Hour: 23, Minute: 59, Second: 58}) || decoded.String() != "23:59:58" {
+		t.Errorf("DecodeDPT10() = %+v (%s)", decoded, decoded)
+	}
+
+	// Monday → day 1
+	if got := EncodeDPT10(time.Date(2026, 3, 30, 8, 5, 0, 0, time.UTC)); got[0] != 1<<5|8 {
+		t.Errorf("EncodeDPT10(Monday 08:05) byte 0 = %02X, want %02X", got[0], 1<<5|8)
+	}
+
+	if _, err := DecodeDPT10([]byte{0x18, 0x00, 0x00}); err == nil {
+		t.Error("DecodeDPT10(hour 24): expected error")
+	}
+	if _, err := DecodeDPT10([]byte{0x00, 0x00}); err == nil {
+		t.Error("DecodeDPT10(2 bytes): expected error")
+	}
+}
+
+func TestDPT11(t *testing.T) {
+	tests := []struct {
+		date time.Time
+		want []byte
+	}{
+		{time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), []byte{16, 10, 26}},
+		{time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC), []byte{31, 12, 99}},
+		{time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), []byte{1, 1, 0}},
+	}
+	for _, tt := range tests {
+		got, err := EncodeDPT11(tt.date)
+		if err != nil || !bytes.Equal(got, tt.want) {
+			t.Errorf("EncodeDPT11(%v) = % X, %v; want % X", tt.date, got, err, tt.want)
+			continue
+		}
+		decoded, err := DecodeDPT11(got)
+		if err != nil || !decoded.Equal(tt.date) {
+			t.Errorf("DecodeDPT11(% X) = %v, %v; want %v", got, decoded, err, tt.date)
+		}
+	}
+
+	if _, err := EncodeDPT11(time.Date(2090, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("EncodeDPT11(2090): expected error")
+	}
+	if _, err := DecodeDPT11([]byte{31, 2, 26}); err == nil {
+		t.Error("DecodeDPT11(31 February): expected error")
+	}
+}
+
+// ─── DPT12/13 (4-byte Counters) ────────────────────────────────────
+
+func TestDPT12(t *testing.T) {
+	tests := []struct {
+		name  string
+		value uint32
+		want  []byte
+	}{
+		{"zero", 0, []byte{0x00, 0x00, 0x00, 0x00}},
+		{"pulses", 123456, []byte{0x00, 0x01, 0xE2, 0x40}},
+		{"max", 4294967295, []byte{0xFF, 0xFF, 0xFF, 0xFF}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EncodeDPT12(tt.value)
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("EncodeDPT12(%d) = % X, want % X", tt.value, got, tt.want)
+			}
+			decoded, err := DecodeDPT12(got)
+			if err != nil || decoded != tt.value {
+				t.Errorf("DecodeDPT12(% X) = %d, %v; want %d", got, decoded, err, tt.value)
+			}
+		})
+	}
+
+	if _, err := DecodeDPT12([]byte{0x00, 0x01, 0xE2}); err == nil {
+		t.Error("DecodeDPT12() with 3 bytes: expected error")
+	}
+}
+
+func TestDPT13(t *testing.T) {
+	tests := []struct {
+		name  string
+		value int32
+		want  []byte
+	}{
+		{"zero", 0, []byte{0x00, 0x00, 0x00, 0x00}},
+		{"energy Wh", 123456, []byte{0x00, 0x01, 0xE2, 0x40}},
+		{"negative (export)", -1, []byte{0xFF, 0xFF, 0xFF, 0xFF}},
+		{"min", -2147483648, []byte{0x80, 0x00, 0x00, 0x00}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EncodeDPT13(tt.value)
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("EncodeDPT13(%d) = % X, want % X", tt.value, got, tt.want)
+			}
+			decoded, err := DecodeDPT13(got)
+			if err != nil || decoded != tt.value {
+				t.Errorf("DecodeDPT13(% X) = %d, %v; want %d", got, decoded, err, tt.value)
+			}
+		})
+	}
+
+	if _, err := DecodeDPT13(nil); err == nil {
+		t.Error("DecodeDPT13() with no data: expected error")
+	}
+}
+
+// ─── DPT14 (4-byte Float) ──────────────────────────────────────────
+
+func TestEncodeDPT14(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   float64
+		want    []byte
+		wantErr bool
+	}{
+		{"zero", 0, []byte{0x00, 0x00, 0x00, 0x00}, false},
+		{"power W", 1500.5, []byte{0x44, 0xBB, 0x90, 0x00}, false},
+		{"voltage V", 230, []byte{0x43, 0x66, 0x00, 0x00}, false},
+		{"negative", -2.5, []byte{0xC0, 0x20, 0x00, 0x00}, false},
+		{"NaN", math.NaN(), nil, true},
+		{"infinity", math.Inf(1), nil, true},
+		{"beyond float32", 1e39, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodeDPT14(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EncodeDPT14(%v) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !bytes.Equal(got, tt.want) {
+				t.Errorf("EncodeDPT14(%v) = % X, want % X", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeDPT14(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    float64
+		wantErr bool
+	}{
+		{"power W", []byte{0x44, 0xBB, 0x90, 0x00}, 1500.5, false},
+		{"current A", []byte{0x3F, 0xC0, 0x00, 0x00}, 1.5, false},
+		{"NaN", []byte{0x7F, 0xC0, 0x00, 0x00}, 0, true},
+		{"infinity", []byte{0x7F, 0x80, 0x00, 0x00}, 0, true},
+		{"too short", []byte{0x44, 0xBB}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeDPT14(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeDPT14() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("DecodeDPT14(% X) = %v, want %v", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnitForDPT(t *testing.T) {
+	tests := map[string]string{
+		"14.056": "W",
+		"14.027": "V",
+		"14.019": "A",
+		"13.010": "Wh",
+		"13.013": "kWh",
+		"9.001":  "°C",
+		"1.001":  "",
+		"99.999": "",
+	}
+	for dpt, want := range tests {
+		if got := UnitForDPT(dpt); got != want {
+			t.Errorf("UnitForDPT(%q) = %q, want %q", dpt, got, want)
+		}
+	}
+}
+
 // ─── DPT17 (Scene Number) ──────────────────────────────────────────
 
 func TestEncodeDPT17(t *testing.T) {
@@ -436,6 +702,62 @@ func TestDecodeDPT18(t *testing.T) {
 	}
 }
 
+// ─── DPT19 (Date and Time) ─────────────────────────────────────────
+
+func TestDPT19(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		time time.Time
+		want []byte
+	}{
+		{
+			name: "winter (GMT)",
+			time: time.Date(2026, 1, 15, 9, 30, 15, 0, london), // Thursday
+			want: []byte{126, 1, 15, 4<<5 | 9, 30, 15, 0x20, 0x00},
+		},
+		{
+			name: "summer (BST) sets SUTI",
+			time: time.Date(2026, 7, 5, 18, 0, 0, 0, london), // Sunday
+			want: []byte{126, 7, 5, 7<<5 | 18, 0, 0, 0x21, 0x00},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodeDPT19(tt.time)
+			if err != nil || !bytes.Equal(got, tt.want) {
+				t.Fatalf("EncodeDPT19() = % X, %v; want % X", got, err, tt.want)
+			}
+			decoded, err := DecodeDPT19(got)
+			if err != nil {
+				t.Fatalf("DecodeDPT19() error: %v", err)
+			}
+			wall := time.Date(tt.time.Year(), tt.time.Month(), tt.time.Day(),
+				tt.time.Hour(), tt.time.Minute(), tt.time.Second(), 0, time.UTC)
+			if !decoded.Equal(wall) {
+				t.Errorf("DecodeDPT19() = %v, want %v", decoded, wall)
+			}
+		})
+	}
+
+	if _, err := EncodeDPT19(time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("EncodeDPT19(1899): expected error")
+	}
+	if _, err := DecodeDPT19([]byte{126, 1, 15, 9, 30, 15, 0x80, 0}); err == nil {
+		t.Error("DecodeDPT19(fault flag): expected error")
+	}
+	if _, err := DecodeDPT19([]byte{126, 1, 15, 9, 30, 15, 0x02, 0}); err == nil {
+		t.Error("DecodeDPT19(no time flag): expected error")
+	}
+	if _, err := DecodeDPT19([]byte{126, 1, 15}); err == nil {
+		t.Error("DecodeDPT19(3 bytes): expected error")
+	}
+}
+
 // ─── DPT232 (RGB Colour) ───────────────────────────────────────────
 
 func TestEncodeDPT232(t *testing.T) {
@@ -497,3 +819,64 @@ func TestDecodeDPT232(t *testing.T) {
 		})
 	}
 }
+
+// ─── DPT251 (RGBW Colour) ──────────────────────────────────────────
+
+func TestDPT251(t *testing.T) {
+	rgbw := RGBW{R: 255, G: 128, B: 0, W: 64}
+	got := EncodeDPT251(rgbw)
+	want := []byte{0xFF, 0x80, 0x00, 0x40, 0x00, 0x0F}
+	if !bytes.Equal(got, want) {
+		t.Errorf("EncodeDPT251() = % X, want % X", got, want)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    RGBW
+		wantErr bool
+	}{
+		{"all valid", want, rgbw, false},
+		{"white invalid", []byte{0xFF, 0x80, 0x00, 0x40, 0x00, 0x0E}, RGBW{R: 255, G: 128}, false},
+		{"only white valid", []byte{0xFF, 0x80, 0x00, 0x40, 0x00, 0x01}, RGBW{W: 64}, false},
+		{"too short", []byte{0xFF, 0x80, 0x00, 0x40}, RGBW{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeDPT251(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeDPT251() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("DecodeDPT251() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ─── DPT242 (xyY Colour) ───────────────────────────────────────────
+
+func TestDPT242(t *testing.T) {
+	c := XYY{X: 0.5, Y: 0.25, Brightness: 100}
+	got := EncodeDPT242(c)
+	want := []byte{0x80, 0x00, 0x40, 0x00, 0xFF, 0x03}
+	if !bytes.Equal(got, want) {
+		t.Errorf("EncodeDPT242() = % X, want % X", got, want)
+	}
+
+	decoded, err := DecodeDPT242(got)
+	if err != nil {
+		t.Fatalf("DecodeDPT242() error = %v", err)
+	}
+	if math.Abs(decoded.X-0.5) > 0.0001 || math.Abs(decoded.Y-0.25) > 0.0001 || decoded.Brightness != 100 {
+		t.Errorf("DecodeDPT242() = %+v, want ~%+v", decoded, c)
+	}
+
+	if _, err := DecodeDPT242([]byte{0x80, 0x00, 0x40, 0x00, 0xFF, 0x00}); err == nil {
+		t.Error("DecodeDPT242() with no valid fields: expected error")
+	}
+	if _, err := DecodeDPT242([]byte{0x80, 0x00}); err == nil {
+		t.Error("DecodeDPT242() with 2 bytes: expected error")
+	}
+}
@@ -27,12 +27,30 @@ var (
 	// ErrTelegramFailed is returned when sending a telegram to the bus fails.
 	ErrTelegramFailed = errors.New("knx: telegram send failed")
 
+	// ErrQueueFull is returned when the outgoing telegram queue is full.
+	ErrQueueFull = errors.New("knx: outgoing telegram queue full")
+
 	// ErrTimeout is returned when an operation times out.
 	ErrTimeout = errors.New("knx: operation timed out")
 
 	// ErrInvalidTelegram is returned when a received telegram is malformed.
 	ErrInvalidTelegram = errors.New("knx: invalid telegram")
 
+	// ErrInvalidKeyring is returned when an ETS keyring file cannot be read.
+	ErrInvalidKeyring = errors.New("knx: invalid keyring")
+
+	// ErrKeyringPassword is returned when a keyring's signature does not
+	// match its password (wrong password or modified file).
+	ErrKeyringPassword = errors.New("knx: keyring password is wrong or the file was modified")
+
+	// ErrKeyringSecret is returned when the stored keyring cannot be
+	// decrypted with the configured keyring secret.
+	ErrKeyringSecret = errors.New("knx: stored keyring cannot be decrypted with the keyring secret")
+
+	// ErrSecureFailed is returned when a KNX Data Secure telegram cannot be
+	// encrypted or fails authentication.
+	ErrSecureFailed = errors.New("knx: secure telegram failed")
+
 	// ErrProtocolDesync is returned when the protocol stream becomes corrupted.
 	// This is a fatal error requiring connection reset.
 	ErrProtocolDesync = errors.New("knx: protocol desync, connection must be reset")
@@ -28,6 +28,9 @@ var CanonicalFunctions = []FunctionDef{
 	{Name: "rgb", StateKey: "rgb", DPT: "232.600", Flags: []string{"write"}, Aliases: []string{"colour"}},
 	{Name: "rgb_status", StateKey: "rgb", DPT: "232.600", Flags: []string{"read", "transmit"}, Aliases: []string{}},
 	{Name: "rgbw", StateKey: "rgbw", DPT: "251.600", Flags: []string{"write"}, Aliases: []string{}},
+	{Name: "rgbw_status", StateKey: "rgbw", DPT: "251.600", Flags: []string{"read", "transmit"}, Aliases: []string{}},
+	{Name: "color_xyy", StateKey: "xyy", DPT: "242.600", Flags: []string{"write"}, Aliases: []string{"colour_xyy", "xyy"}},                                 //nolint:misspell // KNX standard uses American "color"
+	{Name: "color_xyy_status", StateKey: "xyy", DPT: "242.600", Flags: []string{"read", "transmit"}, Aliases: []string{"colour_xyy_status", "xyy_status"}}, //nolint:misspell // KNX standard uses American "color"
 	{Name: "dimming_control", StateKey: "dimming_control", DPT: "3.007", Flags: []string{"write"}, Aliases: []string{"relative_dimming"}},
 
 	// ── Blinds / Shutters ────────────────────────────────────
@@ -47,6 +50,11 @@ var CanonicalFunctions = []FunctionDef{
 	{Name: "heating", StateKey: "heating", DPT: "1.001", Flags: []string{"read", "transmit"}, Aliases: []string{"heat_demand"}},
 	{Name: "cooling", StateKey: "cooling", DPT: "1.001", Flags: []string{"read", "transmit"}, Aliases: []string{"cool_demand"}},
 	{Name: "hvac_mode", StateKey: "hvac_mode", DPT: "20.102", Flags: []string{"write"}, Aliases: []string{"mode"}},
+	{Name: "hvac_mode_status", StateKey: "hvac_mode", DPT: "20.102", Flags: []string{"read", "transmit"}, Aliases: []string{"mode_status"}},
+	{Name: "hvac_control_mode", StateKey: "hvac_control_mode", DPT: "20.105", Flags: []string{"write"}, Aliases: []string{"controller_mode"}},
+	{Name: "hvac_control_mode_status", StateKey: "hvac_control_mode", DPT: "20.105", Flags: []string{"read", "transmit"}, Aliases: []string{"controller_status", "controller_mode_status"}},
+	{Name: "setpoint_shift", StateKey: "setpoint_shift", DPT: "6.010", Flags: []string{"write"}, Aliases: []string{"base_setpoint_shift"}},
+	{Name: "setpoint_shift_status", StateKey: "setpoint_shift", DPT: "6.010", Flags: []string{"read", "transmit"}, Aliases: []string{}},
 	{Name: "valve", StateKey: "valve", DPT: "5.001", Flags: []string{"write"}, Aliases: []string{"valve_cmd", "valve_position"}},
 	{Name: "valve_status", StateKey: "valve", DPT: "5.001", Flags: []string{"read", "transmit"}, Aliases: []string{"valve_feedback"}},
 	{Name: "humidity", StateKey: "humidity", DPT: "9.007", Flags: []string{"read", "transmit"}, Aliases: []string{"rh", "relative_humidity"}},
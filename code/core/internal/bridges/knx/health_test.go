@@ -80,6 +80,10 @@ func (m *mockConnector) SendRead(_ context.Context, _ GroupAddress) error {
 	return nil
 }
 
+func (m *mockConnector) SendResponse(_ context.Context, _ GroupAddress, _ []byte) error {
+	return nil
+}
+
 func (m *mockConnector) SetOnTelegram(_ func(Telegram)) {}
 
 func (m *mockConnector) IsConnected() bool {
@@ -0,0 +1,133 @@
+package knx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HVACMode is the KNX room operating mode (DPT 20.102).
+type HVACMode uint8
+
+// HVAC mode values (DPT 20.102).
+const (
+	HVACModeAuto               HVACMode = 0
+	HVACModeComfort            HVACMode = 1
+	HVACModeStandby            HVACMode = 2
+	HVACModeEconomy            HVACMode = 3
+	HVACModeBuildingProtection HVACMode = 4
+)
+
+// hvacModeNames are the state names of HVAC modes, indexed by value.
+var hvacModeNames = []string{
+	HVACModeAuto:               "auto",
+	HVACModeComfort:            "comfort",
+	HVACModeStandby:            "standby",
+	HVACModeEconomy:            "economy",
+	HVACModeBuildingProtection: "building_protection",
+}
+
+// hvacModeAliases are accepted alternative names for HVAC modes.
+var hvacModeAliases = map[string]HVACMode{
+	"night":            HVACModeEconomy,
+	"frost":            HVACModeBuildingProtection,
+	"frost_protection": HVACModeBuildingProtection,
+	"heat_protection":  HVACModeBuildingProtection,
+	"protection":       HVACModeBuildingProtection,
+}
+
+// String returns the mode's state name (e.g. "comfort").
+func (m HVACMode) String() string {
+	if int(m) < len(hvacModeNames) {
+		return hvacModeNames[m]
+	}
+	return fmt.Sprintf("hvac_mode_%d", m)
+}
+
+// ParseHVACMode parses an HVAC mode name. Accepts the state names and the
+// aliases "night", "frost", "frost_protection", "heat_protection" and
+// "protection". Matching is case-insensitive.
+func ParseHVACMode(name string) (HVACMode, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i, n := range hvacModeNames {
+		if n == name {
+			return HVACMode(i), nil //nolint:gosec // index bounded by hvacModeNames
+		}
+	}
+	if m, ok := hvacModeAliases[name]; ok {
+		return m, nil
+	}
+	return 0, fmt.Errorf("%w: unknown HVAC mode %q", ErrEncodingFailed, name)
+}
+
+// DecodeHVACMode decodes a DPT 20.102 value.
+// Returns an error for values reserved by the KNX specification.
+func DecodeHVACMode(data []byte) (HVACMode, error) {
+	v, err := DecodeDPT20(data)
+	if err != nil {
+		return 0, err
+	}
+	if int(v) >= len(hvacModeNames) {
+		return 0, fmt.Errorf("%w: DPT20.102 reserved value %d", ErrDecodingFailed, v)
+	}
+	return HVACMode(v), nil
+}
+
+// HVACControlMode is the KNX HVAC controller mode (DPT 20.105).
+type HVACControlMode uint8
+
+// hvacControlModeNames are the state names of HVAC control modes, by value.
+// Values 18 and 19 are reserved.
+var hvacControlModeNames = map[HVACControlMode]string{
+	0:  "auto",
+	1:  "heat",
+	2:  "morning_warmup",
+	3:  "cool",
+	4:  "night_purge",
+	5:  "precool",
+	6:  "off",
+	7:  "test",
+	8:  "emergency_heat",
+	9:  "fan_only",
+	10: "free_cool",
+	11: "ice",
+	12: "maximum_heating",
+	13: "economic_heat_cool",
+	14: "dehumidification",
+	15: "calibration",
+	16: "emergency_cool",
+	17: "emergency_steam",
+	20: "nodem",
+}
+
+// String returns the control mode's state name (e.g. "heat").
+func (m HVACControlMode) String() string {
+	if n, ok := hvacControlModeNames[m]; ok {
+		return n
+	}
+	return fmt.Sprintf("hvac_control_mode_%d", m)
+}
+
+// ParseHVACControlMode parses an HVAC control mode name (case-insensitive).
+func ParseHVACControlMode(name string) (HVACControlMode, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for m, n := range hvacControlModeNames {
+		if n == name {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unknown HVAC control mode %q", ErrEncodingFailed, name)
+}
+
+// DecodeHVACControlMode decodes a DPT 20.105 value.
+// Returns an error for values reserved by the KNX specification.
+func DecodeHVACControlMode(data []byte) (HVACControlMode, error) {
+	v, err := DecodeDPT20(data)
+	if err != nil {
+		return 0, err
+	}
+	m := HVACControlMode(v)
+	if _, ok := hvacControlModeNames[m]; !ok {
+		return 0, fmt.Errorf("%w: DPT20.105 reserved value %d", ErrDecodingFailed, v)
+	}
+	return m, nil
+}
@@ -0,0 +1,83 @@
+package knx
+
+import "testing"
+
+func TestParseHVACMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    HVACMode
+		wantErr bool
+	}{
+		{"auto", HVACModeAuto, false},
+		{"comfort", HVACModeComfort, false},
+		{"Standby", HVACModeStandby, false},
+		{"economy", HVACModeEconomy, false},
+		{"night", HVACModeEconomy, false},
+		{"building_protection", HVACModeBuildingProtection, false},
+		{"frost", HVACModeBuildingProtection, false},
+		{"turbo", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHVACMode(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseHVACMode(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseHVACMode(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeHVACMode(t *testing.T) {
+	for v, want := range []string{"auto", "comfort", "standby", "economy", "building_protection"} {
+		got, err := DecodeHVACMode(EncodeDPT20(uint8(v)))
+		if err != nil || got.String() != want {
+			t.Errorf("DecodeHVACMode(%d) = %v, %v; want %s", v, got, err, want)
+		}
+	}
+	if _, err := DecodeHVACMode([]byte{0x05}); err == nil {
+		t.Error("DecodeHVACMode(5): expected error for reserved value")
+	}
+	if _, err := DecodeHVACMode(nil); err == nil {
+		t.Error("DecodeHVACMode(nil): expected error")
+	}
+}
+
+func TestHVACControlMode(t *testing.T) {
+	tests := []struct {
+		value byte
+		want  string
+	}{
+		{0, "auto"},
+		{1, "heat"},
+		{3, "cool"},
+		{6, "off"},
+		{9, "fan_only"},
+		{14, "dehumidification"},
+		{20, "nodem"},
+	}
+	for _, tt := range tests {
+		got, err := DecodeHVACControlMode([]byte{tt.value})
+		if err != nil || got.String() != tt.want {
+			t.Errorf("DecodeHVACControlMode(%d) = %v, %v; want %s", tt.value, got, err, tt.want)
+		}
+		parsed, err := ParseHVACControlMode(tt.want)
+		if err != nil || byte(parsed) != tt.value {
+			t.Errorf("ParseHVACControlMode(%q) = %d, %v; want %d", tt.want, parsed, err, tt.value)
+		}
+	}
+
+	for _, reserved := range []byte{18, 19, 21, 255} {
+		if _, err := DecodeHVACControlMode([]byte{reserved}); err == nil {
+			t.Errorf("DecodeHVACControlMode(%d): expected error for reserved value", reserved)
+		}
+	}
+	if _, err := ParseHVACControlMode("warp"); err == nil {
+		t.Error("ParseHVACControlMode(\"warp\"): expected error")
+	}
+}
@@ -0,0 +1,414 @@
+package knx
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ETS keyring (.knxkeys) constants.
+const (
+	// keyringSalt is the PBKDF2 salt for the keyring password.
+	keyringSalt = "1.keyring.ets.knx.org"
+
+	// keyringIterations is the PBKDF2 iteration count.
+	keyringIterations = 65536
+
+	// secureKeyLen is the length of AES-128 keys in the keyring.
+	secureKeyLen = 16
+
+	// keyringPasswordPrefix is the random prefix before encrypted passwords.
+	keyringPasswordPrefix = 8
+
+	// MaxKeyringSize bounds an uploaded keyring file.
+	MaxKeyringSize = 10 << 20
+)
+
+// Keyring holds the KNX Secure keys and sequence numbers exported from an
+// ETS project as a .knxkeys file. Keys are decrypted; store the keyring
+// with KeyringStore, which encrypts it at rest.
+type Keyring struct {
+	// Project is the ETS project name.
+	Project string `json:"project"`
+
+	// CreatedBy is the ETS version that exported the keyring.
+	CreatedBy string `json:"created_by"`
+
+	// Created is the export timestamp as written by ETS.
+	Created string `json:"created"`
+
+	// GroupKeys maps secure group addresses ("1/2/3") to AES-128 keys.
+	GroupKeys map[string][]byte `json:"group_keys"`
+
+	// Devices lists secure devices with their last known sequence numbers.
+	Devices []KeyringDevice `json:"devices,omitempty"`
+
+	// Backbone is the IP Secure routing backbone, if configured.
+	Backbone *KeyringBackbone `json:"backbone,omitempty"`
+
+	// Interfaces lists IP Secure tunnelling interfaces.
+	Interfaces []KeyringInterface `json:"interfaces,omitempty"`
+
+	// ImportedAt is when the keyring was stored (set by KeyringStore).
+	ImportedAt time.Time `json:"imported_at,omitzero"`
+}
+
+// KeyringDevice is a secure device in the keyring.
+type KeyringDevice struct {
+	IndividualAddress string `json:"individual_address"`
+	SequenceNumber    uint64 `json:"sequence_number"`
+}
+
+// KeyringBackbone is the IP Secure routing backbone.
+type KeyringBackbone struct {
+	MulticastAddress string `json:"multicast_address"`
+	Latency          int    `json:"latency_ms"`
+	Key              []byte `json:"key"`
+}
+
+// KeyringInterface is an IP Secure tunnelling interface.
+type KeyringInterface struct {
+	Type              string `json:"type"`
+	Host              string `json:"host,omitempty"`
+	IndividualAddress string `json:"individual_address"`
+	UserID            int    `json:"user_id,omitempty"`
+	Password          string `json:"password,omitempty"`
+}
+
+// KeyringSummary describes a keyring without its keys.
+type KeyringSummary struct {
+	Project        string    `json:"project"`
+	CreatedBy      string    `json:"created_by"`
+	Created        string    `json:"created"`
+	GroupAddresses []string  `json:"group_addresses"`
+	Devices        int       `json:"devices"`
+	Interfaces     int       `json:"interfaces"`
+	Backbone       bool      `json:"backbone"`
+	ImportedAt     time.Time `json:"imported_at,omitzero"`
+}
+
+// Summary returns the keyring's metadata and secure group addresses,
+// sorted, without any keys.
+func (k *Keyring) Summary() KeyringSummary {
+	gas := make([]string, 0, len(k.GroupKeys))
+	for ga := range k.GroupKeys {
+		gas = append(gas, ga)
+	}
+	sort.Strings(gas)
+	return KeyringSummary{
+		Project:        k.Project,
+		CreatedBy:      k.CreatedBy,
+		Created:        k.Created,
+		GroupAddresses: gas,
+		Devices:        len(k.Devices),
+		Interfaces:     len(k.Interfaces),
+		Backbone:       k.Backbone != nil,
+		ImportedAt:     k.ImportedAt,
+	}
+}
+
+// ParseKeyring verifies and decrypts an ETS keyring (.knxkeys) file.
+//
+// Parameters:
+//   - data: Keyring file content (XML)
+//   - password: Keyring password set when exporting from ETS
+//
+// Returns:
+//   - *Keyring: Keyring with decrypted keys
+//   - error: ErrInvalidKeyring if the file is malformed, ErrKeyringPassword
+//     if the password does not match the file's signature
+func ParseKeyring(data []byte, password string) (*Keyring, error) {
+	if len(data) > MaxKeyringSize {
+		return nil, fmt.Errorf("%w: file exceeds %d bytes", ErrInvalidKeyring, MaxKeyringSize)
+	}
+	root, err := parseXMLTree(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKeyring, err)
+	}
+	if root.name != "Keyring" {
+		return nil, fmt.Errorf("%w: root element is %q, want Keyring", ErrInvalidKeyring, root.name)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(root.attr("Signature"))
+	if err != nil || len(signature) == 0 {
+		return nil, fmt.Errorf("%w: missing or invalid signature", ErrInvalidKeyring)
+	}
+	passwordHash, err := hashKeyringPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKeyring, err)
+	}
+	if subtle.ConstantTimeCompare(signature, keyringSignature(root, passwordHash)) != 1 {
+		return nil, ErrKeyringPassword
+	}
+
+	k := &Keyring{
+		Project:   root.attr("Project"),
+		CreatedBy: root.attr("CreatedBy"),
+		Created:   root.attr("Created"),
+		GroupKeys: make(map[string][]byte),
+	}
+	createdHash := sha256.Sum256([]byte(k.Created))
+	dec := keyringDecrypter{key: passwordHash, iv: createdHash[:aes.BlockSize]}
+
+	for _, child := range root.children {
+		var err error
+		switch child.name {
+		case "Backbone":
+			err = k.parseBackbone(child, dec)
+		case "Interface":
+			err = k.parseInterface(child, dec)
+		case "GroupAddresses":
+			err = k.parseGroupAddresses(child, dec)
+		case "Devices":
+			err = k.parseDevices(child)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidKeyring, err)
+		}
+	}
+	return k, nil
+}
+
+// parseBackbone reads the IP Secure routing backbone.
+func (k *Keyring) parseBackbone(n *xmlNode, dec keyringDecrypter) error {
+	key, err := dec.key16(n.attr("Key"))
+	if err != nil {
+		return fmt.Errorf("backbone key: %w", err)
+	}
+	latency, _ := strconv.Atoi(n.attr("Latency"))
+	k.Backbone = &KeyringBackbone{
+		MulticastAddress: n.attr("MulticastAddress"),
+		Latency:          latency,
+		Key:              key,
+	}
+	return nil
+}
+
+// parseInterface reads an IP Secure tunnelling interface.
+func (k *Keyring) parseInterface(n *xmlNode, dec keyringDecrypter) error {
+	iface := KeyringInterface{
+		Type:              n.attr("Type"),
+		Host:              n.attr("Host"),
+		IndividualAddress: n.attr("IndividualAddress"),
+	}
+	iface.UserID, _ = strconv.Atoi(n.attr("UserID"))
+	if enc := n.attr("Password"); enc != "" {
+		password, err := dec.password(enc)
+		if err != nil {
+			return fmt.Errorf("interface %s password: %w", iface.IndividualAddress, err)
+		}
+		iface.Password = password
+	}
+	k.Interfaces = append(k.Interfaces, iface)
+	return nil
+}
+
+// parseGroupAddresses reads the secure group address keys.
+func (k *Keyring) parseGroupAddresses(n *xmlNode, dec keyringDecrypter) error {
+	for _, g := range n.children {
+		if g.name != "Group" {
+			continue
+		}
+		ga, err := parseKeyringGroupAddress(g.attr("Address"))
+		if err != nil {
+			return err
+		}
+		key, err := dec.key16(g.attr("Key"))
+		if err != nil {
+			return fmt.Errorf("group %s key: %w", ga, err)
+		}
+		k.GroupKeys[ga.String()] = key
+	}
+	return nil
+}
+
+// parseDevices reads secure devices and their sequence numbers.
+func (k *Keyring) parseDevices(n *xmlNode) error {
+	for _, d := range n.children {
+		if d.name != "Device" {
+			continue
+		}
+		ia := d.attr("IndividualAddress")
+		if _, err := parseIndividualAddress(ia); err != nil {
+			return err
+		}
+		var seq uint64
+		if s := d.attr("SequenceNumber"); s != "" {
+			v, err := strconv.ParseUint(s, 10, 48)
+			if err != nil {
+				return fmt.Errorf("device %s sequence number %q: %w", ia, s, err)
+			}
+			seq = v
+		}
+		k.Devices = append(k.Devices, KeyringDevice{IndividualAddress: ia, SequenceNumber: seq})
+	}
+	return nil
+}
+
+// parseKeyringGroupAddress accepts the raw 16-bit form ETS writes ("2049")
+// as well as "1/0/1".
+func parseKeyringGroupAddress(s string) (GroupAddress, error) {
+	if v, err := strconv.ParseUint(s, 10, 16); err == nil {
+		return GroupAddressFromUint16(uint16(v)), nil
+	}
+	return ParseGroupAddress(s)
+}
+
+// hashKeyringPassword derives the key that encrypts keyring secrets.
+func hashKeyringPassword(password string) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, password, []byte(keyringSalt), keyringIterations, secureKeyLen)
+}
+
+// keyringSignature computes the signature ETS writes to the Keyring
+// element: the first 16 bytes of SHA-256 over every element (name and
+// sorted attributes, excluding xmlns and Signature) followed by the
+// base64 password hash.
+func keyringSignature(root *xmlNode, passwordHash []byte) []byte {
+	var buf bytes.Buffer
+	appendString := func(s string) {
+		buf.WriteByte(byte(len(s))) //nolint:gosec // keyring names and values are short
+		buf.WriteString(s)
+	}
+
+	var stream func(n *xmlNode)
+	stream = func(n *xmlNode) {
+		buf.WriteByte(0x01)
+		appendString(n.name)
+		attrs := make([]xml.Attr, 0, len(n.attrs))
+		for _, a := range n.attrs {
+			if a.Name.Local == "xmlns" || a.Name.Space == "xmlns" || a.Name.Local == "Signature" {
+				continue
+			}
+			attrs = append(attrs, a)
+		}
+		sort.Slice(attrs, func(i, j int) bool { return attrs[i].Name.Local < attrs[j].Name.Local })
+		for _, a := range attrs {
+			appendString(a.Name.Local)
+			appendString(a.Value)
+		}
+		for _, c := range n.children {
+			stream(c)
+		}
+		buf.WriteByte(0x02) //nolint:mnd // end of element marker
+	}
+	stream(root)
+	appendString(base64.StdEncoding.EncodeToString(passwordHash))
+
+	sum := sha256.Sum256(buf.Bytes())
+	return sum[:secureKeyLen]
+}
+
+// keyringDecrypter decrypts keyring secrets: AES-128-CBC with the password
+// hash as key and the hash of the Created attribute as IV.
+type keyringDecrypter struct {
+	key []byte
+	iv  []byte
+}
+
+// decrypt decodes and decrypts a base64 secret.
+func (d keyringDecrypter) decrypt(encoded string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64: %w", err)
+	}
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("encrypted length %d is not a multiple of %d", len(data), aes.BlockSize)
+	}
+	block, err := aes.NewCipher(d.key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, d.iv).CryptBlocks(out, data)
+	return out, nil
+}
+
+// key16 decrypts an AES-128 key.
+func (d keyringDecrypter) key16(encoded string) ([]byte, error) {
+	key, err := d.decrypt(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != secureKeyLen {
+		return nil, fmt.Errorf("key length %d, want %d", len(key), secureKeyLen)
+	}
+	return key, nil
+}
+
+// password decrypts a password: 8 random bytes, the password, then
+// padding whose last byte is the padding length.
+func (d keyringDecrypter) password(encoded string) (string, error) {
+	data, err := d.decrypt(encoded)
+	if err != nil {
+		return "", err
+	}
+	pad := int(data[len(data)-1])
+	if pad == 0 || keyringPasswordPrefix+pad > len(data) {
+		return "", errors.New("invalid password padding")
+	}
+	return string(data[keyringPasswordPrefix : len(data)-pad]), nil
+}
+
+// xmlNode is a minimal XML element tree.
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr
+	children []*xmlNode
+}
+
+// attr returns the value of an attribute by local name, or "".
+func (n *xmlNode) attr(name string) string {
+	for _, a := range n.attrs {
+		if a.Name.Local == name && a.Name.Space != "xmlns" {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// parseXMLTree parses a document into an element tree, ignoring text.
+func parseXMLTree(data []byte) (*xmlNode, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var root *xmlNode
+	var stack []*xmlNode
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{name: t.Name.Local, attrs: t.Attr}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, errors.New("multiple root elements")
+				}
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if root == nil {
+		return nil, errors.New("empty document")
+	}
+	return root, nil
+}
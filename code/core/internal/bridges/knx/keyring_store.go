@@ -0,0 +1,171 @@
+package knx
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// keyringKeyLabel domain-separates the derived keyring encryption key.
+const keyringKeyLabel = "graylogic/knx-keyring/v1"
+
+// Ensure KeyringStore implements SequenceStore.
+var _ SequenceStore = (*KeyringStore)(nil)
+
+// KeyringStore persists the imported KNX Secure keyring, encrypted at rest
+// with AES-256-GCM, and Data Secure sequence numbers.
+//
+// The encryption key is derived from a dedicated keyring secret
+// (protocols.knx.keyring_secret), not shared with API authentication.
+// LoadKeyring fails with ErrKeyringSecret if the secret has changed since
+// the keyring was stored.
+//
+// The database must have the knx_secure_keyring and knx_secure_sequences
+// tables created.
+//
+// Thread Safety: All methods are safe for concurrent use.
+type KeyringStore struct {
+	db   *sql.DB
+	aead cipher.AEAD
+}
+
+// NewKeyringStore creates a keyring store.
+//
+// Parameters:
+//   - db: Database with the KNX Secure tables
+//   - secret: Keyring secret the encryption key is derived from
+//
+// Returns:
+//   - *KeyringStore: Ready to use
+//   - error: If the secret is empty
+func NewKeyringStore(db *sql.DB, secret string) (*KeyringStore, error) {
+	if secret == "" {
+		return nil, errors.New("keyring store: secret is required")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(keyringKeyLabel))
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, fmt.Errorf("keyring store: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("keyring store: %w", err)
+	}
+	return &KeyringStore{db: db, aead: aead}, nil
+}
+
+// SaveKeyring encrypts and stores a keyring, replacing any previous one.
+// ImportedAt is set to now.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - k: Keyring to store
+//
+// Returns:
+//   - error: If encryption or the database write fails
+func (s *KeyringStore) SaveKeyring(ctx context.Context, k *Keyring) error {
+	k.ImportedAt = time.Now().UTC().Truncate(time.Second)
+	plain, err := json.Marshal(k)
+	if err != nil {
+		return fmt.Errorf("encoding keyring: %w", err)
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+	sealed := s.aead.Seal(nonce, nonce, plain, nil)
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO knx_secure_keyring (id, project, created, keyring, imported_at)
+		VALUES (1, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			project = excluded.project,
+			created = excluded.created,
+			keyring = excluded.keyring,
+			imported_at = excluded.imported_at
+	`, k.Project, k.Created, sealed, k.ImportedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("storing keyring: %w", err)
+	}
+	return nil
+}
+
+// LoadKeyring reads and decrypts the stored keyring.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//
+// Returns:
+//   - *Keyring: Stored keyring, or nil if none has been imported
+//   - error: If the database read fails, or ErrKeyringSecret if the keyring
+//     was stored with a different secret
+func (s *KeyringStore) LoadKeyring(ctx context.Context) (*Keyring, error) {
+	var sealed []byte
+	err := s.db.QueryRowContext(ctx, `SELECT keyring FROM knx_secure_keyring WHERE id = 1`).Scan(&sealed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil //nolint:nilnil // no keyring imported is not an error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading keyring: %w", err)
+	}
+
+	nonceSize := s.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, errors.New("stored keyring is truncated")
+	}
+	plain, err := s.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return nil, ErrKeyringSecret
+	}
+
+	var k Keyring
+	if err := json.Unmarshal(plain, &k); err != nil {
+		return nil, fmt.Errorf("decoding keyring: %w", err)
+	}
+	return &k, nil
+}
+
+// LoadSequences implements SequenceStore.
+func (s *KeyringStore) LoadSequences(ctx context.Context) (map[string]uint64, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT individual_address, sequence_number FROM knx_secure_sequences`)
+	if err != nil {
+		return nil, fmt.Errorf("reading sequence numbers: %w", err)
+	}
+	defer rows.Close()
+
+	seqs := make(map[string]uint64)
+	for rows.Next() {
+		var ia string
+		var seq int64
+		if err := rows.Scan(&ia, &seq); err != nil {
+			return nil, fmt.Errorf("scanning sequence number: %w", err)
+		}
+		seqs[ia] = uint64(seq) //nolint:gosec // 48-bit values stored as INTEGER
+	}
+	return seqs, rows.Err()
+}
+
+// SaveSequence implements SequenceStore. Stored numbers never decrease.
+func (s *KeyringStore) SaveSequence(ctx context.Context, individualAddress string, seq uint64) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO knx_secure_sequences (individual_address, sequence_number, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(individual_address) DO UPDATE SET
+			sequence_number = MAX(sequence_number, excluded.sequence_number),
+			updated_at = excluded.updated_at
+	`, individualAddress, int64(seq), time.Now().UTC().Format(time.RFC3339)) //nolint:gosec // 48-bit sequence number
+	if err != nil {
+		return fmt.Errorf("storing sequence number: %w", err)
+	}
+	return nil
+}
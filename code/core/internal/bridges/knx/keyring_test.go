@@ -0,0 +1,241 @@
+package knx
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	testKeyringPassword = "correct horse"
+	testKeyringCreated  = "2026-03-01T10:00:00"
+)
+
+var (
+	testGroupKey = []byte{
+		0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77,
+		0x88, 0x99, 0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF,
+	}
+	testBackboneKey = bytes.Repeat([]byte{0x42}, secureKeyLen)
+)
+
+// buildTestKeyring writes a signed keyring the way ETS does, with secrets
+// encrypted under the password.
+func buildTestKeyring(t *testing.T, password string) []byte {
+	t.Helper()
+	hash, err := hashKeyringPassword(password)
+	if err != nil {
+		t.Fatalf("hashKeyringPassword() error: %v", err)
+	}
+	ivHash := sha256.Sum256([]byte(testKeyringCreated))
+	block, err := aes.NewCipher(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypt := func(plain []byte) string {
+		out := make([]byte, len(plain))
+		cipher.NewCBCEncrypter(block, ivHash[:aes.BlockSize]).CryptBlocks(out, plain)
+		return base64.StdEncoding.EncodeToString(out)
+	}
+	// 8-byte prefix, password, padding with its length in the last byte
+	tunnelPassword := append(bytes.Repeat([]byte{0xA5}, keyringPasswordPrefix), "tunnel-pw"...)
+	pad := aes.BlockSize - len(tunnelPassword)%aes.BlockSize
+	tunnelPassword = append(tunnelPassword, bytes.Repeat([]byte{byte(pad)}, pad)...)
+
+	body := fmt.Sprintf(`<Keyring xmlns="http://knx.org/xml/keyring/1" Project="Test House" CreatedBy="ETS 6" Created="%s">
+  <Backbone MulticastAddress="224.0.23.12" Latency="1000" Key="%s" />
+  <Interface Type="Tunneling" Host="1.1.0" IndividualAddress="1.1.251" UserID="2" Password="%s" />
+  <GroupAddresses>
+    <Group Address="2049" Key="%s" />
+    <Group Address="1/2/3" Key="%s" />
+  </GroupAddresses>
+  <Devices>
+    <Device IndividualAddress="1.1.10" SequenceNumber="500" />
+    <Device IndividualAddress="1.1.11" />
+  </Devices>
+</Keyring>`, testKeyringCreated, encrypt(testBackboneKey), encrypt(tunnelPassword), encrypt(testGroupKey), encrypt(testGroupKey))
+
+	root, err := parseXMLTree([]byte(body))
+	if err != nil {
+		t.Fatalf("parseXMLTree() error: %v", err)
+	}
+	signature := base64.StdEncoding.EncodeToString(keyringSignature(root, hash))
+	return []byte(strings.Replace(body, `Project=`, `Signature="`+signature+`" Project=`, 1))
+}
+
+func TestParseKeyring(t *testing.T) {
+	k, err := ParseKeyring(buildTestKeyring(t, testKeyringPassword), testKeyringPassword)
+	if err != nil {
+		t.Fatalf("ParseKeyring() error: %v", err)
+	}
+
+	if k.Project != "Test House" || k.CreatedBy != "ETS 6" || k.Created != testKeyringCreated {
+		t.Errorf("metadata = %q %q %q", k.Project, k.CreatedBy, k.Created)
+	}
+	// 2049 is the raw form of 1/0/1
+	for _, ga := range []string{"1/0/1", "1/2/3"} {
+		if !bytes.Equal(k.GroupKeys[ga], testGroupKey) {
+			t.Errorf("GroupKeys[%s] = % X, want % X", ga, k.GroupKeys[ga], testGroupKey)
+		}
+	}
+	if k.Backbone == nil || k.Backbone.Latency != 1000 || !bytes.Equal(k.Backbone.Key, testBackboneKey) {
+		t.Errorf("Backbone = %+v", k.Backbone)
+	}
+	if len(k.Interfaces) != 1 || k.Interfaces[0].Password != "tunnel-pw" || k.Interfaces[0].UserID != 2 {
+		t.Errorf("Interfaces = %+v", k.Interfaces)
+	}
+	if len(k.Devices) != 2 || k.Devices[0].SequenceNumber != 500 || k.Devices[1].SequenceNumber != 0 {
+		t.Errorf("Devices = %+v", k.Devices)
+	}
+
+	s := k.Summary()
+	if strings.Join(s.GroupAddresses, ",") != "1/0/1,1/2/3" || s.Devices != 2 || !s.Backbone {
+		t.Errorf("Summary() = %+v", s)
+	}
+}
+
+func TestParseKeyringErrors(t *testing.T) {
+	valid := buildTestKeyring(t, testKeyringPassword)
+
+	if _, err := ParseKeyring(valid, "wrong"); !errors.Is(err, ErrKeyringPassword) {
+		t.Errorf("wrong password error = %v, want ErrKeyringPassword", err)
+	}
+
+	// Changing any attribute breaks the signature
+	modified := bytes.Replace(valid, []byte(`SequenceNumber="500"`), []byte(`SequenceNumber="1"`), 1)
+	if _, err := ParseKeyring(modified, testKeyringPassword); !errors.Is(err, ErrKeyringPassword) {
+		t.Errorf("modified file error = %v, want ErrKeyringPassword", err)
+	}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not xml", "not a keyring"},
+		{"wrong root", `<Project Signature="AAAA"/>`},
+		{"no signature", `<Keyring Project="x"/>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseKeyring([]byte(tt.data), testKeyringPassword); !errors.Is(err, ErrInvalidKeyring) {
+				t.Errorf("ParseKeyring() error = %v, want ErrInvalidKeyring", err)
+			}
+		})
+	}
+}
+
+func newTestKeyringStore(t *testing.T, secret string) (*KeyringStore, *sql.DB) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`
+		CREATE TABLE knx_secure_keyring (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			project TEXT NOT NULL,
+			created TEXT NOT NULL,
+			keyring BLOB NOT NULL,
+			imported_at TEXT NOT NULL
+		) STRICT;
+		CREATE TABLE knx_secure_sequences (
+			individual_address TEXT PRIMARY KEY,
+			sequence_number INTEGER NOT NULL,
+			updated_at TEXT NOT NULL
+		) STRICT;
+	`)
+	if err != nil {
+		t.Fatalf("failed to create tables: %v", err)
+	}
+
+	store, err := NewKeyringStore(db, secret)
+	if err != nil {
+		t.Fatalf("NewKeyringStore() error: %v", err)
+	}
+	return store, db
+}
+
+func TestKeyringStore(t *testing.T) {
+	ctx := context.Background()
+	store, db := newTestKeyringStore(t, "a-site-secret-of-at-least-32-characters")
+
+	if k, err := store.LoadKeyring(ctx); k != nil || err != nil {
+		t.Fatalf("LoadKeyring() before import = %v, %v; want nil, nil", k, err)
+	}
+
+	k, err := ParseKeyring(buildTestKeyring(t, testKeyringPassword), testKeyringPassword)
+	if err != nil {
+		t.Fatalf("ParseKeyring() error: %v", err)
+	}
+	if err := store.SaveKeyring(ctx, k); err != nil {
+		t.Fatalf("SaveKeyring() error: %v", err)
+	}
+	if k.ImportedAt.IsZero() {
+		t.Error("SaveKeyring() did not set ImportedAt")
+	}
+
+	// Keys are not stored in the clear
+	var sealed []byte
+	if err := db.QueryRow(`SELECT keyring FROM knx_secure_keyring`).Scan(&sealed); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(sealed, []byte("Test House")) || bytes.Contains(sealed, testGroupKey) {
+		t.Error("stored keyring is not encrypted")
+	}
+
+	loaded, err := store.LoadKeyring(ctx)
+	if err != nil {
+		t.Fatalf("LoadKeyring() error: %v", err)
+	}
+	if loaded.Project != "Test House" || !bytes.Equal(loaded.GroupKeys["1/2/3"], testGroupKey) || !loaded.ImportedAt.Equal(k.ImportedAt) {
+		t.Errorf("LoadKeyring() = %+v", loaded)
+	}
+
+	// A different secret cannot read it
+	other, err := NewKeyringStore(db, "another-site-secret-of-32-characters!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.LoadKeyring(ctx); !errors.Is(err, ErrKeyringSecret) {
+		t.Errorf("LoadKeyring() with a different secret error = %v, want ErrKeyringSecret", err)
+	}
+
+	if _, err := NewKeyringStore(db, ""); err == nil {
+		t.Error("NewKeyringStore() with empty secret succeeded")
+	}
+}
+
+func TestKeyringStoreSequences(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestKeyringStore(t, "a-site-secret-of-at-least-32-characters")
+
+	for _, seq := range []uint64{10, 12, 11} {
+		if err := store.SaveSequence(ctx, "1.1.10", seq); err != nil {
+			t.Fatalf("SaveSequence() error: %v", err)
+		}
+	}
+	if err := store.SaveSequence(ctx, "1.1.250", 1<<40); err != nil {
+		t.Fatalf("SaveSequence() error: %v", err)
+	}
+
+	seqs, err := store.LoadSequences(ctx)
+	if err != nil {
+		t.Fatalf("LoadSequences() error: %v", err)
+	}
+	// Stored numbers never go backwards
+	if seqs["1.1.10"] != 12 || seqs["1.1.250"] != 1<<40 || len(seqs) != 2 {
+		t.Errorf("LoadSequences() = %v", seqs)
+	}
+}
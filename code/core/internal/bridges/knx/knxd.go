@@ -108,6 +108,7 @@ type Logger interface {
 type Connector interface {
 	Send(ctx context.Context, ga GroupAddress, data []byte) error
 	SendRead(ctx context.Context, ga GroupAddress) error
+	SendResponse(ctx context.Context, ga GroupAddress, data []byte) error
 	SetOnTelegram(callback func(Telegram))
 	IsConnected() bool
 	Stats() KNXDStats
@@ -783,6 +784,24 @@ func (c *KNXDClient) SendRead(ctx context.Context, ga GroupAddress) error {
 	return c.sendTelegram(ctx, telegram)
 }
 
+// SendResponse answers a group read request on the KNX bus.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - ga: Group address that was read
+//   - data: DPT-encoded value
+//
+// Returns:
+//   - error: If sending fails or client is not connected
+func (c *KNXDClient) SendResponse(ctx context.Context, ga GroupAddress, data []byte) error {
+	if !c.IsConnected() {
+		return ErrNotConnected
+	}
+
+	telegram := NewResponseTelegram(ga, data)
+	return c.sendTelegram(ctx, telegram)
+}
+
 // sendTelegram sends a telegram to knxd.
 func (c *KNXDClient) sendTelegram(ctx context.Context, t Telegram) error {
 	// Check context
@@ -0,0 +1,274 @@
+package knx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// KNXnet/IP frame constants (KNX Standard 03.08.02 Core, 03.08.04 Tunnelling).
+const (
+	// knxnetipHeaderLen is the length of the KNXnet/IP frame header.
+	knxnetipHeaderLen = 6
+
+	// knxnetipVersion is protocol version 1.0.
+	knxnetipVersion = 0x10
+
+	// knxnetipDefaultPort is the registered KNXnet/IP UDP port.
+	knxnetipDefaultPort = 3671
+
+	// hpaiLen is the length of a Host Protocol Address Information block.
+	hpaiLen = 8
+
+	// hpaiUDP is the IPv4 UDP host protocol code.
+	hpaiUDP = 0x01
+
+	// connHeaderLen is the length of a tunnelling connection header.
+	connHeaderLen = 4
+
+	// knxnetipBufferSize is the receive buffer for one KNXnet/IP datagram.
+	knxnetipBufferSize = 512
+)
+
+// KNXnet/IP service types.
+const (
+	serviceConnectRequest          uint16 = 0x0205
+	serviceConnectResponse         uint16 = 0x0206
+	serviceConnectionStateRequest  uint16 = 0x0207
+	serviceConnectionStateResponse uint16 = 0x0208
+	serviceDisconnectRequest       uint16 = 0x0209
+	serviceDisconnectResponse      uint16 = 0x020A
+	serviceTunnellingRequest       uint16 = 0x0420
+	serviceTunnellingAck           uint16 = 0x0421
+	serviceRoutingIndication       uint16 = 0x0530
+	serviceRoutingLostMessage      uint16 = 0x0531
+	serviceRoutingBusy             uint16 = 0x0532
+)
+
+// KNXnet/IP connection constants.
+const (
+	// connTypeTunnel is the TUNNEL_CONNECTION connection type.
+	connTypeTunnel = 0x04
+
+	// tunnelLinkLayer is the TUNNEL_LINKLAYER KNX layer.
+	tunnelLinkLayer = 0x02
+
+	// criTunnelLen is the length of a tunnelling connection request info block.
+	criTunnelLen = 4
+
+	// crdTunnelLen is the length of a tunnelling connection response data block.
+	crdTunnelLen = 4
+
+	// KNXnet/IP status codes.
+	statusNoError           = 0x00 // E_NO_ERROR
+	statusConnectionID      = 0x21 // E_CONNECTION_ID
+	statusConnectionType    = 0x22 // E_CONNECTION_TYPE
+	statusConnectionOption  = 0x23 // E_CONNECTION_OPTION
+	statusNoMoreConnections = 0x24 // E_NO_MORE_CONNECTIONS
+	statusDataConnection    = 0x26 // E_DATA_CONNECTION
+	statusKNXConnection     = 0x27 // E_KNX_CONNECTION
+	statusTunnellingLayer   = 0x29 // E_TUNNELLING_LAYER
+)
+
+// cEMI (common External Message Interface) constants.
+const (
+	// cemiLDataReq asks the gateway to send a frame on the bus.
+	cemiLDataReq = 0x11
+
+	// cemiLDataCon confirms a frame was sent on the bus.
+	cemiLDataCon = 0x2E
+
+	// cemiLDataInd is a frame received from the bus.
+	cemiLDataInd = 0x29
+
+	// cemiCtrl1 is a standard frame, not repeated, broadcast, low priority.
+	cemiCtrl1 = 0xBC
+
+	// cemiRepeatFlag in control field 1 is cleared on a repeated frame.
+	cemiRepeatFlag = 0x20
+
+	// cemiCtrl2Group is a group destination address with hop count 6.
+	cemiCtrl2Group = 0xE0
+
+	// cemiGroupFlag marks a group destination address in control field 2.
+	cemiGroupFlag = 0x80
+
+	// cemiMinLen is message code + additional info length + ctrl1 + ctrl2
+	// + source(2) + destination(2) + length + TPCI + APCI.
+	cemiMinLen = 11
+)
+
+// encodeKNXnetIP wraps a body in a KNXnet/IP frame header.
+func encodeKNXnetIP(service uint16, body []byte) []byte {
+	frame := make([]byte, knxnetipHeaderLen+len(body))
+	frame[0] = knxnetipHeaderLen
+	frame[1] = knxnetipVersion
+	binary.BigEndian.PutUint16(frame[2:4], service)
+	binary.BigEndian.PutUint16(frame[4:6], uint16(len(frame))) //nolint:gosec // bounded by datagram size
+	copy(frame[knxnetipHeaderLen:], body)
+	return frame
+}
+
+// parseKNXnetIP validates a KNXnet/IP frame header and returns the service
+// type and body.
+func parseKNXnetIP(frame []byte) (uint16, []byte, error) {
+	if len(frame) < knxnetipHeaderLen {
+		return 0, nil, fmt.Errorf("%w: KNXnet/IP frame too short (%d bytes)", ErrInvalidTelegram, len(frame))
+	}
+	if frame[0] != knxnetipHeaderLen || frame[1] != knxnetipVersion {
+		return 0, nil, fmt.Errorf("%w: unsupported KNXnet/IP header % X", ErrInvalidTelegram, frame[:2])
+	}
+	total := int(binary.BigEndian.Uint16(frame[4:6]))
+	if total < knxnetipHeaderLen || total > len(frame) {
+		return 0, nil, fmt.Errorf("%w: KNXnet/IP length %d, got %d bytes", ErrInvalidTelegram, total, len(frame))
+	}
+	return binary.BigEndian.Uint16(frame[2:4]), frame[knxnetipHeaderLen:total], nil
+}
+
+// encodeHPAI encodes an IPv4 UDP endpoint. A nil address encodes 0.0.0.0:0,
+// which asks the gateway to reply to the datagram's source (NAT mode).
+func encodeHPAI(addr *net.UDPAddr) []byte {
+	hpai := make([]byte, hpaiLen)
+	hpai[0] = hpaiLen
+	hpai[1] = hpaiUDP
+	if addr != nil {
+		if ip4 := addr.IP.To4(); ip4 != nil {
+			copy(hpai[2:6], ip4)
+			binary.BigEndian.PutUint16(hpai[6:8], uint16(addr.Port)) //nolint:gosec // port is 0-65535
+		}
+	}
+	return hpai
+}
+
+// parseHPAI decodes an IPv4 UDP endpoint.
+func parseHPAI(b []byte) (*net.UDPAddr, error) {
+	if len(b) < hpaiLen || b[0] != hpaiLen {
+		return nil, fmt.Errorf("%w: invalid HPAI", ErrInvalidTelegram)
+	}
+	return &net.UDPAddr{
+		IP:   net.IPv4(b[2], b[3], b[4], b[5]),
+		Port: int(binary.BigEndian.Uint16(b[6:8])),
+	}, nil
+}
+
+// encodeCEMI encodes a group telegram as a cEMI L_Data frame. The source
+// address is left as 0.0.0 for the gateway to fill in.
+func encodeCEMI(code byte, t Telegram) []byte {
+	groupPacket := t.Encode() // GA(2) + APDU
+	apdu := groupPacket[2:]
+
+	frame := make([]byte, 0, 8+len(apdu)) //nolint:mnd // cEMI header before APDU
+	frame = append(frame, code, 0x00, cemiCtrl1, cemiCtrl2Group, 0x00, 0x00)
+	frame = append(frame, groupPacket[0:2]...)
+	frame = append(frame, byte(len(apdu)-1)) //nolint:gosec // APDU is at most 16 bytes
+	return append(frame, apdu...)
+}
+
+// parseCEMI decodes a cEMI L_Data frame. isGroup is false for frames sent
+// to an individual address, which the bridge ignores.
+func parseCEMI(frame []byte) (code byte, t Telegram, isGroup bool, err error) {
+	if len(frame) < 2 { //nolint:mnd // message code + additional info length
+		return 0, Telegram{}, false, fmt.Errorf("%w: cEMI frame too short", ErrInvalidTelegram)
+	}
+	code = frame[0]
+	// Skip additional information
+	frame = frame[2+int(frame[1]):]
+	if len(frame) < cemiMinLen-2 {
+		return code, Telegram{}, false, fmt.Errorf("%w: cEMI frame too short", ErrInvalidTelegram)
+	}
+
+	ctrl2 := frame[1]
+	if ctrl2&cemiGroupFlag == 0 {
+		return code, Telegram{}, false, nil
+	}
+
+	// Reassemble the knxd GROUPCON receive layout: src(2) + GA(2) + APDU
+	npduLen := int(frame[6])
+	apdu := frame[7:]
+	if len(apdu) < npduLen+1 {
+		return code, Telegram{}, true, fmt.Errorf("%w: cEMI length %d, got %d APDU bytes", ErrInvalidTelegram, npduLen, len(apdu))
+	}
+	packet := make([]byte, 0, 4+npduLen+1) //nolint:mnd // src(2) + GA(2)
+	packet = append(packet, frame[2:6]...)
+	packet = append(packet, apdu[:npduLen+1]...)
+
+	t, err = ParseTelegram(packet)
+	return code, t, true, err
+}
+
+// telegramDispatcher delivers received telegrams to the callback through a
+// bounded worker pool, dropping telegrams when the queue is full. This is
+// the same scheme KNXDClient uses, shared by the KNXnet/IP connectors.
+type telegramDispatcher struct {
+	mu       sync.RWMutex
+	callback func(Telegram)
+	queue    chan Telegram
+	dropped  atomic.Uint64
+}
+
+func newTelegramDispatcher() *telegramDispatcher {
+	return &telegramDispatcher{queue: make(chan Telegram, callbackQueueSize)}
+}
+
+// setCallback sets the function called for each received telegram.
+func (d *telegramDispatcher) setCallback(callback func(Telegram)) {
+	d.mu.Lock()
+	d.callback = callback
+	d.mu.Unlock()
+}
+
+// dispatch queues a telegram for the callback. Returns false if it was
+// dropped because the queue is full.
+func (d *telegramDispatcher) dispatch(t Telegram) bool {
+	d.mu.RLock()
+	hasCallback := d.callback != nil
+	d.mu.RUnlock()
+	if !hasCallback {
+		return true
+	}
+
+	select {
+	case d.queue <- t:
+		return true
+	default:
+		d.dropped.Add(1)
+		return false
+	}
+}
+
+// start launches the callback workers. They exit when done is closed.
+func (d *telegramDispatcher) start(done <-chan struct{}, wg *sync.WaitGroup, onPanic func(error)) {
+	for range callbackWorkerCount {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				case t := <-d.queue:
+					d.deliver(t, onPanic)
+				}
+			}
+		}()
+	}
+}
+
+// deliver calls the callback, recovering from panics.
+func (d *telegramDispatcher) deliver(t Telegram, onPanic func(error)) {
+	d.mu.RLock()
+	callback := d.callback
+	d.mu.RUnlock()
+	if callback == nil {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			onPanic(fmt.Errorf("telegram callback panic: %v", r))
+		}
+	}()
+	callback(t)
+}
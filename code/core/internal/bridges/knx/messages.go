@@ -125,8 +125,31 @@ type StateMessage struct {
 
 	// Address is the protocol-specific address (e.g., "1/2/3").
 	Address string `json:"address"`
+
+	// Source says what caused the change (see StateSource values).
+	// Core latches a manual override on "physical" changes.
+	Source StateSource `json:"source,omitempty"`
+
+	// Units gives the unit of numeric state fields whose DPT has one,
+	// keyed like State (e.g. {"power": "W"}).
+	Units map[string]string `json:"units,omitempty"`
 }
 
+// StateSource identifies what caused a state change.
+type StateSource string
+
+const (
+	// StateSourceCommand is a write-through for a command from Core.
+	StateSourceCommand StateSource = "command"
+
+	// StateSourcePhysical is a group write to a device's command address
+	// from the bus (wall switch, keypad, push button).
+	StateSourcePhysical StateSource = "physical"
+
+	// StateSourceFeedback is status feedback or a read response from the bus.
+	StateSourceFeedback StateSource = "feedback"
+)
+
 // HealthStatus represents the operational status of the bridge.
 type HealthStatus string
 
@@ -0,0 +1,258 @@
+package knx
+
+import (
+	"cmp"
+	"context"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Polling defaults and limits.
+const (
+	// MinPollInterval is the shortest poll interval (seconds). Shorter
+	// intervals from device configs are raised to it.
+	MinPollInterval = 10
+
+	// DefaultPollMaxFailures is the default number of unanswered reads
+	// after which a group address is no longer polled.
+	DefaultPollMaxFailures = 3
+
+	// pollJitter is the random spread applied to each poll interval.
+	pollJitter = 0.1
+)
+
+// pollTarget is a polled group address. A group address mapped by several
+// device functions is polled once, at the shortest interval.
+type pollTarget struct {
+	ga       GroupAddress
+	interval time.Duration
+	devices  []string
+	next     time.Time // When the next read is due
+	sentAt   time.Time // When the pending read was sent (zero once answered)
+	failures int       // Consecutive unanswered reads
+	stalled  bool      // Stopped after too many unanswered reads
+}
+
+// poller sends periodic read requests for group addresses whose devices do
+// not transmit on change. A read that is not answered by the next poll
+// counts as a failure; after maxFailures the group address is no longer
+// polled and its devices are reported unhealthy, until a value for it is
+// seen on the bus again.
+//
+// Thread Safety: All methods are safe for concurrent use.
+type poller struct {
+	mu          sync.Mutex
+	targets     map[string]*pollTarget // By group address
+	maxFailures int
+	now         func() time.Time
+	wake        chan struct{}
+
+	send     func(ctx context.Context, ga GroupAddress) error
+	onHealth func(deviceIDs []string) // Devices whose poll health changed
+}
+
+// newPoller creates a poller that reads group addresses with send.
+func newPoller(maxFailures int, send func(ctx context.Context, ga GroupAddress) error, onHealth func([]string)) *poller {
+	return &poller{
+		targets:     make(map[string]*pollTarget),
+		maxFailures: cmp.Or(maxFailures, DefaultPollMaxFailures),
+		now:         time.Now,
+		wake:        make(chan struct{}, 1),
+		send:        send,
+		onHealth:    onHealth,
+	}
+}
+
+// setTargets replaces the polled group addresses. Group addresses polled
+// before keep their schedule and failure count; new ones start at a random
+// point within their interval so polls spread out.
+//
+// Parameters:
+//   - intervals: Poll interval by group address
+//   - devices: Device IDs by group address
+func (p *poller) setTargets(intervals map[string]time.Duration, devices map[string][]string) {
+	p.mu.Lock()
+	now := p.now()
+	targets := make(map[string]*pollTarget, len(intervals))
+	for gaStr, interval := range intervals {
+		ga, err := ParseGroupAddress(gaStr)
+		if err != nil {
+			continue
+		}
+		t, ok := p.targets[gaStr]
+		if !ok || t.interval != interval {
+			t = &pollTarget{ga: ga, interval: interval, next: now.Add(randomDuration(interval))}
+		}
+		t.devices = devices[gaStr]
+		targets[gaStr] = t
+	}
+	p.targets = targets
+	p.mu.Unlock()
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// answered marks a group address as answering: a value for it was seen on
+// the bus. A stalled group address is polled again.
+func (p *poller) answered(gaStr string) {
+	p.mu.Lock()
+	t, ok := p.targets[gaStr]
+	if !ok {
+		p.mu.Unlock()
+		return
+	}
+	t.sentAt = time.Time{}
+	t.failures = 0
+	resumed := t.stalled
+	if resumed {
+		t.stalled = false
+		t.next = p.now().Add(jittered(t.interval))
+	}
+	devices := t.devices
+	p.mu.Unlock()
+
+	if resumed && p.onHealth != nil {
+		p.onHealth(devices)
+	}
+}
+
+// deviceHealth returns the device's health as far as polling knows:
+// "offline" if every polled group address of the device has stopped
+// answering, "degraded" if some have, and "" otherwise.
+func (p *poller) deviceHealth(deviceID string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	polled, stalled := 0, 0
+	for _, t := range p.targets {
+		if !slices.Contains(t.devices, deviceID) {
+			continue
+		}
+		polled++
+		if t.stalled {
+			stalled++
+		}
+	}
+	switch {
+	case stalled == 0:
+		return ""
+	case stalled == polled:
+		return "offline"
+	default:
+		return "degraded"
+	}
+}
+
+// due returns the group addresses to read now and schedules their next
+// poll. Reads still unanswered count as failures; a group address reaching
+// maxFailures is stalled and its devices returned in stalled.
+func (p *poller) due() (reads []GroupAddress, stalled []string, wait time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	wait = time.Hour
+	for _, t := range p.targets {
+		if t.stalled {
+			continue
+		}
+		if t.next.After(now) {
+			wait = min(wait, t.next.Sub(now))
+			continue
+		}
+		if !t.sentAt.IsZero() {
+			t.failures++
+			if t.failures >= p.maxFailures {
+				t.stalled = true
+				stalled = append(stalled, t.devices...)
+				continue
+			}
+		}
+		t.sentAt = now
+		t.next = now.Add(jittered(t.interval))
+		wait = min(wait, t.next.Sub(now))
+		reads = append(reads, t.ga)
+	}
+	return reads, stalled, wait
+}
+
+// sendFailed forgets a read that could not be sent, so it does not count
+// as unanswered.
+func (p *poller) sendFailed(ga GroupAddress) {
+	p.mu.Lock()
+	if t, ok := p.targets[ga.String()]; ok {
+		t.sentAt = time.Time{}
+	}
+	p.mu.Unlock()
+}
+
+// run polls until ctx is cancelled. Reads go out at background priority.
+func (p *poller) run(ctx context.Context) {
+	readCtx := WithPriority(ctx, PriorityBackground)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-p.wake:
+		}
+
+		reads, stalled, wait := p.due()
+		if len(stalled) > 0 && p.onHealth != nil {
+			p.onHealth(stalled)
+		}
+		for _, ga := range reads {
+			sendCtx, cancel := context.WithTimeout(readCtx, commandTimeout)
+			if err := p.send(sendCtx, ga); err != nil {
+				p.sendFailed(ga)
+			}
+			cancel()
+		}
+
+		timer.Reset(wait)
+	}
+}
+
+// pollInterval returns how often a function is polled, or 0 if it is not.
+// The function's own interval wins over the default for its DPT (full DPT
+// first, then main number).
+func pollInterval(addr AddressConfig, defaults map[string]int) time.Duration {
+	if !addr.HasFlag("read") {
+		return 0
+	}
+	seconds := addr.PollInterval
+	if seconds <= 0 {
+		seconds = defaults[addr.DPT]
+	}
+	if seconds <= 0 {
+		main, _, _ := strings.Cut(addr.DPT, ".")
+		seconds = defaults[main]
+	}
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(max(seconds, MinPollInterval)) * time.Second
+}
+
+// randomDuration returns a random duration in [0, d).
+func randomDuration(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(d))) //nolint:gosec // jitter, not security
+}
+
+// jittered returns d spread randomly by ±pollJitter.
+func jittered(d time.Duration) time.Duration {
+	spread := time.Duration(float64(d) * pollJitter)
+	return d - spread + randomDuration(2*spread)
+}
@@ -0,0 +1,143 @@
+package knx
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPollInterval(t *testing.T) {
+	defaults := map[string]int{"13": 900, "9.001": 300, "9": 600}
+	tests := []struct {
+		name string
+		addr AddressConfig
+		want time.Duration
+	}{
+		{"own interval", AddressConfig{DPT: "13.010", Flags: []string{"read"}, PollInterval: 60}, time.Minute},
+		{"main DPT default", AddressConfig{DPT: "13.010", Flags: []string{"read"}}, 15 * time.Minute},
+		{"full DPT default first", AddressConfig{DPT: "9.001", Flags: []string{"read"}}, 5 * time.Minute},
+		{"raised to minimum", AddressConfig{DPT: "1.001", Flags: []string{"read"}, PollInterval: 1}, MinPollInterval * time.Second},
+		{"no default", AddressConfig{DPT: "1.001", Flags: []string{"read"}}, 0},
+		{"not readable", AddressConfig{DPT: "13.010", Flags: []string{"transmit"}, PollInterval: 60}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pollInterval(tt.addr, defaults); got != tt.want {
+				t.Errorf("pollInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPollerStallsUnansweredGA(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	var changed []string
+	p := newPoller(2, nil, func(ids []string) { changed = append(changed, ids...) })
+	p.now = func() time.Time { return now }
+	p.setTargets(
+		map[string]time.Duration{"6/0/1": time.Minute, "6/0/2": time.Minute},
+		map[string][]string{"6/0/1": {"meter"}, "6/0/2": {"meter"}},
+	)
+
+	// Each GA is first polled within one interval, then about once per interval
+	poll := func() []GroupAddress {
+		now = now.Add(2 * time.Minute)
+		reads, stalled, _ := p.due()
+		changed = append(changed, stalled...)
+		return reads
+	}
+	if reads := poll(); len(reads) != 2 {
+		t.Fatalf("first poll read %v, want both GAs", reads)
+	}
+
+	// 6/0/2 answers, 6/0/1 does not: its second unanswered read stalls it
+	p.answered("6/0/2")
+	if reads := poll(); len(reads) != 2 {
+		t.Fatalf("second poll read %v, want both GAs", reads)
+	}
+	p.answered("6/0/2")
+	reads := poll()
+	if len(reads) != 1 || reads[0].String() != "6/0/2" {
+		t.Fatalf("third poll read %v, want only 6/0/2", reads)
+	}
+	if !slices.Equal(changed, []string{"meter"}) || p.deviceHealth("meter") != "degraded" {
+		t.Errorf("after stall: changed %v, health %q; want meter degraded", changed, p.deviceHealth("meter"))
+	}
+
+	// A value seen on the bus resumes polling
+	p.answered("6/0/1")
+	if p.deviceHealth("meter") != "" || len(changed) != 2 {
+		t.Errorf("after answer: changed %v, health %q; want meter healthy", changed, p.deviceHealth("meter"))
+	}
+	if reads := poll(); len(reads) != 2 {
+		t.Errorf("poll after resume read %v, want both GAs", reads)
+	}
+}
+
+func TestPollerSendFailureNotCounted(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	p := newPoller(1, nil, nil)
+	p.now = func() time.Time { return now }
+	p.setTargets(map[string]time.Duration{"6/0/1": time.Minute}, map[string][]string{"6/0/1": {"meter"}})
+
+	for range 3 {
+		now = now.Add(2 * time.Minute)
+		reads, _, _ := p.due()
+		if len(reads) != 1 {
+			t.Fatalf("poll read %v, want 6/0/1", reads)
+		}
+		p.sendFailed(reads[0])
+	}
+	if health := p.deviceHealth("meter"); health != "" {
+		t.Errorf("health = %q after failed sends, want healthy", health)
+	}
+}
+
+func TestBridgePolling(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Polling = PollingSettings{Defaults: map[string]int{"13": 900}, MaxFailures: 1}
+	registry := &fakeRegistry{devices: []RegistryDevice{{
+		ID:   "meter",
+		Type: "energy_meter",
+		Functions: map[string]FunctionMapping{
+			"energy":  {GA: "6/0/1", DPT: "13.010", Flags: []string{"read"}},
+			"power":   {GA: "6/0/2", DPT: "14.056", Flags: []string{"read"}, PollInterval: 30},
+			"voltage": {GA: "6/0/3", DPT: "14.027", Flags: []string{"read"}},
+		},
+	}}}
+	knxd := NewMockConnector()
+	b, err := NewBridge(BridgeOptions{Config: cfg, MQTTClient: NewMockMQTTClient(), KNXDClient: knxd, Registry: registry})
+	if err != nil {
+		t.Fatalf("NewBridge() error: %v", err)
+	}
+	b.loadDevicesFromRegistry(context.Background())
+
+	b.poller.mu.Lock()
+	energy, power := b.poller.targets["6/0/1"], b.poller.targets["6/0/2"]
+	n := len(b.poller.targets)
+	b.poller.mu.Unlock()
+	if n != 2 || energy == nil || energy.interval != 15*time.Minute || power == nil || power.interval != 30*time.Second {
+		t.Fatalf("poll targets: %d, energy %+v, power %+v", n, energy, power)
+	}
+
+	// Make both due, as if their reads went unanswered
+	b.poller.mu.Lock()
+	for _, target := range b.poller.targets {
+		target.next = time.Time{}
+		target.sentAt = time.Now().Add(-time.Hour)
+	}
+	b.poller.mu.Unlock()
+	_, stalled, _ := b.poller.due()
+	b.updatePollHealth(stalled)
+	if health := registry.getHealth("meter"); health != "offline" {
+		t.Errorf("health = %q, want offline", health)
+	}
+
+	if err := b.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer b.Stop()
+	knxd.SimulateTelegram(Telegram{Destination: GroupAddress{Main: 6, Middle: 0, Sub: 2}, APCI: APCIResponse, Data: []byte{0, 0, 0, 0, 0}})
+	waitFor(t, "meter degraded", func() bool { return registry.getHealth("meter") == "degraded" })
+}
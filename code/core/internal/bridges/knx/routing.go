@@ -0,0 +1,540 @@
+package knx
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// KNXnet/IP routing constants (KNX Standard 03.08.05 Routing).
+const (
+	// DefaultRoutingMulticast is the KNXnet/IP system setup multicast group.
+	DefaultRoutingMulticast = "224.0.23.12"
+
+	// DefaultRoutingAddress is the individual address used as the source of
+	// sent frames when none is configured.
+	DefaultRoutingAddress = "15.15.250"
+
+	// routingMinInterval paces sending to the 50 indications per second a
+	// routing device may send.
+	routingMinInterval = 20 * time.Millisecond
+
+	// routingBusyMaxWait caps the wait time requested by ROUTING_BUSY.
+	routingBusyMaxWait = time.Second
+
+	// routingBusyBurst is the window in which further ROUTING_BUSY frames
+	// count as the same event.
+	routingBusyBurst = 10 * time.Millisecond
+
+	// routingBusySlowdown is the random extra wait per counted busy event.
+	routingBusySlowdown = 50 * time.Millisecond
+
+	// routingBusyDecay is how often the busy count drops by one once the
+	// wait has elapsed.
+	routingBusyDecay = 5 * time.Millisecond
+
+	// routingDuplicateWindow is how long a received frame is remembered to
+	// drop its repetitions (frames with the repeat flag cleared).
+	routingDuplicateWindow = 500 * time.Millisecond
+
+	// routingBusyLen is the length of a ROUTING_BUSY body.
+	routingBusyLen = 6
+
+	// routingLostLen is the length of a ROUTING_LOST_MESSAGE body.
+	routingLostLen = 4
+)
+
+// RoutingConfig holds KNXnet/IP routing connection configuration.
+type RoutingConfig struct {
+	// MulticastAddress is the routing multicast group.
+	// Default: "224.0.23.12"
+	MulticastAddress string
+
+	// Interface is the network interface to join the group on.
+	// If empty, the system default multicast interface is used.
+	Interface string
+
+	// IndividualAddress is the source address of sent frames, "A.L.D".
+	// It must be unique on the IP backbone.
+	// Default: "15.15.250"
+	IndividualAddress string
+}
+
+// Ensure RoutingClient implements Connector.
+var _ Connector = (*RoutingClient)(nil)
+
+// RoutingClient sends and receives group telegrams as KNXnet/IP routing
+// indications on a multicast group, without knxd.
+//
+// Thread Safety:
+//   - All methods are safe for concurrent use.
+//   - Telegram callbacks are invoked from a bounded worker pool.
+//
+// Flow Control:
+//   - Sending is paced to at most 50 indications per second.
+//   - ROUTING_BUSY from a router pauses sending for the requested wait time
+//     plus a random slowdown that grows with repeated busy events.
+//   - Copies of a received frame within 500ms are dropped, as are frames
+//     carrying this client's own source address.
+//
+// Routing is connectionless, so there is nothing to reconnect: the client
+// is connected from ConnectRouting until Close.
+type RoutingClient struct {
+	cfg     RoutingConfig
+	conn    *net.UDPConn
+	group   *net.UDPAddr
+	address uint16 // Source individual address
+
+	// sendMu serialises sending so pacing and busy waits apply in order
+	sendMu   sync.Mutex
+	lastSend time.Time
+
+	flow       routingFlow
+	duplicates duplicateFilter
+
+	dispatcher *telegramDispatcher
+
+	// Shutdown coordination
+	done *closeOnce
+	wg   sync.WaitGroup
+
+	// Logger (optional)
+	logger   Logger
+	loggerMu sync.RWMutex
+
+	// Statistics
+	telegramsTx  atomic.Uint64
+	telegramsRx  atomic.Uint64
+	errorsTotal  atomic.Uint64
+	busyTotal    atomic.Uint64
+	lastActivity atomic.Int64 // Unix timestamp
+}
+
+// ConnectRouting joins the KNXnet/IP routing multicast group.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - cfg: Routing configuration
+//
+// Returns:
+//   - *RoutingClient: Client ready for use
+//   - error: If the configuration is invalid or the group cannot be joined
+func ConnectRouting(ctx context.Context, cfg RoutingConfig) (*RoutingClient, error) {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrConnectionFailed, err)
+		}
+	}
+	applyRoutingDefaults(&cfg)
+
+	group, err := resolveRoutingGroup(cfg.MulticastAddress)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConnectionFailed, err)
+	}
+	if _, err := parseIndividualAddress(cfg.IndividualAddress); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConnectionFailed, err)
+	}
+
+	var ifi *net.Interface
+	if cfg.Interface != "" {
+		if ifi, err = net.InterfaceByName(cfg.Interface); err != nil {
+			return nil, fmt.Errorf("%w: interface %q: %w", ErrConnectionFailed, cfg.Interface, err)
+		}
+	}
+
+	conn, err := net.ListenMulticastUDP("udp4", ifi, group)
+	if err != nil {
+		return nil, fmt.Errorf("%w: join %s: %w", ErrConnectionFailed, group, err)
+	}
+
+	c, err := newRoutingClient(cfg, conn, group)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// newRoutingClient starts a routing client on an open socket. Indications
+// are sent to group; tests pass a loopback stand-in for the multicast group.
+func newRoutingClient(cfg RoutingConfig, conn *net.UDPConn, group *net.UDPAddr) (*RoutingClient, error) {
+	applyRoutingDefaults(&cfg)
+	address, err := parseIndividualAddress(cfg.IndividualAddress)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConnectionFailed, err)
+	}
+
+	c := &RoutingClient{
+		cfg:        cfg,
+		conn:       conn,
+		group:      group,
+		address:    address,
+		duplicates: duplicateFilter{window: routingDuplicateWindow, seen: make(map[string]time.Time)},
+		dispatcher: newTelegramDispatcher(),
+		done:       newCloseOnce(),
+	}
+	c.lastActivity.Store(time.Now().Unix())
+
+	c.wg.Add(1)
+	go c.receiveLoop()
+	c.dispatcher.start(c.done.Done(), &c.wg, func(err error) { c.logError("telegram callback failed", err) })
+	return c, nil
+}
+
+// applyRoutingDefaults fills in empty settings.
+func applyRoutingDefaults(cfg *RoutingConfig) {
+	if cfg.MulticastAddress == "" {
+		cfg.MulticastAddress = DefaultRoutingMulticast
+	}
+	if cfg.IndividualAddress == "" {
+		cfg.IndividualAddress = DefaultRoutingAddress
+	}
+}
+
+// resolveRoutingGroup returns the UDP address of a routing multicast group.
+func resolveRoutingGroup(multicast string) (*net.UDPAddr, error) {
+	ip := net.ParseIP(multicast)
+	if ip == nil || ip.To4() == nil || !ip.IsMulticast() {
+		return nil, fmt.Errorf("invalid multicast address %q", multicast)
+	}
+	return &net.UDPAddr{IP: ip, Port: knxnetipDefaultPort}, nil
+}
+
+// receiveLoop reads datagrams from the multicast group.
+func (c *RoutingClient) receiveLoop() {
+	defer c.wg.Done()
+
+	buf := make([]byte, knxnetipBufferSize)
+	for {
+		n, _, err := c.conn.ReadFromUDP(buf)
+		if err != nil {
+			if c.isClosed() {
+				return
+			}
+			c.errorsTotal.Add(1)
+			c.logError("routing read failed", err)
+			continue
+		}
+
+		service, body, err := parseKNXnetIP(buf[:n])
+		if err != nil {
+			c.errorsTotal.Add(1)
+			c.logError("invalid KNXnet/IP frame", err)
+			continue
+		}
+		c.handleFrame(service, body)
+	}
+}
+
+// handleFrame processes one received KNXnet/IP routing frame.
+func (c *RoutingClient) handleFrame(service uint16, body []byte) {
+	switch service {
+	case serviceRoutingIndication:
+		c.handleIndication(body)
+	case serviceRoutingBusy:
+		if len(body) < routingBusyLen {
+			return
+		}
+		wait := time.Duration(binary.BigEndian.Uint16(body[2:4])) * time.Millisecond
+		c.busyTotal.Add(1)
+		c.flow.busy(time.Now(), min(wait, routingBusyMaxWait))
+	case serviceRoutingLostMessage:
+		if len(body) < routingLostLen {
+			return
+		}
+		lost := binary.BigEndian.Uint16(body[2:4])
+		c.logError("router lost messages", fmt.Errorf("%d frames lost", lost))
+	}
+}
+
+// handleIndication dispatches a routing indication, dropping this client's
+// own frames and repetitions of recently received ones.
+func (c *RoutingClient) handleIndication(frame []byte) {
+	code, t, isGroup, err := parseCEMI(frame)
+	if err != nil {
+		c.errorsTotal.Add(1)
+		c.logError("invalid cEMI frame", err)
+		return
+	}
+	if code != cemiLDataInd || !isGroup {
+		return
+	}
+	if t.Source == formatIndividualAddress(c.address) {
+		return // Looped back from our own send
+	}
+	repeated := frame[2+int(frame[1])]&cemiRepeatFlag == 0
+	if c.duplicates.seenRecently(duplicateKey(frame), repeated, time.Now()) {
+		return
+	}
+
+	c.telegramsRx.Add(1)
+	c.lastActivity.Store(time.Now().Unix())
+	if !c.dispatcher.dispatch(t) {
+		c.errorsTotal.Add(1)
+		c.logError("callback queue full, dropping telegram", nil)
+	}
+}
+
+// duplicateKey identifies a cEMI frame independent of additional
+// information and the repeat flag, so a repetition matches the original.
+func duplicateKey(frame []byte) string {
+	ldata := append([]byte(nil), frame[2+int(frame[1]):]...)
+	ldata[0] |= cemiRepeatFlag
+	return string(ldata)
+}
+
+// Close leaves the multicast group and releases the socket.
+// Safe to call multiple times.
+//
+// Returns:
+//   - error: nil (closing is best-effort)
+func (c *RoutingClient) Close() error {
+	if c.isClosed() {
+		return nil
+	}
+	c.done.Close()
+	c.conn.Close()
+	c.wg.Wait()
+	c.logInfo("routing closed")
+	return nil
+}
+
+// Send sends a group write telegram to the KNX bus.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - ga: Target group address
+//   - data: DPT-encoded payload
+//
+// Returns:
+//   - error: If sending fails or the client is closed
+func (c *RoutingClient) Send(ctx context.Context, ga GroupAddress, data []byte) error {
+	return c.sendTelegram(ctx, NewWriteTelegram(ga, data))
+}
+
+// SendRead sends a group read request to the KNX bus.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - ga: Target group address to read
+//
+// Returns:
+//   - error: If sending fails or the client is closed
+func (c *RoutingClient) SendRead(ctx context.Context, ga GroupAddress) error {
+	return c.sendTelegram(ctx, NewReadTelegram(ga))
+}
+
+// SendResponse answers a group read request on the KNX bus.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - ga: Group address that was read
+//   - data: DPT-encoded value
+//
+// Returns:
+//   - error: If sending fails or the client is closed
+func (c *RoutingClient) SendResponse(ctx context.Context, ga GroupAddress, data []byte) error {
+	return c.sendTelegram(ctx, NewResponseTelegram(ga, data))
+}
+
+// sendTelegram sends a routing indication once pacing and any ROUTING_BUSY
+// wait allow.
+func (c *RoutingClient) sendTelegram(ctx context.Context, t Telegram) error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	if c.isClosed() {
+		return ErrNotConnected
+	}
+
+	now := time.Now()
+	wait := max(c.flow.wait(now), c.lastSend.Add(routingMinInterval).Sub(now))
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w: %w", ErrTelegramFailed, ctx.Err())
+		case <-c.done.Done():
+			timer.Stop()
+			return ErrNotConnected
+		}
+	}
+
+	frame := encodeCEMI(cemiLDataInd, t)
+	binary.BigEndian.PutUint16(frame[4:6], c.address)
+	if _, err := c.conn.WriteToUDP(encodeKNXnetIP(serviceRoutingIndication, frame), c.group); err != nil {
+		c.errorsTotal.Add(1)
+		return fmt.Errorf("%w: %w", ErrTelegramFailed, err)
+	}
+
+	c.lastSend = time.Now()
+	c.telegramsTx.Add(1)
+	c.lastActivity.Store(c.lastSend.Unix())
+	return nil
+}
+
+// isClosed reports whether Close has been called.
+func (c *RoutingClient) isClosed() bool {
+	select {
+	case <-c.done.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// SetOnTelegram sets the callback for received telegrams.
+//
+// Parameters:
+//   - callback: Function called for each received group telegram
+func (c *RoutingClient) SetOnTelegram(callback func(Telegram)) {
+	c.dispatcher.setCallback(callback)
+}
+
+// SetLogger sets the logger for connection events.
+//
+// Parameters:
+//   - logger: Logger instance (can be nil to disable logging)
+func (c *RoutingClient) SetLogger(logger Logger) {
+	c.loggerMu.Lock()
+	c.logger = logger
+	c.loggerMu.Unlock()
+}
+
+// IsConnected reports whether the client is joined to the group.
+func (c *RoutingClient) IsConnected() bool {
+	return !c.isClosed()
+}
+
+// IndividualAddress returns the source address used for sent frames.
+func (c *RoutingClient) IndividualAddress() string {
+	return formatIndividualAddress(c.address)
+}
+
+// BusyTotal returns the number of ROUTING_BUSY frames received.
+func (c *RoutingClient) BusyTotal() uint64 {
+	return c.busyTotal.Load()
+}
+
+// Stats returns current connection statistics.
+//
+// Returns:
+//   - KNXDStats: Snapshot of connection statistics
+func (c *RoutingClient) Stats() KNXDStats {
+	return KNXDStats{
+		TelegramsTx:      c.telegramsTx.Load(),
+		TelegramsRx:      c.telegramsRx.Load(),
+		TelegramsDropped: c.dispatcher.dropped.Load(),
+		ErrorsTotal:      c.errorsTotal.Load(),
+		LastActivity:     time.Unix(c.lastActivity.Load(), 0),
+		Connected:        c.IsConnected(),
+	}
+}
+
+// logInfo logs an info message if logger is set.
+func (c *RoutingClient) logInfo(msg string, args ...any) {
+	c.loggerMu.RLock()
+	logger := c.logger
+	c.loggerMu.RUnlock()
+
+	if logger != nil {
+		logger.Info(msg, args...)
+	}
+}
+
+// logError logs an error message if logger is set.
+func (c *RoutingClient) logError(msg string, err error) {
+	c.loggerMu.RLock()
+	logger := c.logger
+	c.loggerMu.RUnlock()
+
+	if logger != nil {
+		logger.Error(msg, "error", err)
+	}
+}
+
+// routingFlow implements ROUTING_BUSY flow control: sending pauses for the
+// router's wait time plus rand(0..1) × N × 50ms, where N counts busy
+// events (frames within 10ms of each other count once) and decays by one
+// every 5ms after the pause.
+type routingFlow struct {
+	mu         sync.Mutex
+	pauseUntil time.Time
+	busyCount  int
+	lastBusy   time.Time
+	decayedAt  time.Time
+}
+
+// busy records a ROUTING_BUSY frame.
+func (f *routingFlow) busy(now time.Time, wait time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.decay(now)
+	if now.Sub(f.lastBusy) > routingBusyBurst {
+		f.busyCount++
+	}
+	f.lastBusy = now
+
+	slowdown := time.Duration(rand.Float64() * float64(f.busyCount) * float64(routingBusySlowdown)) //nolint:gosec // jitter, not security
+	if until := now.Add(wait + slowdown); until.After(f.pauseUntil) {
+		f.pauseUntil = until
+	}
+}
+
+// wait returns how long sending must still pause.
+func (f *routingFlow) wait(now time.Time) time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.decay(now)
+	return f.pauseUntil.Sub(now)
+}
+
+// decay lowers the busy count once the pause has elapsed. Callers hold mu.
+func (f *routingFlow) decay(now time.Time) {
+	from := f.pauseUntil
+	if f.decayedAt.After(from) {
+		from = f.decayedAt
+	}
+	if f.busyCount == 0 || !now.After(from) {
+		return
+	}
+	steps := int(now.Sub(from) / routingBusyDecay)
+	f.busyCount = max(0, f.busyCount-steps)
+	f.decayedAt = from.Add(time.Duration(steps) * routingBusyDecay)
+}
+
+// duplicateFilter remembers recently seen frames to drop their repetitions.
+type duplicateFilter struct {
+	mu     sync.Mutex
+	window time.Duration
+	seen   map[string]time.Time
+}
+
+// seenRecently reports whether a repeated frame's key was seen within the
+// window. Frames that are not repetitions are never reported, since the same
+// telegram may legitimately be sent twice; every frame is recorded so its
+// repetitions match.
+func (f *duplicateFilter) seenRecently(key string, repeated bool, now time.Time) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for k, t := range f.seen {
+		if now.Sub(t) >= f.window {
+			delete(f.seen, k)
+		}
+	}
+	if _, ok := f.seen[key]; ok && repeated {
+		return true
+	}
+	f.seen[key] = now
+	return false
+}
@@ -0,0 +1,329 @@
+package knx
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMulticastGroup is a loopback stand-in for the routing multicast group:
+// every datagram it receives is forwarded to all members, sender included,
+// as a multicast group with loopback enabled would.
+type fakeMulticastGroup struct {
+	conn    *net.UDPConn
+	mu      sync.Mutex
+	members []*net.UDPAddr
+}
+
+func newFakeMulticastGroup(t *testing.T) *fakeMulticastGroup {
+	t.Helper()
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP() error: %v", err)
+	}
+	g := &fakeMulticastGroup{conn: conn}
+	go g.serve()
+	t.Cleanup(func() { conn.Close() })
+	return g
+}
+
+func (g *fakeMulticastGroup) serve() {
+	buf := make([]byte, knxnetipBufferSize)
+	for {
+		n, _, err := g.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		g.mu.Lock()
+		for _, m := range g.members {
+			_, _ = g.conn.WriteToUDP(buf[:n], m)
+		}
+		g.mu.Unlock()
+	}
+}
+
+// join opens a loopback socket that receives the group's traffic.
+func (g *fakeMulticastGroup) join(t *testing.T) *net.UDPConn {
+	t.Helper()
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP() error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	g.mu.Lock()
+	g.members = append(g.members, conn.LocalAddr().(*net.UDPAddr))
+	g.mu.Unlock()
+	return conn
+}
+
+func (g *fakeMulticastGroup) addr() *net.UDPAddr {
+	return g.conn.LocalAddr().(*net.UDPAddr)
+}
+
+// routingPeer is another router on the fake group.
+type routingPeer struct {
+	t     *testing.T
+	conn  *net.UDPConn
+	group *net.UDPAddr
+}
+
+func (p *routingPeer) send(service uint16, body []byte) {
+	p.t.Helper()
+	if _, err := p.conn.WriteToUDP(encodeKNXnetIP(service, body), p.group); err != nil {
+		p.t.Fatalf("peer write: %v", err)
+	}
+}
+
+// indicate sends a group telegram from 1.1.5.
+func (p *routingPeer) indicate(t Telegram) []byte {
+	cemi := encodeCEMI(cemiLDataInd, t)
+	cemi[4], cemi[5] = 0x11, 0x05
+	p.send(serviceRoutingIndication, cemi)
+	return cemi
+}
+
+// next returns the next routing indication seen on the group.
+func (p *routingPeer) next() ([]byte, bool) {
+	buf := make([]byte, knxnetipBufferSize)
+	for {
+		_ = p.conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+		n, _, err := p.conn.ReadFromUDP(buf)
+		if err != nil {
+			return nil, false
+		}
+		service, body, err := parseKNXnetIP(buf[:n])
+		if err == nil && service == serviceRoutingIndication && body[4] == 0x11 && body[5] == 0xFA {
+			return append([]byte(nil), body...), true
+		}
+	}
+}
+
+// telegramSink collects telegrams delivered to a callback.
+type telegramSink struct {
+	mu        sync.Mutex
+	telegrams []Telegram
+}
+
+func (s *telegramSink) add(t Telegram) {
+	s.mu.Lock()
+	s.telegrams = append(s.telegrams, t)
+	s.mu.Unlock()
+}
+
+func (s *telegramSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.telegrams)
+}
+
+func newTestRouting(t *testing.T) (*RoutingClient, *routingPeer, *telegramSink) {
+	t.Helper()
+	group := newFakeMulticastGroup(t)
+	c, err := newRoutingClient(RoutingConfig{IndividualAddress: "1.1.250"}, group.join(t), group.addr())
+	if err != nil {
+		t.Fatalf("newRoutingClient() error: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	sink := &telegramSink{}
+	c.SetOnTelegram(sink.add)
+	return c, &routingPeer{t: t, conn: group.join(t), group: group.addr()}, sink
+}
+
+func TestParseIndividualAddress(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint16
+		wantErr bool
+	}{
+		{"1.1.250", 0x11FA, false},
+		{"15.15.255", 0xFFFF, false},
+		{"0.0.0", 0, false},
+		{"16.1.1", 0, true},
+		{"1.1.256", 0, true},
+		{"1.1", 0, true},
+		{"a.b.c", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseIndividualAddress(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseIndividualAddress(%q) = %#x, %v; want %#x, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+		if err == nil && formatIndividualAddress(got) != tt.in {
+			t.Errorf("formatIndividualAddress(%#x) = %q, want %q", got, formatIndividualAddress(got), tt.in)
+		}
+	}
+}
+
+func TestRoutingSend(t *testing.T) {
+	c, peer, sink := newTestRouting(t)
+	ga := GroupAddress{Main: 1, Middle: 2, Sub: 3}
+
+	start := time.Now()
+	if err := c.Send(context.Background(), ga, []byte{0x01}); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	if err := c.SendRead(context.Background(), ga); err != nil {
+		t.Fatalf("SendRead() error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < routingMinInterval {
+		t.Errorf("two sends took %v, want at least %v pacing", elapsed, routingMinInterval)
+	}
+
+	want := encodeCEMI(cemiLDataInd, NewWriteTelegram(ga, []byte{0x01}))
+	want[4], want[5] = 0x11, 0xFA
+	got, ok := peer.next()
+	if !ok {
+		t.Fatal("no routing indication on the group")
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("indication = % X, want % X", got, want)
+	}
+	if _, ok := peer.next(); !ok {
+		t.Fatal("read request not seen on the group")
+	}
+
+	// Our own frames come back from the group but are not delivered
+	time.Sleep(50 * time.Millisecond)
+	if n := sink.count(); n != 0 {
+		t.Errorf("delivered %d looped-back telegrams, want 0", n)
+	}
+	if stats := c.Stats(); stats.TelegramsTx != 2 || stats.TelegramsRx != 0 || !stats.Connected {
+		t.Errorf("Stats() = %+v, want 2 sent, 0 received, connected", stats)
+	}
+
+	c.Close()
+	if err := c.Send(context.Background(), ga, []byte{0x00}); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Send() after Close error = %v, want ErrNotConnected", err)
+	}
+}
+
+func TestRoutingReceiveDuplicates(t *testing.T) {
+	c, peer, sink := newTestRouting(t)
+	ga := GroupAddress{Main: 6, Middle: 0, Sub: 1}
+
+	frame := peer.indicate(NewWriteTelegram(ga, []byte{0x0C, 0x1A}))
+	waitFor(t, "telegram", func() bool { return sink.count() == 1 })
+
+	sink.mu.Lock()
+	tg := sink.telegrams[0]
+	sink.mu.Unlock()
+	if tg.Destination != ga || tg.Source != "1.1.5" || !bytes.Equal(tg.Data, []byte{0x0C, 0x1A}) {
+		t.Errorf("received %v from %s, want write to %s from 1.1.5", tg, tg.Source, ga)
+	}
+
+	// A line repetition (repeat flag cleared) is dropped
+	repeated := append([]byte(nil), frame...)
+	repeated[2] &^= cemiRepeatFlag
+	peer.send(serviceRoutingIndication, repeated)
+
+	// A new value is delivered
+	peer.indicate(NewWriteTelegram(ga, []byte{0x0C, 0x1B}))
+	waitFor(t, "second telegram", func() bool { return sink.count() == 2 })
+	time.Sleep(50 * time.Millisecond)
+	if n := sink.count(); n != 2 {
+		t.Errorf("delivered %d telegrams, want 2", n)
+	}
+	if got := c.Stats().TelegramsRx; got != 2 {
+		t.Errorf("TelegramsRx = %d, want 2", got)
+	}
+}
+
+func TestRoutingReceiveUnrepeatedIdentical(t *testing.T) {
+	_, peer, sink := newTestRouting(t)
+	ga := GroupAddress{Main: 6, Middle: 0, Sub: 2}
+
+	// The same value written twice, neither a repetition: both delivered
+	frame := peer.indicate(NewWriteTelegram(ga, []byte{0x01}))
+	peer.send(serviceRoutingIndication, frame)
+	waitFor(t, "both telegrams", func() bool { return sink.count() == 2 })
+}
+
+func TestRoutingBusy(t *testing.T) {
+	c, peer, _ := newTestRouting(t)
+
+	// ROUTING_BUSY: length, device state, wait time 100ms, control field
+	busy := make([]byte, routingBusyLen)
+	busy[0] = routingBusyLen
+	binary.BigEndian.PutUint16(busy[2:4], 100)
+	peer.send(serviceRoutingBusy, busy)
+	waitFor(t, "busy frame", func() bool { return c.BusyTotal() == 1 })
+
+	start := time.Now()
+	if err := c.Send(context.Background(), GroupAddress{Main: 1, Middle: 2, Sub: 3}, []byte{0x01}); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("Send() during ROUTING_BUSY returned after %v, want it held for the wait time", elapsed)
+	}
+
+	// A context that ends during the wait aborts the send
+	peer.send(serviceRoutingBusy, busy)
+	waitFor(t, "second busy frame", func() bool { return c.BusyTotal() == 2 })
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := c.Send(ctx, GroupAddress{Main: 1, Middle: 2, Sub: 3}, []byte{0x00}); !errors.Is(err, ErrTelegramFailed) {
+		t.Errorf("Send() with expiring context error = %v, want ErrTelegramFailed", err)
+	}
+}
+
+func TestRoutingFlow(t *testing.T) {
+	var f routingFlow
+	t0 := time.Unix(1_700_000_000, 0)
+
+	f.busy(t0, 20*time.Millisecond)
+	if f.busyCount != 1 {
+		t.Fatalf("busyCount = %d, want 1", f.busyCount)
+	}
+	if w := f.wait(t0); w < 20*time.Millisecond || w > 20*time.Millisecond+routingBusySlowdown {
+		t.Errorf("wait = %v, want 20ms plus up to one slowdown", w)
+	}
+
+	// Within the burst window: same event
+	f.busy(t0.Add(5*time.Millisecond), 20*time.Millisecond)
+	if f.busyCount != 1 {
+		t.Errorf("busyCount after burst = %d, want 1", f.busyCount)
+	}
+
+	// A separate event increases the slowdown range
+	f.busy(t0.Add(30*time.Millisecond), 20*time.Millisecond)
+	if f.busyCount != 2 {
+		t.Errorf("busyCount after second event = %d, want 2", f.busyCount)
+	}
+
+	// Long after the pause the count decays to zero and sending is free
+	later := t0.Add(time.Second)
+	if w := f.wait(later); w > 0 {
+		t.Errorf("wait long after busy = %v, want <= 0", w)
+	}
+	if f.busyCount != 0 {
+		t.Errorf("busyCount after decay = %d, want 0", f.busyCount)
+	}
+}
+
+func TestConnectRoutingInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  RoutingConfig
+	}{
+		{"unicast group", RoutingConfig{MulticastAddress: "192.168.1.50"}},
+		{"unknown interface", RoutingConfig{Interface: "does-not-exist0"}},
+		{"bad individual address", RoutingConfig{IndividualAddress: "1.1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ConnectRouting(context.Background(), tt.cfg)
+			if err == nil {
+				c.Close()
+			}
+			if !errors.Is(err, ErrConnectionFailed) {
+				t.Errorf("ConnectRouting() error = %v, want ErrConnectionFailed", err)
+			}
+		})
+	}
+}
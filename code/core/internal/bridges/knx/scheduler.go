@@ -0,0 +1,351 @@
+package knx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Scheduler defaults and limits.
+const (
+	// DefaultSchedulerRate is the default telegram budget (telegrams per
+	// second). A 9600 baud TP line carries about 40-50 telegrams per second
+	// at full load; staying well below leaves room for devices on the line.
+	DefaultSchedulerRate = 20
+
+	// MaxSchedulerRate is the highest configurable telegram budget.
+	MaxSchedulerRate = 50
+
+	// DefaultSchedulerQueueSize is the default number of queued telegrams.
+	DefaultSchedulerQueueSize = 500
+
+	// MaxSchedulerQueueSize is the highest configurable queue size.
+	MaxSchedulerQueueSize = 10000
+)
+
+// Priority orders queued telegrams: lower values are sent first.
+type Priority int
+
+// Telegram priorities, highest first.
+const (
+	// PriorityInteractive is for commands a user is waiting on and read
+	// responses. The default for telegrams without a priority.
+	PriorityInteractive Priority = iota
+
+	// PriorityBulk is for scene writes and time broadcasts.
+	PriorityBulk
+
+	// PriorityBackground is for state reads after start-up and reloads.
+	PriorityBackground
+
+	priorityLevels = 3
+)
+
+// priorityKey is the context key for a telegram's priority.
+type priorityKey struct{}
+
+// WithPriority returns a context whose telegrams are queued with priority p.
+//
+// Parameters:
+//   - ctx: Parent context
+//   - p: Priority for telegrams sent with the returned context
+//
+// Returns:
+//   - context.Context: Context carrying the priority
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// priorityFrom returns the priority set by WithPriority, or PriorityInteractive.
+func priorityFrom(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok && p >= 0 && p < priorityLevels {
+		return p
+	}
+	return PriorityInteractive
+}
+
+// SchedulerStats contains outgoing queue counters.
+type SchedulerStats struct {
+	QueueDepth int    // Telegrams waiting to be sent
+	Dropped    uint64 // Telegrams discarded: queue full, or the sender gave up waiting
+	Coalesced  uint64 // Telegrams merged into one already queued for the same GA
+}
+
+// queuedTelegram is a telegram waiting in the scheduler. Telegrams merged
+// into it by coalescing add their waiters.
+type queuedTelegram struct {
+	key      string
+	apci     byte
+	ga       GroupAddress
+	data     []byte
+	priority Priority
+	waiters  []queueWaiter
+}
+
+// queueWaiter is a Send call waiting for its telegram to go out.
+type queueWaiter struct {
+	ctx    context.Context //nolint:containedctx // the sender's context is needed to send and to detect abandoned telegrams
+	result chan error
+}
+
+// telegramScheduler paces outgoing telegrams to a telegram budget per
+// second, sending queued telegrams in priority order. A write, read or
+// response to a group address that is already queued is merged into the
+// queued one (a write keeps the newest value), so repeated updates are
+// sent once.
+//
+// Send, SendRead and SendResponse block until the telegram is sent and
+// return the connector's result. When the queue is full, the newest
+// telegram of the lowest priority below the new one is dropped to make
+// room; if there is none the new telegram is rejected with ErrQueueFull.
+type telegramScheduler struct {
+	Connector
+	interval time.Duration
+	maxQueue int
+
+	mu      sync.Mutex
+	queues  [priorityLevels][]*queuedTelegram
+	pending map[string]*queuedTelegram
+	depth   int
+	stopped bool
+	wake    chan struct{}
+
+	dropped   atomic.Uint64
+	coalesced atomic.Uint64
+}
+
+// newTelegramScheduler creates a scheduler in front of inner. Call run to
+// start sending.
+func newTelegramScheduler(inner Connector, rate, queueSize int) *telegramScheduler {
+	return &telegramScheduler{
+		Connector: inner,
+		interval:  time.Second / time.Duration(rate),
+		maxQueue:  queueSize,
+		pending:   make(map[string]*queuedTelegram),
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Send queues a group write and waits until it is sent.
+func (s *telegramScheduler) Send(ctx context.Context, ga GroupAddress, data []byte) error {
+	return s.enqueue(ctx, APCIWrite, ga, data)
+}
+
+// SendRead queues a group read and waits until it is sent.
+func (s *telegramScheduler) SendRead(ctx context.Context, ga GroupAddress) error {
+	return s.enqueue(ctx, APCIRead, ga, nil)
+}
+
+// SendResponse queues a group response and waits until it is sent.
+func (s *telegramScheduler) SendResponse(ctx context.Context, ga GroupAddress, data []byte) error {
+	return s.enqueue(ctx, APCIResponse, ga, data)
+}
+
+// IndividualAddress returns the wrapped connector's address, if it has one.
+func (s *telegramScheduler) IndividualAddress() string {
+	if r, ok := s.Connector.(addressReporter); ok {
+		return r.IndividualAddress()
+	}
+	return ""
+}
+
+// SchedulerStats returns the scheduler's queue counters.
+func (s *telegramScheduler) SchedulerStats() SchedulerStats {
+	s.mu.Lock()
+	depth := s.depth
+	s.mu.Unlock()
+
+	return SchedulerStats{
+		QueueDepth: depth,
+		Dropped:    s.dropped.Load(),
+		Coalesced:  s.coalesced.Load(),
+	}
+}
+
+// enqueue adds a telegram to the queue (or merges it into a queued one)
+// and waits for the result.
+func (s *telegramScheduler) enqueue(ctx context.Context, apci byte, ga GroupAddress, data []byte) error {
+	if !s.Connector.IsConnected() {
+		return ErrNotConnected
+	}
+
+	priority := priorityFrom(ctx)
+	waiter := queueWaiter{ctx: ctx, result: make(chan error, 1)}
+	key := apciName(apci) + " " + ga.String()
+
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return ErrNotConnected
+	}
+	if queued, ok := s.pending[key]; ok {
+		if apci != APCIRead {
+			queued.data = data
+		}
+		if priority < queued.priority {
+			s.remove(queued)
+			queued.priority = priority
+			s.queues[priority] = append(s.queues[priority], queued)
+			s.depth++
+		}
+		queued.waiters = append(queued.waiters, waiter)
+		s.coalesced.Add(1)
+	} else {
+		if s.depth >= s.maxQueue && !s.makeRoom(priority) {
+			s.mu.Unlock()
+			s.dropped.Add(1)
+			return ErrQueueFull
+		}
+		queued := &queuedTelegram{key: key, apci: apci, ga: ga, data: data, priority: priority, waiters: []queueWaiter{waiter}}
+		s.pending[key] = queued
+		s.queues[priority] = append(s.queues[priority], queued)
+		s.depth++
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	select {
+	case err := <-waiter.result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// makeRoom drops the newest telegram with a lower priority than p.
+// Returns false if there is none. Caller must hold s.mu.
+func (s *telegramScheduler) makeRoom(p Priority) bool {
+	for level := priorityLevels - 1; level > int(p); level-- {
+		queue := s.queues[level]
+		if len(queue) == 0 {
+			continue
+		}
+		victim := queue[len(queue)-1]
+		s.remove(victim)
+		delete(s.pending, victim.key)
+		s.dropped.Add(1)
+		for _, w := range victim.waiters {
+			w.result <- ErrQueueFull
+		}
+		return true
+	}
+	return false
+}
+
+// remove takes a telegram out of its priority queue. Caller must hold s.mu.
+func (s *telegramScheduler) remove(t *queuedTelegram) {
+	queue := s.queues[t.priority]
+	for i, queued := range queue {
+		if queued == t {
+			s.queues[t.priority] = append(queue[:i], queue[i+1:]...)
+			s.depth--
+			return
+		}
+	}
+}
+
+// next removes and returns the first telegram of the highest priority,
+// or nil if the queue is empty.
+func (s *telegramScheduler) next() *queuedTelegram {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for level := range s.queues {
+		if len(s.queues[level]) == 0 {
+			continue
+		}
+		t := s.queues[level][0]
+		s.queues[level][0] = nil
+		s.queues[level] = s.queues[level][1:]
+		s.depth--
+		delete(s.pending, t.key)
+		return t
+	}
+	return nil
+}
+
+// run sends queued telegrams, at most one per interval, until ctx is
+// cancelled. Telegrams still queued then fail with the context's error.
+func (s *telegramScheduler) run(ctx context.Context) {
+	for {
+		if ctx.Err() != nil {
+			s.stop(ctx.Err())
+			return
+		}
+
+		t := s.next()
+		if t == nil {
+			select {
+			case <-s.wake:
+				continue
+			case <-ctx.Done():
+				s.stop(ctx.Err())
+				return
+			}
+		}
+
+		if !s.send(t) {
+			continue // Nobody waiting any more: skip without using the budget
+		}
+
+		select {
+		case <-time.After(s.interval):
+		case <-ctx.Done():
+			s.stop(ctx.Err())
+			return
+		}
+	}
+}
+
+// send sends a telegram on behalf of its waiters and hands them the result.
+// Returns false if every waiter had given up, in which case nothing is sent.
+func (s *telegramScheduler) send(t *queuedTelegram) bool {
+	var sendCtx context.Context
+	for _, w := range t.waiters {
+		if w.ctx.Err() == nil {
+			sendCtx = w.ctx
+			break
+		}
+	}
+	if sendCtx == nil {
+		s.dropped.Add(1)
+		return false
+	}
+
+	var err error
+	switch t.apci {
+	case APCIRead:
+		err = s.Connector.SendRead(sendCtx, t.ga)
+	case APCIResponse:
+		err = s.Connector.SendResponse(sendCtx, t.ga, t.data)
+	default:
+		err = s.Connector.Send(sendCtx, t.ga, t.data)
+	}
+	for _, w := range t.waiters {
+		w.result <- err
+	}
+	return true
+}
+
+// stop fails all queued telegrams with err and rejects new ones.
+func (s *telegramScheduler) stop(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+	for level := range s.queues {
+		for _, t := range s.queues[level] {
+			for _, w := range t.waiters {
+				w.result <- err
+			}
+		}
+		s.queues[level] = nil
+	}
+	s.pending = make(map[string]*queuedTelegram)
+	s.depth = 0
+}
@@ -0,0 +1,170 @@
+package knx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// queueSends starts one Send per GA with the given priority and waits until
+// they are all queued. Returns a wait group and the per-send errors.
+func queueSends(t *testing.T, s *telegramScheduler, p Priority, gas []GroupAddress, data byte) (*sync.WaitGroup, []error) {
+	t.Helper()
+	var wg sync.WaitGroup
+	errs := make([]error, len(gas))
+	before := s.SchedulerStats()
+	for i, ga := range gas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.Send(WithPriority(context.Background(), p), ga, []byte{data})
+		}()
+	}
+	waitFor(t, "telegrams queued", func() bool {
+		st := s.SchedulerStats()
+		return st.QueueDepth+int(st.Coalesced+st.Dropped) >= before.QueueDepth+int(before.Coalesced+before.Dropped)+len(gas)
+	})
+	return &wg, errs
+}
+
+func TestSchedulerPriorityOrder(t *testing.T) {
+	knxd := NewMockConnector()
+	s := newTelegramScheduler(knxd, MaxSchedulerRate, 10)
+
+	ga := func(sub uint8) GroupAddress { return GroupAddress{Main: 1, Middle: 0, Sub: sub} }
+	bg1, _ := queueSends(t, s, PriorityBackground, []GroupAddress{ga(1)}, 0)
+	bg2, _ := queueSends(t, s, PriorityBackground, []GroupAddress{ga(2)}, 0)
+	bulk, _ := queueSends(t, s, PriorityBulk, []GroupAddress{ga(3)}, 0)
+	user, _ := queueSends(t, s, PriorityInteractive, []GroupAddress{ga(4)}, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.run(ctx)
+	for _, wg := range []*sync.WaitGroup{bg1, bg2, bulk, user} {
+		wg.Wait()
+	}
+
+	var got []uint8
+	for _, sent := range knxd.GetSentTelegrams() {
+		got = append(got, sent.GA.Sub)
+	}
+	if want := []uint8{4, 3, 1, 2}; len(got) != len(want) || got[0] != 4 || got[1] != 3 || got[2] != 1 || got[3] != 2 {
+		t.Errorf("sent order = %v, want %v", got, want)
+	}
+	if st := s.SchedulerStats(); st.QueueDepth != 0 || st.Dropped != 0 {
+		t.Errorf("stats = %+v", st)
+	}
+}
+
+func TestSchedulerCoalesce(t *testing.T) {
+	knxd := NewMockConnector()
+	s := newTelegramScheduler(knxd, MaxSchedulerRate, 10)
+	ga := GroupAddress{Main: 1, Middle: 0, Sub: 1}
+
+	scene, sceneErrs := queueSends(t, s, PriorityBulk, []GroupAddress{ga}, 10)
+	user, userErrs := queueSends(t, s, PriorityInteractive, []GroupAddress{ga}, 20)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.run(ctx)
+	scene.Wait()
+	user.Wait()
+
+	sent := knxd.GetSentTelegrams()
+	if len(sent) != 1 || sent[0].Data[0] != 20 {
+		t.Errorf("sent = %+v, want one write of the newest value", sent)
+	}
+	if sceneErrs[0] != nil || userErrs[0] != nil {
+		t.Errorf("errors = %v, %v, want both nil", sceneErrs[0], userErrs[0])
+	}
+	if st := s.SchedulerStats(); st.Coalesced != 1 {
+		t.Errorf("Coalesced = %d, want 1", st.Coalesced)
+	}
+}
+
+func TestSchedulerQueueFull(t *testing.T) {
+	knxd := NewMockConnector()
+	s := newTelegramScheduler(knxd, MaxSchedulerRate, 2)
+
+	bg, bgErrs := queueSends(t, s, PriorityBackground, []GroupAddress{{Main: 1, Sub: 1}, {Main: 1, Sub: 2}}, 0)
+
+	// A command pushes out the newest background telegram
+	user, userErrs := queueSends(t, s, PriorityInteractive, []GroupAddress{{Main: 2, Sub: 1}}, 0)
+
+	// Nothing below background to drop: rejected
+	err := s.Send(WithPriority(context.Background(), PriorityBackground), GroupAddress{Main: 1, Sub: 3}, []byte{0})
+	if !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Send() to a full queue error = %v, want ErrQueueFull", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.run(ctx)
+	bg.Wait()
+	user.Wait()
+
+	if userErrs[0] != nil {
+		t.Errorf("interactive Send() error = %v", userErrs[0])
+	}
+	failed := 0
+	for _, err := range bgErrs {
+		if errors.Is(err, ErrQueueFull) {
+			failed++
+		}
+	}
+	if failed != 1 || len(knxd.GetSentTelegrams()) != 2 {
+		t.Errorf("dropped %d background telegrams and sent %d, want 1 and 2", failed, len(knxd.GetSentTelegrams()))
+	}
+	if st := s.SchedulerStats(); st.Dropped != 2 {
+		t.Errorf("Dropped = %d, want 2", st.Dropped)
+	}
+}
+
+func TestSchedulerPacing(t *testing.T) {
+	knxd := NewMockConnector()
+	s := newTelegramScheduler(knxd, 10, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.run(ctx)
+
+	start := time.Now()
+	for i := range 3 {
+		if err := s.Send(context.Background(), GroupAddress{Main: 1, Sub: uint8(i)}, []byte{0}); err != nil {
+			t.Fatalf("Send() error: %v", err)
+		}
+	}
+	// The first goes straight out, then one every 100ms
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("3 telegrams at 10/s took %v, want >= 200ms", elapsed)
+	}
+}
+
+func TestSchedulerStop(t *testing.T) {
+	knxd := NewMockConnector()
+	s := newTelegramScheduler(knxd, MaxSchedulerRate, 10)
+	wg, errs := queueSends(t, s, PriorityBulk, []GroupAddress{{Main: 1, Sub: 1}}, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.run(ctx)
+	wg.Wait()
+
+	if !errors.Is(errs[0], context.Canceled) {
+		t.Errorf("queued Send() error = %v, want context.Canceled", errs[0])
+	}
+	if err := s.Send(context.Background(), GroupAddress{Main: 1, Sub: 2}, []byte{0}); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Send() after stop error = %v, want ErrNotConnected", err)
+	}
+}
+
+func TestCommandPriority(t *testing.T) {
+	if p := commandPriority(CommandMessage{Source: "scene:scene-evening"}); p != PriorityBulk {
+		t.Errorf("scene command priority = %d, want PriorityBulk", p)
+	}
+	if p := commandPriority(CommandMessage{Source: "api"}); p != PriorityInteractive {
+		t.Errorf("api command priority = %d, want PriorityInteractive", p)
+	}
+}
@@ -0,0 +1,329 @@
+package knx
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// secureTestConnector is a Connector that records prepared telegrams.
+type secureTestConnector struct {
+	mockConnector
+	mu       sync.Mutex
+	sent     []Telegram
+	plain    []Telegram
+	callback func(Telegram)
+}
+
+func (c *secureTestConnector) Send(_ context.Context, ga GroupAddress, data []byte) error {
+	c.mu.Lock()
+	c.plain = append(c.plain, NewWriteTelegram(ga, data))
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *secureTestConnector) SetOnTelegram(callback func(Telegram)) { c.callback = callback }
+
+func (c *secureTestConnector) IndividualAddress() string { return "1.1.250" }
+
+func (c *secureTestConnector) sendTelegram(_ context.Context, t Telegram) error {
+	c.mu.Lock()
+	c.sent = append(c.sent, t)
+	c.mu.Unlock()
+	return nil
+}
+
+// memorySequenceStore is an in-memory SequenceStore.
+type memorySequenceStore struct {
+	mu   sync.Mutex
+	seqs map[string]uint64
+}
+
+func (m *memorySequenceStore) LoadSequences(context.Context) (map[string]uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]uint64, len(m.seqs))
+	for k, v := range m.seqs {
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (m *memorySequenceStore) SaveSequence(_ context.Context, ia string, seq uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seqs[ia] = max(m.seqs[ia], seq)
+	return nil
+}
+
+func newTestSecure(t *testing.T, store SequenceStore) (*SecureConnector, *secureTestConnector, *telegramSink) {
+	t.Helper()
+	inner := &secureTestConnector{mockConnector: mockConnector{connected: true}}
+	s, err := NewSecureConnector(context.Background(), inner, SecureOptions{Store: store})
+	if err != nil {
+		t.Fatalf("NewSecureConnector() error: %v", err)
+	}
+	s.SetKeyring(&Keyring{
+		GroupKeys: map[string][]byte{"1/2/3": testGroupKey},
+		Devices:   []KeyringDevice{{IndividualAddress: "1.1.10", SequenceNumber: 500}},
+	})
+	sink := &telegramSink{}
+	s.SetOnTelegram(sink.add)
+	return s, inner, sink
+}
+
+// secureTelegram builds a received secure telegram from src.
+func secureTelegram(t *testing.T, src string, ga GroupAddress, seq uint64, apdu []byte) Telegram {
+	t.Helper()
+	addr, err := parseIndividualAddress(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secured, err := secureEncrypt(testGroupKey, addr, ga, seq, apdu)
+	if err != nil {
+		t.Fatalf("secureEncrypt() error: %v", err)
+	}
+	return Telegram{Source: src, Destination: ga, APCI: APCISecure, Data: secured}
+}
+
+func TestSecureTelegramEncoding(t *testing.T) {
+	ga := GroupAddress{Main: 1, Middle: 2, Sub: 3}
+	data := []byte{secureSCFGroup, 0, 0, 0, 0, 0, 1, 0xAA, 0xBB, 1, 2, 3, 4}
+	encoded := Telegram{Destination: ga, APCI: APCISecure, Data: data}.Encode()
+
+	parsed, err := ParseTelegram(append([]byte{0x11, 0x0A}, encoded...))
+	if err != nil {
+		t.Fatalf("ParseTelegram() error: %v", err)
+	}
+	if parsed.APCI != APCISecure || parsed.Destination != ga || !bytes.Equal(parsed.Data, data) {
+		t.Errorf("parsed = %v % X, want secure telegram with the secured APDU", parsed, parsed.Data)
+	}
+}
+
+func TestSecureEncryptDecrypt(t *testing.T) {
+	ga := GroupAddress{Main: 1, Middle: 2, Sub: 3}
+	apdu := []byte{0x00, 0x80 | 0x01}
+
+	secured, err := secureEncrypt(testGroupKey, 0x110A, ga, 42, apdu)
+	if err != nil {
+		t.Fatalf("secureEncrypt() error: %v", err)
+	}
+	if len(secured) != secureHeaderLen+len(apdu)+secureMACLen || secured[0] != secureSCFGroup {
+		t.Fatalf("secured = % X", secured)
+	}
+	if bytes.Contains(secured[secureHeaderLen:], apdu) {
+		t.Error("APDU is not encrypted")
+	}
+
+	got, seq, err := secureDecrypt(testGroupKey, 0x110A, ga, secured)
+	if err != nil || seq != 42 || !bytes.Equal(got, apdu) {
+		t.Fatalf("secureDecrypt() = % X, %d, %v; want % X, 42", got, seq, err, apdu)
+	}
+
+	// The MAC covers the payload, the sequence number and both addresses
+	tampered := append([]byte(nil), secured...)
+	tampered[secureHeaderLen] ^= 0x01
+	if _, _, err := secureDecrypt(testGroupKey, 0x110A, ga, tampered); !errors.Is(err, ErrSecureFailed) {
+		t.Errorf("tampered payload error = %v, want ErrSecureFailed", err)
+	}
+	tampered = append([]byte(nil), secured...)
+	tampered[secureSeqLen] ^= 0x01
+	if _, _, err := secureDecrypt(testGroupKey, 0x110A, ga, tampered); err == nil {
+		t.Error("tampered sequence number accepted")
+	}
+	if _, _, err := secureDecrypt(testGroupKey, 0x110B, ga, secured); err == nil {
+		t.Error("wrong source address accepted")
+	}
+	if _, _, err := secureDecrypt(testGroupKey, 0x110A, GroupAddress{Main: 1, Middle: 2, Sub: 4}, secured); err == nil {
+		t.Error("wrong group address accepted")
+	}
+	if _, _, err := secureDecrypt(testBackboneKey, 0x110A, ga, secured); err == nil {
+		t.Error("wrong key accepted")
+	}
+}
+
+// TestSecureVectors checks the secured APDU byte for byte against fixed
+// vectors, so a mistake shared by secureEncrypt and secureDecrypt still
+// fails.
+//
+// These are not published vectors: they were computed outside this package
+// (AES-128 from OpenSSL, with block 0, counter 0 and the associated data
+// laid out from our reading of AN158), so they do not catch a misreading of
+// the frame layout.
+// TODO: replace with the worked examples from KNX AN158 or another
+// implementation's test suite, citing the source.
+func TestSecureVectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		src     uint16
+		ga      GroupAddress
+		seq     uint64
+		apdu    string
+		secured string // SCF, sequence number, ciphertext, MAC
+	}{
+		{
+			name:    "switch on",
+			key:     "C0C1C2C3C4C5C6C7C8C9CACBCCCDCECF",
+			src:     0x1101, // 1.1.1
+			ga:      GroupAddress{Main: 1, Middle: 2, Sub: 3},
+			seq:     0xA1B2,
+			apdu:    "0081", // GroupValueWrite, DPT 1 on
+			secured: "10" + "00000000A1B2" + "CD99" + "60F3FC25",
+		},
+		{
+			name:    "string across two blocks",
+			key:     "000102030405060708090A0B0C0D0E0F",
+			src:     0x11FA, // 1.1.250
+			ga:      GroupAddress{Main: 31, Middle: 7, Sub: 255},
+			seq:     0x0102030405,
+			apdu:    "0080" + "47726179204C6F676963" + "00000000", // GroupValueWrite, DPT 16 "Gray Logic"
+			secured: "10" + "000102030405" + "5AB21DC1B6D098F46EEDE0CB47A09EFE" + "00455069",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, _ := hex.DecodeString(tt.key)
+			apdu, _ := hex.DecodeString(tt.apdu)
+			want, _ := hex.DecodeString(tt.secured)
+
+			got, err := secureEncrypt(key, tt.src, tt.ga, tt.seq, apdu)
+			if err != nil {
+				t.Fatalf("secureEncrypt() error: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("secureEncrypt() = % X, want % X", got, want)
+			}
+
+			plain, seq, err := secureDecrypt(key, tt.src, tt.ga, want)
+			if err != nil || seq != tt.seq || !bytes.Equal(plain, apdu) {
+				t.Errorf("secureDecrypt() = % X, %d, %v; want % X, %d", plain, seq, err, apdu, tt.seq)
+			}
+		})
+	}
+}
+
+func TestSecureConnectorSend(t *testing.T) {
+	store := &memorySequenceStore{seqs: map[string]uint64{"1.1.250": 7}}
+	s, inner, _ := newTestSecure(t, store)
+	secureGA := GroupAddress{Main: 1, Middle: 2, Sub: 3}
+	plainGA := GroupAddress{Main: 1, Middle: 2, Sub: 4}
+
+	if !s.IsSecure(secureGA) || s.IsSecure(plainGA) {
+		t.Fatal("IsSecure() does not match the keyring")
+	}
+
+	ctx := context.Background()
+	if err := s.Send(ctx, secureGA, []byte{0x01}); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	if err := s.SendRead(ctx, secureGA); err != nil {
+		t.Fatalf("SendRead() error: %v", err)
+	}
+	if err := s.Send(ctx, plainGA, []byte{0x00}); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	if len(inner.plain) != 1 || inner.plain[0].Destination != plainGA {
+		t.Errorf("plain sends = %v, want one to %s", inner.plain, plainGA)
+	}
+	if len(inner.sent) != 2 {
+		t.Fatalf("secure sends = %d, want 2", len(inner.sent))
+	}
+
+	// Frames are authenticated with the connector's address and continue
+	// from the stored send counter
+	wantAPDU := [][]byte{NewWriteTelegram(secureGA, []byte{0x01}).Encode()[2:], NewReadTelegram(secureGA).Encode()[2:]}
+	for i, sent := range inner.sent {
+		if sent.APCI != APCISecure {
+			t.Errorf("sent[%d] APCI = %#x, want APCISecure", i, sent.APCI)
+		}
+		apdu, seq, err := secureDecrypt(testGroupKey, 0x11FA, secureGA, sent.Data)
+		if err != nil {
+			t.Fatalf("sent[%d] does not decrypt: %v", i, err)
+		}
+		if seq != uint64(8+i) || !bytes.Equal(apdu, wantAPDU[i]) {
+			t.Errorf("sent[%d] = seq %d, % X; want seq %d, % X", i, seq, apdu, 8+i, wantAPDU[i])
+		}
+	}
+	if store.seqs["1.1.250"] != 9 {
+		t.Errorf("stored send counter = %d, want 9", store.seqs["1.1.250"])
+	}
+	if st := s.SecureStats(); st.Encrypted != 2 || st.Keys != 1 {
+		t.Errorf("SecureStats() = %+v", st)
+	}
+
+	inner.mockConnector.mu.Lock()
+	inner.connected = false
+	inner.mockConnector.mu.Unlock()
+	if err := s.Send(ctx, secureGA, []byte{0x00}); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Send() while disconnected error = %v, want ErrNotConnected", err)
+	}
+	if store.seqs["1.1.250"] != 9 {
+		t.Error("send counter advanced for a frame that was not sent")
+	}
+}
+
+func TestSecureConnectorReceive(t *testing.T) {
+	store := &memorySequenceStore{seqs: map[string]uint64{}}
+	s, inner, sink := newTestSecure(t, store)
+	ga := GroupAddress{Main: 1, Middle: 2, Sub: 3}
+	write := NewWriteTelegram(ga, []byte{0x01}).Encode()[2:]
+
+	// Decrypted into a normal write
+	inner.callback(secureTelegram(t, "1.1.10", ga, 501, write))
+	if sink.count() != 1 {
+		t.Fatalf("delivered %d telegrams, want 1", sink.count())
+	}
+	got := sink.telegrams[0]
+	if got.APCI != APCIWrite || got.Source != "1.1.10" || got.Destination != ga || !bytes.Equal(got.Data, []byte{0x01}) {
+		t.Errorf("delivered %v from %s, want write 01 from 1.1.10", got, got.Source)
+	}
+	if store.seqs["1.1.10"] != 501 {
+		t.Errorf("stored sequence = %d, want 501", store.seqs["1.1.10"])
+	}
+
+	// Replays, old numbers from the keyring and forgeries are dropped
+	inner.callback(secureTelegram(t, "1.1.10", ga, 501, write))
+	inner.callback(secureTelegram(t, "1.1.10", ga, 400, write))
+	forged := secureTelegram(t, "1.1.10", ga, 502, write)
+	forged.Data[len(forged.Data)-1] ^= 0xFF
+	inner.callback(forged)
+	if sink.count() != 1 {
+		t.Errorf("delivered %d telegrams after replays and forgery, want 1", sink.count())
+	}
+
+	// No key: passed on undecrypted
+	other := GroupAddress{Main: 9, Middle: 0, Sub: 1}
+	inner.callback(secureTelegram(t, "1.1.10", other, 1, write))
+	// Plain telegrams pass through
+	inner.callback(NewWriteTelegram(other, []byte{0x00}))
+	if sink.count() != 3 || sink.telegrams[1].APCI != APCISecure || sink.telegrams[2].APCI != APCIWrite {
+		t.Errorf("delivered %v, want the undecryptable and plain telegrams passed on", sink.telegrams)
+	}
+
+	st := s.SecureStats()
+	if st.Decrypted != 1 || st.Replayed != 2 || st.Rejected != 1 || st.NoKey != 1 {
+		t.Errorf("SecureStats() = %+v, want 1 decrypted, 2 replayed, 1 rejected, 1 without key", st)
+	}
+
+	// A restart keeps replay protection from the store
+	s2, inner2, sink2 := newTestSecure(t, store)
+	inner2.callback(secureTelegram(t, "1.1.10", ga, 501, write))
+	if sink2.count() != 0 || s2.SecureStats().Replayed != 1 {
+		t.Error("replay accepted after restart")
+	}
+}
+
+func TestNewSecureConnectorInvalid(t *testing.T) {
+	if _, err := NewSecureConnector(context.Background(), newMockConnector(true), SecureOptions{}); !errors.Is(err, ErrSecureFailed) {
+		t.Errorf("connector without sendTelegram error = %v, want ErrSecureFailed", err)
+	}
+	inner := &secureTestConnector{}
+	if _, err := NewSecureConnector(context.Background(), inner, SecureOptions{IndividualAddress: "1.1"}); !errors.Is(err, ErrSecureFailed) {
+		t.Errorf("invalid address error = %v, want ErrSecureFailed", err)
+	}
+}
@@ -0,0 +1,107 @@
+package knx
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Ensure StateCacheStore implements StateStore.
+var _ StateStore = (*StateCacheStore)(nil)
+
+// StateCacheStore persists the bridge state cache in SQLite.
+//
+// The database must have the knx_state_cache table created.
+//
+// Thread Safety: All methods are safe for concurrent use.
+type StateCacheStore struct {
+	db *sql.DB
+}
+
+// NewStateCacheStore creates a state cache store.
+//
+// Parameters:
+//   - db: Database with the knx_state_cache table
+//
+// Returns:
+//   - *StateCacheStore: Ready to use
+func NewStateCacheStore(db *sql.DB) *StateCacheStore {
+	return &StateCacheStore{db: db}
+}
+
+// LoadStates implements StateStore. Values are returned as decoded from
+// JSON (numbers as float64) and not marked stale.
+func (s *StateCacheStore) LoadStates(ctx context.Context) (map[string]map[string]CachedValue, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT device_id, function, value, updated_at FROM knx_state_cache`)
+	if err != nil {
+		return nil, fmt.Errorf("reading state cache: %w", err)
+	}
+	defer rows.Close()
+
+	states := make(map[string]map[string]CachedValue)
+	for rows.Next() {
+		var deviceID, function, value, updatedAt string
+		if err := rows.Scan(&deviceID, &function, &value, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scanning cached state: %w", err)
+		}
+		var cv CachedValue
+		if err := json.Unmarshal([]byte(value), &cv.Value); err != nil {
+			continue // Unreadable value: the bus will provide a new one
+		}
+		cv.UpdatedAt, _ = time.Parse(time.RFC3339Nano, updatedAt) //nolint:errcheck // zero time if malformed
+		if states[deviceID] == nil {
+			states[deviceID] = make(map[string]CachedValue)
+		}
+		states[deviceID][function] = cv
+	}
+	return states, rows.Err()
+}
+
+// SaveStates implements StateStore. The values are written in one
+// transaction.
+func (s *StateCacheStore) SaveStates(ctx context.Context, states map[string]map[string]CachedValue) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning state cache transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // rollback is no-op after commit
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO knx_state_cache (device_id, function, value, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(device_id, function) DO UPDATE SET
+			value = excluded.value,
+			updated_at = excluded.updated_at
+	`)
+	if err != nil {
+		return fmt.Errorf("preparing state cache insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for deviceID, functions := range states {
+		for function, v := range functions {
+			value, err := json.Marshal(v.Value)
+			if err != nil {
+				return fmt.Errorf("encoding cached state of %s/%s: %w", deviceID, function, err)
+			}
+			if _, err := stmt.ExecContext(ctx, deviceID, function, string(value), v.UpdatedAt.UTC().Format(time.RFC3339Nano)); err != nil {
+				return fmt.Errorf("storing cached state: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing state cache: %w", err)
+	}
+	return nil
+}
+
+// DeleteDevice implements StateStore.
+func (s *StateCacheStore) DeleteDevice(ctx context.Context, deviceID string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM knx_state_cache WHERE device_id = ?`, deviceID); err != nil {
+		return fmt.Errorf("deleting cached state: %w", err)
+	}
+	return nil
+}
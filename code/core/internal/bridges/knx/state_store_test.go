@@ -0,0 +1,248 @@
+package knx
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestStateCacheStore(t *testing.T) *StateCacheStore {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	db.SetMaxOpenConns(1) // Each connection to :memory: is a new database
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`
+		CREATE TABLE knx_state_cache (
+			device_id TEXT NOT NULL,
+			function TEXT NOT NULL,
+			value TEXT NOT NULL,
+			updated_at TEXT NOT NULL,
+			PRIMARY KEY (device_id, function)
+		) STRICT;
+	`)
+	if err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	return NewStateCacheStore(db)
+}
+
+func TestStateCacheStore(t *testing.T) {
+	store := newTestStateCacheStore(t)
+	ctx := context.Background()
+	at := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	for _, batch := range []map[string]map[string]CachedValue{
+		{
+			"light": {
+				"switch_status":     {Value: true, UpdatedAt: at},
+				"brightness_status": {Value: uint8(40), UpdatedAt: at},
+			},
+			"sensor": {"temperature": {Value: 21.5, UpdatedAt: at}},
+		},
+		{"light": {"brightness_status": {Value: uint8(60), UpdatedAt: at}}}, // Replaces 40
+	} {
+		if err := store.SaveStates(ctx, batch); err != nil {
+			t.Fatalf("SaveStates() error: %v", err)
+		}
+	}
+	if err := store.DeleteDevice(ctx, "sensor"); err != nil {
+		t.Fatalf("DeleteDevice() error: %v", err)
+	}
+
+	states, err := store.LoadStates(ctx)
+	if err != nil {
+		t.Fatalf("LoadStates() error: %v", err)
+	}
+	if len(states) != 1 || len(states["light"]) != 2 {
+		t.Fatalf("LoadStates() = %+v, want two light functions", states)
+	}
+	if got := states["light"]["brightness_status"]; got.Value != 60.0 || !got.UpdatedAt.Equal(at) || got.Stale {
+		t.Errorf("brightness_status = %+v, want 60 at %v", got, at)
+	}
+	if got := states["light"]["switch_status"]; got.Value != true {
+		t.Errorf("switch_status = %+v, want true", got)
+	}
+}
+
+func TestBridgeRestoresStateCache(t *testing.T) {
+	store := newTestStateCacheStore(t)
+	ctx := context.Background()
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	if err := store.SaveStates(ctx, map[string]map[string]CachedValue{
+		"switch-hall": {"switch_status": {Value: true, UpdatedAt: at}},
+	}); err != nil {
+		t.Fatalf("SaveStates() error: %v", err)
+	}
+
+	// The registry knows the temperature; the store the switch
+	devices := sharedGADevices()
+	devices[0].State = map[string]any{"on": false, "temperature": 21.5}
+	devices[0].StateUpdatedAt = at.Add(-time.Hour)
+
+	registry := &fakeRegistry{devices: devices}
+	mqtt := NewMockMQTTClient()
+	knxd := NewMockConnector()
+	b, err := NewBridge(BridgeOptions{
+		Config:     createTestConfig(),
+		MQTTClient: mqtt,
+		KNXDClient: knxd,
+		Registry:   registry,
+		StateStore: store,
+	})
+	if err != nil {
+		t.Fatalf("NewBridge() error: %v", err)
+	}
+	if err := b.Start(ctx); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer b.Stop()
+
+	cached := b.CachedState("switch-hall")
+	if sw := cached["switch_status"]; sw.Value != true || !sw.Stale || !sw.UpdatedAt.Equal(at) {
+		t.Errorf("switch_status = %+v, want stale true from the store", sw)
+	}
+	if temp := cached["temperature"]; temp.Value != 21.5 || !temp.Stale {
+		t.Errorf("temperature = %+v, want stale 21.5 from the registry", temp)
+	}
+
+	// 9/0/1 and 9/0/2 are shared with logic-hall, which has no cached state
+	statePublished := func(ga string) bool {
+		for _, p := range mqtt.GetPublished() {
+			var msg StateMessage
+			if p.Topic == StateTopic(ga) && json.Unmarshal(p.Payload, &msg) == nil && msg.DeviceID == "switch-hall" {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The bus confirms the restored switch value: no longer stale, not republished
+	mqtt.ClearPublished()
+	knxd.SimulateTelegram(Telegram{Destination: GroupAddress{Main: 9, Middle: 0, Sub: 1}, APCI: APCIResponse, Data: []byte{0x01}})
+	if sw := b.CachedState("switch-hall")["switch_status"]; sw.Stale || sw.Value != true {
+		t.Errorf("switch_status after confirmation = %+v, want fresh true", sw)
+	}
+	if statePublished("9/0/1") {
+		t.Error("confirmed value was republished")
+	}
+	if got := registry.getHealth("switch-hall"); got != "online" {
+		t.Errorf("health after confirmation = %q, want online", got)
+	}
+
+	// A different temperature (DPT 9 decodes to float64) is a change
+	data, err := EncodeDPT9(22.5)
+	if err != nil {
+		t.Fatalf("EncodeDPT9() error: %v", err)
+	}
+	knxd.SimulateTelegram(Telegram{Destination: GroupAddress{Main: 9, Middle: 0, Sub: 2}, APCI: APCIWrite, Data: data})
+	if !statePublished("9/0/2") {
+		t.Error("changed value was not published")
+	}
+
+	// read_state returns the cached values with their stale markers
+	resp := b.handleReadState(RequestMessage{RequestID: "req-1", Action: "read_state", DeviceID: "switch-hall"})
+	cachedResp, ok := resp.Data["cached"].(map[string]CachedValue)
+	if !resp.Success || !ok || cachedResp["temperature"].Value != 22.5 || cachedResp["temperature"].Stale {
+		t.Errorf("read_state response = %+v, want the cached values", resp)
+	}
+
+	// Changes are written in batches, not from the telegram handler;
+	// Stop writes those since the last flush
+	states, err := store.LoadStates(ctx)
+	if err != nil {
+		t.Fatalf("LoadStates() error: %v", err)
+	}
+	if _, ok := states["switch-hall"]["temperature"]; ok {
+		t.Error("temperature stored before a flush")
+	}
+	b.Stop()
+	states, err = store.LoadStates(ctx)
+	if err != nil {
+		t.Fatalf("LoadStates() error: %v", err)
+	}
+	if temp := states["switch-hall"]["temperature"]; temp.Value != 22.5 || temp.UpdatedAt.Before(at) {
+		t.Errorf("stored temperature = %+v, want 22.5 updated now", temp)
+	}
+	if sw := states["switch-hall"]["switch_status"]; !sw.UpdatedAt.After(at) {
+		t.Errorf("stored switch_status = %+v, want confirmed now", sw)
+	}
+}
+
+// failingStateStore is a StateStore whose saves fail while fail is set.
+type failingStateStore struct {
+	mu    sync.Mutex
+	fail  bool
+	saved map[string]map[string]CachedValue
+}
+
+func (s *failingStateStore) LoadStates(context.Context) (map[string]map[string]CachedValue, error) {
+	return nil, nil
+}
+
+func (s *failingStateStore) SaveStates(_ context.Context, states map[string]map[string]CachedValue) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return errors.New("database is locked")
+	}
+	s.saved = states
+	return nil
+}
+
+func (s *failingStateStore) DeleteDevice(context.Context, string) error {
+	return nil
+}
+
+func TestFlushStateCacheRetries(t *testing.T) {
+	store := &failingStateStore{fail: true}
+	b, err := NewBridge(BridgeOptions{
+		Config:     createTestConfig(),
+		MQTTClient: NewMockMQTTClient(),
+		KNXDClient: NewMockConnector(),
+		StateStore: store,
+	})
+	if err != nil {
+		t.Fatalf("NewBridge() error: %v", err)
+	}
+	defer b.Stop()
+
+	b.checkState("light", "switch_status", true)
+	b.checkState("light", "brightness_status", uint8(40))
+	b.flushStateCache(context.Background())
+
+	// A failed flush keeps the values for the next one
+	store.mu.Lock()
+	store.fail = false
+	store.mu.Unlock()
+	b.checkState("light", "brightness_status", uint8(60))
+	b.flushStateCache(context.Background())
+
+	light := store.saved["light"]
+	if len(light) != 2 || light["switch_status"].Value != true || light["brightness_status"].Value != uint8(60) {
+		t.Errorf("saved = %+v, want both light functions with the latest brightness", store.saved)
+	}
+
+	// Nothing changed since: the next flush writes nothing
+	store.saved = nil
+	b.flushStateCache(context.Background())
+	if store.saved != nil {
+		t.Errorf("saved = %+v, want no write", store.saved)
+	}
+}
+
+func TestValuesEqualNumeric(t *testing.T) {
+	if !valuesEqual(uint8(40), 40.0) || !valuesEqual(float32(1.5), 1.5) {
+		t.Error("numbers of different types with the same value are not equal")
+	}
+	if valuesEqual(uint8(40), 41.0) || valuesEqual(true, 1.0) {
+		t.Error("different values are equal")
+	}
+}
@@ -3,6 +3,8 @@ package knx
 import (
 	"encoding/binary"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -35,6 +37,16 @@ const (
 
 	// APCIWrite is a group write (sends value to devices listening on GA).
 	APCIWrite byte = 0x80
+
+	// APCISecure is a KNX Data Secure telegram (A_SecureService, APCI 0x3F1).
+	// The APCI spans both APDU bytes: the TPCI byte carries the high bits
+	// (apciSecureHigh) and this value is the second byte. Data holds the
+	// secured APDU: security control field, sequence number, encrypted
+	// payload and MAC.
+	APCISecure byte = 0xF1
+
+	// apciSecureHigh is the low two bits of the TPCI byte for APCISecure.
+	apciSecureHigh byte = 0x03
 )
 
 // Telegram size constraints.
@@ -104,7 +116,11 @@ func ParseTelegram(data []byte) (Telegram, error) {
 
 	// Extract data
 	var payload []byte
-	if len(data) > 6 { //nolint:mnd // CEMI frame header length
+	if data[4]&apciSecureHigh == apciSecureHigh && data[5] == APCISecure {
+		// Data Secure: everything after the APCI is the secured APDU
+		apci = APCISecure
+		payload = append([]byte{}, data[6:]...) //nolint:mnd // CEMI frame header length
+	} else if len(data) > 6 { //nolint:mnd // CEMI frame header length
 		// Long frame: data bytes follow after the 6-byte header
 		payload = make([]byte, len(data)-6) //nolint:mnd // CEMI frame header length
 		copy(payload, data[6:])
@@ -132,6 +148,24 @@ func formatIndividualAddress(ia uint16) string {
 	return fmt.Sprintf("%d.%d.%d", area, line, device)
 }
 
+// parseIndividualAddress parses an "A.L.D" individual address.
+func parseIndividualAddress(s string) (uint16, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 { //nolint:mnd // area.line.device
+		return 0, fmt.Errorf("individual address %q must be area.line.device", s)
+	}
+	limits := []uint64{0x0F, 0x0F, 0xFF}
+	var values [3]uint64
+	for i, p := range parts {
+		v, err := strconv.ParseUint(p, 10, 8)
+		if err != nil || v > limits[i] {
+			return 0, fmt.Errorf("individual address %q is out of range", s)
+		}
+		values[i] = v
+	}
+	return uint16(values[0]<<12 | values[1]<<8 | values[2]), nil //nolint:gosec // each part range-checked
+}
+
 // Encode encodes a Telegram to knxd wire format for EIB_OPEN_GROUPCON.
 //
 // The output format is suitable for sending via EIB_GROUP_PACKET on a GROUPCON socket:
@@ -146,6 +180,16 @@ func formatIndividualAddress(ia uint16) string {
 // Returns:
 //   - []byte: Encoded telegram ready for knxd
 func (t Telegram) Encode() []byte {
+	if t.APCI == APCISecure {
+		// Secure APDU: GA(2) + [TPCI|0x03, 0xF1] + secured APDU
+		buf := make([]byte, 4+len(t.Data)) //nolint:mnd // knxd group socket header size
+		binary.BigEndian.PutUint16(buf[0:2], t.Destination.ToUint16())
+		buf[2] = apciSecureHigh
+		buf[3] = APCISecure
+		copy(buf[4:], t.Data)
+		return buf
+	}
+
 	// Determine if data fits in APCI byte (small values ≤ 0x3F)
 	smallData := len(t.Data) == 1 && t.Data[0] <= 0x3F
 
@@ -196,6 +240,8 @@ func (t Telegram) String() string {
 		apciStr = "RESPONSE"
 	case APCIWrite:
 		apciStr = "WRITE"
+	case APCISecure:
+		apciStr = "SECURE"
 	}
 
 	return fmt.Sprintf("Telegram{GA:%s, APCI:%s, Data:%X}", t.Destination, apciStr, t.Data)
@@ -234,6 +280,23 @@ func NewReadTelegram(dest GroupAddress) Telegram {
 	}
 }
 
+// NewResponseTelegram creates a new read response telegram.
+//
+// Parameters:
+//   - dest: Group address the read request was sent to
+//   - data: DPT-encoded payload
+//
+// Returns:
+//   - Telegram: Ready to send via knxd
+func NewResponseTelegram(dest GroupAddress, data []byte) Telegram {
+	return Telegram{
+		Destination: dest,
+		APCI:        APCIResponse,
+		Data:        data,
+		Timestamp:   time.Now(),
+	}
+}
+
 // EncodeKNXDMessage wraps a payload in the knxd message format.
 //
 // Format:
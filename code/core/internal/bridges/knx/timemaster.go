@@ -0,0 +1,312 @@
+package knx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Time master constants.
+const (
+	// timeMasterCheckInterval is how often the time master looks for local
+	// midnight and UTC offset changes (DST, site timezone edits) between
+	// regular broadcasts.
+	timeMasterCheckInterval = time.Minute
+
+	// timeMasterSendTimeout bounds sending one round of time telegrams.
+	timeMasterSendTimeout = 5 * time.Second
+)
+
+// TimezoneProvider supplies the site's IANA timezone name.
+// This interface is satisfied by an adapter over the location repository
+// in main.go. An empty name means the site has no timezone configured.
+type TimezoneProvider interface {
+	SiteTimezone(ctx context.Context) (string, error)
+}
+
+// timeTarget is a group address the time master writes to, with its codec.
+type timeTarget struct {
+	ga     GroupAddress
+	dpt    DPT
+	encode func(time.Time) ([]byte, error)
+}
+
+// TimeMaster broadcasts the site's local date and time to the KNX bus.
+//
+// Time is sent on start, every interval, at local midnight, and whenever
+// the site's UTC offset changes (daylight saving transitions or a timezone
+// edit), so clocks on the bus never show the wrong hour for long. Read
+// requests on the configured group addresses are answered with the
+// current time.
+//
+// Thread Safety: All methods are safe for concurrent use.
+type TimeMaster struct {
+	knxd     Connector
+	targets  []timeTarget
+	interval time.Duration
+	timezone TimezoneProvider
+	fallback string // Configured timezone, used when the site has none
+	now      func() time.Time
+	logger   Logger
+
+	// Resolved location and what was last broadcast
+	mu         sync.Mutex
+	loc        *time.Location
+	locName    string
+	lastDate   string
+	lastOffset int
+	sent       bool
+
+	// Shutdown coordination
+	done     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// TimeMasterConfig holds configuration for the time master.
+type TimeMasterConfig struct {
+	// Settings are the time_master settings from the bridge config.
+	Settings TimeMasterSettings
+
+	// KNXDClient sends the time telegrams.
+	KNXDClient Connector
+
+	// Timezone supplies the site timezone. Optional; if nil or empty,
+	// Settings.Timezone is used, then UTC.
+	Timezone TimezoneProvider
+
+	// Logger is optional.
+	Logger Logger
+
+	// Now returns the current time. Optional; defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewTimeMaster creates a new time master.
+//
+// Parameters:
+//   - cfg: Configuration for the time master
+//
+// Returns:
+//   - *TimeMaster: Ready to start (call Start to begin broadcasting)
+//   - error: If a group address is invalid or none is configured
+func NewTimeMaster(cfg TimeMasterConfig) (*TimeMaster, error) {
+	if cfg.KNXDClient == nil {
+		return nil, fmt.Errorf("knxd client is required")
+	}
+
+	var targets []timeTarget
+	for _, t := range []struct {
+		ga     string
+		dpt    DPT
+		encode func(time.Time) ([]byte, error)
+	}{
+		{cfg.Settings.TimeGA, DPTTimeOfDay, func(t time.Time) ([]byte, error) { return EncodeDPT10(t), nil }},
+		{cfg.Settings.DateGA, DPTDate, EncodeDPT11},
+		{cfg.Settings.DateTimeGA, DPTDateTime, EncodeDPT19},
+	} {
+		if t.ga == "" {
+			continue
+		}
+		ga, err := ParseGroupAddress(t.ga)
+		if err != nil {
+			return nil, fmt.Errorf("time master %s address: %w", t.dpt, err)
+		}
+		targets = append(targets, timeTarget{ga: ga, dpt: t.dpt, encode: t.encode})
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("time master requires at least one group address")
+	}
+
+	interval := time.Duration(cfg.Settings.Interval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	now := cfg.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	return &TimeMaster{
+		knxd:     cfg.KNXDClient,
+		targets:  targets,
+		interval: interval,
+		timezone: cfg.Timezone,
+		fallback: cfg.Settings.Timezone,
+		now:      now,
+		logger:   cfg.Logger,
+		loc:      time.UTC,
+		locName:  "UTC",
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// Start begins broadcasting. The time is sent immediately.
+func (m *TimeMaster) Start(ctx context.Context) {
+	m.wg.Add(1)
+	go m.broadcastLoop(ctx)
+}
+
+// Stop stops broadcasting. Safe to call multiple times.
+func (m *TimeMaster) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.done)
+		m.wg.Wait()
+	})
+}
+
+// Broadcast writes the current local date and time to every configured
+// group address.
+//
+// Returns:
+//   - error: Joined errors for any telegrams that could not be sent
+func (m *TimeMaster) Broadcast(ctx context.Context) error {
+	now := m.localNow(ctx)
+
+	sendCtx, cancel := context.WithTimeout(ctx, timeMasterSendTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, t := range m.targets {
+		data, err := t.encode(now)
+		if err == nil {
+			err = m.knxd.Send(sendCtx, t.ga, data)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", t.dpt, t.ga, err))
+		}
+	}
+
+	_, offset := now.Zone()
+	m.mu.Lock()
+	m.lastDate = now.Format(time.DateOnly)
+	m.lastOffset = offset
+	m.sent = true
+	m.mu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// HandleRead answers a read request if ga is one of the time master's
+// group addresses.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - ga: Group address of the read request
+//
+// Returns:
+//   - bool: True if ga belongs to the time master (whether or not the
+//     response could be sent)
+func (m *TimeMaster) HandleRead(ctx context.Context, ga GroupAddress) bool {
+	for _, t := range m.targets {
+		if t.ga != ga {
+			continue
+		}
+		data, err := t.encode(m.localNow(ctx))
+		if err == nil {
+			sendCtx, cancel := context.WithTimeout(ctx, timeMasterSendTimeout)
+			err = m.knxd.SendResponse(sendCtx, ga, data)
+			cancel()
+		}
+		if err != nil {
+			m.logError("failed to answer time read", err, "ga", ga.String())
+		}
+		return true
+	}
+	return false
+}
+
+// broadcastLoop sends the time on start, every interval, and whenever the
+// local date or UTC offset has changed since the last broadcast.
+func (m *TimeMaster) broadcastLoop(ctx context.Context) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	check := time.NewTicker(timeMasterCheckInterval)
+	defer check.Stop()
+
+	m.broadcast(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.done:
+			return
+		case <-ticker.C:
+			m.broadcast(ctx)
+		case <-check.C:
+			if m.changed(ctx) {
+				m.broadcast(ctx)
+			}
+		}
+	}
+}
+
+// broadcast sends the time, logging any failure.
+func (m *TimeMaster) broadcast(ctx context.Context) {
+	if err := m.Broadcast(ctx); err != nil {
+		m.logError("failed to broadcast time", err)
+	}
+}
+
+// changed reports whether the local date or UTC offset differs from the
+// last broadcast.
+func (m *TimeMaster) changed(ctx context.Context) bool {
+	now := m.localNow(ctx)
+	_, offset := now.Zone()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !m.sent || offset != m.lastOffset || now.Format(time.DateOnly) != m.lastDate
+}
+
+// localNow returns the current time in the site's timezone.
+func (m *TimeMaster) localNow(ctx context.Context) time.Time {
+	return m.now().In(m.location(ctx))
+}
+
+// location resolves the site timezone: the provider's if set, otherwise
+// the configured fallback, otherwise UTC. The last good location is kept
+// if the provider fails or returns an unknown name.
+func (m *TimeMaster) location(ctx context.Context) *time.Location {
+	name := m.fallback
+	if m.timezone != nil {
+		tz, err := m.timezone.SiteTimezone(ctx)
+		if err != nil {
+			m.logError("failed to get site timezone", err)
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			return m.loc
+		}
+		if tz != "" {
+			name = tz
+		}
+	}
+	if name == "" {
+		name = "UTC"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if name == m.locName {
+		return m.loc
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		m.logError("unknown site timezone", err, "timezone", name)
+		return m.loc
+	}
+	m.loc, m.locName = loc, name
+	return loc
+}
+
+// logError logs an error if logger is set.
+func (m *TimeMaster) logError(msg string, err error, keysAndValues ...any) {
+	if m.logger != nil {
+		m.logger.Error(msg, append([]any{"error", err}, keysAndValues...)...)
+	}
+}
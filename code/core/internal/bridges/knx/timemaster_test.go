@@ -0,0 +1,260 @@
+package knx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// staticTimezone is a TimezoneProvider returning a fixed timezone.
+type staticTimezone struct {
+	mu   sync.Mutex
+	name string
+	err  error
+}
+
+func (s *staticTimezone) SiteTimezone(_ context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.name, s.err
+}
+
+// fakeClock is a settable clock for the time master.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+func requireLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	return loc
+}
+
+func newTestTimeMaster(t *testing.T, knxd Connector, tz TimezoneProvider, clock *fakeClock, settings TimeMasterSettings) *TimeMaster {
+	t.Helper()
+	tm, err := NewTimeMaster(TimeMasterConfig{
+		Settings:   settings,
+		KNXDClient: knxd,
+		Timezone:   tz,
+		Now:        clock.Now,
+	})
+	if err != nil {
+		t.Fatalf("NewTimeMaster() error: %v", err)
+	}
+	return tm
+}
+
+func TestNewTimeMasterRequiresAddress(t *testing.T) {
+	if _, err := NewTimeMaster(TimeMasterConfig{KNXDClient: NewMockConnector()}); err == nil {
+		t.Error("NewTimeMaster() with no group addresses: expected error")
+	}
+	_, err := NewTimeMaster(TimeMasterConfig{
+		Settings:   TimeMasterSettings{TimeGA: "bad"},
+		KNXDClient: NewMockConnector(),
+	})
+	if err == nil {
+		t.Error("NewTimeMaster() with invalid GA: expected error")
+	}
+}
+
+func TestTimeMasterBroadcast(t *testing.T) {
+	requireLocation(t, "Europe/London")
+	knxd := NewMockConnector()
+	// 2026-07-05 17:00:00 UTC = 18:00:00 BST, a Sunday
+	clock := &fakeClock{now: time.Date(2026, 7, 5, 17, 0, 0, 0, time.UTC)}
+	tm := newTestTimeMaster(t, knxd, &staticTimezone{name: "Europe/London"}, clock, TimeMasterSettings{
+		TimeGA:     "0/0/1",
+		DateGA:     "0/0/2",
+		DateTimeGA: "0/0/3",
+	})
+
+	if err := tm.Broadcast(context.Background()); err != nil {
+		t.Fatalf("Broadcast() error: %v", err)
+	}
+
+	want := map[GroupAddress][]byte{
+		{Main: 0, Middle: 0, Sub: 1}: {7<<5 | 18, 0, 0},
+		{Main: 0, Middle: 0, Sub: 2}: {5, 7, 26},
+		{Main: 0, Middle: 0, Sub: 3}: {126, 7, 5, 7<<5 | 18, 0, 0, 0x21, 0x00},
+	}
+	sent := knxd.GetSentTelegrams()
+	if len(sent) != len(want) {
+		t.Fatalf("sent %d telegrams, want %d", len(sent), len(want))
+	}
+	for _, s := range sent {
+		if !bytes.Equal(s.Data, want[s.GA]) {
+			t.Errorf("%s = % X, want % X", s.GA, s.Data, want[s.GA])
+		}
+	}
+}
+
+func TestTimeMasterTimezoneFallback(t *testing.T) {
+	requireLocation(t, "America/New_York")
+	clock := &fakeClock{now: time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)}
+	settings := TimeMasterSettings{TimeGA: "0/0/1", Timezone: "America/New_York"}
+
+	tests := []struct {
+		name     string
+		tz       TimezoneProvider
+		wantHour byte
+	}{
+		{"no provider uses config", nil, 7},
+		{"site without timezone uses config", &staticTimezone{}, 7},
+		{"site timezone wins", &staticTimezone{name: "UTC"}, 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			knxd := NewMockConnector()
+			tm := newTestTimeMaster(t, knxd, tt.tz, clock, settings)
+			if err := tm.Broadcast(context.Background()); err != nil {
+				t.Fatalf("Broadcast() error: %v", err)
+			}
+			if got := knxd.GetSentTelegrams()[0].Data[0] & 0x1F; got != tt.wantHour {
+				t.Errorf("hour = %d, want %d", got, tt.wantHour)
+			}
+		})
+	}
+}
+
+func TestTimeMasterKeepsLastTimezoneOnError(t *testing.T) {
+	requireLocation(t, "Europe/Berlin")
+	knxd := NewMockConnector()
+	tz := &staticTimezone{name: "Europe/Berlin"}
+	clock := &fakeClock{now: time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)}
+	tm := newTestTimeMaster(t, knxd, tz, clock, TimeMasterSettings{TimeGA: "0/0/1"})
+
+	if err := tm.Broadcast(context.Background()); err != nil {
+		t.Fatalf("Broadcast() error: %v", err)
+	}
+	tz.mu.Lock()
+	tz.err = errors.New("database locked")
+	tz.mu.Unlock()
+	if err := tm.Broadcast(context.Background()); err != nil {
+		t.Fatalf("Broadcast() error: %v", err)
+	}
+
+	for i, s := range knxd.GetSentTelegrams() {
+		if hour := s.Data[0] & 0x1F; hour != 13 {
+			t.Errorf("telegram %d hour = %d, want 13 (CET)", i, hour)
+		}
+	}
+}
+
+func TestTimeMasterDetectsDSTAndMidnight(t *testing.T) {
+	requireLocation(t, "Europe/London")
+	tz := &staticTimezone{name: "Europe/London"}
+	// BST starts 2026-03-29 01:00 UTC (clocks go 01:00 GMT → 02:00 BST)
+	clock := &fakeClock{now: time.Date(2026, 3, 29, 0, 58, 0, 0, time.UTC)}
+	tm := newTestTimeMaster(t, NewMockConnector(), tz, clock, TimeMasterSettings{TimeGA: "0/0/1"})
+	ctx := context.Background()
+
+	if !tm.changed(ctx) {
+		t.Error("changed() before first broadcast = false, want true")
+	}
+	if err := tm.Broadcast(ctx); err != nil {
+		t.Fatalf("Broadcast() error: %v", err)
+	}
+
+	clock.Set(time.Date(2026, 3, 29, 0, 59, 0, 0, time.UTC))
+	if tm.changed(ctx) {
+		t.Error("changed() within the same offset and day = true, want false")
+	}
+
+	clock.Set(time.Date(2026, 3, 29, 1, 0, 0, 0, time.UTC))
+	if !tm.changed(ctx) {
+		t.Error("changed() after DST start = false, want true")
+	}
+	if err := tm.Broadcast(ctx); err != nil {
+		t.Fatalf("Broadcast() error: %v", err)
+	}
+
+	clock.Set(time.Date(2026, 3, 29, 22, 59, 0, 0, time.UTC)) // 23:59 BST
+	if tm.changed(ctx) {
+		t.Error("changed() before midnight = true, want false")
+	}
+	clock.Set(time.Date(2026, 3, 29, 23, 0, 0, 0, time.UTC)) // 00:00 BST
+	if !tm.changed(ctx) {
+		t.Error("changed() after local midnight = false, want true")
+	}
+}
+
+func TestTimeMasterHandleRead(t *testing.T) {
+	knxd := NewMockConnector()
+	clock := &fakeClock{now: time.Date(2026, 10, 16, 8, 15, 30, 0, time.UTC)}
+	tm := newTestTimeMaster(t, knxd, nil, clock, TimeMasterSettings{TimeGA: "0/0/1", DateGA: "0/0/2"})
+
+	if !tm.HandleRead(context.Background(), GroupAddress{Main: 0, Middle: 0, Sub: 2}) {
+		t.Fatal("HandleRead(date GA) = false, want true")
+	}
+	if tm.HandleRead(context.Background(), GroupAddress{Main: 1, Middle: 0, Sub: 1}) {
+		t.Error("HandleRead(unrelated GA) = true, want false")
+	}
+
+	responses := knxd.GetResponses()
+	if len(responses) != 1 {
+		t.Fatalf("sent %d responses, want 1", len(responses))
+	}
+	if want := []byte{16, 10, 26}; !bytes.Equal(responses[0].Data, want) {
+		t.Errorf("response = % X, want % X", responses[0].Data, want)
+	}
+	if len(knxd.GetSentTelegrams()) != 0 {
+		t.Error("HandleRead() sent a write telegram, want response only")
+	}
+}
+
+func TestBridgeTimeMaster(t *testing.T) {
+	mqtt := NewMockMQTTClient()
+	knxd := NewMockConnector()
+	cfg := createTestConfig()
+	cfg.TimeMaster = TimeMasterSettings{Enabled: true, TimeGA: "0/0/1", Interval: 3600}
+
+	b := createTestBridge(t, BridgeOptions{
+		Config:     cfg,
+		MQTTClient: mqtt,
+		KNXDClient: knxd,
+	})
+	if err := b.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer b.Stop()
+
+	// Time is broadcast on startup
+	timeGA := GroupAddress{Main: 0, Middle: 0, Sub: 1}
+	deadline := time.Now().Add(time.Second)
+	for {
+		sent := knxd.GetSentTelegrams()
+		if len(sent) > 0 && sent[0].GA == timeGA {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no time telegram sent on startup")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Read requests on the time GA are answered
+	knxd.SimulateTelegram(Telegram{Destination: timeGA, APCI: APCIRead})
+	responses := knxd.GetResponses()
+	if len(responses) != 1 || responses[0].GA != timeGA || len(responses[0].Data) != dpt10Len {
+		t.Errorf("responses = %+v, want one DPT10 response on %s", responses, timeGA)
+	}
+}
@@ -0,0 +1,812 @@
+package knx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default timeouts and intervals for KNXnet/IP tunnelling.
+const (
+	// defaultHeartbeatInterval is how often the connection state is checked.
+	// The gateway drops a tunnel after 120 seconds without a heartbeat.
+	defaultHeartbeatInterval = 60 * time.Second
+
+	// defaultHeartbeatTimeout is CONNECTIONSTATE_REQUEST_TIMEOUT.
+	defaultHeartbeatTimeout = 10 * time.Second
+
+	// defaultTunnelAckTimeout is TUNNELLING_REQUEST_TIMEOUT.
+	defaultTunnelAckTimeout = time.Second
+
+	// heartbeatAttempts is how many connection state requests may go
+	// unanswered before the tunnel is considered lost.
+	heartbeatAttempts = 3
+
+	// tunnelSendAttempts is the original tunnelling request plus one repeat.
+	tunnelSendAttempts = 2
+
+	// tunnelDisconnectTimeout bounds the disconnect on Close.
+	tunnelDisconnectTimeout = time.Second
+)
+
+// TunnelConfig holds KNXnet/IP tunnelling connection configuration.
+type TunnelConfig struct {
+	// Gateway is the KNX/IP interface address, "host" or "host:port".
+	// Default port: 3671.
+	Gateway string
+
+	// LocalAddress is the local IPv4 address to bind to and advertise to
+	// the gateway. If empty, the client binds to all interfaces and asks
+	// the gateway to reply to the datagram's source (NAT mode).
+	LocalAddress string
+
+	// ConnectTimeout is the maximum time to wait for a connect response.
+	// Default: 10 seconds.
+	ConnectTimeout time.Duration
+
+	// HeartbeatInterval is how often the connection state is checked.
+	// Default: 60 seconds.
+	HeartbeatInterval time.Duration
+
+	// HeartbeatTimeout is how long to wait for a connection state response.
+	// Default: 10 seconds.
+	HeartbeatTimeout time.Duration
+
+	// AckTimeout is how long to wait for a tunnelling ack before repeating.
+	// Default: 1 second.
+	AckTimeout time.Duration
+
+	// ReconnectInterval is the initial delay between reconnection attempts.
+	// Default: 5 seconds.
+	ReconnectInterval time.Duration
+}
+
+// Ensure TunnelClient implements Connector.
+var _ Connector = (*TunnelClient)(nil)
+
+// tunnelAck is a received TUNNELLING_ACK.
+type tunnelAck struct {
+	channel uint8
+	seq     uint8
+	status  uint8
+}
+
+// connectResponse is a received CONNECT_RESPONSE.
+type connectResponse struct {
+	channel  uint8
+	status   uint8
+	dataAddr *net.UDPAddr
+	address  uint16
+}
+
+// TunnelClient connects to a KNX/IP interface with KNXnet/IP tunnelling,
+// without knxd.
+//
+// Thread Safety:
+//   - All methods are safe for concurrent use.
+//   - Telegram callbacks are invoked from a bounded worker pool.
+//
+// Connection Supervision:
+//   - A connection state request is sent every HeartbeatInterval; after
+//     three unanswered requests the tunnel is considered lost.
+//   - Each tunnelling request is repeated once if not acked within
+//     AckTimeout; a second miss drops the tunnel.
+//   - A lost tunnel is re-established with exponential backoff starting at
+//     ReconnectInterval up to maxReconnectInterval, until Close is called.
+type TunnelClient struct {
+	cfg     TunnelConfig
+	conn    *net.UDPConn
+	gateway *net.UDPAddr
+	natMode bool
+
+	// Tunnel state, reset on every (re)connect
+	connMu    sync.RWMutex
+	connected bool
+	channel   uint8
+	dataAddr  *net.UDPAddr
+	address   uint16 // Individual address assigned by the gateway
+	sendSeq   uint8
+	recvSeq   uint8
+
+	// sendMu serialises tunnelling requests: one may be outstanding
+	sendMu sync.Mutex
+
+	// Responses routed from the receive loop to waiting goroutines
+	connectResp chan connectResponse
+	stateResp   chan uint8
+	acks        chan tunnelAck
+	lost        chan struct{}
+
+	reconnecting atomic.Bool
+
+	dispatcher *telegramDispatcher
+
+	// Shutdown coordination
+	done *closeOnce
+	wg   sync.WaitGroup
+
+	// Logger (optional)
+	logger   Logger
+	loggerMu sync.RWMutex
+
+	// Statistics
+	telegramsTx     atomic.Uint64
+	telegramsRx     atomic.Uint64
+	errorsTotal     atomic.Uint64
+	reconnectsTotal atomic.Uint64
+	lastActivity    atomic.Int64 // Unix timestamp
+}
+
+// ConnectTunnel opens a KNXnet/IP tunnelling connection to a KNX/IP
+// interface.
+//
+// Parameters:
+//   - ctx: Context for cancellation (used for the initial connection)
+//   - cfg: Tunnel configuration
+//
+// Returns:
+//   - *TunnelClient: Connected client ready for use
+//   - error: If the gateway cannot be reached or refuses the connection
+func ConnectTunnel(ctx context.Context, cfg TunnelConfig) (*TunnelClient, error) {
+	applyTunnelDefaults(&cfg)
+
+	gateway, err := resolveGateway(cfg.Gateway)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConnectionFailed, err)
+	}
+
+	var local *net.UDPAddr
+	if cfg.LocalAddress != "" {
+		ip := net.ParseIP(cfg.LocalAddress)
+		if ip == nil || ip.To4() == nil {
+			return nil, fmt.Errorf("%w: invalid local address %q", ErrConnectionFailed, cfg.LocalAddress)
+		}
+		local = &net.UDPAddr{IP: ip}
+	}
+	conn, err := net.ListenUDP("udp4", local)
+	if err != nil {
+		return nil, fmt.Errorf("%w: listen: %w", ErrConnectionFailed, err)
+	}
+
+	c := &TunnelClient{
+		cfg:         cfg,
+		conn:        conn,
+		gateway:     gateway,
+		natMode:     local == nil,
+		connectResp: make(chan connectResponse, 1),
+		stateResp:   make(chan uint8, 1),
+		acks:        make(chan tunnelAck, 1),
+		lost:        make(chan struct{}, 1),
+		dispatcher:  newTelegramDispatcher(),
+		done:        newCloseOnce(),
+	}
+	c.lastActivity.Store(time.Now().Unix())
+
+	c.wg.Add(1)
+	go c.receiveLoop()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := c.connect(ctx); err != nil {
+		c.done.Close()
+		conn.Close()
+		c.wg.Wait()
+		return nil, fmt.Errorf("%w: %w", ErrConnectionFailed, err)
+	}
+
+	c.dispatcher.start(c.done.Done(), &c.wg, func(err error) { c.logError("telegram callback failed", err) })
+	c.wg.Add(1)
+	go c.superviseLoop()
+
+	return c, nil
+}
+
+// applyTunnelDefaults fills in zero-valued timeouts.
+func applyTunnelDefaults(cfg *TunnelConfig) {
+	if cfg.ConnectTimeout == 0 {
+		cfg.ConnectTimeout = defaultConnectTimeout
+	}
+	if cfg.HeartbeatInterval == 0 {
+		cfg.HeartbeatInterval = defaultHeartbeatInterval
+	}
+	if cfg.HeartbeatTimeout == 0 {
+		cfg.HeartbeatTimeout = defaultHeartbeatTimeout
+	}
+	if cfg.AckTimeout == 0 {
+		cfg.AckTimeout = defaultTunnelAckTimeout
+	}
+	if cfg.ReconnectInterval == 0 {
+		cfg.ReconnectInterval = defaultReconnectInterval
+	}
+}
+
+// resolveGateway resolves "host" or "host:port" to a UDP address.
+func resolveGateway(gateway string) (*net.UDPAddr, error) {
+	if gateway == "" {
+		return nil, errors.New("gateway address is required")
+	}
+	if _, _, err := net.SplitHostPort(gateway); err != nil {
+		gateway = net.JoinHostPort(gateway, strconv.Itoa(knxnetipDefaultPort))
+	}
+	addr, err := net.ResolveUDPAddr("udp4", gateway)
+	if err != nil {
+		return nil, fmt.Errorf("resolve gateway: %w", err)
+	}
+	return addr, nil
+}
+
+// connect sends a CONNECT_REQUEST and waits for the gateway to assign a
+// tunnel channel. Sequence counters start at zero on every connection.
+func (c *TunnelClient) connect(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.ConnectTimeout)
+	defer cancel()
+
+	hpai := c.localHPAI()
+	body := make([]byte, 0, 2*hpaiLen+criTunnelLen)
+	body = append(body, hpai...) // control endpoint
+	body = append(body, hpai...) // data endpoint
+	body = append(body, criTunnelLen, connTypeTunnel, tunnelLinkLayer, 0x00)
+
+	drain(c.connectResp)
+	if err := c.write(c.gateway, serviceConnectRequest, body); err != nil {
+		return err
+	}
+
+	var resp connectResponse
+	select {
+	case resp = <-c.connectResp:
+	case <-ctx.Done():
+		return fmt.Errorf("connect response: %w", ErrTimeout)
+	case <-c.done.Done():
+		return ErrNotConnected
+	}
+	if resp.status != statusNoError {
+		return fmt.Errorf("gateway refused connection: %s", tunnelStatusText(resp.status))
+	}
+
+	dataAddr := resp.dataAddr
+	if c.natMode || dataAddr == nil || dataAddr.IP.IsUnspecified() || dataAddr.Port == 0 {
+		dataAddr = c.gateway
+	}
+
+	c.sendMu.Lock()
+	c.connMu.Lock()
+	c.connected = true
+	c.channel = resp.channel
+	c.dataAddr = dataAddr
+	c.address = resp.address
+	c.sendSeq = 0
+	c.recvSeq = 0
+	c.connMu.Unlock()
+	c.sendMu.Unlock()
+
+	c.lastActivity.Store(time.Now().Unix())
+	c.logInfo("tunnel connected",
+		"gateway", c.gateway.String(),
+		"channel", resp.channel,
+		"address", formatIndividualAddress(resp.address))
+	return nil
+}
+
+// localHPAI returns the endpoint advertised to the gateway.
+func (c *TunnelClient) localHPAI() []byte {
+	if c.natMode {
+		return encodeHPAI(nil)
+	}
+	local, _ := c.conn.LocalAddr().(*net.UDPAddr)
+	return encodeHPAI(local)
+}
+
+// receiveLoop reads datagrams and routes them by service type.
+func (c *TunnelClient) receiveLoop() {
+	defer c.wg.Done()
+
+	buf := make([]byte, knxnetipBufferSize)
+	for {
+		n, _, err := c.conn.ReadFromUDP(buf)
+		if err != nil {
+			if c.isClosed() {
+				return
+			}
+			c.errorsTotal.Add(1)
+			c.logError("tunnel read failed", err)
+			continue
+		}
+
+		service, body, err := parseKNXnetIP(buf[:n])
+		if err != nil {
+			c.errorsTotal.Add(1)
+			c.logError("invalid KNXnet/IP frame", err)
+			continue
+		}
+		c.handleFrame(service, body)
+	}
+}
+
+// handleFrame processes one received KNXnet/IP frame.
+func (c *TunnelClient) handleFrame(service uint16, body []byte) {
+	switch service {
+	case serviceConnectResponse:
+		if resp, ok := parseConnectResponse(body); ok {
+			offer(c.connectResp, resp)
+		}
+	case serviceConnectionStateResponse:
+		if len(body) >= 2 && c.isChannel(body[0]) {
+			offer(c.stateResp, body[1])
+		}
+	case serviceTunnellingAck:
+		if len(body) >= connHeaderLen {
+			offer(c.acks, tunnelAck{channel: body[1], seq: body[2], status: body[3]})
+		}
+	case serviceTunnellingRequest:
+		c.handleTunnellingRequest(body)
+	case serviceDisconnectRequest:
+		if len(body) >= 1 && c.isChannel(body[0]) {
+			c.writeLogged(c.gateway, serviceDisconnectResponse, []byte{body[0], statusNoError})
+			c.connectionLost("gateway closed the tunnel")
+		}
+	case serviceDisconnectResponse:
+		// Reply to our own disconnect; nothing to do
+	}
+}
+
+// parseConnectResponse decodes a CONNECT_RESPONSE body.
+func parseConnectResponse(body []byte) (connectResponse, bool) {
+	if len(body) < 2 { //nolint:mnd // channel + status
+		return connectResponse{}, false
+	}
+	resp := connectResponse{channel: body[0], status: body[1]}
+	if resp.status != statusNoError {
+		return resp, true
+	}
+	if len(body) < 2+hpaiLen+crdTunnelLen {
+		return connectResponse{}, false
+	}
+	dataAddr, err := parseHPAI(body[2 : 2+hpaiLen])
+	if err != nil {
+		return connectResponse{}, false
+	}
+	crd := body[2+hpaiLen:]
+	resp.dataAddr = dataAddr
+	resp.address = uint16(crd[2])<<8 | uint16(crd[3])
+	return resp, true
+}
+
+// handleTunnellingRequest acks and processes a frame from the gateway.
+// A repeat of the previous sequence number is acked again but not
+// processed; any other unexpected sequence number is discarded unacked.
+func (c *TunnelClient) handleTunnellingRequest(body []byte) {
+	if len(body) < connHeaderLen {
+		return
+	}
+	channel, seq := body[1], body[2]
+
+	c.connMu.Lock()
+	if !c.connected || channel != c.channel {
+		c.connMu.Unlock()
+		return
+	}
+	expected := c.recvSeq
+	if seq == expected {
+		c.recvSeq++
+	}
+	dataAddr := c.dataAddr
+	c.connMu.Unlock()
+
+	switch seq {
+	case expected:
+	case expected - 1:
+		c.writeLogged(dataAddr, serviceTunnellingAck, []byte{connHeaderLen, channel, seq, statusNoError})
+		return
+	default:
+		return
+	}
+	c.writeLogged(dataAddr, serviceTunnellingAck, []byte{connHeaderLen, channel, seq, statusNoError})
+	c.lastActivity.Store(time.Now().Unix())
+
+	code, t, isGroup, err := parseCEMI(body[connHeaderLen:])
+	if err != nil {
+		c.errorsTotal.Add(1)
+		c.logError("invalid cEMI frame", err)
+		return
+	}
+	if code != cemiLDataInd || !isGroup {
+		return // Confirmations and individually addressed frames
+	}
+
+	c.telegramsRx.Add(1)
+	if !c.dispatcher.dispatch(t) {
+		c.errorsTotal.Add(1)
+		c.logError("callback queue full, dropping telegram", nil)
+	}
+}
+
+// superviseLoop sends heartbeats and re-establishes a lost tunnel.
+func (c *TunnelClient) superviseLoop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done.Done():
+			return
+		case <-ticker.C:
+			if c.IsConnected() && !c.heartbeat() {
+				c.connectionLost("heartbeat not answered")
+			}
+		case <-c.lost:
+			c.reconnect()
+		}
+	}
+}
+
+// heartbeat sends connection state requests until one is answered.
+// Returns false if the gateway reports an error or never answers.
+func (c *TunnelClient) heartbeat() bool {
+	c.connMu.RLock()
+	channel := c.channel
+	c.connMu.RUnlock()
+
+	body := append([]byte{channel, 0x00}, c.localHPAI()...)
+	for range heartbeatAttempts {
+		drain(c.stateResp)
+		c.writeLogged(c.gateway, serviceConnectionStateRequest, body)
+
+		select {
+		case status := <-c.stateResp:
+			if status != statusNoError {
+				c.logError("connection state error", errors.New(tunnelStatusText(status)))
+				return false
+			}
+			return true
+		case <-time.After(c.cfg.HeartbeatTimeout):
+		case <-c.done.Done():
+			return true
+		}
+	}
+	return false
+}
+
+// connectionLost marks the tunnel down and wakes the supervisor to
+// reconnect. Safe to call from any goroutine, any number of times.
+func (c *TunnelClient) connectionLost(reason string) {
+	c.connMu.Lock()
+	wasConnected := c.connected
+	c.connected = false
+	c.connMu.Unlock()
+
+	if wasConnected {
+		c.errorsTotal.Add(1)
+		c.logInfo("tunnel lost, will attempt reconnection", "reason", reason)
+		offer(c.lost, struct{}{})
+	}
+}
+
+// reconnect re-establishes the tunnel with exponential backoff.
+// Returns when connected or when Close is called.
+func (c *TunnelClient) reconnect() {
+	c.reconnecting.Store(true)
+	defer c.reconnecting.Store(false)
+
+	// Release the old channel in case the gateway still holds it
+	c.connMu.RLock()
+	channel := c.channel
+	c.connMu.RUnlock()
+	c.writeLogged(c.gateway, serviceDisconnectRequest, append([]byte{channel, 0x00}, c.localHPAI()...))
+
+	backoff := c.cfg.ReconnectInterval
+	for attempt := 1; ; attempt++ {
+		if c.isClosed() {
+			return
+		}
+		c.logInfo("attempting tunnel reconnection", "attempt", attempt)
+
+		err := c.connect(context.Background())
+		if err == nil {
+			c.reconnectsTotal.Add(1)
+			return
+		}
+		c.errorsTotal.Add(1)
+		c.logError("tunnel reconnect failed", err)
+
+		select {
+		case <-c.done.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(time.Duration(float64(backoff)*1.5), maxReconnectInterval) //nolint:mnd // backoff factor
+	}
+}
+
+// Close disconnects the tunnel and releases the socket.
+// Safe to call multiple times.
+//
+// Returns:
+//   - error: nil (closing is best-effort)
+func (c *TunnelClient) Close() error {
+	if c.isClosed() {
+		return nil
+	}
+
+	c.connMu.Lock()
+	wasConnected := c.connected
+	channel := c.channel
+	c.connected = false
+	c.connMu.Unlock()
+
+	if wasConnected {
+		// Best effort: tell the gateway to free the channel
+		_ = c.conn.SetWriteDeadline(time.Now().Add(tunnelDisconnectTimeout))
+		c.writeLogged(c.gateway, serviceDisconnectRequest, append([]byte{channel, 0x00}, c.localHPAI()...))
+	}
+
+	c.done.Close()
+	c.conn.Close()
+	c.wg.Wait()
+
+	c.logInfo("tunnel closed")
+	return nil
+}
+
+// Send sends a group write telegram to the KNX bus.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - ga: Target group address
+//   - data: DPT-encoded payload
+//
+// Returns:
+//   - error: If sending fails or the tunnel is not connected
+func (c *TunnelClient) Send(ctx context.Context, ga GroupAddress, data []byte) error {
+	return c.sendTelegram(ctx, NewWriteTelegram(ga, data))
+}
+
+// SendRead sends a group read request to the KNX bus.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - ga: Target group address to read
+//
+// Returns:
+//   - error: If sending fails or the tunnel is not connected
+func (c *TunnelClient) SendRead(ctx context.Context, ga GroupAddress) error {
+	return c.sendTelegram(ctx, NewReadTelegram(ga))
+}
+
+// SendResponse answers a group read request on the KNX bus.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - ga: Group address that was read
+//   - data: DPT-encoded value
+//
+// Returns:
+//   - error: If sending fails or the tunnel is not connected
+func (c *TunnelClient) SendResponse(ctx context.Context, ga GroupAddress, data []byte) error {
+	return c.sendTelegram(ctx, NewResponseTelegram(ga, data))
+}
+
+// sendTelegram sends a tunnelling request and waits for the gateway's ack,
+// repeating once. The tunnel is dropped if the repeat is not acked either.
+func (c *TunnelClient) sendTelegram(ctx context.Context, t Telegram) error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	c.connMu.RLock()
+	connected, channel, seq, dataAddr := c.connected, c.channel, c.sendSeq, c.dataAddr
+	c.connMu.RUnlock()
+	if !connected {
+		return ErrNotConnected
+	}
+
+	body := append([]byte{connHeaderLen, channel, seq, 0x00}, encodeCEMI(cemiLDataReq, t)...)
+	for range tunnelSendAttempts {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrTelegramFailed, ctx.Err())
+		default:
+		}
+
+		drain(c.acks)
+		if err := c.write(dataAddr, serviceTunnellingRequest, body); err != nil {
+			c.errorsTotal.Add(1)
+			return fmt.Errorf("%w: %w", ErrTelegramFailed, err)
+		}
+
+		status, acked, err := c.waitAck(ctx, channel, seq)
+		if err != nil {
+			return err
+		}
+		if !acked {
+			continue // Repeat once
+		}
+		if status != statusNoError {
+			c.errorsTotal.Add(1)
+			return fmt.Errorf("%w: gateway rejected frame: %s", ErrTelegramFailed, tunnelStatusText(status))
+		}
+
+		c.connMu.Lock()
+		if c.connected && c.channel == channel {
+			c.sendSeq++
+		}
+		c.connMu.Unlock()
+		c.telegramsTx.Add(1)
+		c.lastActivity.Store(time.Now().Unix())
+		return nil
+	}
+
+	c.connectionLost("tunnelling request not acked")
+	return fmt.Errorf("%w: no ack from gateway: %w", ErrTelegramFailed, ErrTimeout)
+}
+
+// waitAck waits up to AckTimeout for the ack of (channel, seq).
+// Acks for other frames are ignored.
+func (c *TunnelClient) waitAck(ctx context.Context, channel, seq uint8) (status uint8, acked bool, err error) {
+	timer := time.NewTimer(c.cfg.AckTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case ack := <-c.acks:
+			if ack.channel == channel && ack.seq == seq {
+				return ack.status, true, nil
+			}
+		case <-timer.C:
+			return 0, false, nil
+		case <-ctx.Done():
+			return 0, false, fmt.Errorf("%w: %w", ErrTelegramFailed, ctx.Err())
+		case <-c.done.Done():
+			return 0, false, ErrNotConnected
+		}
+	}
+}
+
+// write sends one KNXnet/IP frame.
+func (c *TunnelClient) write(addr *net.UDPAddr, service uint16, body []byte) error {
+	if _, err := c.conn.WriteToUDP(encodeKNXnetIP(service, body), addr); err != nil {
+		return fmt.Errorf("write %s: %w", addr, err)
+	}
+	return nil
+}
+
+// writeLogged sends one KNXnet/IP frame, logging any failure.
+func (c *TunnelClient) writeLogged(addr *net.UDPAddr, service uint16, body []byte) {
+	if err := c.write(addr, service, body); err != nil && !c.isClosed() {
+		c.errorsTotal.Add(1)
+		c.logError("tunnel write failed", err)
+	}
+}
+
+// isChannel reports whether channel is the current tunnel's channel.
+func (c *TunnelClient) isChannel(channel uint8) bool {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+	return c.channel == channel
+}
+
+// isClosed returns true if the client has been closed.
+func (c *TunnelClient) isClosed() bool {
+	select {
+	case <-c.done.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// SetOnTelegram sets the callback for received telegrams.
+//
+// Parameters:
+//   - callback: Function to call when a telegram is received
+func (c *TunnelClient) SetOnTelegram(callback func(Telegram)) {
+	c.dispatcher.setCallback(callback)
+}
+
+// SetLogger sets the logger for this client.
+func (c *TunnelClient) SetLogger(logger Logger) {
+	c.loggerMu.Lock()
+	c.logger = logger
+	c.loggerMu.Unlock()
+}
+
+// IsConnected returns true if the tunnel is established.
+func (c *TunnelClient) IsConnected() bool {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+	return c.connected
+}
+
+// IndividualAddress returns the individual address the gateway assigned to
+// the tunnel (e.g. "1.1.250").
+func (c *TunnelClient) IndividualAddress() string {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+	return formatIndividualAddress(c.address)
+}
+
+// Stats returns current operational statistics.
+func (c *TunnelClient) Stats() KNXDStats {
+	return KNXDStats{
+		TelegramsTx:      c.telegramsTx.Load(),
+		TelegramsRx:      c.telegramsRx.Load(),
+		TelegramsDropped: c.dispatcher.dropped.Load(),
+		ErrorsTotal:      c.errorsTotal.Load(),
+		ReconnectsTotal:  c.reconnectsTotal.Load(),
+		LastActivity:     time.Unix(c.lastActivity.Load(), 0),
+		Connected:        c.IsConnected(),
+		Reconnecting:     c.reconnecting.Load(),
+	}
+}
+
+// logInfo logs an info message if logger is set.
+func (c *TunnelClient) logInfo(msg string, keysAndValues ...any) {
+	c.loggerMu.RLock()
+	logger := c.logger
+	c.loggerMu.RUnlock()
+
+	if logger != nil {
+		logger.Info(msg, keysAndValues...)
+	}
+}
+
+// logError logs an error message if logger is set.
+func (c *TunnelClient) logError(msg string, err error) {
+	c.loggerMu.RLock()
+	logger := c.logger
+	c.loggerMu.RUnlock()
+
+	if logger != nil {
+		logger.Error(msg, "error", err)
+	}
+}
+
+// tunnelStatusText names a KNXnet/IP status code.
+func tunnelStatusText(status uint8) string {
+	switch status {
+	case statusNoError:
+		return "no error"
+	case statusConnectionID:
+		return "unknown connection id"
+	case statusConnectionType:
+		return "connection type not supported"
+	case statusConnectionOption:
+		return "connection option not supported"
+	case statusNoMoreConnections:
+		return "no more connections (all tunnels in use)"
+	case statusDataConnection:
+		return "data connection error"
+	case statusKNXConnection:
+		return "KNX connection error"
+	case statusTunnellingLayer:
+		return "tunnelling layer not supported"
+	default:
+		return fmt.Sprintf("status 0x%02X", status)
+	}
+}
+
+// offer sends v on a buffered channel, dropping it if the channel is full.
+func offer[T any](ch chan T, v T) {
+	select {
+	case ch <- v:
+	default:
+	}
+}
+
+// drain discards any value waiting on a buffered channel.
+func drain[T any](ch chan T) {
+	for {
+		select {
+		case <-ch:
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,418 @@
+package knx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeGateway is a local UDP stand-in for a KNX/IP interface's tunnelling
+// server. It assigns channel 7, 8, ... and individual address 1.1.250.
+type fakeGateway struct {
+	t    *testing.T
+	conn *net.UDPConn
+
+	mu              sync.Mutex
+	client          *net.UDPAddr
+	channel         uint8
+	connects        int
+	disconnects     int
+	refuseStatus    uint8 // Non-zero: refuse connect requests with this status
+	dropAcks        int   // Tunnelling requests to leave unacked
+	silentHeartbeat bool
+	requests        []tunnelRequest
+	sendSeq         uint8
+
+	clientAcks chan tunnelAck
+}
+
+// tunnelRequest is a tunnelling request received by the fake gateway.
+type tunnelRequest struct {
+	seq  uint8
+	cemi []byte
+}
+
+func newFakeGateway(t *testing.T) *fakeGateway {
+	t.Helper()
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP() error: %v", err)
+	}
+	g := &fakeGateway{t: t, conn: conn, channel: 6, clientAcks: make(chan tunnelAck, 16)}
+	go g.serve()
+	t.Cleanup(func() { conn.Close() })
+	return g
+}
+
+func (g *fakeGateway) addr() string {
+	return g.conn.LocalAddr().String()
+}
+
+func (g *fakeGateway) serve() {
+	buf := make([]byte, knxnetipBufferSize)
+	for {
+		n, from, err := g.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		service, body, err := parseKNXnetIP(buf[:n])
+		if err != nil {
+			continue
+		}
+		g.handle(from, service, append([]byte(nil), body...))
+	}
+}
+
+func (g *fakeGateway) handle(from *net.UDPAddr, service uint16, body []byte) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch service {
+	case serviceConnectRequest:
+		g.connects++
+		if g.refuseStatus != statusNoError {
+			g.send(from, serviceConnectResponse, []byte{0, g.refuseStatus})
+			return
+		}
+		g.channel++
+		g.client = from
+		g.sendSeq = 0
+		local, _ := g.conn.LocalAddr().(*net.UDPAddr)
+		resp := append([]byte{g.channel, statusNoError}, encodeHPAI(local)...)
+		resp = append(resp, crdTunnelLen, connTypeTunnel, 0x11, 0xFA) // 1.1.250
+		g.send(from, serviceConnectResponse, resp)
+	case serviceConnectionStateRequest:
+		if !g.silentHeartbeat {
+			g.send(from, serviceConnectionStateResponse, []byte{body[0], statusNoError})
+		}
+	case serviceTunnellingRequest:
+		g.requests = append(g.requests, tunnelRequest{seq: body[2], cemi: body[connHeaderLen:]})
+		if g.dropAcks > 0 {
+			g.dropAcks--
+			return
+		}
+		g.send(from, serviceTunnellingAck, []byte{connHeaderLen, body[1], body[2], statusNoError})
+	case serviceTunnellingAck:
+		g.clientAcks <- tunnelAck{channel: body[1], seq: body[2], status: body[3]}
+	case serviceDisconnectRequest:
+		g.disconnects++
+		g.send(from, serviceDisconnectResponse, []byte{body[0], statusNoError})
+	}
+}
+
+// send writes a frame; callers hold g.mu.
+func (g *fakeGateway) send(to *net.UDPAddr, service uint16, body []byte) {
+	if _, err := g.conn.WriteToUDP(encodeKNXnetIP(service, body), to); err != nil {
+		g.t.Errorf("fake gateway write: %v", err)
+	}
+}
+
+// indicate sends a bus telegram to the client with the given sequence number.
+func (g *fakeGateway) indicate(seq uint8, t Telegram) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	cemi := encodeCEMI(cemiLDataInd, t)
+	cemi[4], cemi[5] = 0x11, 0x05 // source 1.1.5
+	g.send(g.client, serviceTunnellingRequest, append([]byte{connHeaderLen, g.channel, seq, 0}, cemi...))
+}
+
+// disconnect asks the client to close the tunnel.
+func (g *fakeGateway) disconnect() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.send(g.client, serviceDisconnectRequest, append([]byte{g.channel, 0}, encodeHPAI(nil)...))
+}
+
+func (g *fakeGateway) set(f func(g *fakeGateway)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	f(g)
+}
+
+func (g *fakeGateway) snapshot() (connects, disconnects int, requests []tunnelRequest) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.connects, g.disconnects, append([]tunnelRequest(nil), g.requests...)
+}
+
+func connectTestTunnel(t *testing.T, g *fakeGateway, cfg TunnelConfig) *TunnelClient {
+	t.Helper()
+	cfg.Gateway = g.addr()
+	if cfg.AckTimeout == 0 {
+		cfg.AckTimeout = 50 * time.Millisecond
+	}
+	if cfg.ReconnectInterval == 0 {
+		cfg.ReconnectInterval = 10 * time.Millisecond
+	}
+	cfg.ConnectTimeout = time.Second
+	c, err := ConnectTunnel(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("ConnectTunnel() error: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCEMIRoundTrip(t *testing.T) {
+	ga := GroupAddress{Main: 1, Middle: 2, Sub: 3}
+	tests := []struct {
+		name string
+		t    Telegram
+		want []byte
+	}{
+		{"short write", NewWriteTelegram(ga, []byte{0x01}),
+			[]byte{cemiLDataReq, 0, cemiCtrl1, cemiCtrl2Group, 0, 0, 0x0A, 0x03, 0x01, 0x00, 0x81}},
+		{"long write", NewWriteTelegram(ga, []byte{0x0C, 0x1A}),
+			[]byte{cemiLDataReq, 0, cemiCtrl1, cemiCtrl2Group, 0, 0, 0x0A, 0x03, 0x03, 0x00, 0x80, 0x0C, 0x1A}},
+		{"read", NewReadTelegram(ga),
+			[]byte{cemiLDataReq, 0, cemiCtrl1, cemiCtrl2Group, 0, 0, 0x0A, 0x03, 0x01, 0x00, 0x00}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame := encodeCEMI(cemiLDataReq, tt.t)
+			if !bytes.Equal(frame, tt.want) {
+				t.Fatalf("encodeCEMI() = % X, want % X", frame, tt.want)
+			}
+			code, got, isGroup, err := parseCEMI(frame)
+			if err != nil || !isGroup || code != cemiLDataReq {
+				t.Fatalf("parseCEMI() = %#x, %v, %v", code, isGroup, err)
+			}
+			if got.Destination != ga || got.APCI != tt.t.APCI || !bytes.Equal(got.Data, tt.t.Data) {
+				t.Errorf("parseCEMI() = %v, want %v", got, tt.t)
+			}
+		})
+	}
+
+	// Additional info is skipped; individually addressed frames are not group
+	withInfo := []byte{cemiLDataInd, 2, 0xAA, 0xBB, cemiCtrl1, 0x60, 0x11, 0x05, 0x11, 0x06, 0x01, 0x00, 0x81}
+	if _, _, isGroup, err := parseCEMI(withInfo); err != nil || isGroup {
+		t.Errorf("parseCEMI(individual) isGroup = %v, err = %v; want false, nil", isGroup, err)
+	}
+	if _, _, _, err := parseCEMI([]byte{cemiLDataInd, 0, cemiCtrl1}); !errors.Is(err, ErrInvalidTelegram) {
+		t.Errorf("parseCEMI(short) error = %v, want ErrInvalidTelegram", err)
+	}
+}
+
+func TestParseKNXnetIP(t *testing.T) {
+	frame := encodeKNXnetIP(serviceTunnellingAck, []byte{4, 7, 1, 0})
+	service, body, err := parseKNXnetIP(frame)
+	if err != nil || service != serviceTunnellingAck || !bytes.Equal(body, []byte{4, 7, 1, 0}) {
+		t.Errorf("parseKNXnetIP() = %#x, % X, %v", service, body, err)
+	}
+
+	bad := append([]byte(nil), frame...)
+	bad[1] = 0x20
+	if _, _, err := parseKNXnetIP(bad); !errors.Is(err, ErrInvalidTelegram) {
+		t.Errorf("parseKNXnetIP(version 2) error = %v, want ErrInvalidTelegram", err)
+	}
+	if _, _, err := parseKNXnetIP(frame[:8]); !errors.Is(err, ErrInvalidTelegram) {
+		t.Errorf("parseKNXnetIP(truncated) error = %v, want ErrInvalidTelegram", err)
+	}
+}
+
+func TestTunnelConnectAndSend(t *testing.T) {
+	g := newFakeGateway(t)
+	c := connectTestTunnel(t, g, TunnelConfig{})
+
+	if !c.IsConnected() {
+		t.Fatal("IsConnected() = false after ConnectTunnel")
+	}
+	if got := c.IndividualAddress(); got != "1.1.250" {
+		t.Errorf("IndividualAddress() = %q, want 1.1.250", got)
+	}
+
+	ga := GroupAddress{Main: 1, Middle: 2, Sub: 3}
+	if err := c.Send(context.Background(), ga, []byte{0x01}); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	if err := c.SendRead(context.Background(), ga); err != nil {
+		t.Fatalf("SendRead() error: %v", err)
+	}
+
+	_, _, requests := g.snapshot()
+	if len(requests) != 2 {
+		t.Fatalf("gateway received %d tunnelling requests, want 2", len(requests))
+	}
+	for i, r := range requests {
+		if r.seq != uint8(i) {
+			t.Errorf("request %d seq = %d, want %d", i, r.seq, i)
+		}
+	}
+	if want := encodeCEMI(cemiLDataReq, NewWriteTelegram(ga, []byte{0x01})); !bytes.Equal(requests[0].cemi, want) {
+		t.Errorf("cEMI = % X, want % X", requests[0].cemi, want)
+	}
+	if stats := c.Stats(); stats.TelegramsTx != 2 || !stats.Connected {
+		t.Errorf("Stats() = %+v, want 2 sent and connected", stats)
+	}
+
+	c.Close()
+	waitFor(t, "disconnect request", func() bool {
+		_, disconnects, _ := g.snapshot()
+		return disconnects == 1
+	})
+	if err := c.Send(context.Background(), ga, []byte{0x00}); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Send() after Close error = %v, want ErrNotConnected", err)
+	}
+}
+
+func TestTunnelReceive(t *testing.T) {
+	g := newFakeGateway(t)
+	c := connectTestTunnel(t, g, TunnelConfig{})
+
+	var mu sync.Mutex
+	var received []Telegram
+	c.SetOnTelegram(func(tg Telegram) {
+		mu.Lock()
+		received = append(received, tg)
+		mu.Unlock()
+	})
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received)
+	}
+
+	ga := GroupAddress{Main: 6, Middle: 0, Sub: 1}
+	g.indicate(0, NewWriteTelegram(ga, []byte{0x0C, 0x1A}))
+	waitFor(t, "telegram", func() bool { return count() == 1 })
+	if ack := <-g.clientAcks; ack.seq != 0 {
+		t.Errorf("ack seq = %d, want 0", ack.seq)
+	}
+
+	mu.Lock()
+	tg := received[0]
+	mu.Unlock()
+	if tg.Destination != ga || tg.Source != "1.1.5" || !bytes.Equal(tg.Data, []byte{0x0C, 0x1A}) {
+		t.Errorf("received %v from %s, want write to %s from 1.1.5", tg, tg.Source, ga)
+	}
+
+	// A repeat of seq 0 is acked again but not delivered twice
+	g.indicate(0, NewWriteTelegram(ga, []byte{0x0C, 0x1A}))
+	if ack := <-g.clientAcks; ack.seq != 0 {
+		t.Errorf("repeat ack seq = %d, want 0", ack.seq)
+	}
+
+	// An out-of-sequence frame is discarded without an ack
+	g.indicate(5, NewWriteTelegram(ga, []byte{0x00, 0x00}))
+	select {
+	case ack := <-g.clientAcks:
+		t.Errorf("out-of-sequence frame acked: %+v", ack)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	g.indicate(1, NewWriteTelegram(ga, []byte{0x0C, 0x1B}))
+	waitFor(t, "second telegram", func() bool { return count() == 2 })
+	if stats := c.Stats(); stats.TelegramsRx != 2 {
+		t.Errorf("TelegramsRx = %d, want 2", stats.TelegramsRx)
+	}
+}
+
+func TestTunnelRepeatAndReconnect(t *testing.T) {
+	g := newFakeGateway(t)
+	c := connectTestTunnel(t, g, TunnelConfig{})
+	ga := GroupAddress{Main: 1, Middle: 2, Sub: 3}
+
+	// One missing ack: the request is repeated with the same sequence number
+	g.set(func(g *fakeGateway) { g.dropAcks = 1 })
+	if err := c.Send(context.Background(), ga, []byte{0x01}); err != nil {
+		t.Fatalf("Send() with one lost ack error: %v", err)
+	}
+	_, _, requests := g.snapshot()
+	if len(requests) != 2 || requests[0].seq != 0 || requests[1].seq != 0 {
+		t.Fatalf("requests = %+v, want seq 0 sent twice", requests)
+	}
+
+	// Two missing acks: the tunnel is dropped and re-established
+	g.set(func(g *fakeGateway) { g.dropAcks = 2 })
+	err := c.Send(context.Background(), ga, []byte{0x00})
+	if !errors.Is(err, ErrTelegramFailed) {
+		t.Fatalf("Send() with two lost acks error = %v, want ErrTelegramFailed", err)
+	}
+	waitFor(t, "reconnect", func() bool {
+		connects, _, _ := g.snapshot()
+		return connects == 2 && c.IsConnected()
+	})
+	if got := c.Stats().ReconnectsTotal; got != 1 {
+		t.Errorf("ReconnectsTotal = %d, want 1", got)
+	}
+
+	// Sequence numbers restart on the new channel
+	if err := c.Send(context.Background(), ga, []byte{0x01}); err != nil {
+		t.Fatalf("Send() after reconnect error: %v", err)
+	}
+	_, _, requests = g.snapshot()
+	if last := requests[len(requests)-1]; last.seq != 0 {
+		t.Errorf("seq after reconnect = %d, want 0", last.seq)
+	}
+}
+
+func TestTunnelHeartbeatLoss(t *testing.T) {
+	g := newFakeGateway(t)
+	c := connectTestTunnel(t, g, TunnelConfig{
+		HeartbeatInterval: 20 * time.Millisecond,
+		HeartbeatTimeout:  10 * time.Millisecond,
+	})
+
+	g.set(func(g *fakeGateway) { g.silentHeartbeat = true })
+	waitFor(t, "heartbeat loss", func() bool {
+		connects, _, _ := g.snapshot()
+		return connects >= 2
+	})
+
+	g.set(func(g *fakeGateway) { g.silentHeartbeat = false })
+	waitFor(t, "reconnect", c.IsConnected)
+}
+
+func TestTunnelGatewayDisconnect(t *testing.T) {
+	g := newFakeGateway(t)
+	c := connectTestTunnel(t, g, TunnelConfig{})
+
+	g.disconnect()
+	waitFor(t, "reconnect after gateway disconnect", func() bool {
+		connects, _, _ := g.snapshot()
+		return connects == 2 && c.IsConnected()
+	})
+}
+
+func TestTunnelConnectRefused(t *testing.T) {
+	g := newFakeGateway(t)
+	g.set(func(g *fakeGateway) { g.refuseStatus = statusNoMoreConnections })
+
+	_, err := ConnectTunnel(context.Background(), TunnelConfig{Gateway: g.addr(), ConnectTimeout: time.Second})
+	if !errors.Is(err, ErrConnectionFailed) || !strings.Contains(err.Error(), "no more connections") {
+		t.Errorf("ConnectTunnel() error = %v, want refused with no more connections", err)
+	}
+}
+
+func TestTunnelConnectTimeout(t *testing.T) {
+	// A bound socket that never answers
+	silent, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP() error: %v", err)
+	}
+	defer silent.Close()
+
+	_, err = ConnectTunnel(context.Background(), TunnelConfig{
+		Gateway:        silent.LocalAddr().String(),
+		ConnectTimeout: 50 * time.Millisecond,
+	})
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("ConnectTunnel() error = %v, want ErrTimeout", err)
+	}
+}
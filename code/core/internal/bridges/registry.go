@@ -0,0 +1,363 @@
+package bridges
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nerrad567/gray-logic-core/internal/device"
+)
+
+// Logger defines the logging interface used by the Registry.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// noopLogger is a logger that does nothing.
+type noopLogger struct{}
+
+func (noopLogger) Debug(string, ...any) {}
+func (noopLogger) Info(string, ...any)  {}
+func (noopLogger) Warn(string, ...any)  {}
+func (noopLogger) Error(string, ...any) {}
+
+// DeviceRegistry is the interface the registry needs from the device package.
+// *device.Registry satisfies it.
+type DeviceRegistry interface {
+	GetDevicesByProtocol(ctx context.Context, protocol device.Protocol) ([]device.Device, error)
+	SetDeviceHealth(ctx context.Context, id string, status device.HealthStatus) error
+}
+
+// WSHub is the interface for broadcasting WebSocket events.
+type WSHub interface {
+	Broadcast(channel string, payload any)
+}
+
+// Status is a bridge's reported operational status.
+type Status string
+
+// Status values. All but StatusOffline are reported by bridges themselves;
+// StatusOffline also comes from the bridge's MQTT last will, or is set by
+// Core when a bridge stops reporting.
+const (
+	StatusHealthy   Status = "healthy"
+	StatusDegraded  Status = "degraded"
+	StatusUnhealthy Status = "unhealthy"
+	StatusOffline   Status = "offline"
+	StatusStarting  Status = "starting"
+	StatusStopping  Status = "stopping"
+)
+
+// Down reports whether a bridge in this status is not serving its devices.
+func (s Status) Down() bool {
+	return s == StatusOffline || s == StatusStopping
+}
+
+const (
+	// DefaultStaleAfter is how long a bridge may go without a health report
+	// before it is considered offline (three missed 30-second reports).
+	DefaultStaleAfter = 90 * time.Second
+
+	// reasonStale is the reason recorded when Core marks a silent bridge offline.
+	reasonStale = "no_health_report"
+
+	// healthTopicPrefix precedes the protocol in graylogic/health/{protocol}.
+	healthTopicPrefix = "graylogic/health/"
+)
+
+// Connection describes a bridge's link to its protocol bus.
+type Connection struct {
+	Status         string     `json:"status"`
+	Address        string     `json:"address,omitempty"`
+	ConnectedSince *time.Time `json:"connected_since,omitempty"`
+}
+
+// Statistics are a bridge's reported message counters.
+type Statistics struct {
+	MessagesReceived uint64 `json:"messages_received"`
+	MessagesSent     uint64 `json:"messages_sent"`
+	Errors           uint64 `json:"errors"`
+}
+
+// Bridge is the last known health of a protocol bridge.
+type Bridge struct {
+	ID             string      `json:"id"`
+	Protocol       string      `json:"protocol"`
+	Status         Status      `json:"status"`
+	Reason         string      `json:"reason,omitempty"`
+	Version        string      `json:"version,omitempty"`
+	UptimeSeconds  int64       `json:"uptime_seconds"`
+	DevicesManaged int         `json:"devices_managed"`
+	Connection     *Connection `json:"connection,omitempty"`
+	Statistics     *Statistics `json:"statistics,omitempty"`
+	ReportedAt     time.Time   `json:"reported_at"`
+	LastSeen       time.Time   `json:"last_seen"`
+	StatusSince    time.Time   `json:"status_since"`
+}
+
+// healthMessage is the health report a bridge publishes on
+// graylogic/health/{protocol} (retained, and as its MQTT last will).
+type healthMessage struct {
+	Bridge         string      `json:"bridge"`
+	Timestamp      time.Time   `json:"timestamp"`
+	Status         Status      `json:"status"`
+	Version        string      `json:"version"`
+	UptimeSeconds  int64       `json:"uptime_seconds"`
+	Connection     *Connection `json:"connection,omitempty"`
+	Statistics     *Statistics `json:"statistics,omitempty"`
+	DevicesManaged int         `json:"devices_managed"`
+	Reason         string      `json:"reason,omitempty"`
+}
+
+// Registry tracks the health of protocol bridges from their health reports.
+//
+// When a bridge goes down (offline or stopping, or silent for longer than
+// the stale timeout) the devices on its protocol are marked
+// device.HealthStatusUnknown. Every status change is broadcast as
+// "bridge.status". Devices return to online as the bridge reports
+// their traffic again.
+//
+// Thread Safety: All public methods are safe for concurrent use.
+type Registry struct {
+	devices    DeviceRegistry
+	hub        WSHub
+	logger     Logger
+	staleAfter time.Duration
+	now        func() time.Time
+
+	mu      sync.RWMutex
+	bridges map[string]*Bridge
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewRegistry creates a new bridge registry.
+//
+// Parameters:
+//   - devices: Device registry whose devices are marked unknown when their bridge goes down (may be nil)
+//   - hub: WebSocket hub for bridge.status events (may be nil)
+//   - logger: Logger instance (may be nil)
+func NewRegistry(devices DeviceRegistry, hub WSHub, logger Logger) *Registry {
+	if logger == nil {
+		logger = noopLogger{}
+	}
+	return &Registry{
+		devices:    devices,
+		hub:        hub,
+		logger:     logger,
+		staleAfter: DefaultStaleAfter,
+		now:        time.Now,
+		bridges:    make(map[string]*Bridge),
+	}
+}
+
+// SetStaleAfter sets how long a bridge may stay silent before it is marked
+// offline. Must be called before Start.
+func (r *Registry) SetStaleAfter(d time.Duration) {
+	if d > 0 {
+		r.staleAfter = d
+	}
+}
+
+// Start begins checking for bridges that have stopped reporting.
+func (r *Registry) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	r.mu.Lock()
+	r.cancel = cancel
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.staleAfter / 3) //nolint:mnd // check three times per stale window
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.CheckStale(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the staleness check.
+func (r *Registry) Stop() {
+	r.mu.Lock()
+	cancel := r.cancel
+	r.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	r.wg.Wait()
+}
+
+// HandleHealth records a bridge health report received on
+// graylogic/health/{protocol}.
+func (r *Registry) HandleHealth(topic string, payload []byte) error {
+	protocol := strings.TrimPrefix(topic, healthTopicPrefix)
+	if protocol == topic || protocol == "" || strings.Contains(protocol, "/") {
+		return fmt.Errorf("unexpected health topic %q", topic)
+	}
+
+	var msg healthMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("parsing health report: %w", err)
+	}
+	if msg.Status == "" {
+		return fmt.Errorf("health report from %q has no status", protocol)
+	}
+
+	id := msg.Bridge
+	if id == "" {
+		id = protocol
+	}
+	now := r.now().UTC()
+
+	r.mu.Lock()
+	b, ok := r.bridges[id]
+	if !ok {
+		b = &Bridge{ID: id, StatusSince: now}
+		r.bridges[id] = b
+	}
+	previous := b.Status
+	b.Protocol = protocol
+	b.Reason = msg.Reason
+	b.ReportedAt = msg.Timestamp
+	b.LastSeen = now
+	if !msg.Status.Down() {
+		// The last will carries no details; keep the last reported ones.
+		b.Version = msg.Version
+		b.UptimeSeconds = msg.UptimeSeconds
+		b.DevicesManaged = msg.DevicesManaged
+		b.Connection = msg.Connection
+		b.Statistics = msg.Statistics
+	}
+	changed := r.setStatus(b, msg.Status, now)
+	snapshot := *b
+	r.mu.Unlock()
+
+	if changed {
+		r.statusChanged(context.Background(), snapshot, previous)
+	}
+	return nil
+}
+
+// CheckStale marks bridges offline that have not reported within the
+// stale timeout. Called periodically once started.
+func (r *Registry) CheckStale(ctx context.Context) {
+	now := r.now().UTC()
+
+	type change struct {
+		bridge   Bridge
+		previous Status
+	}
+	var changes []change
+
+	r.mu.Lock()
+	for _, b := range r.bridges {
+		if b.Status.Down() || now.Sub(b.LastSeen) < r.staleAfter {
+			continue
+		}
+		previous := b.Status
+		b.Reason = reasonStale
+		r.setStatus(b, StatusOffline, now)
+		changes = append(changes, change{bridge: *b, previous: previous})
+	}
+	r.mu.Unlock()
+
+	for _, c := range changes {
+		r.statusChanged(ctx, c.bridge, c.previous)
+	}
+}
+
+// List returns every known bridge, ordered by ID.
+func (r *Registry) List() []Bridge {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]Bridge, 0, len(r.bridges))
+	for _, b := range r.bridges {
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// Get returns a bridge by ID.
+func (r *Registry) Get(id string) (Bridge, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	b, ok := r.bridges[id]
+	if !ok {
+		return Bridge{}, false
+	}
+	return *b, true
+}
+
+// setStatus updates a bridge's status and reports whether it changed.
+// Caller must hold r.mu.
+func (r *Registry) setStatus(b *Bridge, status Status, now time.Time) bool {
+	if b.Status == status {
+		return false
+	}
+	b.Status = status
+	b.StatusSince = now
+	return true
+}
+
+// statusChanged logs and broadcasts a status change, marking the bridge's
+// devices unknown when it has gone down.
+func (r *Registry) statusChanged(ctx context.Context, b Bridge, previous Status) {
+	affected := 0
+	if b.Status.Down() && !previous.Down() {
+		r.logger.Warn("bridge offline", "bridge", b.ID, "protocol", b.Protocol, "status", b.Status, "reason", b.Reason)
+		affected = r.markDevicesUnknown(ctx, b.Protocol)
+	} else {
+		r.logger.Info("bridge status changed", "bridge", b.ID, "protocol", b.Protocol, "status", b.Status, "previous", previous)
+	}
+
+	if r.hub != nil {
+		r.hub.Broadcast("bridge.status", map[string]any{
+			"bridge_id":        b.ID,
+			"protocol":         b.Protocol,
+			"status":           b.Status,
+			"previous_status":  previous,
+			"reason":           b.Reason,
+			"devices_affected": affected,
+		})
+	}
+}
+
+// markDevicesUnknown sets the health of every device on the protocol that
+// is not already unknown. Returns the number of devices changed.
+func (r *Registry) markDevicesUnknown(ctx context.Context, protocol string) int {
+	if r.devices == nil {
+		return 0
+	}
+	devices, err := r.devices.GetDevicesByProtocol(ctx, device.Protocol(protocol))
+	if err != nil {
+		r.logger.Error("failed to list bridge devices", "protocol", protocol, "error", err)
+		return 0
+	}
+
+	affected := 0
+	for i := range devices {
+		if devices[i].HealthStatus == device.HealthStatusUnknown {
+			continue
+		}
+		if err := r.devices.SetDeviceHealth(ctx, devices[i].ID, device.HealthStatusUnknown); err != nil {
+			r.logger.Warn("failed to mark device health unknown", "device_id", devices[i].ID, "error", err)
+			continue
+		}
+		affected++
+	}
+	return affected
+}
@@ -0,0 +1,206 @@
+package bridges
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nerrad567/gray-logic-core/internal/device"
+)
+
+type mockDevices struct {
+	mu      sync.Mutex
+	devices []device.Device
+}
+
+func (m *mockDevices) GetDevicesByProtocol(_ context.Context, protocol device.Protocol) ([]device.Device, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []device.Device
+	for _, d := range m.devices {
+		if d.Protocol == protocol {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockDevices) SetDeviceHealth(_ context.Context, id string, status device.HealthStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.devices {
+		if m.devices[i].ID == id {
+			m.devices[i].HealthStatus = status
+		}
+	}
+	return nil
+}
+
+func (m *mockDevices) health(id string) device.HealthStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, d := range m.devices {
+		if d.ID == id {
+			return d.HealthStatus
+		}
+	}
+	return ""
+}
+
+type broadcast struct {
+	channel string
+	payload map[string]any
+}
+
+type mockHub struct {
+	mu         sync.Mutex
+	broadcasts []broadcast
+}
+
+func (h *mockHub) Broadcast(channel string, payload any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	p, _ := payload.(map[string]any)
+	h.broadcasts = append(h.broadcasts, broadcast{channel: channel, payload: p})
+}
+
+func (h *mockHub) get() []broadcast {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]broadcast(nil), h.broadcasts...)
+}
+
+func newTestDevices() *mockDevices {
+	return &mockDevices{devices: []device.Device{
+		{ID: "light-01", Protocol: device.ProtocolKNX, HealthStatus: device.HealthStatusOnline},
+		{ID: "light-02", Protocol: device.ProtocolKNX, HealthStatus: device.HealthStatusOnline},
+		{ID: "dimmer-01", Protocol: device.ProtocolDALI, HealthStatus: device.HealthStatusOnline},
+	}}
+}
+
+const healthyReport = `{
+	"bridge": "knx-bridge-01", "timestamp": "2026-03-10T12:00:00Z", "status": "healthy",
+	"version": "1.0.0", "uptime_seconds": 3600, "devices_managed": 2,
+	"connection": {"status": "connected", "address": "localhost:6720"},
+	"statistics": {"messages_received": 10, "messages_sent": 4, "errors": 0}
+}`
+
+func TestRegistry_HandleHealth(t *testing.T) {
+	devices := newTestDevices()
+	hub := &mockHub{}
+	r := NewRegistry(devices, hub, nil)
+
+	if err := r.HandleHealth("graylogic/health/knx", []byte(healthyReport)); err != nil {
+		t.Fatalf("HandleHealth: %v", err)
+	}
+
+	b, ok := r.Get("knx-bridge-01")
+	if !ok {
+		t.Fatal("bridge not registered")
+	}
+	if b.Protocol != "knx" || b.Status != StatusHealthy || b.Version != "1.0.0" ||
+		b.Statistics == nil || b.Statistics.MessagesReceived != 10 || b.LastSeen.IsZero() {
+		t.Errorf("Get() = %+v", b)
+	}
+	if got := hub.get(); len(got) != 1 || got[0].payload["status"] != StatusHealthy {
+		t.Errorf("broadcasts = %+v", got)
+	}
+
+	// Same status again: no new event.
+	if err := r.HandleHealth("graylogic/health/knx", []byte(healthyReport)); err != nil {
+		t.Fatalf("HandleHealth: %v", err)
+	}
+	if n := len(hub.get()); n != 1 {
+		t.Errorf("%d broadcasts after repeated report, want 1", n)
+	}
+
+	// Last will: devices go unknown, details are kept.
+	lwt := `{"bridge": "knx-bridge-01", "timestamp": "2026-03-10T12:01:00Z", "status": "offline", "reason": "unexpected_disconnect"}`
+	if err := r.HandleHealth("graylogic/health/knx", []byte(lwt)); err != nil {
+		t.Fatalf("HandleHealth: %v", err)
+	}
+	b, _ = r.Get("knx-bridge-01")
+	if b.Status != StatusOffline || b.Reason != "unexpected_disconnect" || b.Version != "1.0.0" {
+		t.Errorf("after LWT Get() = %+v", b)
+	}
+	if devices.health("light-01") != device.HealthStatusUnknown || devices.health("light-02") != device.HealthStatusUnknown {
+		t.Error("KNX devices not marked unknown")
+	}
+	if devices.health("dimmer-01") != device.HealthStatusOnline {
+		t.Error("DALI device marked unknown")
+	}
+	got := hub.get()
+	if len(got) != 2 || got[1].channel != "bridge.status" ||
+		got[1].payload["previous_status"] != StatusHealthy || got[1].payload["devices_affected"] != 2 {
+		t.Errorf("broadcasts = %+v", got)
+	}
+}
+
+func TestRegistry_HandleHealthErrors(t *testing.T) {
+	r := NewRegistry(nil, nil, nil)
+	tests := []struct {
+		name    string
+		topic   string
+		payload string
+	}{
+		{"wrong topic", "graylogic/state/knx/1/2/3", healthyReport},
+		{"nested topic", "graylogic/health/knx/extra", healthyReport},
+		{"bad json", "graylogic/health/knx", "{"},
+		{"no status", "graylogic/health/knx", `{"bridge": "knx-bridge-01"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.HandleHealth(tt.topic, []byte(tt.payload)); err == nil {
+				t.Error("HandleHealth() error = nil")
+			}
+		})
+	}
+	if n := len(r.List()); n != 0 {
+		t.Errorf("%d bridges registered from bad reports", n)
+	}
+}
+
+func TestRegistry_CheckStale(t *testing.T) {
+	devices := newTestDevices()
+	hub := &mockHub{}
+	r := NewRegistry(devices, hub, nil)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	if err := r.HandleHealth("graylogic/health/knx", []byte(healthyReport)); err != nil {
+		t.Fatalf("HandleHealth: %v", err)
+	}
+	// Missing bridge ID falls back to the protocol.
+	if err := r.HandleHealth("graylogic/health/dali", []byte(`{"status": "healthy"}`)); err != nil {
+		t.Fatalf("HandleHealth: %v", err)
+	}
+
+	now = now.Add(DefaultStaleAfter - time.Second)
+	r.CheckStale(context.Background())
+	if b, _ := r.Get("knx-bridge-01"); b.Status != StatusHealthy {
+		t.Errorf("status before stale timeout = %s", b.Status)
+	}
+
+	now = now.Add(time.Second)
+	if err := r.HandleHealth("graylogic/health/dali", []byte(`{"status": "healthy"}`)); err != nil {
+		t.Fatalf("HandleHealth: %v", err)
+	}
+	r.CheckStale(context.Background())
+
+	b, _ := r.Get("knx-bridge-01")
+	if b.Status != StatusOffline || b.Reason != reasonStale || !b.StatusSince.Equal(now) {
+		t.Errorf("stale bridge = %+v", b)
+	}
+	if devices.health("light-01") != device.HealthStatusUnknown {
+		t.Error("stale bridge's devices not marked unknown")
+	}
+	if b, _ := r.Get("dali"); b.Status != StatusHealthy {
+		t.Errorf("reporting bridge status = %s", b.Status)
+	}
+
+	list := r.List()
+	if len(list) != 2 || list[0].ID != "dali" || list[1].ID != "knx-bridge-01" {
+		t.Errorf("List() = %+v", list)
+	}
+}
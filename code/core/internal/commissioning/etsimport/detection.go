@@ -320,6 +320,18 @@ func DefaultDetectionRules() []DetectionRule {
 					NameContains: []string{"cool", "kühl"},
 					Flags:        []string{"read", "transmit"},
 				},
+				{
+					DPT:          "20.102",
+					Function:     "hvac_mode",
+					NameContains: []string{"mode", "betriebsart", "modus"},
+					Flags:        []string{"write"},
+				},
+				{
+					DPT:          "6.010",
+					Function:     "setpoint_shift",
+					NameContains: []string{"shift", "verschiebung"},
+					Flags:        []string{"write"},
+				},
 			},
 		},
 
@@ -1407,6 +1407,8 @@ func inferTypeFromDPT(dpt string) string { //nolint:gocyclo // DPT-to-type mappi
 		return "light_rgb"
 	case "251.600": // RGBW
 		return "light_rgbw"
+	case "242.600": // xyY colour
+		return "light_rgb"
 
 	// Blinds/Shutters
 	case "1.008": // Up/Down
@@ -1437,7 +1439,7 @@ func inferTypeFromDPT(dpt string) string { //nolint:gocyclo // DPT-to-type mappi
 		return "scene_controller"
 
 	// HVAC
-	case "20.102": // HVAC mode
+	case "20.102", "20.105": // HVAC mode, HVAC control mode
 		return typeThermostat
 	}
 
@@ -1617,6 +1619,7 @@ func inferFunctionFromDPTValue(dpt string) string {
 		"7.600":   "color_temperature", //nolint:misspell // KNX standard uses American "color"
 		"232.600": "rgb",
 		"251.600": "rgbw",
+		"242.600": "color_xyy", //nolint:misspell // KNX standard uses American "color"
 
 		// Energy
 		"13.010": "active_energy",
@@ -1631,6 +1634,8 @@ func inferFunctionFromDPTValue(dpt string) string {
 
 		// HVAC
 		"20.102": "hvac_mode",
+		"20.105": "hvac_control_mode",
+		"6.010":  "setpoint_shift",
 	}
 
 	if fn, ok := dptFunctions[dpt]; ok {
@@ -132,7 +132,8 @@ func deepCopyValue(v any) any {
 //	    "application_program": "M-0001_...",
 //	    "functions": {
 //	      "switch":        {"ga": "1/0/1", "dpt": "1.001", "flags": ["write"]},
-//	      "switch_status": {"ga": "1/0/2", "dpt": "1.001", "flags": ["read", "transmit"]}
+//	      "switch_status": {"ga": "1/0/2", "dpt": "1.001", "flags": ["read", "transmit"]},
+//	      "energy":        {"ga": "6/0/1", "dpt": "13.010", "flags": ["read"], "poll_interval": 900}
 //	    }
 //	  }
 //
@@ -146,6 +147,10 @@ type KNXFunctionConfig struct {
 	GA    string   `json:"ga"`
 	DPT   string   `json:"dpt"`
 	Flags []string `json:"flags"`
+
+	// PollInterval is how often the bridge reads the GA (seconds), for
+	// devices that do not transmit on change. 0 uses the bridge default.
+	PollInterval int `json:"poll_interval,omitempty"`
 }
 
 // GetKNXFunctions extracts the typed function map from a KNX Address.
@@ -177,6 +182,12 @@ func GetKNXFunctions(addr Address) map[string]KNXFunctionConfig {
 		if dpt, ok := entry["dpt"].(string); ok {
 			fc.DPT = dpt
 		}
+		switch v := entry["poll_interval"].(type) {
+		case float64: // decoded from JSON
+			fc.PollInterval = int(v)
+		case int:
+			fc.PollInterval = v
+		}
 		if flags, ok := entry["flags"].([]any); ok {
 			for _, f := range flags {
 				if s, ok := f.(string); ok {
@@ -525,6 +536,51 @@ func AllCapabilities() []Capability {
 	}
 }
 
+// commandCapabilities maps control commands to the capability a device
+// needs to accept them.
+var commandCapabilities = map[string]Capability{
+	"on":              CapOnOff,
+	"off":             CapOnOff,
+	"turn_on":         CapOnOff,
+	"turn_off":        CapOnOff,
+	"toggle":          CapOnOff,
+	"dim":             CapDim,
+	"set_level":       CapDim,
+	"set_color_temp":  CapColorTemp, //nolint:misspell // matches capability name
+	"set_color":       CapColorRGB,  //nolint:misspell // matches capability name
+	"set_rgb":         CapColorRGB,  //nolint:misspell // matches capability name
+	"set_rgbw":        CapColorRGB,  //nolint:misspell // matches capability name
+	"set_hsv":         CapColorRGB,  //nolint:misspell // matches capability name
+	"set_position":    CapPosition,
+	"stop":            CapPosition,
+	"set_tilt":        CapTilt,
+	"set_speed":       CapSpeed,
+	"set_setpoint":    CapTemperatureSet,
+	"set_temperature": CapTemperatureSet,
+	"shift_setpoint":  CapTemperatureSet,
+	"set_hvac_mode":   CapModeSelect,
+	"lock":            CapLockUnlock,
+	"unlock":          CapLockUnlock,
+}
+
+// CommandCapability returns the capability a device needs for a command.
+// Reports false for commands with no capability requirement, which are
+// left to the protocol bridge to accept or reject.
+func CommandCapability(command string) (Capability, bool) {
+	c, ok := commandCapabilities[command]
+	return c, ok
+}
+
+// HasCapability reports whether the device has the capability.
+func (d *Device) HasCapability(c Capability) bool {
+	for _, have := range d.Capabilities {
+		if have == c {
+			return true
+		}
+	}
+	return false
+}
+
 // HealthStatus represents the device health state.
 type HealthStatus string
 
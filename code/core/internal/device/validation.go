@@ -358,6 +358,9 @@ func validateKNXAddress(addr Address) error {
 		if fc.GA == "" {
 			return fmt.Errorf("%w: KNX function %q has empty ga", ErrInvalidAddress, name)
 		}
+		if fc.PollInterval < 0 {
+			return fmt.Errorf("%w: KNX function %q has negative poll_interval", ErrInvalidAddress, name)
+		}
 	}
 
 	return nil
@@ -409,6 +409,22 @@ func TestValidateAddress(t *testing.T) {
 			}},
 			wantErr: ErrInvalidAddress,
 		},
+		{
+			name:     "KNX function with poll_interval",
+			protocol: ProtocolKNX,
+			address: Address{"functions": map[string]any{
+				"energy": map[string]any{"ga": "6/0/1", "dpt": "13.010", "flags": []any{"read"}, "poll_interval": float64(900)},
+			}},
+			wantErr: nil,
+		},
+		{
+			name:     "KNX function with negative poll_interval",
+			protocol: ProtocolKNX,
+			address: Address{"functions": map[string]any{
+				"energy": map[string]any{"ga": "6/0/1", "dpt": "13.010", "flags": []any{"read"}, "poll_interval": float64(-1)},
+			}},
+			wantErr: ErrInvalidAddress,
+		},
 
 		// DALI addresses
 		{
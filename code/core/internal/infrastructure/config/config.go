@@ -12,17 +12,18 @@ import (
 // Config is the root configuration structure for Gray Logic Core.
 // All configuration is loaded from YAML and can be overridden by environment variables.
 type Config struct {
-	Site      SiteConfig      `yaml:"site"`
-	Database  DatabaseConfig  `yaml:"database"`
-	MQTT      MQTTConfig      `yaml:"mqtt"`
-	API       APIConfig       `yaml:"api"`
-	WebSocket WebSocketConfig `yaml:"websocket"`
-	TSDB      TSDBConfig      `yaml:"tsdb"`
-	Logging   LoggingConfig   `yaml:"logging"`
-	Protocols ProtocolsConfig `yaml:"protocols"`
-	Security  SecurityConfig  `yaml:"security"`
-	DevMode   bool            `yaml:"dev_mode"`
-	PanelDir  string          `yaml:"panel_dir"` // Dev only: serve Flutter panel from filesystem instead of embed
+	Site       SiteConfig       `yaml:"site"`
+	Database   DatabaseConfig   `yaml:"database"`
+	MQTT       MQTTConfig       `yaml:"mqtt"`
+	API        APIConfig        `yaml:"api"`
+	WebSocket  WebSocketConfig  `yaml:"websocket"`
+	TSDB       TSDBConfig       `yaml:"tsdb"`
+	Logging    LoggingConfig    `yaml:"logging"`
+	Protocols  ProtocolsConfig  `yaml:"protocols"`
+	Automation AutomationConfig `yaml:"automation"`
+	Security   SecurityConfig   `yaml:"security"`
+	DevMode    bool             `yaml:"dev_mode"`
+	PanelDir   string           `yaml:"panel_dir"` // Dev only: serve Flutter panel from filesystem instead of embed
 }
 
 // SiteConfig contains the default site identifier.
@@ -146,6 +147,9 @@ type KNXConfig struct {
 	ConfigFile string `yaml:"config_file"` // Path to KNX bridge config (devices, mappings)
 	KNXDHost   string `yaml:"knxd_host"`
 	KNXDPort   int    `yaml:"knxd_port"`
+	// KeyringSecret encrypts the imported KNX Secure keyring at rest.
+	// Kept apart from the JWT secret so rotating one does not affect the other.
+	KeyringSecret string `yaml:"keyring_secret"`
 	// KNXD contains knxd daemon management settings
 	KNXD KNXDConfig `yaml:"knxd"`
 }
@@ -283,6 +287,36 @@ type RateLimitConfig struct {
 	RequestsPerMinute int  `yaml:"requests_per_minute"`
 }
 
+// AutomationConfig contains automation engine settings.
+type AutomationConfig struct {
+	Override OverrideConfig `yaml:"override"`
+}
+
+// OverrideConfig contains manual override latch settings.
+// See docs/automation/override-logic.md.
+type OverrideConfig struct {
+	// Enabled turns on latching devices changed by a physical input.
+	Enabled bool `yaml:"enabled"`
+
+	// TimeoutMinutes clears a latch after this long (0 = no timeout).
+	// Default: 120
+	TimeoutMinutes int `yaml:"timeout_minutes"`
+
+	// BypassPriority is the scene priority (1-100) at or above which
+	// schedules and rules ignore the latch, e.g. safety scenes.
+	// Default: 80
+	BypassPriority int `yaml:"bypass_priority"`
+
+	// ResetOnModeChange clears all latches when the site mode changes.
+	// Default: true
+	ResetOnModeChange bool `yaml:"reset_on_mode_change"`
+
+	// ResetOnSchedule lets the next scheduled scene clear the latch on
+	// the devices it controls instead of skipping them.
+	// Default: true
+	ResetOnSchedule bool `yaml:"reset_on_schedule"`
+}
+
 // Load reads configuration from a YAML file and applies environment variable overrides.
 //
 // The configuration loading order is:
@@ -368,6 +402,15 @@ func defaultConfig() *Config {
 			Format: "json",
 			Output: "stdout",
 		},
+		Automation: AutomationConfig{
+			Override: OverrideConfig{
+				Enabled:           true,
+				TimeoutMinutes:    120,
+				BypassPriority:    80,
+				ResetOnModeChange: true,
+				ResetOnSchedule:   true,
+			},
+		},
 		Security: SecurityConfig{
 			JWT: JWTConfig{
 				AccessTokenTTL:  15,
@@ -415,6 +458,11 @@ func applyEnvOverrides(cfg *Config) {
 		cfg.Protocols.KNX.KNXD.Backend.Host = v
 	}
 
+	// KNX Secure keyring encryption secret
+	if v := os.Getenv("GRAYLOGIC_KNX_KEYRING_SECRET"); v != "" {
+		cfg.Protocols.KNX.KeyringSecret = v
+	}
+
 	// Security - JWT secret (IMPORTANT: always override in production)
 	if v := os.Getenv("GRAYLOGIC_JWT_SECRET"); v != "" {
 		cfg.Security.JWT.Secret = v
@@ -453,6 +501,16 @@ func (c *Config) Validate() error {
 		errs = append(errs, "api.port must be between 1 and 65535")
 	}
 
+	// Automation validation
+	if o := c.Automation.Override; o.Enabled {
+		if o.TimeoutMinutes < 0 {
+			errs = append(errs, "automation.override.timeout_minutes must not be negative")
+		}
+		if o.BypassPriority < 1 || o.BypassPriority > 100 {
+			errs = append(errs, "automation.override.bypass_priority must be between 1 and 100")
+		}
+	}
+
 	// Security validation - JWT secret is REQUIRED
 	// For building automation systems, authentication security is critical.
 	// Empty or weak secrets could allow attackers to forge tokens and
@@ -464,6 +522,16 @@ func (c *Config) Validate() error {
 		errs = append(errs, "security.jwt.secret must be at least 32 characters for adequate security")
 	}
 
+	// The KNX keyring secret protects the bus encryption keys at rest
+	const minKeyringSecretLength = 32
+	if c.Protocols.KNX.Enabled {
+		if c.Protocols.KNX.KeyringSecret == "" {
+			errs = append(errs, "protocols.knx.keyring_secret is required when KNX is enabled (set GRAYLOGIC_KNX_KEYRING_SECRET environment variable)")
+		} else if len(c.Protocols.KNX.KeyringSecret) < minKeyringSecretLength {
+			errs = append(errs, "protocols.knx.keyring_secret must be at least 32 characters for adequate security")
+		}
+	}
+
 	if len(errs) > 0 {
 		return fmt.Errorf("configuration errors: %s", strings.Join(errs, "; "))
 	}
@@ -196,6 +196,42 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "KNX enabled without keyring secret",
+			config: &Config{
+				Site:      SiteConfig{ID: "site-001"},
+				Database:  DatabaseConfig{Path: "/data/graylogic.db"},
+				MQTT:      MQTTConfig{QoS: 1},
+				API:       APIConfig{Port: 8080},
+				Security:  SecurityConfig{JWT: JWTConfig{Secret: validJWTSecret}},
+				Protocols: ProtocolsConfig{KNX: KNXConfig{Enabled: true}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "KNX keyring secret too short",
+			config: &Config{
+				Site:      SiteConfig{ID: "site-001"},
+				Database:  DatabaseConfig{Path: "/data/graylogic.db"},
+				MQTT:      MQTTConfig{QoS: 1},
+				API:       APIConfig{Port: 8080},
+				Security:  SecurityConfig{JWT: JWTConfig{Secret: validJWTSecret}},
+				Protocols: ProtocolsConfig{KNX: KNXConfig{Enabled: true, KeyringSecret: "short"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "KNX with keyring secret",
+			config: &Config{
+				Site:      SiteConfig{ID: "site-001"},
+				Database:  DatabaseConfig{Path: "/data/graylogic.db"},
+				MQTT:      MQTTConfig{QoS: 1},
+				API:       APIConfig{Port: 8080},
+				Security:  SecurityConfig{JWT: JWTConfig{Secret: validJWTSecret}},
+				Protocols: ProtocolsConfig{KNX: KNXConfig{Enabled: true, KeyringSecret: "a-keyring-secret-of-32-characters!"}},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -243,6 +279,7 @@ func TestApplyEnvOverrides(t *testing.T) {
 	t.Setenv("GRAYLOGIC_API_HOST", "192.168.1.1")
 	t.Setenv("GRAYLOGIC_TSDB_URL", "http://vm.example.com:8428")
 	t.Setenv("GRAYLOGIC_JWT_SECRET", "jwt-secret")
+	t.Setenv("GRAYLOGIC_KNX_KEYRING_SECRET", "keyring-secret")
 
 	applyEnvOverrides(cfg)
 
@@ -273,6 +310,10 @@ func TestApplyEnvOverrides(t *testing.T) {
 	if cfg.Security.JWT.Secret != "jwt-secret" {
 		t.Errorf("Security.JWT.Secret = %q, want %q", cfg.Security.JWT.Secret, "jwt-secret")
 	}
+
+	if cfg.Protocols.KNX.KeyringSecret != "keyring-secret" {
+		t.Errorf("Protocols.KNX.KeyringSecret = %q, want %q", cfg.Protocols.KNX.KeyringSecret, "keyring-secret")
+	}
 }
 
 func TestDefaultConfig(t *testing.T) {
@@ -0,0 +1,6 @@
+-- Rollback: Schedule Schema for Gray Logic Core
+-- Version: 20261016_090000
+--
+-- WARNING: This will DELETE ALL schedule data.
+
+DROP TABLE IF EXISTS schedules;
@@ -0,0 +1,50 @@
+-- Schedule Schema for Gray Logic Core
+-- Version: 20261016_090000
+--
+-- This migration creates the table for:
+--   - Schedules (time, cron and astronomical triggers for scenes)
+--
+-- Schema Rules (per database-schema.md):
+--   - STRICT mode enforced for type safety
+--   - All tables use TEXT for UUIDs
+--   - Timestamps stored as TEXT in ISO 8601 format (UTC)
+--   - Additive-only changes (no DROP/RENAME after production)
+
+-- ============================================================================
+-- SCHEDULES
+-- ============================================================================
+-- A schedule fires a scene at a fixed time, on a cron pattern, or relative
+-- to sunrise/sunset at the site location. Next-run times are computed at
+-- runtime and are not stored; last_run_at drives missed-run detection after
+-- a restart.
+
+CREATE TABLE schedules (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL UNIQUE,
+    description TEXT,
+
+    enabled INTEGER NOT NULL DEFAULT 1,
+
+    -- Trigger stored as JSON: {"type": "sunset", "value": "-15m", "days": ["mon"]}
+    trigger_config TEXT NOT NULL,
+
+    -- Execute target
+    execute_type TEXT NOT NULL DEFAULT 'scene',   -- scene
+    scene_id TEXT,
+
+    -- Missed-run handling
+    missed_run_policy TEXT NOT NULL DEFAULT 'skip',   -- skip, run_once
+    missed_run_grace_min INTEGER NOT NULL DEFAULT 60,
+
+    last_run_at TEXT,
+
+    -- Timestamps
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+
+    FOREIGN KEY (scene_id) REFERENCES scenes(id) ON DELETE CASCADE
+) STRICT;
+
+CREATE INDEX idx_schedules_enabled ON schedules(enabled);
+CREATE INDEX idx_schedules_scene_id ON schedules(scene_id);
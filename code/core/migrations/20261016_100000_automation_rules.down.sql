@@ -0,0 +1,6 @@
+-- Rollback: Automation Rule Schema for Gray Logic Core
+-- Version: 20261016_100000
+--
+-- WARNING: This will DELETE ALL automation rule data.
+
+DROP TABLE IF EXISTS automation_rules;
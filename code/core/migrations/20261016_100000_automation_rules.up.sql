@@ -0,0 +1,48 @@
+-- Automation Rule Schema for Gray Logic Core
+-- Version: 20261016_100000
+--
+-- This migration creates the table for:
+--   - Automation rules (device state triggers that run a scene or command)
+--
+-- Schema Rules (per database-schema.md):
+--   - STRICT mode enforced for type safety
+--   - All tables use TEXT for UUIDs
+--   - Timestamps stored as TEXT in ISO 8601 format (UTC)
+--   - Additive-only changes (no DROP/RENAME after production)
+
+-- ============================================================================
+-- AUTOMATION RULES
+-- ============================================================================
+-- A rule watches one device state key and fires when it changes to,
+-- crosses, or stays at a value. The execute target is stored as JSON
+-- because it may be a scene or a direct device command; a rule whose
+-- scene has been deleted fails at fire time and reports the error on
+-- graylogic/core/automation/{id}/fired.
+
+CREATE TABLE automation_rules (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL UNIQUE,
+    description TEXT,
+
+    enabled INTEGER NOT NULL DEFAULT 1,
+
+    -- Trigger stored as JSON: {"type": "crosses", "device_id": "...", "key": "temperature", "threshold": 24}
+    trigger_config TEXT NOT NULL,
+
+    -- Execute stored as JSON: {"type": "scene", "scene_id": "..."}
+    --                      or {"type": "command", "device_id": "...", "command": "on"}
+    execute_config TEXT NOT NULL,
+
+    -- Rate limiting
+    debounce_ms INTEGER NOT NULL DEFAULT 0,
+    cooldown_seconds INTEGER NOT NULL DEFAULT 0,
+
+    last_fired_at TEXT,
+
+    -- Timestamps
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+) STRICT;
+
+CREATE INDEX idx_automation_rules_enabled ON automation_rules(enabled);
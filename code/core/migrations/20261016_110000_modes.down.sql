@@ -0,0 +1,8 @@
+-- Rollback: Mode Schema for Gray Logic Core
+-- Version: 20261016_110000
+--
+-- WARNING: This will DELETE ALL mode definitions and mode schedules.
+
+DELETE FROM schedules WHERE execute_type = 'mode';
+ALTER TABLE schedules DROP COLUMN mode_id;
+DROP TABLE IF EXISTS modes;
@@ -0,0 +1,55 @@
+-- Mode Schema for Gray Logic Core
+-- Version: 20261016_110000
+--
+-- This migration creates the table for:
+--   - Modes (home, away, night, holiday) with transition scenes and behaviour
+-- and lets schedules target a mode instead of a scene.
+--
+-- Schema Rules (per database-schema.md):
+--   - STRICT mode enforced for type safety
+--   - All tables use TEXT for UUIDs
+--   - Timestamps stored as TEXT in ISO 8601 format (UTC)
+--   - Additive-only changes (no DROP/RENAME after production)
+
+-- ============================================================================
+-- MODES
+-- ============================================================================
+-- The current mode is sites.mode_current; sites.modes_available is kept in
+-- sync with the rows of this table. The id is the slug stored there.
+
+CREATE TABLE modes (
+    id TEXT PRIMARY KEY,                          -- "home", "night"
+    name TEXT NOT NULL,
+    icon TEXT,
+    color TEXT,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+
+    -- Transition scenes
+    entry_scene_id TEXT,
+    exit_scene_id TEXT,
+
+    -- Behaviour stored as JSON:
+    -- {"enable_schedules": [...], "disable_schedules": [...], "enable_rules": [...], "disable_rules": [...]}
+    behaviour TEXT NOT NULL DEFAULT '{}',
+
+    -- Timestamps
+    created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+    updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+
+    FOREIGN KEY (entry_scene_id) REFERENCES scenes(id) ON DELETE SET NULL,
+    FOREIGN KEY (exit_scene_id) REFERENCES scenes(id) ON DELETE SET NULL
+) STRICT;
+
+-- Standard modes (match the default sites.modes_available)
+INSERT INTO modes (id, name, icon, color, sort_order) VALUES
+    ('home', 'Home', 'home', '#22C55E', 0),
+    ('away', 'Away', 'door-open', '#EAB308', 1),
+    ('night', 'Night', 'moon', '#6366F1', 2),
+    ('holiday', 'Holiday', 'plane', '#0EA5E9', 3);
+
+-- ============================================================================
+-- SCHEDULES: mode target
+-- ============================================================================
+-- execute_type 'mode' sets mode_id as the site mode when the schedule fires.
+
+ALTER TABLE schedules ADD COLUMN mode_id TEXT REFERENCES modes(id) ON DELETE CASCADE;
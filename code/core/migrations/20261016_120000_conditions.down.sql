@@ -0,0 +1,8 @@
+-- Rollback: Condition Schema for Gray Logic Core
+-- Version: 20261016_120000
+--
+-- WARNING: This will DELETE ALL scene, schedule and rule conditions.
+
+ALTER TABLE automation_rules DROP COLUMN conditions;
+ALTER TABLE schedules DROP COLUMN conditions;
+ALTER TABLE scenes DROP COLUMN conditions;
@@ -0,0 +1,23 @@
+-- Condition Schema for Gray Logic Core
+-- Version: 20261016_120000
+--
+-- This migration adds optional conditions to:
+--   - Scenes (checked before activation)
+--   - Schedules and automation rules (checked when they fire)
+--
+-- Schema Rules (per database-schema.md):
+--   - STRICT mode enforced for type safety
+--   - Additive-only changes (no DROP/RENAME after production)
+
+-- ============================================================================
+-- CONDITIONS
+-- ============================================================================
+-- Stored as a JSON array of condition trees, NULL when there are none:
+-- [{"type": "time_window", "after": "22:00", "before": "06:00"},
+--  {"type": "or", "conditions": [{"type": "mode", "modes": ["night"]}, ...]}]
+-- Top-level conditions are ANDed. Scene executions that were not run
+-- because conditions failed are recorded with status 'conditions_not_met'.
+
+ALTER TABLE scenes ADD COLUMN conditions TEXT;
+ALTER TABLE schedules ADD COLUMN conditions TEXT;
+ALTER TABLE automation_rules ADD COLUMN conditions TEXT;
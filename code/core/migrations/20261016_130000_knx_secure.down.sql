@@ -0,0 +1,7 @@
+-- Rollback: KNX Secure Schema for Gray Logic Core
+-- Version: 20261016_130000
+--
+-- WARNING: This will DELETE the imported keyring and all sequence numbers.
+
+DROP TABLE IF EXISTS knx_secure_sequences;
+DROP TABLE IF EXISTS knx_secure_keyring;
@@ -0,0 +1,38 @@
+-- KNX Secure Schema for Gray Logic Core
+-- Version: 20261016_130000
+--
+-- This migration creates the tables for:
+--   - The imported ETS keyring (KNX Data Secure group keys), encrypted at rest
+--   - Data Secure sequence numbers, so replay protection survives restarts
+--
+-- Schema Rules (per database-schema.md):
+--   - STRICT mode enforced for type safety
+--   - Timestamps stored as TEXT in ISO 8601 format (UTC)
+--   - Additive-only changes (no DROP/RENAME after production)
+
+-- ============================================================================
+-- KEYRING
+-- ============================================================================
+-- Single row. keyring is the decoded keyring as JSON, sealed with AES-256-GCM
+-- (nonce || ciphertext) under a key derived from the site JWT secret.
+-- Changing that secret makes the row unreadable; import the keyring again.
+
+CREATE TABLE knx_secure_keyring (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    project TEXT NOT NULL,                        -- ETS project name
+    created TEXT NOT NULL,                        -- Keyring export timestamp
+    keyring BLOB NOT NULL,
+    imported_at TEXT NOT NULL
+) STRICT;
+
+-- ============================================================================
+-- SEQUENCE NUMBERS
+-- ============================================================================
+-- Highest Data Secure sequence number seen from (or sent by) each individual
+-- address. Only ever increases.
+
+CREATE TABLE knx_secure_sequences (
+    individual_address TEXT PRIMARY KEY,          -- "1.1.250"
+    sequence_number INTEGER NOT NULL,
+    updated_at TEXT NOT NULL
+) STRICT;
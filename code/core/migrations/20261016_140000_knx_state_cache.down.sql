@@ -0,0 +1,7 @@
+-- Rollback: KNX State Cache Schema for Gray Logic Core
+-- Version: 20261016_140000
+--
+-- WARNING: This will DELETE all cached KNX state. The bridge starts from
+-- the device registry's last known state instead.
+
+DROP TABLE IF EXISTS knx_state_cache;
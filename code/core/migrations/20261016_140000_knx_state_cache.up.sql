@@ -0,0 +1,27 @@
+-- KNX State Cache Schema for Gray Logic Core
+-- Version: 20261016_140000
+--
+-- This migration creates the table for the KNX bridge's state cache, so the
+-- last value of each device function survives restarts.
+--
+-- Schema Rules (per database-schema.md):
+--   - STRICT mode enforced for type safety
+--   - Timestamps stored as TEXT in ISO 8601 format (UTC)
+--   - Additive-only changes (no DROP/RENAME after production)
+
+-- ============================================================================
+-- STATE CACHE
+-- ============================================================================
+-- Last decoded value per device function, and when the bus (or a command
+-- the bridge sent) last set it. Restored values are marked stale in memory
+-- until the bus confirms them.
+
+CREATE TABLE knx_state_cache (
+    device_id TEXT NOT NULL,
+    function TEXT NOT NULL,              -- Device function, e.g. "switch_status"
+    value TEXT NOT NULL,                 -- JSON-encoded value
+    updated_at TEXT NOT NULL,
+
+    PRIMARY KEY (device_id, function),
+    FOREIGN KEY (device_id) REFERENCES devices(id) ON DELETE CASCADE
+) STRICT;